@@ -44,6 +44,8 @@ pkg/util/interval/generic/doc.go:  //go:generate ../../util/interval/generic/gen
 pkg/util/interval/generic/example_t.go://go:generate ./gen.sh *example generic
 pkg/util/log/channels.go://go:generate go run gen/main.go logpb/log.proto channel.go channel/channel_generated.go
 pkg/util/log/channels.go://go:generate go run gen/main.go logpb/log.proto log_channels.go log_channels_generated.go
+pkg/util/log/channels.go://go:generate go run gen/main.go logpb/log.proto log_channels.json ../../../docs/generated/log_channels.json
+pkg/util/log/channels.go://go:generate go run gen/main.go logpb/log.proto log_channels.ts ../../../docs/generated/log_channels.ts
 pkg/util/log/channels.go://go:generate go run gen/main.go logpb/log.proto logging.md ../../../docs/generated/logging.md
 pkg/util/log/channels.go://go:generate go run gen/main.go logpb/log.proto severity.go severity/severity_generated.go
 pkg/util/log/sinks.go://go:generate mockgen -package=log -destination=mocks_generated_test.go --mock_names=TestingLogSink=MockLogSink . TestingLogSink
@@ -14,3 +14,12 @@ git grep --recurse-submodules -n 'redact\.RegisterSafeType' | \
     grep -v '^vendor/github.com/cockroachdb/redact' | \
     sed -E -e 's/^([^:]*):[0-9]+:.*redact\.RegisterSafeType\((.*)\).*/\1 | \`\2\`/g' | \
     LC_ALL=C sort
+echo
+echo "The following types are reported through a custom redaction function"
+echo "registered with log.RegisterSafeStringer:"
+echo
+echo "File | Type"; echo "--|--"
+git grep --recurse-submodules -n 'log\.RegisterSafeStringer(' -- ':!*_test.go' | \
+    grep -vE '^([^:]*):[0-9]+:[ 	]*//' | \
+    sed -E -e 's/^([^:]*):[0-9]+:.*log\.RegisterSafeStringer\((.*),$/\1 | \`\2\`/g' | \
+    LC_ALL=C sort
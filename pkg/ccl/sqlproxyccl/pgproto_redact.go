@@ -0,0 +1,42 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package sqlproxyccl
+
+import (
+	"reflect"
+
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/redact"
+	"github.com/jackc/pgproto3/v2"
+)
+
+// formatErrorResponse prints a pgwire ErrorResponse message, reporting
+// its severity, SQLSTATE code and source location as safe. The message
+// and the other fields may contain user data, and are thus considered
+// sensitive.
+func formatErrorResponse(w redact.SafePrinter, v interface{}) {
+	msg := v.(*pgproto3.ErrorResponse)
+	w.Printf("%s: %s (SQLSTATE %s)",
+		redact.Safe(msg.Severity), msg.Message, redact.Safe(msg.Code))
+	if msg.Detail != "" {
+		w.Printf("\nDETAIL: %s", msg.Detail)
+	}
+	if msg.Hint != "" {
+		w.Printf("\nHINT: %s", msg.Hint)
+	}
+	if msg.Routine != "" {
+		w.Printf("\n-- %s:%d in %s()",
+			redact.Safe(msg.File), redact.Safe(msg.Line), redact.Safe(msg.Routine))
+	}
+}
+
+func init() {
+	log.RegisterSafeStringer(reflect.TypeOf(&pgproto3.ErrorResponse{}),
+		"severity, SQLSTATE code and source location", formatErrorResponse)
+}
@@ -0,0 +1,40 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package sqlproxyccl
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logtestutils"
+	"github.com/jackc/pgproto3/v2"
+)
+
+func TestErrorResponseRedaction(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	logtestutils.CheckSafeStringers(t,
+		logtestutils.SafeStringerCase{
+			Value: &pgproto3.ErrorResponse{
+				Severity: "FATAL",
+				Code:     "28P01",
+				Message:  `password authentication failed for user "alice"`,
+				Detail:   "secret detail",
+				Hint:     "secret hint",
+				File:     "auth.go",
+				Line:     42,
+				Routine:  "authPassword",
+			},
+			Sensitive: []string{"alice", "secret"},
+			Safe:      []string{"FATAL", "28P01", "auth.go:42", "authPassword"},
+		},
+	)
+}
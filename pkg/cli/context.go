@@ -72,6 +72,7 @@ func initCLIDefaults() {
 	setCertContextDefaults()
 	setDebugRecoverContextDefaults()
 	setDebugSendKVBatchContextDefaults()
+	setDebugTailLogsContextDefaults()
 
 	initPreFlagsDefaults()
 
@@ -1654,6 +1654,7 @@ var debugCmds = []*cobra.Command{
 	debugBallastCmd,
 	debugCheckLogConfigCmd,
 	debugDecodeKeyCmd,
+	debugDecodeLogCmd,
 	debugDecodeValueCmd,
 	debugDecodeProtoCmd,
 	debugGossipValuesCmd,
@@ -1666,6 +1667,7 @@ var debugCmds = []*cobra.Command{
 	debugListFilesCmd,
 	debugResetQuorumCmd,
 	debugSendKVBatchCmd,
+	debugTailLogsCmd,
 	debugRecoverCmd,
 }
 
@@ -1810,10 +1812,18 @@ func init() {
 	f.BoolVar(&debugMergeLogsOpts.redactInput, "redact", debugMergeLogsOpts.redactInput,
 		"redact the input files to remove sensitive information")
 	f.StringVar(&debugMergeLogsOpts.format, "format", "",
-		"log format of the input files")
+		"log format of the input files; if unspecified, the format is detected separately for each file")
 	f.Var(&debugMergeLogsOpts.useColor, "color",
 		"force use of TTY escape codes to colorize the output")
 
+	f = debugDecodeLogCmd.Flags()
+	f.StringVar(&debugDecodeLogOpts.format, "format", debugDecodeLogOpts.format,
+		"log format of the output")
+	f.StringVar(&debugDecodeLogOpts.inputFormat, "input-format", "",
+		"log format of the input files; if unspecified, the format is detected separately for each file")
+	f.BoolVar(&debugDecodeLogOpts.redactInput, "redact", debugDecodeLogOpts.redactInput,
+		"redact the input files to remove sensitive information")
+
 	f = debugDecodeKeyCmd.Flags()
 	f.Var(&decodeKeyOptions.encoding, "encoding", "key argument encoding")
 
@@ -1844,6 +1854,16 @@ func init() {
 		"whether to keep the CollectedSpans field on the response, to learn about how traces work")
 	f.StringVar(&debugSendKVBatchContext.traceFile, "trace-output", debugSendKVBatchContext.traceFile,
 		"the output file to use for the trace. If left empty, output to stderr.")
+
+	f = debugTailLogsCmd.Flags()
+	f.Var(&debugTailLogsContext.channels, "channels",
+		"selection of channels to include in the output (default all)")
+	f.Var(&debugTailLogsContext.severity, "severity",
+		"minimum severity of the entries to include in the output")
+	f.StringVar(&debugTailLogsContext.pattern, "pattern", debugTailLogsContext.pattern,
+		"regular expression that the log messages must match")
+	f.BoolVar(&debugTailLogsContext.redact, "redact", debugTailLogsContext.redact,
+		"redact sensitive data from the log entries")
 }
 
 func initPebbleCmds(cmd *cobra.Command) {
@@ -0,0 +1,83 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cli
+
+import (
+	"bufio"
+	"io"
+	"os"
+
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+	"github.com/cockroachdb/errors"
+	"github.com/spf13/cobra"
+)
+
+var debugDecodeLogCmd = &cobra.Command{
+	Use:   "decode-log <log file>...",
+	Short: "convert log files to another format",
+	Long: `
+Decodes the entries of the given log files and prints them to stdout
+in the format specified by --format. The input format is detected
+separately for each file, unless specified with --input-format.
+
+This is notably useful to read files produced with the binary
+crdb-proto format.
+`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runDebugDecodeLog,
+}
+
+var debugDecodeLogOpts = struct {
+	format      string
+	inputFormat string
+	redactInput bool
+}{
+	format: "crdb-v2",
+}
+
+func runDebugDecodeLog(cmd *cobra.Command, args []string) error {
+	o := debugDecodeLogOpts
+	editMode := log.SelectEditMode(o.redactInput, true /* keepRedactable */)
+	w := bufio.NewWriter(cmd.OutOrStdout())
+	for _, path := range args {
+		if err := decodeLogFile(path, o.inputFormat, o.format, editMode, w); err != nil {
+			return errors.Wrapf(err, "decoding %s", path)
+		}
+	}
+	return w.Flush()
+}
+
+func decodeLogFile(
+	path, inputFormat, format string, editMode log.EditSensitiveData, w io.Writer,
+) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	d, err := log.NewEntryDecoderWithFormat(f, editMode, inputFormat)
+	if err != nil {
+		return err
+	}
+	for {
+		var e logpb.Entry
+		if err := d.Decode(&e); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if err := log.FormatLegacyEntryWithFormat(e, format, w); err != nil {
+			return err
+		}
+	}
+}
@@ -442,6 +442,7 @@ type fileLogStream struct {
 	prevTime int64
 	fi       fileInfo
 	f        *os.File
+	r        io.ReadCloser
 	d        log.EntryDecoder
 	read     bool
 	editMode log.EditSensitiveData
@@ -478,8 +479,14 @@ func newFileLogStream(
 }
 
 func (s *fileLogStream) close() {
-	s.f.Close()
+	if s.r != nil {
+		// This also closes the file.
+		s.r.Close()
+	} else {
+		s.f.Close()
+	}
 	s.f = nil
+	s.r = nil
 	s.d = nil
 }
 
@@ -488,6 +495,19 @@ func (s *fileLogStream) open() bool {
 	if s.f, s.err = os.Open(s.fi.path); s.err != nil {
 		return false
 	}
+	if log.IsCompressedLogFile(s.fi.path) {
+		// Compressed files cannot be seeked into. The format is detected
+		// by the decoder, and the entries before from are skipped when
+		// reading instead.
+		if s.r, s.err = log.NewDecompressingReader(s.f, s.fi.path); s.err != nil {
+			s.f.Close()
+			return false
+		}
+		if s.d, s.err = log.NewEntryDecoderWithFormat(bufio.NewReaderSize(s.r, readBufSize), s.editMode, s.format); s.err != nil {
+			return false
+		}
+		return true
+	}
 	if s.format == "" {
 		if _, s.format, s.err = log.ReadFormatFromLogFile(s.f); s.err != nil {
 			return false
@@ -559,7 +579,9 @@ func (s *fileLogStream) error() error        { return s.err }
 func seekToFirstAfterFrom(
 	f *os.File, from time.Time, editMode log.EditSensitiveData, format string,
 ) (err error) {
-	if from.IsZero() {
+	if from.IsZero() || format == "crdb-proto" {
+		// The binary format cannot be decoded from an arbitrary offset.
+		// The entries before from are skipped when reading instead.
 		return nil
 	}
 	fi, err := f.Stat()
@@ -0,0 +1,104 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/cockroachdb/cockroach/pkg/cli/clierrorplus"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logconfig"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+	"github.com/spf13/cobra"
+)
+
+var debugTailLogsContext = struct {
+	// The channels to include in the output. All channels if empty.
+	channels logconfig.ChannelList
+	// The minimum severity of the entries in the output.
+	severity log.Severity
+	// The regular expression that entries must match.
+	pattern string
+	// Whether to redact sensitive data from the entries.
+	redact bool
+}{}
+
+func setDebugTailLogsContextDefaults() {
+	debugTailLogsContext.channels = logconfig.ChannelList{}
+	debugTailLogsContext.severity = severity.INFO
+	debugTailLogsContext.pattern = ""
+	debugTailLogsContext.redact = false
+}
+
+var debugTailLogsCmd = &cobra.Command{
+	Use:   "tail-logs [<node id>]",
+	Short: "follow the log entries emitted by a node",
+	Long: `
+Streams the log entries emitted by the given node, or the node the
+command is connected to if no node ID is given, from this point on.
+The command runs until interrupted.
+
+The entries can be restricted to some channels, a minimum severity
+and a regular expression that the log messages must match. If the
+command cannot keep up with the rate of log entries, some entries are
+dropped and a warning is printed to the standard error.
+`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: clierrorplus.MaybeDecorateError(runDebugTailLogs),
+}
+
+func runDebugTailLogs(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	nodeID := "local"
+	if len(args) > 0 {
+		nodeID = args[0]
+	}
+
+	conn, _, finish, err := getClientGRPCConn(ctx, serverCfg)
+	if err != nil {
+		return err
+	}
+	defer finish()
+
+	stream, err := serverpb.NewStatusClient(conn).TailLogs(ctx, &serverpb.TailLogsRequest{
+		NodeId:   nodeID,
+		Channels: debugTailLogsContext.channels.Channels,
+		Severity: debugTailLogsContext.severity,
+		Pattern:  debugTailLogsContext.pattern,
+		Redact:   debugTailLogsContext.redact,
+	})
+	if err != nil {
+		return err
+	}
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if resp.Dropped > 0 {
+			fmt.Fprintf(stderr, "warning: %d log entries were dropped\n", resp.Dropped)
+		}
+		for _, e := range resp.Entries {
+			if err := log.FormatLegacyEntry(e, os.Stdout); err != nil {
+				return err
+			}
+		}
+	}
+}
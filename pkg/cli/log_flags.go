@@ -195,6 +195,11 @@ func setupLogging(ctx context.Context, cmd *cobra.Command, isServerCmd, applyCon
 	}
 	cliCtx.logShutdownFn = logShutdownFn
 
+	// Record the configuration applied at start-up and the state of the
+	// sinks, so that the subsequent changes and delivery problems can be
+	// traced back to it.
+	log.ReportStartup(ctx)
+
 	// If using a custom config, report the configuration at the start of the logging stream.
 	if cliCtx.logConfigInput.isSet {
 		log.Ops.Infof(ctx, "using explicit logging configuration:\n%s", cliCtx.logConfigInput.s)
@@ -451,6 +456,10 @@ func addPredefinedLogFiles(c *logconfig.Config) {
 
 // predefinedLogFiles are the files defined when the --log flag
 // does not otherwise override the file sinks.
+//
+// The routing of channels to file groups is reported to the DB Console
+// via docs/generated/log_channels.ts; when modifying it, also update
+// defaultFileGroups in pkg/util/log/gen.
 // TODO(knz): add the PRIVILEGES channel.
 const predefinedLogFiles = `
 sinks:
@@ -465,7 +474,7 @@ sinks:
   sql-auth:               { channels: SESSIONS, auditable: true }
   sql-audit:              { channels: SENSITIVE_ACCESS, auditable: true }
   sql-exec:               { channels: SQL_EXEC }
-  sql-schema:             { channels: SQL_SCHEMA }
+  sql-schema:             { channels: [SQL_SCHEMA, SCHEMA_CHANGES] }
   sql-slow:               { channels: SQL_PERF }
   sql-slow-internal-only: { channels: SQL_INTERNAL_PERF }
   telemetry:
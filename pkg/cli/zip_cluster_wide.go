@@ -80,6 +80,7 @@ var debugZipTablesPerCluster = []string{
 	"crdb_internal.cluster_database_privileges",
 	"crdb_internal.cluster_execution_insights",
 	"crdb_internal.cluster_locks",
+	"crdb_internal.cluster_logging",
 	"crdb_internal.cluster_queries",
 	"crdb_internal.cluster_sessions",
 	"crdb_internal.cluster_settings",
@@ -101,8 +102,12 @@ var debugZipTablesPerCluster = []string{
 	"crdb_internal.kv_node_status",
 	"crdb_internal.kv_store_status",
 
+	"crdb_internal.node_recent_log_entries",
+
 	"crdb_internal.regions",
 	"crdb_internal.schema_changes",
+	"crdb_internal.schema_change_stage_errors",
+	"crdb_internal.schema_change_stages",
 	"crdb_internal.super_regions",
 	"crdb_internal.partitions",
 	"crdb_internal.zones",
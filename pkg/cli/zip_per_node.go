@@ -66,6 +66,7 @@ var debugZipTablesPerNode = []string{
 	"crdb_internal.gossip_nodes",
 
 	"crdb_internal.leases",
+	"crdb_internal.logging_sinks",
 
 	"crdb_internal.node_build_info",
 	"crdb_internal.node_contention_events",
@@ -486,7 +487,8 @@ func (zc *debugZipContext) collectPerNodeData(
 			}
 
 			logPrinter := nodePrinter.withPrefix("log file: %s", file.Name)
-			name := prefix + "/logs/" + file.Name
+			// The log entries are retrieved decompressed.
+			name := prefix + "/logs/" + log.UncompressedLogFileName(file.Name)
 			var entries *serverpb.LogEntriesResponse
 			sf := logPrinter.start("requesting file")
 			if requestErr := zc.runZipFn(ctx, sf,
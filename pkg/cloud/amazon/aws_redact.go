@@ -0,0 +1,40 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package amazon
+
+import (
+	"reflect"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/redact"
+)
+
+// formatAWSError prints an AWS SDK error, reporting the error code as
+// well as the status code and request ID of failed requests as safe.
+// The message may contain bucket or object names, and is thus
+// considered sensitive.
+func formatAWSError(w redact.SafePrinter, v interface{}) {
+	err := v.(awserr.Error)
+	w.Printf("%s: %s", redact.Safe(err.Code()), err.Message())
+	if rf, ok := err.(awserr.RequestFailure); ok {
+		w.Printf("\n\tstatus code: %d, request id: %s",
+			redact.Safe(rf.StatusCode()), redact.Safe(rf.RequestID()))
+	}
+	if orig := err.OrigErr(); orig != nil {
+		w.Printf("\ncaused by: %v", orig)
+	}
+}
+
+func init() {
+	log.RegisterSafeStringer(reflect.TypeOf((*awserr.Error)(nil)).Elem(),
+		"error code, status code and request ID", formatAWSError)
+}
@@ -0,0 +1,41 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package amazon
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logtestutils"
+	"github.com/cockroachdb/errors"
+)
+
+func TestAWSErrorRedaction(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	logtestutils.CheckSafeStringers(t,
+		logtestutils.SafeStringerCase{
+			Value:     awserr.New(s3.ErrCodeNoSuchKey, "no such key: customers/alice.csv", nil),
+			Sensitive: []string{"alice"},
+			Safe:      []string{s3.ErrCodeNoSuchKey},
+		},
+		logtestutils.SafeStringerCase{
+			Value: awserr.NewRequestFailure(
+				awserr.New("AccessDenied", "access denied to bucket secret-bucket",
+					errors.New("credentials of bob")),
+				403, "REQ123"),
+			Sensitive: []string{"secret-bucket", "bob"},
+			Safe:      []string{"AccessDenied", "403", "REQ123"},
+		},
+	)
+}
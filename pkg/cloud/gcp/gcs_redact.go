@@ -0,0 +1,36 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package gcp
+
+import (
+	"reflect"
+
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/redact"
+	"google.golang.org/api/googleapi"
+)
+
+// formatGoogleAPIError prints a Google API error, reporting the HTTP
+// status code and the reasons of the error items as safe. The messages
+// may contain bucket or object names, and are thus considered
+// sensitive.
+func formatGoogleAPIError(w redact.SafePrinter, v interface{}) {
+	err := v.(*googleapi.Error)
+	w.Printf("googleapi: Error %d: %s", redact.Safe(err.Code), err.Message)
+	for _, item := range err.Errors {
+		w.Printf(", %s: %s", redact.Safe(item.Reason), item.Message)
+	}
+}
+
+func init() {
+	log.RegisterSafeStringer(reflect.TypeOf(&googleapi.Error{}),
+		"HTTP status code and error reasons", formatGoogleAPIError)
+}
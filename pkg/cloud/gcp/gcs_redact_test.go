@@ -0,0 +1,37 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package gcp
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logtestutils"
+	"google.golang.org/api/googleapi"
+)
+
+func TestGoogleAPIErrorRedaction(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	logtestutils.CheckSafeStringers(t,
+		logtestutils.SafeStringerCase{
+			Value: &googleapi.Error{
+				Code:    404,
+				Message: "No such object: secret-bucket/customers/alice.csv",
+				Errors: []googleapi.ErrorItem{
+					{Reason: "notFound", Message: "No such object: secret-bucket/customers/alice.csv"},
+				},
+			},
+			Sensitive: []string{"secret-bucket", "alice"},
+			Safe:      []string{"404", "notFound"},
+		},
+	)
+}
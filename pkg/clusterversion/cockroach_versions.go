@@ -294,6 +294,12 @@ const (
 	// ids in sequences' back references and attempts a best-effort-based matching
 	// to update those column IDs.
 	UpdateInvalidColumnIDsInSequenceBackReferences
+	// SystemSchemaChangeElementStatusesTable adds the
+	// system.schema_change_element_statuses table.
+	SystemSchemaChangeElementStatusesTable
+	// SystemSchemaChangeThroughputTable adds the
+	// system.schema_change_throughput table.
+	SystemSchemaChangeThroughputTable
 
 	// *************************************************
 	// Step (1): Add new versions here.
@@ -492,6 +498,14 @@ var rawVersionsSingleton = keyedVersions{
 		Key:     UpdateInvalidColumnIDsInSequenceBackReferences,
 		Version: roachpb.Version{Major: 22, Minor: 1, Internal: 66},
 	},
+	{
+		Key:     SystemSchemaChangeElementStatusesTable,
+		Version: roachpb.Version{Major: 22, Minor: 1, Internal: 68},
+	},
+	{
+		Key:     SystemSchemaChangeThroughputTable,
+		Version: roachpb.Version{Major: 22, Minor: 1, Internal: 70},
+	},
 
 	// *************************************************
 	// Step (2): Add new versions here.
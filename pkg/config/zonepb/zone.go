@@ -14,6 +14,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
@@ -21,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/errors"
 	"github.com/gogo/protobuf/proto"
@@ -1026,6 +1028,97 @@ func (z *ZoneConfig) DeleteIndexSubzones(indexID uint32) {
 	z.Subzones = subzones
 }
 
+// SetIndexGCTTL overrides the GC TTL of the specified index and of its
+// partitions, leaving the other fields of their subzones untouched. If the
+// index has no subzone of its own, one is added along with a subzone span
+// covering the whole index, unless spans for its partitions already exist.
+//
+// The span is derived from the index ID alone, which allows this to be used
+// for indexes which are no longer present in the table descriptor.
+func (z *ZoneConfig) SetIndexGCTTL(indexID uint32, ttlSeconds int32) {
+	hasIndexSubzone := false
+	for i := range z.Subzones {
+		if s := &z.Subzones[i]; s.IndexID == indexID {
+			s.Config.GC = &GCPolicy{TTLSeconds: ttlSeconds}
+			hasIndexSubzone = hasIndexSubzone || s.PartitionName == ""
+		}
+	}
+	if hasIndexSubzone {
+		return
+	}
+	z.Subzones = append(z.Subzones, Subzone{
+		IndexID: indexID,
+		Config:  ZoneConfig{GC: &GCPolicy{TTLSeconds: ttlSeconds}},
+	})
+	// Subzone span keys omit the table prefix.
+	key := roachpb.Key(encoding.EncodeUvarintAscending(nil, uint64(indexID)))
+	for _, s := range z.SubzoneSpans {
+		if bytes.HasPrefix(s.Key, key) {
+			return
+		}
+	}
+	i := sort.Search(len(z.SubzoneSpans), func(i int) bool {
+		return z.SubzoneSpans[i].Key.Compare(key) > 0
+	})
+	z.SubzoneSpans = append(z.SubzoneSpans, SubzoneSpan{})
+	copy(z.SubzoneSpans[i+1:], z.SubzoneSpans[i:])
+	z.SubzoneSpans[i] = SubzoneSpan{Key: key, SubzoneIndex: int32(len(z.Subzones) - 1)}
+}
+
+// CopyIndexSubzones copies the subzones of the specified source index and of
+// its partitions, along with their subzone spans, to the target index. Any
+// subzone of the target index with the same partition name is overwritten. It
+// returns whether it performed any work.
+//
+// The spans are derived from those of the source index by substituting the
+// index ID, which assumes that both indexes share the same partitioning.
+func (z *ZoneConfig) CopyIndexSubzones(fromIndexID, toIndexID uint32) bool {
+	subzoneIndexes := make(map[int32]int32)
+	for i := range z.Subzones {
+		if s := z.Subzones[i]; s.IndexID == fromIndexID {
+			s.IndexID = toIndexID
+			z.SetSubzone(s)
+			for j := range z.Subzones {
+				if z.Subzones[j].IndexID == toIndexID && z.Subzones[j].PartitionName == s.PartitionName {
+					subzoneIndexes[int32(i)] = int32(j)
+				}
+			}
+		}
+	}
+	if len(subzoneIndexes) == 0 {
+		return false
+	}
+	// Subzone span keys omit the table prefix.
+	fromKey := roachpb.Key(encoding.EncodeUvarintAscending(nil, uint64(fromIndexID)))
+	toKey := roachpb.Key(encoding.EncodeUvarintAscending(nil, uint64(toIndexID)))
+	rekey := func(k roachpb.Key) roachpb.Key {
+		if len(k) == 0 {
+			return k
+		}
+		return append(toKey[:len(toKey):len(toKey)], k[len(fromKey):]...)
+	}
+	spans := z.SubzoneSpans[:0:0]
+	for _, s := range z.SubzoneSpans {
+		if !bytes.HasPrefix(s.Key, toKey) {
+			spans = append(spans, s)
+		}
+	}
+	for _, s := range z.SubzoneSpans {
+		if j, ok := subzoneIndexes[s.SubzoneIndex]; ok && bytes.HasPrefix(s.Key, fromKey) {
+			spans = append(spans, SubzoneSpan{
+				Key:          rekey(s.Key),
+				EndKey:       rekey(s.EndKey),
+				SubzoneIndex: j,
+			})
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].Key.Compare(spans[j].Key) < 0
+	})
+	z.SubzoneSpans = spans
+	return true
+}
+
 // SubzoneSplits returns the split points determined by a ZoneConfig's subzones.
 func (z ZoneConfig) SubzoneSplits() []roachpb.RKey {
 	var out []roachpb.RKey
@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	proto "github.com/gogo/protobuf/proto"
@@ -475,6 +476,104 @@ func TestZoneConfigSubzones(t *testing.T) {
 	}
 }
 
+func TestZoneConfigSetIndexGCTTL(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	indexKey := func(indexID uint32) roachpb.Key {
+		return encoding.EncodeUvarintAscending(nil, uint64(indexID))
+	}
+	gc := func(ttlSeconds int32) ZoneConfig {
+		return ZoneConfig{GC: &GCPolicy{TTLSeconds: ttlSeconds}}
+	}
+
+	zone := ZoneConfig{}
+	zone.DeleteTableConfig()
+	zone.SetIndexGCTTL(3, 60)
+	zone.SetIndexGCTTL(5, 60)
+	zone.SetIndexGCTTL(1, 10)
+	require.Equal(t, []Subzone{
+		{IndexID: 3, Config: gc(60)},
+		{IndexID: 5, Config: gc(60)},
+		{IndexID: 1, Config: gc(10)},
+	}, zone.Subzones)
+	require.Equal(t, []SubzoneSpan{
+		{Key: indexKey(1), SubzoneIndex: 2},
+		{Key: indexKey(3), SubzoneIndex: 0},
+		{Key: indexKey(5), SubzoneIndex: 1},
+	}, zone.SubzoneSpans)
+	require.True(t, zone.IsSubzonePlaceholder())
+
+	// Overriding the TTL of an index with a subzone leaves the spans alone.
+	zone.SetIndexGCTTL(3, 30)
+	require.Equal(t, gc(30), zone.GetSubzoneExact(3, "").Config)
+	require.Len(t, zone.SubzoneSpans, 3)
+
+	// Partitions have their TTL overridden too, and their spans are not
+	// shadowed by a span for the whole index.
+	partitionSpan := SubzoneSpan{
+		Key: append(indexKey(7), 'a'), EndKey: append(indexKey(7), 'b'), SubzoneIndex: 3,
+	}
+	zone.SetSubzone(Subzone{
+		IndexID: 7, PartitionName: "p", Config: ZoneConfig{NumReplicas: proto.Int32(5)},
+	})
+	zone.SubzoneSpans = append(zone.SubzoneSpans, partitionSpan)
+	zone.SetIndexGCTTL(7, 10)
+	require.Equal(t, ZoneConfig{NumReplicas: proto.Int32(5), GC: &GCPolicy{TTLSeconds: 10}},
+		zone.GetSubzoneExact(7, "p").Config)
+	require.Equal(t, gc(10), zone.GetSubzoneExact(7, "").Config)
+	require.Equal(t, partitionSpan, zone.SubzoneSpans[len(zone.SubzoneSpans)-1])
+	require.Len(t, zone.SubzoneSpans, 4)
+}
+
+func TestZoneConfigCopyIndexSubzones(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	indexKey := func(indexID uint32) roachpb.Key {
+		return encoding.EncodeUvarintAscending(nil, uint64(indexID))
+	}
+	replicas := func(n int32) ZoneConfig {
+		return ZoneConfig{NumReplicas: proto.Int32(n)}
+	}
+
+	zone := ZoneConfig{}
+	zone.DeleteTableConfig()
+	require.False(t, zone.CopyIndexSubzones(1, 2))
+
+	zone.SetSubzone(Subzone{IndexID: 1, Config: replicas(5)})
+	zone.SetSubzone(Subzone{IndexID: 1, PartitionName: "p", Config: replicas(7)})
+	zone.SetSubzone(Subzone{IndexID: 3, Config: replicas(3)})
+	zone.SubzoneSpans = []SubzoneSpan{
+		{Key: indexKey(1), EndKey: append(indexKey(1), 'a'), SubzoneIndex: 0},
+		{Key: append(indexKey(1), 'a'), EndKey: append(indexKey(1), 'b'), SubzoneIndex: 1},
+		{Key: append(indexKey(1), 'b'), EndKey: indexKey(1).PrefixEnd(), SubzoneIndex: 0},
+		{Key: indexKey(3), SubzoneIndex: 2},
+	}
+	require.True(t, zone.CopyIndexSubzones(1, 4))
+	require.Equal(t, []Subzone{
+		{IndexID: 1, Config: replicas(5)},
+		{IndexID: 1, PartitionName: "p", Config: replicas(7)},
+		{IndexID: 3, Config: replicas(3)},
+		{IndexID: 4, Config: replicas(5)},
+		{IndexID: 4, PartitionName: "p", Config: replicas(7)},
+	}, zone.Subzones)
+	require.Equal(t, []SubzoneSpan{
+		{Key: indexKey(1), EndKey: append(indexKey(1), 'a'), SubzoneIndex: 0},
+		{Key: append(indexKey(1), 'a'), EndKey: append(indexKey(1), 'b'), SubzoneIndex: 1},
+		{Key: append(indexKey(1), 'b'), EndKey: indexKey(1).PrefixEnd(), SubzoneIndex: 0},
+		{Key: indexKey(3), SubzoneIndex: 2},
+		{Key: indexKey(4), EndKey: append(indexKey(4), 'a'), SubzoneIndex: 3},
+		{Key: append(indexKey(4), 'a'), EndKey: append(indexKey(4), 'b'), SubzoneIndex: 4},
+		{Key: append(indexKey(4), 'b'), EndKey: indexKey(4).PrefixEnd(), SubzoneIndex: 3},
+	}, zone.SubzoneSpans)
+
+	// Copying again overwrites the subzones of the target index in place.
+	zone.SetSubzone(Subzone{IndexID: 1, Config: replicas(9)})
+	require.True(t, zone.CopyIndexSubzones(1, 4))
+	require.Equal(t, replicas(9), zone.GetSubzoneExact(4, "").Config)
+	require.Len(t, zone.Subzones, 5)
+	require.Len(t, zone.SubzoneSpans, 7)
+}
+
 // TestZoneConfigMarshalYAML makes sure that ZoneConfig is correctly marshaled
 // to YAML and back.
 func TestZoneConfigMarshalYAML(t *testing.T) {
@@ -286,6 +286,9 @@ DOCS_SRCS = [
   "//docs/generated/sql:window_functions.md",
   "//docs/generated/swagger:spec.json",
   "//docs/generated:eventlog.md",
+  "//docs/generated:eventlog_channels.md",
+  "//docs/generated:log_channels.json",
+  "//docs/generated:log_channels.ts",
   "//docs/generated:logformats.md",
   "//docs/generated:logging.md",
   "//docs/generated:logsinks.md",
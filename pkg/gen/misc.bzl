@@ -15,10 +15,14 @@ MISC_SRCS = [
   "//pkg/util/interval/generic:example_interval_btree_test.go",
   "//pkg/util/log/channel:channel_generated.go",
   "//pkg/util/log/eventpb/eventpbgen:log_channels_generated.go",
+  "//pkg/util/log/eventpb:event_types_generated.go",
   "//pkg/util/log/eventpb:eventlog_channels_generated.go",
   "//pkg/util/log/eventpb:json_encode_generated.go",
   "//pkg/util/log/logpb:json_encode_generated.go",
+  "//pkg/util/log/logzap:zap_adapter_generated.go",
   "//pkg/util/log/severity:severity_generated.go",
   "//pkg/util/log:log_channels_generated.go",
+  "//pkg/util/log:log_metrics_generated.go",
+  "//pkg/util/log:test_log_capture_generated.go",
   "//pkg/util/timeutil:lowercase_timezones_generated.go",
 ]
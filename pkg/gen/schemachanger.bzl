@@ -9,12 +9,14 @@ SCHEMACHANGER_SRCS = [
   "//pkg/sql/schemachanger/rel:systemattribute_string.go",
   "//pkg/sql/schemachanger/scexec:mocks_generated_test.go",
   "//pkg/sql/schemachanger/scop:backfill_visitor_generated.go",
+  "//pkg/sql/schemachanger/scop:costclass_string.go",
   "//pkg/sql/schemachanger/scop:mutation_visitor_generated.go",
   "//pkg/sql/schemachanger/scop:phase_string.go",
   "//pkg/sql/schemachanger/scop:type_string.go",
   "//pkg/sql/schemachanger/scop:validation_visitor_generated.go",
   "//pkg/sql/schemachanger/scpb:elements_generated.go",
   "//pkg/sql/schemachanger/scpb:uml/table.puml",
+  "//pkg/sql/schemachanger/scplan/internal/opgen:dispatch_generated.go",
   "//pkg/sql/schemachanger/scplan/internal/scgraph:depedgekind_string.go",
   "//pkg/sql/schemachanger/screl:attr_string.go",
   "//pkg/sql/schemachanger:sctest_generated_test.go",
@@ -34,6 +34,7 @@ STRINGER_SRCS = [
   "//pkg/sql/schemachanger/rel/internal/cyclegraphtest:testattr_string.go",
   "//pkg/sql/schemachanger/rel/internal/entitynodetest:testattr_string.go",
   "//pkg/sql/schemachanger/rel:systemattribute_string.go",
+  "//pkg/sql/schemachanger/scop:costclass_string.go",
   "//pkg/sql/schemachanger/scop:phase_string.go",
   "//pkg/sql/schemachanger/scop:type_string.go",
   "//pkg/sql/schemachanger/scplan/internal/scgraph:depedgekind_string.go",
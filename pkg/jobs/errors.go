@@ -17,9 +17,11 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/errors/errorspb"
 )
 
 // errRetryJobSentinel exists so the errors returned from MarkAsRetryJobError can
@@ -65,6 +67,17 @@ func MarkPauseRequestError(reason error) error {
 	return errors.Mark(reason, errPauseSelfSentinel)
 }
 
+// errReleaseClaimSentinel exists so the errors returned from
+// MarkAsReleaseClaimError can be marked with it.
+var errReleaseClaimSentinel = errors.New("job requested its claim be released")
+
+// MarkAsReleaseClaimError marks an error as a request from the job to have its
+// claim released rather than be retried on the current node, so that it may be
+// adopted anew, possibly by another node.
+func MarkAsReleaseClaimError(reason error) error {
+	return errors.Mark(reason, errReleaseClaimSentinel)
+}
+
 // PauseRequestExplained is a prose used to wrap and explain a pause-request error.
 const PauseRequestExplained = "pausing due to error; use RESUME JOB to try to proceed once the issue is resolved, or CANCEL JOB to rollback"
 
@@ -160,22 +173,44 @@ func (e *retriableExecutionError) SafeFormatError(p errors.Printer) error {
 func (e *retriableExecutionError) toRetriableExecutionFailure(
 	ctx context.Context, maxErrorSize int,
 ) *jobspb.RetriableExecutionFailure {
-	// If the cause is too large, we format it, losing all structure, and retain
-	// a prefix.
 	ef := &jobspb.RetriableExecutionFailure{
 		Status:               string(e.status),
 		ExecutionStartMicros: timeutil.ToUnixMicros(e.start),
 		ExecutionEndMicros:   timeutil.ToUnixMicros(e.end),
 		InstanceID:           e.instanceID,
 	}
-	if encodedCause := errors.EncodeError(ctx, e.cause); encodedCause.Size() < maxErrorSize {
-		ef.Error = &encodedCause
-	} else {
-		formatted := e.cause.Error()
-		if len(formatted) > maxErrorSize {
-			formatted = formatted[:maxErrorSize]
-		}
-		ef.TruncatedError = formatted
-	}
+	ef.Error, ef.TruncatedError = encodeExecutionError(ctx, e.cause, maxErrorSize)
 	return ef
 }
+
+// EncodeExecutionError encodes an error encountered during the execution of
+// a job so that it can be persisted for introspection. If the encoded error
+// exceeds the jobs.execution_errors.max_entry_size cluster setting, a prefix
+// of the formatted error is returned instead.
+func EncodeExecutionError(
+	ctx context.Context, sv *settings.Values, err error,
+) (encoded *errorspb.EncodedError, truncated string) {
+	return encodeExecutionError(ctx, err, int(executionErrorsMaxEntrySize.Get(sv)))
+}
+
+// ExecutionErrorsMaxEntries returns the maximum number of errors encountered
+// during the execution of a job which are persisted for introspection, as
+// governed by the jobs.execution_errors.max_entries cluster setting.
+func ExecutionErrorsMaxEntries(sv *settings.Values) int {
+	return int(executionErrorsMaxEntriesSetting.Get(sv))
+}
+
+func encodeExecutionError(
+	ctx context.Context, err error, maxErrorSize int,
+) (encoded *errorspb.EncodedError, truncated string) {
+	// If the error is too large, we format it, losing all structure, and retain
+	// a prefix.
+	if encodedErr := errors.EncodeError(ctx, err); encodedErr.Size() < maxErrorSize {
+		return &encodedErr, ""
+	}
+	formatted := err.Error()
+	if len(formatted) > maxErrorSize {
+		formatted = formatted[:maxErrorSize]
+	}
+	return nil, formatted
+}
@@ -2803,6 +2803,60 @@ func TestRegistryTestingNudgeAdoptionQueue(t *testing.T) {
 		"started: %v, before:	%v", started, before)
 }
 
+// TestReleaseClaim ensures that a job which requests its claim be released is
+// adopted anew rather than retried in place.
+func TestReleaseClaim(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	defer jobs.ResetConstructors()()
+
+	ctx := context.Background()
+
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+	registry := s.JobRegistry().(*jobs.Registry)
+	tdb := sqlutils.MakeSQLRunner(db)
+
+	rec := jobs.Record{
+		DescriptorIDs: []descpb.ID{1},
+		Details:       jobspb.BackupDetails{},
+		Progress:      jobspb.BackupProgress{},
+	}
+	resuming := make(chan chan error)
+	jobs.RegisterConstructor(jobspb.TypeBackup, func(_ *jobs.Job, _ *cluster.Settings) jobs.Resumer {
+		return jobs.FakeResumer{
+			OnResume: func(ctx context.Context) error {
+				errCh := make(chan error)
+				resuming <- errCh
+				return <-errCh
+			},
+		}
+	}, jobs.UsesTenantCostControl)
+	jobID := registry.MakeJobID()
+	_, err := registry.CreateAdoptableJobWithTxn(ctx, rec, jobID, nil /* txn */)
+	require.NoError(t, err)
+
+	registry.TestingNudgeAdoptionQueue()
+	errCh := <-resuming
+	errCh <- jobs.MarkAsReleaseClaimError(errors.New("elsewhere"))
+	testutils.SucceedsSoon(t, func() error {
+		var claimed bool
+		tdb.QueryRow(t, `SELECT claim_session_id IS NOT NULL FROM system.jobs WHERE id = $1`,
+			jobID).Scan(&claimed)
+		if claimed {
+			return errors.New("claim not yet released")
+		}
+		return nil
+	})
+
+	registry.TestingNudgeAdoptionQueue()
+	errCh = <-resuming
+	errCh <- nil
+	require.NoError(t, registry.WaitForJobs(
+		ctx, s.InternalExecutor().(sqlutil.InternalExecutor), []jobspb.JobID{jobID},
+	))
+}
+
 func TestStatusSafeFormatter(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
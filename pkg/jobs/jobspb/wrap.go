@@ -369,6 +369,30 @@ func (m ChangefeedDetails) MarshalJSONPB(marshaller *jsonpb.Marshaler) ([]byte,
 	return json.Marshal(m)
 }
 
+// RunningStage returns the progress of the stage of the schema change which
+// is being executed, or nil if there is none.
+func (p *NewSchemaChangeProgress) RunningStage() *SchemaChangeStageProgress {
+	for i := range p.Stages {
+		if s := &p.Stages[i]; s.StartedMicros != 0 && s.CompletedMicros == 0 {
+			return s
+		}
+	}
+	return nil
+}
+
+// FractionCompleted returns the fraction of the stages of the schema change
+// which are done, including the partial progress of the running stage.
+func (p *NewSchemaChangeProgress) FractionCompleted() float32 {
+	if len(p.Stages) == 0 {
+		return 0
+	}
+	var sum float32
+	for i := range p.Stages {
+		sum += p.Stages[i].FractionCompleted
+	}
+	return sum / float32(len(p.Stages))
+}
+
 // DescRewriteMap maps old descriptor IDs to new descriptor and parent IDs.
 type DescRewriteMap map[descpb.ID]*DescriptorRewrite
 
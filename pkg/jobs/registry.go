@@ -688,6 +688,27 @@ SELECT claim_session_id
    AND status IN ` + claimableStatusTupleString + `
 )`
 
+const releaseClaimQuery = `
+UPDATE system.jobs
+   SET claim_session_id = NULL
+ WHERE id = $1
+   AND claim_session_id = $2`
+
+// releaseClaim removes the claim held on the job by the current session, which
+// makes it available for adoption by any node.
+func (r *Registry) releaseClaim(ctx context.Context, job *Job) error {
+	if job.session == nil {
+		return errors.AssertionFailedf("job %d: cannot release claim without a session", job.ID())
+	}
+	if _, err := r.ex.Exec(
+		ctx, "release-job-claim", nil /* txn */, releaseClaimQuery,
+		job.ID(), job.session.ID().UnsafeBytes(),
+	); err != nil {
+		return errors.Wrapf(err, "job %d: could not release claim", job.ID())
+	}
+	return nil
+}
+
 type withSessionFunc func(ctx context.Context, s sqlliveness.Session)
 
 func (r *Registry) withSession(ctx context.Context, f withSessionFunc) {
@@ -1262,6 +1283,13 @@ func (r *Registry) stepThroughStateMachine(
 			}
 			return errors.Wrap(err, PauseRequestExplained)
 		}
+		if errors.Is(err, errReleaseClaimSentinel) {
+			jm.ResumeRetryError.Inc(1)
+			if err := r.releaseClaim(ctx, job); err != nil {
+				return err
+			}
+			return errors.Wrapf(err, "job %d: released claim", job.ID())
+		}
 		// TODO(spaskob): enforce a limit on retries.
 
 		if nonCancelableRetry := job.Payload().Noncancelable && !IsPermanentJobError(err); nonCancelableRetry ||
@@ -20,6 +20,7 @@ import (
 	otel_logs_pb "github.com/cockroachdb/cockroach/pkg/obsservice/obspb/opentelemetry-proto/logs/v1"
 	otel_res_pb "github.com/cockroachdb/cockroach/pkg/obsservice/obspb/opentelemetry-proto/resource/v1"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
@@ -317,6 +318,10 @@ func (s *EventsServer) SendEvent(
 		return
 	}
 
+	if typ == obspb.EventlogEvent {
+		event.Attributes = withEventlogChannelAttribute(event.Attributes)
+	}
+
 	// Make sure there's room for the new event. If there isn't, we'll drop
 	// events from the front of the buffer (the oldest), until there is room.
 	newEventSize, err := sizeOfEvent(event)
@@ -359,6 +364,30 @@ func (s *EventsServer) SendEvent(
 	}
 }
 
+// withEventlogChannelAttribute returns the attributes of an EventlogEvent,
+// extended with the logging channel which the event was emitted on, as
+// determined by its event type. The attributes are returned unchanged if the
+// event type is unknown.
+func withEventlogChannelAttribute(attrs []*otel_pb.KeyValue) []*otel_pb.KeyValue {
+	for _, kv := range attrs {
+		if kv.Key != obspb.EventlogEventTypeAttribute {
+			continue
+		}
+		info, ok := eventpb.EventTypes[kv.Value.GetStringValue()]
+		if !ok {
+			break
+		}
+		// Copy the attributes, which may be shared with the caller.
+		res := make([]*otel_pb.KeyValue, len(attrs), len(attrs)+1)
+		copy(res, attrs)
+		return append(res, &otel_pb.KeyValue{
+			Key:   obspb.EventlogEventChannelAttribute,
+			Value: &otel_pb.AnyValue{Value: &otel_pb.AnyValue_StringValue{StringValue: info.Channel.String()}},
+		})
+	}
+	return attrs
+}
+
 // sizeOfEvent computes the size, in bytes, of event. This size will be used for
 // memory accounting.
 //
@@ -20,3 +20,7 @@ const (
 // EventlogEventTypeAttribute represents the key of the attribute containing
 // the event type of an EventlogEvent.
 const EventlogEventTypeAttribute = "event_type"
+
+// EventlogEventChannelAttribute represents the key of the attribute containing
+// the logging channel an EventlogEvent was emitted on.
+const EventlogEventChannelAttribute = "log_channel"
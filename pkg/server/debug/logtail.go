@@ -0,0 +1,185 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package debug
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/channel"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/redact"
+)
+
+// logTailOptions are the options of the /debug/logtail endpoint.
+//
+// In contrast to /debug/logspy, the endpoint streams the entries until
+// the client disconnects, unless a duration or a count is specified.
+type logTailOptions struct {
+	// Channels restricts the entries to the given channels, specified as
+	// a comma-separated list of channel names. All the channels are
+	// selected if empty.
+	Channels []log.Channel
+	// Severity is the minimum severity of the entries.
+	Severity log.Severity
+	Grep     regexpAsString
+	Redact   bool
+	Flatten  bool
+	// Count, if non-zero, stops the stream after that many entries.
+	Count int
+	// Duration, if non-zero, stops the stream after that duration.
+	Duration time.Duration
+}
+
+func logTailOptionsFromValues(values url.Values) (logTailOptions, error) {
+	var opts logTailOptions
+	if s := values.Get("channels"); s != "" {
+		for _, name := range strings.Split(s, ",") {
+			ch, ok := logpb.Channel_value[strings.ToUpper(strings.TrimSpace(name))]
+			if !ok {
+				return opts, errors.Newf("unknown channel: %q", name)
+			}
+			opts.Channels = append(opts.Channels, log.Channel(ch))
+		}
+	}
+	if s := values.Get("severity"); s != "" {
+		if err := opts.Severity.Set(s); err != nil {
+			return opts, err
+		}
+	}
+	// The remaining options are parsed like those of /debug/logspy.
+	rawValues := map[string]string{}
+	for _, k := range []string{"grep", "count", "duration"} {
+		if v := values.Get(k); v != "" {
+			rawValues[k] = v
+		}
+	}
+	data, err := json.Marshal(rawValues)
+	if err != nil {
+		return opts, err
+	}
+	var parsed struct {
+		Grep     regexpAsString
+		Count    intAsString
+		Duration durationAsString
+	}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return opts, err
+	}
+	opts.Grep = parsed.Grep
+	opts.Count = int(parsed.Count)
+	opts.Duration = time.Duration(parsed.Duration)
+	opts.Redact = values.Get("redact") != "" && values.Get("redact") != "0"
+	opts.Flatten = values.Get("flatten") != "" && values.Get("flatten") != "0"
+	return opts, nil
+}
+
+// logTail serves /debug/logtail, which streams the log entries
+// emitted from the moment the request is received.
+type logTail struct {
+	tail func(ctx context.Context, opts log.TailOptions) (*log.TailSubscription, func())
+}
+
+func (lt *logTail) handleDebugLogTail(w http.ResponseWriter, r *http.Request) {
+	opts, err := logTailOptionsFromValues(r.URL.Query())
+	if err != nil {
+		http.Error(w, "while parsing options: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Add("Content-type", "text/plain; charset=UTF-8")
+	ctx := r.Context()
+	if err := lt.run(ctx, w, opts); err != nil {
+		// This is likely a broken HTTP connection, so nothing too unexpected.
+		log.Infof(ctx, "%v", err)
+	}
+}
+
+func (lt *logTail) run(ctx context.Context, w io.Writer, opts logTailOptions) error {
+	if opts.Duration > 0 {
+		var cancel func()
+		ctx, cancel = context.WithTimeout(ctx, opts.Duration)
+		defer cancel()
+	}
+
+	sub, cleanup := lt.tail(ctx, log.TailOptions{
+		Channels: opts.Channels,
+		Severity: opts.Severity,
+		Pattern:  opts.Grep.re,
+		EditMode: log.SelectEditMode(opts.Redact, log.KeepRedactable),
+	})
+	defer cleanup()
+
+	const flushInterval = time.Second
+	var flushTimer timeutil.Timer
+	defer flushTimer.Stop()
+	flushTimer.Reset(flushInterval)
+
+	numReportedEntries := 0
+	for {
+		select {
+		case <-ctx.Done():
+			err := ctx.Err()
+			if errors.Is(err, context.DeadlineExceeded) {
+				// Common case: timeout after the configured duration.
+				return nil
+			}
+			return err
+
+		case entry := <-sub.Entries():
+			if dropped := sub.TakeDropped(); dropped > 0 {
+				warning := log.MakeLegacyEntry(
+					ctx, severity.WARNING, channel.DEV,
+					0 /* depth */, true, /* redactable */
+					"%d messages were dropped", redact.Safe(dropped))
+				if err := outputLogTailEntry(w, warning, opts.Flatten); err != nil {
+					return err
+				}
+			}
+			if err := outputLogTailEntry(w, entry, opts.Flatten); err != nil {
+				return errors.Wrap(err, "while writing entry")
+			}
+			numReportedEntries++
+			if opts.Count > 0 && numReportedEntries >= opts.Count {
+				return nil
+			}
+
+		case <-flushTimer.C:
+			flushTimer.Read = true
+			flushTimer.Reset(flushInterval)
+			if flusher, ok := w.(http.Flusher); ok {
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+func outputLogTailEntry(w io.Writer, entry logpb.Entry, flatten bool) error {
+	if flatten {
+		return log.FormatLegacyEntry(entry, w)
+	}
+	j, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	_, err1 := w.Write(j)
+	_, err2 := w.Write([]byte("\n"))
+	return errors.CombineErrors(err1, err2)
+}
@@ -0,0 +1,83 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package debug
+
+import (
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/channel"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugLogTailOptions(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testCases := []struct {
+		vals    url.Values
+		expOpts logTailOptions
+		expErr  string
+	}{
+		{
+			vals: map[string][]string{
+				"channels": {"ops, sql_schema"},
+				"severity": {"warning"},
+				"grep":     {`^foo$`},
+				"count":    {"12"},
+				"duration": {"9s"},
+				"redact":   {"1"},
+				"flatten":  {"1"},
+			},
+			expOpts: logTailOptions{
+				Channels: []log.Channel{channel.OPS, channel.SQL_SCHEMA},
+				Severity: severity.WARNING,
+				Grep:     regexpAsString{re: regexp.MustCompile(`^foo$`)},
+				Count:    12,
+				Duration: 9 * time.Second,
+				Redact:   true,
+				Flatten:  true,
+			},
+		},
+		{
+			// When nothing is given, stream everything until the client
+			// disconnects.
+			expOpts: logTailOptions{},
+		},
+		{
+			vals:   map[string][]string{"channels": {"nonexistent"}},
+			expErr: `unknown channel: "nonexistent"`,
+		},
+		{
+			vals:   map[string][]string{"count": {"many"}},
+			expErr: `strconv.Atoi: parsing "many": invalid syntax`,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run("", func(t *testing.T) {
+			opts, err := logTailOptionsFromValues(tc.vals)
+			if !testutils.IsError(err, tc.expErr) {
+				t.Fatalf("%d: expected error %s, got %s", i, tc.expErr, err)
+			}
+			if err == nil {
+				require.Equal(t, tc.expOpts.Grep.String(), opts.Grep.String())
+				tc.expOpts.Grep, opts.Grep = regexpAsString{}, regexpAsString{}
+				require.Equal(t, tc.expOpts, opts)
+			}
+		})
+	}
+}
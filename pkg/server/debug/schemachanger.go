@@ -0,0 +1,110 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package debug
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+)
+
+// schemaChangerEndpoint is the prefix of the /debug/schemachanger/{job_id}
+// endpoint.
+const schemaChangerEndpoint = "/debug/schemachanger/"
+
+// schemaChangerOptions are the options of the /debug/schemachanger endpoint.
+type schemaChangerOptions struct {
+	JobID int64
+	// Deps selects the dependency graph of the plan, instead of its stages.
+	Deps bool
+	// Viz redirects to a page rendering the graph, instead of serving it in
+	// the DOT format.
+	Viz bool
+}
+
+func schemaChangerOptionsFromRequest(path string, values url.Values) (schemaChangerOptions, error) {
+	var opts schemaChangerOptions
+	jobID, err := strconv.ParseInt(strings.TrimPrefix(path, schemaChangerEndpoint), 10, 64)
+	if err != nil {
+		return opts, errors.Wrap(err, "invalid job ID")
+	}
+	opts.JobID = jobID
+	switch g := values.Get("graph"); g {
+	case "", "stages":
+	case "deps":
+		opts.Deps = true
+	default:
+		return opts, errors.Newf("unknown graph: %q", g)
+	}
+	switch f := values.Get("format"); f {
+	case "", "dot":
+	case "viz":
+		opts.Viz = true
+	default:
+		return opts, errors.Newf("unknown format: %q", f)
+	}
+	return opts, nil
+}
+
+// schemaChanger serves /debug/schemachanger/{job_id}, which renders the plan
+// for the remaining stages of an in-flight declarative schema change job.
+type schemaChanger struct {
+	// drawPlan renders the plan of the job in the DOT format.
+	drawPlan func(ctx context.Context, jobID int64, deps bool) (string, error)
+	// vizURL returns the URL of a page rendering a graph in the DOT format.
+	vizURL func(dot string) (string, error)
+}
+
+func (sc *schemaChanger) handleDebugSchemaChanger(w http.ResponseWriter, r *http.Request) {
+	opts, err := schemaChangerOptionsFromRequest(r.URL.Path, r.URL.Query())
+	if err != nil {
+		http.Error(w, "while parsing options: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	dot, err := sc.drawPlan(r.Context(), opts.JobID, opts.Deps)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("while planning job %d: %v", opts.JobID, err), http.StatusInternalServerError)
+		return
+	}
+	if opts.Viz {
+		u, err := sc.vizURL(dot)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		http.Redirect(w, r, u, http.StatusFound)
+		return
+	}
+	w.Header().Add("Content-type", "text/vnd.graphviz; charset=UTF-8")
+	_, _ = fmt.Fprint(w, dot)
+}
+
+// RegisterSchemaChanger registers the /debug/schemachanger/{job_id} endpoint,
+// which renders the plan for the remaining stages of an in-flight declarative
+// schema change job, as drawn by drawPlan. By default, the stages of the plan
+// along with their progress are served in the DOT format. The following query
+// parameters are supported:
+//
+//   - graph=deps renders the dependency graph of the plan instead.
+//   - format=viz redirects to the URL returned by vizURL, which renders the
+//     graph as SVG in the browser.
+func (ds *Server) RegisterSchemaChanger(
+	drawPlan func(ctx context.Context, jobID int64, deps bool) (string, error),
+	vizURL func(dot string) (string, error),
+) {
+	sc := &schemaChanger{drawPlan: drawPlan, vizURL: vizURL}
+	ds.mux.HandleFunc(schemaChangerEndpoint, sc.handleDebugSchemaChanger)
+}
@@ -0,0 +1,113 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package debug
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugSchemaChangerOptions(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testCases := []struct {
+		path    string
+		vals    url.Values
+		expOpts schemaChangerOptions
+		expErr  string
+	}{
+		{
+			path:    "/debug/schemachanger/123",
+			expOpts: schemaChangerOptions{JobID: 123},
+		},
+		{
+			path: "/debug/schemachanger/123",
+			vals: map[string][]string{
+				"graph":  {"deps"},
+				"format": {"viz"},
+			},
+			expOpts: schemaChangerOptions{JobID: 123, Deps: true, Viz: true},
+		},
+		{
+			path:   "/debug/schemachanger/",
+			expErr: `invalid job ID`,
+		},
+		{
+			path:   "/debug/schemachanger/123",
+			vals:   map[string][]string{"graph": {"ops"}},
+			expErr: `unknown graph: "ops"`,
+		},
+		{
+			path:   "/debug/schemachanger/123",
+			vals:   map[string][]string{"format": {"svg"}},
+			expErr: `unknown format: "svg"`,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run("", func(t *testing.T) {
+			opts, err := schemaChangerOptionsFromRequest(tc.path, tc.vals)
+			if !testutils.IsError(err, tc.expErr) {
+				t.Fatalf("%d: expected error %s, got %s", i, tc.expErr, err)
+			}
+			if err == nil {
+				require.Equal(t, tc.expOpts, opts)
+			}
+		})
+	}
+}
+
+func TestDebugSchemaChangerHandler(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	sc := &schemaChanger{
+		drawPlan: func(_ context.Context, jobID int64, deps bool) (string, error) {
+			if jobID != 123 {
+				return "", errors.Newf("job %d not found", jobID)
+			}
+			if deps {
+				return "digraph deps {}", nil
+			}
+			return "digraph stages {}", nil
+		},
+		vizURL: func(dot string) (string, error) {
+			return "https://example.com/viz#" + url.PathEscape(dot), nil
+		},
+	}
+	get := func(target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		sc.handleDebugSchemaChanger(w, httptest.NewRequest("GET", target, nil))
+		return w
+	}
+
+	w := get("/debug/schemachanger/123")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "digraph stages {}", w.Body.String())
+
+	w = get("/debug/schemachanger/123?graph=deps&format=viz")
+	require.Equal(t, http.StatusFound, w.Code)
+	require.Equal(t, "https://example.com/viz#digraph%20deps%20%7B%7D", w.Header().Get("Location"))
+
+	w = get("/debug/schemachanger/456")
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	require.Contains(t, w.Body.String(), "job 456 not found")
+
+	w = get("/debug/schemachanger/abc")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -122,6 +122,11 @@ func NewServer(
 	}
 	mux.HandleFunc("/debug/logspy", spy.handleDebugLogSpy)
 
+	// Set up the log tail, which streams the newly emitted log entries
+	// until the client disconnects.
+	lt := logTail{tail: log.Tail}
+	mux.HandleFunc("/debug/logtail", lt.handleDebugLogTail)
+
 	ps := pprofui.NewServer(pprofui.NewMemStorage(pprofui.ProfileConcurrency, pprofui.ProfileExpiry), profiler)
 	mux.Handle("/debug/pprof/ui/", http.StripPrefix("/debug/pprof/ui", ps))
 
@@ -0,0 +1,58 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// logChannelSeverities overrides the severity thresholds of the
+// logging channels on every node, without a restart. The overrides are
+// global to the process, so the setting is reserved to the system
+// tenant: otherwise, in shared-process deployments, a secondary tenant
+// could silence or flood the logs of the host.
+var logChannelSeverities = settings.RegisterValidatedStringSetting(
+	settings.SystemOnly,
+	"server.log.channel_severities",
+	"comma-separated list of CHANNEL=SEVERITY pairs overriding the severity threshold "+
+		"configured for the listed logging channels (e.g. SQL_EXEC=INFO); "+
+		"empty to use the logging configuration",
+	"",
+	func(_ *settings.Values, s string) error {
+		_, err := log.ParseChannelSeverities(s)
+		return err
+	},
+)
+
+// startLogChannelSeverityOverrides applies the value of the
+// server.log.channel_severities cluster setting to the logging
+// subsystem, now and upon every change.
+//
+// Note that overrides set via the crdb_internal builtins are replaced
+// the next time the cluster setting changes.
+func startLogChannelSeverityOverrides(ctx context.Context, sv *settings.Values) {
+	apply := func(ctx context.Context) {
+		spec := logChannelSeverities.Get(sv)
+		if err := log.SetChannelSeverities(spec); err != nil {
+			// The setting is validated, so this is not expected.
+			log.Warningf(ctx, "unable to apply logging severity overrides %q: %v", spec, err)
+			return
+		}
+		log.Ops.Infof(ctx, "logging severity overrides set to %q", spec)
+	}
+	logChannelSeverities.SetOnChange(sv, apply)
+	if logChannelSeverities.Get(sv) != "" {
+		apply(ctx)
+	}
+}
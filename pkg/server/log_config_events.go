@@ -0,0 +1,46 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+	"github.com/cockroachdb/redact"
+)
+
+func init() {
+	// Report the changes to the logging configuration on the OPS
+	// channel, so that audits can reconstruct how log routing evolved.
+	log.ConfigChangeReporter = func(ctx context.Context, source string, diff redact.RedactableString) {
+		log.StructuredEvent(ctx, &eventpb.SetLoggingConfig{
+			Source: source,
+			Diff:   diff,
+		})
+	}
+
+	// Report the logging configuration applied at start-up on the OPS
+	// channel, so that debug zips contain an authoritative record of
+	// how logging was set up.
+	log.StartupReporter = func(ctx context.Context, report log.StartupReport) {
+		ev := &eventpb.LoggingStartupReport{Config: report.Config}
+		for _, s := range report.Sinks {
+			sink := s.Type + ":" + s.Name
+			ev.Sinks = append(ev.Sinks, sink)
+			if s.Err != nil {
+				ev.FailedProbes = append(ev.FailedProbes, fmt.Sprintf("%s: %v", sink, s.Err))
+			}
+		}
+		log.StructuredEvent(ctx, ev)
+	}
+}
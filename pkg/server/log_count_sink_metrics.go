@@ -0,0 +1,134 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/gogo/protobuf/proto"
+	prometheusgo "github.com/prometheus/client_model/go"
+)
+
+// logCountSinkMetrics reports the counts maintained by the count sinks
+// of the logging configuration. See log.CountSinks().
+type logCountSinkMetrics struct {
+	Entries         *logCountSinkEntries
+	OverflowEntries *metric.Gauge
+}
+
+// MetricStruct implements the metric.Struct interface.
+func (*logCountSinkMetrics) MetricStruct() {}
+
+var _ metric.Struct = (*logCountSinkMetrics)(nil)
+
+func newLogCountSinkMetrics() *logCountSinkMetrics {
+	return &logCountSinkMetrics{
+		Entries: &logCountSinkEntries{Metadata: metric.Metadata{
+			Name:        "log.count_sinks.entries",
+			Help:        "Number of log entries counted by the count sinks",
+			Measurement: "Log Entries",
+			Unit:        metric.Unit_COUNT,
+		}},
+		OverflowEntries: metric.NewFunctionalGauge(metric.Metadata{
+			Name:        "log.count_sinks.overflow_entries",
+			Help:        "Number of log entries not counted by the count sinks because they reached their maximum number of keys",
+			Measurement: "Log Entries",
+			Unit:        metric.Unit_COUNT,
+		}, func() (res int64) {
+			for _, s := range log.CountSinks() {
+				res += s.Overflow
+			}
+			return res
+		}),
+	}
+}
+
+// logCountSinkEntries is the total number of log entries counted by
+// the count sinks. When exported to Prometheus with child metrics
+// enabled, the count for every key of every sink is also reported,
+// labeled with the name of the sink and the label values of the key.
+type logCountSinkEntries struct {
+	metric.Metadata
+}
+
+var _ metric.Iterable = (*logCountSinkEntries)(nil)
+var _ metric.PrometheusIterable = (*logCountSinkEntries)(nil)
+
+// GetMetadata is part of the metric.Iterable interface.
+func (m *logCountSinkEntries) GetMetadata() metric.Metadata {
+	md := m.Metadata
+	md.MetricType = prometheusgo.MetricType_COUNTER
+	return md
+}
+
+// Inspect is part of the metric.Iterable interface.
+func (m *logCountSinkEntries) Inspect(f func(interface{})) { f(m) }
+
+// GetType is part of the metric.PrometheusExportable interface.
+func (m *logCountSinkEntries) GetType() *prometheusgo.MetricType {
+	return prometheusgo.MetricType_COUNTER.Enum()
+}
+
+// ToPrometheusMetric is part of the metric.PrometheusExportable interface.
+func (m *logCountSinkEntries) ToPrometheusMetric() *prometheusgo.Metric {
+	return &prometheusgo.Metric{
+		Counter: &prometheusgo.Counter{Value: proto.Float64(float64(m.count()))},
+	}
+}
+
+// Each is part of the metric.PrometheusIterable interface.
+func (m *logCountSinkEntries) Each(
+	labels []*prometheusgo.LabelPair, f func(metric *prometheusgo.Metric),
+) {
+	for _, s := range log.CountSinks() {
+		// Prometheus label names cannot contain colons: "file:line"
+		// is reported as "file_line".
+		names := make([]string, len(s.Labels))
+		for i, l := range s.Labels {
+			names[i] = strings.ReplaceAll(string(l), ":", "_")
+		}
+		for _, c := range s.Counts {
+			childLabels := make([]*prometheusgo.LabelPair, 0, len(labels)+1+len(names))
+			childLabels = append(childLabels, labels...)
+			childLabels = append(childLabels, &prometheusgo.LabelPair{
+				Name:  proto.String("sink"),
+				Value: proto.String(s.Name),
+			})
+			for i := range names {
+				childLabels = append(childLabels, &prometheusgo.LabelPair{
+					Name:  proto.String(names[i]),
+					Value: proto.String(c.Values[i]),
+				})
+			}
+			f(&prometheusgo.Metric{
+				Label:   childLabels,
+				Counter: &prometheusgo.Counter{Value: proto.Float64(float64(c.Count))},
+			})
+		}
+	}
+}
+
+// MarshalJSON marshals to JSON.
+func (m *logCountSinkEntries) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.count())
+}
+
+func (m *logCountSinkEntries) count() (res int64) {
+	for _, s := range log.CountSinks() {
+		for _, c := range s.Counts {
+			res += c.Count
+		}
+	}
+	return res
+}
@@ -0,0 +1,53 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+)
+
+// logMessageMetrics reports, for each logging channel and severity,
+// how many log entries were emitted by the logging methods of the
+// channel loggers. See log.Metrics.
+type logMessageMetrics struct {
+	// Count is indexed by channel, then by the index of the severity in
+	// log.MetricSeverities. It is flattened since the metric registry
+	// only supports arrays of metrics with a single dimension.
+	Count [int(logpb.Channel_CHANNEL_MAX) * len(log.MetricSeverities)]*metric.Counter
+}
+
+// MetricStruct implements the metric.Struct interface.
+func (*logMessageMetrics) MetricStruct() {}
+
+var _ metric.Struct = (*logMessageMetrics)(nil)
+
+func newLogMessageMetrics() *logMessageMetrics {
+	m := &logMessageMetrics{}
+	for i := range m.Count {
+		ch := log.Channel(i / len(log.MetricSeverities))
+		sev := log.MetricSeverities[i%len(log.MetricSeverities)]
+		m.Count[i] = metric.NewFunctionalCounter(metric.Metadata{
+			Name: fmt.Sprintf("log.messages.%s.%s.count",
+				strings.ToLower(ch.String()), strings.ToLower(sev.String())),
+			Help:        fmt.Sprintf("Number of log entries emitted on the %s channel with severity %s", ch, sev),
+			Measurement: "Log Entries",
+			Unit:        metric.Unit_COUNT,
+		}, func() int64 {
+			return log.EntryCount(ch, sev)
+		})
+	}
+	return m
+}
@@ -0,0 +1,61 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+)
+
+// logRedactionMetrics reports, for each logging channel, how many log
+// entries were emitted with and without a redactable payload. This
+// makes it possible to track the progress of the redaction annotation
+// work, and to alert on regressions in sensitive channels.
+type logRedactionMetrics struct {
+	Redactable   [logpb.Channel_CHANNEL_MAX]*metric.Counter
+	Unredactable [logpb.Channel_CHANNEL_MAX]*metric.Counter
+}
+
+// MetricStruct implements the metric.Struct interface.
+func (*logRedactionMetrics) MetricStruct() {}
+
+var _ metric.Struct = (*logRedactionMetrics)(nil)
+
+func newLogRedactionMetrics() *logRedactionMetrics {
+	m := &logRedactionMetrics{}
+	for i := range m.Redactable {
+		ch := log.Channel(i)
+		name := strings.ToLower(ch.String())
+		m.Redactable[i] = metric.NewFunctionalCounter(metric.Metadata{
+			Name:        fmt.Sprintf("log.%s.entries.redactable", name),
+			Help:        fmt.Sprintf("Number of log entries emitted on the %s channel with a redactable payload", ch),
+			Measurement: "Log Entries",
+			Unit:        metric.Unit_COUNT,
+		}, func() int64 {
+			redactable, _ := log.RedactionCoverage(ch)
+			return redactable
+		})
+		m.Unredactable[i] = metric.NewFunctionalCounter(metric.Metadata{
+			Name:        fmt.Sprintf("log.%s.entries.unredactable", name),
+			Help:        fmt.Sprintf("Number of log entries emitted on the %s channel with a payload that is not redactable", ch),
+			Measurement: "Log Entries",
+			Unit:        metric.Unit_COUNT,
+		}, func() int64 {
+			_, unredactable := log.RedactionCoverage(ch)
+			return unredactable
+		})
+	}
+	return m
+}
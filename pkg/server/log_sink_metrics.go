@@ -0,0 +1,86 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+)
+
+// logSinkTypes lists the types of log sinks reported by
+// log.SinkHealth().
+var logSinkTypes = [...]string{"file", "fluent", "http", "syslog", "journald", "eventlog"}
+
+// logSinkMetrics reports the health of the log sinks, aggregated per
+// type of sink. The details for each sink are available in the
+// crdb_internal.logging_sinks virtual table.
+type logSinkMetrics struct {
+	DeliveryFailures [len(logSinkTypes)]*metric.Counter
+	DroppedEntries   [len(logSinkTypes)]*metric.Counter
+	TruncatedEntries [len(logSinkTypes)]*metric.Counter
+	QueuedEntries    [len(logSinkTypes)]*metric.Gauge
+	QueuedBytes      [len(logSinkTypes)]*metric.Gauge
+}
+
+// MetricStruct implements the metric.Struct interface.
+func (*logSinkMetrics) MetricStruct() {}
+
+var _ metric.Struct = (*logSinkMetrics)(nil)
+
+func newLogSinkMetrics() *logSinkMetrics {
+	m := &logSinkMetrics{}
+	for i, typ := range logSinkTypes {
+		typ := typ
+		sum := func(fn func(h *log.SinkHealthInfo) int64) func() int64 {
+			return func() (res int64) {
+				for _, h := range log.SinkHealth() {
+					if h.Type == typ {
+						res += fn(&h)
+					}
+				}
+				return res
+			}
+		}
+		m.DeliveryFailures[i] = metric.NewFunctionalCounter(metric.Metadata{
+			Name:        fmt.Sprintf("log.sinks.%s.delivery_failures", typ),
+			Help:        fmt.Sprintf("Number of errors reported by the %s log sinks", typ),
+			Measurement: "Errors",
+			Unit:        metric.Unit_COUNT,
+		}, sum(func(h *log.SinkHealthInfo) int64 { return h.DeliveryFailures }))
+		m.DroppedEntries[i] = metric.NewFunctionalCounter(metric.Metadata{
+			Name:        fmt.Sprintf("log.sinks.%s.dropped_entries", typ),
+			Help:        fmt.Sprintf("Number of log entries dropped before they reached the %s log sinks", typ),
+			Measurement: "Log Entries",
+			Unit:        metric.Unit_COUNT,
+		}, sum(func(h *log.SinkHealthInfo) int64 { return h.DroppedEntries }))
+		m.TruncatedEntries[i] = metric.NewFunctionalCounter(metric.Metadata{
+			Name:        fmt.Sprintf("log.sinks.%s.truncated_entries", typ),
+			Help:        fmt.Sprintf("Number of log entries truncated because they exceeded the max-entry-size of the %s log sinks", typ),
+			Measurement: "Log Entries",
+			Unit:        metric.Unit_COUNT,
+		}, sum(func(h *log.SinkHealthInfo) int64 { return h.TruncatedEntries }))
+		m.QueuedEntries[i] = metric.NewFunctionalGauge(metric.Metadata{
+			Name:        fmt.Sprintf("log.sinks.%s.queued_entries", typ),
+			Help:        fmt.Sprintf("Number of log entries buffered for delivery to the %s log sinks", typ),
+			Measurement: "Log Entries",
+			Unit:        metric.Unit_COUNT,
+		}, sum(func(h *log.SinkHealthInfo) int64 { return h.QueuedEntries }))
+		m.QueuedBytes[i] = metric.NewFunctionalGauge(metric.Metadata{
+			Name:        fmt.Sprintf("log.sinks.%s.queued_bytes", typ),
+			Help:        fmt.Sprintf("Size of the log entries buffered for delivery to the %s log sinks", typ),
+			Measurement: "Memory",
+			Unit:        metric.Unit_BYTES,
+		}, sum(func(h *log.SinkHealthInfo) int64 { return h.QueuedBytes }))
+	}
+	return m
+}
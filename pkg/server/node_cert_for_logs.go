@@ -0,0 +1,52 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"crypto/tls"
+
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+)
+
+// nodeCertificateForLogs returns the function with which the log sinks
+// configured with sign-with-node-cert retrieve the node certificate.
+// See log.SetNodeCertificateFn().
+//
+// The key pair is only parsed again after the certificate manager has
+// reloaded the node certificate.
+func nodeCertificateForLogs(cm *security.CertificateManager) func() (*tls.Certificate, error) {
+	var mu struct {
+		syncutil.Mutex
+		certInfo *security.CertInfo
+		cert     *tls.Certificate
+	}
+	return func() (*tls.Certificate, error) {
+		certInfo := cm.NodeCert()
+		if certInfo == nil {
+			return nil, errors.New("no node certificate found")
+		}
+		if certInfo.Error != nil {
+			return nil, certInfo.Error
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if certInfo != mu.certInfo {
+			cert, err := tls.X509KeyPair(certInfo.FileContents, certInfo.KeyFileContents)
+			if err != nil {
+				return nil, errors.Wrap(err, "parsing the node certificate")
+			}
+			mu.certInfo, mu.cert = certInfo, &cert
+		}
+		return mu.cert, nil
+	}
+}
@@ -72,6 +72,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/optionalnodeliveness"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire"
 	_ "github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scjob" // register jobs declared outside of pkg/sql
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scplan"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
 	_ "github.com/cockroachdb/cockroach/pkg/sql/ttl/ttljob"      // register jobs declared outside of pkg/sql
 	_ "github.com/cockroachdb/cockroach/pkg/sql/ttl/ttlschedule" // register schedules declared outside of pkg/sql
@@ -292,6 +293,7 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 		}
 		cm.RegisterSignalHandler(stopper)
 		registry.AddMetricStruct(cm.Metrics())
+		log.SetNodeCertificateFn(nodeCertificateForLogs(cm))
 	}
 
 	// Check the compatibility between the configured addresses and that
@@ -325,6 +327,10 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 
 	runtimeSampler := status.NewRuntimeStatSampler(ctx, clock)
 	registry.AddMetricStruct(runtimeSampler)
+	registry.AddMetricStruct(newLogRedactionMetrics())
+	registry.AddMetricStruct(newLogMessageMetrics())
+	registry.AddMetricStruct(newLogSinkMetrics())
+	registry.AddMetricStruct(newLogCountSinkMetrics())
 
 	registry.AddMetric(base.LicenseTTL)
 
@@ -867,6 +873,7 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 	sStatus.setStmtDiagnosticsRequester(sqlServer.execCfg.StmtDiagnosticsRecorder)
 	sStatus.baseStatusServer.sqlServer = sqlServer
 	debugServer := debug.NewServer(cfg.BaseConfig.AmbientCtx, st, sqlServer.pgServer.HBADebugFn(), sStatus)
+	debugServer.RegisterSchemaChanger(sqlServer.drawSchemaChangePlan, scplan.VizURL)
 	node.InitLogger(sqlServer.execCfg)
 
 	drain := newDrainServer(cfg.BaseConfig, stopper, grpcServer, sqlServer)
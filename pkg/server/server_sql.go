@@ -28,6 +28,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/featureflag"
 	"github.com/cockroachdb/cockroach/pkg/gossip"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/bulk"
@@ -1249,6 +1250,9 @@ func (s *SQLServer) preStart(
 		return err
 	}
 	s.stmtDiagnosticsRegistry.Start(ctx, stopper)
+	if s.execCfg.Codec.ForSystemTenant() {
+		startLogChannelSeverityOverrides(ctx, &s.execCfg.Settings.SV)
+	}
 	if err := s.execCfg.TableStatsCache.Start(ctx, s.execCfg.Codec, s.execCfg.RangeFeedFactory); err != nil {
 		return err
 	}
@@ -1382,6 +1386,12 @@ func (s *SQLServer) preStart(
 	)
 
 	scheduledlogging.Start(ctx, stopper, s.execCfg.DB, s.execCfg.Settings, s.internalExecutor, s.execCfg.CaptureIndexUsageStatsKnobs)
+
+	// Report whether the declarative schema changer is disabled right away,
+	// rather than upon the first schema change. This doesn't prevent the
+	// server from starting, schema changes fall back to the legacy schema
+	// changer.
+	_ = sql.CheckDeclarativeSchemaChanger(ctx)
 	return nil
 }
 
@@ -1404,6 +1414,14 @@ func (s *SQLServer) AnnotateCtx(ctx context.Context) context.Context {
 	return s.ambientCtx.AnnotateCtx(ctx)
 }
 
+// drawSchemaChangePlan renders the plan of the declarative schema change job
+// with the given ID in the DOT format, for the /debug/schemachanger endpoint.
+func (s *SQLServer) drawSchemaChangePlan(
+	ctx context.Context, jobID int64, deps bool,
+) (string, error) {
+	return sql.DeclarativeSchemaChangePlanDOT(ctx, s.execCfg, jobspb.JobID(jobID), deps)
+}
+
 // startServeSQL starts accepting incoming SQL connections over TCP.
 // It also starts listening on the Unix socket, if that was configured.
 func (s *SQLServer) startServeSQL(
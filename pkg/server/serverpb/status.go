@@ -32,6 +32,10 @@ type SQLStatusServer interface {
 	StatementDetails(context.Context, *StatementDetailsRequest) (*StatementDetailsResponse, error)
 	ListDistSQLFlows(context.Context, *ListDistSQLFlowsRequest) (*ListDistSQLFlowsResponse, error)
 	ListLocalDistSQLFlows(context.Context, *ListDistSQLFlowsRequest) (*ListDistSQLFlowsResponse, error)
+	LoggingState(context.Context, *LoggingStateRequest) (*LoggingStateResponse, error)
+	LocalLoggingState(context.Context, *LoggingStateRequest) (*LoggingStateResponse, error)
+	RecentLogEntries(context.Context, *RecentLogEntriesRequest) (*RecentLogEntriesResponse, error)
+	LocalRecentLogEntries(context.Context, *RecentLogEntriesRequest) (*RecentLogEntriesResponse, error)
 	Profile(context.Context, *ProfileRequest) (*JSONResponse, error)
 	IndexUsageStatistics(context.Context, *IndexUsageStatisticsRequest) (*IndexUsageStatisticsResponse, error)
 	ResetIndexUsageStats(context.Context, *ResetIndexUsageStatsRequest) (*ResetIndexUsageStatsResponse, error)
@@ -90,6 +90,9 @@ const (
 	// Default Maximum number of log entries returned.
 	defaultMaxLogEntries = 1000
 
+	// Maximum number of log entries sent in a single TailLogs response.
+	maxTailLogsBatchSize = 100
+
 	// statusPrefix is the root of the cluster statistics and metrics API.
 	statusPrefix = "/_status/"
 
@@ -419,6 +422,81 @@ func (b *baseStatusServer) ListLocalDistSQLFlows(
 	return response, nil
 }
 
+// LocalLoggingState returns the run-time logging configuration of this
+// node, along with the occupancy of the buffers of its log sinks.
+func (b *baseStatusServer) LocalLoggingState(
+	ctx context.Context, _ *serverpb.LoggingStateRequest,
+) (*serverpb.LoggingStateResponse, error) {
+	ctx = propagateGatewayMetadata(ctx)
+	ctx = b.AnnotateCtx(ctx)
+
+	if _, err := b.privilegeChecker.requireAdminUser(ctx); err != nil {
+		// NB: not using serverError() here since the priv checker
+		// already returns a proper gRPC error status.
+		return nil, err
+	}
+
+	nodeIDOrZero, _ := b.sqlServer.sqlIDContainer.OptionalNodeID()
+	state := serverpb.NodeLoggingState{
+		NodeID:            nodeIDOrZero,
+		VModule:           log.GetVModule(),
+		ChannelSeverities: log.GetChannelSeverities(),
+	}
+	for ch := log.Channel(0); ch < logpb.Channel_CHANNEL_MAX; ch++ {
+		if vmodule := log.GetVModuleForChannel(ch); vmodule != "" {
+			if state.ChannelVModule == nil {
+				state.ChannelVModule = make(map[string]string)
+			}
+			state.ChannelVModule[ch.String()] = vmodule
+		}
+	}
+	for _, h := range log.SinkHealth() {
+		sink := serverpb.NodeLoggingState_Sink{
+			Type:           h.Type,
+			Name:           h.Name,
+			Target:         h.Target,
+			Buffered:       h.Buffered,
+			Spooled:        h.Spooled,
+			QueuedEntries:  h.QueuedEntries,
+			QueuedBytes:    h.QueuedBytes,
+			MaxQueuedBytes: h.MaxQueuedBytes,
+		}
+		for _, ch := range h.Channels {
+			sink.Channels = append(sink.Channels, ch.String())
+		}
+		state.Sinks = append(state.Sinks, sink)
+	}
+	return &serverpb.LoggingStateResponse{
+		Nodes: []serverpb.NodeLoggingState{state},
+	}, nil
+}
+
+// LocalRecentLogEntries returns the log entries of severity WARNING and
+// above retained in memory by this node.
+func (b *baseStatusServer) LocalRecentLogEntries(
+	ctx context.Context, req *serverpb.RecentLogEntriesRequest,
+) (*serverpb.RecentLogEntriesResponse, error) {
+	ctx = propagateGatewayMetadata(ctx)
+	ctx = b.AnnotateCtx(ctx)
+
+	if _, err := b.privilegeChecker.requireAdminUser(ctx); err != nil {
+		// NB: not using serverError() here since the priv checker
+		// already returns a proper gRPC error status.
+		return nil, err
+	}
+
+	nodeIDOrZero, _ := b.sqlServer.sqlIDContainer.OptionalNodeID()
+	editMode := log.SelectEditMode(req.Redact, log.KeepRedactable)
+	entries := log.RecentEntries(req.Severity, editMode)
+	response := &serverpb.RecentLogEntriesResponse{
+		Entries: make([]serverpb.RecentLogEntry, len(entries)),
+	}
+	for i, e := range entries {
+		response.Entries[i] = serverpb.RecentLogEntry{NodeID: nodeIDOrZero, Entry: e}
+	}
+	return response, nil
+}
+
 func (b *baseStatusServer) localExecutionInsights(
 	ctx context.Context,
 ) (*serverpb.ListExecutionInsightsResponse, error) {
@@ -1308,6 +1386,109 @@ func (s *statusServer) Logs(
 	return &serverpb.LogEntriesResponse{Entries: entries}, nil
 }
 
+// TailLogs streams the log entries emitted by a node from the moment
+// the call is received, until the client cancels it. The entries can be
+// filtered by channel, minimum severity and a regexp pattern on the
+// message.
+//
+// Entries are batched into responses as they become available. If the
+// client does not keep up, entries are dropped and the number of
+// dropped entries is reported in the next response.
+func (s *statusServer) TailLogs(
+	req *serverpb.TailLogsRequest, stream serverpb.Status_TailLogsServer,
+) error {
+	ctx := propagateGatewayMetadata(stream.Context())
+	ctx = s.AnnotateCtx(ctx)
+
+	if _, err := s.privilegeChecker.requireAdminUser(ctx); err != nil {
+		// NB: not using serverError() here since the priv checker
+		// already returns a proper gRPC error status.
+		return err
+	}
+
+	nodeID, local, err := s.parseNodeID(req.NodeId)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	if !local {
+		// This request is for another node. Forward it, along with all
+		// the responses.
+		client, err := s.dialNode(ctx, nodeID)
+		if err != nil {
+			return serverError(ctx, err)
+		}
+		return delegateTailLogs(ctx, req, client, stream)
+	}
+
+	var regex *regexp.Regexp
+	if len(req.Pattern) > 0 {
+		if regex, err = regexp.Compile(req.Pattern); err != nil {
+			return status.Errorf(codes.InvalidArgument, "regex pattern could not be compiled: %s", err)
+		}
+	}
+
+	sub, cleanup := log.Tail(ctx, log.TailOptions{
+		Channels: req.Channels,
+		Severity: req.Severity,
+		Pattern:  regex,
+		EditMode: log.SelectEditMode(req.Redact, log.KeepRedactable),
+	})
+	defer cleanup()
+
+	for {
+		var resp serverpb.TailLogsResponse
+		select {
+		case e := <-sub.Entries():
+			resp.Entries = append(resp.Entries, e)
+		case <-ctx.Done():
+			return nil
+		case <-s.stopper.ShouldQuiesce():
+			return nil
+		}
+		// Collect the entries that are already available, so that bursts
+		// of entries do not result in as many responses.
+	batch:
+		for len(resp.Entries) < maxTailLogsBatchSize {
+			select {
+			case e := <-sub.Entries():
+				resp.Entries = append(resp.Entries, e)
+			default:
+				break batch
+			}
+		}
+		resp.Dropped = sub.TakeDropped()
+		if err := stream.Send(&resp); err != nil {
+			return err
+		}
+	}
+}
+
+// delegateTailLogs forwards a TailLogs request to another node, and
+// relays all the responses to the client.
+func delegateTailLogs(
+	ctx context.Context,
+	req *serverpb.TailLogsRequest,
+	client serverpb.StatusClient,
+	stream serverpb.Status_TailLogsServer,
+) error {
+	tailClient, err := client.TailLogs(ctx, req)
+	if err != nil {
+		return err
+	}
+	for {
+		resp, err := tailClient.Recv()
+		if err != nil {
+			if err == io.EOF || ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
 // Stacks returns goroutine or thread stack traces.
 func (s *statusServer) Stacks(
 	ctx context.Context, req *serverpb.StacksRequest,
@@ -3081,6 +3262,96 @@ func (s *statusServer) ListDistSQLFlows(
 	return &response, nil
 }
 
+// LoggingState returns the run-time logging configuration of every node
+// in the cluster, along with the occupancy of the buffers of their log
+// sinks, in a single fan-out call.
+func (s *statusServer) LoggingState(
+	ctx context.Context, request *serverpb.LoggingStateRequest,
+) (*serverpb.LoggingStateResponse, error) {
+	ctx = propagateGatewayMetadata(ctx)
+	ctx = s.AnnotateCtx(ctx)
+
+	// Check permissions early to avoid fan-out to all nodes.
+	if _, err := s.privilegeChecker.requireAdminUser(ctx); err != nil {
+		// NB: not using serverError() here since the priv checker
+		// already returns a proper gRPC error status.
+		return nil, err
+	}
+
+	var response serverpb.LoggingStateResponse
+	dialFn := func(ctx context.Context, nodeID roachpb.NodeID) (interface{}, error) {
+		client, err := s.dialNode(ctx, nodeID)
+		return client, err
+	}
+	nodeFn := func(ctx context.Context, client interface{}, _ roachpb.NodeID) (interface{}, error) {
+		statusClient := client.(serverpb.StatusClient)
+		return statusClient.LocalLoggingState(ctx, request)
+	}
+	responseFn := func(_ roachpb.NodeID, nodeResp interface{}) {
+		if nodeResp == nil {
+			return
+		}
+		response.Nodes = append(response.Nodes, nodeResp.(*serverpb.LoggingStateResponse).Nodes...)
+	}
+	errorFn := func(nodeID roachpb.NodeID, err error) {
+		errResponse := serverpb.ListActivityError{NodeID: nodeID, Message: err.Error()}
+		response.Errors = append(response.Errors, errResponse)
+	}
+
+	if err := s.iterateNodes(ctx, "logging state", dialFn, nodeFn, responseFn, errorFn); err != nil {
+		return nil, serverError(ctx, err)
+	}
+	sort.Slice(response.Nodes, func(i, j int) bool {
+		return response.Nodes[i].NodeID < response.Nodes[j].NodeID
+	})
+	return &response, nil
+}
+
+// RecentLogEntries returns the log entries of severity WARNING and above
+// retained in memory by every node in the cluster.
+func (s *statusServer) RecentLogEntries(
+	ctx context.Context, request *serverpb.RecentLogEntriesRequest,
+) (*serverpb.RecentLogEntriesResponse, error) {
+	ctx = propagateGatewayMetadata(ctx)
+	ctx = s.AnnotateCtx(ctx)
+
+	// Check permissions early to avoid fan-out to all nodes.
+	if _, err := s.privilegeChecker.requireAdminUser(ctx); err != nil {
+		// NB: not using serverError() here since the priv checker
+		// already returns a proper gRPC error status.
+		return nil, err
+	}
+
+	var response serverpb.RecentLogEntriesResponse
+	dialFn := func(ctx context.Context, nodeID roachpb.NodeID) (interface{}, error) {
+		client, err := s.dialNode(ctx, nodeID)
+		return client, err
+	}
+	nodeFn := func(ctx context.Context, client interface{}, _ roachpb.NodeID) (interface{}, error) {
+		statusClient := client.(serverpb.StatusClient)
+		return statusClient.LocalRecentLogEntries(ctx, request)
+	}
+	responseFn := func(_ roachpb.NodeID, nodeResp interface{}) {
+		if nodeResp == nil {
+			return
+		}
+		response.Entries = append(response.Entries, nodeResp.(*serverpb.RecentLogEntriesResponse).Entries...)
+	}
+	errorFn := func(nodeID roachpb.NodeID, err error) {
+		errResponse := serverpb.ListActivityError{NodeID: nodeID, Message: err.Error()}
+		response.Errors = append(response.Errors, errResponse)
+	}
+
+	if err := s.iterateNodes(ctx, "recent log entries", dialFn, nodeFn, responseFn, errorFn); err != nil {
+		return nil, serverError(ctx, err)
+	}
+	// The entries of each node are already ordered by timestamp.
+	sort.SliceStable(response.Entries, func(i, j int) bool {
+		return response.Entries[i].NodeID < response.Entries[j].NodeID
+	})
+	return &response, nil
+}
+
 // mergeDistSQLRemoteFlows takes in two slices of DistSQL remote flows (that
 // satisfy the contract of serverpb.ListDistSQLFlowsResponse) and merges them
 // together while adhering to the same contract.
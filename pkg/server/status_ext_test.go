@@ -15,6 +15,7 @@ import (
 	"fmt"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
@@ -22,6 +23,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/testutils/testcluster"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/channel"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+	"github.com/cockroachdb/redact"
 	"github.com/stretchr/testify/require"
 )
 
@@ -68,3 +72,57 @@ func TestStatusLocalStacks(t *testing.T) {
 		})
 	}
 }
+
+// TestStatusTailLogs verifies that newly emitted log entries are
+// streamed via the TailLogs RPC, both locally and when forwarded.
+func TestStatusTailLogs(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	tc := testcluster.StartTestCluster(t, 2 /* nodes */, base.TestClusterArgs{})
+	defer tc.Stopper().Stop(ctx)
+
+	cc, err := tc.GetStatusClient(ctx, t, 0 /* idx */)
+	require.NoError(t, err)
+
+	for _, nodeID := range []string{"local", "2"} {
+		t.Run("node="+nodeID, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+
+			marker := fmt.Sprintf("tail logs marker %s", nodeID)
+			stream, err := cc.TailLogs(ctx, &serverpb.TailLogsRequest{
+				NodeId:   nodeID,
+				Channels: []log.Channel{channel.OPS},
+				Severity: severity.WARNING,
+				Pattern:  marker,
+			})
+			require.NoError(t, err)
+
+			// The subscription is established asynchronously, so keep
+			// emitting the marker until it is received.
+			done := make(chan struct{})
+			defer close(done)
+			go func() {
+				for {
+					log.Ops.Warningf(context.Background(), "%s", redact.Safe(marker))
+					select {
+					case <-done:
+						return
+					case <-time.After(10 * time.Millisecond):
+					}
+				}
+			}()
+
+			resp, err := stream.Recv()
+			require.NoError(t, err)
+			require.NotEmpty(t, resp.Entries)
+			for _, e := range resp.Entries {
+				require.Equal(t, channel.OPS, e.Channel)
+				require.Equal(t, severity.WARNING, e.Severity)
+				require.Equal(t, marker, e.Message)
+			}
+		})
+	}
+}
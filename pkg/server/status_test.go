@@ -2429,6 +2429,78 @@ func TestListActivitySecurity(t *testing.T) {
 	}
 }
 
+func TestStatusLoggingState(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	testCluster := serverutils.StartNewTestCluster(t, 2, base.TestClusterArgs{})
+	defer testCluster.Stopper().Stop(ctx)
+
+	// The logging configuration is process-wide, hence shared by the
+	// nodes of the test cluster.
+	require.NoError(t, log.SetVModule("status=2"))
+	defer func() { _ = log.SetVModule("") }()
+
+	var resp serverpb.LoggingStateResponse
+	require.NoError(t, getStatusJSONProtoWithAdminOption(
+		testCluster.Server(0), "logging_state", &resp, true /* isAdmin */))
+	require.Empty(t, resp.Errors)
+	require.Len(t, resp.Nodes, 2)
+	for i, n := range resp.Nodes {
+		require.Equal(t, testCluster.Server(i).NodeID(), n.NodeID)
+		require.Equal(t, "status=2", n.VModule)
+	}
+
+	// The endpoint is restricted to admin users.
+	err := getStatusJSONProtoWithAdminOption(
+		testCluster.Server(0), "local_logging_state", &resp, false /* isAdmin */)
+	require.True(t, testutils.IsError(err, "status: 403"), "expected 403 error, got %v", err)
+
+	sqlDB := sqlutils.MakeSQLRunner(testCluster.ServerConn(1))
+	sqlDB.CheckQueryResults(t, `
+SELECT node_id, value FROM crdb_internal.cluster_logging WHERE kind = 'vmodule' ORDER BY node_id`,
+		[][]string{{"1", "status=2"}, {"2", "status=2"}})
+}
+
+func TestStatusRecentLogEntries(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	testCluster := serverutils.StartNewTestCluster(t, 2, base.TestClusterArgs{})
+	defer testCluster.Stopper().Stop(ctx)
+
+	// The recent entries are retained process-wide, hence reported by
+	// every node of the test cluster.
+	const marker = "recent log entries test"
+	log.Ops.Warningf(ctx, "%s", marker)
+
+	var resp serverpb.RecentLogEntriesResponse
+	require.NoError(t, getStatusJSONProtoWithAdminOption(
+		testCluster.Server(0), "recent_log_entries", &resp, true /* isAdmin */))
+	require.Empty(t, resp.Errors)
+	var nodeIDs []roachpb.NodeID
+	for _, e := range resp.Entries {
+		if strings.Contains(e.Entry.Message, marker) {
+			require.Equal(t, logpb.Severity_WARNING, e.Entry.Severity)
+			nodeIDs = append(nodeIDs, e.NodeID)
+		}
+	}
+	require.Equal(t, []roachpb.NodeID{1, 2}, nodeIDs)
+
+	// The endpoint is restricted to admin users.
+	err := getStatusJSONProtoWithAdminOption(
+		testCluster.Server(0), "local_recent_log_entries", &resp, false /* isAdmin */)
+	require.True(t, testutils.IsError(err, "status: 403"), "expected 403 error, got %v", err)
+
+	sqlDB := sqlutils.MakeSQLRunner(testCluster.ServerConn(1))
+	sqlDB.CheckQueryResults(t, `
+SELECT node_id, severity, channel FROM crdb_internal.node_recent_log_entries
+WHERE message LIKE '%`+marker+`%' ORDER BY node_id`,
+		[][]string{{"1", "WARNING", "OPS"}, {"2", "WARNING", "OPS"}})
+}
+
 func TestMergeDistSQLRemoteFlows(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -826,6 +826,46 @@ func (t *tenantStatusServer) ListLocalDistSQLFlows(
 	return t.baseStatusServer.ListLocalDistSQLFlows(ctx, request)
 }
 
+func (t *tenantStatusServer) LoggingState(
+	ctx context.Context, request *serverpb.LoggingStateRequest,
+) (*serverpb.LoggingStateResponse, error) {
+	if t.sqlServer.SQLInstanceID() == 0 {
+		return nil, status.Errorf(codes.Unavailable, "instanceID not set")
+	}
+
+	return t.LocalLoggingState(ctx, request)
+}
+
+func (t *tenantStatusServer) LocalLoggingState(
+	ctx context.Context, request *serverpb.LoggingStateRequest,
+) (*serverpb.LoggingStateResponse, error) {
+	if t.sqlServer.SQLInstanceID() == 0 {
+		return nil, status.Errorf(codes.Unavailable, "instanceID not set")
+	}
+
+	return t.baseStatusServer.LocalLoggingState(ctx, request)
+}
+
+func (t *tenantStatusServer) RecentLogEntries(
+	ctx context.Context, request *serverpb.RecentLogEntriesRequest,
+) (*serverpb.RecentLogEntriesResponse, error) {
+	if t.sqlServer.SQLInstanceID() == 0 {
+		return nil, status.Errorf(codes.Unavailable, "instanceID not set")
+	}
+
+	return t.LocalRecentLogEntries(ctx, request)
+}
+
+func (t *tenantStatusServer) LocalRecentLogEntries(
+	ctx context.Context, request *serverpb.RecentLogEntriesRequest,
+) (*serverpb.RecentLogEntriesResponse, error) {
+	if t.sqlServer.SQLInstanceID() == 0 {
+		return nil, status.Errorf(codes.Unavailable, "instanceID not set")
+	}
+
+	return t.baseStatusServer.LocalRecentLogEntries(ctx, request)
+}
+
 // Profile implements the profiling endpoint by delegating the request
 // to the local handler. If the requested node_id is not the same as
 // the current instance ID, it performs an RPC call to fetch the profile
@@ -918,6 +918,51 @@ func NumRangesInSpanContainedBy(
 	return total, inContainedBy, nil
 }
 
+// LeaseholderLocality returns the most specific locality shared by the nodes
+// holding the leases for the ranges overlapping the given spans, insofar as
+// they are known to the DistSQL planner.
+func LeaseholderLocality(
+	ctx context.Context, db *kv.DB, distSQLPlanner *DistSQLPlanner, spans []roachpb.Span,
+) (roachpb.Locality, error) {
+	txn := db.NewTxn(ctx, "leaseholder-locality")
+	spanResolver := distSQLPlanner.spanResolver.NewSpanResolverIterator(txn)
+	var tiers []roachpb.Tier
+	first := true
+	for _, span := range spans {
+		for spanResolver.Seek(ctx, span, kvcoord.Ascending); ; spanResolver.Next(ctx) {
+			if !spanResolver.Valid() {
+				return roachpb.Locality{}, spanResolver.Error()
+			}
+			replica, err := spanResolver.ReplicaInfo(ctx)
+			if err != nil {
+				return roachpb.Locality{}, err
+			}
+			desc, err := distSQLPlanner.nodeDescs.GetNodeDescriptor(replica.NodeID)
+			if err != nil {
+				return roachpb.Locality{}, err
+			}
+			if first {
+				tiers = append(tiers, desc.Locality.Tiers...)
+				first = false
+			} else {
+				// Only retain the tiers shared with this leaseholder.
+				n := 0
+				for n < len(tiers) && n < len(desc.Locality.Tiers) && tiers[n] == desc.Locality.Tiers[n] {
+					n++
+				}
+				tiers = tiers[:n]
+			}
+			if len(tiers) == 0 {
+				return roachpb.Locality{}, nil
+			}
+			if !spanResolver.NeedAnother() {
+				break
+			}
+		}
+	}
+	return roachpb.Locality{Tiers: tiers}, nil
+}
+
 // TODO(adityamaru): Consider moving this to sql/backfill. It has a lot of
 // schema changer dependencies which will need to be passed around.
 func (sc *SchemaChanger) distIndexBackfill(
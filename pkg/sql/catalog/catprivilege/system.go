@@ -64,6 +64,8 @@ var (
 		catconstants.SpanCountTableName,
 		catconstants.SystemPrivilegeTableName,
 		catconstants.SystemExternalConnectionsTableName,
+		catconstants.SchemaChangeElementStatusesTableName,
+		catconstants.SchemaChangeThroughputTableName,
 	}
 
 	readWriteSystemSequences = []catconstants.SystemTableName{
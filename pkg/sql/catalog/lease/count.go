@@ -54,3 +54,40 @@ func CountLeases(
 	count := int(tree.MustBeDInt(values[0]))
 	return count, nil
 }
+
+// leaseHolder describes the unexpired leases held by a node on a descriptor
+// version.
+type leaseHolder struct {
+	nodeID    int64
+	numLeases int64
+	// expiration is the latest expiration time of the leases, in nanoseconds
+	// since the Unix epoch.
+	expiration int64
+}
+
+// getLeaseHolders returns the nodes holding unexpired leases on a descriptor
+// version at a particular time, ordered by node ID.
+func getLeaseHolders(
+	ctx context.Context, executor sqlutil.InternalExecutor, v IDVersion, at hlc.Timestamp,
+) ([]leaseHolder, error) {
+	stmt := fmt.Sprintf(`SELECT "nodeID", count(1), max(expiration) FROM system.public.lease AS OF SYSTEM TIME '%s' `+
+		`WHERE "descID" = %d AND version = %d AND expiration > $1 GROUP BY "nodeID" ORDER BY "nodeID"`,
+		at.AsOfSystemTime(), v.ID, v.Version)
+	rows, err := executor.QueryBufferedEx(
+		ctx, "get-lease-holders", nil, /* txn */
+		sessiondata.InternalExecutorOverride{User: username.RootUserName()},
+		stmt, at.GoTime(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	holders := make([]leaseHolder, 0, len(rows))
+	for _, row := range rows {
+		holders = append(holders, leaseHolder{
+			nodeID:     int64(tree.MustBeDInt(row[0])),
+			numLeases:  int64(tree.MustBeDInt(row[1])),
+			expiration: tree.MustBeDTimestamp(row[2]).UnixNano(),
+		})
+	}
+	return holders, nil
+}
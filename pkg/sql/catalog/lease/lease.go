@@ -38,6 +38,7 @@ import (
 	kvstorage "github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
 	"github.com/cockroachdb/cockroach/pkg/util/log/logcrash"
 	"github.com/cockroachdb/cockroach/pkg/util/metric"
 	"github.com/cockroachdb/cockroach/pkg/util/quotapool"
@@ -76,6 +77,19 @@ var LeaseJitterFraction = settings.RegisterFloatSetting(
 	base.DefaultDescriptorLeaseJitterFraction,
 	between0and1inclusive)
 
+// LeaseWaitDiagnosticsThreshold controls how long WaitForOneVersion waits
+// for the leases on the previous version of a descriptor before emitting a
+// structured event describing these leases.
+var LeaseWaitDiagnosticsThreshold = settings.RegisterDurationSetting(
+	settings.TenantWritable,
+	"sql.catalog.descriptor_lease_wait.diagnostics_threshold",
+	"duration after which a schema change waiting for the leases on the previous "+
+		"version of a descriptor reports the nodes holding them, and again at "+
+		"this interval until the wait completes; 0 disables the reports",
+	time.Minute,
+	settings.NonNegativeDuration,
+)
+
 // WaitForNoVersion returns once there are no unexpired leases left
 // for any version of the descriptor.
 func (m *Manager) WaitForNoVersion(
@@ -121,6 +135,8 @@ func (m *Manager) WaitForNoVersion(
 func (m *Manager) WaitForOneVersion(
 	ctx context.Context, id descpb.ID, retryOpts retry.Options,
 ) (desc catalog.Descriptor, _ error) {
+	start := timeutil.Now()
+	var lastReport time.Time
 	for lastCount, r := 0, retry.Start(retryOpts); r.Next(); {
 		if err := m.DB().Txn(ctx, func(ctx context.Context, txn *kv.Txn) (err error) {
 			version := m.storage.settings.Version.ActiveVersion(ctx)
@@ -145,10 +161,43 @@ func (m *Manager) WaitForOneVersion(
 			lastCount = count
 			log.Infof(ctx, "waiting for %d leases to expire: desc=%v", count, descs)
 		}
+		if threshold := LeaseWaitDiagnosticsThreshold.Get(&m.storage.settings.SV); threshold > 0 &&
+			timeutil.Since(start) >= threshold && timeutil.Since(lastReport) >= threshold {
+			lastReport = timeutil.Now()
+			m.reportLeaseWait(ctx, descs[0], now, lastReport.Sub(start))
+		}
 	}
 	return desc, nil
 }
 
+// reportLeaseWait emits a structured event describing the unexpired leases on
+// the given descriptor version, which a schema change has been waiting on for
+// the given duration. Failures to look up the leases are only logged, since
+// they should not affect the schema change.
+func (m *Manager) reportLeaseWait(
+	ctx context.Context, v IDVersion, now hlc.Timestamp, waited time.Duration,
+) {
+	holders, err := getLeaseHolders(ctx, m.storage.internalExecutor, v, now)
+	if err != nil {
+		log.Warningf(ctx, "failed to look up the leases on %v: %v", v, err)
+		return
+	}
+	ev := &eventpb.SchemaChangeLeaseWait{
+		DescriptorID:   uint32(v.ID),
+		DescriptorName: v.Name,
+		Version:        uint32(v.Version),
+		WaitDuration:   waited.Nanoseconds(),
+	}
+	for _, h := range holders {
+		ev.NumLeases += uint32(h.numLeases)
+		ev.NodeIDs = append(ev.NodeIDs, uint32(h.nodeID))
+		if h.expiration > ev.LatestExpiration {
+			ev.LatestExpiration = h.expiration
+		}
+	}
+	log.StructuredEvent(ctx, ev)
+}
+
 // IDVersion represents a descriptor ID, version pair that are
 // meant to map to a single immutable descriptor.
 type IDVersion struct {
@@ -17,6 +17,8 @@ import (
 	"context"
 	gosql "database/sql"
 	"fmt"
+	"math"
+	"regexp"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -3333,3 +3335,49 @@ func TestDescriptorRemovedFromCacheWhenLeaseRenewalForThisDescriptorFails(t *tes
 			typeDesc.GetName(), typeDesc.GetID())
 	})
 }
+
+// TestLeaseWaitDiagnostics checks that a schema change which waits for the
+// leases on the previous version of a descriptor reports them in a structured
+// event once the diagnostics threshold has elapsed.
+func TestLeaseWaitDiagnostics(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	s, sqlDB, _ := serverutils.StartServer(t, createTestServerParams())
+	defer s.Stopper().Stop(ctx)
+	tdb := sqlutils.MakeSQLRunner(sqlDB)
+	tdb.Exec(t, `SET CLUSTER SETTING sql.catalog.descriptor_lease_wait.diagnostics_threshold = '1ms'`)
+	tdb.Exec(t, `CREATE TABLE t (k INT PRIMARY KEY)`)
+	var tableID descpb.ID
+	tdb.QueryRow(t, `SELECT 't'::regclass::int`).Scan(&tableID)
+
+	// Hold a lease on the current version of the table in an open transaction,
+	// so that the schema change waits for it after publishing the next version.
+	tx, err := sqlDB.Begin()
+	require.NoError(t, err)
+	_, err = tx.Exec(`SELECT * FROM t`)
+	require.NoError(t, err)
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := sqlDB.Exec(`ALTER TABLE t RENAME TO u`)
+		errCh <- err
+	}()
+
+	pattern := regexp.MustCompile(fmt.Sprintf(
+		`"EventType":"schema_change_lease_wait".*"DescriptorID":%d,.*"NumLeases":1,"NodeIDs":\[%d\]`,
+		tableID, s.SQLInstanceID()))
+	testutils.SucceedsSoon(t, func() error {
+		log.Flush()
+		entries, err := log.FetchEntriesFromFiles(0, math.MaxInt64, 1, pattern, log.WithMarkedSensitiveData)
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return errors.New("lease wait not reported yet")
+		}
+		return nil
+	})
+	require.NoError(t, tx.Commit())
+	require.NoError(t, <-errCh)
+}
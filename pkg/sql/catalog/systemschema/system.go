@@ -698,6 +698,38 @@ CREATE TABLE system.external_connections (
 	CONSTRAINT "primary" PRIMARY KEY (connection_name),
 	FAMILY "primary" (connection_name, created, updated, connection_type, connection_details, owner)
 );`
+
+	// SchemaChangeElementStatusesTableSchema describes a table which records
+	// the latest status transition of each element targeted by the in-flight
+	// declarative schema changes, so that it may be watched by a changefeed.
+	SchemaChangeElementStatusesTableSchema = `
+CREATE TABLE system.schema_change_element_statuses (
+	job_id INT8 NOT NULL,
+	element_id INT8 NOT NULL,
+	descriptor_id INT8 NOT NULL,
+	element STRING NOT NULL,
+	from_status STRING NOT NULL,
+	to_status STRING NOT NULL,
+	updated TIMESTAMP NOT NULL DEFAULT now(),
+	CONSTRAINT "primary" PRIMARY KEY (job_id, element_id),
+	FAMILY "primary" (job_id, element_id, descriptor_id, element, from_status, to_status, updated)
+);`
+
+	// SchemaChangeThroughputTableSchema describes a table which records the
+	// throughput of the backfill and validation stages of the past declarative
+	// schema changes, from which the duration of new ones gets estimated.
+	SchemaChangeThroughputTableSchema = `
+CREATE TABLE system.schema_change_throughput (
+	kind STRING NOT NULL,
+	job_id INT8 NOT NULL,
+	stage INT8 NOT NULL,
+	rows INT8 NOT NULL,
+	bytes INT8 NOT NULL,
+	duration INTERVAL NOT NULL,
+	completed TIMESTAMP NOT NULL DEFAULT now(),
+	CONSTRAINT "primary" PRIMARY KEY (kind, job_id, stage),
+	FAMILY "primary" (kind, job_id, stage, rows, bytes, duration, completed)
+);`
 )
 
 func pk(name string) descpb.IndexDescriptor {
@@ -2562,6 +2594,77 @@ var (
 			},
 		),
 	)
+
+	// SchemaChangeElementStatusesTable is the descriptor for the table
+	// recording the element status transitions of declarative schema changes.
+	SchemaChangeElementStatusesTable = registerSystemTable(
+		SchemaChangeElementStatusesTableSchema,
+		systemTable(
+			catconstants.SchemaChangeElementStatusesTableName,
+			descpb.InvalidID, // dynamically assigned
+			[]descpb.ColumnDescriptor{
+				{Name: "job_id", ID: 1, Type: types.Int},
+				{Name: "element_id", ID: 2, Type: types.Int},
+				{Name: "descriptor_id", ID: 3, Type: types.Int},
+				{Name: "element", ID: 4, Type: types.String},
+				{Name: "from_status", ID: 5, Type: types.String},
+				{Name: "to_status", ID: 6, Type: types.String},
+				{Name: "updated", ID: 7, Type: types.Timestamp, DefaultExpr: &nowString},
+			},
+			[]descpb.ColumnFamilyDescriptor{
+				{
+					Name:        "primary",
+					ID:          0,
+					ColumnNames: []string{"job_id", "element_id", "descriptor_id", "element", "from_status", "to_status", "updated"},
+					ColumnIDs:   []descpb.ColumnID{1, 2, 3, 4, 5, 6, 7},
+				},
+			},
+			descpb.IndexDescriptor{
+				Name:                "primary",
+				ID:                  1,
+				Unique:              true,
+				KeyColumnNames:      []string{"job_id", "element_id"},
+				KeyColumnDirections: []catpb.IndexColumn_Direction{catpb.IndexColumn_ASC, catpb.IndexColumn_ASC},
+				KeyColumnIDs:        []descpb.ColumnID{1, 2},
+			},
+		),
+	)
+
+	// SchemaChangeThroughputTable is the descriptor for the table recording the
+	// throughput of the backfill and validation stages of declarative schema
+	// changes.
+	SchemaChangeThroughputTable = registerSystemTable(
+		SchemaChangeThroughputTableSchema,
+		systemTable(
+			catconstants.SchemaChangeThroughputTableName,
+			descpb.InvalidID, // dynamically assigned
+			[]descpb.ColumnDescriptor{
+				{Name: "kind", ID: 1, Type: types.String},
+				{Name: "job_id", ID: 2, Type: types.Int},
+				{Name: "stage", ID: 3, Type: types.Int},
+				{Name: "rows", ID: 4, Type: types.Int},
+				{Name: "bytes", ID: 5, Type: types.Int},
+				{Name: "duration", ID: 6, Type: types.Interval},
+				{Name: "completed", ID: 7, Type: types.Timestamp, DefaultExpr: &nowString},
+			},
+			[]descpb.ColumnFamilyDescriptor{
+				{
+					Name:        "primary",
+					ID:          0,
+					ColumnNames: []string{"kind", "job_id", "stage", "rows", "bytes", "duration", "completed"},
+					ColumnIDs:   []descpb.ColumnID{1, 2, 3, 4, 5, 6, 7},
+				},
+			},
+			descpb.IndexDescriptor{
+				Name:                "primary",
+				ID:                  1,
+				Unique:              true,
+				KeyColumnNames:      []string{"kind", "job_id", "stage"},
+				KeyColumnDirections: []catpb.IndexColumn_Direction{catpb.IndexColumn_ASC, catpb.IndexColumn_ASC, catpb.IndexColumn_ASC},
+				KeyColumnIDs:        []descpb.ColumnID{1, 2, 3},
+			},
+		),
+	)
 )
 
 type descRefByName struct {
@@ -124,10 +124,12 @@ func (ex *connExecutor) execStmt(
 		ev, payload = ex.execStmtInNoTxnState(ctx, ast)
 
 	case stateOpen:
-		err = ex.execWithProfiling(ctx, ast, prepared, func(ctx context.Context) error {
+		stmtCtx, forwardLogNotices := ex.captureLogNotices(ctx)
+		err = ex.execWithProfiling(stmtCtx, ast, prepared, func(ctx context.Context) error {
 			ev, payload, err = ex.execStmtInOpenState(ctx, parserStmt, prepared, pinfo, res, canAutoCommit)
 			return err
 		})
+		forwardLogNotices(ctx, res)
 		switch ev.(type) {
 		case eventNonRetriableErr:
 			ex.recordFailure()
@@ -15,10 +15,12 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/roleoption"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scrun"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
 	"github.com/cockroachdb/errors"
@@ -29,6 +31,9 @@ type controlJobsNode struct {
 	desiredStatus jobs.Status
 	numRows       int
 	reason        string
+	// atStage, if non-zero, is the post-commit stage before which a
+	// declarative schema change job is to pause.
+	atStage int64
 }
 
 var jobCommandToDesiredStatus = map[tree.JobCommand]jobs.Status{
@@ -67,6 +72,10 @@ func (n *controlJobsNode) startExec(params runParams) error {
 		return errors.AssertionFailedf("status %v is not %v and thus does not support a reason %v",
 			n.desiredStatus, jobs.StatusPaused, n.reason)
 	}
+	if n.desiredStatus != jobs.StatusPaused && n.atStage != 0 {
+		return errors.AssertionFailedf("status %v is not %v and thus does not support a stage",
+			n.desiredStatus, jobs.StatusPaused)
+	}
 
 	reg := params.p.ExecCfg().JobRegistry
 	for {
@@ -112,7 +121,11 @@ func (n *controlJobsNode) startExec(params runParams) error {
 
 		switch n.desiredStatus {
 		case jobs.StatusPaused:
-			err = reg.PauseRequested(params.ctx, params.p.txn, jobspb.JobID(jobID), n.reason)
+			if n.atStage != 0 {
+				err = pauseJobAtStage(params.ctx, reg, params.p.txn, jobspb.JobID(jobID), n.atStage)
+			} else {
+				err = reg.PauseRequested(params.ctx, params.p.txn, jobspb.JobID(jobID), n.reason)
+			}
 		case jobs.StatusRunning:
 			err = reg.Unpause(params.ctx, params.p.txn, jobspb.JobID(jobID))
 		case jobs.StatusCanceled:
@@ -136,6 +149,32 @@ func (n *controlJobsNode) startExec(params runParams) error {
 	return nil
 }
 
+// pauseJobAtStage requests that the declarative schema change job with the
+// given ID pauses before executing the given post-commit stage.
+func pauseJobAtStage(
+	ctx context.Context, reg *jobs.Registry, txn *kv.Txn, id jobspb.JobID, stage int64,
+) error {
+	const useReadLock = false
+	return reg.UpdateJobWithTxn(ctx, id, txn, useReadLock, func(
+		txn *kv.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater,
+	) error {
+		progress := md.Progress.GetNewSchemaChange()
+		if progress == nil {
+			return pgerror.Newf(pgcode.WrongObjectType,
+				"job %d is not a declarative schema change job and cannot be paused at a stage", id)
+		}
+		if md.Status.Terminal() {
+			return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+				"job %d is %s and cannot be paused at a stage", id, md.Status)
+		}
+		if err := scrun.SetPauseAtStage(progress, stage); err != nil {
+			return pgerror.WithCandidateCode(err, pgcode.InvalidParameterValue)
+		}
+		ju.UpdateProgress(md.Progress)
+		return nil
+	})
+}
+
 func (*controlJobsNode) Next(runParams) (bool, error) { return false, nil }
 
 func (*controlJobsNode) Values() tree.Datums { return nil }
@@ -57,6 +57,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/roleoption"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scrun"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins/builtinsregistry"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
@@ -111,6 +112,7 @@ var crdbInternal = virtualSchema{
 		catconstants.CrdbInternalClusterDistSQLFlowsTableID:         crdbInternalClusterDistSQLFlowsTable,
 		catconstants.CrdbInternalClusterExecutionInsightsTableID:    crdbInternalClusterExecutionInsightsTable,
 		catconstants.CrdbInternalClusterLocksTableID:                crdbInternalClusterLocksTable,
+		catconstants.CrdbInternalClusterLoggingTableID:              crdbInternalClusterLoggingTable,
 		catconstants.CrdbInternalClusterQueriesTableID:              crdbInternalClusterQueriesTable,
 		catconstants.CrdbInternalClusterTransactionsTableID:         crdbInternalClusterTxnsTable,
 		catconstants.CrdbInternalClusterSessionsTableID:             crdbInternalClusterSessionsTable,
@@ -137,6 +139,9 @@ var crdbInternal = virtualSchema{
 		catconstants.CrdbInternalKVNodeStatusTableID:                crdbInternalKVNodeStatusTable,
 		catconstants.CrdbInternalKVStoreStatusTableID:               crdbInternalKVStoreStatusTable,
 		catconstants.CrdbInternalLeasesTableID:                      crdbInternalLeasesTable,
+		catconstants.CrdbInternalLoggingSinksTableID:                crdbInternalLoggingSinksTable,
+		catconstants.CrdbInternalSchemaChangeStageErrorsTableID:     crdbInternalSchemaChangeStageErrorsTable,
+		catconstants.CrdbInternalSchemaChangeStagesTableID:          crdbInternalSchemaChangeStagesTable,
 		catconstants.CrdbInternalLocalContentionEventsTableID:       crdbInternalLocalContentionEventsTable,
 		catconstants.CrdbInternalLocalDistSQLFlowsTableID:           crdbInternalLocalDistSQLFlowsTable,
 		catconstants.CrdbInternalLocalQueriesTableID:                crdbInternalLocalQueriesTable,
@@ -144,6 +149,7 @@ var crdbInternal = virtualSchema{
 		catconstants.CrdbInternalLocalSessionsTableID:               crdbInternalLocalSessionsTable,
 		catconstants.CrdbInternalLocalMetricsTableID:                crdbInternalLocalMetricsTable,
 		catconstants.CrdbInternalNodeExecutionInsightsTableID:       crdbInternalNodeExecutionInsightsTable,
+		catconstants.CrdbInternalNodeRecentLogEntriesTableID:        crdbInternalNodeRecentLogEntriesTable,
 		catconstants.CrdbInternalNodeStmtStatsTableID:               crdbInternalNodeStmtStatsTable,
 		catconstants.CrdbInternalNodeTxnStatsTableID:                crdbInternalNodeTxnStatsTable,
 		catconstants.CrdbInternalPartitionsTableID:                  crdbInternalPartitionsTable,
@@ -947,6 +953,7 @@ CREATE TABLE crdb_internal.jobs (
 				}
 
 				// Extract data from the progress field.
+				var stageErrors []jobspb.SchemaChangeStageError
 				if progressBytes != tree.DNull {
 					progress, err := jobs.UnmarshalProgress(progressBytes)
 					if err != nil {
@@ -979,12 +986,19 @@ CREATE TABLE crdb_internal.jobs (
 							}
 						}
 						traceID = tree.NewDInt(tree.DInt(progress.TraceID))
+						if sc := progress.GetNewSchemaChange(); sc != nil {
+							stageErrors = sc.StageErrors
+						}
 					}
 				}
 				if payload != nil {
-					executionErrors = jobs.FormatRetriableExecutionErrorLogToStringArray(
+					arr := jobs.FormatRetriableExecutionErrorLogToStringArray(
 						ctx, payload.RetriableExecutionFailureLog,
 					)
+					for i := range stageErrors {
+						_ = arr.Append(tree.NewDString(formatSchemaChangeStageError(ctx, &stageErrors[i])))
+					}
+					executionErrors = arr
 					// It's not clear why we'd ever see an error here,
 					var err error
 					executionEvents, err = jobs.FormatRetriableExecutionErrorLogToJSON(
@@ -1030,6 +1044,211 @@ CREATE TABLE crdb_internal.jobs (
 	},
 }
 
+var crdbInternalSchemaChangeStageErrorsTable = virtualSchemaTable{
+	comment: `errors encountered by the stages of declarative schema change jobs (KV scan)`,
+	schema: `
+CREATE TABLE crdb_internal.schema_change_stage_errors (
+  job_id          INT NOT NULL,
+  phase           STRING NOT NULL,
+  stage_ordinal   INT NOT NULL,
+  stages_in_phase INT NOT NULL,
+  rollback        BOOL NOT NULL,
+  attempt         INT NOT NULL,
+  failed          TIMESTAMP NOT NULL,
+  error           STRING NOT NULL
+)`,
+	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		return forEachVisibleSchemaChangeJobProgress(ctx, p, "crdb-internal-schema-change-stage-errors-table", func(
+			id tree.Datum, progress *jobspb.NewSchemaChangeProgress,
+		) error {
+			for i := range progress.StageErrors {
+				se := &progress.StageErrors[i]
+				failed, err := tree.MakeDTimestamp(timeutil.FromUnixMicros(se.TimestampMicros), time.Microsecond)
+				if err != nil {
+					return err
+				}
+				if err := addRow(
+					id,
+					tree.NewDString(se.Phase),
+					tree.NewDInt(tree.DInt(se.Ordinal)),
+					tree.NewDInt(tree.DInt(se.StagesInPhase)),
+					tree.MakeDBool(tree.DBool(se.Rollback)),
+					tree.NewDInt(tree.DInt(se.Attempt)),
+					failed,
+					tree.NewDString(decodeSchemaChangeStageError(ctx, se).Error()),
+				); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	},
+}
+
+var crdbInternalSchemaChangeStagesTable = virtualSchemaTable{
+	comment: `progress of the stages of declarative schema change jobs (KV scan)`,
+	schema: `
+CREATE TABLE crdb_internal.schema_change_stages (
+  job_id               INT NOT NULL,
+  phase                STRING NOT NULL,
+  stage_ordinal        INT NOT NULL,
+  stages_in_phase      INT NOT NULL,
+  rollback             BOOL NOT NULL,
+  op_type              STRING NOT NULL,
+  num_ops              INT NOT NULL,
+  status               STRING NOT NULL,
+  started              TIMESTAMP,
+  completed            TIMESTAMP,
+  fraction_completed   FLOAT NOT NULL,
+  estimated_completion TIMESTAMP
+)`,
+	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		now := timeutil.Now()
+		timestampOrNull := func(t time.Time) (tree.Datum, error) {
+			if t.IsZero() {
+				return tree.DNull, nil
+			}
+			return tree.MakeDTimestamp(t, time.Microsecond)
+		}
+		return forEachVisibleSchemaChangeJobProgress(ctx, p, "crdb-internal-schema-change-stages-table", func(
+			id tree.Datum, progress *jobspb.NewSchemaChangeProgress,
+		) error {
+			for i := range progress.Stages {
+				sp := &progress.Stages[i]
+				var started, completed time.Time
+				status := "pending"
+				if sp.StartedMicros != 0 {
+					started = timeutil.FromUnixMicros(sp.StartedMicros)
+					status = "running"
+				}
+				if sp.CompletedMicros != 0 {
+					completed = timeutil.FromUnixMicros(sp.CompletedMicros)
+					status = "completed"
+				}
+				eta, _ := scrun.StageProgressEstimatedCompletion(sp, now)
+				datums := make(tree.Datums, 0, 3)
+				for _, t := range []time.Time{started, completed, eta} {
+					d, err := timestampOrNull(t)
+					if err != nil {
+						return err
+					}
+					datums = append(datums, d)
+				}
+				if err := addRow(
+					id,
+					tree.NewDString(sp.Phase),
+					tree.NewDInt(tree.DInt(sp.Ordinal)),
+					tree.NewDInt(tree.DInt(sp.StagesInPhase)),
+					tree.MakeDBool(tree.DBool(sp.Rollback)),
+					tree.NewDString(sp.Type),
+					tree.NewDInt(tree.DInt(sp.NumOps)),
+					tree.NewDString(status),
+					datums[0],
+					datums[1],
+					tree.NewDFloat(tree.DFloat(sp.FractionCompleted)),
+					datums[2],
+				); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	},
+}
+
+// forEachVisibleSchemaChangeJobProgress calls fn with the ID and the progress
+// of every declarative schema change job which the current user is allowed
+// to see, following the access rules of crdb_internal.jobs.
+func forEachVisibleSchemaChangeJobProgress(
+	ctx context.Context,
+	p *planner,
+	opName string,
+	fn func(id tree.Datum, progress *jobspb.NewSchemaChangeProgress) error,
+) error {
+	currentUser := p.SessionData().User()
+	isAdmin, err := p.HasAdminRole(ctx)
+	if err != nil {
+		return err
+	}
+	hasControlJob, err := p.HasRoleOption(ctx, roleoption.CONTROLJOB)
+	if err != nil {
+		return err
+	}
+
+	// Beware: we're querying system.jobs as root; we need to be careful to filter
+	// out results that the current user is not able to see.
+	it, err := p.ExtendedEvalContext().ExecCfg.InternalExecutor.QueryIteratorEx(
+		ctx, opName, p.txn,
+		sessiondata.InternalExecutorOverride{User: username.RootUserName()},
+		`SELECT id, payload, progress FROM system.jobs`)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := it.Close(); err != nil {
+			log.Warningf(ctx, "error closing an iterator: %v", err)
+		}
+	}()
+
+	for {
+		ok, err := it.Next(ctx)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+		r := it.Cur()
+		id, payloadBytes, progressBytes := r[0], r[1], r[2]
+		// Errors decoding the job metadata are reported by crdb_internal.jobs.
+		payload, err := jobs.UnmarshalPayload(payloadBytes)
+		if err != nil || payload.Type() != jobspb.TypeNewSchemaChange || progressBytes == tree.DNull {
+			continue
+		}
+		// See crdb_internal.jobs for the access rules.
+		if owner := payload.UsernameProto.Decode(); !isAdmin && owner != currentUser {
+			ownedByAdmin, err := p.UserHasAdminRole(ctx, owner)
+			if err != nil {
+				return err
+			}
+			if ownedByAdmin || !hasControlJob {
+				continue
+			}
+		}
+		progress, err := jobs.UnmarshalProgress(progressBytes)
+		if err != nil || progress.GetNewSchemaChange() == nil {
+			continue
+		}
+		if err := fn(id, progress.GetNewSchemaChange()); err != nil {
+			return err
+		}
+	}
+}
+
+// decodeSchemaChangeStageError returns the error recorded in the stage error
+// entry of a declarative schema change job.
+func decodeSchemaChangeStageError(ctx context.Context, se *jobspb.SchemaChangeStageError) error {
+	if se.Error != nil {
+		return errors.DecodeError(ctx, *se.Error)
+	}
+	return errors.Newf("(truncated) %s", se.TruncatedError)
+}
+
+// formatSchemaChangeStageError formats the stage error entry of a declarative
+// schema change job for crdb_internal.jobs.
+func formatSchemaChangeStageError(ctx context.Context, se *jobspb.SchemaChangeStageError) string {
+	attempt := fmt.Sprintf("attempt %d", se.Attempt)
+	if se.Rollback {
+		attempt += ", rollback"
+	}
+	failed, _ := tree.MakeDTimestamp(timeutil.FromUnixMicros(se.TimestampMicros), time.Microsecond)
+	return fmt.Sprintf(
+		"%s stage %d of %d (%s) failed at %v: %v",
+		se.Phase, se.Ordinal, se.StagesInPhase, attempt, failed,
+		decodeSchemaChangeStageError(ctx, se),
+	)
+}
+
 // execStatAvg is a helper for execution stats shown in virtual tables. Returns
 // NULL when the count is 0, or the mean of the given NumericStat.
 func execStatAvg(count int64, n roachpb.NumericStat) tree.Datum {
@@ -2371,6 +2590,150 @@ func populateDistSQLFlowsTable(
 	return nil
 }
 
+// crdbInternalClusterLoggingTable exposes the run-time logging
+// configuration of every node, one row per setting or sink.
+var crdbInternalClusterLoggingTable = virtualSchemaTable{
+	comment: `logging configuration and log sink occupancy across all nodes (cluster RPC; expensive!)`,
+	schema: `
+CREATE TABLE crdb_internal.cluster_logging (
+  node_id          INT NOT NULL,
+  kind             STRING NOT NULL,
+  name             STRING,
+  value            STRING NOT NULL,
+  queued_entries   INT,
+  queued_bytes     INT,
+  max_queued_bytes INT
+)`,
+	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		if err := p.RequireAdminRole(ctx, "read crdb_internal.cluster_logging"); err != nil {
+			return err
+		}
+		response, err := p.extendedEvalCtx.SQLStatusServer.LoggingState(ctx, &serverpb.LoggingStateRequest{})
+		if err != nil {
+			return err
+		}
+		for i := range response.Nodes {
+			n := &response.Nodes[i]
+			nodeID := tree.NewDInt(tree.DInt(n.NodeID))
+			addSetting := func(kind string, name tree.Datum, value string) error {
+				return addRow(
+					nodeID,                 // node_id
+					tree.NewDString(kind),  // kind
+					name,                   // name
+					tree.NewDString(value), // value
+					tree.DNull,             // queued_entries
+					tree.DNull,             // queued_bytes
+					tree.DNull,             // max_queued_bytes
+				)
+			}
+			if err := addSetting("vmodule", tree.DNull, n.VModule); err != nil {
+				return err
+			}
+			channels := make([]string, 0, len(n.ChannelVModule))
+			for ch := range n.ChannelVModule {
+				channels = append(channels, ch)
+			}
+			sort.Strings(channels)
+			for _, ch := range channels {
+				if err := addSetting("channel vmodule", tree.NewDString(ch), n.ChannelVModule[ch]); err != nil {
+					return err
+				}
+			}
+			severities, err := log.ParseChannelSeverities(n.ChannelSeverities)
+			if err != nil {
+				return err
+			}
+			overridden := make([]log.Channel, 0, len(severities))
+			for ch := range severities {
+				overridden = append(overridden, ch)
+			}
+			sort.Slice(overridden, func(i, j int) bool { return overridden[i] < overridden[j] })
+			for _, ch := range overridden {
+				if err := addSetting("channel severity", tree.NewDString(ch.String()), severities[ch].String()); err != nil {
+					return err
+				}
+			}
+			for _, sink := range n.Sinks {
+				queuedEntries, queuedBytes, maxQueuedBytes := tree.DNull, tree.DNull, tree.DNull
+				if sink.Buffered || sink.Spooled {
+					queuedEntries = tree.NewDInt(tree.DInt(sink.QueuedEntries))
+					queuedBytes = tree.NewDInt(tree.DInt(sink.QueuedBytes))
+					maxQueuedBytes = tree.NewDInt(tree.DInt(sink.MaxQueuedBytes))
+				}
+				if err := addRow(
+					nodeID,                             // node_id
+					tree.NewDString(sink.Type+" sink"), // kind
+					tree.NewDString(sink.Name),         // name
+					tree.NewDString(sink.Target),       // value
+					queuedEntries,                      // queued_entries
+					queuedBytes,                        // queued_bytes
+					maxQueuedBytes,                     // max_queued_bytes
+				); err != nil {
+					return err
+				}
+			}
+		}
+		for _, rpcErr := range response.Errors {
+			log.Warningf(ctx, "%v", rpcErr.Message)
+		}
+		return nil
+	},
+}
+
+// crdbInternalNodeRecentLogEntriesTable exposes the recent log entries
+// of severity WARNING and above retained in memory by every node,
+// regardless of the logging configuration.
+var crdbInternalNodeRecentLogEntriesTable = virtualSchemaTable{
+	comment: `recent WARNING, ERROR and FATAL log entries retained in memory across all nodes (cluster RPC; expensive!)`,
+	schema: `
+CREATE TABLE crdb_internal.node_recent_log_entries (
+  node_id   INT NOT NULL,
+  timestamp TIMESTAMPTZ NOT NULL,
+  severity  STRING NOT NULL,
+  channel   STRING NOT NULL,
+  goroutine INT NOT NULL,
+  file      STRING NOT NULL,
+  line      INT NOT NULL,
+  tags      STRING NOT NULL,
+  message   STRING NOT NULL
+)`,
+	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		if err := p.RequireAdminRole(ctx, "read crdb_internal.node_recent_log_entries"); err != nil {
+			return err
+		}
+		response, err := p.extendedEvalCtx.SQLStatusServer.RecentLogEntries(ctx, &serverpb.RecentLogEntriesRequest{
+			Severity: log.RecentEntriesSeverity,
+		})
+		if err != nil {
+			return err
+		}
+		for i := range response.Entries {
+			e := &response.Entries[i].Entry
+			ts, err := tree.MakeDTimestampTZ(timeutil.Unix(0, e.Time), time.Microsecond)
+			if err != nil {
+				return err
+			}
+			if err := addRow(
+				tree.NewDInt(tree.DInt(response.Entries[i].NodeID)), // node_id
+				ts,                                   // timestamp
+				tree.NewDString(e.Severity.String()), // severity
+				tree.NewDString(e.Channel.String()),  // channel
+				tree.NewDInt(tree.DInt(e.Goroutine)), // goroutine
+				tree.NewDString(e.File),              // file
+				tree.NewDInt(tree.DInt(e.Line)),      // line
+				tree.NewDString(e.Tags),              // tags
+				tree.NewDString(e.Message),           // message
+			); err != nil {
+				return err
+			}
+		}
+		for _, rpcErr := range response.Errors {
+			log.Warningf(ctx, "%v", rpcErr.Message)
+		}
+		return nil
+	},
+}
+
 // crdbInternalLocalMetricsTable exposes a snapshot of the metrics on the
 // current node.
 var crdbInternalLocalMetricsTable = virtualSchemaTable{
@@ -3201,6 +3564,74 @@ CREATE TABLE crdb_internal.feature_usage (
 	},
 }
 
+// crdbInternalLoggingSinksTable exposes the health of the log sinks.
+var crdbInternalLoggingSinksTable = virtualSchemaTable{
+	comment: "log sinks and their delivery health (RAM; local node only)",
+	schema: `
+CREATE TABLE crdb_internal.logging_sinks (
+  node_id           INT NOT NULL,
+  sink_type         STRING NOT NULL,
+  sink_name         STRING NOT NULL,
+  target            STRING NOT NULL,
+  channels          STRING[] NOT NULL,
+  buffered          BOOL NOT NULL,
+  backpressure      STRING,
+  queued_entries    INT,
+  queued_bytes      INT,
+  delivery_failures INT NOT NULL,
+  dropped_entries   INT NOT NULL,
+  last_error        STRING,
+  last_error_time   TIMESTAMPTZ
+)`,
+	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		if err := p.RequireAdminRole(ctx, "read crdb_internal.logging_sinks"); err != nil {
+			return err
+		}
+		nodeID, _ := p.execCfg.NodeInfo.NodeID.OptionalNodeID() // zero if not available
+		for _, h := range log.SinkHealth() {
+			channels := tree.NewDArray(types.String)
+			for _, ch := range h.Channels {
+				if err := channels.Append(tree.NewDString(ch.String())); err != nil {
+					return err
+				}
+			}
+			backpressure, queuedEntries, queuedBytes := tree.DNull, tree.DNull, tree.DNull
+			if h.Buffered {
+				backpressure = tree.NewDString(string(h.Backpressure))
+				queuedEntries = tree.NewDInt(tree.DInt(h.QueuedEntries))
+				queuedBytes = tree.NewDInt(tree.DInt(h.QueuedBytes))
+			}
+			lastError, lastErrorTime := tree.DNull, tree.DNull
+			if h.LastError != nil {
+				lastError = tree.NewDString(h.LastError.Error())
+				ts, err := tree.MakeDTimestampTZ(h.LastErrorTime, time.Microsecond)
+				if err != nil {
+					return err
+				}
+				lastErrorTime = ts
+			}
+			if err := addRow(
+				tree.NewDInt(tree.DInt(nodeID)),             // node_id
+				tree.NewDString(h.Type),                     // sink_type
+				tree.NewDString(h.Name),                     // sink_name
+				tree.NewDString(h.Target),                   // target
+				channels,                                    // channels
+				tree.MakeDBool(tree.DBool(h.Buffered)),      // buffered
+				backpressure,                                // backpressure
+				queuedEntries,                               // queued_entries
+				queuedBytes,                                 // queued_bytes
+				tree.NewDInt(tree.DInt(h.DeliveryFailures)), // delivery_failures
+				tree.NewDInt(tree.DInt(h.DroppedEntries)),   // dropped_entries
+				lastError,                                   // last_error
+				lastErrorTime,                               // last_error_time
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
 // crdbInternalForwardDependenciesTable exposes the forward
 // inter-descriptor dependencies.
 //
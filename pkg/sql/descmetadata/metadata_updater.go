@@ -16,6 +16,7 @@ import (
 	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/config/zonepb"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/security/username"
@@ -26,6 +27,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scexec"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
@@ -231,6 +233,40 @@ func (mu metadataUpdater) DeleteZoneConfig(
 		"DELETE FROM system.zones WHERE id = $1", id)
 }
 
+// SetIndexGCTTL implements scexec.DescriptorMetadataUpdater.
+func (mu metadataUpdater) SetIndexGCTTL(
+	ctx context.Context, tableID descpb.ID, indexID descpb.IndexID, ttlSeconds int32,
+) error {
+	ie := mu.ieFactory.NewInternalExecutor(mu.sessionData)
+	zone, err := NewZoneConfigGetter(mu.txn, ie).GetZoneConfig(ctx, tableID)
+	if err != nil {
+		return err
+	}
+	if zone == nil {
+		zone = zonepb.NewZoneConfig()
+		zone.DeleteTableConfig()
+	}
+	zone.SetIndexGCTTL(uint32(indexID), ttlSeconds)
+	_, err = mu.UpsertZoneConfig(ctx, tableID, zone)
+	return err
+}
+
+// CopyIndexZoneConfig implements scexec.DescriptorMetadataUpdater.
+func (mu metadataUpdater) CopyIndexZoneConfig(
+	ctx context.Context, tableID descpb.ID, sourceIndexID, indexID descpb.IndexID,
+) error {
+	ie := mu.ieFactory.NewInternalExecutor(mu.sessionData)
+	zone, err := NewZoneConfigGetter(mu.txn, ie).GetZoneConfig(ctx, tableID)
+	if err != nil || zone == nil {
+		return err
+	}
+	if !zone.CopyIndexSubzones(uint32(sourceIndexID), uint32(indexID)) {
+		return nil
+	}
+	_, err = mu.UpsertZoneConfig(ctx, tableID, zone)
+	return err
+}
+
 // UpsertZoneConfig implements scexec.DescriptorMetadataUpdater.
 func (mu metadataUpdater) UpsertZoneConfig(
 	ctx context.Context, id descpb.ID, zone *zonepb.ZoneConfig,
@@ -243,3 +279,43 @@ func (mu metadataUpdater) UpsertZoneConfig(
 	return ie.Exec(ctx, "upsert-zone", mu.txn,
 		"UPSERT INTO system.zones (id, config) VALUES ($1, $2)", id, bytes)
 }
+
+// UpsertSchemaChangeElementStatuses implements
+// scexec.DescriptorMetadataUpdater.
+func (mu metadataUpdater) UpsertSchemaChangeElementStatuses(
+	ctx context.Context, jobID jobspb.JobID, transitions []scop.ElementStatusTransition,
+) error {
+	ie := mu.ieFactory.NewInternalExecutor(mu.sessionData)
+	for _, t := range transitions {
+		if _, err := ie.ExecEx(
+			ctx,
+			"upsert-schema-change-element-status",
+			mu.txn,
+			sessiondata.InternalExecutorOverride{User: username.RootUserName()},
+			`UPSERT INTO system.schema_change_element_statuses
+  (job_id, element_id, descriptor_id, element, from_status, to_status, updated)
+VALUES ($1, $2, $3, $4, $5, $6, now())`,
+			jobID, t.ElementID, int64(t.DescID), t.Element, t.From.String(), t.To.String(),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteSchemaChangeElementStatuses implements
+// scexec.DescriptorMetadataUpdater.
+func (mu metadataUpdater) DeleteSchemaChangeElementStatuses(
+	ctx context.Context, jobID jobspb.JobID,
+) error {
+	ie := mu.ieFactory.NewInternalExecutor(mu.sessionData)
+	_, err := ie.ExecEx(
+		ctx,
+		"delete-schema-change-element-statuses",
+		mu.txn,
+		sessiondata.InternalExecutorOverride{User: username.RootUserName()},
+		"DELETE FROM system.schema_change_element_statuses WHERE job_id = $1",
+		jobID,
+	)
+	return err
+}
@@ -1119,7 +1119,7 @@ func (e *distSQLSpecExecFactory) ConstructRecursiveCTE(
 }
 
 func (e *distSQLSpecExecFactory) ConstructControlJobs(
-	command tree.JobCommand, input exec.Node, reason tree.TypedExpr,
+	command tree.JobCommand, input exec.Node, reason tree.TypedExpr, atStage int64,
 ) (exec.Node, error) {
 	return nil, unimplemented.NewWithIssue(47473, "experimental opt-driven distsql planning: control jobs")
 }
@@ -117,6 +117,28 @@ func processDescriptorTable(
 	}, nil
 }
 
+// Catalog returns the catalog made of the descriptors and namespace entries
+// read from the system tables. The descriptors are upgraded like in
+// ExamineDescriptors, failures to do so are reported to stdout.
+func Catalog(
+	descTable DescriptorTable, namespaceTable NamespaceTable, stdout io.Writer,
+) (nstree.Catalog, error) {
+	descLookupFn, err := processDescriptorTable(stdout, descTable)
+	if err != nil {
+		return nstree.Catalog{}, err
+	}
+	var cb nstree.MutableCatalog
+	for _, row := range namespaceTable {
+		cb.UpsertNamespaceEntry(row.NameInfo, descpb.ID(row.ID))
+	}
+	for _, row := range descTable {
+		if desc := descLookupFn(descpb.ID(row.ID)); desc != nil {
+			cb.UpsertDescriptorEntry(desc)
+		}
+	}
+	return cb.Catalog, nil
+}
+
 // Examine runs a suite of consistency checks over system tables.
 func Examine(
 	ctx context.Context,
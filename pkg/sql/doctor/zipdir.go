@@ -0,0 +1,185 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package doctor
+
+import (
+	"bufio"
+	hx "encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// FromZipDir collects system table data from a decompressed debug zip dir.
+// If verbose is set, the files are reported as they are read.
+func FromZipDir(
+	zipDirPath string, verbose bool,
+) (
+	descTable DescriptorTable,
+	namespaceTable NamespaceTable,
+	jobsTable JobsTable,
+	retErr error,
+) {
+	descTable = make(DescriptorTable, 0)
+	if err := slurp(zipDirPath, verbose, "system.descriptor.txt", func(row string) error {
+		fields := strings.Fields(row)
+		last := len(fields) - 1
+		i, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse descriptor id %s", fields[0])
+		}
+
+		descBytes, err := hx.DecodeString(fields[last])
+		if err != nil {
+			return errors.Wrapf(err, "failed to decode hex descriptor %d", i)
+		}
+		ts := hlc.Timestamp{WallTime: timeutil.Now().UnixNano()}
+		descTable = append(descTable, DescriptorTableRow{ID: int64(i), DescBytes: descBytes, ModTime: ts})
+		return nil
+	}); err != nil {
+		return nil, nil, nil, err
+	}
+
+	// Handle old debug zips where the namespace table dump is from namespace2.
+	namespaceFileName := "system.namespace2.txt"
+	if _, err := os.Stat(namespaceFileName); err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			// Handle unexpected errors.
+			return nil, nil, nil, err
+		}
+		namespaceFileName = "system.namespace.txt"
+	}
+
+	namespaceTable = make(NamespaceTable, 0)
+	if err := slurp(zipDirPath, verbose, namespaceFileName, func(row string) error {
+		fields := strings.Fields(row)
+		parID, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse parent id %s", fields[0])
+		}
+		parSchemaID, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse parent schema id %s", fields[1])
+		}
+		id, err := strconv.Atoi(fields[3])
+		if err != nil {
+			if fields[3] == "NULL" {
+				id = int(descpb.InvalidID)
+			} else {
+				return errors.Wrapf(err, "failed to parse id %s", fields[3])
+			}
+		}
+
+		namespaceTable = append(namespaceTable, NamespaceTableRow{
+			NameInfo: descpb.NameInfo{
+				ParentID: descpb.ID(parID), ParentSchemaID: descpb.ID(parSchemaID), Name: fields[2],
+			},
+			ID: int64(id),
+		})
+		return nil
+	}); err != nil {
+		return nil, nil, nil, err
+	}
+
+	jobsTable = make(JobsTable, 0)
+	if err := slurp(zipDirPath, verbose, "system.jobs.txt", func(row string) error {
+		fields := strings.Fields(row)
+		md := jobs.JobMetadata{}
+		md.Status = jobs.Status(fields[1])
+
+		id, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse job id %s", fields[0])
+		}
+		md.ID = jobspb.JobID(id)
+
+		last := len(fields) - 1
+		payloadBytes, err := hx.DecodeString(fields[last-1])
+		if err != nil {
+			return errors.Wrapf(err, "job %d: failed to decode hex payload", id)
+		}
+		md.Payload = &jobspb.Payload{}
+		if err := protoutil.Unmarshal(payloadBytes, md.Payload); err != nil {
+			return errors.Wrap(err, "failed unmarshalling job payload")
+		}
+		progressBytes, err := hx.DecodeString(fields[last])
+		if err != nil {
+			return errors.Wrapf(err, "job %d: failed to decode hex progress", id)
+		}
+		md.Progress = &jobspb.Progress{}
+		if err := protoutil.Unmarshal(progressBytes, md.Progress); err != nil {
+			return errors.Wrap(err, "failed unmarshalling job progress")
+		}
+
+		jobsTable = append(jobsTable, md)
+		return nil
+	}); err != nil {
+		return nil, nil, nil, err
+	}
+
+	return descTable, namespaceTable, jobsTable, nil
+}
+
+// slurp reads a file in zipDirPath and processes its contents.
+func slurp(
+	zipDirPath string, verbose bool, fileName string, tableMapFn func(row string) error,
+) error {
+	filePath := path.Join(zipDirPath, fileName)
+
+	// Check for existence of companion .err.txt file.
+	_, err := os.Stat(filePath + ".err.txt")
+	if err == nil {
+		// A .err.txt file exists.
+		fmt.Printf("WARNING: errors occurred during the production of %s, contents may be missing or incomplete.\n", fileName)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		// Handle unexpected errors.
+		return err
+	}
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if verbose {
+		fmt.Println("reading " + filePath)
+	}
+	return tableMap(f, tableMapFn)
+}
+
+// tableMap applies `fn` to all rows in `in`.
+func tableMap(in io.Reader, fn func(string) error) error {
+	firstLine := true
+	sc := bufio.NewScanner(in)
+	// Read lines up to 50 MB in size.
+	sc.Buffer(make([]byte, 64*1024), 50*1024*1024)
+	for sc.Scan() {
+		if firstLine {
+			firstLine = false
+			continue
+		}
+		if err := fn(sc.Text()); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
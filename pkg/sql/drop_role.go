@@ -185,17 +185,14 @@ func (n *DropRoleNode) startExec(params runParams) error {
 					ObjectName: tn.String(),
 				})
 		}
-		for _, u := range tableDescriptor.GetPrivileges().Users {
-			if _, ok := userNames[u.User()]; ok {
-				if privilegeObjectFormatter.Len() > 0 {
-					privilegeObjectFormatter.WriteString(", ")
-				}
-				parentName := lCtx.getDatabaseName(tableDescriptor)
-				schemaName := lCtx.getSchemaName(tableDescriptor)
-				tn := tree.MakeTableNameWithSchema(tree.Name(parentName), tree.Name(schemaName), tree.Name(tableDescriptor.GetName()))
-				privilegeObjectFormatter.FormatNode(&tn)
-				break
+		if tableHasPrivilegesForAnyUser(tableDescriptor, userNames) {
+			if privilegeObjectFormatter.Len() > 0 {
+				privilegeObjectFormatter.WriteString(", ")
 			}
+			parentName := lCtx.getDatabaseName(tableDescriptor)
+			schemaName := lCtx.getSchemaName(tableDescriptor)
+			tn := tree.MakeTableNameWithSchema(tree.Name(parentName), tree.Name(schemaName), tree.Name(tableDescriptor.GetName()))
+			privilegeObjectFormatter.FormatNode(&tn)
 		}
 	}
 	for _, schemaDesc := range lCtx.schemaDescs {
@@ -472,6 +469,26 @@ func (*DropRoleNode) Values() tree.Datums { return tree.Datums{} }
 // Close implements the planNode interface.
 func (*DropRoleNode) Close(context.Context) {}
 
+// tableHasPrivilegesForAnyUser returns true if any of the users in userNames
+// has privileges on the table or on any of its columns.
+func tableHasPrivilegesForAnyUser(
+	tableDescriptor catalog.TableDescriptor, userNames map[username.SQLUsername][]objectAndType,
+) bool {
+	for _, u := range tableDescriptor.GetPrivileges().Users {
+		if _, ok := userNames[u.User()]; ok {
+			return true
+		}
+	}
+	for _, col := range tableDescriptor.AllColumns() {
+		for _, u := range col.ColumnDesc().Privileges {
+			if _, ok := userNames[u.User()]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // accumulateDependentDefaultPrivileges checks for any default privileges
 // that the users in userNames have and append them to the objectAndType array.
 func accumulateDependentDefaultPrivileges(
@@ -3052,6 +3052,10 @@ func (m *sessionDataMutator) SetOptimizerUseForecasts(val bool) {
 	m.data.OptimizerUseForecasts = val
 }
 
+func (m *sessionDataMutator) SetLogNoticesEnabled(val bool) {
+	m.data.LogNoticesEnabled = val
+}
+
 func (m *sessionDataMutator) SetOptimizerUseHistograms(val bool) {
 	m.data.OptimizerUseHistograms = val
 }
@@ -138,6 +138,7 @@ func buildStatementBundle(
 	b.addExecPlan(planString)
 	b.addDistSQLDiagrams()
 	b.addExplainVec()
+	b.addDeclarativeFallback()
 	b.addTrace()
 	b.addEnv(ctx)
 
@@ -301,6 +302,15 @@ func (b *stmtBundleBuilder) addExplainVec() {
 	}
 }
 
+// addDeclarativeFallback adds the diagnostics of the fallback of the
+// declarative schema changer to the legacy schema changer, if any, as file
+// declarative-fallback.txt.
+func (b *stmtBundleBuilder) addDeclarativeFallback() {
+	if d := b.plan.declarativeFallback; d != nil {
+		b.z.AddFile("declarative-fallback.txt", d.String())
+	}
+}
+
 // addTrace adds three files to the bundle: two are a json representation of the
 // trace (the default and the jaeger formats), the third one is a human-readable
 // representation.
@@ -12,13 +12,19 @@ package sql
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scplan"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scrun"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/errors"
 )
@@ -67,18 +73,41 @@ func (n *explainDDLNode) startExec(params runParams) error {
 			return explainNotPossibleError
 		}
 	}
-	return n.setExplainValues(scNode.plannedState)
+	sv := &params.ExecCfg().Settings.SV
+	planParams := scplan.Params{
+		ActiveVersion: params.ExecCfg().Settings.Version.ActiveVersion(params.ctx),
+		SafeMode:      scrun.SafeModeEnabled(sv),
+		TableStatsResolver: newTxnSchemaChangerTableStatsResolver(
+			params.ctx, params.ExecCfg(), params.p.txn, params.p.Descriptors(),
+		),
+		ThroughputHistory:           NewSchemaChangerThroughputHistory(params.ctx, params.ExecCfg()),
+		SequentialBackfillThreshold: scrun.SequentialBackfillThreshold(sv),
+		PlanRollbacks:               n.options.Flags[tree.ExplainFlagRollback],
+	}
+	if n.options.Flags[tree.ExplainFlagVerify] {
+		return n.setVerifyValues(params, scNode.plannedState, planParams)
+	}
+	return n.setExplainValues(scNode.plannedState, planParams)
 }
 
-func (n *explainDDLNode) setExplainValues(scState scpb.CurrentState) (err error) {
+// makeExplainDDLPlan plans the schema change of an EXPLAIN (DDL) statement,
+// as if it were executed, but with a placeholder job ID. The execution phase
+// and the job ID supplier in the given params are overridden.
+func makeExplainDDLPlan(scState scpb.CurrentState, params scplan.Params) (scplan.Plan, error) {
+	params.ExecutionPhase = scop.StatementPhase
+	params.SchemaChangerJobIDSupplier = func() jobspb.JobID { return 1 }
+	p, err := scplan.MakePlan(scState, params)
+	return p, errors.WithAssertionFailure(err)
+}
+
+func (n *explainDDLNode) setExplainValues(
+	scState scpb.CurrentState, planParams scplan.Params,
+) (err error) {
 	defer func() {
 		err = errors.WithAssertionFailure(err)
 	}()
 	var p scplan.Plan
-	p, err = scplan.MakePlan(scState, scplan.Params{
-		ExecutionPhase:             scop.StatementPhase,
-		SchemaChangerJobIDSupplier: func() jobspb.JobID { return 1 },
-	})
+	p, err = makeExplainDDLPlan(scState, planParams)
 	if err != nil {
 		return err
 	}
@@ -92,7 +121,11 @@ func (n *explainDDLNode) setExplainValues(scState scpb.CurrentState) (err error)
 	}
 
 	var info string
-	if n.options.Flags[tree.ExplainFlagVerbose] {
+	if n.options.Flags[tree.ExplainFlagRollback] {
+		info, err = p.ExplainRollbacks(n.options.Flags[tree.ExplainFlagVerbose])
+	} else if n.options.Flags[tree.ExplainFlagJSON] {
+		info, err = p.ExplainJSON()
+	} else if n.options.Flags[tree.ExplainFlagVerbose] {
 		info, err = p.ExplainVerbose()
 	} else {
 		info, err = p.ExplainCompact()
@@ -102,3 +135,120 @@ func (n *explainDDLNode) setExplainValues(scState scpb.CurrentState) (err error)
 	}
 	return err
 }
+
+// DeclarativeSchemaChangePlanJSON is part of the eval.Planner interface.
+func (p *planner) DeclarativeSchemaChangePlanJSON(
+	ctx context.Context, jobID int64,
+) (string, error) {
+	sc, _, err := makeDeclarativeSchemaChangePlan(
+		ctx, p.txn, p.Descriptors(), p.ExecCfg(), jobspb.JobID(jobID),
+	)
+	if err != nil {
+		return "", err
+	}
+	return sc.ExplainJSON()
+}
+
+// DeclarativeSchemaChangePlanDOT returns a graphviz rendering of the plan for
+// the remaining stages of the given declarative schema change job. If deps is
+// set, the dependency graph of the plan is rendered; otherwise, its stages are
+// rendered along with their progress: which one is to be executed next, and
+// how many times each one has failed so far. It backs the
+// /debug/schemachanger endpoint.
+func DeclarativeSchemaChangePlanDOT(
+	ctx context.Context, execCfg *ExecutorConfig, jobID jobspb.JobID, deps bool,
+) (dot string, _ error) {
+	err := DescsTxn(ctx, execCfg, func(ctx context.Context, txn *kv.Txn, col *descs.Collection) error {
+		sc, job, err := makeDeclarativeSchemaChangePlan(ctx, txn, col, execCfg, jobID)
+		if err != nil {
+			return err
+		}
+		if deps {
+			dot, err = sc.DependenciesDOT()
+			return err
+		}
+		var stageErrors []jobspb.SchemaChangeStageError
+		if progress := job.Progress(); progress.GetNewSchemaChange() != nil {
+			stageErrors = progress.GetNewSchemaChange().StageErrors
+		}
+		dot, err = sc.StagesDOT(func(s scplan.Stage) string {
+			annotation := "pending"
+			if next := sc.Stages[0]; s.Phase == next.Phase && s.Ordinal == next.Ordinal {
+				annotation = "next"
+			}
+			// Look for the errors of the previous attempts of this stage.
+			fingerprint := scrun.StageFingerprint(s)
+			for i := len(stageErrors) - 1; i >= 0; i-- {
+				if se := &stageErrors[i]; se.Fingerprint == fingerprint &&
+					se.Phase == s.Phase.String() && se.Rollback == sc.InRollback {
+					return fmt.Sprintf("%s, failed %d time(s): %v",
+						annotation, se.Attempt, decodeSchemaChangeStageError(ctx, se))
+				}
+			}
+			return annotation
+		})
+		return err
+	})
+	return dot, err
+}
+
+// makeDeclarativeSchemaChangePlan plans the remaining stages of the given
+// declarative schema change job, based on the state stored in the descriptors
+// it targets. It also returns the job.
+func makeDeclarativeSchemaChangePlan(
+	ctx context.Context,
+	txn *kv.Txn,
+	col *descs.Collection,
+	execCfg *ExecutorConfig,
+	jobID jobspb.JobID,
+) (scplan.Plan, *jobs.Job, error) {
+	job, err := execCfg.JobRegistry.LoadJobWithTxn(ctx, jobID, txn)
+	if err != nil {
+		return scplan.Plan{}, nil, err
+	}
+	payload := job.Payload()
+	if payload.GetNewSchemaChange() == nil {
+		return scplan.Plan{}, nil, pgerror.Newf(pgcode.InvalidParameterValue,
+			"job %d is not a declarative schema change job", jobID)
+	}
+	// Collect the schema changer state of the descriptors targeted by the job,
+	// skipping those which have already been removed.
+	var descriptorStates []*scpb.DescriptorState
+	for _, id := range payload.DescriptorIDs {
+		desc, err := col.GetImmutableDescriptorByID(ctx, txn, id, tree.CommonLookupFlags{
+			AvoidLeased:    true,
+			IncludeOffline: true,
+			IncludeDropped: true,
+		})
+		if err != nil {
+			if errors.Is(err, catalog.ErrDescriptorNotFound) {
+				continue
+			}
+			return scplan.Plan{}, nil, err
+		}
+		if s := desc.GetDeclarativeSchemaChangerState(); s != nil && s.JobID == jobID {
+			descriptorStates = append(descriptorStates, s)
+		}
+	}
+	if len(descriptorStates) == 0 {
+		return scplan.Plan{}, nil, pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+			"schema change job %d has no remaining stages", jobID)
+	}
+	state, err := scpb.MakeCurrentStateFromDescriptors(descriptorStates)
+	if err != nil {
+		return scplan.Plan{}, nil, err
+	}
+	sc, err := scplan.MakePlan(state, scplan.Params{
+		ExecutionPhase:              scop.PostCommitPhase,
+		SchemaChangerJobIDSupplier:  func() jobspb.JobID { return jobID },
+		ActiveVersion:               execCfg.Settings.Version.ActiveVersion(ctx),
+		SafeMode:                    payload.GetNewSchemaChange().SafeMode,
+		TableStatsResolver:          newTxnSchemaChangerTableStatsResolver(ctx, execCfg, txn, col),
+		ThroughputHistory:           NewSchemaChangerThroughputHistory(ctx, execCfg),
+		SequentialBackfillThreshold: scrun.SequentialBackfillThreshold(&execCfg.Settings.SV),
+	})
+	if err != nil {
+		return scplan.Plan{}, nil, err
+	}
+	return sc, job, nil
+}
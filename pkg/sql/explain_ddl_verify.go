@@ -0,0 +1,134 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scplan"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/screl"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
+)
+
+// setVerifyValues sets the output of EXPLAIN (DDL, VERIFY) statements. The
+// schema change is planned as for EXPLAIN (DDL), which checks the dependency
+// rules and assigns the stages, and then verified against the current state
+// of the descriptors. The warnings which should be considered before running
+// the statement are reported one per row. As for any other EXPLAIN (DDL)
+// statement, no schema change job is created.
+func (n *explainDDLNode) setVerifyValues(
+	params runParams, scState scpb.CurrentState, planParams scplan.Params,
+) error {
+	p, err := makeExplainDDLPlan(scState, planParams)
+	if err != nil {
+		return err
+	}
+	warnings, err := verifyDeclarativeSchemaChangePlan(params.ctx, params.p, p)
+	if err != nil {
+		return err
+	}
+	if len(warnings) == 0 {
+		warnings = []string{"no warnings"}
+	}
+	n.values = make([]tree.Datums, len(warnings))
+	for i, w := range warnings {
+		n.values[i] = tree.Datums{tree.NewDString(w)}
+	}
+	return nil
+}
+
+// verifyDeclarativeSchemaChangePlan returns the warnings about the given plan:
+//   - the descriptors targeted by the schema change which fail validation in
+//     their current state;
+//   - the cluster upgrade in progress, if any, since the schema change is
+//     planned for the active cluster version;
+//   - the backfills, along with the estimated number of rows of the
+//     backfilled tables;
+//   - the validations of constraints, which fail the schema change if the
+//     existing rows violate them.
+func verifyDeclarativeSchemaChangePlan(
+	ctx context.Context, p *planner, sc scplan.Plan,
+) (warnings []string, _ error) {
+	var ids catalog.DescriptorIDSet
+	for _, t := range sc.TargetState.Targets {
+		ids.Add(screl.GetDescID(t.Element()))
+	}
+	descs := make(map[descpb.ID]catalog.Descriptor, ids.Len())
+	for _, id := range ids.Ordered() {
+		desc, err := p.Descriptors().GetImmutableDescriptorByID(ctx, p.txn, id, tree.CommonLookupFlags{
+			AvoidLeased:    true,
+			IncludeOffline: true,
+			IncludeDropped: true,
+		})
+		if err != nil {
+			// Descriptors created by the schema change don't exist yet.
+			if errors.Is(err, catalog.ErrDescriptorNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		descs[id] = desc
+		if err := p.Descriptors().Validate(
+			ctx, p.txn, catalog.NoValidationTelemetry, catalog.ValidationLevelAllPreTxnCommit, desc,
+		); err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s %q (%d) fails validation: %v",
+				desc.DescriptorType(), desc.GetName(), id, err))
+		}
+	}
+
+	if binaryVersion := p.ExecCfg().Settings.Version.BinaryVersion(); !sc.Params.ActiveVersion.IsActiveVersion(binaryVersion) {
+		warnings = append(warnings, fmt.Sprintf(
+			"the cluster upgrade to version %s is not finalized: the schema change is planned for version %s",
+			binaryVersion, sc.Params.ActiveVersion))
+	}
+
+	tableName := func(id descpb.ID) string {
+		if desc, ok := descs[id]; ok {
+			return fmt.Sprintf("%q", desc.GetName())
+		}
+		return fmt.Sprintf("[%d]", id)
+	}
+	estimatedRows := func(id descpb.ID) string {
+		if table, ok := descs[id].(catalog.TableDescriptor); ok {
+			stats, err := p.ExecCfg().TableStatsCache.GetTableStats(ctx, table)
+			if err == nil && len(stats) > 0 {
+				return fmt.Sprintf("%d rows as of the latest table statistics", stats[0].RowCount)
+			}
+		}
+		return "unknown number of rows"
+	}
+	for _, s := range sc.Stages {
+		stage := fmt.Sprintf("stage %d of %d in %s", s.Ordinal, s.StagesInPhase, s.Phase)
+		for _, op := range s.EdgeOps {
+			switch op := op.(type) {
+			case *scop.BackfillIndex:
+				warnings = append(warnings, fmt.Sprintf(
+					"%s backfills index %d of table %s from index %d: %s",
+					stage, op.IndexID, tableName(op.TableID), op.SourceIndexID, estimatedRows(op.TableID)))
+			case *scop.ValidateUniqueIndex:
+				warnings = append(warnings, fmt.Sprintf(
+					"%s validates the uniqueness of index %d of table %s: the schema change fails if existing rows violate it",
+					stage, op.IndexID, tableName(op.TableID)))
+			case *scop.ValidateCheckConstraint:
+				warnings = append(warnings, fmt.Sprintf(
+					"%s validates check constraint %q of table %s: until then, it is only enforced on writes, and the schema change fails if existing rows violate it",
+					stage, op.Name, tableName(op.TableID)))
+			}
+		}
+	}
+	return warnings, nil
+}
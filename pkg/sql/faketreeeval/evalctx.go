@@ -291,6 +291,13 @@ func (*DummyEvalPlanner) RepairTTLScheduledJobForTable(ctx context.Context, tabl
 	return errors.WithStack(errEvalPlanner)
 }
 
+// DeclarativeSchemaChangePlanJSON is part of the Planner interface.
+func (*DummyEvalPlanner) DeclarativeSchemaChangePlanJSON(
+	ctx context.Context, jobID int64,
+) (string, error) {
+	return "", errors.WithStack(errEvalPlanner)
+}
+
 // ExecutorConfig is part of the Planner interface.
 func (*DummyEvalPlanner) ExecutorConfig() interface{} {
 	return nil
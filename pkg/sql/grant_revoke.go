@@ -36,6 +36,11 @@ import (
 	"github.com/cockroachdb/errors"
 )
 
+// errColumnPrivilegesNotSupported is returned when planning GRANT or REVOKE
+// on columns, which only the declarative schema changer implements.
+var errColumnPrivilegesNotSupported = pgerror.New(pgcode.FeatureNotSupported,
+	"column privileges are only supported by the declarative schema changer")
+
 // Grant adds privileges to users.
 // TODO(marc): open questions:
 // - should we have root always allowed and not present in the permissions list?
@@ -43,6 +48,9 @@ import (
 //   Notes: postgres requires the object owner.
 //          mysql requires the "grant option" and the same privileges, and sometimes superuser.
 func (p *planner) Grant(ctx context.Context, n *tree.Grant) (planNode, error) {
+	if n.Columns != nil {
+		return nil, errColumnPrivilegesNotSupported
+	}
 	grantOn, err := p.getGrantOnObject(ctx, n.Targets, sqltelemetry.IncIAMGrantPrivilegesCounter)
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot get the privileges on the grant targets")
@@ -100,6 +108,9 @@ func (p *planner) Grant(ctx context.Context, n *tree.Grant) (planNode, error) {
 //   Notes: postgres requires the object owner.
 //          mysql requires the "grant option" and the same privileges, and sometimes superuser.
 func (p *planner) Revoke(ctx context.Context, n *tree.Revoke) (planNode, error) {
+	if n.Columns != nil {
+		return nil, errColumnPrivilegesNotSupported
+	}
 	grantOn, err := p.getGrantOnObject(ctx, n.Targets, sqltelemetry.IncIAMRevokePrivilegesCounter)
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot get the privileges on the grant targets")
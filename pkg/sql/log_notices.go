@@ -0,0 +1,92 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+)
+
+const (
+	// logNoticesMaxBytes is the maximum size of the log entries captured
+	// while executing a statement, when the log_notices_enabled session
+	// variable is set. When it is exceeded, the oldest entries are
+	// discarded.
+	logNoticesMaxBytes = 64 << 10
+
+	// logNoticesMaxEntries is the maximum number of log entries
+	// forwarded to the client as notices for a single statement.
+	logNoticesMaxEntries = 20
+)
+
+// captureLogNotices starts capturing the log entries with severity
+// WARNING or higher emitted under the returned context, if the session
+// has enabled the log_notices_enabled session variable. The entries are
+// redacted as they are captured.
+//
+// The returned function must be called once the statement has executed,
+// to forward the captured entries to the client as notices and release
+// them.
+func (ex *connExecutor) captureLogNotices(
+	ctx context.Context,
+) (context.Context, func(context.Context, noticeSender)) {
+	if !ex.sessionData().LogNoticesEnabled {
+		return ctx, func(context.Context, noticeSender) {}
+	}
+	captureCtx, c, err := log.CaptureForContext(ctx, log.CaptureOptions{
+		Threshold: severity.WARNING,
+		MaxBytes:  logNoticesMaxBytes,
+		EditMode:  log.WithoutSensitiveData,
+	})
+	if err != nil {
+		log.Warningf(ctx, "unable to capture log entries for notices: %v", err)
+		return ctx, func(context.Context, noticeSender) {}
+	}
+	return captureCtx, func(ctx context.Context, res noticeSender) {
+		defer func() {
+			if err := c.Close(); err != nil {
+				log.Warningf(ctx, "unable to release the log entries captured for notices: %v", err)
+			}
+		}()
+		ex.sendLogNotices(ctx, c, res)
+	}
+}
+
+// sendLogNotices forwards the log entries of the given capture to the
+// client as WARNING notices, up to logNoticesMaxEntries. The entries
+// which are not forwarded are summarized in a final notice.
+func (ex *connExecutor) sendLogNotices(ctx context.Context, c *log.Capture, res noticeSender) {
+	if pgnotice.DisplaySeverityWarning > pgnotice.DisplaySeverity(ex.sessionData().NoticeDisplaySeverity) ||
+		!NoticesEnabled.Get(&ex.server.cfg.Settings.SV) {
+		return
+	}
+	entries, err := c.Entries()
+	if err != nil {
+		log.Warningf(ctx, "unable to retrieve the log entries captured for notices: %v", err)
+		return
+	}
+	omitted := c.Dropped()
+	if len(entries) > logNoticesMaxEntries {
+		omitted += int64(len(entries) - logNoticesMaxEntries)
+		entries = entries[:logNoticesMaxEntries]
+	}
+	for _, e := range entries {
+		res.BufferNotice(pgnotice.NewWithSeverityf("WARNING",
+			"server log (%s, %s): %s", e.Channel, e.Severity, e.Message))
+	}
+	if omitted > 0 {
+		res.BufferNotice(pgnotice.NewWithSeverityf("WARNING",
+			"server log: %d more log entries were not reported", omitted))
+	}
+}
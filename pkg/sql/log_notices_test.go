@@ -0,0 +1,97 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql_test
+
+import (
+	"context"
+	gosql "database/sql"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogNotices(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const marker = "log_notices_test"
+	ctx := context.Background()
+	s, _, _ := serverutils.StartServer(t, base.TestServerArgs{
+		Knobs: base.TestingKnobs{
+			SQLExecutor: &sql.ExecutorTestingKnobs{
+				BeforeExecute: func(ctx context.Context, stmt string) {
+					if !strings.Contains(stmt, marker) {
+						return
+					}
+					log.Ops.Infof(ctx, "not forwarded")
+					log.Ops.Warningf(ctx, "hello %s", "world")
+					for i := 0; i < 30; i++ {
+						log.Health.Errorf(ctx, "error %d", i)
+					}
+				},
+			},
+		},
+	})
+	defer s.Stopper().Stop(ctx)
+
+	pgURL, cleanup := sqlutils.PGUrl(t, s.ServingSQLAddr(), t.Name(), url.User(username.RootUser))
+	defer cleanup()
+	connector, err := pq.NewConnector(pgURL.String())
+	require.NoError(t, err)
+	var mu struct {
+		syncutil.Mutex
+		notices []string
+	}
+	db := gosql.OpenDB(pq.ConnectorWithNoticeHandler(connector, func(n *pq.Error) {
+		mu.Lock()
+		defer mu.Unlock()
+		mu.notices = append(mu.notices, n.Message)
+	}))
+	defer db.Close()
+	// The session variable only applies to the connection it is set on.
+	db.SetMaxOpenConns(1)
+	runner := sqlutils.MakeSQLRunner(db)
+	notices := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		res := mu.notices
+		mu.notices = nil
+		return res
+	}
+
+	// No entries are forwarded by default.
+	runner.Exec(t, "SELECT '"+marker+"'")
+	require.Empty(t, notices())
+
+	runner.Exec(t, "SET log_notices_enabled = true")
+	runner.Exec(t, "SELECT '"+marker+"'")
+	res := notices()
+	require.Len(t, res, 21)
+	require.Equal(t, "server log (OPS, WARNING): hello ‹×›", res[0])
+	require.Equal(t, "server log (HEALTH, ERROR): error 0", res[1])
+	require.Equal(t, "server log: 11 more log entries were not reported", res[20])
+
+	// The notices are subject to the display severity of the session.
+	runner.Exec(t, "SET notice_display_severity = 'error'")
+	runner.Exec(t, "SELECT '"+marker+"'")
+	require.Empty(t, notices())
+}
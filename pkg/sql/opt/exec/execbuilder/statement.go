@@ -286,6 +286,7 @@ func (b *Builder) buildControlJobs(ctl *memo.ControlJobsExpr) (execPlan, error)
 		ctl.Command,
 		input.root,
 		reason,
+		ctl.AtStage,
 	)
 	if err != nil {
 		return execPlan{}, err
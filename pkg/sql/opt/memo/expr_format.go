@@ -1660,6 +1660,9 @@ func FormatPrivate(f *ExprFmtCtx, private interface{}, physProps *physical.Requi
 
 	case *ControlJobsPrivate:
 		fmt.Fprintf(f.Buffer, " (%s)", tree.JobCommandToStatement[t.Command])
+		if t.AtStage != 0 {
+			fmt.Fprintf(f.Buffer, " [at-stage=%d]", t.AtStage)
+		}
 
 	case *CancelPrivate:
 		if t.IfExists {
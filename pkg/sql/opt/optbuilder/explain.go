@@ -50,8 +50,14 @@ func (b *Builder) buildExplain(explain *tree.Explain, inScope *scope) (outScope
 		telemetry.Inc(sqltelemetry.ExplainVecUseCounter)
 
 	case tree.ExplainDDL:
-		if explain.Flags[tree.ExplainFlagViz] {
+		if explain.Flags[tree.ExplainFlagVerify] {
+			telemetry.Inc(sqltelemetry.ExplainDDLVerify)
+		} else if explain.Flags[tree.ExplainFlagRollback] {
+			telemetry.Inc(sqltelemetry.ExplainDDLRollback)
+		} else if explain.Flags[tree.ExplainFlagViz] {
 			telemetry.Inc(sqltelemetry.ExplainDDLViz)
+		} else if explain.Flags[tree.ExplainFlagJSON] {
+			telemetry.Inc(sqltelemetry.ExplainDDLJSON)
 		} else if explain.Flags[tree.ExplainFlagVerbose] {
 			telemetry.Inc(sqltelemetry.ExplainDDLVerbose)
 		} else {
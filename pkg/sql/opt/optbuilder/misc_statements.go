@@ -50,6 +50,7 @@ func (b *Builder) buildControlJobs(n *tree.ControlJobs, inScope *scope) (outScop
 		&memo.ControlJobsPrivate{
 			Props:   inputScope.makePhysicalProps(),
 			Command: n.Command,
+			AtStage: n.AtStage,
 		},
 	)
 	return outScope
@@ -2048,7 +2048,7 @@ func (ef *execFactory) ConstructAlterRangeRelocate(
 
 // ConstructControlJobs is part of the exec.Factory interface.
 func (ef *execFactory) ConstructControlJobs(
-	command tree.JobCommand, input exec.Node, reason tree.TypedExpr,
+	command tree.JobCommand, input exec.Node, reason tree.TypedExpr, atStage int64,
 ) (exec.Node, error) {
 	reasonDatum, err := eval.Expr(ef.planner.EvalContext(), reason)
 	if err != nil {
@@ -2068,6 +2068,7 @@ func (ef *execFactory) ConstructControlJobs(
 		rows:          input.(planNode),
 		desiredStatus: jobCommandToDesiredStatus[command],
 		reason:        reasonStr,
+		atStage:       atStage,
 	}, nil
 }
 
@@ -933,7 +933,7 @@ func (u *sqlSymUnion) functionObjs() tree.FuncObjs {
 %token <str> SKIP_MISSING_SEQUENCES SKIP_MISSING_SEQUENCE_OWNERS SKIP_MISSING_VIEWS SMALLINT SMALLSERIAL SNAPSHOT SOME SPLIT SQL
 %token <str> SQLLOGIN
 
-%token <str> STABLE START STATE STATISTICS STATUS STDIN STREAM STRICT STRING STORAGE STORE STORED STORING SUBSTRING SUPER
+%token <str> STABLE STAGE START STATE STATISTICS STATUS STDIN STREAM STRICT STRING STORAGE STORE STORED STORING SUBSTRING SUPER
 %token <str> SUPPORT SURVIVE SURVIVAL SYMMETRIC SYNTAX SYSTEM SQRT SUBSCRIPTION STATEMENTS
 
 %token <str> TABLE TABLES TABLESPACE TEMP TEMPLATE TEMPORARY TENANT TENANTS TESTING_RELOCATE TEXT THEN
@@ -5409,6 +5409,8 @@ deallocate_stmt:
 // %Text:
 // Grant privileges:
 //   GRANT {ALL [PRIVILEGES] | <privileges...> } ON <targets...> TO <grantees...>
+// Grant privileges on columns:
+//   GRANT {ALL [PRIVILEGES] | <privileges...> } ( <columns...> ) ON [TABLE] <tablename> TO <grantees...>
 // Grant role membership:
 //   GRANT <roles...> TO <grantees...> [WITH ADMIN OPTION]
 //
@@ -5428,6 +5430,10 @@ grant_stmt:
   {
     $$.val = &tree.Grant{Privileges: $2.privilegeList(), Grantees: $6.roleSpecList(), Targets: $4.grantTargetList(), WithGrantOption: $7.bool(),}
   }
+| GRANT privileges '(' name_list ')' ON grant_targets TO role_spec_list opt_with_grant_option
+  {
+    $$.val = &tree.Grant{Privileges: $2.privilegeList(), Columns: $4.nameList(), Grantees: $9.roleSpecList(), Targets: $7.grantTargetList(), WithGrantOption: $10.bool(),}
+  }
 | GRANT privilege_list TO role_spec_list
   {
     $$.val = &tree.GrantRole{Roles: $2.nameList(), Members: $4.roleSpecList(), AdminOption: false}
@@ -5509,6 +5515,8 @@ grant_stmt:
 // %Text:
 // Revoke privileges:
 //   REVOKE {ALL | <privileges...> } ON <targets...> FROM <grantees...>
+// Revoke privileges on columns:
+//   REVOKE {ALL | <privileges...> } ( <columns...> ) ON [TABLE] <tablename> FROM <grantees...>
 // Revoke role membership:
 //   REVOKE [ADMIN OPTION FOR] <roles...> FROM <grantees...>
 //
@@ -5532,6 +5540,14 @@ revoke_stmt:
   {
     $$.val = &tree.Revoke{Privileges: $5.privilegeList(), Grantees: $9.roleSpecList(), Targets: $7.grantTargetList(), GrantOptionFor: true}
   }
+| REVOKE privileges '(' name_list ')' ON grant_targets FROM role_spec_list
+  {
+    $$.val = &tree.Revoke{Privileges: $2.privilegeList(), Columns: $4.nameList(), Grantees: $9.roleSpecList(), Targets: $7.grantTargetList(), GrantOptionFor: false}
+  }
+| REVOKE GRANT OPTION FOR privileges '(' name_list ')' ON grant_targets FROM role_spec_list
+  {
+    $$.val = &tree.Revoke{Privileges: $5.privilegeList(), Columns: $7.nameList(), Grantees: $12.roleSpecList(), Targets: $10.grantTargetList(), GrantOptionFor: true}
+  }
 | REVOKE privilege_list FROM role_spec_list
   {
     $$.val = &tree.RevokeRole{Roles: $2.nameList(), Members: $4.roleSpecList(), AdminOption: false }
@@ -7876,7 +7892,11 @@ resume_all_jobs_stmt:
 // %Category: Misc
 // %Text:
 // PAUSE JOBS <selectclause>
-// PAUSE JOB <jobid>
+// PAUSE JOB <jobid> [AT STAGE <stage>]
+//
+// With AT STAGE, a declarative schema change job is paused once it
+// reaches the given post-commit stage, numbered from 1, before
+// executing it.
 // %SeeAlso: SHOW JOBS, CANCEL JOBS, RESUME JOBS
 pause_jobs_stmt:
   PAUSE JOB a_expr
@@ -7888,6 +7908,16 @@ pause_jobs_stmt:
       Command: tree.PauseJob,
     }
   }
+| PAUSE JOB a_expr AT STAGE iconst64
+  {
+    $$.val = &tree.ControlJobs{
+      Jobs: &tree.Select{
+        Select: &tree.ValuesClause{Rows: []tree.Exprs{tree.Exprs{$3.expr()}}},
+      },
+      Command: tree.PauseJob,
+      AtStage: $6.int64(),
+    }
+  }
 | PAUSE JOB a_expr WITH REASON '=' string_or_placeholder
   {
     $$.val = &tree.ControlJobs{
@@ -15425,6 +15455,7 @@ unreserved_keyword:
 | SQL
 | SQLLOGIN
 | STABLE
+| STAGE
 | START
 | STATE
 | STATEMENTS
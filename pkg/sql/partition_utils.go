@@ -109,19 +109,24 @@ func GenerateSubzoneSpans(
 
 	var indexCovering covering.Covering
 	var partitionCoverings []covering.Covering
+	addIndexCovering := func(indexID descpb.IndexID) {
+		if _, indexSubzoneExists := subzoneIndexByIndexID[indexID]; !indexSubzoneExists {
+			return
+		}
+		idxSpan := tableDesc.IndexSpan(codec, indexID)
+		// Each index starts with a unique prefix, so (from a precedence
+		// perspective) it's safe to append them all together.
+		indexCovering = append(indexCovering, covering.Range{
+			Start: idxSpan.Key, End: idxSpan.EndKey,
+			Payload: zonepb.Subzone{IndexID: uint32(indexID)},
+		})
+	}
+	indexesInDesc := make(map[descpb.IndexID]struct{})
 	if err := catalog.ForEachIndex(tableDesc, catalog.IndexOpts{
 		AddMutations: true,
 	}, func(idx catalog.Index) error {
-		_, indexSubzoneExists := subzoneIndexByIndexID[idx.GetID()]
-		if indexSubzoneExists {
-			idxSpan := tableDesc.IndexSpan(codec, idx.GetID())
-			// Each index starts with a unique prefix, so (from a precedence
-			// perspective) it's safe to append them all together.
-			indexCovering = append(indexCovering, covering.Range{
-				Start: idxSpan.Key, End: idxSpan.EndKey,
-				Payload: zonepb.Subzone{IndexID: uint32(idx.GetID())},
-			})
-		}
+		indexesInDesc[idx.GetID()] = struct{}{}
+		addIndexCovering(idx.GetID())
 
 		var emptyPrefix []tree.Datum
 		indexPartitionCoverings, err := indexCoveringsForPartitioning(
@@ -140,6 +145,21 @@ func GenerateSubzoneSpans(
 		return nil, err
 	}
 
+	// The declarative schema changer overrides the GC TTL of the indexes it
+	// drops via subzones, which outlive the indexes in the descriptor until
+	// their data is garbage collected (see zonepb.ZoneConfig.SetIndexGCTTL).
+	// The span of an index only depends on its ID, so keep covering it. The
+	// spans of the partitions of such an index cannot be rebuilt, but they
+	// are contained in the span of the index subzone, which carries the
+	// same GC TTL.
+	for _, subzone := range subzones {
+		indexID := descpb.IndexID(subzone.IndexID)
+		if _, ok := indexesInDesc[indexID]; ok || len(subzone.PartitionName) > 0 {
+			continue
+		}
+		addIndexCovering(indexID)
+	}
+
 	// OverlapCoveringMerge returns the payloads for any coverings that overlap
 	// in the same order they were input. So, we require that they be ordered
 	// with highest precedence first, so the first payload of each range is the
@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
 	"github.com/cockroachdb/cockroach/pkg/sql/physicalplan"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scerrors"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
@@ -323,6 +324,11 @@ type planTop struct {
 	// diagrams, are saved here.
 	distSQLFlowInfos []flowInfo
 
+	// If we are collecting query diagnostics and the declarative schema
+	// changer fell back to the legacy schema changer, the diagnostics of the
+	// fallback are saved here.
+	declarativeFallback *scerrors.FallbackDiagnostics
+
 	instrumentation *instrumentationHelper
 }
 
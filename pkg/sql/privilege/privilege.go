@@ -126,6 +126,9 @@ var (
 	GlobalPrivileges             = List{ALL, BACKUP, MODIFYCLUSTERSETTING, EXTERNALCONNECTION, VIEWACTIVITY, VIEWACTIVITYREDACTED, VIEWCLUSTERSETTING, CANCELQUERY, NOSQLLOGIN, VIEWCLUSTERMETADATA, VIEWDEBUG}
 	VirtualTablePrivileges       = List{ALL, SELECT}
 	ExternalConnectionPrivileges = List{ALL, USAGE, DROP}
+	// ColumnPrivileges are the table privileges which can also be granted on
+	// the columns of a table.
+	ColumnPrivileges = List{SELECT, INSERT, UPDATE}
 )
 
 // Mask returns the bitmask for a given privilege.
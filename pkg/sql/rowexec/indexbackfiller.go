@@ -26,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/admission/admissionpb"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -425,18 +426,19 @@ func (ib *indexBackfiller) buildIndexEntryBatch(
 	defer traceSpan.Finish()
 	start := timeutil.Now()
 	var entries []rowenc.IndexEntry
-	if err := ib.flowCtx.Cfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
-		if err := txn.SetFixedTimestamp(ctx, readAsOf); err != nil {
-			return err
-		}
+	if err := ib.flowCtx.Cfg.DB.TxnWithAdmissionControl(ctx, roachpb.AdmissionHeader_FROM_SQL, admissionpb.BulkNormalPri,
+		func(ctx context.Context, txn *kv.Txn) error {
+			if err := txn.SetFixedTimestamp(ctx, readAsOf); err != nil {
+				return err
+			}
 
-		// TODO(knz): do KV tracing in DistSQL processors.
-		var err error
-		entries, key, memUsedBuildingBatch, err = ib.BuildIndexEntriesChunk(
-			ctx, txn, ib.desc, sp, ib.spec.ChunkSize, false, /* traceKV */
-		)
-		return err
-	}); err != nil {
+			// TODO(knz): do KV tracing in DistSQL processors.
+			var err error
+			entries, key, memUsedBuildingBatch, err = ib.BuildIndexEntriesChunk(
+				ctx, txn, ib.desc, sp, ib.spec.ChunkSize, false, /* traceKV */
+			)
+			return err
+		}); err != nil {
 		return nil, nil, 0, err
 	}
 	prepTime := timeutil.Since(start)
@@ -13,12 +13,14 @@ package sql
 import (
 	"context"
 	"reflect"
+	"sync"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
 	"github.com/cockroachdb/cockroach/pkg/sql/descmetadata"
@@ -27,12 +29,16 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scerrors"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scexec"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scplan"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scrun"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
 	"github.com/cockroachdb/cockroach/pkg/util/retry"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/redact"
@@ -60,6 +66,20 @@ func (p *planner) SchemaChange(ctx context.Context, stmt tree.Statement) (planNo
 			mode == sessiondatapb.UseNewSchemaChangerUnsafe) && !p.extendedEvalCtx.TxnIsSingleStmt) {
 		return nil, nil
 	}
+	// The declarative schema changer is disabled altogether if its planner
+	// registries failed verification.
+	if err := CheckDeclarativeSchemaChanger(ctx); err != nil {
+		if mode == sessiondatapb.UseNewSchemaChangerUnsafeAlways {
+			return nil, err
+		}
+		return nil, nil
+	}
+	// Statements may be explicitly excluded from declarative planning, as an
+	// escape hatch against bugs in the declarative schema changer.
+	if tag, ok := scbuild.ForcedLegacyStatementTag(&p.ExecCfg().Settings.SV, stmt, mode); ok {
+		p.logEventsOnlyExternally(ctx, &eventpb.ForceLegacySchemaChanger{StatementTag: tag})
+		return nil, nil
+	}
 	scs := p.extendedEvalCtx.SchemaChangerState
 	scs.stmts = append(scs.stmts, p.stmt.SQL)
 	deps := scdeps.NewBuilderDependencies(
@@ -80,6 +100,7 @@ func (p *planner) SchemaChange(ctx context.Context, stmt tree.Statement) (planNo
 	state, err := scbuild.Build(ctx, deps, scs.state, stmt)
 	if scerrors.HasNotImplemented(err) &&
 		mode != sessiondatapb.UseNewSchemaChangerUnsafeAlways {
+		p.recordDeclarativeFallback(err)
 		return nil, nil
 	}
 	if err != nil {
@@ -98,6 +119,43 @@ func (p *planner) SchemaChange(ctx context.Context, stmt tree.Statement) (planNo
 	}, nil
 }
 
+// recordDeclarativeFallback records the diagnostics of the statement which
+// the declarative schema changer did not build in telemetry and, when one is
+// being collected, in the statement diagnostics bundle.
+func (p *planner) recordDeclarativeFallback(err error) {
+	d, ok := scerrors.GetFallbackDiagnostics(err)
+	if !ok {
+		return
+	}
+	telemetry.Inc(sqltelemetry.DeclarativeSchemaChangerFallbackCounter(d.UnsupportedNode))
+	if p.curPlan.instrumentation != nil && p.curPlan.instrumentation.collectBundle {
+		p.curPlan.declarativeFallback = &d
+	}
+}
+
+// declarativeSchemaChangerDisabledOnce ensures that the declarative schema
+// changer getting disabled is reported once per process.
+var declarativeSchemaChangerDisabledOnce sync.Once
+
+// CheckDeclarativeSchemaChanger returns an error if the planner registries of
+// the declarative schema changer failed verification, which is the result of
+// a bug in its op specs or rules. In that case, the declarative schema changer
+// is disabled instead of the node failing to start: schema changes get planned
+// by the legacy schema changer, and the error is reported once, as an ERROR on
+// the OPS channel and as a DeclarativeSchemaChangerDisabled event.
+func CheckDeclarativeSchemaChanger(ctx context.Context) error {
+	err := scplan.VerifyRegistries()
+	if err != nil {
+		declarativeSchemaChangerDisabledOnce.Do(func() {
+			log.Ops.Shoutf(ctx, severity.ERROR,
+				"the declarative schema changer is disabled, "+
+					"schema changes will be planned by the legacy schema changer: %v", err)
+			log.StructuredEvent(ctx, &eventpb.DeclarativeSchemaChangerDisabled{Error: err.Error()})
+		})
+	}
+	return err
+}
+
 // waitForDescriptorSchemaChanges polls the specified descriptor (in separate
 // transactions) until all its ongoing schema changes have completed.
 // Internally, this call will restart the planner's underlying transaction and
@@ -43,6 +43,24 @@ var (
 		Measurement: "Errors",
 		Unit:        metric.Unit_COUNT,
 	}
+	metaPlacementPreferred = metric.Metadata{
+		Name:        "sql.schema_changer.placement.preferred",
+		Help:        "Counter of the number of declarative schema change jobs executed in the locality of the leaseholders of their ranges",
+		Measurement: "Schema changes",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaPlacementDeferred = metric.Metadata{
+		Name:        "sql.schema_changer.placement.deferred",
+		Help:        "Counter of the number of declarative schema change jobs released by a node to be executed in the locality of the leaseholders of their ranges",
+		Measurement: "Schema changes",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaPlacementFallback = metric.Metadata{
+		Name:        "sql.schema_changer.placement.fallback",
+		Help:        "Counter of the number of declarative schema change jobs executed outside of the locality of the leaseholders of their ranges",
+		Measurement: "Schema changes",
+		Unit:        metric.Unit_COUNT,
+	}
 )
 
 // SchemaChangerMetrics are metrics corresponding to the schema changer.
@@ -53,6 +71,12 @@ type SchemaChangerMetrics struct {
 	PermanentErrors      *metric.Counter
 	ConstraintErrors     telemetry.Counter
 	UncategorizedErrors  telemetry.Counter
+
+	// PlacementPreferred, PlacementDeferred and PlacementFallback count the
+	// placement decisions made when resuming declarative schema change jobs.
+	PlacementPreferred *metric.Counter
+	PlacementDeferred  *metric.Counter
+	PlacementFallback  *metric.Counter
 }
 
 // MetricStruct makes SchemaChangerMetrics a metric.Struct.
@@ -69,5 +93,8 @@ func NewSchemaChangerMetrics() *SchemaChangerMetrics {
 		PermanentErrors:      metric.NewCounter(metaPermanentErrors),
 		ConstraintErrors:     sqltelemetry.SchemaChangeErrorCounter("constraint_violation"),
 		UncategorizedErrors:  sqltelemetry.SchemaChangeErrorCounter("uncategorized"),
+		PlacementPreferred:   metric.NewCounter(metaPlacementPreferred),
+		PlacementDeferred:    metric.NewCounter(metaPlacementDeferred),
+		PlacementFallback:    metric.NewCounter(metaPlacementFallback),
 	}
 }
@@ -0,0 +1,118 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scplan"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+// schemaChangerTableStatsResolver implements scplan.TableStatsResolver using
+// the table statistics cache. Statistics are best-effort: failing to read
+// them only leaves the stages of the plan without an estimate.
+type schemaChangerTableStatsResolver struct {
+	ctx      context.Context
+	execCfg  *ExecutorConfig
+	getTable func(ctx context.Context, id descpb.ID) (catalog.TableDescriptor, error)
+}
+
+var _ scplan.TableStatsResolver = (*schemaChangerTableStatsResolver)(nil)
+
+// NewSchemaChangerTableStatsResolver returns an scplan.TableStatsResolver for
+// the schema changer job, which reads each table descriptor in its own
+// transaction.
+func NewSchemaChangerTableStatsResolver(
+	ctx context.Context, execCfg *ExecutorConfig,
+) scplan.TableStatsResolver {
+	return &schemaChangerTableStatsResolver{
+		ctx:     ctx,
+		execCfg: execCfg,
+		getTable: func(ctx context.Context, id descpb.ID) (table catalog.TableDescriptor, err error) {
+			err = DescsTxn(ctx, execCfg, func(ctx context.Context, txn *kv.Txn, col *descs.Collection) error {
+				table, err = getTableForStats(ctx, txn, col, id)
+				return err
+			})
+			return table, err
+		},
+	}
+}
+
+// newTxnSchemaChangerTableStatsResolver returns an scplan.TableStatsResolver
+// which reads the table descriptors in the given transaction.
+func newTxnSchemaChangerTableStatsResolver(
+	ctx context.Context, execCfg *ExecutorConfig, txn *kv.Txn, col *descs.Collection,
+) scplan.TableStatsResolver {
+	return &schemaChangerTableStatsResolver{
+		ctx:     ctx,
+		execCfg: execCfg,
+		getTable: func(ctx context.Context, id descpb.ID) (catalog.TableDescriptor, error) {
+			return getTableForStats(ctx, txn, col, id)
+		},
+	}
+}
+
+func getTableForStats(
+	ctx context.Context, txn *kv.Txn, col *descs.Collection, id descpb.ID,
+) (catalog.TableDescriptor, error) {
+	desc, err := col.GetImmutableDescriptorByID(ctx, txn, id, tree.CommonLookupFlags{
+		AvoidLeased:    true,
+		IncludeOffline: true,
+		IncludeDropped: true,
+	})
+	if err != nil {
+		// Descriptors created by the schema change don't exist yet.
+		if errors.Is(err, catalog.ErrDescriptorNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	table, _ := desc.(catalog.TableDescriptor)
+	return table, nil
+}
+
+// TableStats implements the scplan.TableStatsResolver interface. The average
+// size of a row is the sum of the average sizes of its columns, as of their
+// latest single-column statistics.
+func (r *schemaChangerTableStatsResolver) TableStats(
+	tableID descpb.ID,
+) (_ scplan.TableStats, ok bool, _ error) {
+	table, err := r.getTable(r.ctx, tableID)
+	if err != nil || table == nil {
+		return scplan.TableStats{}, false, err
+	}
+	stats, err := r.execCfg.TableStatsCache.GetTableStats(r.ctx, table)
+	if err != nil {
+		log.Warningf(r.ctx, "failed to read the statistics of table %d: %v", tableID, err)
+		return scplan.TableStats{}, false, nil
+	}
+	if len(stats) == 0 {
+		return scplan.TableStats{}, false, nil
+	}
+	ret := scplan.TableStats{RowCount: stats[0].RowCount}
+	var seen util.FastIntSet
+	for _, s := range stats {
+		if len(s.ColumnIDs) != 1 || seen.Contains(int(s.ColumnIDs[0])) {
+			continue
+		}
+		seen.Add(int(s.ColumnIDs[0]))
+		ret.AvgRowSize += s.AvgSize
+	}
+	return ret, true, nil
+}
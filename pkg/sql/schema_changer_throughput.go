@@ -0,0 +1,148 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scrun"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+const (
+	// throughputHistorySimilarityFactor bounds the ratio between the number
+	// of rows of the past stages from which the throughput of a stage is
+	// derived, and the number of rows of the stage.
+	throughputHistorySimilarityFactor = 4
+
+	// throughputHistorySampleSize is the maximum number of past stages from
+	// which the throughput of a stage is derived.
+	throughputHistorySampleSize = 10
+
+	// throughputHistoryMaxRecords is the number of records of each kind of
+	// stage retained in system.schema_change_throughput.
+	throughputHistoryMaxRecords = 100
+)
+
+// schemaChangerThroughputHistory implements scrun.ThroughputHistory using the
+// system.schema_change_throughput table. The history is best-effort: failing
+// to read it only leaves the stages of the plan without an estimated
+// duration.
+type schemaChangerThroughputHistory struct {
+	ctx     context.Context
+	execCfg *ExecutorConfig
+}
+
+var _ scrun.ThroughputHistory = (*schemaChangerThroughputHistory)(nil)
+
+// NewSchemaChangerThroughputHistory returns an scrun.ThroughputHistory for
+// the schema changer, which reads and writes the history in its own
+// transactions.
+func NewSchemaChangerThroughputHistory(
+	ctx context.Context, execCfg *ExecutorConfig,
+) scrun.ThroughputHistory {
+	return &schemaChangerThroughputHistory{ctx: ctx, execCfg: execCfg}
+}
+
+// throughputKind is the kind of the records of the stages of the given type.
+func throughputKind(stageType scop.Type) string {
+	return strings.ToLower(strings.TrimSuffix(stageType.String(), "Type"))
+}
+
+func (h *schemaChangerThroughputHistory) isActive(ctx context.Context) bool {
+	return h.execCfg.Settings.Version.IsActive(ctx, clusterversion.SystemSchemaChangeThroughputTable)
+}
+
+// Throughput implements the scplan.ThroughputHistory interface. The
+// throughput is the total number of rows over the total duration of the most
+// recent past stages of the same type whose number of rows is within a
+// constant factor of the given one.
+func (h *schemaChangerThroughputHistory) Throughput(
+	stageType scop.Type, rows uint64,
+) (rowsPerSecond float64, ok bool, _ error) {
+	ctx := h.ctx
+	if !h.isActive(ctx) {
+		return 0, false, nil
+	}
+	row, err := h.execCfg.InternalExecutor.QueryRowEx(
+		ctx, "schema-change-throughput", nil, /* txn */
+		sessiondata.InternalExecutorOverride{User: username.RootUserName()},
+		`SELECT sum(rows)::FLOAT8, sum(extract(epoch FROM duration))::FLOAT8
+   FROM (
+          SELECT rows, duration
+            FROM system.schema_change_throughput
+           WHERE kind = $1 AND rows BETWEEN $2 AND $3
+        ORDER BY completed DESC
+           LIMIT $4
+        )`,
+		throughputKind(stageType),
+		int64(rows/throughputHistorySimilarityFactor),
+		int64(rows*throughputHistorySimilarityFactor),
+		throughputHistorySampleSize,
+	)
+	if err != nil {
+		log.Warningf(ctx, "failed to read the throughput history of %s stages: %v", stageType, err)
+		return 0, false, nil
+	}
+	if row == nil || row[0] == tree.DNull || row[1] == tree.DNull {
+		return 0, false, nil
+	}
+	totalRows, totalSeconds := float64(tree.MustBeDFloat(row[0])), float64(tree.MustBeDFloat(row[1]))
+	if totalSeconds <= 0 {
+		return 0, false, nil
+	}
+	return totalRows / totalSeconds, true, nil
+}
+
+// RecordThroughput implements the scrun.ThroughputHistory interface. Only the
+// most recent records of each kind of stage are retained.
+func (h *schemaChangerThroughputHistory) RecordThroughput(
+	ctx context.Context, r scrun.ThroughputRecord,
+) error {
+	if !h.isActive(ctx) {
+		return nil
+	}
+	kind := throughputKind(r.StageType)
+	return h.execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		override := sessiondata.InternalExecutorOverride{User: username.RootUserName()}
+		if _, err := h.execCfg.InternalExecutor.ExecEx(
+			ctx, "record-schema-change-throughput", txn, override,
+			`UPSERT INTO system.schema_change_throughput (kind, job_id, stage, rows, bytes, duration)
+     VALUES ($1, $2, $3, $4, $5, $6::FLOAT8 * INTERVAL '1 second')`,
+			kind, int64(r.JobID), r.Stage, int64(r.Rows), int64(r.Bytes), r.Duration.Seconds(),
+		); err != nil {
+			return err
+		}
+		_, err := h.execCfg.InternalExecutor.ExecEx(
+			ctx, "prune-schema-change-throughput", txn, override,
+			`DELETE FROM system.schema_change_throughput
+      WHERE kind = $1
+        AND completed
+            <= (
+                SELECT completed
+                  FROM system.schema_change_throughput
+                 WHERE kind = $1
+              ORDER BY completed DESC
+                OFFSET $2
+                 LIMIT 1
+              )`,
+			kind, throughputHistoryMaxRecords,
+		)
+		return err
+	})
+}
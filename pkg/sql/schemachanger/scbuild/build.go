@@ -13,7 +13,9 @@ package scbuild
 import (
 	"context"
 	"runtime"
+	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
@@ -32,6 +34,18 @@ import (
 	"github.com/cockroachdb/errors"
 )
 
+// DroppedIndexGCTTL is the GC TTL applied to the indexes dropped by the
+// declarative schema changer. The override is removed along with the index
+// data once it has been garbage collected.
+var DroppedIndexGCTTL = settings.RegisterDurationSetting(
+	settings.TenantWritable,
+	"sql.schema.dropped_index_gc_ttl",
+	"if non-zero, the GC TTL applied to indexes dropped by the declarative "+
+		"schema changer instead of that of their table",
+	0,
+	settings.NonNegativeDuration,
+)
+
 // Build constructs a new state from an initial state and a statement.
 //
 // The function takes an AST for a DDL statement and constructs targets
@@ -78,6 +92,9 @@ func Build(
 				recErr,
 			)
 		}
+		if scerrors.HasNotImplemented(err) {
+			scerrors.SetFallbackDiagnostics(err, makeFallbackDiagnostics(bs, n))
+		}
 	}()
 	scbuildstmt.Process(b, an.GetStatement())
 	an.ValidateAnnotations()
@@ -87,6 +104,10 @@ func Build(
 		Targets:       make([]scpb.Target, 0, len(bs.output)),
 		Statements:    els.statements,
 		Authorization: els.authorization,
+		DroppedIndexGCTTLSeconds: int32(
+			DroppedIndexGCTTL.Get(&bs.clusterSettings.SV) / time.Second,
+		),
+		SessionData: els.sessionData,
 	}
 	current := make([]scpb.Status, 0, len(bs.output))
 	for _, e := range bs.output {
@@ -213,6 +234,9 @@ type eventLogState struct {
 	// authorization contains application and user names for the current session.
 	authorization scpb.Authorization
 
+	// sessionData is the snapshot of the current session.
+	sessionData scpb.SessionData
+
 	// statementMetaData is used to associate each element in the output to the
 	// statement which resulted in it being added there.
 	statementMetaData scpb.TargetMetadata
@@ -238,6 +262,12 @@ func newEventLogState(d Dependencies, initial scpb.CurrentState, n tree.Statemen
 			AppName:  d.SessionData().ApplicationName,
 			UserName: d.SessionData().SessionUser().Normalized(),
 		},
+		sessionData: scpb.SessionData{
+			UserName:        d.SessionData().SessionUser().Normalized(),
+			ApplicationName: d.SessionData().ApplicationName,
+			Database:        d.SessionData().Database,
+			SearchPath:      append([]string(nil), d.SessionData().SearchPath.GetPathArray()...),
+		},
 		sourceElementID: new(scpb.SourceElementID),
 		statementMetaData: scpb.TargetMetadata{
 			StatementID:     uint32(len(stmts)),
@@ -74,6 +74,16 @@ func (b *builderState) Ensure(
 		if current != scpb.Status_UNKNOWN {
 			es.current = current
 		}
+		if es.target == target && es.metadata.Size() > 0 && meta.Size() > 0 &&
+			es.metadata.StatementID != meta.StatementID {
+			// The same target was already set by another statement in the
+			// transaction. Keep the metadata of that statement, to which the
+			// events of the target remain attributed, and record that this
+			// statement produced the target as well.
+			merged := es.metadata
+			merged.MergeStatementIDs(meta)
+			meta = merged
+		}
 		es.target = target
 		es.element = e
 		es.metadata = meta
@@ -171,6 +181,17 @@ func (b *builderState) CurrentUserHasAdminOrIsMemberOf(role username.SQLUsername
 	return ok
 }
 
+// CheckRoleExists implements the scbuildstmt.PrivilegeChecker interface.
+func (b *builderState) CheckRoleExists(role username.SQLUsername) {
+	exists, err := b.auth.RoleExists(b.ctx, role)
+	if err != nil {
+		panic(err)
+	}
+	if !exists {
+		panic(pgerror.Newf(pgcode.UndefinedObject, "role/user %q does not exist", role))
+	}
+}
+
 var _ scbuildstmt.TableHelpers = (*builderState)(nil)
 
 // NextTableColumnID implements the scbuildstmt.TableHelpers interface.
@@ -161,6 +161,11 @@ func run(
 				expected := scerrors.NotImplementedError(nil)
 				require.Errorf(t, err, "%s: expected %T instead of success for", stmt.SQL, expected)
 				require.Truef(t, scerrors.HasNotImplemented(err), "%s: expected %T instead of %v", stmt.SQL, expected, err)
+				diagnostics, ok := scerrors.GetFallbackDiagnostics(err)
+				require.Truef(t, ok, "%s: expected fallback diagnostics for %v", stmt.SQL, err)
+				require.Equal(t, stmt.AST.StatementTag(), diagnostics.StatementTag)
+				require.NotEmpty(t, diagnostics.ASTShape)
+				require.NotEmpty(t, diagnostics.UnsupportedNode)
 			}
 		})
 		return ""
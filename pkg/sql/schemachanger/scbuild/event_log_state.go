@@ -34,6 +34,7 @@ func (e *eventLogState) EventLogStateWithNewSourceElementID() scbuildstmt.EventL
 	return &eventLogState{
 		statements:      e.statements,
 		authorization:   e.authorization,
+		sessionData:     e.sessionData,
 		sourceElementID: e.sourceElementID,
 		statementMetaData: scpb.TargetMetadata{
 			StatementID:     e.statementMetaData.StatementID,
@@ -0,0 +1,66 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scbuild
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scerrors"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// makeFallbackDiagnostics returns the diagnostics of the statement, which the
+// builder rejected as not implemented, see scerrors.SetFallbackDiagnostics.
+func makeFallbackDiagnostics(bs *builderState, n tree.Statement) scerrors.FallbackDiagnostics {
+	d := scerrors.FallbackDiagnostics{
+		StatementTag:    n.StatementTag(),
+		ASTShape:        astShape(n),
+		UnsupportedNode: scerrors.NodeTypeName(n),
+	}
+	ids := make([]catid.DescID, 0, len(bs.descCache))
+	for id, c := range bs.descCache {
+		if c.desc != nil {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for _, id := range ids {
+		desc := bs.descCache[id].desc
+		h := fnv.New64a()
+		var buf [12]byte
+		binary.BigEndian.PutUint32(buf[:4], uint32(id))
+		binary.BigEndian.PutUint64(buf[4:], uint64(desc.GetVersion()))
+		_, _ = h.Write(buf[:])
+		d.CatalogContext = append(d.CatalogContext,
+			fmt.Sprintf("%s:%016x", desc.DescriptorType(), h.Sum64()))
+	}
+	return d
+}
+
+// astShape returns the types of the nodes of the statement which determine
+// whether the builder supports it.
+func astShape(n tree.Statement) string {
+	switch t := n.(type) {
+	case *tree.AlterTable:
+		cmds := make([]string, len(t.Cmds))
+		for i, cmd := range t.Cmds {
+			cmds[i] = scerrors.NodeTypeName(cmd)
+		}
+		return fmt.Sprintf("%s(%s)", scerrors.NodeTypeName(n), strings.Join(cmds, ", "))
+	default:
+		return scerrors.NodeTypeName(n)
+	}
+}
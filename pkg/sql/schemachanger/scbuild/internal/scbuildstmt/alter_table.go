@@ -12,6 +12,7 @@ package scbuildstmt
 
 import (
 	"reflect"
+	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
@@ -56,6 +57,7 @@ var supportedAlterTableStatements = map[reflect.Type]supportedAlterTableCommand{
 		d, ok := t.ConstraintDef.(*tree.UniqueConstraintTableDef)
 		return ok && d.PrimaryKey && t.ValidationBehavior == tree.ValidationDefault
 	}, minSupportedClusterVersion: clusterversion.Start22_2},
+	reflect.TypeOf((*tree.AlterTableDropConstraint)(nil)): {fn: alterTableDropConstraint, on: true, minSupportedClusterVersion: clusterversion.Start22_2},
 }
 
 func init() {
@@ -92,6 +94,12 @@ func init() {
 	}
 }
 
+// alterTableCmdTag returns the tag identifying the ALTER TABLE command, e.g.
+// `ALTER TABLE ADD COLUMN`.
+func alterTableCmdTag(cmd tree.AlterTableCmd) string {
+	return "ALTER TABLE " + strings.ToUpper(strings.ReplaceAll(cmd.TelemetryName(), "_", " "))
+}
+
 // AlterTableIsSupported determines if the entire set of alter table commands
 // are supported.
 func alterTableIsSupported(n *tree.AlterTable, mode sessiondatapb.NewSchemaChangerMode) bool {
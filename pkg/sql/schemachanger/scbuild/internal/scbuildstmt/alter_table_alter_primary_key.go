@@ -73,7 +73,7 @@ func alterPrimaryKey(b BuildCtx, tn *tree.TableName, tbl *scpb.Table, t alterPri
 	fallBackIfShardedIndexExists(b, t, tbl.TableID)
 	fallBackIfRegionalByRowTable(b, t, tbl.TableID)
 	fallBackIfDescColInRowLevelTTLTables(b, tbl.TableID, t)
-	fallBackIfZoneConfigExists(b, t.n, tbl.TableID)
+	fallBackIfPartitionedTableHasZoneConfig(b, t, tbl.TableID)
 
 	// Retrieve old primary index and its name elements.
 	oldPrimaryIndexElem, newPrimaryIndexElem := getPrimaryIndexes(b, tbl.TableID)
@@ -135,11 +135,16 @@ func alterPrimaryKey(b BuildCtx, tn *tree.TableName, tbl *scpb.Table, t alterPri
 	}
 	out.apply(b.Drop)
 	sharding := makeShardedDescriptor(b, t)
+	// Carry over any zone configuration of the old primary index to the new
+	// one when it becomes public.
+	_, _, zoneConfigElem := scpb.FindTableZoneConfig(b.QueryByID(tbl.TableID))
+	inheritsZoneConfig := zoneConfigElem != nil
 	var sourcePrimaryIndexElem *scpb.PrimaryIndex
 	if rowidToDrop == nil {
 		// We're NOT dropping the rowid column => do one primary index swap.
 		in, tempIn := makeSwapIndexSpec(b, out, out.primary.IndexID, inColumns)
 		in.primary.Sharding = sharding
+		in.primary.InheritsSourceZoneConfig = inheritsZoneConfig
 		if t.Name != "" {
 			in.name.Name = string(t.Name)
 		}
@@ -156,11 +161,13 @@ func alterPrimaryKey(b BuildCtx, tn *tree.TableName, tbl *scpb.Table, t alterPri
 		// Swap once to the new PK but storing rowid.
 		union, tempUnion := makeSwapIndexSpec(b, out, out.primary.IndexID, unionColumns)
 		union.primary.Sharding = protoutil.Clone(sharding).(*catpb.ShardedDescriptor)
+		union.primary.InheritsSourceZoneConfig = inheritsZoneConfig
 		union.apply(b.AddTransient)
 		tempUnion.apply(b.AddTransient)
 		// Swap again to the final primary index: same PK but NOT storing rowid.
 		in, tempIn := makeSwapIndexSpec(b, union, union.primary.IndexID, inColumns)
 		in.primary.Sharding = sharding
+		in.primary.InheritsSourceZoneConfig = inheritsZoneConfig
 		if t.Name != "" {
 			in.name.Name = string(t.Name)
 		}
@@ -374,6 +381,23 @@ func fallBackIfRegionalByRowTable(b BuildCtx, t alterPrimaryKeySpec, tableID cat
 	}
 }
 
+// fallBackIfPartitionedTableHasZoneConfig panics with an unimplemented
+// error if the table has a zone config and partitioned indexes, because
+// the subzone spans of the partitions cannot be carried over to a new
+// primary index with different key columns.
+func fallBackIfPartitionedTableHasZoneConfig(
+	b BuildCtx, t alterPrimaryKeySpec, tableID catid.DescID,
+) {
+	tableElts := b.QueryByID(tableID)
+	if _, _, elem := scpb.FindTableZoneConfig(tableElts); elem == nil {
+		return
+	}
+	if _, _, elem := scpb.FindIndexPartitioning(tableElts); elem != nil {
+		panic(scerrors.NotImplementedErrorf(t.n, "ALTER PRIMARY KEY on a partitioned table "+
+			"with a zone configuration is not yet supported."))
+	}
+}
+
 // fallBackIfDescColInRowLevelTTLTables panics with an unimplemented
 // error if the table is a (row-level-ttl table && (it has a descending
 // key column || it has any inbound/outbound FK constraint)).
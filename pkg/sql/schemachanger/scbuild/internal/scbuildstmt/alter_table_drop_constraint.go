@@ -0,0 +1,81 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scbuildstmt
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scerrors"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
+)
+
+// alterTableDropConstraint implements ALTER TABLE ... DROP CONSTRAINT.
+//
+// Each DROP CONSTRAINT command in an ALTER TABLE statement drops the elements
+// of one constraint, so that dropping several constraints in one statement
+// results in a single schema change job. A constraint dropped by an earlier
+// command in the same statement or transaction is considered to no longer
+// exist, as in the legacy schema changer.
+//
+// Only CHECK and FOREIGN KEY constraints are supported at this point; dropping
+// any other kind of constraint falls back to the legacy schema changer.
+func alterTableDropConstraint(
+	b BuildCtx, tn *tree.TableName, tbl *scpb.Table, t *tree.AlterTableDropConstraint,
+) {
+	elts := b.ResolveConstraint(tbl.TableID, t.Constraint, ResolveParams{
+		IsExistenceOptional: t.IfExists,
+		RequiredPrivilege:   privilege.CREATE,
+	})
+	if elts == nil {
+		return
+	}
+	var constraint scpb.Element
+	var current scpb.Status
+	var target scpb.TargetStatus
+	elts.ForEachElementStatus(func(status scpb.Status, ts scpb.TargetStatus, e scpb.Element) {
+		switch e.(type) {
+		case *scpb.CheckConstraint, *scpb.ForeignKeyConstraint, *scpb.UniqueWithoutIndexConstraint,
+			*scpb.PrimaryIndex, *scpb.SecondaryIndex:
+			constraint, current, target = e, status, ts
+		}
+	})
+	if constraint == nil {
+		panic(errors.AssertionFailedf("failed to find constraint %q in %v which was already resolved",
+			t.Constraint, tn))
+	}
+	if target == scpb.ToAbsent {
+		if t.IfExists {
+			return
+		}
+		panic(pgerror.Newf(pgcode.UndefinedObject,
+			"constraint %q of relation %q does not exist", t.Constraint, tn.Object()))
+	}
+	switch constraint.(type) {
+	case *scpb.CheckConstraint, *scpb.ForeignKeyConstraint:
+	default:
+		// Dropping UNIQUE WITHOUT INDEX constraints requires checking for
+		// dependent foreign keys, and dropping those backed by an index is
+		// either disallowed or amounts to altering the primary key.
+		panic(scerrors.NotImplementedError(t))
+	}
+	if current != scpb.Status_PUBLIC {
+		panic(pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+			"constraint %q in the middle of being added, try again later", t.Constraint))
+	}
+	elts.ForEachElementStatus(func(_ scpb.Status, target scpb.TargetStatus, e scpb.Element) {
+		if target != scpb.ToAbsent {
+			b.Drop(e)
+		}
+	})
+}
@@ -181,6 +181,9 @@ type PrivilegeChecker interface {
 	// CurrentUserHasAdminOrIsMemberOf returns true iff the current user is (1)
 	// an admin or (2) has membership in the specified role.
 	CurrentUserHasAdminOrIsMemberOf(member username.SQLUsername) bool
+
+	// CheckRoleExists panics if the specified role does not exist.
+	CheckRoleExists(role username.SQLUsername)
 }
 
 // TableHelpers has methods useful for creating new table elements.
@@ -91,6 +91,11 @@ func DropOwnedBy(b BuildCtx, n *tree.DropOwnedBy) {
 					b.Drop(e)
 				}
 			})
+			scpb.ForEachColumnPrivileges(elts, func(_ scpb.Status, _ scpb.TargetStatus, e *scpb.ColumnPrivileges) {
+				if e.UserName == role.Normalized() {
+					b.Drop(e)
+				}
+			})
 		}
 	}
 
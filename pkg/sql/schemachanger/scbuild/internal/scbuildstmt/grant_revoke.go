@@ -0,0 +1,167 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scbuildstmt
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/decodeusername"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
+)
+
+// Grant implements GRANT <privileges> (<columns>) ON TABLE <tables> TO
+// <grantees>, which grants privileges on the columns of tables.
+func Grant(b BuildCtx, n *tree.Grant) {
+	if n.WithGrantOption {
+		panic(pgerror.New(pgcode.FeatureNotSupported,
+			"column privileges cannot be granted with the grant option"))
+	}
+	changeColumnPrivileges(b, n.Privileges, n.Columns, &n.Targets, n.Grantees,
+		func(old, privileges uint32) uint32 { return old | privileges },
+	)
+}
+
+// Revoke implements REVOKE <privileges> (<columns>) ON TABLE <tables> FROM
+// <grantees>, which revokes privileges on the columns of tables.
+func Revoke(b BuildCtx, n *tree.Revoke) {
+	if n.GrantOptionFor {
+		panic(pgerror.New(pgcode.FeatureNotSupported,
+			"column privileges cannot be granted with the grant option"))
+	}
+	changeColumnPrivileges(b, n.Privileges, n.Columns, &n.Targets, n.Grantees,
+		func(old, privileges uint32) uint32 { return old &^ privileges },
+	)
+}
+
+// grantIsSupported returns true for the GRANT statements which grant
+// privileges on columns, the others are not implemented.
+func grantIsSupported(n *tree.Grant, _ sessiondatapb.NewSchemaChangerMode) bool {
+	return n.Columns != nil
+}
+
+// revokeIsSupported returns true for the REVOKE statements which revoke
+// privileges on columns, the others are not implemented.
+func revokeIsSupported(n *tree.Revoke, _ sessiondatapb.NewSchemaChangerMode) bool {
+	return n.Columns != nil
+}
+
+// changeColumnPrivileges replaces the privileges of the grantees on the
+// columns of the target tables by the result of the change function, which is
+// passed their current privileges and the privileges in the statement.
+func changeColumnPrivileges(
+	b BuildCtx,
+	privileges privilege.List,
+	columns tree.NameList,
+	targets *tree.GrantTargetList,
+	grantees tree.RoleSpecList,
+	change func(old, privileges uint32) uint32,
+) {
+	if targets.Databases != nil || targets.Schemas != nil || targets.Types != nil ||
+		targets.Functions != nil || targets.ExternalConnections != nil || targets.System ||
+		targets.Tables.SequenceOnly || len(targets.Tables.TablePatterns) == 0 {
+		panic(pgerror.New(pgcode.InvalidGrantOperation,
+			"column privileges can only be granted on tables"))
+	}
+	bits := columnPrivilegesToBitField(privileges)
+	roles, err := decodeusername.FromRoleSpecList(
+		b.SessionData(), username.PurposeValidation, grantees,
+	)
+	if err != nil {
+		panic(err)
+	}
+	for _, role := range roles {
+		if !role.IsPublicRole() {
+			b.CheckRoleExists(role)
+		}
+	}
+	for i, pattern := range targets.Tables.TablePatterns {
+		tp, err := pattern.NormalizeTablePattern()
+		if err != nil {
+			panic(err)
+		}
+		tn, ok := tp.(*tree.TableName)
+		if !ok {
+			panic(pgerror.New(pgcode.InvalidGrantOperation,
+				"column privileges can only be granted on named tables"))
+		}
+		tableElts := b.ResolveTable(tn.ToUnresolvedObjectName(), ResolveParams{
+			IsExistenceOptional: false,
+			RequiredPrivilege:   privilege.SELECT,
+		})
+		_, _, tbl := scpb.FindTable(tableElts)
+		if !b.HasOwnership(tbl) {
+			panic(pgerror.Newf(pgcode.InsufficientPrivilege,
+				"must be owner of table %s to change its column privileges", tn.Object()))
+		}
+		tn.ObjectNamePrefix = b.NamePrefix(tbl)
+		targets.Tables.TablePatterns[i] = tn
+		for _, name := range columns {
+			columnElts := b.ResolveColumn(tbl.TableID, name, ResolveParams{
+				IsExistenceOptional: false,
+				RequiredPrivilege:   privilege.SELECT,
+			})
+			_, _, col := scpb.FindColumn(columnElts)
+			if col.IsSystemColumn {
+				panic(pgerror.Newf(pgcode.InvalidGrantOperation,
+					"cannot change the privileges of system column %q", name))
+			}
+			for _, role := range roles {
+				var old *scpb.ColumnPrivileges
+				scpb.ForEachColumnPrivileges(columnElts.Filter(publicTargetFilter), func(
+					_ scpb.Status, _ scpb.TargetStatus, e *scpb.ColumnPrivileges,
+				) {
+					if e.UserName == role.Normalized() {
+						old = e
+					}
+				})
+				var oldBits uint32
+				if old != nil {
+					oldBits = old.Privileges
+				}
+				newBits := change(oldBits, bits)
+				if newBits == oldBits {
+					continue
+				}
+				if old != nil {
+					b.Drop(old)
+				}
+				if newBits != 0 {
+					b.Add(&scpb.ColumnPrivileges{
+						TableID:    tbl.TableID,
+						ColumnID:   col.ColumnID,
+						UserName:   role.Normalized(),
+						Privileges: newBits,
+					})
+				}
+			}
+		}
+	}
+}
+
+// columnPrivilegesToBitField returns the bitfield of the privileges in the
+// statement, which must be privileges which can be granted on columns. ALL
+// stands for all of them.
+func columnPrivilegesToBitField(privileges privilege.List) uint32 {
+	if privileges.Contains(privilege.ALL) {
+		return privilege.ColumnPrivileges.ToBitField()
+	}
+	for _, p := range privileges {
+		if !privilege.ColumnPrivileges.Contains(p) {
+			panic(pgerror.Newf(pgcode.InvalidGrantOperation,
+				"invalid privilege type %s for column", p))
+		}
+	}
+	return privileges.ToBitField()
+}
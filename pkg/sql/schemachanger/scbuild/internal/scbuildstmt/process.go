@@ -12,6 +12,7 @@ package scbuildstmt
 
 import (
 	"reflect"
+	"sort"
 
 	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scerrors"
@@ -83,6 +84,8 @@ var supportedStatements = map[reflect.Type]supportedStatement{
 	reflect.TypeOf((*tree.CommentOnColumn)(nil)):     {fn: CommentOnColumn, on: true, minSupportedClusterVersion: clusterversion.Start22_2},
 	reflect.TypeOf((*tree.CommentOnIndex)(nil)):      {fn: CommentOnIndex, on: true, minSupportedClusterVersion: clusterversion.Start22_2},
 	reflect.TypeOf((*tree.CommentOnConstraint)(nil)): {fn: CommentOnConstraint, on: true, minSupportedClusterVersion: clusterversion.Start22_2},
+	reflect.TypeOf((*tree.Grant)(nil)):               {fn: Grant, on: true, extraChecks: grantIsSupported, minSupportedClusterVersion: clusterversion.Start22_2},
+	reflect.TypeOf((*tree.Revoke)(nil)):              {fn: Revoke, on: true, extraChecks: revokeIsSupported, minSupportedClusterVersion: clusterversion.Start22_2},
 	// TODO (Xiang): turn on `DROP INDEX` as fully supported.
 	reflect.TypeOf((*tree.DropIndex)(nil)): {fn: DropIndex, on: false, minSupportedClusterVersion: clusterversion.Start22_2},
 }
@@ -135,6 +138,33 @@ func CheckIfStmtIsSupported(n tree.Statement, mode sessiondatapb.NewSchemaChange
 	return isFullySupported(n, info.on, info.extraChecks, mode)
 }
 
+// StatementTags returns the tags by which the declarative schema changer
+// support for the statement can be referred to: its statement tag and, for
+// ALTER TABLE, the tags of each of its commands, e.g. `ALTER TABLE ADD COLUMN`.
+func StatementTags(n tree.Statement) []string {
+	tags := []string{n.StatementTag()}
+	if at, ok := n.(*tree.AlterTable); ok {
+		for _, cmd := range at.Cmds {
+			tags = append(tags, alterTableCmdTag(cmd))
+		}
+	}
+	return tags
+}
+
+// SupportedStatementTags returns the tags of all the statements and ALTER
+// TABLE commands implemented by the declarative schema changer, sorted.
+func SupportedStatementTags() []string {
+	var tags []string
+	for t := range supportedStatements {
+		tags = append(tags, reflect.New(t.Elem()).Interface().(tree.Statement).StatementTag())
+	}
+	for t := range supportedAlterTableStatements {
+		tags = append(tags, alterTableCmdTag(reflect.New(t.Elem()).Interface().(tree.AlterTableCmd)))
+	}
+	sort.Strings(tags)
+	return tags
+}
+
 // Process dispatches on the statement type to populate the BuilderState
 // embedded in the BuildCtx. Any error will be panicked.
 func Process(b BuildCtx, n tree.Statement) {
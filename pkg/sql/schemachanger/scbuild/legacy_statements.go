@@ -0,0 +1,83 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scbuild
+
+import (
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scbuild/internal/scbuildstmt"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
+	"github.com/cockroachdb/errors"
+)
+
+// forceLegacyStatements lists the statements which are planned by the legacy
+// schema changer even when the declarative schema changer supports them. This
+// is meant as a targeted escape hatch when a bug is found in the declarative
+// planning of a particular statement.
+var forceLegacyStatements = settings.RegisterValidatedStringSetting(
+	settings.TenantWritable,
+	"sql.schema.force_legacy_schema_changer_statements",
+	"comma-separated list of statement tags, e.g. `DROP INDEX` or "+
+		"`ALTER TABLE ADD COLUMN`, which are never planned by the declarative "+
+		"schema changer",
+	"",
+	validateForceLegacyStatements,
+)
+
+func validateForceLegacyStatements(_ *settings.Values, s string) error {
+	supported := make(map[string]struct{})
+	for _, tag := range scbuildstmt.SupportedStatementTags() {
+		supported[tag] = struct{}{}
+	}
+	for tag := range parseStatementTags(s) {
+		if _, ok := supported[tag]; !ok {
+			return errors.WithHintf(
+				errors.Newf("statement %q is not supported by the declarative schema changer", tag),
+				"Supported statements: %s", strings.Join(scbuildstmt.SupportedStatementTags(), ", "),
+			)
+		}
+	}
+	return nil
+}
+
+// parseStatementTags parses the comma-separated list of statement tags,
+// normalizing case and whitespace.
+func parseStatementTags(s string) map[string]struct{} {
+	tags := make(map[string]struct{})
+	for _, tag := range strings.Split(s, ",") {
+		if tag = strings.Join(strings.Fields(strings.ToUpper(tag)), " "); tag != "" {
+			tags[tag] = struct{}{}
+		}
+	}
+	return tags
+}
+
+// ForcedLegacyStatementTag returns the tag listed in the
+// sql.schema.force_legacy_schema_changer_statements cluster setting which
+// matches the statement, if the declarative schema changer would otherwise
+// attempt to plan it.
+func ForcedLegacyStatementTag(
+	sv *settings.Values, n tree.Statement, mode sessiondatapb.NewSchemaChangerMode,
+) (tag string, ok bool) {
+	s := forceLegacyStatements.Get(sv)
+	if s == "" || !scbuildstmt.CheckIfStmtIsSupported(n, mode) {
+		return "", false
+	}
+	forced := parseStatementTags(s)
+	for _, tag := range scbuildstmt.StatementTags(n) {
+		if _, ok := forced[tag]; ok {
+			return tag, true
+		}
+	}
+	return "", false
+}
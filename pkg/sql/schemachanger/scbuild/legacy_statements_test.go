@@ -0,0 +1,76 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scbuild
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForcedLegacyStatementTag(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+
+	require.NoError(t, validateForceLegacyStatements(nil, "drop  index, ALTER TABLE add column"))
+	require.Error(t, validateForceLegacyStatements(nil, "CREATE TABLE"))
+	require.Error(t, validateForceLegacyStatements(nil, "ALTER TABLE RENAME COLUMN"))
+
+	forceLegacyStatements.Override(ctx, &st.SV, "drop  index, ALTER TABLE add column")
+	for _, tc := range []struct {
+		sql    string
+		mode   sessiondatapb.NewSchemaChangerMode
+		tag    string
+		forced bool
+	}{
+		{
+			sql:    "ALTER TABLE t ADD COLUMN b INT",
+			mode:   sessiondatapb.UseNewSchemaChangerOn,
+			tag:    "ALTER TABLE ADD COLUMN",
+			forced: true,
+		},
+		{
+			sql:  "ALTER TABLE t DROP COLUMN b",
+			mode: sessiondatapb.UseNewSchemaChangerOn,
+		},
+		{
+			sql:    "DROP INDEX t@idx",
+			mode:   sessiondatapb.UseNewSchemaChangerUnsafe,
+			tag:    "DROP INDEX",
+			forced: true,
+		},
+		{
+			// DROP INDEX is not planned by the declarative schema changer by
+			// default anyway.
+			sql:  "DROP INDEX t@idx",
+			mode: sessiondatapb.UseNewSchemaChangerOn,
+		},
+		{
+			sql:  "DROP TABLE t",
+			mode: sessiondatapb.UseNewSchemaChangerOn,
+		},
+	} {
+		t.Run(tc.sql, func(t *testing.T) {
+			stmt, err := parser.ParseOne(tc.sql)
+			require.NoError(t, err)
+			tag, forced := ForcedLegacyStatementTag(&st.SV, stmt.AST, tc.mode)
+			require.Equal(t, tc.forced, forced)
+			require.Equal(t, tc.tag, tag)
+		})
+	}
+}
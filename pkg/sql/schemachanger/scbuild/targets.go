@@ -0,0 +1,382 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scbuild
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/config/zonepb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/faketreeeval"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scerrors"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/redact"
+)
+
+// TargetsDependencies are the dependencies of BuildTargets. They are the
+// sanctioned subset of Dependencies which internal tools must provide to
+// build DDL statements into targets, without going through SQL.
+//
+// The fields which are not required may be left unset, in which case
+// BuildTargets falls back to the behavior documented for each of them. New
+// fields are only ever added with such a fallback, so that callers keep on
+// working as this struct evolves.
+type TargetsDependencies struct {
+	// Settings are the cluster settings. Required.
+	Settings *cluster.Settings
+
+	// SessionData is the data of the session on behalf of which the statement
+	// is built, which notably determines the current database and the search
+	// path. Required.
+	SessionData *sessiondata.SessionData
+
+	// CatalogReader resolves the objects referenced by the statement, subject
+	// to the contract of the CatalogReader interface. Required.
+	CatalogReader CatalogReader
+
+	// ClusterID is the ID of the cluster, which determines whether the CCL
+	// features are enabled.
+	ClusterID uuid.UUID
+
+	// TenantID is the ID of the tenant in which the statement is built. It
+	// defaults to the system tenant.
+	TenantID roachpb.TenantID
+
+	// AuthorizationAccessor checks the privileges of the user of the session.
+	// When unset, the statement is built on behalf of the node, as if by a
+	// member of the admin role.
+	AuthorizationAccessor AuthorizationAccessor
+
+	// TableReader inspects the contents of the tables. When unset, tables are
+	// assumed to be non-empty.
+	TableReader TableReader
+
+	// IndexPartitioningCCLCallback creates the partitioning descriptors of
+	// indexes. When unset, statements which partition indexes fail.
+	IndexPartitioningCCLCallback CreatePartitioningCCLCallback
+
+	// CommentCache provides the comments of the descriptors. When unset, the
+	// descriptors are assumed not to have any.
+	CommentCache CommentCache
+
+	// ZoneConfigGetter provides the zone configs of the descriptors. When
+	// unset, the descriptors are assumed not to have any.
+	ZoneConfigGetter ZoneConfigGetter
+}
+
+// BuildTargets builds the given DDL statement into the targets of the schema
+// change which it performs. It is the supported entry point for tools which
+// leverage the declarative schema changer, e.g. to build statements derived
+// from existing descriptors, without constructing SQL strings.
+//
+// The statement must be fully supported by the declarative schema changer. No
+// telemetry is recorded and no notices are sent for it.
+func BuildTargets(
+	ctx context.Context, deps TargetsDependencies, stmt tree.Statement,
+) ([]scpb.Target, error) {
+	switch {
+	case deps.Settings == nil:
+		return nil, errors.AssertionFailedf("missing cluster settings")
+	case deps.SessionData == nil:
+		return nil, errors.AssertionFailedf("missing session data")
+	case deps.CatalogReader == nil:
+		return nil, errors.AssertionFailedf("missing catalog reader")
+	}
+	if !CheckIfSupported(stmt) {
+		return nil, scerrors.NotImplementedError(stmt)
+	}
+	state, err := Build(ctx, targetsDeps{deps: deps, stmt: stmt}, scpb.CurrentState{}, stmt)
+	if err != nil {
+		return nil, err
+	}
+	return state.Targets, nil
+}
+
+// targetsDeps implements Dependencies for BuildTargets.
+type targetsDeps struct {
+	deps TargetsDependencies
+	stmt tree.Statement
+}
+
+var _ Dependencies = targetsDeps{}
+
+// ClusterSettings implements the Dependencies interface.
+func (d targetsDeps) ClusterSettings() *cluster.Settings {
+	return d.deps.Settings
+}
+
+// SessionData implements the Dependencies interface.
+func (d targetsDeps) SessionData() *sessiondata.SessionData {
+	return d.deps.SessionData
+}
+
+// CatalogReader implements the Dependencies interface.
+func (d targetsDeps) CatalogReader() CatalogReader {
+	return d.deps.CatalogReader
+}
+
+// TableReader implements the Dependencies interface.
+func (d targetsDeps) TableReader() TableReader {
+	if d.deps.TableReader != nil {
+		return d.deps.TableReader
+	}
+	return nonEmptyTableReader{}
+}
+
+// AuthorizationAccessor implements the Dependencies interface.
+func (d targetsDeps) AuthorizationAccessor() AuthorizationAccessor {
+	if d.deps.AuthorizationAccessor != nil {
+		return d.deps.AuthorizationAccessor
+	}
+	return nodeAuthorizationAccessor{}
+}
+
+// ClusterID implements the Dependencies interface.
+func (d targetsDeps) ClusterID() uuid.UUID {
+	return d.deps.ClusterID
+}
+
+// Codec implements the Dependencies interface.
+func (d targetsDeps) Codec() keys.SQLCodec {
+	if d.deps.TenantID.IsSet() {
+		return keys.MakeSQLCodec(d.deps.TenantID)
+	}
+	return keys.SystemSQLCodec
+}
+
+// Statements implements the Dependencies interface.
+func (d targetsDeps) Statements() []string {
+	return []string{tree.AsString(d.stmt)}
+}
+
+// AstFormatter implements the Dependencies interface.
+func (d targetsDeps) AstFormatter() AstFormatter {
+	return targetsAstFormatter{}
+}
+
+// FeatureChecker implements the Dependencies interface.
+func (d targetsDeps) FeatureChecker() FeatureChecker {
+	return allowAllFeatureChecker{}
+}
+
+// IndexPartitioningCCLCallback implements the Dependencies interface.
+func (d targetsDeps) IndexPartitioningCCLCallback() CreatePartitioningCCLCallback {
+	if d.deps.IndexPartitioningCCLCallback != nil {
+		return d.deps.IndexPartitioningCCLCallback
+	}
+	return func(
+		context.Context,
+		*cluster.Settings,
+		*eval.Context,
+		func(tree.Name) (catalog.Column, error),
+		int,
+		[]string,
+		*tree.PartitionBy,
+		[]tree.Name,
+		bool,
+	) ([]catalog.Column, catpb.PartitioningDescriptor, error) {
+		return nil, catpb.PartitioningDescriptor{}, errors.New("index partitioning is not supported")
+	}
+}
+
+// DescriptorCommentCache implements the Dependencies interface.
+func (d targetsDeps) DescriptorCommentCache() CommentCache {
+	if d.deps.CommentCache != nil {
+		return d.deps.CommentCache
+	}
+	return noCommentCache{}
+}
+
+// ZoneConfigGetter implements the Dependencies interface.
+func (d targetsDeps) ZoneConfigGetter() ZoneConfigGetter {
+	if d.deps.ZoneConfigGetter != nil {
+		return d.deps.ZoneConfigGetter
+	}
+	return noZoneConfigGetter{}
+}
+
+// ClientNoticeSender implements the Dependencies interface.
+func (d targetsDeps) ClientNoticeSender() eval.ClientNoticeSender {
+	return &faketreeeval.DummyClientNoticeSender{}
+}
+
+// IncrementSchemaChangeAlterCounter implements the Dependencies interface.
+func (d targetsDeps) IncrementSchemaChangeAlterCounter(string, ...string) {}
+
+// IncrementSchemaChangeDropCounter implements the Dependencies interface.
+func (d targetsDeps) IncrementSchemaChangeDropCounter(string) {}
+
+// IncrementSchemaChangeAddColumnTypeCounter implements the Dependencies
+// interface.
+func (d targetsDeps) IncrementSchemaChangeAddColumnTypeCounter(string) {}
+
+// IncrementSchemaChangeAddColumnQualificationCounter implements the
+// Dependencies interface.
+func (d targetsDeps) IncrementSchemaChangeAddColumnQualificationCounter(string) {}
+
+// IncrementUserDefinedSchemaCounter implements the Dependencies interface.
+func (d targetsDeps) IncrementUserDefinedSchemaCounter(sqltelemetry.UserDefinedSchemaTelemetryType) {
+}
+
+// IncrementEnumCounter implements the Dependencies interface.
+func (d targetsDeps) IncrementEnumCounter(sqltelemetry.EnumTelemetryType) {}
+
+// IncrementDropOwnedByCounter implements the Dependencies interface.
+func (d targetsDeps) IncrementDropOwnedByCounter() {}
+
+// nonEmptyTableReader is the TableReader of BuildTargets when none is
+// provided.
+type nonEmptyTableReader struct{}
+
+// IsTableEmpty implements the TableReader interface.
+func (nonEmptyTableReader) IsTableEmpty(context.Context, descpb.ID, descpb.IndexID) bool {
+	return false
+}
+
+// nodeAuthorizationAccessor is the AuthorizationAccessor of BuildTargets when
+// none is provided: all privileges are granted.
+type nodeAuthorizationAccessor struct{}
+
+// CheckPrivilege implements the AuthorizationAccessor interface.
+func (nodeAuthorizationAccessor) CheckPrivilege(
+	context.Context, catalog.PrivilegeObject, privilege.Kind,
+) error {
+	return nil
+}
+
+// HasAdminRole implements the AuthorizationAccessor interface.
+func (nodeAuthorizationAccessor) HasAdminRole(context.Context) (bool, error) {
+	return true, nil
+}
+
+// HasOwnership implements the AuthorizationAccessor interface.
+func (nodeAuthorizationAccessor) HasOwnership(
+	context.Context, catalog.PrivilegeObject,
+) (bool, error) {
+	return true, nil
+}
+
+// CheckPrivilegeForUser implements the AuthorizationAccessor interface.
+func (nodeAuthorizationAccessor) CheckPrivilegeForUser(
+	context.Context, catalog.PrivilegeObject, privilege.Kind, username.SQLUsername,
+) error {
+	return nil
+}
+
+// MemberOfWithAdminOption implements the AuthorizationAccessor interface.
+func (nodeAuthorizationAccessor) MemberOfWithAdminOption(
+	context.Context, username.SQLUsername,
+) (map[username.SQLUsername]bool, error) {
+	return map[username.SQLUsername]bool{username.AdminRoleName(): true}, nil
+}
+
+// RoleExists implements the AuthorizationAccessor interface.
+func (nodeAuthorizationAccessor) RoleExists(context.Context, username.SQLUsername) (bool, error) {
+	return true, nil
+}
+
+// targetsAstFormatter is the AstFormatter of BuildTargets.
+type targetsAstFormatter struct{}
+
+// FormatAstAsRedactableString implements the AstFormatter interface.
+func (targetsAstFormatter) FormatAstAsRedactableString(
+	statement tree.Statement, annotations *tree.Annotations,
+) redact.RedactableString {
+	f := tree.NewFmtCtx(
+		tree.FmtAlwaysQualifyTableNames|tree.FmtMarkRedactionNode,
+		tree.FmtAnnotations(annotations),
+	)
+	f.FormatNode(statement)
+	return redact.RedactableString(f.CloseAndGetString())
+}
+
+// allowAllFeatureChecker is the FeatureChecker of BuildTargets: the feature
+// flags only apply to the statements issued by users.
+type allowAllFeatureChecker struct{}
+
+// CheckFeature implements the FeatureChecker interface.
+func (allowAllFeatureChecker) CheckFeature(context.Context, tree.SchemaFeatureName) error {
+	return nil
+}
+
+// CanPerformDropOwnedBy implements the FeatureChecker interface.
+func (allowAllFeatureChecker) CanPerformDropOwnedBy(
+	context.Context, username.SQLUsername,
+) (bool, error) {
+	return true, nil
+}
+
+// noCommentCache is the CommentCache of BuildTargets when none is provided.
+type noCommentCache struct{}
+
+// LoadCommentsForObjects implements the CommentCache interface.
+func (noCommentCache) LoadCommentsForObjects(context.Context, []descpb.ID) error {
+	return nil
+}
+
+// GetDatabaseComment implements the CommentCache interface.
+func (noCommentCache) GetDatabaseComment(context.Context, catid.DescID) (string, bool, error) {
+	return "", false, nil
+}
+
+// GetSchemaComment implements the CommentCache interface.
+func (noCommentCache) GetSchemaComment(context.Context, catid.DescID) (string, bool, error) {
+	return "", false, nil
+}
+
+// GetTableComment implements the CommentCache interface.
+func (noCommentCache) GetTableComment(context.Context, catid.DescID) (string, bool, error) {
+	return "", false, nil
+}
+
+// GetColumnComment implements the CommentCache interface.
+func (noCommentCache) GetColumnComment(
+	context.Context, catid.DescID, catid.PGAttributeNum,
+) (string, bool, error) {
+	return "", false, nil
+}
+
+// GetIndexComment implements the CommentCache interface.
+func (noCommentCache) GetIndexComment(
+	context.Context, catid.DescID, catid.IndexID,
+) (string, bool, error) {
+	return "", false, nil
+}
+
+// GetConstraintComment implements the CommentCache interface.
+func (noCommentCache) GetConstraintComment(
+	context.Context, catid.DescID, catid.ConstraintID,
+) (string, bool, error) {
+	return "", false, nil
+}
+
+// noZoneConfigGetter is the ZoneConfigGetter of BuildTargets when none is
+// provided.
+type noZoneConfigGetter struct{}
+
+// GetZoneConfig implements the ZoneConfigGetter interface.
+func (noZoneConfigGetter) GetZoneConfig(context.Context, descpb.ID) (*zonepb.ZoneConfig, error) {
+	return nil, nil
+}
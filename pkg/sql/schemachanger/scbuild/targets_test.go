@@ -0,0 +1,87 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scbuild_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scbuild"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scdeps/sctestdeps"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scerrors"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildTargets(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, sqlDB, _ := serverutils.StartServer(t, base.TestServerArgs{
+		DisableDefaultTestTenant: true,
+	})
+	defer s.Stopper().Stop(ctx)
+	tdb := sqlutils.MakeSQLRunner(sqlDB)
+	tdb.Exec(t, `CREATE TABLE defaultdb.foo (i INT PRIMARY KEY)`)
+
+	// The catalog state is read from the test server, which is not otherwise
+	// involved in building the targets.
+	td := sctestdeps.NewTestDependencies(
+		sctestdeps.WithDescriptors(sctestdeps.ReadDescriptorsFromDB(ctx, t, tdb).Catalog),
+		sctestdeps.WithNamespace(sctestdeps.ReadNamespaceFromDB(t, tdb).Catalog),
+		sctestdeps.WithCurrentDatabase(sctestdeps.ReadCurrentDatabaseFromDB(t, tdb)),
+		sctestdeps.WithSessionData(sctestdeps.ReadSessionDataFromDB(t, tdb, func(*sessiondata.SessionData) {})),
+	)
+	deps := scbuild.TargetsDependencies{
+		Settings:      td.ClusterSettings(),
+		SessionData:   td.SessionData(),
+		CatalogReader: td.CatalogReader(),
+	}
+	buildTargets := func(sql string) ([]scpb.Target, error) {
+		stmt, err := parser.ParseOne(sql)
+		require.NoError(t, err)
+		return scbuild.BuildTargets(ctx, deps, stmt.AST)
+	}
+
+	t.Run("missing dependencies", func(t *testing.T) {
+		stmt, err := parser.ParseOne(`ALTER TABLE defaultdb.foo ADD COLUMN j INT`)
+		require.NoError(t, err)
+		_, err = scbuild.BuildTargets(ctx, scbuild.TargetsDependencies{}, stmt.AST)
+		require.Error(t, err)
+	})
+
+	t.Run("supported", func(t *testing.T) {
+		targets, err := buildTargets(`ALTER TABLE defaultdb.foo ADD COLUMN j INT DEFAULT 42`)
+		require.NoError(t, err)
+		var column *scpb.ColumnName
+		for _, target := range targets {
+			if e, ok := target.Element().(*scpb.ColumnName); ok && e.Name == "j" {
+				column = e
+				require.Equal(t, scpb.Status_PUBLIC, target.TargetStatus)
+			}
+		}
+		require.NotNil(t, column, "no target for the name of the new column in %v", targets)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := buildTargets(`ALTER TABLE defaultdb.foo RENAME TO bar`)
+		require.Error(t, err)
+		require.True(t, scerrors.HasNotImplemented(err), "expected a not implemented error, got %v", err)
+	})
+}
@@ -453,6 +453,14 @@ func (w *walkCtx) walkColumn(tbl catalog.TableDescriptor, col catalog.Column) {
 	} else if err != nil {
 		panic(err)
 	}
+	for _, user := range col.ColumnDesc().Privileges {
+		w.ev(scpb.Status_PUBLIC, &scpb.ColumnPrivileges{
+			TableID:    tbl.GetID(),
+			ColumnID:   col.GetID(),
+			UserName:   user.User().Normalized(),
+			Privileges: user.Privileges,
+		})
+	}
 	owns := catalog.MakeDescriptorIDSet(col.ColumnDesc().OwnsSequenceIds...)
 	owns.Remove(catid.InvalidDescID)
 	owns.ForEach(func(id descpb.ID) {
@@ -126,6 +126,25 @@ func (d *txnDeps) UpdateSchemaChangeJob(
 	})
 }
 
+// CheckpointSchemaChangeJob implements the scexec.TransactionalJobRegistry
+// interface.
+func (d *txnDeps) CheckpointSchemaChangeJob(
+	ctx context.Context, id jobspb.JobID, checkpoint []byte,
+) error {
+	const useReadLock = false
+	return d.jobRegistry.UpdateJobWithTxn(ctx, id, d.txn, useReadLock, func(
+		txn *kv.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater,
+	) error {
+		progress := md.Progress.GetNewSchemaChange()
+		if progress == nil {
+			return errors.AssertionFailedf("job %d is not a schema change job", id)
+		}
+		progress.Checkpoint = checkpoint
+		ju.UpdateProgress(md.Progress)
+		return nil
+	})
+}
+
 var _ scexec.Catalog = (*txnDeps)(nil)
 
 // MustReadImmutableDescriptors implements the scmutationexec.CatalogReader interface.
@@ -373,6 +392,11 @@ func (d *execDeps) Clock() scmutationexec.Clock {
 	return d.clock
 }
 
+// ClusterSettings implements the scexec.Dependencies interface.
+func (d *execDeps) ClusterSettings() *cluster.Settings {
+	return d.settings
+}
+
 var _ scexec.Dependencies = (*execDeps)(nil)
 
 // Catalog implements the scexec.Dependencies interface.
@@ -23,10 +23,12 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scexec"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scexec/backfiller"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scplan"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scrun"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
 )
 
 // NewJobRunDependencies returns an scrun.JobRunDependencies implementation built from the
@@ -45,6 +47,8 @@ func NewJobRunDependencies(
 	indexValidator scexec.IndexValidator,
 	metadataUpdaterFactory MetadataUpdaterFactory,
 	statsRefresher scexec.StatsRefresher,
+	tableStatsResolver scplan.TableStatsResolver,
+	throughputHistory scrun.ThroughputHistory,
 	testingKnobs *scexec.TestingKnobs,
 	statements []string,
 	sessionData *sessiondata.SessionData,
@@ -68,6 +72,8 @@ func NewJobRunDependencies(
 		sessionData:           sessionData,
 		kvTrace:               kvTrace,
 		statsRefresher:        statsRefresher,
+		tableStatsResolver:    tableStatsResolver,
+		throughputHistory:     throughputHistory,
 	}
 }
 
@@ -76,6 +82,8 @@ type jobExecutionDeps struct {
 	db                    *kv.DB
 	eventLoggerFactory    func(txn *kv.Txn) scexec.EventLogger
 	statsRefresher        scexec.StatsRefresher
+	tableStatsResolver    scplan.TableStatsResolver
+	throughputHistory     scrun.ThroughputHistory
 	backfiller            scexec.Backfiller
 	merger                scexec.Merger
 	commentUpdaterFactory MetadataUpdaterFactory
@@ -170,3 +178,62 @@ func (d *jobExecutionDeps) WithTxnInJob(ctx context.Context, fn scrun.JobTxnFunc
 	}
 	return nil
 }
+
+// RecordStageError implements the scrun.JobRunDependencies interface.
+func (d *jobExecutionDeps) RecordStageError(
+	ctx context.Context, stageErr jobspb.SchemaChangeStageError, err error,
+) error {
+	stageErr.Error, stageErr.TruncatedError = jobs.EncodeExecutionError(ctx, &d.settings.SV, err)
+	return d.job.Update(ctx, nil /* txn */, func(
+		txn *kv.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater,
+	) error {
+		progress := md.Progress.GetNewSchemaChange()
+		if progress == nil {
+			return errors.AssertionFailedf("job %d is not a schema change job", d.job.ID())
+		}
+		progress.StageErrors = scrun.AppendStageError(
+			progress.StageErrors, stageErr, jobs.ExecutionErrorsMaxEntries(&d.settings.SV),
+		)
+		ju.UpdateProgress(md.Progress)
+		return nil
+	})
+}
+
+// Checkpoint implements the scrun.JobRunDependencies interface.
+func (d *jobExecutionDeps) Checkpoint() []byte {
+	progress := d.job.Progress()
+	if sc := progress.GetNewSchemaChange(); sc != nil {
+		return sc.Checkpoint
+	}
+	return nil
+}
+
+// TableStatsResolver implements the scrun.JobRunDependencies interface.
+func (d *jobExecutionDeps) TableStatsResolver() scplan.TableStatsResolver {
+	return d.tableStatsResolver
+}
+
+// ThroughputHistory implements the scrun.JobRunDependencies interface.
+func (d *jobExecutionDeps) ThroughputHistory() scrun.ThroughputHistory {
+	return d.throughputHistory
+}
+
+// UpdateStageProgress implements the scrun.JobRunDependencies interface.
+func (d *jobExecutionDeps) UpdateStageProgress(
+	ctx context.Context, fn func(p *jobspb.NewSchemaChangeProgress),
+) error {
+	return d.job.Update(ctx, nil /* txn */, func(
+		txn *kv.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater,
+	) error {
+		progress := md.Progress.GetNewSchemaChange()
+		if progress == nil {
+			return errors.AssertionFailedf("job %d is not a schema change job", d.job.ID())
+		}
+		fn(progress)
+		md.Progress.Progress = &jobspb.Progress_FractionCompleted{
+			FractionCompleted: progress.FractionCompleted(),
+		}
+		ju.UpdateProgress(md.Progress)
+		return nil
+	})
+}
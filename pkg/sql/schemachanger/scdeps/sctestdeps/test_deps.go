@@ -38,6 +38,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scexec"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scexec/scmutationexec"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scplan"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scplan/scviz"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scrun"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
@@ -175,6 +176,11 @@ func (s *TestState) MemberOfWithAdminOption(
 	return nil, nil
 }
 
+// RoleExists implements the scbuild.AuthorizationAccessor interface.
+func (s *TestState) RoleExists(ctx context.Context, role username.SQLUsername) (bool, error) {
+	return true, nil
+}
+
 // IndexPartitioningCCLCallback implements the scbuild.Dependencies interface.
 func (s *TestState) IndexPartitioningCCLCallback() scbuild.CreatePartitioningCCLCallback {
 	if ccl := scdeps.CreatePartitioningCCL; ccl != nil {
@@ -789,6 +795,9 @@ func (b *testCatalogChangeBatcher) ValidateAndRun(ctx context.Context) error {
 						ds[k] = "<redacted>"
 					}
 				}
+				// The session data snapshot duplicates the test session's
+				// settings, which are of no interest here.
+				delete(ds, "sessionData")
 			}
 		})
 		b.s.LogSideEffectf("upsert descriptor #%d\n%s", desc.GetID(), diff)
@@ -924,6 +933,33 @@ func (s *TestState) UpdateSchemaChangeJob(
 	return fn(oldJobMetadata, updateProgress, updatePayload)
 }
 
+// CheckpointSchemaChangeJob implements the scexec.TransactionalJobRegistry
+// interface.
+func (s *TestState) CheckpointSchemaChangeJob(
+	_ context.Context, id jobspb.JobID, checkpoint []byte,
+) error {
+	scJob := s.schemaChangeJobRecord(id)
+	if scJob == nil {
+		return errors.AssertionFailedf("schema change job not found")
+	}
+	progress, _ := scJob.Progress.(jobspb.NewSchemaChangeProgress)
+	progress.Checkpoint = checkpoint
+	scJob.Progress = progress
+	return nil
+}
+
+// schemaChangeJobRecord returns the record of the latest job with the given
+// ID, or nil if there is none.
+func (s *TestState) schemaChangeJobRecord(id jobspb.JobID) *jobs.Record {
+	var scJob *jobs.Record
+	for i, job := range s.jobs {
+		if job.JobID == id {
+			scJob = &s.jobs[i]
+		}
+	}
+	return scJob
+}
+
 // MakeJobID implements the scexec.TransactionalJobRegistry interface.
 func (s *TestState) MakeJobID() jobspb.JobID {
 	if s.jobCounter == 0 {
@@ -963,6 +999,49 @@ func (s *TestState) WithTxnInJob(ctx context.Context, fn scrun.JobTxnFunc) (err
 	return err
 }
 
+// RecordStageError implements the scrun.JobRunDependencies interface.
+func (s *TestState) RecordStageError(
+	_ context.Context, stageErr jobspb.SchemaChangeStageError, err error,
+) error {
+	s.LogSideEffectf("record error for %s stage %d of %d of schema change job #%d: %v",
+		stageErr.Phase, stageErr.Ordinal, stageErr.StagesInPhase, s.SchemaChangerJobID(), err)
+	return nil
+}
+
+// UpdateStageProgress implements the scrun.JobRunDependencies interface.
+func (s *TestState) UpdateStageProgress(
+	_ context.Context, fn func(p *jobspb.NewSchemaChangeProgress),
+) error {
+	fn(&s.stageProgress)
+	return nil
+}
+
+// Checkpoint implements the scrun.JobRunDependencies interface.
+func (s *TestState) Checkpoint() []byte {
+	if scJob := s.schemaChangeJobRecord(s.SchemaChangerJobID()); scJob != nil {
+		if progress, ok := scJob.Progress.(jobspb.NewSchemaChangeProgress); ok {
+			return progress.Checkpoint
+		}
+	}
+	return nil
+}
+
+// TableStatsResolver implements the scrun.JobRunDependencies interface.
+func (s *TestState) TableStatsResolver() scplan.TableStatsResolver {
+	return nil
+}
+
+// ThroughputHistory implements the scrun.JobRunDependencies interface.
+func (s *TestState) ThroughputHistory() scrun.ThroughputHistory {
+	return nil
+}
+
+// StageProgress returns the progress of the stages of the schema change job,
+// as recorded by UpdateStageProgress.
+func (s *TestState) StageProgress() *jobspb.NewSchemaChangeProgress {
+	return &s.stageProgress
+}
+
 // ValidateForwardIndexes implements the index validator interface.
 func (s *TestState) ValidateForwardIndexes(
 	_ context.Context,
@@ -1103,6 +1182,55 @@ func (s *TestState) UpsertZoneConfig(
 	return 1, nil
 }
 
+// SetIndexGCTTL implements scexec.DescriptorMetadataUpdater.
+func (s *TestState) SetIndexGCTTL(
+	ctx context.Context, tableID descpb.ID, indexID descpb.IndexID, ttlSeconds int32,
+) error {
+	s.LogSideEffectf("set GC TTL of index %d of table %d to %ds", indexID, tableID, ttlSeconds)
+	zone := s.zoneConfigs[tableID]
+	if zone == nil {
+		zone = zonepb.NewZoneConfig()
+		zone.DeleteTableConfig()
+		s.zoneConfigs[tableID] = zone
+	}
+	zone.SetIndexGCTTL(uint32(indexID), ttlSeconds)
+	return nil
+}
+
+// CopyIndexZoneConfig implements scexec.DescriptorMetadataUpdater.
+func (s *TestState) CopyIndexZoneConfig(
+	ctx context.Context, tableID descpb.ID, sourceIndexID, indexID descpb.IndexID,
+) error {
+	zone := s.zoneConfigs[tableID]
+	if zone == nil || !zone.CopyIndexSubzones(uint32(sourceIndexID), uint32(indexID)) {
+		return nil
+	}
+	s.LogSideEffectf("copy zone config of index %d of table %d to index %d",
+		sourceIndexID, tableID, indexID)
+	return nil
+}
+
+// UpsertSchemaChangeElementStatuses implements
+// scexec.DescriptorMetadataUpdater.
+func (s *TestState) UpsertSchemaChangeElementStatuses(
+	ctx context.Context, jobID jobspb.JobID, transitions []scop.ElementStatusTransition,
+) error {
+	for _, t := range transitions {
+		s.LogSideEffectf("upsert status %s → %s of element %d %s for job %d",
+			t.From, t.To, t.ElementID, t.Element, jobID)
+	}
+	return nil
+}
+
+// DeleteSchemaChangeElementStatuses implements
+// scexec.DescriptorMetadataUpdater.
+func (s *TestState) DeleteSchemaChangeElementStatuses(
+	ctx context.Context, jobID jobspb.JobID,
+) error {
+	s.LogSideEffectf("delete element statuses for job %d", jobID)
+	return nil
+}
+
 // DescriptorMetadataUpdater implement scexec.Dependencies.
 func (s *TestState) DescriptorMetadataUpdater(
 	ctx context.Context,
@@ -68,6 +68,7 @@ type TestState struct {
 	jobCounter              int
 	txnCounter              int
 	sideEffectLogBuffer     strings.Builder
+	stageProgress           jobspb.NewSchemaChangeProgress
 
 	// The below portions fo the Dependencies are stored as interfaces because
 	// we permit users of this package to override the default implementations.
@@ -15,6 +15,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/security/username"
@@ -171,6 +172,7 @@ func MakePlan(t *testing.T, state scpb.CurrentState, phase scop.Phase) scplan.Pl
 	plan, err := scplan.MakePlan(state, scplan.Params{
 		ExecutionPhase:             phase,
 		SchemaChangerJobIDSupplier: func() jobspb.JobID { return 1 },
+		ActiveVersion:              clusterversion.TestingClusterVersion,
 	})
 	require.NoError(t, err)
 	return plan
@@ -23,6 +23,9 @@ import (
 type notImplementedError struct {
 	n      tree.NodeFormatter
 	detail string
+
+	// diagnostics are set by the builder, see SetFallbackDiagnostics.
+	diagnostics *FallbackDiagnostics
 }
 
 // TODO(ajwerner): Deal with redaction.
@@ -0,0 +1,92 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scerrors
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+)
+
+// FallbackDiagnostics describe a statement which the declarative schema
+// changer did not build, so that it fell back to the legacy schema changer.
+// They are recorded in telemetry and in the statement diagnostics bundles to
+// help prioritize the support of new statements, and are free of any names
+// or values from the statement.
+type FallbackDiagnostics struct {
+	// StatementTag is the tag of the statement.
+	StatementTag string
+
+	// ASTShape is the shape of the statement: the types of its nodes which
+	// determine whether it is supported, e.g. the commands of an ALTER TABLE.
+	ASTShape string
+
+	// UnsupportedNode is the type of the first node of the statement which is
+	// not supported, or that of the statement when the node is unknown.
+	UnsupportedNode string
+
+	// Detail is the reason why the node is not supported, if any.
+	Detail string
+
+	// CatalogContext are hashes of the descriptors resolved while building the
+	// statement, prefixed by their type, which tell apart the reports for the
+	// same statement on different objects without revealing them.
+	CatalogContext []string
+}
+
+// String formats the diagnostics as the contents of a diagnostics bundle file.
+func (d FallbackDiagnostics) String() string {
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "statement tag: %s\n", d.StatementTag)
+	fmt.Fprintf(&buf, "AST shape: %s\n", d.ASTShape)
+	fmt.Fprintf(&buf, "unsupported node: %s\n", d.UnsupportedNode)
+	if d.Detail != "" {
+		fmt.Fprintf(&buf, "detail: %s\n", d.Detail)
+	}
+	buf.WriteString("catalog context:\n")
+	for _, h := range d.CatalogContext {
+		fmt.Fprintf(&buf, "  %s\n", h)
+	}
+	return buf.String()
+}
+
+// SetFallbackDiagnostics attaches the diagnostics to the error, if it is one
+// for which HasNotImplemented returns true. Their UnsupportedNode and Detail
+// fields are taken from the error, when it has them.
+func SetFallbackDiagnostics(err error, d FallbackDiagnostics) {
+	var e *notImplementedError
+	if !errors.As(err, &e) {
+		return
+	}
+	if e.n != nil {
+		d.UnsupportedNode = NodeTypeName(e.n)
+	}
+	d.Detail = e.detail
+	e.diagnostics = &d
+}
+
+// GetFallbackDiagnostics returns the diagnostics attached to the error by
+// SetFallbackDiagnostics, if any.
+func GetFallbackDiagnostics(err error) (FallbackDiagnostics, bool) {
+	var e *notImplementedError
+	if !errors.As(err, &e) || e.diagnostics == nil {
+		return FallbackDiagnostics{}, false
+	}
+	return *e.diagnostics, true
+}
+
+// NodeTypeName returns the name of the type of the node, without its package
+// and pointer qualifiers, e.g. AlterTableAddColumn.
+func NodeTypeName(n interface{}) string {
+	name := fmt.Sprintf("%T", n)
+	return name[strings.LastIndexByte(name, '.')+1:]
+}
@@ -101,9 +101,23 @@ func newTrackerConfig(codec keys.SQLCodec, rc RangeCounter, job *jobs.Job) track
 	return trackerConfig{
 		numRangesInSpanContainedBy: rc.NumRangesInSpanContainedBy,
 		writeProgressFraction: func(ctx context.Context, fractionProgressed float32) error {
-			if err := job.FractionProgressed(
-				ctx, nil /* txn */, jobs.FractionUpdater(fractionProgressed),
-			); err != nil {
+			if err := job.FractionProgressed(ctx, nil /* txn */, func(
+				ctx context.Context, details jobspb.ProgressDetails,
+			) float32 {
+				// Report the fraction as the progress of the running stage, if the
+				// progress of the stages is tracked, and derive the fraction of the
+				// job from it.
+				sc, ok := details.(*jobspb.Progress_NewSchemaChange)
+				if !ok {
+					return fractionProgressed
+				}
+				sp := sc.NewSchemaChange.RunningStage()
+				if sp == nil {
+					return fractionProgressed
+				}
+				sp.FractionCompleted = fractionProgressed
+				return sc.NewSchemaChange.FractionCompleted()
+			}); err != nil {
 				return jobs.SimplifyInvalidStatusError(err)
 			}
 			return nil
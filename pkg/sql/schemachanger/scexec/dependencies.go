@@ -19,9 +19,11 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scexec/scmutationexec"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
@@ -44,6 +46,7 @@ type Dependencies interface {
 	StatsRefresher() StatsRefreshQueue
 	GetTestingKnobs() *TestingKnobs
 	Telemetry() Telemetry
+	ClusterSettings() *cluster.Settings
 
 	// Statements returns the statements behind this schema change.
 	Statements() []string
@@ -117,6 +120,10 @@ type TransactionalJobRegistry interface {
 	// via the supplied callback.
 	UpdateSchemaChangeJob(ctx context.Context, id jobspb.JobID, fn JobUpdateCallback) error
 
+	// CheckpointSchemaChangeJob persists the encoded checkpoint of the state of
+	// the schema change in the progress of its job.
+	CheckpointSchemaChangeJob(ctx context.Context, id jobspb.JobID, checkpoint []byte) error
+
 	// MakeJobID is used to make a JobID.
 	MakeJobID() jobspb.JobID
 
@@ -365,6 +372,29 @@ type DescriptorMetadataUpdater interface {
 
 	// DeleteZoneConfig deletes a zone config for a given descriptor.
 	DeleteZoneConfig(ctx context.Context, id descpb.ID) (numAffected int, err error)
+
+	// SetIndexGCTTL overrides the GC TTL of an index in the zone config of its
+	// table, creating a subzone placeholder for the table if necessary.
+	SetIndexGCTTL(
+		ctx context.Context, tableID descpb.ID, indexID descpb.IndexID, ttlSeconds int32,
+	) error
+
+	// CopyIndexZoneConfig copies the subzones of an index in the zone config of
+	// its table to another index, if there are any.
+	CopyIndexZoneConfig(
+		ctx context.Context, tableID descpb.ID, sourceIndexID, indexID descpb.IndexID,
+	) error
+
+	// UpsertSchemaChangeElementStatuses records the latest status transitions
+	// of the elements targeted by a schema changer job in the
+	// system.schema_change_element_statuses table.
+	UpsertSchemaChangeElementStatuses(
+		ctx context.Context, jobID jobspb.JobID, transitions []scop.ElementStatusTransition,
+	) error
+
+	// DeleteSchemaChangeElementStatuses deletes the records pertaining to a
+	// schema changer job from the system.schema_change_element_statuses table.
+	DeleteSchemaChangeElementStatuses(ctx context.Context, jobID jobspb.JobID) error
 }
 
 // StatsRefreshQueue queues table for stats refreshes.
@@ -32,7 +32,10 @@ import (
 // be confusing than valuable. Not much is being done transactionally.
 
 func executeBackfillOps(ctx context.Context, deps Dependencies, execute []scop.Op) (err error) {
-	backfillsToExecute, mergesToExecute := extractBackfillsAndMergesFromOps(execute)
+	backfillsToExecute, mergesToExecute, sequential, err := extractBackfillsAndMergesFromOps(ctx, execute)
+	if err != nil {
+		return err
+	}
 	tables, err := getTableDescriptorsForBackfillsAndMerges(ctx, deps.Catalog(), backfillsToExecute, mergesToExecute)
 	if err != nil {
 		return err
@@ -44,7 +47,7 @@ func executeBackfillOps(ctx context.Context, deps Dependencies, execute []scop.O
 	if err != nil {
 		return err
 	}
-	return runBackfiller(ctx, deps, tracker, backfillProgresses, mergeProgresses, tables)
+	return runBackfiller(ctx, deps, tracker, backfillProgresses, mergeProgresses, sequential, tables)
 }
 
 func getTableDescriptorsForBackfillsAndMerges(
@@ -72,28 +75,63 @@ func getTableDescriptorsForBackfillsAndMerges(
 	return tables, nil
 }
 
-func extractBackfillsAndMergesFromOps(execute []scop.Op) ([]Backfill, []Merge) {
-	var bfs []Backfill
-	var ms []Merge
+func extractBackfillsAndMergesFromOps(
+	ctx context.Context, execute []scop.Op,
+) ([]Backfill, []Merge, map[backfillSource]bool, error) {
+	var c backfillCollector
 	for _, op := range execute {
-		switch op := op.(type) {
-		case *scop.BackfillIndex:
-			bfs = append(bfs, Backfill{
-				TableID:       op.TableID,
-				SourceIndexID: op.SourceIndexID,
-				DestIndexIDs:  []descpb.IndexID{op.IndexID},
-			})
-		case *scop.MergeIndex:
-			ms = append(ms, Merge{
-				TableID:        op.TableID,
-				SourceIndexIDs: []descpb.IndexID{op.TemporaryIndexID},
-				DestIndexIDs:   []descpb.IndexID{op.BackfilledIndexID},
-			})
-		default:
-			panic("unimplemented")
+		if err := op.(scop.BackfillOp).Visit(ctx, &c); err != nil {
+			return nil, nil, nil, errors.Wrapf(err, "%T: %v", op, op)
 		}
 	}
-	return mergeBackfillsFromSameSource(bfs), mergeMergesFromSameTable(ms)
+	return mergeBackfillsFromSameSource(c.backfills), mergeMergesFromSameTable(c.merges), c.sequential, nil
+}
+
+// backfillSource identifies the backfills which get compacted together by
+// mergeBackfillsFromSameSource.
+type backfillSource struct {
+	tableID       descpb.ID
+	sourceIndexID descpb.IndexID
+}
+
+// backfillCollector collects the backfills and merges performed by backfill
+// ops. Implementing the generated scop.BackfillVisitor interface ensures that
+// every backfill op can be executed.
+type backfillCollector struct {
+	backfills []Backfill
+	merges    []Merge
+
+	// sequential contains the sources of the backfills which should not run
+	// concurrently with the others.
+	sequential map[backfillSource]bool
+}
+
+var _ scop.BackfillVisitor = (*backfillCollector)(nil)
+
+// BackfillIndex is part of the scop.BackfillVisitor interface.
+func (c *backfillCollector) BackfillIndex(_ context.Context, op scop.BackfillIndex) error {
+	c.backfills = append(c.backfills, Backfill{
+		TableID:       op.TableID,
+		SourceIndexID: op.SourceIndexID,
+		DestIndexIDs:  []descpb.IndexID{op.IndexID},
+	})
+	if op.Sequential {
+		if c.sequential == nil {
+			c.sequential = make(map[backfillSource]bool)
+		}
+		c.sequential[backfillSource{tableID: op.TableID, sourceIndexID: op.SourceIndexID}] = true
+	}
+	return nil
+}
+
+// MergeIndex is part of the scop.BackfillVisitor interface.
+func (c *backfillCollector) MergeIndex(_ context.Context, op scop.MergeIndex) error {
+	c.merges = append(c.merges, Merge{
+		TableID:        op.TableID,
+		SourceIndexIDs: []descpb.IndexID{op.TemporaryIndexID},
+		DestIndexIDs:   []descpb.IndexID{op.BackfilledIndexID},
+	})
+	return nil
 }
 
 // mergeBackfillsFromSameSource will take a slice of backfills which
@@ -310,6 +348,7 @@ func runBackfiller(
 	tracker BackfillerTracker,
 	backfillProgresses []BackfillProgress,
 	mergeProgresses []MergeProgress,
+	sequential map[backfillSource]bool,
 	tables map[descpb.ID]catalog.TableDescriptor,
 ) error {
 	if deps.GetTestingKnobs() != nil &&
@@ -330,7 +369,9 @@ func runBackfiller(
 	mf := func(ctx context.Context, p *MergeProgress) error {
 		return im.MergeIndexes(ctx, *p, tracker, tables[p.TableID])
 	}
-	if err := forEachProgressConcurrently(ctx, op, backfillProgresses, mergeProgresses, bf, mf); err != nil {
+	if err := runBackfillsAndMerges(
+		ctx, op, backfillProgresses, mergeProgresses, sequential, bf, mf,
+	); err != nil {
 		pgCode := pgerror.GetPGCode(err)
 		// Determine the type of error we encountered.
 		if pgCode == pgcode.CheckViolation ||
@@ -354,6 +395,40 @@ func runBackfiller(
 	return tracker.FlushCheckpoint(ctx)
 }
 
+// runBackfillsAndMerges runs the backfills and the merges concurrently, except
+// for the sequential backfills which run one after the other once all the
+// others are done. This bounds the resources used at any given time by the
+// larger backfills.
+func runBackfillsAndMerges(
+	ctx context.Context,
+	op redact.SafeString,
+	bs []BackfillProgress,
+	ms []MergeProgress,
+	sequential map[backfillSource]bool,
+	bf func(context.Context, *BackfillProgress) error,
+	mf func(context.Context, *MergeProgress) error,
+) error {
+	var concurrent, sequentialBs []BackfillProgress
+	for _, p := range bs {
+		if sequential[backfillSource{tableID: p.TableID, sourceIndexID: p.SourceIndexID}] {
+			sequentialBs = append(sequentialBs, p)
+		} else {
+			concurrent = append(concurrent, p)
+		}
+	}
+	if err := forEachProgressConcurrently(ctx, op, concurrent, ms, bf, mf); err != nil {
+		return err
+	}
+	for i := range sequentialBs {
+		if err := forEachProgressConcurrently(
+			ctx, op, sequentialBs[i:i+1], nil /* ms */, bf, nil, /* mf */
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func runBackfill(
 	ctx context.Context,
 	splitter IndexSpanSplitter,
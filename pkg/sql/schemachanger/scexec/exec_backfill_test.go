@@ -269,6 +269,65 @@ func TestExecBackfiller(t *testing.T) {
 				require.NoError(t, scexec.ExecuteStage(ctx, deps, ops))
 			},
 		},
+		{
+			name: "two tables, one backfilled sequentially",
+			f: func(t *testing.T, tdb *sqlutils.SQLRunner) {
+				tdb.Exec(t, "create table foo (i INT PRIMARY KEY, j INT)")
+				tdb.Exec(t, "create table bar (i INT PRIMARY KEY, j INT)")
+				descs := sctestdeps.ReadDescriptorsFromDB(ctx, t, tdb)
+				var fooID, barID descpb.ID
+				for _, name := range []string{"foo", "bar"} {
+					tab := findTableWithName(descs.Catalog, name)
+					require.NotNil(t, tab)
+					if name == "foo" {
+						fooID = tab.GetID()
+					} else {
+						barID = tab.GetID()
+					}
+					mut := tabledesc.NewBuilder(tab.TableDesc()).BuildExistingMutableTable()
+					addIndexMutation(t, mut, "idx", 2, false /* isTempIndex */, "j")
+					descs.UpsertDescriptorEntry(mut)
+				}
+
+				mc, bt, bf, _, deps := setupTestDeps(t, tdb, descs.Catalog)
+				defer mc.Finish()
+				foo := getTableDescriptor(ctx, t, deps, fooID)
+				bar := getTableDescriptor(ctx, t, deps, barID)
+
+				progressOf := func(id descpb.ID) scexec.BackfillProgress {
+					backfill := scexec.Backfill{
+						TableID:       id,
+						SourceIndexID: 1,
+						DestIndexIDs:  []descpb.IndexID{2},
+					}
+					progress := scexec.BackfillProgress{
+						Backfill:              backfill,
+						MinimumWriteTimestamp: hlc.Timestamp{WallTime: 1},
+					}
+					bt.EXPECT().
+						GetBackfillProgress(gomock.Any(), backfill).
+						Return(progress, nil)
+					return progress
+				}
+				progressFoo, progressBar := progressOf(fooID), progressOf(barID)
+				backfillFooCall := bf.EXPECT().
+					BackfillIndexes(gomock.Any(), progressFoo, bt, foo)
+				backfillBarCall := bf.EXPECT().
+					BackfillIndexes(gomock.Any(), progressBar, bt, bar).
+					After(backfillFooCall)
+				bt.EXPECT().
+					FlushCheckpoint(gomock.Any()).
+					After(backfillBarCall)
+				bt.EXPECT().
+					FlushFractionCompleted(gomock.Any()).
+					After(backfillBarCall)
+				sequentialOp := backfillIndexOp(barID, 1, 2)
+				sequentialOp.Sequential = true
+				require.NoError(t, scexec.ExecuteStage(ctx, deps, []scop.Op{
+					sequentialOp, backfillIndexOp(fooID, 1, 2),
+				}))
+			},
+		},
 		{name: "simple merge", f: func(t *testing.T, tdb *sqlutils.SQLRunner) {
 			tdb.Exec(t, "create table foo (i INT PRIMARY KEY, j INT)")
 			descs := sctestdeps.ReadDescriptorsFromDB(ctx, t, tdb)
@@ -149,10 +149,11 @@ func logEvents(ctx context.Context, mvs *mutationVisitorState, el EventLogger) e
 		entries := eventLogEntriesForStatement(mvs.eventsByStatement[statementID])
 		for _, e := range entries {
 			// TODO(postamar): batch these
-			switch e.event.(type) {
+			switch ev := e.event.(type) {
 			case eventpb.EventWithCommonSQLPayload:
 				details := e.details
 				details.DescriptorID = uint32(e.id)
+				truncateEvent(&details, ev, mvs.schemaChangerJobID())
 				if err := el.LogEvent(ctx, details, e.event); err != nil {
 					return err
 				}
@@ -164,9 +165,39 @@ func logEvents(ctx context.Context, mvs *mutationVisitorState, el EventLogger) e
 
 		}
 	}
+	for _, event := range mvs.schemaChangeEvents {
+		if err := el.LogEventForSchemaChange(ctx, event); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// maxEventLogPayloadSize is the budget for the size of the JSON
+// representation of the events logged by the declarative schema changer.
+// Events which exceed it are truncated instead of failing the write to the
+// event log, which is bounded by the maximum row size.
+const maxEventLogPayloadSize = 1 << 20
+
+// truncateEvent truncates the event, along with the common details which
+// are injected into it when it is logged, if it exceeds
+// maxEventLogPayloadSize. Truncated events are flagged as such and point to
+// the schema changer job, if any, which holds the full details of the
+// schema change.
+func truncateEvent(
+	details *eventpb.CommonSQLEventDetails,
+	event eventpb.EventWithCommonSQLPayload,
+	jobID jobspb.JobID,
+) {
+	*event.CommonSQLDetails() = *details
+	if !eventpb.TruncatePayload(event, maxEventLogPayloadSize) {
+		return
+	}
+	*details = *event.CommonSQLDetails()
+	details.Truncated = true
+	details.DetailsJobID = int64(jobID)
+}
+
 func eventLogEntriesForStatement(statementEvents []eventPayload) (logEntries []eventPayload) {
 	// A dependent event is one which is generated because of a
 	// dependency getting modified from the source object. An example
@@ -296,6 +327,25 @@ func updateDescriptorMetadata(
 			return err
 		}
 	}
+	for _, zc := range mvs.indexZoneConfigsToCopy {
+		if err := m.CopyIndexZoneConfig(ctx, zc.tableID, zc.sourceIndexID, zc.indexID); err != nil {
+			return err
+		}
+	}
+	for _, ttl := range mvs.indexGCTTLsToSet {
+		if err := m.SetIndexGCTTL(ctx, ttl.tableID, ttl.indexID, ttl.ttlSeconds); err != nil {
+			return err
+		}
+	}
+	for _, u := range mvs.elementStatusUpdates {
+		if u.transitions == nil {
+			if err := m.DeleteSchemaChangeElementStatuses(ctx, u.jobID); err != nil {
+				return err
+			}
+		} else if err := m.UpsertSchemaChangeElementStatuses(ctx, u.jobID, u.transitions); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -398,8 +448,12 @@ type mutationVisitorState struct {
 	schemaChangerJob             *jobs.Record
 	schemaChangerJobUpdates      map[jobspb.JobID]schemaChangerJobUpdate
 	eventsByStatement            map[uint32][]eventPayload
+	schemaChangeEvents           []eventpb.EventWithCommonSchemaChangePayload
 	scheduleIDsToDelete          []int64
 	statsToRefresh               map[descpb.ID]struct{}
+	indexGCTTLsToSet             []indexGCTTLToSet
+	indexZoneConfigsToCopy       []indexZoneConfigToCopy
+	elementStatusUpdates         []elementStatusUpdate
 
 	gcJobs
 }
@@ -410,6 +464,25 @@ type constraintCommentToUpdate struct {
 	comment      string
 }
 
+type indexGCTTLToSet struct {
+	tableID    descpb.ID
+	indexID    descpb.IndexID
+	ttlSeconds int32
+}
+
+type indexZoneConfigToCopy struct {
+	tableID       descpb.ID
+	sourceIndexID descpb.IndexID
+	indexID       descpb.IndexID
+}
+
+// elementStatusUpdate records the element status transitions of a schema
+// changer job, or deletes the records if the transitions are nil.
+type elementStatusUpdate struct {
+	jobID       jobspb.JobID
+	transitions []scop.ElementStatusTransition
+}
+
 type commentToUpdate struct {
 	id          int64
 	subID       int64
@@ -550,6 +623,44 @@ func (mvs *mutationVisitorState) DeleteSchedule(scheduleID int64) {
 	mvs.scheduleIDsToDelete = append(mvs.scheduleIDsToDelete, scheduleID)
 }
 
+func (mvs *mutationVisitorState) SetIndexGCTTL(
+	tableID descpb.ID, indexID descpb.IndexID, ttlSeconds int32,
+) {
+	mvs.indexGCTTLsToSet = append(mvs.indexGCTTLsToSet, indexGCTTLToSet{
+		tableID:    tableID,
+		indexID:    indexID,
+		ttlSeconds: ttlSeconds,
+	})
+}
+
+func (mvs *mutationVisitorState) CopyIndexZoneConfig(
+	tableID descpb.ID, sourceIndexID, indexID descpb.IndexID,
+) {
+	mvs.indexZoneConfigsToCopy = append(mvs.indexZoneConfigsToCopy, indexZoneConfigToCopy{
+		tableID:       tableID,
+		sourceIndexID: sourceIndexID,
+		indexID:       indexID,
+	})
+}
+
+func (mvs *mutationVisitorState) UpsertSchemaChangeElementStatuses(
+	jobID jobspb.JobID, transitions []scop.ElementStatusTransition,
+) {
+	if len(transitions) == 0 {
+		return
+	}
+	mvs.elementStatusUpdates = append(mvs.elementStatusUpdates, elementStatusUpdate{
+		jobID:       jobID,
+		transitions: transitions,
+	})
+}
+
+func (mvs *mutationVisitorState) DeleteSchemaChangeElementStatuses(jobID jobspb.JobID) {
+	mvs.elementStatusUpdates = append(mvs.elementStatusUpdates, elementStatusUpdate{
+		jobID: jobID,
+	})
+}
+
 func (mvs *mutationVisitorState) RefreshStats(descriptorID descpb.ID) {
 	mvs.statsToRefresh[descriptorID] = struct{}{}
 }
@@ -565,6 +676,7 @@ func (mvs *mutationVisitorState) AddNewSchemaChangerJob(
 	auth scpb.Authorization,
 	descriptorIDs catalog.DescriptorIDSet,
 	runningStatus string,
+	safeMode bool,
 ) error {
 	if mvs.schemaChangerJob != nil {
 		return errors.AssertionFailedf("cannot create more than one new schema change job")
@@ -577,6 +689,7 @@ func (mvs *mutationVisitorState) AddNewSchemaChangerJob(
 		descriptorIDs,
 		runningStatus,
 	)
+	mvs.schemaChangerJob.Details = jobspb.NewSchemaChangeDetails{SafeMode: safeMode}
 	return nil
 }
 
@@ -623,6 +736,18 @@ func MakeDeclarativeSchemaChangeJobRecord(
 	return rec
 }
 
+// schemaChangerJobID returns the ID of the schema changer job created or
+// updated by the mutations, if any.
+func (mvs *mutationVisitorState) schemaChangerJobID() jobspb.JobID {
+	if mvs.schemaChangerJob != nil {
+		return mvs.schemaChangerJob.JobID
+	}
+	for jobID := range mvs.schemaChangerJobUpdates {
+		return jobID
+	}
+	return jobspb.InvalidJobID
+}
+
 // EnqueueEvent implements the scmutationexec.MutationVisitorStateUpdater
 // interface.
 func (mvs *mutationVisitorState) EnqueueEvent(
@@ -642,3 +767,11 @@ func (mvs *mutationVisitorState) EnqueueEvent(
 	)
 	return nil
 }
+
+// EnqueueSchemaChangeEvent implements the
+// scmutationexec.MutationVisitorStateUpdater interface.
+func (mvs *mutationVisitorState) EnqueueSchemaChangeEvent(
+	event eventpb.EventWithCommonSchemaChangePayload,
+) {
+	mvs.schemaChangeEvents = append(mvs.schemaChangeEvents, event)
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scerrors"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
 	"github.com/cockroachdb/errors"
 )
 
@@ -38,9 +39,12 @@ func executeValidateUniqueIndex(
 	if err != nil {
 		return err
 	}
-	// Execute the validation operation as a root user.
+	// Execute the validation operation as a root user, with the admission
+	// control priority corresponding to the cost of scanning the table.
+	qos := sessiondatapb.QoSLevel(AdmissionPriority(op.CostClass()))
 	execOverride := sessiondata.InternalExecutorOverride{
-		User: username.RootUserName(),
+		User:             username.RootUserName(),
+		QualityOfService: &qos,
 	}
 	if index.GetType() == descpb.IndexDescriptor_FORWARD {
 		err = deps.IndexValidator().ValidateForwardIndexes(ctx, table, []catalog.Index{index}, execOverride)
@@ -69,17 +73,31 @@ func executeValidationOps(ctx context.Context, deps Dependencies, ops []scop.Op)
 }
 
 func executeValidationOp(ctx context.Context, deps Dependencies, op scop.Op) (err error) {
-	switch op := op.(type) {
-	case *scop.ValidateUniqueIndex:
-		if err = executeValidateUniqueIndex(ctx, deps, op); err != nil {
-			return errors.Wrapf(err, "%T: %v", op, op)
-		}
-	case *scop.ValidateCheckConstraint:
-		if err = executeValidateCheckConstraint(ctx, deps, op); err != nil {
-			return errors.Wrapf(err, "%T: %v", op, op)
-		}
-	default:
-		panic("unimplemented")
+	if err = op.(scop.ValidationOp).Visit(ctx, validationVisitor{deps: deps}); err != nil {
+		return errors.Wrapf(err, "%T: %v", op, op)
 	}
 	return nil
 }
+
+// validationVisitor executes validation ops. Implementing the generated
+// scop.ValidationVisitor interface ensures that every validation op can be
+// executed.
+type validationVisitor struct {
+	deps Dependencies
+}
+
+var _ scop.ValidationVisitor = validationVisitor{}
+
+// ValidateUniqueIndex is part of the scop.ValidationVisitor interface.
+func (v validationVisitor) ValidateUniqueIndex(
+	ctx context.Context, op scop.ValidateUniqueIndex,
+) error {
+	return executeValidateUniqueIndex(ctx, v.deps, &op)
+}
+
+// ValidateCheckConstraint is part of the scop.ValidationVisitor interface.
+func (v validationVisitor) ValidateCheckConstraint(
+	ctx context.Context, op scop.ValidateCheckConstraint,
+) error {
+	return executeValidateCheckConstraint(ctx, v.deps, &op)
+}
@@ -14,6 +14,7 @@ import (
 	"context"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
+	"github.com/cockroachdb/cockroach/pkg/util/admission/admissionpb"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/errors"
 )
@@ -38,3 +39,17 @@ func ExecuteStage(ctx context.Context, deps Dependencies, ops []scop.Op) error {
 		return errors.AssertionFailedf("unknown ops type %d", typ)
 	}
 }
+
+// AdmissionPriority returns the admission control priority with which the work
+// of ops of the given cost class is performed. Ops which scan or write table
+// data are subject to the same elastic regulation as other bulk jobs, whereas
+// the descriptor changes of metadata-only ops are admitted at the normal
+// priority so as not to hold up the schema change, and with it its leases.
+func AdmissionPriority(c scop.CostClass) admissionpb.WorkPriority {
+	switch c {
+	case scop.BulkReadCost, scop.BulkWriteCost:
+		return admissionpb.BulkNormalPri
+	default:
+		return admissionpb.NormalPri
+	}
+}
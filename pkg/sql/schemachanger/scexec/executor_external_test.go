@@ -568,6 +568,34 @@ func (noopMetadataUpdater) UpsertZoneConfig(
 	return 0, nil
 }
 
+// SetIndexGCTTL implements scexec.DescriptorMetadataUpdater
+func (noopMetadataUpdater) SetIndexGCTTL(
+	ctx context.Context, tableID descpb.ID, indexID descpb.IndexID, ttlSeconds int32,
+) error {
+	return nil
+}
+
+// CopyIndexZoneConfig implements scexec.DescriptorMetadataUpdater
+func (noopMetadataUpdater) CopyIndexZoneConfig(
+	ctx context.Context, tableID descpb.ID, sourceIndexID, indexID descpb.IndexID,
+) error {
+	return nil
+}
+
+// UpsertSchemaChangeElementStatuses implements scexec.DescriptorMetadataUpdater
+func (noopMetadataUpdater) UpsertSchemaChangeElementStatuses(
+	ctx context.Context, jobID jobspb.JobID, transitions []scop.ElementStatusTransition,
+) error {
+	return nil
+}
+
+// DeleteSchemaChangeElementStatuses implements scexec.DescriptorMetadataUpdater
+func (noopMetadataUpdater) DeleteSchemaChangeElementStatuses(
+	ctx context.Context, jobID jobspb.JobID,
+) error {
+	return nil
+}
+
 var _ scexec.Backfiller = noopBackfiller{}
 var _ scexec.IndexValidator = noopIndexValidator{}
 var _ scexec.EventLogger = noopEventLogger{}
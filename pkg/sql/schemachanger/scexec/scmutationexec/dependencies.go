@@ -85,6 +85,13 @@ type MutationVisitorStateUpdater interface {
 	// AddNewGCJobForIndex enqueues a GC job for the given table index.
 	AddNewGCJobForIndex(stmt scop.StatementForDropJob, tbl catalog.TableDescriptor, index catalog.Index)
 
+	// SetIndexGCTTL overrides the GC TTL of the given table index.
+	SetIndexGCTTL(tableID descpb.ID, indexID descpb.IndexID, ttlSeconds int32)
+
+	// CopyIndexZoneConfig copies the zone configurations of the given source
+	// index to the given table index.
+	CopyIndexZoneConfig(tableID descpb.ID, sourceIndexID, indexID descpb.IndexID)
+
 	// AddNewSchemaChangerJob adds a schema changer job.
 	AddNewSchemaChangerJob(
 		jobID jobspb.JobID,
@@ -93,6 +100,7 @@ type MutationVisitorStateUpdater interface {
 		auth scpb.Authorization,
 		descriptorIDs catalog.DescriptorIDSet,
 		runningStatus string,
+		safeMode bool,
 	) error
 
 	// UpdateSchemaChangerJob will update the progress and payload of the
@@ -104,6 +112,16 @@ type MutationVisitorStateUpdater interface {
 		descriptorIDsToRemove catalog.DescriptorIDSet,
 	) error
 
+	// UpsertSchemaChangeElementStatuses records the given element status
+	// transitions of a schema changer job.
+	UpsertSchemaChangeElementStatuses(
+		jobID jobspb.JobID, transitions []scop.ElementStatusTransition,
+	)
+
+	// DeleteSchemaChangeElementStatuses deletes the records of the element
+	// status transitions of a schema changer job.
+	DeleteSchemaChangeElementStatuses(jobID jobspb.JobID)
+
 	// EnqueueEvent will enqueue an event to be written to the event log.
 	EnqueueEvent(
 		id descpb.ID,
@@ -112,6 +130,11 @@ type MutationVisitorStateUpdater interface {
 		event logpb.EventPayload,
 	) error
 
+	// EnqueueSchemaChangeEvent will enqueue an event pertaining to the schema
+	// change itself, rather than to one of its statements, to be written to
+	// the event log after the statement events.
+	EnqueueSchemaChangeEvent(event eventpb.EventWithCommonSchemaChangePayload)
+
 	// DeleteSchedule deletes a scheduled job.
 	DeleteSchedule(scheduleID int64)
 
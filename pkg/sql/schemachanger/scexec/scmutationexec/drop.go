@@ -60,6 +60,20 @@ func (m *visitor) CreateGcJobForIndex(ctx context.Context, op scop.CreateGcJobFo
 	return nil
 }
 
+func (m *visitor) SetDroppedIndexGCTTL(ctx context.Context, op scop.SetDroppedIndexGCTTL) error {
+	desc, err := m.s.GetDescriptor(ctx, op.TableID)
+	if err != nil {
+		return err
+	}
+	// If the table is being dropped, the index data will be reclaimed along
+	// with it.
+	if desc.Dropped() {
+		return nil
+	}
+	m.s.SetIndexGCTTL(op.TableID, op.IndexID, op.TTLSeconds)
+	return nil
+}
+
 func (m *visitor) MarkDescriptorAsPublic(
 	ctx context.Context, op scop.MarkDescriptorAsPublic,
 ) error {
@@ -46,6 +46,32 @@ func (m *visitor) LogEvent(ctx context.Context, op scop.LogEvent) error {
 	return m.s.EnqueueEvent(descID, op.TargetMetadata, details, event)
 }
 
+func (m *visitor) LogSchemaChangeStageEvent(
+	ctx context.Context, op scop.LogSchemaChangeStageEvent,
+) error {
+	details := eventpb.CommonSchemaChangeStageEventDetails{
+		JobID:              int64(op.JobID),
+		User:               op.Authorization.UserName,
+		ApplicationName:    op.Authorization.AppName,
+		Phase:              op.Phase.String(),
+		StageOrdinal:       uint32(op.StageOrdinal),
+		StagesInPhase:      uint32(op.StagesInPhase),
+		InRollback:         op.InRollback,
+		ElementTransitions: op.ElementTransitions,
+	}
+	if op.Completed {
+		m.s.EnqueueSchemaChangeEvent(&eventpb.FinishSchemaChangeStage{
+			CommonSchemaChangeStageEventDetails: details,
+			NumOps:                              uint32(op.NumOps),
+		})
+	} else {
+		m.s.EnqueueSchemaChangeEvent(&eventpb.BeginSchemaChangeStage{
+			CommonSchemaChangeStageEventDetails: details,
+		})
+	}
+	return nil
+}
+
 // Indicates that no payload is required for the current element,
 // an event will be generated by a different element.
 var errEventPayloadNotRequired = errors.Newf("no payload required")
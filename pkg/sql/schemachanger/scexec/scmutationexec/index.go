@@ -202,6 +202,11 @@ func (m *visitor) MakeAddedPrimaryIndexPublic(
 	return nil
 }
 
+func (m *visitor) CopyIndexZoneConfig(ctx context.Context, op scop.CopyIndexZoneConfig) error {
+	m.s.CopyIndexZoneConfig(op.TableID, op.SourceIndexID, op.IndexID)
+	return nil
+}
+
 func (m *visitor) MakeAddedSecondaryIndexPublic(
 	ctx context.Context, op scop.MakeAddedSecondaryIndexPublic,
 ) error {
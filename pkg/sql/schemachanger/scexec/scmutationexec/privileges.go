@@ -0,0 +1,132 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scmutationexec
+
+import (
+	"context"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+	"github.com/cockroachdb/redact"
+)
+
+func (m *visitor) UpsertColumnPrivileges(
+	ctx context.Context, op scop.UpsertColumnPrivileges,
+) error {
+	d, user, err := m.checkOutColumnForPrivileges(ctx, op.TableID, op.ColumnID, op.User)
+	if err != nil || d == nil {
+		return err
+	}
+	i, found := findColumnPrivileges(d, user)
+	if found {
+		d.Privileges[i].Privileges = op.Privileges
+		return nil
+	}
+	d.Privileges = append(d.Privileges, catpb.UserPrivileges{})
+	copy(d.Privileges[i+1:], d.Privileges[i:])
+	d.Privileges[i] = catpb.UserPrivileges{
+		UserProto:  user.EncodeProto(),
+		Privileges: op.Privileges,
+	}
+	return nil
+}
+
+func (m *visitor) RemoveColumnPrivileges(
+	ctx context.Context, op scop.RemoveColumnPrivileges,
+) error {
+	d, user, err := m.checkOutColumnForPrivileges(ctx, op.TableID, op.ColumnID, op.User)
+	if err != nil || d == nil {
+		return err
+	}
+	// The privileges may have already been replaced by the ones of another
+	// element, in which case there is nothing to remove.
+	i, found := findColumnPrivileges(d, user)
+	if !found || d.Privileges[i].Privileges != op.Privileges {
+		return nil
+	}
+	d.Privileges = append(d.Privileges[:i], d.Privileges[i+1:]...)
+	return nil
+}
+
+// checkOutColumnForPrivileges returns the descriptor of the column whose
+// privileges are modified, or nil if its table is being dropped.
+func (m *visitor) checkOutColumnForPrivileges(
+	ctx context.Context, tableID descpb.ID, columnID descpb.ColumnID, userName string,
+) (*descpb.ColumnDescriptor, username.SQLUsername, error) {
+	user, err := username.MakeSQLUsernameFromUserInput(userName, username.PurposeValidation)
+	if err != nil {
+		return nil, username.SQLUsername{}, err
+	}
+	tbl, err := m.checkOutTable(ctx, tableID)
+	if err != nil || tbl.Dropped() {
+		return nil, user, err
+	}
+	col, err := tbl.FindColumnWithID(columnID)
+	if err != nil {
+		return nil, user, err
+	}
+	return col.ColumnDesc(), user, nil
+}
+
+// findColumnPrivileges returns the index of the privileges of the user in
+// the privileges of the column, which are sorted by user, and whether they
+// were found. If they were not, the index is the one at which they should be
+// inserted.
+func findColumnPrivileges(d *descpb.ColumnDescriptor, user username.SQLUsername) (int, bool) {
+	i := sort.Search(len(d.Privileges), func(i int) bool {
+		return !d.Privileges[i].User().LessThan(user)
+	})
+	return i, i < len(d.Privileges) && d.Privileges[i].User() == user
+}
+
+func (m *visitor) LogColumnPrivilegesEvent(
+	ctx context.Context, op scop.LogColumnPrivilegesEvent,
+) error {
+	fullName, err := m.nr.GetFullyQualifiedName(ctx, op.TableID)
+	if err != nil {
+		return err
+	}
+	tbl, err := m.checkOutTable(ctx, op.TableID)
+	if err != nil {
+		return err
+	}
+	event := &eventpb.ChangeColumnPrivilege{
+		CommonSQLPrivilegeEventDetails: eventpb.CommonSQLPrivilegeEventDetails{
+			Grantee: op.Grantee,
+			GrantedPrivileges: privilege.ListFromBitField(
+				op.GrantedPrivileges, privilege.Table,
+			).SortedNames(),
+			RevokedPrivileges: privilege.ListFromBitField(
+				op.RevokedPrivileges, privilege.Table,
+			).SortedNames(),
+		},
+		TableName: fullName,
+	}
+	for _, columnID := range op.ColumnIDs {
+		col, err := tbl.FindColumnWithID(columnID)
+		if err != nil {
+			return err
+		}
+		event.ColumnNames = append(event.ColumnNames, col.GetName())
+	}
+	details := eventpb.CommonSQLEventDetails{
+		ApplicationName: op.Authorization.AppName,
+		User:            op.Authorization.UserName,
+		Statement:       redact.RedactableString(op.Statement),
+		Tag:             op.StatementTag,
+	}
+	return m.s.EnqueueEvent(op.TableID, op.TargetMetadata, details, event)
+}
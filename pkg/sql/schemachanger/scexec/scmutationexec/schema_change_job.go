@@ -29,6 +29,7 @@ func (m *visitor) CreateSchemaChangerJob(
 		job.Authorization,
 		catalog.MakeDescriptorIDSet(job.DescriptorIDs...),
 		job.RunningStatus,
+		job.SafeMode,
 	)
 }
 
@@ -43,6 +44,17 @@ func (m *visitor) UpdateSchemaChangerJob(
 	)
 }
 
+func (m *visitor) UpdateSchemaChangeElementStatuses(
+	ctx context.Context, op scop.UpdateSchemaChangeElementStatuses,
+) error {
+	if op.JobDone {
+		m.s.DeleteSchemaChangeElementStatuses(op.JobID)
+	} else {
+		m.s.UpsertSchemaChangeElementStatuses(op.JobID, op.Transitions)
+	}
+	return nil
+}
+
 func (m *visitor) SetJobStateOnDescriptor(
 	ctx context.Context, op scop.SetJobStateOnDescriptor,
 ) error {
@@ -0,0 +1,194 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package scfmt renders the elements and the states of the declarative schema
+// changer in a stable, human-readable form, and diffs states.
+package scfmt
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scplan/scviz"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/screl"
+	"github.com/cockroachdb/errors"
+	"github.com/kylelemons/godebug/diff"
+	"gopkg.in/yaml.v2"
+)
+
+// Element returns a one-line rendering of the element: its type followed by
+// the attributes which identify it, e.g. Column:{DescID: 104, ColumnID: 2}.
+func Element(e scpb.Element) string {
+	return screl.ElementString(e)
+}
+
+// ElementDetails returns a multi-line YAML rendering of all the fields of the
+// element which aren't set to their zero value. Unlike the proto text format,
+// the fields are sorted by name and embedded structs are lifted into their
+// parent, which keeps the rendering of wide elements readable.
+func ElementDetails(e scpb.Element) (string, error) {
+	if e == nil {
+		return "", errors.AssertionFailedf("nil element")
+	}
+	m, err := scviz.ToMap(e, false /* emitDefaults */)
+	if err != nil {
+		return "", err
+	}
+	scviz.WalkMap(m, scviz.RewriteEmbeddedIntoParent)
+	out, err := yaml.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+// Target returns a one-line rendering of the target: its element followed by
+// its target status, e.g. [Column:{DescID: 104, ColumnID: 2}, PUBLIC].
+func Target(t *scpb.Target) string {
+	return fmt.Sprintf("[%s, %s]", Element(t.Element()), t.TargetStatus)
+}
+
+// State returns a multi-line rendering of the state. The targets are sorted by
+// element, such that the rendering doesn't depend on the order in which they
+// were added to the state. When verbose is set, each target is followed by
+// the details of its element.
+func State(s scpb.CurrentState, verbose bool) (string, error) {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "in rollback: %t, revertible: %t\n", s.InRollback, s.Revertible)
+	if len(s.Statements) > 0 {
+		sb.WriteString("statements:\n")
+		for i, stmt := range s.Statements {
+			fmt.Fprintf(&sb, "  - [%d] %s\n", i, stmt.RedactedStatement)
+		}
+	}
+	if len(s.Targets) > 0 {
+		sb.WriteString("targets:\n")
+	}
+	for _, i := range sortedTargets(s) {
+		fmt.Fprintf(&sb, "  - [%s, %s]\n", Target(&s.Targets[i]), s.Current[i])
+		if !verbose {
+			continue
+		}
+		details, err := ElementDetails(s.Targets[i].Element())
+		if err != nil {
+			return "", err
+		}
+		writeIndented(&sb, "      ", details)
+	}
+	return sb.String(), nil
+}
+
+// Diff returns a multi-line rendering of the changes from state a to state b,
+// or an empty string if they are equivalent. Targets are matched by element:
+// those only in a are prefixed with "-", those only in b with "+", and those
+// whose statuses or element details changed with "~". The changed details are
+// listed below the latter as "-" and "+" lines.
+func Diff(a, b scpb.CurrentState) (string, error) {
+	var sb strings.Builder
+	if a.InRollback != b.InRollback {
+		fmt.Fprintf(&sb, "~ in rollback: %t → %t\n", a.InRollback, b.InRollback)
+	}
+	if a.Revertible != b.Revertible {
+		fmt.Fprintf(&sb, "~ revertible: %t → %t\n", a.Revertible, b.Revertible)
+	}
+	removed := func(i int) {
+		fmt.Fprintf(&sb, "- [%s, %s]\n", Target(&a.Targets[i]), a.Current[i])
+	}
+	added := func(j int) {
+		fmt.Fprintf(&sb, "+ [%s, %s]\n", Target(&b.Targets[j]), b.Current[j])
+	}
+	changed := func(i, j int) error {
+		ta, tb := &a.Targets[i], &b.Targets[j]
+		da, err := ElementDetails(ta.Element())
+		if err != nil {
+			return err
+		}
+		db, err := ElementDetails(tb.Element())
+		if err != nil {
+			return err
+		}
+		if ta.TargetStatus == tb.TargetStatus && a.Current[i] == b.Current[j] && da == db {
+			return nil
+		}
+		fmt.Fprintf(&sb, "~ [[%s, %s], %s]\n", Element(tb.Element()),
+			arrow(ta.TargetStatus, tb.TargetStatus), arrow(a.Current[i], b.Current[j]))
+		if da == db {
+			return nil
+		}
+		for _, line := range strings.Split(diff.Diff(da, db), "\n") {
+			if strings.HasPrefix(line, "-") || strings.HasPrefix(line, "+") {
+				fmt.Fprintf(&sb, "    %s %s\n", line[:1], line[1:])
+			}
+		}
+		return nil
+	}
+	// Merge the targets of both states, sorted by element.
+	as, bs := sortedTargets(a), sortedTargets(b)
+	for len(as) > 0 || len(bs) > 0 {
+		switch {
+		case len(bs) == 0:
+			removed(as[0])
+			as = as[1:]
+		case len(as) == 0:
+			added(bs[0])
+			bs = bs[1:]
+		default:
+			ea, eb := a.Targets[as[0]].Element(), b.Targets[bs[0]].Element()
+			if less, eq := screl.CompareElements(ea, eb); eq {
+				if err := changed(as[0], bs[0]); err != nil {
+					return "", err
+				}
+				as, bs = as[1:], bs[1:]
+			} else if less {
+				removed(as[0])
+				as = as[1:]
+			} else {
+				added(bs[0])
+				bs = bs[1:]
+			}
+		}
+	}
+	return sb.String(), nil
+}
+
+// sortedTargets returns the indexes of the targets of the state, sorted by
+// element and then by target status.
+func sortedTargets(s scpb.CurrentState) []int {
+	idx := make([]int, len(s.Targets))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool {
+		ti, tj := &s.Targets[idx[i]], &s.Targets[idx[j]]
+		if less, eq := screl.CompareElements(ti.Element(), tj.Element()); !eq {
+			return less
+		}
+		return ti.TargetStatus < tj.TargetStatus
+	})
+	return idx
+}
+
+// arrow renders a status, or its change if the statuses differ.
+func arrow(from, to scpb.Status) string {
+	if from == to {
+		return from.String()
+	}
+	return fmt.Sprintf("%s → %s", from, to)
+}
+
+func writeIndented(sb *strings.Builder, indent, s string) {
+	for _, line := range strings.Split(s, "\n") {
+		sb.WriteString(indent)
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+}
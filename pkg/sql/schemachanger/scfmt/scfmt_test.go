@@ -0,0 +1,89 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scfmt
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat(t *testing.T) {
+	a := scpb.CurrentState{
+		TargetState: scpb.TargetState{
+			Statements: []scpb.Statement{
+				{RedactedStatement: "ALTER TABLE ‹defaultdb›.‹public›.‹t› ADD COLUMN ‹j› INT8"},
+			},
+			Targets: []scpb.Target{
+				scpb.MakeTarget(scpb.ToPublic, &scpb.Column{TableID: 104, ColumnID: 3}, nil),
+				scpb.MakeTarget(scpb.ToPublic, &scpb.Column{TableID: 104, ColumnID: 2}, nil),
+			},
+		},
+		Current:    []scpb.Status{scpb.Status_ABSENT, scpb.Status_ABSENT},
+		Revertible: true,
+	}
+	b := scpb.CurrentState{
+		TargetState: scpb.TargetState{
+			Targets: []scpb.Target{
+				scpb.MakeTarget(scpb.ToAbsent, &scpb.Column{TableID: 104, ColumnID: 4}, nil),
+				scpb.MakeTarget(scpb.ToPublic, &scpb.Column{TableID: 104, ColumnID: 2, IsHidden: true}, nil),
+			},
+		},
+		Current: []scpb.Status{scpb.Status_PUBLIC, scpb.Status_DELETE_ONLY},
+	}
+
+	// The targets are sorted by element.
+	s, err := State(a, false /* verbose */)
+	require.NoError(t, err)
+	require.Equal(t, `in rollback: false, revertible: true
+statements:
+  - [0] ALTER TABLE ‹defaultdb›.‹public›.‹t› ADD COLUMN ‹j› INT8
+targets:
+  - [[Column:{DescID: 104, ColumnID: 2}, PUBLIC], ABSENT]
+  - [[Column:{DescID: 104, ColumnID: 3}, PUBLIC], ABSENT]
+`, s)
+
+	s, err = State(b, true /* verbose */)
+	require.NoError(t, err)
+	require.Equal(t, `in rollback: false, revertible: false
+targets:
+  - [[Column:{DescID: 104, ColumnID: 2}, PUBLIC], DELETE_ONLY]
+      columnId: 2
+      isHidden: true
+      tableId: 104
+  - [[Column:{DescID: 104, ColumnID: 4}, ABSENT], PUBLIC]
+      columnId: 4
+      tableId: 104
+`, s)
+
+	d, err := Diff(a, b)
+	require.NoError(t, err)
+	require.Equal(t, `~ revertible: true → false
+~ [[Column:{DescID: 104, ColumnID: 2}, PUBLIC], ABSENT → DELETE_ONLY]
+    + isHidden: true
+- [[Column:{DescID: 104, ColumnID: 3}, PUBLIC], ABSENT]
++ [[Column:{DescID: 104, ColumnID: 4}, ABSENT], PUBLIC]
+`, d)
+
+	// Equivalent states have no diff, regardless of the order of the targets.
+	d, err = Diff(a, a.DeepCopy())
+	require.NoError(t, err)
+	require.Empty(t, d)
+	d, err = Diff(b, scpb.CurrentState{
+		TargetState: scpb.TargetState{
+			Targets: []scpb.Target{b.Targets[1], b.Targets[0]},
+		},
+		Current: []scpb.Status{b.Current[1], b.Current[0]},
+	})
+	require.NoError(t, err)
+	require.Empty(t, d)
+}
@@ -703,6 +703,49 @@ func TestSchemaChangerJobRunningStatus(t *testing.T) {
 	require.Regexp(t, "PostCommit.* pending", runningStatus1.Load().(string))
 }
 
+func TestSchemaChangerJobStageProgress(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	params, _ := tests.CreateTestServerParams()
+	params.Knobs = base.TestingKnobs{
+		JobsTestingKnobs: jobs.NewTestingKnobsWithShortIntervals(),
+	}
+
+	s, sqlDB, _ := serverutils.StartServer(t, params)
+	defer s.Stopper().Stop(ctx)
+
+	tdb := sqlutils.MakeSQLRunner(sqlDB)
+	tdb.Exec(t, `SET use_declarative_schema_changer = 'off'`)
+	tdb.Exec(t, `CREATE DATABASE db`)
+	tdb.Exec(t, `CREATE TABLE db.t (a INT PRIMARY KEY)`)
+	tdb.Exec(t, `INSERT INTO db.t SELECT generate_series(1, 10)`)
+	tdb.Exec(t, `SET use_declarative_schema_changer = 'unsafe'`)
+	tdb.Exec(t, `ALTER TABLE db.t ADD COLUMN b INT NOT NULL DEFAULT (123)`)
+
+	var jobID jobspb.JobID
+	tdb.QueryRow(t, `
+SELECT job_id
+  FROM [SHOW JOBS]
+ WHERE job_type = 'NEW SCHEMA CHANGE'
+ ORDER BY created DESC
+ LIMIT 1`).Scan(&jobID)
+
+	// All the stages of the job are completed, including the backfill.
+	results := tdb.QueryStr(t, `
+SELECT DISTINCT status, fraction_completed, estimated_completion IS NULL
+  FROM crdb_internal.schema_change_stages
+ WHERE job_id = $1`, jobID)
+	require.Equal(t, [][]string{{"completed", "1", "true"}}, results)
+	results = tdb.QueryStr(t, `
+SELECT count(*) > 0
+  FROM crdb_internal.schema_change_stages
+ WHERE job_id = $1 AND phase = 'PostCommitPhase' AND op_type = 'backfill'`, jobID)
+	require.Equal(t, [][]string{{"true"}}, results)
+	results = tdb.QueryStr(t, `SELECT fraction_completed FROM [SHOW JOBS] WHERE job_id = $1`, jobID)
+	require.Equal(t, [][]string{{"1"}}, results)
+}
+
 func TestSchemaChangerJobErrorDetails(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	ctx := context.Background()
@@ -736,10 +779,16 @@ func TestSchemaChangerJobErrorDetails(t *testing.T) {
 	tdb.ExpectErr(t, `boom`, `ALTER TABLE db.t ADD COLUMN b INT NOT NULL DEFAULT (123)`)
 	jobID := jobspb.JobID(atomic.LoadInt64(&jobIDValue))
 
-	// Check that the error is featured in the jobs table.
+	// Check that the error is featured in the jobs table, along with the
+	// history of the stage errors.
 	results := tdb.QueryStr(t, `SELECT execution_errors FROM crdb_internal.jobs WHERE job_id = $1`, jobID)
 	require.Len(t, results, 1)
-	require.Regexp(t, `^\{\"reverting execution from .* on 1 failed: boom\"\}$`, results[0][0])
+	require.Regexp(t, `^\{\"reverting execution from .* on 1 failed: boom\",\"PostCommitPhase stage \d+ of \d+ \(attempt 1\) failed at .*: boom\"`, results[0][0])
+	results = tdb.QueryStr(t, `
+SELECT phase, attempt, error
+  FROM crdb_internal.schema_change_stage_errors
+ WHERE job_id = $1 AND NOT rollback`, jobID)
+	require.Equal(t, [][]string{{"PostCommitPhase", "1", "boom"}}, results)
 
 	// Check that the error details are also featured in the jobs table.
 	checkErrWithDetails := func(ee *errorspb.EncodedError) {
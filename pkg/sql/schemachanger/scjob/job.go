@@ -66,6 +66,11 @@ func (n *newSchemaChangeResumer) run(ctx context.Context, execCtxI interface{})
 	if err := execCfg.JobRegistry.CheckPausepoint("newschemachanger.before.exec"); err != nil {
 		return err
 	}
+	if !n.rollback {
+		if err := n.checkPlacement(ctx, execCfg); err != nil {
+			return err
+		}
+	}
 	payload := n.job.Payload()
 	deps := scdeps.NewJobRunDependencies(
 		execCfg.CollectionFactory,
@@ -91,6 +96,8 @@ func (n *newSchemaChangeResumer) run(ctx context.Context, execCtxI interface{})
 			)
 		},
 		execCfg.StatsRefresher,
+		sql.NewSchemaChangerTableStatsResolver(ctx, execCfg),
+		sql.NewSchemaChangerThroughputHistory(ctx, execCfg),
 		execCfg.DeclarativeSchemaChangerTestingKnobs,
 		payload.Statement,
 		execCtx.SessionData(),
@@ -105,6 +112,7 @@ func (n *newSchemaChangeResumer) run(ctx context.Context, execCtxI interface{})
 		n.job.ID(),
 		payload.DescriptorIDs,
 		n.rollback,
+		n.job.Details().(jobspb.NewSchemaChangeDetails).SafeMode,
 	)
 	// Return permanent errors back, otherwise we will try to retry
 	if sql.IsPermanentSchemaChangeError(err) {
@@ -0,0 +1,145 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scjob
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// placementFallbackTimeout bounds the time during which a declarative schema
+// change job waits to be adopted by a node in the locality of the leaseholders
+// of the ranges it affects.
+var placementFallbackTimeout = settings.RegisterDurationSetting(
+	settings.TenantWritable,
+	"sql.schema_changer.placement_fallback_timeout",
+	"amount of time during which the post-commit stages of a declarative schema "+
+		"change are deferred to nodes in the locality of the leaseholders of the "+
+		"affected ranges, after which any node may execute them; "+
+		"set to 0 to disable locality-aware placement",
+	time.Minute,
+	settings.NonNegativeDuration,
+)
+
+// checkPlacement determines whether the current node should execute the
+// post-commit stages of the schema change according to the placement hint of
+// the job, which is first set if missing. If another node should execute them
+// instead, an error requesting the release of the job's claim is returned.
+func (n *newSchemaChangeResumer) checkPlacement(
+	ctx context.Context, execCfg *sql.ExecutorConfig,
+) error {
+	timeout := placementFallbackTimeout.Get(&execCfg.Settings.SV)
+	if timeout == 0 {
+		return nil
+	}
+	hint := n.job.Details().(jobspb.NewSchemaChangeDetails).PlacementHint
+	if hint == nil {
+		var err error
+		if hint, err = n.setPlacementHint(ctx, execCfg, timeout); err != nil {
+			// The hint is merely an optimization, don't let it get in the way.
+			log.Warningf(ctx, "failed to set placement hint: %v", err)
+			return nil
+		}
+	}
+	metrics := execCfg.SchemaChangerMetrics
+	switch {
+	case localityMatches(execCfg.Locality, hint.Locality):
+		metrics.PlacementPreferred.Inc(1)
+	case timeutil.Now().UnixNano() >= hint.FallbackAfter:
+		log.Infof(ctx, "executing outside of preferred locality %s", hint.Locality)
+		metrics.PlacementFallback.Inc(1)
+	default:
+		metrics.PlacementDeferred.Inc(1)
+		return jobs.MarkAsReleaseClaimError(errors.Newf(
+			"deferring execution to a node in locality %s", hint.Locality,
+		))
+	}
+	return nil
+}
+
+// setPlacementHint computes the placement hint from the leaseholders of the
+// ranges of the tables affected by the schema change and stores it in the job.
+func (n *newSchemaChangeResumer) setPlacementHint(
+	ctx context.Context, execCfg *sql.ExecutorConfig, timeout time.Duration,
+) (*jobspb.PlacementHint, error) {
+	var spans []roachpb.Span
+	if err := sql.DescsTxn(ctx, execCfg, func(
+		ctx context.Context, txn *kv.Txn, col *descs.Collection,
+	) error {
+		spans = spans[:0]
+		ds, err := col.GetImmutableDescriptorsByID(ctx, txn, tree.CommonLookupFlags{
+			Required:       true,
+			AvoidLeased:    true,
+			IncludeOffline: true,
+			IncludeDropped: true,
+		}, n.job.Payload().DescriptorIDs...)
+		if err != nil {
+			return err
+		}
+		for _, desc := range ds {
+			if tbl, ok := desc.(catalog.TableDescriptor); ok && tbl.IsPhysicalTable() {
+				spans = append(spans, tbl.TableSpan(execCfg.Codec))
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	locality, err := sql.LeaseholderLocality(ctx, execCfg.DB, execCfg.DistSQLPlanner, spans)
+	if err != nil {
+		return nil, err
+	}
+	hint := &jobspb.PlacementHint{
+		Locality:      locality,
+		FallbackAfter: timeutil.Now().Add(timeout).UnixNano(),
+	}
+	if err := n.job.Update(ctx, nil /* txn */, func(
+		txn *kv.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater,
+	) error {
+		details := md.Payload.GetNewSchemaChange()
+		if details == nil {
+			return errors.AssertionFailedf("job %d: expected new schema change details", n.job.ID())
+		}
+		details.PlacementHint = hint
+		ju.UpdatePayload(md.Payload)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return hint, nil
+}
+
+// localityMatches returns whether the locality lies within that of the
+// placement hint.
+func localityMatches(l, hint roachpb.Locality) bool {
+	if len(hint.Tiers) > len(l.Tiers) {
+		return false
+	}
+	for i, t := range hint.Tiers {
+		if l.Tiers[i] != t {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,179 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package sclegacy translates simple declarative schema changes into the
+// format of the legacy schema changer: mutations enqueued on a table
+// descriptor, processed by a legacy schema change job.
+//
+// This exists to ease rolling upgrades: the declarative schema change jobs
+// created by nodes running the new version may not be adopted by nodes
+// still running the previous version, whereas the legacy schema change jobs
+// can be adopted by any node. Only a small subset of the declarative schema
+// changes, for which the equivalence with the legacy schema changer is
+// straightforward, can be exported.
+package sclegacy
+
+import (
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scerrors"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/screl"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/redact"
+)
+
+// ErrNotExportable is returned, wrapped, by Export when the schema change
+// cannot be translated into the format of the legacy schema changer.
+var ErrNotExportable = errors.New("schema change cannot be exported to the legacy schema changer")
+
+// Plan is a declarative schema change translated into the format of the
+// legacy schema changer.
+type Plan struct {
+	// TableID is the ID of the table descriptor on which the mutations
+	// are enqueued.
+	TableID descpb.ID
+
+	// DroppedIndexID is the ID of the secondary index dropped by the
+	// schema change.
+	DroppedIndexID descpb.IndexID
+
+	// Statements are the statements of the schema change, used to describe
+	// the legacy schema change job.
+	Statements []scpb.Statement
+
+	// Authorization is the authorization of the user performing the schema
+	// change, who owns the legacy schema change job.
+	Authorization scpb.Authorization
+}
+
+// Export translates the declarative schema change described by the current
+// state into the format of the legacy schema changer, provided that none of
+// its stages have been executed yet.
+//
+// The only schema changes supported at this point are those dropping a
+// single secondary index, without any dependent objects, comments or zone
+// configs, or sharding or expression columns. Any other schema change
+// results in an error wrapping ErrNotExportable.
+func Export(cs scpb.CurrentState) (*Plan, error) {
+	if len(cs.Targets) == 0 {
+		return nil, errors.Wrap(ErrNotExportable, "no targets")
+	}
+	if cs.InRollback {
+		return nil, errors.Wrap(ErrNotExportable, "schema change is being rolled back")
+	}
+	var idx *scpb.SecondaryIndex
+	for i, t := range cs.Targets {
+		e := t.Element()
+		if scpb.AsTargetStatus(t.TargetStatus) != scpb.ToAbsent {
+			return nil, errors.Wrapf(ErrNotExportable,
+				"unsupported target status %s for %s", t.TargetStatus, screl.ElementString(e))
+		}
+		if cs.Current[i] != scpb.Status_PUBLIC {
+			return nil, errors.Wrapf(ErrNotExportable,
+				"schema change already in progress for %s", screl.ElementString(e))
+		}
+		switch e := e.(type) {
+		case *scpb.SecondaryIndex:
+			if idx != nil {
+				return nil, errors.Wrap(ErrNotExportable, "more than one index is dropped")
+			}
+			if e.Sharding != nil && e.Sharding.IsSharded {
+				return nil, errors.Wrap(ErrNotExportable, "hash-sharded indexes are not supported")
+			}
+			idx = e
+		case *scpb.IndexName, *scpb.IndexColumn, *scpb.SecondaryIndexPartial, *scpb.IndexPartitioning:
+			// These are dropped along with the index descriptor.
+		default:
+			return nil, errors.Wrapf(ErrNotExportable,
+				"unsupported element %s", screl.ElementString(e))
+		}
+	}
+	if idx == nil {
+		return nil, errors.Wrap(ErrNotExportable, "no index is dropped")
+	}
+	// All the elements must belong to the dropped index.
+	for _, t := range cs.Targets {
+		e := t.Element()
+		if id, ok := screl.GetIndexID(e); screl.GetDescID(e) != idx.TableID || !ok || id != idx.IndexID {
+			return nil, errors.Wrapf(ErrNotExportable,
+				"element %s does not belong to the dropped index", screl.ElementString(e))
+		}
+	}
+	return &Plan{
+		TableID:        idx.TableID,
+		DroppedIndexID: idx.IndexID,
+		Statements:     cs.Statements,
+		Authorization:  cs.Authorization,
+	}, nil
+}
+
+// Apply enqueues the mutations of the plan on the table descriptor, as the
+// legacy schema changer would have done, and returns the record of the
+// legacy schema change job which processes them.
+func (p *Plan) Apply(
+	codec keys.SQLCodec, tbl *tabledesc.Mutable, jobID jobspb.JobID,
+) (*jobs.Record, error) {
+	if tbl.GetID() != p.TableID {
+		return nil, errors.AssertionFailedf(
+			"expected table descriptor %d, got %d", p.TableID, tbl.GetID())
+	}
+	if tbl.GetDeclarativeSchemaChangerState() != nil {
+		return nil, scerrors.ConcurrentSchemaChangeError(tbl)
+	}
+	idx := catalog.FindPublicNonPrimaryIndex(tbl, func(candidate catalog.Index) bool {
+		return candidate.GetID() == p.DroppedIndexID
+	})
+	if idx == nil {
+		return nil, errors.Wrapf(ErrNotExportable,
+			"index %d of table %d is not a public secondary index", p.DroppedIndexID, p.TableID)
+	}
+	idxDesc := idx.IndexDescDeepCopy()
+	if err := tbl.AddDropIndexMutation(&idxDesc); err != nil {
+		return nil, err
+	}
+	tbl.RemovePublicNonPrimaryIndex(idx.Ordinal())
+	mutationID := tbl.ClusterVersion().NextMutationID
+	tbl.MutationJobs = append(tbl.MutationJobs, descpb.TableDescriptor_MutationJob{
+		MutationID: mutationID, JobID: jobID,
+	})
+
+	stmtStrs := make([]string, len(p.Statements))
+	for i, stmt := range p.Statements {
+		// As for the declarative schema change jobs, use the redactable
+		// string because it's been normalized and fully-qualified.
+		stmtStrs[i] = redact.RedactableString(stmt.RedactedStatement).StripMarkers()
+	}
+	return &jobs.Record{
+		JobID:         jobID,
+		Description:   strings.Join(stmtStrs, "; "),
+		Username:      username.MakeSQLUsernameFromPreNormalizedString(p.Authorization.UserName),
+		DescriptorIDs: descpb.IDs{tbl.GetID()},
+		Details: jobspb.SchemaChangeDetails{
+			DescID:          tbl.GetID(),
+			TableMutationID: mutationID,
+			ResumeSpanList: []jobspb.ResumeSpanList{{
+				ResumeSpans: []roachpb.Span{tbl.PrimaryIndexSpan(codec)},
+			}},
+			// The version distinction for database jobs doesn't matter for
+			// jobs on tables.
+			FormatVersion: jobspb.DatabaseJobFormatVersion,
+		},
+		Progress: jobspb.SchemaChangeProgress{},
+	}, nil
+}
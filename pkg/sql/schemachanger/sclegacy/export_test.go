@@ -0,0 +1,184 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sclegacy
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// makeDropIndexState returns the state of a declarative DROP INDEX of the
+// index 2 of the table 104, before any of its stages are executed.
+func makeDropIndexState(extra ...scpb.Element) scpb.CurrentState {
+	elts := append([]scpb.Element{
+		&scpb.SecondaryIndex{Index: scpb.Index{TableID: 104, IndexID: 2, IsCreatedExplicitly: true}},
+		&scpb.IndexName{TableID: 104, IndexID: 2, Name: "idx"},
+		&scpb.IndexColumn{TableID: 104, IndexID: 2, ColumnID: 2},
+		&scpb.IndexColumn{TableID: 104, IndexID: 2, ColumnID: 1, Kind: scpb.IndexColumn_KEY_SUFFIX},
+	}, extra...)
+	cs := scpb.CurrentState{
+		TargetState: scpb.TargetState{
+			Statements: []scpb.Statement{{
+				Statement:         "DROP INDEX t@idx",
+				RedactedStatement: "DROP INDEX ‹defaultdb›.‹public›.‹t›@‹idx›",
+				StatementTag:      "DROP INDEX",
+			}},
+			Authorization: scpb.Authorization{UserName: "root"},
+		},
+	}
+	for _, e := range elts {
+		cs.Targets = append(cs.Targets, scpb.MakeTarget(scpb.ToAbsent, e, nil /* metadata */))
+		cs.Current = append(cs.Current, scpb.Status_PUBLIC)
+	}
+	return cs
+}
+
+func makeTable() *tabledesc.Mutable {
+	return tabledesc.NewBuilder(&descpb.TableDescriptor{
+		ID:       104,
+		Name:     "t",
+		ParentID: 100,
+		Version:  1,
+		Columns: []descpb.ColumnDescriptor{
+			{ID: 1, Name: "k", Type: types.Int},
+			{ID: 2, Name: "v", Type: types.Int, Nullable: true},
+		},
+		NextColumnID: 3,
+		PrimaryIndex: descpb.IndexDescriptor{
+			ID:                  1,
+			Name:                "t_pkey",
+			Unique:              true,
+			KeyColumnNames:      []string{"k"},
+			KeyColumnIDs:        []descpb.ColumnID{1},
+			KeyColumnDirections: []catpb.IndexColumn_Direction{catpb.IndexColumn_ASC},
+		},
+		Indexes: []descpb.IndexDescriptor{{
+			ID:                  2,
+			Name:                "idx",
+			KeyColumnNames:      []string{"v"},
+			KeyColumnIDs:        []descpb.ColumnID{2},
+			KeyColumnDirections: []catpb.IndexColumn_Direction{catpb.IndexColumn_ASC},
+			KeySuffixColumnIDs:  []descpb.ColumnID{1},
+		}},
+		NextIndexID:    3,
+		NextMutationID: 1,
+	}).BuildExistingMutableTable()
+}
+
+func TestExportDropIndex(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	p, err := Export(makeDropIndexState())
+	require.NoError(t, err)
+	require.Equal(t, descpb.ID(104), p.TableID)
+	require.Equal(t, descpb.IndexID(2), p.DroppedIndexID)
+
+	tbl := makeTable()
+	rec, err := p.Apply(keys.SystemSQLCodec, tbl, 42)
+	require.NoError(t, err)
+
+	// The index is moved to a drop mutation, as the legacy schema changer
+	// would have done it.
+	require.Empty(t, tbl.PublicNonPrimaryIndexes())
+	require.Len(t, tbl.Mutations, 1)
+	m := tbl.Mutations[0]
+	require.Equal(t, descpb.IndexID(2), m.GetIndex().ID)
+	require.Equal(t, descpb.DescriptorMutation_DROP, m.Direction)
+	require.Equal(t, descpb.DescriptorMutation_DELETE_AND_WRITE_ONLY, m.State)
+	require.Equal(t, descpb.MutationID(1), m.MutationID)
+	require.Equal(t, []descpb.TableDescriptor_MutationJob{{MutationID: 1, JobID: 42}}, tbl.MutationJobs)
+
+	require.Equal(t, jobspb.JobID(42), rec.JobID)
+	require.Equal(t, "DROP INDEX defaultdb.public.t@idx", rec.Description)
+	require.Equal(t, "root", rec.Username.Normalized())
+	require.Equal(t, descpb.IDs{104}, rec.DescriptorIDs)
+	details := rec.Details.(jobspb.SchemaChangeDetails)
+	require.Equal(t, descpb.ID(104), details.DescID)
+	require.Equal(t, descpb.MutationID(1), details.TableMutationID)
+	require.Len(t, details.ResumeSpanList, 1)
+	require.Equal(t, tbl.PrimaryIndexSpan(keys.SystemSQLCodec), details.ResumeSpanList[0].ResumeSpans[0])
+}
+
+func TestExportUnsupported(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, tc := range []struct {
+		name string
+		cs   func() scpb.CurrentState
+	}{
+		{
+			name: "no targets",
+			cs:   func() scpb.CurrentState { return scpb.CurrentState{} },
+		},
+		{
+			name: "comment",
+			cs: func() scpb.CurrentState {
+				return makeDropIndexState(&scpb.IndexComment{TableID: 104, IndexID: 2, Comment: "c"})
+			},
+		},
+		{
+			name: "other index",
+			cs: func() scpb.CurrentState {
+				return makeDropIndexState(&scpb.IndexName{TableID: 104, IndexID: 3, Name: "other"})
+			},
+		},
+		{
+			name: "sharded",
+			cs: func() scpb.CurrentState {
+				cs := makeDropIndexState()
+				cs.Targets[0] = scpb.MakeTarget(scpb.ToAbsent, &scpb.SecondaryIndex{Index: scpb.Index{
+					TableID: 104, IndexID: 2, Sharding: &catpb.ShardedDescriptor{IsSharded: true},
+				}}, nil /* metadata */)
+				return cs
+			},
+		},
+		{
+			name: "in progress",
+			cs: func() scpb.CurrentState {
+				cs := makeDropIndexState()
+				cs.Current[0] = scpb.Status_VALIDATED
+				return cs
+			},
+		},
+		{
+			name: "rollback",
+			cs: func() scpb.CurrentState {
+				cs := makeDropIndexState()
+				cs.InRollback = true
+				return cs
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Export(tc.cs())
+			require.True(t, errors.Is(err, ErrNotExportable), "%v", err)
+		})
+	}
+
+	// The index must still be a public secondary index when the plan is
+	// applied.
+	p, err := Export(makeDropIndexState())
+	require.NoError(t, err)
+	tbl := makeTable()
+	tbl.Indexes = nil
+	_, err = p.Apply(keys.SystemSQLCodec, tbl, 42)
+	require.True(t, errors.Is(err, ErrNotExportable), "%v", err)
+}
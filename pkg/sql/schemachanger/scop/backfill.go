@@ -20,12 +20,20 @@ type backfillOp struct{ baseOp }
 // Type implements the Op interface.
 func (backfillOp) Type() Type { return BackfillType }
 
+// CostClass implements the Op interface.
+func (backfillOp) CostClass() CostClass { return BulkWriteCost }
+
 // BackfillIndex specifies an index backfill operation.
 type BackfillIndex struct {
 	backfillOp
 	TableID       descpb.ID
 	SourceIndexID descpb.IndexID
 	IndexID       descpb.IndexID
+
+	// Sequential is set when the backfill is expected to be large enough that
+	// it should not run concurrently with the other backfills and merges of
+	// its stage.
+	Sequential bool
 }
 
 // MergeIndex specifies an index merge operation.
@@ -10,6 +10,11 @@
 
 package scop
 
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+)
+
 // StatementForDropJob is a statement used to build a description for a
 // drop job. The set of statements associated with the drop job will
 // be accumulated for the description.
@@ -27,3 +32,21 @@ type StatementForDropJob struct {
 	// job.
 	Rollback bool
 }
+
+// ElementStatusTransition is the transition of an element targeted by a
+// schema change from one status to another.
+type ElementStatusTransition struct {
+
+	// ElementID identifies the element among the targets of the schema change.
+	ElementID int
+
+	// DescID is the ID of the descriptor to which the element belongs.
+	DescID descpb.ID
+
+	// Element is the string representation of the element.
+	Element string
+
+	// From and To are the statuses of the element before and after the
+	// transition.
+	From, To scpb.Status
+}
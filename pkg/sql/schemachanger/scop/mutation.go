@@ -35,11 +35,19 @@ var _ = mutationOp{baseOp: baseOp{}}
 
 func (mutationOp) Type() Type { return MutationType }
 
+// CostClass implements the Op interface.
+func (mutationOp) CostClass() CostClass { return MetadataOnlyCost }
+
 // NotImplemented is a placeholder for operations which haven't been defined yet.
+// It does nothing when executed, but the planner reports the transitions for
+// which it gets emitted in the annotations of the plan. Reason explains why
+// the transition has no operations, in particular when it is intentionally
+// a no-op.
 // TODO(postamar): remove all of these
 type NotImplemented struct {
 	mutationOp
 	ElementType string
+	Reason      string
 }
 
 // MakeAddedTempIndexDeleteOnly adds a non-existent index to the
@@ -117,6 +125,15 @@ type MakeAddedPrimaryIndexPublic struct {
 	IndexID descpb.IndexID
 }
 
+// CopyIndexZoneConfig copies the zone configurations of an index and of its
+// partitions, if any, to the index which replaces it.
+type CopyIndexZoneConfig struct {
+	mutationOp
+	TableID       descpb.ID
+	SourceIndexID descpb.IndexID
+	IndexID       descpb.IndexID
+}
+
 // MakeDroppedPrimaryIndexDeleteAndWriteOnly moves a dropped primary index from
 // public to DELETE_AND_WRITE_ONLY.
 type MakeDroppedPrimaryIndexDeleteAndWriteOnly struct {
@@ -139,6 +156,15 @@ type CreateGcJobForDatabase struct {
 	StatementForDropJob
 }
 
+// SetDroppedIndexGCTTL overrides the GC TTL of a dropped index so that its
+// data can be reclaimed ahead of that of its table.
+type SetDroppedIndexGCTTL struct {
+	mutationOp
+	TableID    descpb.ID
+	IndexID    descpb.IndexID
+	TTLSeconds int32
+}
+
 // CreateGcJobForIndex creates a GC job for a given table index.
 type CreateGcJobForIndex struct {
 	mutationOp
@@ -324,6 +350,37 @@ type LogEvent struct {
 	TargetStatus scpb.Status
 }
 
+// LogSchemaChangeStageEvent logs an event reporting that a post-commit stage
+// of a schema change job begins or completes.
+type LogSchemaChangeStageEvent struct {
+	mutationOp
+	JobID         jobspb.JobID
+	Authorization scpb.Authorization
+	Phase         Phase
+	StageOrdinal  int
+	StagesInPhase int
+	InRollback    bool
+	// Completed is false when the stage begins and true when it completes.
+	Completed bool
+	// NumOps is the number of ops in the stage.
+	NumOps int
+	// ElementTransitions describes the status transitions of the elements in
+	// the stage.
+	ElementTransitions []string
+}
+
+// UpdateSchemaChangeElementStatuses records the status transitions of the
+// elements targeted by a schema change job in the
+// system.schema_change_element_statuses table.
+type UpdateSchemaChangeElementStatuses struct {
+	mutationOp
+	JobID       jobspb.JobID
+	Transitions []ElementStatusTransition
+	// JobDone is set in the last stage of the job, in which case the records
+	// pertaining to the job are deleted instead.
+	JobDone bool
+}
+
 // AddColumnFamily adds a new column family to the table.
 type AddColumnFamily struct {
 	mutationOp
@@ -458,6 +515,10 @@ type CreateSchemaChangerJob struct {
 	// be thought of as !Revertible.
 	NonCancelable bool
 	RunningStatus string
+
+	// SafeMode records whether the stages were planned in safe mode, so that
+	// the job plans its remaining stages in the same way.
+	SafeMode bool
 }
 
 // UpsertTableComment is used to add a comment to a table.
@@ -567,6 +628,40 @@ type RemoveUserPrivileges struct {
 	User   string
 }
 
+// UpsertColumnPrivileges is used to set a user's privileges on a column.
+type UpsertColumnPrivileges struct {
+	mutationOp
+	TableID    descpb.ID
+	ColumnID   descpb.ColumnID
+	User       string
+	Privileges uint32
+}
+
+// RemoveColumnPrivileges is used to revoke a user's privileges on a column.
+// The privileges are only removed if they are still the ones specified, as
+// they may have been replaced by an UpsertColumnPrivileges op in the same
+// stage.
+type RemoveColumnPrivileges struct {
+	mutationOp
+	TableID    descpb.ID
+	ColumnID   descpb.ColumnID
+	User       string
+	Privileges uint32
+}
+
+// LogColumnPrivilegesEvent logs a single event for the column-level
+// privileges granted to or revoked from a user on the columns of a table by
+// a statement.
+type LogColumnPrivilegesEvent struct {
+	mutationOp
+	EventBase
+	TableID           descpb.ID
+	Grantee           string
+	ColumnIDs         []descpb.ColumnID
+	GrantedPrivileges uint32
+	RevokedPrivileges uint32
+}
+
 // DeleteSchedule is used to delete a schedule ID from the database.
 type DeleteSchedule struct {
 	mutationOp
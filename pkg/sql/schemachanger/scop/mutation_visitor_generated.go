@@ -32,9 +32,11 @@ type MutationVisitor interface {
 	MakeBackfillingIndexDeleteOnly(context.Context, MakeBackfillingIndexDeleteOnly) error
 	MakeAddedSecondaryIndexPublic(context.Context, MakeAddedSecondaryIndexPublic) error
 	MakeAddedPrimaryIndexPublic(context.Context, MakeAddedPrimaryIndexPublic) error
+	CopyIndexZoneConfig(context.Context, CopyIndexZoneConfig) error
 	MakeDroppedPrimaryIndexDeleteAndWriteOnly(context.Context, MakeDroppedPrimaryIndexDeleteAndWriteOnly) error
 	CreateGcJobForTable(context.Context, CreateGcJobForTable) error
 	CreateGcJobForDatabase(context.Context, CreateGcJobForDatabase) error
+	SetDroppedIndexGCTTL(context.Context, SetDroppedIndexGCTTL) error
 	CreateGcJobForIndex(context.Context, CreateGcJobForIndex) error
 	MarkDescriptorAsPublic(context.Context, MarkDescriptorAsPublic) error
 	MarkDescriptorAsOffline(context.Context, MarkDescriptorAsOffline) error
@@ -60,6 +62,8 @@ type MutationVisitor interface {
 	RemoveSchemaParent(context.Context, RemoveSchemaParent) error
 	AddIndexPartitionInfo(context.Context, AddIndexPartitionInfo) error
 	LogEvent(context.Context, LogEvent) error
+	LogSchemaChangeStageEvent(context.Context, LogSchemaChangeStageEvent) error
+	UpdateSchemaChangeElementStatuses(context.Context, UpdateSchemaChangeElementStatuses) error
 	AddColumnFamily(context.Context, AddColumnFamily) error
 	AddColumnDefaultExpression(context.Context, AddColumnDefaultExpression) error
 	RemoveColumnDefaultExpression(context.Context, RemoveColumnDefaultExpression) error
@@ -91,6 +95,9 @@ type MutationVisitor interface {
 	RemoveConstraintComment(context.Context, RemoveConstraintComment) error
 	RemoveDatabaseRoleSettings(context.Context, RemoveDatabaseRoleSettings) error
 	RemoveUserPrivileges(context.Context, RemoveUserPrivileges) error
+	UpsertColumnPrivileges(context.Context, UpsertColumnPrivileges) error
+	RemoveColumnPrivileges(context.Context, RemoveColumnPrivileges) error
+	LogColumnPrivilegesEvent(context.Context, LogColumnPrivilegesEvent) error
 	DeleteSchedule(context.Context, DeleteSchedule) error
 	RefreshStats(context.Context, RefreshStats) error
 	AddColumnToIndex(context.Context, AddColumnToIndex) error
@@ -147,6 +154,11 @@ func (op MakeAddedPrimaryIndexPublic) Visit(ctx context.Context, v MutationVisit
 	return v.MakeAddedPrimaryIndexPublic(ctx, op)
 }
 
+// Visit is part of the MutationOp interface.
+func (op CopyIndexZoneConfig) Visit(ctx context.Context, v MutationVisitor) error {
+	return v.CopyIndexZoneConfig(ctx, op)
+}
+
 // Visit is part of the MutationOp interface.
 func (op MakeDroppedPrimaryIndexDeleteAndWriteOnly) Visit(ctx context.Context, v MutationVisitor) error {
 	return v.MakeDroppedPrimaryIndexDeleteAndWriteOnly(ctx, op)
@@ -162,6 +174,11 @@ func (op CreateGcJobForDatabase) Visit(ctx context.Context, v MutationVisitor) e
 	return v.CreateGcJobForDatabase(ctx, op)
 }
 
+// Visit is part of the MutationOp interface.
+func (op SetDroppedIndexGCTTL) Visit(ctx context.Context, v MutationVisitor) error {
+	return v.SetDroppedIndexGCTTL(ctx, op)
+}
+
 // Visit is part of the MutationOp interface.
 func (op CreateGcJobForIndex) Visit(ctx context.Context, v MutationVisitor) error {
 	return v.CreateGcJobForIndex(ctx, op)
@@ -287,6 +304,16 @@ func (op LogEvent) Visit(ctx context.Context, v MutationVisitor) error {
 	return v.LogEvent(ctx, op)
 }
 
+// Visit is part of the MutationOp interface.
+func (op LogSchemaChangeStageEvent) Visit(ctx context.Context, v MutationVisitor) error {
+	return v.LogSchemaChangeStageEvent(ctx, op)
+}
+
+// Visit is part of the MutationOp interface.
+func (op UpdateSchemaChangeElementStatuses) Visit(ctx context.Context, v MutationVisitor) error {
+	return v.UpdateSchemaChangeElementStatuses(ctx, op)
+}
+
 // Visit is part of the MutationOp interface.
 func (op AddColumnFamily) Visit(ctx context.Context, v MutationVisitor) error {
 	return v.AddColumnFamily(ctx, op)
@@ -442,6 +469,21 @@ func (op RemoveUserPrivileges) Visit(ctx context.Context, v MutationVisitor) err
 	return v.RemoveUserPrivileges(ctx, op)
 }
 
+// Visit is part of the MutationOp interface.
+func (op UpsertColumnPrivileges) Visit(ctx context.Context, v MutationVisitor) error {
+	return v.UpsertColumnPrivileges(ctx, op)
+}
+
+// Visit is part of the MutationOp interface.
+func (op RemoveColumnPrivileges) Visit(ctx context.Context, v MutationVisitor) error {
+	return v.RemoveColumnPrivileges(ctx, op)
+}
+
+// Visit is part of the MutationOp interface.
+func (op LogColumnPrivilegesEvent) Visit(ctx context.Context, v MutationVisitor) error {
+	return v.LogColumnPrivilegesEvent(ctx, op)
+}
+
 // Visit is part of the MutationOp interface.
 func (op DeleteSchedule) Visit(ctx context.Context, v MutationVisitor) error {
 	return v.DeleteSchedule(ctx, op)
@@ -13,6 +13,7 @@ package scop
 // Op represents an action to be taken on a single descriptor.
 type Op interface {
 	Type() Type
+	CostClass() CostClass
 }
 
 // Type represents the type of operation for an Op. Ops can be grouped into the
@@ -32,4 +33,33 @@ const (
 	ValidationType
 )
 
+// CostClass represents the kind of work performed by an Op, which determines
+// how its execution is regulated by admission control.
+type CostClass int
+
+//go:generate stringer -type=CostClass
+
+const (
+	_ CostClass = iota
+	// MetadataOnlyCost represents ops which only read and write descriptors and
+	// other system table rows, the cost of which doesn't depend on the size of
+	// the data.
+	MetadataOnlyCost
+	// BulkReadCost represents ops which scan table data, like validations.
+	BulkReadCost
+	// BulkWriteCost represents ops which write table data, like backfills.
+	BulkWriteCost
+)
+
+// CostClassOf returns the most expensive cost class among the given ops.
+func CostClassOf(ops []Op) CostClass {
+	c := MetadataOnlyCost
+	for _, op := range ops {
+		if oc := op.CostClass(); oc > c {
+			c = oc
+		}
+	}
+	return c
+}
+
 type baseOp struct{}
@@ -0,0 +1,93 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scop_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOpTypes checks that every op visited by one of the generated visitors
+// reports the corresponding op type. The executor dispatches the ops on their
+// type, and then executes them using an implementation of the visitor for that
+// type, which the compiler checks to handle every op. Together, these ensure
+// that every op has an executor implementation.
+func TestOpTypes(t *testing.T) {
+	for _, tc := range []struct {
+		typ     scop.Type
+		cost    scop.CostClass
+		op      reflect.Type
+		visitor reflect.Type
+	}{
+		{
+			typ:     scop.MutationType,
+			cost:    scop.MetadataOnlyCost,
+			op:      reflect.TypeOf((*scop.MutationOp)(nil)).Elem(),
+			visitor: reflect.TypeOf((*scop.MutationVisitor)(nil)).Elem(),
+		},
+		{
+			typ:     scop.BackfillType,
+			cost:    scop.BulkWriteCost,
+			op:      reflect.TypeOf((*scop.BackfillOp)(nil)).Elem(),
+			visitor: reflect.TypeOf((*scop.BackfillVisitor)(nil)).Elem(),
+		},
+		{
+			typ:     scop.ValidationType,
+			cost:    scop.BulkReadCost,
+			op:      reflect.TypeOf((*scop.ValidationOp)(nil)).Elem(),
+			visitor: reflect.TypeOf((*scop.ValidationVisitor)(nil)).Elem(),
+		},
+	} {
+		t.Run(tc.typ.String(), func(t *testing.T) {
+			require.NotZero(t, tc.visitor.NumMethod())
+			for i := 0; i < tc.visitor.NumMethod(); i++ {
+				m := tc.visitor.Method(i)
+				// The visitor methods take the context and the op as arguments.
+				require.Equal(t, 2, m.Type.NumIn(), m.Name)
+				opType := m.Type.In(1)
+				require.Equal(t, m.Name, opType.Name())
+				op, ok := reflect.New(opType).Interface().(scop.Op)
+				require.Truef(t, ok, "%s does not implement scop.Op", opType)
+				require.Equalf(t, tc.typ, op.Type(), "unexpected type for %s", opType)
+				require.Equalf(t, tc.cost, op.CostClass(), "unexpected cost class for %s", opType)
+				require.Truef(t, reflect.PtrTo(opType).Implements(tc.op),
+					"%s does not implement %s", opType, tc.op)
+				for _, other := range []reflect.Type{
+					reflect.TypeOf((*scop.MutationOp)(nil)).Elem(),
+					reflect.TypeOf((*scop.BackfillOp)(nil)).Elem(),
+					reflect.TypeOf((*scop.ValidationOp)(nil)).Elem(),
+				} {
+					if other != tc.op {
+						require.Falsef(t, reflect.PtrTo(opType).Implements(other),
+							"%s also implements %s", opType, other)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCostClassOf(t *testing.T) {
+	require.Equal(t, scop.MetadataOnlyCost, scop.CostClassOf(nil))
+	require.Equal(t, scop.MetadataOnlyCost, scop.CostClassOf([]scop.Op{
+		&scop.MakeAddedIndexBackfilling{}, &scop.SetJobStateOnDescriptor{},
+	}))
+	require.Equal(t, scop.BulkReadCost, scop.CostClassOf([]scop.Op{
+		&scop.SetJobStateOnDescriptor{}, &scop.ValidateUniqueIndex{},
+	}))
+	require.Equal(t, scop.BulkWriteCost, scop.CostClassOf([]scop.Op{
+		&scop.ValidateUniqueIndex{}, &scop.BackfillIndex{}, &scop.MergeIndex{},
+	}))
+	require.Equal(t, "BulkWriteCost", scop.BulkWriteCost.String())
+}
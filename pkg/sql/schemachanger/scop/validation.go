@@ -18,6 +18,9 @@ type validationOp struct{ baseOp }
 
 func (validationOp) Type() Type { return ValidationType }
 
+// CostClass implements the Op interface.
+func (validationOp) CostClass() CostClass { return BulkReadCost }
+
 // ValidateUniqueIndex validates uniqueness of entries for a unique index.
 type ValidateUniqueIndex struct {
 	validationOp
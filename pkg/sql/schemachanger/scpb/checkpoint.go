@@ -0,0 +1,52 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scpb
+
+import "github.com/cockroachdb/errors"
+
+// CheckpointVersion is the version of the format of the checkpoints written
+// by this binary. See Checkpoint.
+const CheckpointVersion = 1
+
+// MakeCheckpoint returns the checkpoint of the given state, once the given
+// number of post-commit stages have been completed.
+func MakeCheckpoint(s CurrentState, completedStages int64) Checkpoint {
+	s = s.DeepCopy()
+	return Checkpoint{
+		Version:         CheckpointVersion,
+		TargetState:     s.TargetState,
+		Current:         s.Current,
+		InRollback:      s.InRollback,
+		Revertible:      s.Revertible,
+		CompletedStages: completedStages,
+	}
+}
+
+// CurrentState returns the state stored in the checkpoint. It returns an error
+// if the checkpoint isn't in the format written by this binary.
+func (c *Checkpoint) CurrentState() (CurrentState, error) {
+	if c.Version != CheckpointVersion {
+		return CurrentState{}, errors.Newf(
+			"unsupported checkpoint version %d, expected %d", c.Version, CheckpointVersion,
+		)
+	}
+	if len(c.Current) != len(c.TargetState.Targets) {
+		return CurrentState{}, errors.AssertionFailedf(
+			"checkpoint has %d statuses for %d targets", len(c.Current), len(c.TargetState.Targets),
+		)
+	}
+	return CurrentState{
+		TargetState: c.TargetState,
+		Current:     c.Current,
+		InRollback:  c.InRollback,
+		Revertible:  c.Revertible,
+	}.DeepCopy(), nil
+}
@@ -265,6 +265,37 @@ func FindColumnOnUpdateExpression(b ElementStatusIterator) (current Status, targ
 	return current, target, element
 }
 
+func (e ColumnPrivileges) element() {}
+
+// ForEachColumnPrivileges iterates over elements of type ColumnPrivileges.
+func ForEachColumnPrivileges(
+	b ElementStatusIterator, fn func(current Status, target TargetStatus, e *ColumnPrivileges),
+) {
+  if b == nil {
+    return
+  }
+	b.ForEachElementStatus(func(current Status, target TargetStatus, e Element) {
+		if elt, ok := e.(*ColumnPrivileges); ok {
+			fn(current, target, elt)
+		}
+	})
+}
+
+// FindColumnPrivileges finds the first element of type ColumnPrivileges.
+func FindColumnPrivileges(b ElementStatusIterator) (current Status, target TargetStatus, element *ColumnPrivileges) {
+  if b == nil {
+    return current, target, element
+  }
+	b.ForEachElementStatus(func(c Status, t TargetStatus, e Element) {
+		if elt, ok := e.(*ColumnPrivileges); ok {
+			element = elt
+			current = c
+			target = t
+		}
+	})
+	return current, target, element
+}
+
 func (e ColumnType) element() {}
 
 // ForEachColumnType iterates over elements of type ColumnType.
@@ -110,6 +110,32 @@ func MakeTarget(status TargetStatus, elem Element, metadata *TargetMetadata) Tar
 // created and has no relation to the descriptor ID.
 type SourceElementID uint32
 
+// StatementIDs returns the IDs of all the statements which produced the
+// target, starting with the one to which its events are attributed.
+func (m *TargetMetadata) StatementIDs() []uint32 {
+	return append([]uint32{m.StatementID}, m.AdditionalStatementIDs...)
+}
+
+// MergeStatementIDs records that the statements which produced the target
+// described by other also produced this target. The statement to which the
+// events of this target are attributed remains unchanged.
+func (m *TargetMetadata) MergeStatementIDs(other TargetMetadata) {
+	for _, id := range other.StatementIDs() {
+		if id == m.StatementID {
+			continue
+		}
+		ids := m.AdditionalStatementIDs
+		i := sort.Search(len(ids), func(i int) bool { return ids[i] >= id })
+		if i < len(ids) && ids[i] == id {
+			continue
+		}
+		ids = append(ids, 0)
+		copy(ids[i+1:], ids[i:])
+		ids[i] = id
+		m.AdditionalStatementIDs = ids
+	}
+}
+
 // Clone will make a deep copy of the DescriptorState.
 func (m *DescriptorState) Clone() *DescriptorState {
 	if m == nil {
@@ -159,6 +185,8 @@ func MakeCurrentStateFromDescriptors(descriptorStates []*DescriptorState) (Curre
 			stmts[stmt.StatementRank] = stmt.Statement
 		}
 		s.Authorization = cs.Authorization
+		s.DroppedIndexGCTTLSeconds = cs.DroppedIndexGCTTLSeconds
+		s.SessionData = cs.SessionData
 	}
 	sort.Sort(&stateAndRanks{CurrentState: &s, ranks: targetRanks})
 	var sr stmtsAndRanks
@@ -0,0 +1,89 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scplan
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/screl"
+)
+
+// Annotation records a status transition of a target in the plan for which
+// the op functions emitted a scop.NotImplemented op instead of ops which
+// implement it. Annotations make the gaps in the op coverage, and the
+// transitions which are intentionally no-ops, visible in the plan.
+type Annotation struct {
+	// StageIdx is the index of the stage in which the transition occurs in
+	// the stages of the plan.
+	StageIdx int
+
+	// Target is the target of the transition.
+	Target *scpb.Target
+
+	// From and To are the statuses of the target before and after the
+	// transition.
+	From, To scpb.Status
+
+	// Reason is the reason of the scop.NotImplemented op, if any.
+	Reason string
+}
+
+// String implements the fmt.Stringer interface.
+func (a Annotation) String() string {
+	n := &screl.Node{Target: a.Target, CurrentStatus: a.From}
+	return fmt.Sprintf("%s -> %s: %s", screl.NodeString(n), a.To, a.reason())
+}
+
+// reason returns the reason of the annotation, or a placeholder when the
+// scop.NotImplemented op doesn't provide one.
+func (a Annotation) reason() string {
+	if a.Reason == "" {
+		return "not implemented"
+	}
+	return a.Reason
+}
+
+// AnnotationsForStage returns the annotations of the transitions which occur
+// in the stage at the given index.
+func (p Plan) AnnotationsForStage(stageIdx int) (ret []Annotation) {
+	for _, a := range p.Annotations {
+		if a.StageIdx == stageIdx {
+			ret = append(ret, a)
+		}
+	}
+	return ret
+}
+
+// annotatePlan collects the annotations of the plan from the
+// scop.NotImplemented ops which originate from the op-edges of its stages.
+func annotatePlan(p *Plan) {
+	for i, s := range p.Stages {
+		for _, op := range s.EdgeOps {
+			ni, ok := op.(*scop.NotImplemented)
+			if !ok {
+				continue
+			}
+			oe := p.Graph.GetOpEdgeFromOp(op)
+			if oe == nil {
+				continue
+			}
+			p.Annotations = append(p.Annotations, Annotation{
+				StageIdx: i,
+				Target:   oe.From().Target,
+				From:     oe.From().CurrentStatus,
+				To:       oe.To().CurrentStatus,
+				Reason:   ni.Reason,
+			})
+		}
+	}
+}
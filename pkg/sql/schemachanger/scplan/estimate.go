@@ -0,0 +1,176 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scplan
+
+import (
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
+	"github.com/cockroachdb/errors"
+)
+
+// TableStatsResolver provides the table statistics from which the cost of
+// the backfill and validation stages of a plan gets estimated.
+type TableStatsResolver interface {
+	// TableStats returns the statistics of the table, if it has any.
+	TableStats(tableID descpb.ID) (stats TableStats, ok bool, err error)
+}
+
+// TableStats are the statistics of a table used for cost estimation.
+type TableStats struct {
+	// RowCount is the estimated number of rows in the table.
+	RowCount uint64
+
+	// AvgRowSize is the estimated average size of a row, in bytes.
+	AvgRowSize uint64
+}
+
+// ThroughputHistory provides the throughput of the backfill and validation
+// stages of past schema changes, from which the duration of the stages of a
+// plan is estimated.
+type ThroughputHistory interface {
+	// Throughput returns the throughput, in rows read per second, of the past
+	// stages of the given type which read a number of rows similar to the
+	// given one, if there are any.
+	Throughput(stageType scop.Type, rows uint64) (rowsPerSecond float64, ok bool, err error)
+}
+
+// EstimatedTotal returns the sum of the estimated costs of the stages in the
+// plan, and whether any stage has an estimate at all. The total duration is
+// zero unless all the estimates have a duration.
+func (p Plan) EstimatedTotal() (total Estimate, ok bool) {
+	var missingDuration bool
+	for _, s := range p.Stages {
+		if s.Estimate != nil {
+			total.Add(*s.Estimate)
+			missingDuration = missingDuration || s.Estimate.Duration == 0
+			ok = true
+		}
+	}
+	if missingDuration {
+		total.Duration = 0
+	}
+	return total, ok
+}
+
+// estimateStages sets the estimated cost of the backfill and validation
+// stages of the plan, when there are statistics for all the tables which they
+// scan. Index merges are not accounted for: the temporary indexes which they
+// read only contain the writes which took place during the backfill. The
+// duration of the estimated stages is derived from the throughput history in
+// the plan params, if any.
+func estimateStages(p *Plan) error {
+	r := p.Params.TableStatsResolver
+	if r == nil {
+		return nil
+	}
+	type cached struct {
+		stats TableStats
+		ok    bool
+	}
+	cache := make(map[descpb.ID]cached)
+	getStats := func(tableID descpb.ID) (TableStats, bool, error) {
+		if c, found := cache[tableID]; found {
+			return c.stats, c.ok, nil
+		}
+		stats, ok, err := r.TableStats(tableID)
+		if err != nil {
+			return TableStats{}, false, errors.Wrapf(err, "resolving statistics of table %d", tableID)
+		}
+		cache[tableID] = cached{stats: stats, ok: ok}
+		return stats, ok, nil
+	}
+	for i := range p.Stages {
+		s := &p.Stages[i]
+		if s.Type() == scop.MutationType {
+			continue
+		}
+		var e Estimate
+		var hasEstimate, missingStats bool
+		for _, op := range s.EdgeOps {
+			var tableID descpb.ID
+			var writes bool
+			switch op := op.(type) {
+			case *scop.BackfillIndex:
+				tableID, writes = op.TableID, true
+			case *scop.ValidateUniqueIndex:
+				tableID = op.TableID
+			case *scop.ValidateCheckConstraint:
+				tableID = op.TableID
+			default:
+				continue
+			}
+			stats, ok, err := getStats(tableID)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				missingStats = true
+				break
+			}
+			opEstimate := Estimate{
+				Rows:  stats.RowCount,
+				Bytes: stats.RowCount * stats.AvgRowSize,
+			}
+			if writes {
+				opEstimate.IndexWrites = stats.RowCount
+			}
+			e.Add(opEstimate)
+			hasEstimate = true
+		}
+		if !hasEstimate || missingStats {
+			continue
+		}
+		if err := estimateDuration(p.Params.ThroughputHistory, s.Type(), &e); err != nil {
+			return err
+		}
+		s.Estimate = &e
+	}
+	return nil
+}
+
+// estimateDuration sets the estimated duration of a stage of the given type
+// from the throughput of the past stages, if there is any history.
+func estimateDuration(h ThroughputHistory, stageType scop.Type, e *Estimate) error {
+	if h == nil || e.Rows == 0 {
+		return nil
+	}
+	rowsPerSecond, ok, err := h.Throughput(stageType, e.Rows)
+	if err != nil {
+		return errors.Wrapf(err, "resolving the throughput of %s stages", stageType)
+	}
+	if ok && rowsPerSecond > 0 {
+		e.Duration = time.Duration(float64(e.Rows) / rowsPerSecond * float64(time.Second))
+	}
+	return nil
+}
+
+// chooseBackfillStrategies marks the index backfills of the stages whose
+// estimated size exceeds the threshold in the plan params as sequential. The
+// backfills of stages without an estimate keep on running concurrently.
+func chooseBackfillStrategies(p *Plan) {
+	threshold := p.Params.SequentialBackfillThreshold
+	if threshold <= 0 {
+		return
+	}
+	for _, s := range p.Stages {
+		if s.Type() != scop.BackfillType || s.Estimate == nil ||
+			s.Estimate.Bytes < uint64(threshold) {
+			continue
+		}
+		for _, op := range s.EdgeOps {
+			if bf, ok := op.(*scop.BackfillIndex); ok {
+				bf.Sequential = true
+			}
+		}
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package opgen
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+)
+
+// DescriptorStateResolver provides read-only access to facts about the
+// current state of the descriptors which are not captured by elements,
+// for the benefit of the opgen functions.
+//
+// The resolver is consulted at planning time; its answers are cached for
+// the duration of the planning, so it is queried at most once for any
+// given fact. It is not queried concurrently.
+type DescriptorStateResolver interface {
+	// IndexHasZoneConfig returns whether the zone config of the table has
+	// a subzone for the index.
+	IndexHasZoneConfig(tableID catid.DescID, indexID catid.IndexID) (bool, error)
+}
+
+// descriptorState wraps a DescriptorStateResolver with a cache. A nil
+// resolver is allowed, in which case the facts are unknown and the opgen
+// functions must act conservatively. It is safe for concurrent use by the
+// opgen functions.
+type descriptorState struct {
+	resolver DescriptorStateResolver
+
+	mu struct {
+		syncutil.Mutex
+		indexZoneConfigs map[indexKey]bool
+	}
+}
+
+type indexKey struct {
+	tableID catid.DescID
+	indexID catid.IndexID
+}
+
+// indexHasZoneConfig returns whether the index may have a zone config of
+// its own. It returns true when this cannot be determined.
+func (ds *descriptorState) indexHasZoneConfig(
+	tableID catid.DescID, indexID catid.IndexID,
+) bool {
+	if ds.resolver == nil {
+		return true
+	}
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	k := indexKey{tableID: tableID, indexID: indexID}
+	if ret, ok := ds.mu.indexZoneConfigs[k]; ok {
+		return ret
+	}
+	ret, err := ds.resolver.IndexHasZoneConfig(tableID, indexID)
+	if err != nil {
+		// Planning recovers from panics and returns them as errors.
+		panic(errors.Wrapf(err, "resolving zone config of index %d of table %d",
+			indexID, tableID))
+	}
+	if ds.mu.indexZoneConfigs == nil {
+		ds.mu.indexZoneConfigs = make(map[indexKey]bool)
+	}
+	ds.mu.indexZoneConfigs[k] = ret
+	return ret
+}
@@ -0,0 +1,118 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package opgen
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDescriptorStateResolver is a DescriptorStateResolver backed by a
+// static set of facts, which counts the queries it receives.
+type fakeDescriptorStateResolver struct {
+	indexZoneConfigs map[indexKey]bool
+	err              error
+	calls            int
+}
+
+var _ DescriptorStateResolver = (*fakeDescriptorStateResolver)(nil)
+
+func (f *fakeDescriptorStateResolver) IndexHasZoneConfig(
+	tableID catid.DescID, indexID catid.IndexID,
+) (bool, error) {
+	f.calls++
+	return f.indexZoneConfigs[indexKey{tableID: tableID, indexID: indexID}], f.err
+}
+
+func TestDescriptorStateCaching(t *testing.T) {
+	fake := &fakeDescriptorStateResolver{
+		indexZoneConfigs: map[indexKey]bool{{tableID: 104, indexID: 1}: true},
+	}
+	ds := descriptorState{resolver: fake}
+	for i := 0; i < 3; i++ {
+		require.True(t, ds.indexHasZoneConfig(104, 1))
+		require.False(t, ds.indexHasZoneConfig(104, 2))
+	}
+	require.Equal(t, 2, fake.calls)
+
+	// The opgen functions may be called concurrently, in which case the
+	// resolver is still queried once for any given fact.
+	fake.calls = 0
+	ds = descriptorState{resolver: fake}
+	results := make([]bool, 8)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = ds.indexHasZoneConfig(104, 1)
+		}(i)
+	}
+	wg.Wait()
+	require.Equal(t, []bool{true, true, true, true, true, true, true, true}, results)
+	require.Equal(t, 1, fake.calls)
+
+	// Without a resolver, the facts are unknown and the worst is assumed.
+	require.True(t, (&descriptorState{}).indexHasZoneConfig(104, 2))
+
+	// Errors are raised as panics, which planning recovers from.
+	fake = &fakeDescriptorStateResolver{err: errors.New("boom")}
+	require.PanicsWithError(t, "resolving zone config of index 1 of table 104: boom", func() {
+		(&descriptorState{resolver: fake}).indexHasZoneConfig(104, 1)
+	})
+}
+
+func TestCopyIndexZoneConfigUsesResolver(t *testing.T) {
+	newIndex := &scpb.PrimaryIndex{Index: scpb.Index{
+		TableID:                  104,
+		IndexID:                  2,
+		SourceIndexID:            1,
+		InheritsSourceZoneConfig: true,
+	}}
+	cs := scpb.CurrentState{
+		TargetState: scpb.TargetState{
+			Statements: []scpb.Statement{{Statement: "ALTER PRIMARY KEY"}},
+			Targets:    []scpb.Target{scpb.MakeTarget(scpb.ToPublic, newIndex, nil /* metadata */)},
+		},
+	}
+	el := cs.Targets[0].Element()
+	tgt, found := findTarget(el, scpb.Status_PUBLIC)
+	require.True(t, found)
+	var toPublic *transition
+	for i := range tgt.transitions {
+		if tgt.transitions[i].to == scpb.Status_PUBLIC {
+			toPublic = &tgt.transitions[i]
+		}
+	}
+	require.NotNil(t, toPublic)
+
+	hasCopyOp := func(resolver DescriptorStateResolver) bool {
+		md := makeTargetsWithElementMap(cs, resolver, clusterversion.TestingClusterVersion)
+		for _, op := range toPublic.ops(el, &md) {
+			if _, ok := op.(*scop.CopyIndexZoneConfig); ok {
+				return true
+			}
+		}
+		return false
+	}
+	require.True(t, hasCopyOp(nil))
+	require.True(t, hasCopyOp(&fakeDescriptorStateResolver{
+		indexZoneConfigs: map[indexKey]bool{{tableID: 104, indexID: 1}: true},
+	}))
+	require.False(t, hasCopyOp(&fakeDescriptorStateResolver{}))
+}
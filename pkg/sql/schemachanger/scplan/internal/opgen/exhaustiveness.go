@@ -0,0 +1,140 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package opgen
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+	"github.com/cockroachdb/errors"
+)
+
+// elementTypes returns the types of all the members of scpb.ElementProto.
+func elementTypes() []reflect.Type {
+	elementProtoType := reflect.TypeOf((*scpb.ElementProto)(nil)).Elem()
+	ret := make([]reflect.Type, elementProtoType.NumField())
+	for i := range ret {
+		ret[i] = elementProtoType.Field(i).Type
+	}
+	return ret
+}
+
+// checkExhaustiveness checks the registered targets against all the element
+// types.
+func (r *registry) checkExhaustiveness() error {
+	return checkExhaustiveness(elementTypes(), r.targets)
+}
+
+// checkExhaustiveness verifies that the targets cover every status transition
+// which the elements of the given types can undergo, whereas register only
+// validates the targets of one element type in isolation. For each element
+// type, it checks that:
+//   - there is one target to ABSENT, and one to PUBLIC or TRANSIENT_ABSENT;
+//   - any status reached by a revertible transition towards PUBLIC or
+//     TRANSIENT_ABSENT has a path to ABSENT, for the schema change to be
+//     rolled back, and conversely, that any status reached by a revertible
+//     transition towards ABSENT has a path to PUBLIC;
+//   - the transitions which may take place before the post-commit phase only
+//     emit mutation ops.
+//
+// The returned error lists all the violations, so that a faulty op spec is
+// reported precisely when the registry is frozen, instead of failing
+// somewhere down the line when a schema change gets planned.
+func checkExhaustiveness(elementTypes []reflect.Type, targets []target) error {
+	var violations []string
+	report := func(elType reflect.Type, format string, args ...interface{}) {
+		violations = append(violations,
+			elType.Elem().Name()+": "+fmt.Sprintf(format, args...))
+	}
+	byElementType := make(map[reflect.Type]map[scpb.Status]*target)
+	for _, typ := range elementTypes {
+		byElementType[typ] = make(map[scpb.Status]*target)
+	}
+	for i := range targets {
+		t := &targets[i]
+		typ := reflect.TypeOf(t.e)
+		m, ok := byElementType[typ]
+		if !ok {
+			report(typ, "not a member of scpb.ElementProto")
+			continue
+		}
+		if m[t.status] != nil {
+			report(typ, "multiple targets to %s", t.status)
+			continue
+		}
+		m[t.status] = t
+	}
+	for _, typ := range elementTypes {
+		m := byElementType[typ]
+		drop := m[scpb.Status_ABSENT]
+		if drop == nil {
+			report(typ, "no target to %s", scpb.Status_ABSENT)
+		}
+		if m[scpb.Status_PUBLIC] == nil && m[scpb.Status_TRANSIENT_ABSENT] == nil {
+			report(typ, "no target to %s nor %s",
+				scpb.Status_PUBLIC, scpb.Status_TRANSIENT_ABSENT)
+		}
+		for _, s := range []scpb.Status{
+			scpb.Status_PUBLIC, scpb.Status_TRANSIENT_ABSENT, scpb.Status_ABSENT,
+		} {
+			t := m[s]
+			if t == nil {
+				continue
+			}
+			// A schema change rolled back while a target to PUBLIC or to
+			// TRANSIENT_ABSENT is revertible flips it to ABSENT. Conversely, a
+			// target to ABSENT flips to PUBLIC.
+			var rollback *target
+			if s == scpb.Status_ABSENT {
+				rollback = m[scpb.Status_PUBLIC]
+			} else {
+				rollback = drop
+			}
+			for _, tr := range t.transitions {
+				if tr.opType != 0 && tr.opType != scop.MutationType &&
+					tr.minPhase < scop.PostCommitPhase {
+					report(typ, "transition %s -> %s towards %s emits %s ops before the post-commit phase",
+						tr.from, tr.to, s, tr.opType)
+				}
+				if rollback == nil || !tr.revertible {
+					continue
+				}
+				if !hasPath(rollback, tr.to) {
+					report(typ, "status %s reached by revertible transition %s -> %s towards %s has no path to %s",
+						tr.to, tr.from, tr.to, s, rollback.status)
+				}
+			}
+		}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	sort.Strings(violations)
+	return errors.Newf("op specs are not exhaustive:\n%s", strings.Join(violations, "\n"))
+}
+
+// hasPath returns whether the transitions of the target lead from the given
+// status to the target status.
+func hasPath(t *target, from scpb.Status) bool {
+	if from == t.status {
+		return true
+	}
+	for _, tr := range t.transitions {
+		if tr.from == from {
+			return true
+		}
+	}
+	return false
+}
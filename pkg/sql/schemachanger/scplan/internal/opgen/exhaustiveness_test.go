@@ -0,0 +1,127 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package opgen
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/datadriven"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExhaustiveness checks that the op specs registered in this package are
+// exhaustive.
+func TestExhaustiveness(t *testing.T) {
+	require.NoError(t, opRegistry.checkExhaustiveness())
+}
+
+// TestCheckExhaustiveness runs checkExhaustiveness against the targets defined
+// in the testdata files, one per line, as follows:
+//
+//	<element> <target status>: <initial status> <transition>...
+//
+// where each transition is either `-> S` for a transition to status S which
+// emits mutation ops, `-[backfill]-> S` or `-[validation]-> S` for one which
+// emits backfill or validation ops, or `S~` for a status S equivalent to the
+// current status. Non-revertible transitions are suffixed with `!`, as in
+// `->! S`. A line consisting only of an element name adds its type to the
+// checked element types without defining any target.
+func TestCheckExhaustiveness(t *testing.T) {
+	types := make(map[string]reflect.Type)
+	for _, typ := range elementTypes() {
+		types[typ.Elem().Name()] = typ
+	}
+	datadriven.RunTest(t, testutils.TestDataPath(t, "exhaustiveness"), func(
+		t *testing.T, d *datadriven.TestData,
+	) string {
+		if d.Cmd != "check" {
+			d.Fatalf(t, "unknown command %s", d.Cmd)
+		}
+		parseStatus := func(s string) scpb.Status {
+			v, ok := scpb.Status_value[s]
+			if !ok {
+				d.Fatalf(t, "unknown status %s", s)
+			}
+			return scpb.Status(v)
+		}
+		var elementTypes []reflect.Type
+		var targets []target
+		seen := make(map[reflect.Type]bool)
+		for _, line := range strings.Split(d.Input, "\n") {
+			head, chain := line, ""
+			if i := strings.Index(line, ":"); i >= 0 {
+				head, chain = line[:i], line[i+1:]
+			}
+			fields := strings.Fields(head)
+			typ, ok := types[fields[0]]
+			if !ok {
+				d.Fatalf(t, "unknown element %s", fields[0])
+			}
+			if !seen[typ] {
+				seen[typ] = true
+				elementTypes = append(elementTypes, typ)
+			}
+			if len(fields) == 1 {
+				continue
+			}
+			tokens := strings.Fields(chain)
+			spec := targetSpec{from: parseStatus(tokens[0]), to: parseStatus(fields[1])}
+			var opTypes []scop.Type
+			for i := 1; i < len(tokens); i++ {
+				tok := tokens[i]
+				if strings.HasSuffix(tok, "~") {
+					spec.transitionSpecs = append(spec.transitionSpecs,
+						equiv(parseStatus(strings.TrimSuffix(tok, "~"))))
+					opTypes = append(opTypes, 0)
+					continue
+				}
+				ts := transitionSpec{revertible: !strings.HasSuffix(tok, "!")}
+				switch strings.TrimSuffix(tok, "!") {
+				case "->":
+					opTypes = append(opTypes, scop.MutationType)
+				case "-[backfill]->":
+					opTypes = append(opTypes, scop.BackfillType)
+				case "-[validation]->":
+					opTypes = append(opTypes, scop.ValidationType)
+				default:
+					d.Fatalf(t, "unknown transition %s", tok)
+				}
+				if i++; i == len(tokens) {
+					d.Fatalf(t, "missing status after %s", tok)
+				}
+				ts.to = parseStatus(tokens[i])
+				spec.transitionSpecs = append(spec.transitionSpecs, ts)
+			}
+			e := reflect.New(typ.Elem()).Interface().(scpb.Element)
+			transitions, err := makeTransitions(e, spec)
+			if err != nil {
+				d.Fatalf(t, "%v", err)
+			}
+			for i := range transitions {
+				transitions[i].opType = opTypes[i]
+			}
+			targets = append(targets, target{
+				e:           e,
+				status:      spec.to,
+				transitions: transitions,
+			})
+		}
+		if err := checkExhaustiveness(elementTypes, targets); err != nil {
+			return err.Error()
+		}
+		return "ok"
+	})
+}
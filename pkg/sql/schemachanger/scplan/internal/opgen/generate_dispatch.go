@@ -0,0 +1,331 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+//go:build generator
+// +build generator
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/cockroachdb/cockroach/pkg/cli/exit"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/gostdlib/go/format"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, "ERROR:", err)
+		exit.WithCode(exit.UnspecifiedError())
+	}
+}
+
+// opFuncSignature is the signature of an op function: it takes a pointer to
+// an element, optionally followed by a *targetsWithElementMap and then by a
+// *scpb.SessionData, and returns either one or more pointers to ops or a
+// slice of ops.
+type opFuncSignature struct {
+	Element string
+	WithMD  bool
+	WithSD  bool
+	// Ops are the comma-separated names of the types of the ops returned by
+	// the function, unless it returns a slice of ops.
+	Ops   string
+	Slice bool
+}
+
+// OpList returns the names of the types of the ops returned by the function.
+func (sig opFuncSignature) OpList() []string {
+	return strings.Split(sig.Ops, ",")
+}
+
+// Results returns the result list of the function signature.
+func (sig opFuncSignature) Results() string {
+	if sig.Slice {
+		return "[]scop.Op"
+	}
+	ops := sig.OpList()
+	for i, op := range ops {
+		ops[i] = "*scop." + op
+	}
+	if len(ops) == 1 {
+		return ops[0]
+	}
+	return "(" + strings.Join(ops, ", ") + ")"
+}
+
+// Params returns the parameter list of the function signature.
+func (sig opFuncSignature) Params() string {
+	params := "*scpb." + sig.Element
+	if sig.WithMD {
+		params += ", *targetsWithElementMap"
+	}
+	if sig.WithSD {
+		params += ", *scpb.SessionData"
+	}
+	return params
+}
+
+// Args returns the argument list with which the function is called.
+func (sig opFuncSignature) Args() string {
+	args := "e.(*scpb." + sig.Element + ")"
+	if sig.WithMD {
+		args += ", md"
+	}
+	if sig.WithSD {
+		args += ", &md.SessionData"
+	}
+	return args
+}
+
+// Vars returns the comma-separated names of the variables which are assigned
+// the ops returned by the function.
+func (sig opFuncSignature) Vars() string {
+	vars := sig.OpList()
+	for i := range vars {
+		vars[i] = fmt.Sprintf("op%d", i)
+	}
+	return strings.Join(vars, ", ")
+}
+
+type info struct {
+	Pkg  string
+	Sigs []opFuncSignature
+}
+
+func run() error {
+	if len(os.Args) < 4 {
+		return errors.Newf("usage: %s <package> <output> <input>...\n", os.Args[0])
+	}
+	pkg, out := os.Args[1], os.Args[2]
+
+	// The inputs may be glob patterns, since go:generate does not expand
+	// them.
+	var inputs []string
+	for _, arg := range os.Args[3:] {
+		matches, err := filepath.Glob(arg)
+		if err != nil {
+			return err
+		}
+		inputs = append(inputs, matches...)
+	}
+
+	sigs := make(map[opFuncSignature]struct{})
+	fset := token.NewFileSet()
+	for _, in := range inputs {
+		f, err := parser.ParseFile(fset, in, nil, 0)
+		if err != nil {
+			return err
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			if lit, ok := n.(*ast.FuncLit); ok {
+				if sig, ok := opFuncSignatureOf(lit.Type); ok {
+					sigs[sig] = struct{}{}
+				}
+			}
+			return true
+		})
+	}
+	sorted := make([]opFuncSignature, 0, len(sigs))
+	for sig := range sigs {
+		sorted = append(sorted, sig)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.Element != b.Element {
+			return a.Element < b.Element
+		}
+		if a.Slice != b.Slice {
+			return !a.Slice
+		}
+		if a.Ops != b.Ops {
+			return a.Ops < b.Ops
+		}
+		if a.WithMD != b.WithMD {
+			return !a.WithMD
+		}
+		return !a.WithSD && b.WithSD
+	})
+
+	tmpl, err := template.New("dispatch").Parse(dispatchTemplate)
+	if err != nil {
+		return err
+	}
+
+	// Render the template.
+	var gen bytes.Buffer
+	if err := tmpl.Execute(&gen, info{Pkg: pkg, Sigs: sorted}); err != nil {
+		return err
+	}
+
+	// Run gofmt on the generated source.
+	formatted, err := format.Source(gen.Bytes())
+	if err != nil {
+		return errors.Wrap(err, "gofmt")
+	}
+
+	// Write the output file.
+	return os.WriteFile(out, formatted, 0666)
+}
+
+// opFuncSignatureOf returns the signature of the given function type, if it
+// is the type of an op function.
+func opFuncSignatureOf(t *ast.FuncType) (sig opFuncSignature, ok bool) {
+	var params []ast.Expr
+	for _, p := range t.Params.List {
+		n := len(p.Names)
+		if n == 0 {
+			n = 1
+		}
+		for i := 0; i < n; i++ {
+			params = append(params, p.Type)
+		}
+	}
+	var results []ast.Expr
+	if t.Results != nil {
+		for _, r := range t.Results.List {
+			n := len(r.Names)
+			if n == 0 {
+				n = 1
+			}
+			for i := 0; i < n; i++ {
+				results = append(results, r.Type)
+			}
+		}
+	}
+	if len(params) < 1 || len(params) > 3 || len(results) < 1 {
+		return sig, false
+	}
+	if sig.Element, ok = qualifiedPointerType(params[0], "scpb"); !ok {
+		return sig, false
+	}
+	if len(params) >= 2 {
+		star, isStar := params[1].(*ast.StarExpr)
+		if !isStar {
+			return sig, false
+		}
+		if id, isIdent := star.X.(*ast.Ident); !isIdent || id.Name != "targetsWithElementMap" {
+			return sig, false
+		}
+		sig.WithMD = true
+	}
+	if len(params) == 3 {
+		if name, isSD := qualifiedPointerType(params[2], "scpb"); !isSD || name != "SessionData" {
+			return sig, false
+		}
+		sig.WithSD = true
+	}
+	if len(results) == 1 && isOpSliceType(results[0]) {
+		sig.Slice = true
+		return sig, true
+	}
+	ops := make([]string, len(results))
+	for i, r := range results {
+		if ops[i], ok = qualifiedPointerType(r, "scop"); !ok {
+			return sig, false
+		}
+	}
+	sig.Ops = strings.Join(ops, ",")
+	return sig, true
+}
+
+// isOpSliceType returns true iff e is of the form []scop.Op.
+func isOpSliceType(e ast.Expr) bool {
+	arr, ok := e.(*ast.ArrayType)
+	if !ok || arr.Len != nil {
+		return false
+	}
+	sel, ok := arr.Elt.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Op" {
+		return false
+	}
+	id, ok := sel.X.(*ast.Ident)
+	return ok && id.Name == "scop"
+}
+
+// qualifiedPointerType returns the name of the type if e is of the form
+// *pkg.Type.
+func qualifiedPointerType(e ast.Expr, pkg string) (string, bool) {
+	star, ok := e.(*ast.StarExpr)
+	if !ok {
+		return "", false
+	}
+	sel, ok := star.X.(*ast.SelectorExpr)
+	if !ok {
+		return "", false
+	}
+	if id, ok := sel.X.(*ast.Ident); !ok || id.Name != pkg {
+		return "", false
+	}
+	return sel.Sel.Name, true
+}
+
+const dispatchTemplate = `// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Code generated by generate_dispatch.go. DO NOT EDIT.
+
+package {{.Pkg}}
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+)
+
+// generatedOpFunc returns the statically typed form of fn, or nil if fn does
+// not have the signature of any of the op functions in this package.
+func generatedOpFunc(fn interface{}) opFuncImpl {
+	switch fn := fn.(type) {
+{{- range .Sigs}}
+	case func({{.Params}}) {{.Results}}:
+		return func(e scpb.Element, {{if .WithMD}}md{{else}}_{{end}} *targetsWithElementMap, ops []scop.Op) []scop.Op {
+{{- if .Slice}}
+			for _, op := range fn({{.Args}}) {
+				if op != nil {
+					ops = append(ops, op)
+				}
+			}
+{{- else if eq (len .OpList) 1}}
+			if op := fn({{.Args}}); op != nil {
+				ops = append(ops, op)
+			}
+{{- else}}
+			{{.Vars}} := fn({{.Args}})
+{{- range $i, $op := .OpList}}
+			if op{{$i}} != nil {
+				ops = append(ops, op{{$i}})
+			}
+{{- end}}
+{{- end}}
+			return ops
+		}
+{{- end}}
+	default:
+		return nil
+	}
+}
+`
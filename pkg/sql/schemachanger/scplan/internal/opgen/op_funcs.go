@@ -13,15 +13,19 @@ package opgen
 import (
 	"reflect"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/screl"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/redact"
 )
 
-func newLogEventBase(e scpb.Element, md *targetsWithElementMap) scop.EventBase {
+func newLogEventBase(
+	e scpb.Element, md *targetsWithElementMap, sd *scpb.SessionData,
+) scop.EventBase {
 	idx, ok := md.elementToTarget[e]
 	if !ok {
 		panic(errors.AssertionFailedf(
@@ -31,13 +35,15 @@ func newLogEventBase(e scpb.Element, md *targetsWithElementMap) scop.EventBase {
 	t := md.Targets[idx]
 	return scop.EventBase{
 		TargetMetadata: *protoutil.Clone(&t.Metadata).(*scpb.TargetMetadata),
-		Authorization:  *protoutil.Clone(&md.Authorization).(*scpb.Authorization),
+		Authorization:  scpb.Authorization{UserName: sd.UserName, AppName: sd.ApplicationName},
 		Statement:      md.Statements[t.Metadata.StatementID].RedactedStatement,
 		StatementTag:   md.Statements[t.Metadata.StatementID].StatementTag,
 	}
 }
 
-func newLogEventOp(e scpb.Element, md *targetsWithElementMap) *scop.LogEvent {
+func newLogEventOp(
+	e scpb.Element, md *targetsWithElementMap, sd *scpb.SessionData,
+) *scop.LogEvent {
 	idx, ok := md.elementToTarget[e]
 	if !ok {
 		panic(errors.AssertionFailedf(
@@ -46,7 +52,7 @@ func newLogEventOp(e scpb.Element, md *targetsWithElementMap) *scop.LogEvent {
 	}
 	t := md.Targets[idx]
 	return &scop.LogEvent{
-		EventBase:    newLogEventBase(e, md),
+		EventBase:    newLogEventBase(e, md, sd),
 		Element:      *protoutil.Clone(&t.ElementProto).(*scpb.ElementProto),
 		TargetStatus: t.TargetStatus,
 	}
@@ -65,23 +71,145 @@ func statementForDropJob(e scpb.Element, md *targetsWithElementMap) scop.Stateme
 	}
 }
 
+// setDroppedIndexGCTTL returns an op applying the GC TTL override for dropped
+// indexes, if the schema change has one.
+func setDroppedIndexGCTTL(
+	tableID catid.DescID, indexID catid.IndexID, md *targetsWithElementMap,
+) *scop.SetDroppedIndexGCTTL {
+	if md.DroppedIndexGCTTLSeconds == 0 {
+		return nil
+	}
+	return &scop.SetDroppedIndexGCTTL{
+		TableID:    tableID,
+		IndexID:    indexID,
+		TTLSeconds: md.DroppedIndexGCTTLSeconds,
+	}
+}
+
+// updateColumnExpressionBackReferences returns the ops which update the
+// back-references to the table of a column expression in the types and
+// sequences which it uses.
+func updateColumnExpressionBackReferences(
+	tableID catid.DescID, columnID catid.ColumnID, expr *scpb.Expression,
+) []scop.Op {
+	var ops []scop.Op
+	if len(expr.UsesTypeIDs) > 0 {
+		ops = append(ops, &scop.UpdateTableBackReferencesInTypes{
+			TypeIDs:               expr.UsesTypeIDs,
+			BackReferencedTableID: tableID,
+		})
+	}
+	if len(expr.UsesSequenceIDs) > 0 {
+		ops = append(ops, &scop.UpdateBackReferencesInSequences{
+			SequenceIDs:            expr.UsesSequenceIDs,
+			BackReferencedTableID:  tableID,
+			BackReferencedColumnID: columnID,
+		})
+	}
+	return ops
+}
+
+// newLogColumnPrivilegesEventOp returns an op logging a single event for the
+// column-level privileges of a user which the statement targeting the element
+// grants or revokes on the columns of its table. The op is only returned for
+// the first of the targets of these privileges, and only for the columns
+// which are not dropped along with them.
+func newLogColumnPrivilegesEventOp(
+	this *scpb.ColumnPrivileges, md *targetsWithElementMap, sd *scpb.SessionData,
+) *scop.LogColumnPrivilegesEvent {
+	idx := md.elementToTarget[this]
+	stmtID := md.Targets[idx].Metadata.StatementID
+	var columnIDs []catid.ColumnID
+	before := make(map[catid.ColumnID]uint32)
+	after := make(map[catid.ColumnID]uint32)
+	dropped := make(map[catid.ColumnID]bool)
+	for i := range md.Targets {
+		t := &md.Targets[i]
+		switch e := t.Element().(type) {
+		case *scpb.Table:
+			if e.TableID == this.TableID && t.TargetStatus == scpb.Status_ABSENT {
+				return nil
+			}
+		case *scpb.Column:
+			if e.TableID == this.TableID && t.TargetStatus == scpb.Status_ABSENT {
+				dropped[e.ColumnID] = true
+			}
+		case *scpb.ColumnPrivileges:
+			if e.TableID != this.TableID || e.UserName != this.UserName ||
+				t.Metadata.StatementID != stmtID {
+				continue
+			}
+			if len(before) == 0 && len(after) == 0 && i != idx {
+				return nil
+			}
+			if _, seen := before[e.ColumnID]; !seen {
+				if _, seen = after[e.ColumnID]; !seen {
+					columnIDs = append(columnIDs, e.ColumnID)
+				}
+			}
+			if t.TargetStatus == scpb.Status_ABSENT {
+				before[e.ColumnID] |= e.Privileges
+			} else {
+				after[e.ColumnID] |= e.Privileges
+			}
+		}
+	}
+	op := &scop.LogColumnPrivilegesEvent{
+		EventBase: newLogEventBase(this, md, sd),
+		TableID:   this.TableID,
+		Grantee:   this.UserName,
+	}
+	for _, columnID := range columnIDs {
+		if dropped[columnID] {
+			continue
+		}
+		granted := after[columnID] &^ before[columnID]
+		revoked := before[columnID] &^ after[columnID]
+		if granted == 0 && revoked == 0 {
+			continue
+		}
+		op.ColumnIDs = append(op.ColumnIDs, columnID)
+		op.GrantedPrivileges |= granted
+		op.RevokedPrivileges |= revoked
+	}
+	if len(op.ColumnIDs) == 0 {
+		return nil
+	}
+	return op
+}
+
 // targetsWithElementMap is one of the available arguments to an opgen
 // function. It allows access to the fields of the TargetState and, via
 // a lookup map, the fields of the element itself.
 //
 // This map allows opgen functions to find their target without an O(N)
-// lookup.
+// lookup. It also provides access to the facts about the descriptors
+// which are not captured by the elements, via the descriptor state.
+//
+// The session data snapshot of the TargetState is passed on its own to the
+// opgen functions which take a *scpb.SessionData after it.
 type targetsWithElementMap struct {
 	scpb.TargetState
 	elementToTarget map[scpb.Element]int
 	InRollback      bool
+	*descriptorState
+
+	// activeVersion determines which ops get emitted by version-gated op
+	// functions, see emitIfActive.
+	activeVersion clusterversion.ClusterVersion
 }
 
-func makeTargetsWithElementMap(cs scpb.CurrentState) targetsWithElementMap {
+func makeTargetsWithElementMap(
+	cs scpb.CurrentState,
+	resolver DescriptorStateResolver,
+	activeVersion clusterversion.ClusterVersion,
+) targetsWithElementMap {
 	md := targetsWithElementMap{
 		InRollback:      cs.InRollback,
 		TargetState:     cs.TargetState,
 		elementToTarget: make(map[scpb.Element]int),
+		descriptorState: &descriptorState{resolver: resolver},
+		activeVersion:   activeVersion,
 	}
 	for i := range cs.Targets {
 		e := cs.Targets[i].Element()
@@ -93,6 +221,12 @@ func makeTargetsWithElementMap(cs scpb.CurrentState) targetsWithElementMap {
 		}
 		md.elementToTarget[e] = i
 	}
+	// The states built before the session data snapshot was introduced only
+	// carry the user and application names of the session, as authorization.
+	if md.SessionData.UserName == "" {
+		md.SessionData.UserName = md.Authorization.UserName
+		md.SessionData.ApplicationName = md.Authorization.AppName
+	}
 	return md
 }
 
@@ -100,34 +234,149 @@ func makeTargetsWithElementMap(cs scpb.CurrentState) targetsWithElementMap {
 // given an element value.
 type opsFunc func(element scpb.Element, md *targetsWithElementMap) []scop.Op
 
-func makeOpsFunc(el scpb.Element, fns []interface{}) (opsFunc, scop.Type, error) {
+// opFuncImpl is the statically typed form of an op function, as produced by
+// generatedOpFunc. It appends the non-nil ops returned by the op function to
+// ops and returns the resulting slice.
+type opFuncImpl func(element scpb.Element, md *targetsWithElementMap, ops []scop.Op) []scop.Op
+
+// opFunc is a checked op function. If versioned is set, the function only
+// applies as of minVersion and downlevel applies otherwise, unless it is nil,
+// in which case nothing is emitted.
+type opFunc struct {
+	fn         opFuncImpl
+	versioned  bool
+	minVersion clusterversion.Key
+	downlevel  opFuncImpl
+}
+
+//go:generate go run ./generate_dispatch.go opgen dispatch_generated.go opgen_*.go
+
+// dispatchOpFunc returns the statically typed form of a checked op function,
+// which avoids calling it via reflection every time a plan is built.
+func dispatchOpFunc(fn interface{}) (opFuncImpl, error) {
+	impl := generatedOpFunc(fn)
+	if impl == nil {
+		return nil, errors.AssertionFailedf(
+			"%T has no generated dispatch code, run go generate", fn,
+		)
+	}
+	return impl, nil
+}
+
+// reflectOpFunc returns the form of a checked op function which calls it via
+// reflection.
+func reflectOpFunc(fn interface{}) opFuncImpl {
+	fnV := reflect.ValueOf(fn)
+	numIn := fnV.Type().NumIn()
+	return func(e scpb.Element, md *targetsWithElementMap, ops []scop.Op) []scop.Op {
+		in := []reflect.Value{reflect.ValueOf(e)}
+		if numIn >= 2 {
+			in = append(in, reflect.ValueOf(md))
+		}
+		if numIn == 3 {
+			in = append(in, reflect.ValueOf(&md.SessionData))
+		}
+		for _, out := range fnV.Call(in) {
+			if out.IsNil() {
+				continue
+			}
+			switch v := out.Interface().(type) {
+			case []scop.Op:
+				for _, op := range v {
+					if op != nil {
+						ops = append(ops, op)
+					}
+				}
+			case scop.Op:
+				ops = append(ops, v)
+			}
+		}
+		return ops
+	}
+}
+
+// makeOpsFunc compiles the op functions of a transition into an opsFunc,
+// and returns the type of the ops which it emits. The type of the ops in the
+// slices returned by op functions can't be checked statically: they are
+// expected to be of the same type as those returned by the other op functions
+// of the transition, or of the mutation type if there are none, and the
+// opsFunc panics if they aren't.
+func makeOpsFunc(el scpb.Element, fns []emitFnSpec) (opsFunc, scop.Type, error) {
 	var opType scop.Type
-	var funcValues []reflect.Value
-	for _, fn := range fns {
-		typ, err := checkOpFunc(el, fn)
-		if err != nil {
-			return nil, 0, err
+	var funcValues []opFunc
+	checkType := func(typ scop.Type) error {
+		if typ == 0 {
+			// The op function returns a slice of ops.
+			return nil
 		}
-		if len(funcValues) > 0 && typ != opType {
-			return nil, 0, errors.Errorf("conflicting operation types for %T: %s != %s",
+		if opType != 0 && typ != opType {
+			return errors.Errorf("conflicting operation types for %T: %s != %s",
 				el, opType, typ)
 		}
 		opType = typ
-		funcValues = append(funcValues, reflect.ValueOf(fn))
+		return nil
+	}
+	for _, spec := range fns {
+		typ, err := checkOpFunc(el, spec.fn)
+		if err != nil {
+			return nil, 0, err
+		}
+		if err := checkType(typ); err != nil {
+			return nil, 0, err
+		}
+		var fn opFuncImpl
+		if spec.reflective {
+			fn = reflectOpFunc(spec.fn)
+		} else if fn, err = dispatchOpFunc(spec.fn); err != nil {
+			return nil, 0, err
+		}
+		f := opFunc{fn: fn}
+		if spec.versioned {
+			f.versioned = true
+			f.minVersion = spec.minVersion
+			switch spec.downlevel {
+			case nil:
+				return nil, 0, errors.Errorf(
+					"%T is emitted as of version %s but has no downlevel behavior",
+					spec.fn, spec.minVersion,
+				)
+			case emitNothing:
+			default:
+				typ, err := checkOpFunc(el, spec.downlevel)
+				if err != nil {
+					return nil, 0, errors.Wrapf(err, "downlevel of %T", spec.fn)
+				}
+				if err := checkType(typ); err != nil {
+					return nil, 0, err
+				}
+				if f.downlevel, err = dispatchOpFunc(spec.downlevel); err != nil {
+					return nil, 0, err
+				}
+			}
+		}
+		funcValues = append(funcValues, f)
+	}
+	if opType == 0 {
+		opType = scop.MutationType
 	}
 	return func(element scpb.Element, md *targetsWithElementMap) []scop.Op {
 		ret := make([]scop.Op, 0, len(funcValues))
-		in := []reflect.Value{reflect.ValueOf(element)}
-		inWithMeta := []reflect.Value{reflect.ValueOf(element), reflect.ValueOf(md)}
-		for _, fn := range funcValues {
-			var out []reflect.Value
-			if fn.Type().NumIn() == 1 {
-				out = fn.Call(in)
-			} else {
-				out = fn.Call(inWithMeta)
+		for _, f := range funcValues {
+			fn := f.fn
+			if f.versioned && !md.activeVersion.IsActive(f.minVersion) {
+				if f.downlevel == nil {
+					continue
+				}
+				fn = f.downlevel
 			}
-			if !out[0].IsNil() {
-				ret = append(ret, out[0].Interface().(scop.Op))
+			ret = fn(element, md, ret)
+		}
+		for _, op := range ret {
+			if op.Type() != opType {
+				panic(errors.AssertionFailedf(
+					"%T emitted for %s is of type %s instead of %s",
+					op, screl.ElementString(element), op.Type(), opType,
+				))
 			}
 		}
 		return ret
@@ -135,12 +384,21 @@ func makeOpsFunc(el scpb.Element, fns []interface{}) (opsFunc, scop.Type, error)
 }
 
 var (
+	targetsWithElementMapType = reflect.TypeOf((*targetsWithElementMap)(nil))
+	sessionDataType           = reflect.TypeOf((*scpb.SessionData)(nil))
+	opSliceType               = reflect.TypeOf([]scop.Op(nil))
 	opInterfaceType           = reflect.TypeOf((*scop.Op)(nil)).Elem()
 	mutationOpInterfaceType   = reflect.TypeOf((*scop.MutationOp)(nil)).Elem()
 	validationOpInterfaceType = reflect.TypeOf((*scop.ValidationOp)(nil)).Elem()
 	backfillOpInterfaceType   = reflect.TypeOf((*scop.BackfillOp)(nil)).Elem()
 )
 
+// checkOpFunc checks that fn is an op function for the given element and
+// returns the type of the ops which it returns. An op function takes the
+// element, optionally followed by a *targetsWithElementMap and then by a
+// *scpb.SessionData, and returns either
+// one or more pointers to ops of the same type, or a []scop.Op, in which case
+// the type of the ops can't be determined and zero is returned.
 func checkOpFunc(el scpb.Element, fn interface{}) (opType scop.Type, _ error) {
 	fnV := reflect.ValueOf(fn)
 	fnT := fnV.Type()
@@ -150,36 +408,51 @@ func checkOpFunc(el scpb.Element, fn interface{}) (opType scop.Type, _ error) {
 		)
 	}
 	elType := reflect.TypeOf(el)
-	if !(fnT.NumIn() == 1 && fnT.In(0) == elType) &&
-		!(fnT.NumIn() == 2 && fnT.In(0) == elType &&
-			fnT.In(1) == reflect.TypeOf((*targetsWithElementMap)(nil))) {
+	if n := fnT.NumIn(); n < 1 || n > 3 || fnT.In(0) != elType ||
+		(n >= 2 && fnT.In(1) != targetsWithElementMapType) ||
+		(n == 3 && fnT.In(2) != sessionDataType) {
 		return 0, errors.Errorf(
-			"expected %v to be a func with one argument of type %s", fnT, elType,
+			"expected %v to be a func with a first argument of type %s, "+
+				"optionally followed by arguments of types %s and %s",
+			fnT, elType, targetsWithElementMapType, sessionDataType,
 		)
 	}
 	returnTypeError := func() error {
 		return errors.Errorf(
-			"expected %v to be a func with one return value of a "+
-				"pointer type which implements %s", fnT, opType,
+			"expected %v to be a func with return values of pointer types "+
+				"which implement %s, or with a single return value of type %s",
+			fnT, opInterfaceType, opSliceType,
 		)
 	}
-	if fnT.NumOut() != 1 {
-		return 0, returnTypeError()
+	if fnT.NumOut() == 1 && fnT.Out(0) == opSliceType {
+		return 0, nil
 	}
-	out := fnT.Out(0)
-	if out.Kind() != reflect.Ptr || !out.Implements(opInterfaceType) {
+	if fnT.NumOut() == 0 {
 		return 0, returnTypeError()
 	}
-	switch {
-	case out.Implements(mutationOpInterfaceType):
-		opType = scop.MutationType
-	case out.Implements(validationOpInterfaceType):
-		opType = scop.ValidationType
-	case out.Implements(backfillOpInterfaceType):
-		opType = scop.BackfillType
-	default:
-		return 0, errors.AssertionFailedf("%s implemented %s but does not conform to any known type",
-			out, opInterfaceType)
+	for i := 0; i < fnT.NumOut(); i++ {
+		out := fnT.Out(i)
+		if out.Kind() != reflect.Ptr || !out.Implements(opInterfaceType) {
+			return 0, returnTypeError()
+		}
+		var typ scop.Type
+		switch {
+		case out.Implements(mutationOpInterfaceType):
+			typ = scop.MutationType
+		case out.Implements(validationOpInterfaceType):
+			typ = scop.ValidationType
+		case out.Implements(backfillOpInterfaceType):
+			typ = scop.BackfillType
+		default:
+			return 0, errors.AssertionFailedf("%s implemented %s but does not conform to any known type",
+				out, opInterfaceType)
+		}
+		if opType != 0 && typ != opType {
+			return 0, errors.Errorf(
+				"expected the ops returned by %v to be of the same type: %s != %s", fnT, opType, typ,
+			)
+		}
+		opType = typ
 	}
 	return opType, nil
 }
@@ -14,27 +14,75 @@ import (
 	"context"
 	"reflect"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scplan/internal/scgraph"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scplan/internal/scparallel"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/screl"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
 )
 
 type registry struct {
 	targets []target
+
+	// frozen is set once all the targets have been registered, after which
+	// the registry is read-only and may be used concurrently.
+	frozen bool
+
+	// err records the errors encountered while registering the targets, which
+	// are reported by Freeze.
+	err error
 }
 
 var opRegistry = &registry{}
 
+// Freeze marks the registry as immutable. Any subsequent registration
+// panics. It is intended to be called once, after all the package init
+// functions have registered their targets and before any planning takes
+// place. It returns an error, leaving the registry as is, if the targets
+// failed to register or if the registered targets are not exhaustive.
+func Freeze() error {
+	if opRegistry.err != nil {
+		return errors.NewAssertionErrorWithWrappedErrf(opRegistry.err, "invalid op registry")
+	}
+	if err := opRegistry.checkExhaustiveness(); err != nil {
+		return errors.NewAssertionErrorWithWrappedErrf(err, "invalid op registry")
+	}
+	opRegistry.freeze()
+	return nil
+}
+
+func (r *registry) freeze() {
+	r.frozen = true
+	// Clip the capacity so that an append to the slice can never write
+	// into the backing array shared by concurrent readers.
+	r.targets = r.targets[:len(r.targets):len(r.targets)]
+}
+
 // BuildGraph constructs a graph with operation edges populated from an initial
-// state.
-func BuildGraph(cs scpb.CurrentState) (*scgraph.Graph, error) {
-	return opRegistry.buildGraph(cs)
+// state. The resolver, which may be nil, is consulted by the opgen functions
+// for facts about the descriptors which are not captured by the elements.
+// The active cluster version determines which ops get emitted by the
+// version-gated op functions. The op edges are generated by up to concurrency
+// workers, see scparallel.Do.
+func BuildGraph(
+	cs scpb.CurrentState,
+	resolver DescriptorStateResolver,
+	activeVersion clusterversion.ClusterVersion,
+	concurrency int,
+) (*scgraph.Graph, error) {
+	return opRegistry.buildGraph(cs, resolver, activeVersion, concurrency)
 }
 
-func (r *registry) buildGraph(cs scpb.CurrentState) (_ *scgraph.Graph, err error) {
+func (r *registry) buildGraph(
+	cs scpb.CurrentState,
+	resolver DescriptorStateResolver,
+	activeVersion clusterversion.ClusterVersion,
+	concurrency int,
+) (_ *scgraph.Graph, err error) {
 	start := timeutil.Now()
 	defer func() {
 		if err != nil || !log.V(2) {
@@ -47,21 +95,26 @@ func (r *registry) buildGraph(cs scpb.CurrentState) (_ *scgraph.Graph, err error
 		return nil, err
 	}
 	// Iterate through each match of initial state target's to target rules
-	// and apply the relevant op edges to the graph. Copy out the elements
-	// to not mutate the database in place.
+	// and generate the relevant op edges. The targets are independent of one
+	// another and the op functions only read the targets, so this is sharded
+	// across workers. The edges are then added to the graph in the order of
+	// the targets, which keeps the graph independent of the scheduling.
 	type toAdd struct {
 		transition
-		n *screl.Node
+		n       *screl.Node
+		emitted []scop.Op
 	}
-	var edgesToAdd []toAdd
-	md := makeTargetsWithElementMap(cs)
-	for _, t := range r.targets {
-		edgesToAdd = edgesToAdd[:0]
-		if err := t.iterateFunc(g.Database(), func(n *screl.Node) error {
+	edgesToAdd := make([][]toAdd, len(r.targets))
+	errs := make([]error, len(r.targets))
+	md := makeTargetsWithElementMap(cs, resolver, activeVersion)
+	scparallel.Do(concurrency, len(r.targets), func(i int) {
+		t := &r.targets[i]
+		var edges []toAdd
+		if errs[i] = t.iterateFunc(g.Database(), func(n *screl.Node) error {
 			status := n.CurrentStatus
 			for _, op := range t.transitions {
 				if op.from == status {
-					edgesToAdd = append(edgesToAdd, toAdd{
+					edges = append(edges, toAdd{
 						transition: op,
 						n:          n,
 					})
@@ -69,21 +122,27 @@ func (r *registry) buildGraph(cs scpb.CurrentState) (_ *scgraph.Graph, err error
 				}
 			}
 			return nil
-		}); err != nil {
-			return nil, err
+		}); errs[i] != nil {
+			return
 		}
-		for _, e := range edgesToAdd {
-			var ops []scop.Op
-			if e.ops != nil {
-				ops = e.ops(e.n.Element(), &md)
+		for j := range edges {
+			if e := &edges[j]; e.ops != nil {
+				e.emitted = e.ops(e.n.Element(), &md)
 			}
+		}
+		edgesToAdd[i] = edges
+	})
+	for i := range r.targets {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		for _, e := range edgesToAdd[i] {
 			if err := g.AddOpEdges(
-				e.n.Target, e.from, e.to, e.revertible, e.canFail, e.minPhase, ops...,
+				e.n.Target, e.from, e.to, e.revertible, e.canFail, e.minPhase, e.emitted...,
 			); err != nil {
 				return nil, err
 			}
 		}
-
 	}
 	return g, nil
 }
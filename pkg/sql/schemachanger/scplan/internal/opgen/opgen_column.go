@@ -26,8 +26,8 @@ func init() {
 						Column: *protoutil.Clone(this).(*scpb.Column),
 					}
 				}),
-				emit(func(this *scpb.Column, md *targetsWithElementMap) *scop.LogEvent {
-					return newLogEventOp(this, md)
+				emit(func(this *scpb.Column, md *targetsWithElementMap, sd *scpb.SessionData) *scop.LogEvent {
+					return newLogEventOp(this, md, sd)
 				}),
 			),
 			to(scpb.Status_WRITE_ONLY,
@@ -39,9 +39,9 @@ func init() {
 				}),
 			),
 			to(scpb.Status_PUBLIC,
-				emit(func(this *scpb.Column, md *targetsWithElementMap) *scop.MakeColumnPublic {
+				emit(func(this *scpb.Column, md *targetsWithElementMap, sd *scpb.SessionData) *scop.MakeColumnPublic {
 					return &scop.MakeColumnPublic{
-						EventBase: newLogEventBase(this, md),
+						EventBase: newLogEventBase(this, md, sd),
 						TableID:   this.TableID,
 						ColumnID:  this.ColumnID,
 					}
@@ -62,8 +62,8 @@ func init() {
 						ColumnID: this.ColumnID,
 					}
 				}),
-				emit(func(this *scpb.Column, md *targetsWithElementMap) *scop.LogEvent {
-					return newLogEventOp(this, md)
+				emit(func(this *scpb.Column, md *targetsWithElementMap, sd *scpb.SessionData) *scop.LogEvent {
+					return newLogEventOp(this, md, sd)
 				}),
 			),
 			to(scpb.Status_DELETE_ONLY,
@@ -76,9 +76,9 @@ func init() {
 				}),
 			),
 			to(scpb.Status_ABSENT,
-				emit(func(this *scpb.Column, md *targetsWithElementMap) *scop.MakeColumnAbsent {
+				emit(func(this *scpb.Column, md *targetsWithElementMap, sd *scpb.SessionData) *scop.MakeColumnAbsent {
 					return &scop.MakeColumnAbsent{
-						EventBase: newLogEventBase(this, md),
+						EventBase: newLogEventBase(this, md, sd),
 						TableID:   this.TableID,
 						ColumnID:  this.ColumnID,
 					}
@@ -28,8 +28,8 @@ func init() {
 						PGAttributeNum: this.PgAttributeNum,
 					}
 				}),
-				emit(func(this *scpb.ColumnComment, md *targetsWithElementMap) *scop.LogEvent {
-					return newLogEventOp(this, md)
+				emit(func(this *scpb.ColumnComment, md *targetsWithElementMap, sd *scpb.SessionData) *scop.LogEvent {
+					return newLogEventOp(this, md, sd)
 				}),
 			),
 		),
@@ -43,8 +43,8 @@ func init() {
 						PgAttributeNum: this.PgAttributeNum,
 					}
 				}),
-				emit(func(this *scpb.ColumnComment, md *targetsWithElementMap) *scop.LogEvent {
-					return newLogEventOp(this, md)
+				emit(func(this *scpb.ColumnComment, md *targetsWithElementMap, sd *scpb.SessionData) *scop.LogEvent {
+					return newLogEventOp(this, md, sd)
 				}),
 			),
 		),
@@ -26,24 +26,8 @@ func init() {
 						Default: *protoutil.Clone(this).(*scpb.ColumnDefaultExpression),
 					}
 				}),
-				emit(func(this *scpb.ColumnDefaultExpression) *scop.UpdateTableBackReferencesInTypes {
-					if len(this.UsesTypeIDs) == 0 {
-						return nil
-					}
-					return &scop.UpdateTableBackReferencesInTypes{
-						TypeIDs:               this.UsesTypeIDs,
-						BackReferencedTableID: this.TableID,
-					}
-				}),
-				emit(func(this *scpb.ColumnDefaultExpression) *scop.UpdateBackReferencesInSequences {
-					if len(this.UsesSequenceIDs) == 0 {
-						return nil
-					}
-					return &scop.UpdateBackReferencesInSequences{
-						SequenceIDs:            this.UsesSequenceIDs,
-						BackReferencedTableID:  this.TableID,
-						BackReferencedColumnID: this.ColumnID,
-					}
+				emit(func(this *scpb.ColumnDefaultExpression) []scop.Op {
+					return updateColumnExpressionBackReferences(this.TableID, this.ColumnID, &this.Expression)
 				}),
 			),
 		),
@@ -56,24 +40,8 @@ func init() {
 						ColumnID: this.ColumnID,
 					}
 				}),
-				emit(func(this *scpb.ColumnDefaultExpression) *scop.UpdateTableBackReferencesInTypes {
-					if len(this.UsesTypeIDs) == 0 {
-						return nil
-					}
-					return &scop.UpdateTableBackReferencesInTypes{
-						TypeIDs:               this.UsesTypeIDs,
-						BackReferencedTableID: this.TableID,
-					}
-				}),
-				emit(func(this *scpb.ColumnDefaultExpression) *scop.UpdateBackReferencesInSequences {
-					if len(this.UsesSequenceIDs) == 0 {
-						return nil
-					}
-					return &scop.UpdateBackReferencesInSequences{
-						SequenceIDs:            this.UsesSequenceIDs,
-						BackReferencedTableID:  this.TableID,
-						BackReferencedColumnID: this.ColumnID,
-					}
+				emit(func(this *scpb.ColumnDefaultExpression) []scop.Op {
+					return updateColumnExpressionBackReferences(this.TableID, this.ColumnID, &this.Expression)
 				}),
 			),
 		),
@@ -26,24 +26,8 @@ func init() {
 						OnUpdate: *protoutil.Clone(this).(*scpb.ColumnOnUpdateExpression),
 					}
 				}),
-				emit(func(this *scpb.ColumnOnUpdateExpression) *scop.UpdateTableBackReferencesInTypes {
-					if len(this.UsesTypeIDs) == 0 {
-						return nil
-					}
-					return &scop.UpdateTableBackReferencesInTypes{
-						TypeIDs:               this.UsesTypeIDs,
-						BackReferencedTableID: this.TableID,
-					}
-				}),
-				emit(func(this *scpb.ColumnOnUpdateExpression) *scop.UpdateBackReferencesInSequences {
-					if len(this.UsesSequenceIDs) == 0 {
-						return nil
-					}
-					return &scop.UpdateBackReferencesInSequences{
-						SequenceIDs:            this.UsesSequenceIDs,
-						BackReferencedTableID:  this.TableID,
-						BackReferencedColumnID: this.ColumnID,
-					}
+				emit(func(this *scpb.ColumnOnUpdateExpression) []scop.Op {
+					return updateColumnExpressionBackReferences(this.TableID, this.ColumnID, &this.Expression)
 				}),
 			),
 		),
@@ -56,24 +40,8 @@ func init() {
 						ColumnID: this.ColumnID,
 					}
 				}),
-				emit(func(this *scpb.ColumnOnUpdateExpression) *scop.UpdateTableBackReferencesInTypes {
-					if len(this.UsesTypeIDs) == 0 {
-						return nil
-					}
-					return &scop.UpdateTableBackReferencesInTypes{
-						TypeIDs:               this.UsesTypeIDs,
-						BackReferencedTableID: this.TableID,
-					}
-				}),
-				emit(func(this *scpb.ColumnOnUpdateExpression) *scop.UpdateBackReferencesInSequences {
-					if len(this.UsesSequenceIDs) == 0 {
-						return nil
-					}
-					return &scop.UpdateBackReferencesInSequences{
-						SequenceIDs:            this.UsesSequenceIDs,
-						BackReferencedTableID:  this.TableID,
-						BackReferencedColumnID: this.ColumnID,
-					}
+				emit(func(this *scpb.ColumnOnUpdateExpression) []scop.Op {
+					return updateColumnExpressionBackReferences(this.TableID, this.ColumnID, &this.Expression)
 				}),
 			),
 		),
@@ -0,0 +1,53 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package opgen
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+)
+
+func init() {
+	opRegistry.register((*scpb.ColumnPrivileges)(nil),
+		toPublic(
+			scpb.Status_ABSENT,
+			to(scpb.Status_PUBLIC,
+				emit(func(this *scpb.ColumnPrivileges) *scop.UpsertColumnPrivileges {
+					return &scop.UpsertColumnPrivileges{
+						TableID:    this.TableID,
+						ColumnID:   this.ColumnID,
+						User:       this.UserName,
+						Privileges: this.Privileges,
+					}
+				}),
+				emit(func(this *scpb.ColumnPrivileges, md *targetsWithElementMap, sd *scpb.SessionData) *scop.LogColumnPrivilegesEvent {
+					return newLogColumnPrivilegesEventOp(this, md, sd)
+				}),
+			),
+		),
+		toAbsent(
+			scpb.Status_PUBLIC,
+			to(scpb.Status_ABSENT,
+				emit(func(this *scpb.ColumnPrivileges) *scop.RemoveColumnPrivileges {
+					return &scop.RemoveColumnPrivileges{
+						TableID:    this.TableID,
+						ColumnID:   this.ColumnID,
+						User:       this.UserName,
+						Privileges: this.Privileges,
+					}
+				}),
+				emit(func(this *scpb.ColumnPrivileges, md *targetsWithElementMap, sd *scpb.SessionData) *scop.LogColumnPrivilegesEvent {
+					return newLogColumnPrivilegesEventOp(this, md, sd)
+				}),
+			),
+		),
+	)
+}
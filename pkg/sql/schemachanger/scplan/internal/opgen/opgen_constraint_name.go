@@ -31,7 +31,7 @@ func init() {
 				// TODO(postamar): remove revertibility constraint when possible
 				revertible(false),
 				emit(func(this *scpb.ConstraintName) *scop.NotImplemented {
-					return notImplemented(this)
+					return notImplementedBecause(this, "removed along with the constraint")
 				}),
 			),
 		),
@@ -26,8 +26,8 @@ func init() {
 						Comment:    this.Comment,
 					}
 				}),
-				emit(func(this *scpb.DatabaseComment, md *targetsWithElementMap) *scop.LogEvent {
-					return newLogEventOp(this, md)
+				emit(func(this *scpb.DatabaseComment, md *targetsWithElementMap, sd *scpb.SessionData) *scop.LogEvent {
+					return newLogEventOp(this, md, sd)
 				}),
 			),
 		),
@@ -39,8 +39,8 @@ func init() {
 						DatabaseID: this.DatabaseID,
 					}
 				}),
-				emit(func(this *scpb.DatabaseComment, md *targetsWithElementMap) *scop.LogEvent {
-					return newLogEventOp(this, md)
+				emit(func(this *scpb.DatabaseComment, md *targetsWithElementMap, sd *scpb.SessionData) *scop.LogEvent {
+					return newLogEventOp(this, md, sd)
 				}),
 			),
 		),
@@ -52,8 +52,8 @@ func init() {
 				}),
 			),
 			to(scpb.Status_ABSENT,
-				emit(func(this *scpb.EnumType, md *targetsWithElementMap) *scop.LogEvent {
-					return newLogEventOp(this, md)
+				emit(func(this *scpb.EnumType, md *targetsWithElementMap, sd *scpb.SessionData) *scop.LogEvent {
+					return newLogEventOp(this, md, sd)
 				}),
 				emit(func(this *scpb.EnumType) *scop.DeleteDescriptor {
 					return &scop.DeleteDescriptor{
@@ -27,11 +27,12 @@ func init() {
 						Comment: this.Comment,
 					}
 				}),
-				emit(func(this *scpb.IndexComment, md *targetsWithElementMap) *scop.LogEvent {
-					return newLogEventOp(this, md)
+				emit(func(this *scpb.IndexComment, md *targetsWithElementMap, sd *scpb.SessionData) *scop.LogEvent {
+					return newLogEventOp(this, md, sd)
 				}),
 			),
 		),
+		toTransientAbsentLikePublic(),
 		toAbsent(
 			scpb.Status_PUBLIC,
 			to(scpb.Status_ABSENT,
@@ -41,8 +42,8 @@ func init() {
 						IndexID: this.IndexID,
 					}
 				}),
-				emit(func(this *scpb.IndexComment, md *targetsWithElementMap) *scop.LogEvent {
-					return newLogEventOp(this, md)
+				emit(func(this *scpb.IndexComment, md *targetsWithElementMap, sd *scpb.SessionData) *scop.LogEvent {
+					return newLogEventOp(this, md, sd)
 				}),
 			),
 		),
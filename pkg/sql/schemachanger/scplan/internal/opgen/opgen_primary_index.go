@@ -84,13 +84,24 @@ func init() {
 				}),
 			),
 			to(scpb.Status_PUBLIC,
-				emit(func(this *scpb.PrimaryIndex, md *targetsWithElementMap) *scop.MakeAddedPrimaryIndexPublic {
+				emit(func(this *scpb.PrimaryIndex, md *targetsWithElementMap, sd *scpb.SessionData) *scop.MakeAddedPrimaryIndexPublic {
 					return &scop.MakeAddedPrimaryIndexPublic{
-						EventBase: newLogEventBase(this, md),
+						EventBase: newLogEventBase(this, md, sd),
 						TableID:   this.TableID,
 						IndexID:   this.IndexID,
 					}
 				}),
+				emit(func(this *scpb.PrimaryIndex, md *targetsWithElementMap) *scop.CopyIndexZoneConfig {
+					if !this.InheritsSourceZoneConfig ||
+						!md.indexHasZoneConfig(this.TableID, this.SourceIndexID) {
+						return nil
+					}
+					return &scop.CopyIndexZoneConfig{
+						TableID:       this.TableID,
+						SourceIndexID: this.SourceIndexID,
+						IndexID:       this.IndexID,
+					}
+				}),
 			),
 		),
 		toTransientAbsentLikePublic(),
@@ -121,16 +132,19 @@ func init() {
 			equiv(scpb.Status_BACKFILLED),
 			equiv(scpb.Status_BACKFILL_ONLY),
 			to(scpb.Status_ABSENT,
-				emit(func(this *scpb.PrimaryIndex, md *targetsWithElementMap) *scop.CreateGcJobForIndex {
-					return &scop.CreateGcJobForIndex{
-						TableID:             this.TableID,
-						IndexID:             this.IndexID,
-						StatementForDropJob: statementForDropJob(this, md),
-					}
+				emit(func(this *scpb.PrimaryIndex, md *targetsWithElementMap) (
+					*scop.SetDroppedIndexGCTTL, *scop.CreateGcJobForIndex,
+				) {
+					return setDroppedIndexGCTTL(this.TableID, this.IndexID, md),
+						&scop.CreateGcJobForIndex{
+							TableID:             this.TableID,
+							IndexID:             this.IndexID,
+							StatementForDropJob: statementForDropJob(this, md),
+						}
 				}),
-				emit(func(this *scpb.PrimaryIndex, md *targetsWithElementMap) *scop.MakeIndexAbsent {
+				emit(func(this *scpb.PrimaryIndex, md *targetsWithElementMap, sd *scpb.SessionData) *scop.MakeIndexAbsent {
 					return &scop.MakeIndexAbsent{
-						EventBase: newLogEventBase(this, md),
+						EventBase: newLogEventBase(this, md, sd),
 						TableID:   this.TableID,
 						IndexID:   this.IndexID,
 					}
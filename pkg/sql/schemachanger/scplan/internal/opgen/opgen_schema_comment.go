@@ -26,8 +26,8 @@ func init() {
 						Comment:  this.Comment,
 					}
 				}),
-				emit(func(this *scpb.SchemaComment, md *targetsWithElementMap) *scop.LogEvent {
-					return newLogEventOp(this, md)
+				emit(func(this *scpb.SchemaComment, md *targetsWithElementMap, sd *scpb.SessionData) *scop.LogEvent {
+					return newLogEventOp(this, md, sd)
 				}),
 			),
 		),
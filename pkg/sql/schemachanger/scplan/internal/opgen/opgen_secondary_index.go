@@ -122,15 +122,18 @@ func init() {
 			equiv(scpb.Status_BACKFILLED),
 			equiv(scpb.Status_BACKFILL_ONLY),
 			to(scpb.Status_ABSENT,
-				emit(func(this *scpb.SecondaryIndex, md *targetsWithElementMap) *scop.LogEvent {
-					return newLogEventOp(this, md)
+				emit(func(this *scpb.SecondaryIndex, md *targetsWithElementMap, sd *scpb.SessionData) *scop.LogEvent {
+					return newLogEventOp(this, md, sd)
 				}),
-				emit(func(this *scpb.SecondaryIndex, md *targetsWithElementMap) *scop.CreateGcJobForIndex {
-					return &scop.CreateGcJobForIndex{
-						TableID:             this.TableID,
-						IndexID:             this.IndexID,
-						StatementForDropJob: statementForDropJob(this, md),
-					}
+				emit(func(this *scpb.SecondaryIndex, md *targetsWithElementMap) (
+					*scop.SetDroppedIndexGCTTL, *scop.CreateGcJobForIndex,
+				) {
+					return setDroppedIndexGCTTL(this.TableID, this.IndexID, md),
+						&scop.CreateGcJobForIndex{
+							TableID:             this.TableID,
+							IndexID:             this.IndexID,
+							StatementForDropJob: statementForDropJob(this, md),
+						}
 				}),
 				emit(func(this *scpb.SecondaryIndex) *scop.MakeIndexAbsent {
 					return &scop.MakeIndexAbsent{
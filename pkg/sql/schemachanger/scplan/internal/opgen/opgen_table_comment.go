@@ -26,8 +26,8 @@ func init() {
 						Comment: this.Comment,
 					}
 				}),
-				emit(func(this *scpb.TableComment, md *targetsWithElementMap) *scop.LogEvent {
-					return newLogEventOp(this, md)
+				emit(func(this *scpb.TableComment, md *targetsWithElementMap, sd *scpb.SessionData) *scop.LogEvent {
+					return newLogEventOp(this, md, sd)
 				}),
 			),
 		),
@@ -39,8 +39,8 @@ func init() {
 						TableID: this.TableID,
 					}
 				}),
-				emit(func(this *scpb.TableComment, md *targetsWithElementMap) *scop.LogEvent {
-					return newLogEventOp(this, md)
+				emit(func(this *scpb.TableComment, md *targetsWithElementMap, sd *scpb.SessionData) *scop.LogEvent {
+					return newLogEventOp(this, md, sd)
 				}),
 			),
 		),
@@ -0,0 +1,92 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package opgen
+
+import (
+	"reflect"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+	"github.com/cockroachdb/errors"
+)
+
+// PluginTarget defines the op edges of an element type towards one of its
+// target statuses, on behalf of a plugin. It is the counterpart of the target
+// specs built by toPublic, toAbsent and toTransientAbsent.
+type PluginTarget struct {
+	// To is the target status, which is either ABSENT, PUBLIC or
+	// TRANSIENT_ABSENT.
+	To scpb.Status
+
+	// From is the initial status.
+	From scpb.Status
+
+	// Transitions lead from the initial status to the target status.
+	Transitions []PluginTransition
+}
+
+// PluginTransition is a transition towards a PluginTarget.
+type PluginTransition struct {
+	// To is the status reached by the transition.
+	To scpb.Status
+
+	// NonRevertible is set if the schema change can't be rolled back once the
+	// transition has taken place.
+	NonRevertible bool
+
+	// Emit contains the op functions of the transition. Like those passed to
+	// emit, they take the element and return either one or more pointers to ops
+	// of the same type, or a []scop.Op.
+	Emit []interface{}
+}
+
+// RegisterPluginTargets registers the targets of an element type on behalf of
+// a plugin. Unlike register, it returns an error if the targets are invalid or
+// if some targets are already registered for the element type.
+func RegisterPluginTargets(plugin string, e scpb.Element, targets ...PluginTarget) error {
+	return opRegistry.registerPlugin(plugin, e, targets)
+}
+
+func (r *registry) registerPlugin(plugin string, e scpb.Element, targets []PluginTarget) error {
+	if r.frozen {
+		return errors.AssertionFailedf("registering %T: registry is frozen", e)
+	}
+	for _, t := range r.targets {
+		if reflect.TypeOf(t.e) != reflect.TypeOf(e) {
+			continue
+		}
+		owner := "the in-tree op specs"
+		if t.plugin != "" {
+			owner = "plugin " + t.plugin
+		}
+		return errors.Errorf("targets for %T are already registered by %s", e, owner)
+	}
+	specs := make([]targetSpec, len(targets))
+	for i, pt := range targets {
+		transitionSpecs := make([]transitionSpec, len(pt.Transitions))
+		for j, tr := range pt.Transitions {
+			ts := transitionSpec{to: tr.To, revertible: !tr.NonRevertible}
+			for _, fn := range tr.Emit {
+				ts.emitFns = append(ts.emitFns, emitFnSpec{fn: fn, reflective: true})
+			}
+			transitionSpecs[j] = ts
+		}
+		specs[i] = asTargetSpec(pt.To, pt.From, transitionSpecs...)
+	}
+	newTargets, err := makeTargets(e, specs)
+	if err != nil {
+		return errors.Wrapf(err, "element %T", e)
+	}
+	for i := range newTargets {
+		newTargets[i].plugin = plugin
+	}
+	r.targets = append(r.targets, newTargets...)
+	return nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package opgen
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterPlugin(t *testing.T) {
+	r := &registry{}
+	notImpl := func(this *scpb.Database) *scop.NotImplemented {
+		return notImplemented(this)
+	}
+	r.register(&scpb.Database{},
+		toPublic(scpb.Status_ABSENT, to(scpb.Status_PUBLIC, emit(notImpl))),
+		toAbsent(scpb.Status_PUBLIC, to(scpb.Status_ABSENT, emit(notImpl))),
+	)
+	schemaTargets := []PluginTarget{
+		{
+			To:   scpb.Status_PUBLIC,
+			From: scpb.Status_ABSENT,
+			Transitions: []PluginTransition{{
+				To: scpb.Status_PUBLIC,
+				Emit: []interface{}{func(this *scpb.Schema) *scop.MarkDescriptorAsPublic {
+					return &scop.MarkDescriptorAsPublic{DescID: this.SchemaID}
+				}},
+			}},
+		},
+		{
+			To:   scpb.Status_ABSENT,
+			From: scpb.Status_PUBLIC,
+			Transitions: []PluginTransition{{
+				To:            scpb.Status_ABSENT,
+				NonRevertible: true,
+				Emit: []interface{}{func(this *scpb.Schema) []scop.Op {
+					return []scop.Op{&scop.MarkDescriptorAsDropped{DescID: this.SchemaID}, nil}
+				}},
+			}},
+		},
+	}
+
+	// Element types can't have targets registered by several owners.
+	err := r.registerPlugin("foo", &scpb.Database{}, schemaTargets)
+	require.EqualError(t, err, "targets for *scpb.Database are already registered by the in-tree op specs")
+	require.NoError(t, r.registerPlugin("foo", &scpb.Schema{}, schemaTargets))
+	err = r.registerPlugin("bar", &scpb.Schema{}, schemaTargets)
+	require.EqualError(t, err, "targets for *scpb.Schema are already registered by plugin foo")
+
+	// Invalid targets are rejected.
+	err = r.registerPlugin("bar", &scpb.Table{}, schemaTargets)
+	require.Error(t, err)
+	require.Regexp(t, "element \\*scpb.Table: .* expected .* to be a func with one argument", err)
+	require.Len(t, r.targets, 4)
+
+	// The op functions of plugins are called via reflection.
+	schema := &scpb.Schema{SchemaID: 104}
+	for _, tg := range r.targets[2:] {
+		require.Equal(t, "foo", tg.plugin)
+		require.Len(t, tg.transitions, 1)
+		require.Equal(t, scop.MutationType, tg.transitions[0].opType)
+	}
+	require.Equal(t, scpb.Status_ABSENT, r.targets[2].status)
+	require.False(t, r.targets[2].transitions[0].revertible)
+	require.Equal(t,
+		[]scop.Op{&scop.MarkDescriptorAsDropped{DescID: 104}},
+		r.targets[2].transitions[0].ops(schema, nil /* md */),
+	)
+	require.Equal(t,
+		[]scop.Op{&scop.MarkDescriptorAsPublic{DescID: 104}},
+		r.targets[3].transitions[0].ops(schema, nil /* md */),
+	)
+
+	r.freeze()
+	err = r.registerPlugin("bar", &scpb.Table{}, nil)
+	require.EqualError(t, err, "registering *scpb.Table: registry is frozen")
+}
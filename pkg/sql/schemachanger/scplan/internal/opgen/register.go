@@ -29,6 +29,15 @@ func notImplemented(e scpb.Element) *scop.NotImplemented {
 	}
 }
 
+// notImplementedBecause is like notImplemented, but records why the
+// transition has no operations. The reason shows up in the annotations of the
+// plans which contain the transition.
+func notImplementedBecause(e scpb.Element, reason string) *scop.NotImplemented {
+	ret := notImplemented(e)
+	ret.Reason = reason
+	return ret
+}
+
 func toPublic(initialStatus scpb.Status, specs ...transitionSpec) targetSpec {
 	return asTargetSpec(scpb.Status_PUBLIC, initialStatus, specs...)
 }
@@ -50,19 +59,36 @@ func asTargetSpec(to, from scpb.Status, specs ...transitionSpec) targetSpec {
 }
 
 // register constructs all operations edges for a given element.
-// Intended to be called during init, register panics on any error.
+// Intended to be called during init, register panics if the registry is
+// frozen. Any other error is recorded instead, and reported by Freeze, so that
+// an invalid spec disables the declarative schema changer rather than
+// preventing the node from starting.
 func (r *registry) register(e scpb.Element, targetSpecs ...targetSpec) {
-	onErrPanic := func(err error) {
-		if err != nil {
-			panic(errors.NewAssertionErrorWithWrappedErrf(err, "element %T", e))
-		}
+	if r.frozen {
+		panic(errors.AssertionFailedf("element %T: registry is frozen", e))
 	}
+	targets, err := makeTargets(e, targetSpecs)
+	if err != nil {
+		r.err = errors.CombineErrors(r.err, errors.Wrapf(err, "element %T", e))
+		return
+	}
+	r.targets = append(r.targets, targets...)
+}
+
+// makeTargets constructs and validates the targets of an element.
+func makeTargets(e scpb.Element, targetSpecs []targetSpec) ([]target, error) {
 	fullTargetSpecs, err := populateAndValidateSpecs(targetSpecs)
-	onErrPanic(err)
+	if err != nil {
+		return nil, err
+	}
 	targets, err := buildTargets(e, fullTargetSpecs)
-	onErrPanic(err)
-	onErrPanic(validateTargets(targets))
-	r.targets = append(r.targets, targets...)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateTargets(targets); err != nil {
+		return nil, err
+	}
+	return targets, nil
 }
 
 func populateAndValidateSpecs(targetSpecs []targetSpec) ([]targetSpec, error) {
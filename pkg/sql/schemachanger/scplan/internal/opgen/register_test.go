@@ -11,11 +11,16 @@
 package opgen
 
 import (
+	"fmt"
 	"reflect"
 	"sort"
 	"testing"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
+	"github.com/stretchr/testify/require"
 )
 
 func TestOpGen(t *testing.T) {
@@ -53,3 +58,285 @@ func TestOpGen(t *testing.T) {
 		})
 	}
 }
+
+// TestRollbackCoverage checks that an element can be rolled back from any
+// status which it can reach while the schema change is still revertible. In
+// other words, the ops emitted by the revertible transitions towards a
+// non-ABSENT target must have counterparts in the transitions of the element's
+// ABSENT target, which undo their effects when the schema change is rolled
+// back.
+func TestRollbackCoverage(t *testing.T) {
+	for _, tg := range opRegistry.targets {
+		if tg.status == scpb.Status_ABSENT {
+			continue
+		}
+		elType := reflect.TypeOf(tg.e)
+		t.Run(fmt.Sprintf("%s/%s", elType.Elem().Name(), tg.status), func(t *testing.T) {
+			var drop *target
+			for i := range opRegistry.targets {
+				if other := &opRegistry.targets[i]; other.status == scpb.Status_ABSENT &&
+					reflect.TypeOf(other.e) == elType {
+					drop = other
+				}
+			}
+			require.NotNil(t, drop, "no ABSENT target")
+			canRollBackFrom := map[scpb.Status]bool{scpb.Status_ABSENT: true}
+			for _, tr := range drop.transitions {
+				canRollBackFrom[tr.from] = true
+			}
+			for _, tr := range tg.transitions {
+				if !tr.revertible {
+					break
+				}
+				require.Truef(t, canRollBackFrom[tr.to],
+					"cannot roll back from status %s reached by transition %s -> %s",
+					tr.to, tr.from, tr.to)
+			}
+		})
+	}
+}
+
+func TestRegisterAfterFreeze(t *testing.T) {
+	r := &registry{}
+	r.register(&scpb.Database{},
+		toPublic(
+			scpb.Status_ABSENT,
+			to(scpb.Status_PUBLIC, emit(func(this *scpb.Database) *scop.NotImplemented {
+				return notImplemented(this)
+			})),
+		),
+		toAbsent(
+			scpb.Status_PUBLIC,
+			to(scpb.Status_ABSENT, emit(func(this *scpb.Database) *scop.NotImplemented {
+				return notImplemented(this)
+			})),
+		),
+	)
+	r.freeze()
+	require.Len(t, r.targets, 2)
+	require.Panics(t, func() {
+		r.register(&scpb.Schema{}, toAbsent(
+			scpb.Status_PUBLIC,
+			to(scpb.Status_ABSENT, emit(func(this *scpb.Schema) *scop.NotImplemented {
+				return notImplemented(this)
+			})),
+		))
+	})
+	require.Len(t, r.targets, 2)
+}
+
+func TestEmitIfActive(t *testing.T) {
+	db := &scpb.Database{DatabaseID: 104}
+	cs := scpb.CurrentState{
+		TargetState: scpb.TargetState{
+			Statements: []scpb.Statement{{Statement: "DROP DATABASE db"}},
+			Targets:    []scpb.Target{scpb.MakeTarget(scpb.ToAbsent, db, nil /* metadata */)},
+		},
+	}
+	el := cs.Targets[0].Element()
+	markDropped := func(this *scpb.Database) *scop.MarkDescriptorAsDropped {
+		return &scop.MarkDescriptorAsDropped{DescID: this.DatabaseID}
+	}
+	notImpl := func(this *scpb.Database) *scop.NotImplemented {
+		return notImplemented(this)
+	}
+	backfill := func(this *scpb.Database) *scop.BackfillIndex {
+		return &scop.BackfillIndex{TableID: this.DatabaseID}
+	}
+	oldVersion := clusterversion.ClusterVersion{Version: clusterversion.ByKey(clusterversion.V22_1)}
+	newVersion := clusterversion.TestingClusterVersion
+
+	opTypes := func(fn opsFunc, v clusterversion.ClusterVersion) (ret []string) {
+		md := makeTargetsWithElementMap(cs, nil /* resolver */, v)
+		for _, op := range fn(el, &md) {
+			ret = append(ret, reflect.TypeOf(op).Elem().Name())
+		}
+		return ret
+	}
+
+	// The downlevel op is emitted on older cluster versions.
+	spec := to(scpb.Status_ABSENT, emitIfActive(clusterversion.Start22_2, markDropped, notImpl))
+	fn, opType, err := makeOpsFunc(el, spec.emitFns)
+	require.NoError(t, err)
+	require.Equal(t, scop.MutationType, opType)
+	require.Equal(t, []string{"MarkDescriptorAsDropped"}, opTypes(fn, newVersion))
+	require.Equal(t, []string{"NotImplemented"}, opTypes(fn, oldVersion))
+	// The downlevel op is also emitted when the cluster version is not set.
+	require.Equal(t, []string{"NotImplemented"}, opTypes(fn, clusterversion.ClusterVersion{}))
+
+	// Nothing is emitted on older cluster versions.
+	spec = to(scpb.Status_ABSENT,
+		emit(notImpl),
+		emitIfActive(clusterversion.Start22_2, markDropped, emitNothing),
+	)
+	fn, _, err = makeOpsFunc(el, spec.emitFns)
+	require.NoError(t, err)
+	require.Equal(t, []string{"NotImplemented", "MarkDescriptorAsDropped"}, opTypes(fn, newVersion))
+	require.Equal(t, []string{"NotImplemented"}, opTypes(fn, oldVersion))
+
+	// The downlevel behavior must be defined.
+	spec = to(scpb.Status_ABSENT, emitIfActive(clusterversion.Start22_2, markDropped, nil))
+	_, _, err = makeOpsFunc(el, spec.emitFns)
+	require.Regexp(t, "no downlevel behavior", err)
+
+	// The downlevel op must be of the same type.
+	spec = to(scpb.Status_ABSENT, emitIfActive(clusterversion.Start22_2, markDropped, backfill))
+	_, _, err = makeOpsFunc(el, spec.emitFns)
+	require.Regexp(t, "conflicting operation types", err)
+
+	// Registration fails without a downlevel behavior. The error is recorded
+	// instead of panicking, and the target is not registered.
+	r := &registry{}
+	r.register(db, toAbsent(
+		scpb.Status_PUBLIC,
+		to(scpb.Status_ABSENT, emitIfActive(clusterversion.Start22_2, markDropped, nil)),
+	))
+	require.Regexp(t, "element \\*scpb.Database: .*no downlevel behavior", r.err)
+	require.Empty(t, r.targets)
+}
+
+func TestGeneratedDispatch(t *testing.T) {
+	db := &scpb.Database{DatabaseID: 104}
+
+	// Op functions are called without reflection.
+	fn := generatedOpFunc(func(this *scpb.Database) *scop.MarkDescriptorAsDropped {
+		return &scop.MarkDescriptorAsDropped{DescID: this.DatabaseID}
+	})
+	require.NotNil(t, fn)
+	require.Equal(t, []scop.Op{&scop.MarkDescriptorAsDropped{DescID: 104}}, fn(db, nil /* md */, nil /* ops */))
+
+	// A nil op is not wrapped in a non-nil interface.
+	fn = generatedOpFunc(func(this *scpb.Database) *scop.MarkDescriptorAsDropped {
+		return nil
+	})
+	require.Empty(t, fn(db, nil /* md */, nil /* ops */))
+
+	// Op functions with signatures not found in the opgen_*.go files have no
+	// generated dispatch code.
+	spec := to(scpb.Status_ABSENT, emit(func(this *scpb.Database) *scop.BackfillIndex {
+		return &scop.BackfillIndex{TableID: this.DatabaseID}
+	}))
+	_, _, err := makeOpsFunc(db, spec.emitFns)
+	require.Regexp(t, "no generated dispatch code", err)
+}
+
+func TestMultipleOps(t *testing.T) {
+	idx := &scpb.SecondaryIndex{Index: scpb.Index{TableID: 104, IndexID: 2}}
+	expr := &scpb.ColumnDefaultExpression{
+		TableID:  104,
+		ColumnID: 2,
+		Expression: scpb.Expression{
+			UsesTypeIDs:     []catid.DescID{105},
+			UsesSequenceIDs: []catid.DescID{106},
+		},
+	}
+	cs := scpb.CurrentState{
+		TargetState: scpb.TargetState{
+			Targets: []scpb.Target{
+				scpb.MakeTarget(scpb.ToAbsent, idx, nil /* metadata */),
+				scpb.MakeTarget(scpb.ToAbsent, expr, nil /* metadata */),
+			},
+			DroppedIndexGCTTLSeconds: 60,
+		},
+	}
+	md := makeTargetsWithElementMap(cs, nil /* resolver */, clusterversion.TestingClusterVersion)
+	opTypes := func(fn opsFunc, el scpb.Element) (ret []string) {
+		for _, op := range fn(el, &md) {
+			ret = append(ret, reflect.TypeOf(op).Elem().Name())
+		}
+		return ret
+	}
+	gcOps := func(this *scpb.SecondaryIndex, md *targetsWithElementMap) (
+		*scop.SetDroppedIndexGCTTL, *scop.CreateGcJobForIndex,
+	) {
+		return setDroppedIndexGCTTL(this.TableID, this.IndexID, md),
+			&scop.CreateGcJobForIndex{TableID: this.TableID, IndexID: this.IndexID}
+	}
+	backRefOps := func(this *scpb.ColumnDefaultExpression) []scop.Op {
+		return updateColumnExpressionBackReferences(this.TableID, this.ColumnID, &this.Expression)
+	}
+
+	// Op functions may return several ops, which are all emitted.
+	typ, err := checkOpFunc(idx, gcOps)
+	require.NoError(t, err)
+	require.Equal(t, scop.MutationType, typ)
+	fn, typ, err := makeOpsFunc(idx, to(scpb.Status_ABSENT, emit(gcOps)).emitFns)
+	require.NoError(t, err)
+	require.Equal(t, scop.MutationType, typ)
+	require.Equal(t, []string{"SetDroppedIndexGCTTL", "CreateGcJobForIndex"}, opTypes(fn, idx))
+	// The nil ops are skipped.
+	md.DroppedIndexGCTTLSeconds = 0
+	require.Equal(t, []string{"CreateGcJobForIndex"}, opTypes(fn, idx))
+
+	// The ops returned together must be of the same type.
+	_, err = checkOpFunc(idx, func(this *scpb.SecondaryIndex) (*scop.MakeIndexAbsent, *scop.BackfillIndex) {
+		return nil, nil
+	})
+	require.Regexp(t, "to be of the same type", err)
+
+	// Op functions may return a slice of ops, the type of which is only known
+	// once they are emitted.
+	typ, err = checkOpFunc(expr, backRefOps)
+	require.NoError(t, err)
+	require.Zero(t, typ)
+	fn, typ, err = makeOpsFunc(expr, to(scpb.Status_ABSENT, emit(backRefOps)).emitFns)
+	require.NoError(t, err)
+	require.Equal(t, scop.MutationType, typ)
+	require.Equal(t, []string{
+		"UpdateTableBackReferencesInTypes", "UpdateBackReferencesInSequences",
+	}, opTypes(fn, expr))
+	expr.UsesTypeIDs = nil
+	require.Equal(t, []string{"UpdateBackReferencesInSequences"}, opTypes(fn, expr))
+}
+
+func TestSessionDataOpFuncs(t *testing.T) {
+	tbl := &scpb.Table{TableID: 104}
+	cs := scpb.CurrentState{
+		TargetState: scpb.TargetState{
+			Targets: []scpb.Target{
+				scpb.MakeTarget(scpb.ToAbsent, tbl, nil /* metadata */),
+			},
+			Statements:    []scpb.Statement{{Statement: "DROP TABLE t"}},
+			Authorization: scpb.Authorization{UserName: "root", AppName: "app"},
+			SessionData: scpb.SessionData{
+				UserName:        "foo",
+				ApplicationName: "bar",
+				Database:        "db",
+			},
+		},
+	}
+	logEvent := func(this *scpb.Table, md *targetsWithElementMap, sd *scpb.SessionData) *scop.LogEvent {
+		return newLogEventOp(this, md, sd)
+	}
+
+	// Op functions may take the session data snapshot after the map.
+	typ, err := checkOpFunc(tbl, logEvent)
+	require.NoError(t, err)
+	require.Equal(t, scop.MutationType, typ)
+	_, err = checkOpFunc(tbl, func(this *scpb.Table, sd *scpb.SessionData) *scop.LogEvent {
+		return nil
+	})
+	require.Regexp(t, "optionally followed by arguments of types", err)
+	_, err = checkOpFunc(tbl, func(
+		this *scpb.Table, md *targetsWithElementMap, a *scpb.Authorization,
+	) *scop.LogEvent {
+		return nil
+	})
+	require.Regexp(t, "optionally followed by arguments of types", err)
+
+	for _, reflective := range []bool{false, true} {
+		fn, _, err := makeOpsFunc(tbl, []emitFnSpec{{fn: logEvent, reflective: reflective}})
+		require.NoError(t, err)
+		md := makeTargetsWithElementMap(cs, nil /* resolver */, clusterversion.TestingClusterVersion)
+		ops := fn(tbl, &md)
+		require.Len(t, ops, 1)
+		require.Equal(t, scpb.Authorization{UserName: "foo", AppName: "bar"},
+			ops[0].(*scop.LogEvent).Authorization)
+	}
+
+	// The states built before the snapshot was introduced fall back to the
+	// authorization.
+	cs.SessionData = scpb.SessionData{}
+	md := makeTargetsWithElementMap(cs, nil /* resolver */, clusterversion.TestingClusterVersion)
+	require.Equal(t, scpb.SessionData{UserName: "root", ApplicationName: "app"}, md.SessionData)
+}
@@ -10,7 +10,10 @@
 
 package opgen
 
-import "github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+import (
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+)
 
 type targetSpec struct {
 	from, to        scpb.Status
@@ -21,7 +24,7 @@ type transitionSpec struct {
 	from       scpb.Status
 	to         scpb.Status
 	revertible bool
-	emitFns    []interface{}
+	emitFns    []emitFnSpec
 }
 
 type transitionProperty interface {
@@ -43,10 +46,33 @@ func revertible(b bool) transitionProperty {
 	return revertibleProperty(b)
 }
 
+// emit adds an op function to the transition. The op function takes the
+// element, optionally followed by a *targetsWithElementMap and then by the
+// *scpb.SessionData snapshot of the session which built the schema change,
+// and returns either one or more pointers to ops of the same type, for ops
+// which are emitted together, or a []scop.Op. The nil ops which it returns
+// are not emitted.
 func emit(fn interface{}) transitionProperty {
-	return emitFnSpec{fn}
+	return emitFnSpec{fn: fn}
 }
 
+// emitIfActive is like emit, except that fn only emits its op when the cluster
+// version is at least minVersion. On older cluster versions, the downlevel
+// behavior applies instead: downlevel is either another op function, which
+// emits its op in lieu of fn, or emitNothing. The downlevel behavior must be
+// defined for every version-gated op.
+func emitIfActive(
+	minVersion clusterversion.Key, fn interface{}, downlevel interface{},
+) transitionProperty {
+	return emitFnSpec{fn: fn, versioned: true, minVersion: minVersion, downlevel: downlevel}
+}
+
+// emitNothing is the downlevel behavior passed to emitIfActive for ops which
+// are not emitted at all on older cluster versions.
+var emitNothing interface{} = emitNothingSpec{}
+
+type emitNothingSpec struct{}
+
 type revertibleProperty bool
 
 func (r revertibleProperty) apply(spec *transitionSpec) {
@@ -57,8 +83,18 @@ var _ transitionProperty = revertibleProperty(true)
 
 type emitFnSpec struct {
 	fn interface{}
+
+	// versioned is set for ops emitted by emitIfActive, in which case fn only
+	// applies as of minVersion and downlevel applies otherwise.
+	versioned  bool
+	minVersion clusterversion.Key
+	downlevel  interface{}
+
+	// reflective is set for the op functions of plugins, which have no
+	// generated dispatch code and are called via reflection instead.
+	reflective bool
 }
 
 func (e emitFnSpec) apply(spec *transitionSpec) {
-	spec.emitFns = append(spec.emitFns, e.fn)
+	spec.emitFns = append(spec.emitFns, e)
 }
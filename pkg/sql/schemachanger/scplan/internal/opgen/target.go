@@ -24,6 +24,9 @@ type target struct {
 	status      scpb.Status
 	transitions []transition
 	iterateFunc func(*rel.Database, func(*screl.Node) error) error
+
+	// plugin is the name of the plugin which registered the target, if any.
+	plugin string
 }
 
 // transition represents a transition from one status to the next towards a
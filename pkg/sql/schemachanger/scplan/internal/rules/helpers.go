@@ -361,7 +361,7 @@ func isColumnDependent(e scpb.Element) bool {
 	switch e.(type) {
 	case *scpb.ColumnType:
 		return true
-	case *scpb.ColumnName, *scpb.ColumnComment, *scpb.IndexColumn:
+	case *scpb.ColumnName, *scpb.ColumnComment, *scpb.ColumnPrivileges, *scpb.IndexColumn:
 		return true
 	}
 	return isColumnTypeDependent(e)
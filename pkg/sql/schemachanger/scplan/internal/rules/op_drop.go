@@ -13,7 +13,6 @@ package rules
 import (
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/rel"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
-	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/screl"
 )
 
 // When dropping a table or a view, skip all removal ops for column elements
@@ -38,7 +37,7 @@ func init() {
 	registerOpRule(
 		"skip column removal ops on relation drop",
 		column.node,
-		screl.MustQuery(
+		rel.Clauses{
 			relation.Type(
 				(*scpb.Table)(nil),
 				(*scpb.View)(nil),
@@ -58,13 +57,13 @@ func init() {
 				scpb.Status_PUBLIC,
 				scpb.Status_WRITE_ONLY,
 			),
-		),
+		},
 	)
 
 	registerOpRule(
 		"skip column dependents removal ops on relation drop",
 		dep.node,
-		screl.MustQuery(
+		rel.Clauses{
 			relation.Type(
 				(*scpb.Table)(nil),
 				(*scpb.View)(nil),
@@ -74,6 +73,7 @@ func init() {
 			),
 			dep.Type(
 				(*scpb.ColumnName)(nil),
+				(*scpb.ColumnPrivileges)(nil),
 			),
 
 			joinOnDescID(relation, column, relationID),
@@ -85,7 +85,7 @@ func init() {
 			column.targetStatus(scpb.ToAbsent),
 			dep.joinTargetNode(),
 			dep.targetStatus(scpb.ToAbsent),
-		),
+		},
 	)
 }
 
@@ -101,7 +101,7 @@ func init() {
 	registerOpRule(
 		"skip index removal ops on relation drop",
 		index.node,
-		screl.MustQuery(
+		rel.Clauses{
 			relation.Type(
 				(*scpb.Table)(nil),
 				(*scpb.View)(nil),
@@ -118,13 +118,13 @@ func init() {
 			relation.targetStatus(scpb.ToAbsent),
 			index.joinTargetNode(),
 			index.targetStatus(scpb.ToAbsent),
-		),
+		},
 	)
 
 	registerOpRule(
 		"skip index dependents removal ops on relation drop",
 		dep.node,
-		screl.MustQuery(
+		rel.Clauses{
 			relation.Type(
 				(*scpb.Table)(nil),
 				(*scpb.View)(nil),
@@ -145,7 +145,7 @@ func init() {
 			index.targetStatus(scpb.ToAbsent),
 			dep.joinTargetNode(),
 			dep.targetStatus(scpb.ToAbsent),
-		),
+		},
 	)
 }
 
@@ -162,7 +162,7 @@ func init() {
 	registerOpRule(
 		"skip constraint removal ops on relation drop",
 		constraint.node,
-		screl.MustQuery(
+		rel.Clauses{
 			relation.Type(
 				(*scpb.Table)(nil),
 				(*scpb.View)(nil),
@@ -177,13 +177,13 @@ func init() {
 			relation.targetStatus(scpb.ToAbsent),
 			constraint.joinTargetNode(),
 			constraint.targetStatus(scpb.ToAbsent),
-		),
+		},
 	)
 
 	registerOpRule(
 		"skip constraint dependents removal ops on relation drop",
 		dep.node,
-		screl.MustQuery(
+		rel.Clauses{
 			relation.Type(
 				(*scpb.Table)(nil),
 				(*scpb.View)(nil),
@@ -206,7 +206,7 @@ func init() {
 			constraint.targetStatus(scpb.ToAbsent),
 			dep.joinTargetNode(),
 			dep.targetStatus(scpb.ToAbsent),
-		),
+		},
 	)
 }
 
@@ -226,7 +226,7 @@ func init() {
 	registerOpRule(
 		"skip element removal ops on descriptor drop",
 		dep.node,
-		screl.MustQuery(
+		rel.Clauses{
 			desc.typeFilter(IsDescriptor),
 			dep.Type(
 				(*scpb.ColumnFamily)(nil),
@@ -241,7 +241,7 @@ func init() {
 			desc.targetStatus(scpb.ToAbsent),
 			dep.joinTargetNode(),
 			dep.targetStatus(scpb.ToAbsent),
-		),
+		},
 	)
 }
 
@@ -255,7 +255,7 @@ func init() {
 	registerOpRule(
 		"skip table comment removal ops on descriptor drop",
 		dep.node,
-		screl.MustQuery(
+		rel.Clauses{
 			desc.Type(
 				(*scpb.Table)(nil),
 				(*scpb.View)(nil),
@@ -274,7 +274,7 @@ func init() {
 			desc.targetStatus(scpb.ToAbsent),
 			dep.joinTargetNode(),
 			dep.targetStatus(scpb.ToAbsent),
-		),
+		},
 	)
 }
 
@@ -287,7 +287,7 @@ func init() {
 	registerOpRule(
 		"skip table zone config removal ops on descriptor drop",
 		dep.node,
-		screl.MustQuery(
+		rel.Clauses{
 			desc.Type(
 				(*scpb.Table)(nil),
 				(*scpb.View)(nil),
@@ -303,7 +303,7 @@ func init() {
 			desc.targetStatus(scpb.ToAbsent),
 			dep.joinTargetNode(),
 			dep.targetStatus(scpb.ToAbsent),
-		),
+		},
 	)
 }
 
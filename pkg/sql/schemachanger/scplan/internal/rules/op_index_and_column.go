@@ -13,7 +13,6 @@ package rules
 import (
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/rel"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
-	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/screl"
 )
 
 // Skill all IndexColumn removal ops for indexes which are also being removed.
@@ -26,7 +25,7 @@ func init() {
 	registerOpRule(
 		"skip index-column removal ops on index removal",
 		ic.node,
-		screl.MustQuery(
+		rel.Clauses{
 			ic.Type((*scpb.IndexColumn)(nil)),
 			index.typeFilter(isIndex),
 			joinOnIndexID(ic, index, relationID, indexID),
@@ -35,6 +34,6 @@ func init() {
 			ic.currentStatus(scpb.Status_PUBLIC, scpb.Status_TRANSIENT_PUBLIC),
 			index.joinTarget(),
 			index.targetStatus(scpb.ToAbsent, scpb.Transient),
-		),
+		},
 	)
 }
@@ -0,0 +1,95 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package rules
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/rel"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scplan/internal/scgraph"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/screl"
+	"github.com/cockroachdb/errors"
+)
+
+// NodeVars refers to the related element, target and node entities of one
+// end of a dependency rule registered by a plugin.
+type NodeVars struct {
+	v nodeVars
+}
+
+// Element returns the variable bound to the element.
+func (v NodeVars) Element() rel.Var { return v.v.el }
+
+// Target returns the variable bound to the target.
+func (v NodeVars) Target() rel.Var { return v.v.target }
+
+// Node returns the variable bound to the node.
+func (v NodeVars) Node() rel.Var { return v.v.node }
+
+// Type binds the element to elements of the same types as the given values.
+func (v NodeVars) Type(valuesForTypeOf ...interface{}) rel.Clause {
+	return v.v.Type(valuesForTypeOf...)
+}
+
+// CurrentStatus constrains the current status of the node.
+func (v NodeVars) CurrentStatus(status ...scpb.Status) rel.Clause {
+	return v.v.currentStatus(status...)
+}
+
+// TargetStatus constrains the status of the target.
+func (v NodeVars) TargetStatus(status ...scpb.TargetStatus) rel.Clause {
+	return v.v.targetStatus(status...)
+}
+
+// JoinOnDescID joins the elements of both nodes on their descriptor ID.
+func (v NodeVars) JoinOnDescID(other NodeVars, descriptorIDVar rel.Var) rel.Clause {
+	return joinOnDescID(v.v, other.v, descriptorIDVar)
+}
+
+// RegisterPluginDepRule registers a dependency rule on behalf of a plugin,
+// like registerDepRule does for the in-tree rules. Unlike the latter, it
+// returns an error if the rule is invalid or if its name is already used by a
+// rule registered by someone else.
+func RegisterPluginDepRule(
+	plugin string,
+	ruleName scgraph.RuleName,
+	kind scgraph.DepEdgeKind,
+	fromEl, toEl string,
+	def func(from, to NodeVars) rel.Clauses,
+) error {
+	if registry.frozen {
+		return errors.AssertionFailedf("registering rule %s: registry is frozen", ruleName)
+	}
+	for _, dr := range registry.depRules {
+		if dr.name == ruleName && dr.plugin != plugin {
+			owner := "the in-tree rules"
+			if dr.plugin != "" {
+				owner = "plugin " + dr.plugin
+			}
+			return errors.Errorf("dep rule %q is already registered by %s", ruleName, owner)
+		}
+	}
+	from, to := mkNodeVars(fromEl), mkNodeVars(toEl)
+	c := def(NodeVars{v: from}, NodeVars{v: to})
+	c = append(c, from.joinTargetNode(), to.joinTargetNode())
+	q, err := rel.NewQuery(screl.Schema, c...)
+	if err != nil {
+		return errors.Wrapf(err, "dep rule %q", ruleName)
+	}
+	registry.depRules = append(registry.depRules, registeredDepRule{
+		name:   ruleName,
+		kind:   kind,
+		from:   from.node,
+		to:     to.node,
+		q:      q,
+		plugin: plugin,
+	})
+	return nil
+}
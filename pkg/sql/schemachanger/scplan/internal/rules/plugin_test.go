@@ -0,0 +1,114 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package rules
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/rel"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scplan/internal/opgen"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scplan/internal/scgraph"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/screl"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterPluginDepRule(t *testing.T) {
+	defer func(depRules []registeredDepRule) {
+		registry.depRules = depRules
+	}(registry.depRules)
+
+	// This rule contradicts the in-tree rule which requires the name of a
+	// column to be public before the column becomes public.
+	columnBeforeName := func(from, to NodeVars) rel.Clauses {
+		return rel.Clauses{
+			from.Type((*scpb.Column)(nil)),
+			to.Type((*scpb.ColumnName)(nil)),
+			from.JoinOnDescID(to, "table-id"),
+			from.Element().AttrEqVar(screl.ColumnID, "column-id"),
+			to.Element().AttrEqVar(screl.ColumnID, "column-id"),
+			from.TargetStatus(scpb.ToPublic),
+			to.TargetStatus(scpb.ToPublic),
+			from.CurrentStatus(scpb.Status_PUBLIC),
+			to.CurrentStatus(scpb.Status_PUBLIC),
+		}
+	}
+
+	// Rule names can't be shared by several owners.
+	err := RegisterPluginDepRule(
+		"foo", "column existence precedes column dependents", scgraph.Precedence,
+		"column", "name", columnBeforeName,
+	)
+	require.EqualError(t, err,
+		`dep rule "column existence precedes column dependents" is already registered by the in-tree rules`)
+	require.NoError(t, RegisterPluginDepRule(
+		"foo", "column public before its name", scgraph.Precedence,
+		"column", "name", columnBeforeName,
+	))
+	err = RegisterPluginDepRule(
+		"bar", "column public before its name", scgraph.Precedence,
+		"column", "name", columnBeforeName,
+	)
+	require.EqualError(t, err,
+		`dep rule "column public before its name" is already registered by plugin foo`)
+
+	// The cycle introduced by the rule is detected once the rule is applied.
+	cs := scpb.CurrentState{
+		TargetState: scpb.TargetState{
+			Statements: []scpb.Statement{{Statement: "ALTER TABLE t ADD COLUMN j INT8"}},
+			Targets: []scpb.Target{
+				scpb.MakeTarget(scpb.ToPublic, &scpb.Column{TableID: 104, ColumnID: 2}, nil),
+				scpb.MakeTarget(scpb.ToPublic, &scpb.ColumnName{TableID: 104, ColumnID: 2, Name: "j"}, nil),
+			},
+		},
+		Current: []scpb.Status{scpb.Status_ABSENT, scpb.Status_ABSENT},
+	}
+	g, err := opgen.BuildGraph(cs, nil /* resolver */, clusterversion.ClusterVersion{}, 0 /* concurrency */)
+	require.NoError(t, err)
+	require.NoError(t, ApplyDepRules(g, 0 /* concurrency */))
+	require.EqualError(t, g.Validate(), "graph is not acyclical")
+}
+
+type unknownAttr struct{}
+
+func (unknownAttr) String() string { return "unknown" }
+
+func TestRegisterInvalidRule(t *testing.T) {
+	defer func(depRules []registeredDepRule, opRules []registeredOpRule, err error) {
+		registry.depRules, registry.opRules, registry.err = depRules, opRules, err
+	}(registry.depRules, registry.opRules, registry.err)
+	numDepRules, numOpRules := len(registry.depRules), len(registry.opRules)
+
+	// Invalid rules are not registered, and their errors get reported by
+	// Freeze instead of panicking.
+	column := mkNodeVars("column")
+	registerOpRule("invalid op rule", column.node, rel.Clauses{
+		column.Type((*scpb.Column)(nil)),
+		column.el.AttrEq(unknownAttr{}, "foo"),
+		column.joinTargetNode(),
+	})
+	registerDepRule("invalid dep rule", scgraph.Precedence, "column", "name",
+		func(from, to nodeVars) rel.Clauses {
+			return rel.Clauses{
+				from.Type((*scpb.Column)(nil)),
+				to.currentStatus(),
+			}
+		})
+	require.Len(t, registry.depRules, numDepRules)
+	require.Len(t, registry.opRules, numOpRules)
+	err := Freeze()
+	require.Regexp(t, `invalid rules registry: .*op rule "invalid op rule": .*unknown attribute unknown`, err)
+	// The subsequent errors are retained as secondary errors.
+	require.Regexp(t, `dep rule "invalid dep rule": empty current status values`, fmt.Sprintf("%+v", err))
+	require.False(t, registry.frozen)
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/rel"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scplan/internal/scgraph"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scplan/internal/scparallel"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/screl"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
@@ -26,54 +27,80 @@ import (
 )
 
 // ApplyDepRules adds dependency edges to the graph according to the
-// registered dependency rules.
-func ApplyDepRules(g *scgraph.Graph) error {
-	for _, dr := range registry.depRules {
+// registered dependency rules. The rules are evaluated by up to concurrency
+// workers, see scparallel.Do, and the resulting edges are added to the graph
+// in the order of the rules.
+func ApplyDepRules(g *scgraph.Graph, concurrency int) error {
+	type depEdge struct {
+		from, to *screl.Node
+	}
+	edges := make([][]depEdge, len(registry.depRules))
+	errs := make([]error, len(registry.depRules))
+	scparallel.Do(concurrency, len(registry.depRules), func(i int) {
+		dr := &registry.depRules[i]
 		start := timeutil.Now()
-		var added int
-		if err := dr.q.Iterate(g.Database(), func(r rel.Result) error {
-			from := r.Var(dr.from).(*screl.Node)
-			to := r.Var(dr.to).(*screl.Node)
-			added++
-			return g.AddDepEdge(
-				dr.name, dr.kind, from.Target, from.CurrentStatus, to.Target, to.CurrentStatus,
-			)
-		}); err != nil {
-			return errors.Wrapf(err, "applying dep rule %s", dr.name)
+		if errs[i] = dr.q.Iterate(g.Database(), func(r rel.Result) error {
+			edges[i] = append(edges[i], depEdge{
+				from: r.Var(dr.from).(*screl.Node),
+				to:   r.Var(dr.to).(*screl.Node),
+			})
+			return nil
+		}); errs[i] != nil {
+			return
 		}
 		if log.V(2) {
 			log.Infof(
 				context.TODO(), "applying dep rule %s %d took %v",
-				dr.name, added, timeutil.Since(start),
+				dr.name, len(edges[i]), timeutil.Since(start),
 			)
 		}
+	})
+	for i, dr := range registry.depRules {
+		if errs[i] != nil {
+			return errors.Wrapf(errs[i], "applying dep rule %s", dr.name)
+		}
+		for _, e := range edges[i] {
+			if err := g.AddDepEdge(
+				dr.name, dr.kind, e.from.Target, e.from.CurrentStatus, e.to.Target, e.to.CurrentStatus,
+			); err != nil {
+				return errors.Wrapf(err, "applying dep rule %s", dr.name)
+			}
+		}
 	}
 	return nil
 }
 
 // ApplyOpRules marks op edges as no-op in a shallow copy of the graph according
-// to the registered rules.
-func ApplyOpRules(g *scgraph.Graph) (*scgraph.Graph, error) {
+// to the registered rules. The rules are evaluated by up to concurrency
+// workers, see scparallel.Do.
+func ApplyOpRules(g *scgraph.Graph, concurrency int) (*scgraph.Graph, error) {
 	db := g.Database()
-	m := make(map[*screl.Node][]scgraph.RuleName)
-	for _, rule := range registry.opRules {
-		var added int
+	nodes := make([][]*screl.Node, len(registry.opRules))
+	errs := make([]error, len(registry.opRules))
+	scparallel.Do(concurrency, len(registry.opRules), func(i int) {
+		rule := &registry.opRules[i]
 		start := timeutil.Now()
-		err := rule.q.Iterate(db, func(r rel.Result) error {
-			added++
-			n := r.Var(rule.from).(*screl.Node)
-			m[n] = append(m[n], rule.name)
+		if errs[i] = rule.q.Iterate(db, func(r rel.Result) error {
+			nodes[i] = append(nodes[i], r.Var(rule.from).(*screl.Node))
 			return nil
-		})
-		if err != nil {
-			return nil, errors.Wrapf(err, "applying op rule %s", rule.name)
+		}); errs[i] != nil {
+			return
 		}
 		if log.V(2) {
 			log.Infof(
 				context.TODO(), "applying op rule %s %d took %v",
-				rule.name, added, timeutil.Since(start),
+				rule.name, len(nodes[i]), timeutil.Since(start),
 			)
 		}
+	})
+	m := make(map[*screl.Node][]scgraph.RuleName)
+	for i, rule := range registry.opRules {
+		if errs[i] != nil {
+			return nil, errors.Wrapf(errs[i], "applying op rule %s", rule.name)
+		}
+		for _, n := range nodes[i] {
+			m[n] = append(m[n], rule.name)
+		}
 	}
 	// Mark any op edges from these nodes as no-op.
 	ret := g.ShallowClone()
@@ -89,6 +116,37 @@ func ApplyOpRules(g *scgraph.Graph) (*scgraph.Graph, error) {
 var registry struct {
 	depRules []registeredDepRule
 	opRules  []registeredOpRule
+
+	// frozen is set once all the rules have been registered, after which
+	// the registry is read-only and may be used concurrently.
+	frozen bool
+
+	// err records the errors encountered while registering the in-tree rules,
+	// which are reported by Freeze.
+	err error
+}
+
+// Freeze marks the registry as immutable. Any subsequent rule registration
+// panics. It is intended to be called once, after all the package init
+// functions have registered their rules and before any planning takes place.
+// It returns an error, leaving the registry as is, if any of the in-tree rules
+// failed to register.
+func Freeze() error {
+	if registry.err != nil {
+		return errors.NewAssertionErrorWithWrappedErrf(registry.err, "invalid rules registry")
+	}
+	registry.frozen = true
+	// Clip the capacities so that an append to the slices can never write
+	// into the backing arrays shared by concurrent readers.
+	registry.depRules = registry.depRules[:len(registry.depRules):len(registry.depRules)]
+	registry.opRules = registry.opRules[:len(registry.opRules):len(registry.opRules)]
+	return nil
+}
+
+func assertNotFrozen(ruleName scgraph.RuleName) {
+	if registry.frozen {
+		panic(errors.AssertionFailedf("registering rule %s: registry is frozen", ruleName))
+	}
 }
 
 type registeredDepRule struct {
@@ -96,6 +154,9 @@ type registeredDepRule struct {
 	from, to rel.Var
 	q        *rel.Query
 	kind     scgraph.DepEdgeKind
+
+	// plugin is the name of the plugin which registered the rule, if any.
+	plugin string
 }
 
 type registeredOpRule struct {
@@ -106,29 +167,44 @@ type registeredOpRule struct {
 
 // registerDepRule registers a rule from which a set of dependency edges will
 // be derived in a graph. The edge will be formed from the node containing
-// the fromEl entity to the node containing the toEl entity.
+// the fromEl entity to the node containing the toEl entity. An invalid rule is
+// not registered, and its error is reported by Freeze.
 func registerDepRule(
 	ruleName scgraph.RuleName,
 	kind scgraph.DepEdgeKind,
 	fromEl, toEl string,
 	def func(from, to nodeVars) rel.Clauses,
 ) {
+	assertNotFrozen(ruleName)
 	from, to := mkNodeVars(fromEl), mkNodeVars(toEl)
-	c := def(from, to)
-	c = append(c, from.joinTargetNode(), to.joinTargetNode())
+	q, err := makeRuleQuery(func() rel.Clauses {
+		c := def(from, to)
+		return append(c, from.joinTargetNode(), to.joinTargetNode())
+	})
+	if err != nil {
+		recordRegistrationError(errors.Wrapf(err, "dep rule %q", ruleName))
+		return
+	}
 	registry.depRules = append(registry.depRules, registeredDepRule{
 		name: ruleName,
 		kind: kind,
 		from: from.node,
 		to:   to.node,
-		q:    screl.MustQuery(c...),
+		q:    q,
 	})
 }
 
-// registerOpRule adds a graph q that will label as no-op the op edge originating
-// from this node. There can only be one such edge per node, as per the edge
-// definitions in opgen.
-func registerOpRule(rn scgraph.RuleName, from rel.Var, q *rel.Query) {
+// registerOpRule adds a graph query, built from the clauses, that will label
+// as no-op the op edge originating from this node. There can only be one such
+// edge per node, as per the edge definitions in opgen. An invalid rule is not
+// registered, and its error is reported by Freeze.
+func registerOpRule(rn scgraph.RuleName, from rel.Var, c rel.Clauses) {
+	assertNotFrozen(rn)
+	q, err := makeRuleQuery(func() rel.Clauses { return c })
+	if err != nil {
+		recordRegistrationError(errors.Wrapf(err, "op rule %q", rn))
+		return
+	}
 	registry.opRules = append(registry.opRules, registeredOpRule{
 		name: rn,
 		from: from,
@@ -136,6 +212,30 @@ func registerOpRule(rn scgraph.RuleName, from rel.Var, q *rel.Query) {
 	})
 }
 
+// makeRuleQuery builds the query of a rule from the clauses returned by the
+// function, turning any panic into an error.
+func makeRuleQuery(clauses func() rel.Clauses) (q *rel.Query, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			rAsErr, ok := r.(error)
+			if !ok {
+				rAsErr = errors.AssertionFailedf("%v", r)
+			}
+			err = rAsErr
+		}
+	}()
+	return rel.NewQuery(screl.Schema, clauses()...)
+}
+
+// recordRegistrationError records an error encountered while registering the
+// in-tree rules. Rules are registered by the package init functions, which
+// therefore don't panic on invalid rules: the errors get reported by Freeze,
+// which disables the declarative schema changer instead of preventing the
+// node from starting.
+func recordRegistrationError(err error) {
+	registry.err = errors.CombineErrors(registry.err, err)
+}
+
 // nodeVars represents three variables intended to refer to
 // related element, target, and node entities.
 type nodeVars struct {
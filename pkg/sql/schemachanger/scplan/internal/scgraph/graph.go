@@ -16,6 +16,7 @@ import (
 	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/rel"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scfmt"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/screl"
@@ -114,7 +115,8 @@ func New(cs scpb.CurrentState) (*Graph, error) {
 	for i, status := range cs.Current {
 		t := &cs.Targets[i]
 		if existing, ok := g.targetIdxMap[t]; ok {
-			return nil, errors.Errorf("invalid initial state contains duplicate target: %v and %v", *t, cs.Targets[existing])
+			return nil, errors.Errorf("invalid initial state contains duplicate target: %s and %s",
+				scfmt.Target(t), scfmt.Target(&cs.Targets[existing]))
 		}
 		idx := len(g.targets)
 		g.targetIdxMap[t] = idx
@@ -180,7 +182,7 @@ func (g *Graph) getOrCreateNode(t *scpb.Target, s scpb.Status) (*screl.Node, err
 func (g *Graph) getTargetStatusMap(target *scpb.Target) map[scpb.Status]*screl.Node {
 	idx, ok := g.targetIdxMap[target]
 	if !ok {
-		panic(errors.Errorf("target %v does not exist", target))
+		panic(errors.Errorf("target %s does not exist", scfmt.Target(target)))
 	}
 	return g.targetNodes[idx]
 }
@@ -34,11 +34,11 @@ import (
 
 // StagesURL returns a URL to a rendering of the stages of the Plan.
 func StagesURL(cs scpb.CurrentState, g *scgraph.Graph, stages []scstage.Stage) (string, error) {
-	gv, err := DrawStages(cs, g, stages)
+	gv, err := DrawStages(cs, g, stages, nil /* annotate */)
 	if err != nil {
 		return "", err
 	}
-	return buildURL(gv)
+	return BuildURL(gv)
 }
 
 // DependenciesURL returns a URL to a rendering of the graph used to build
@@ -48,10 +48,11 @@ func DependenciesURL(cs scpb.CurrentState, g *scgraph.Graph) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return buildURL(gv)
+	return BuildURL(gv)
 }
 
-func buildURL(gv string) (string, error) {
+// BuildURL returns a URL to a rendering of the given graphviz string.
+func BuildURL(gv string) (string, error) {
 	var buf bytes.Buffer
 	w := gzip.NewWriter(&buf)
 	if _, err := io.WriteString(w, gv); err != nil {
@@ -68,12 +69,16 @@ func buildURL(gv string) (string, error) {
 	}).String(), nil
 }
 
-// DrawStages returns a graphviz string of the stages of the Plan.
-func DrawStages(cs scpb.CurrentState, g *scgraph.Graph, stages []scstage.Stage) (string, error) {
+// DrawStages returns a graphviz string of the stages of the Plan. If annotate
+// is not nil, the label of each stage is followed by the annotation it returns
+// for that stage, if any.
+func DrawStages(
+	cs scpb.CurrentState, g *scgraph.Graph, stages []scstage.Stage, annotate func(scstage.Stage) string,
+) (string, error) {
 	if len(stages) == 0 {
 		return "", errors.Errorf("missing stages in plan")
 	}
-	gv, err := drawStages(cs, g, stages)
+	gv, err := drawStages(cs, g, stages, annotate)
 	if err != nil {
 		return "", err
 	}
@@ -93,7 +98,7 @@ func DrawDependencies(cs scpb.CurrentState, g *scgraph.Graph) (string, error) {
 }
 
 func drawStages(
-	cs scpb.CurrentState, g *scgraph.Graph, stages []scstage.Stage,
+	cs scpb.CurrentState, g *scgraph.Graph, stages []scstage.Stage, annotate func(scstage.Stage) string,
 ) (*dot.Graph, error) {
 	dg := dot.NewGraph()
 	stagesSubgraph := dg.Subgraph("stages", dot.ClusterOption{})
@@ -137,6 +142,11 @@ func drawStages(
 	for _, st := range stages {
 		stage := st.String()
 		sg := stagesSubgraph.Subgraph(stage, dot.ClusterOption{})
+		if annotate != nil {
+			if annotation := annotate(st); annotation != "" {
+				sg.Label(stage + "\n" + annotation)
+			}
+		}
 		next := st.After
 		nextNodes := make([]dot.Node, len(curNodes))
 		m := make(map[scpb.Element][]scop.Op, len(curNodes))
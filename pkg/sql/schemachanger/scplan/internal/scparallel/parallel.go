@@ -0,0 +1,69 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package scparallel shards the planning work which is independent across
+// elements and rules over a pool of workers.
+package scparallel
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// Do calls fn for each index in [0, n), using up to concurrency goroutines.
+// A concurrency of zero or less stands for GOMAXPROCS. The calls may happen
+// in any order, so fn should store its results at their index for the caller
+// to merge them in a deterministic order once Do returns.
+//
+// Planning reports errors by panicking, so a panic in fn does not crash the
+// process: it is re-raised in the calling goroutine once all the workers are
+// done. When several calls panic, the panic with the lowest index is raised,
+// so that the outcome does not depend on the scheduling of the workers.
+func Do(concurrency, n int, fn func(i int)) {
+	if concurrency <= 0 {
+		concurrency = runtime.GOMAXPROCS(0)
+	}
+	if concurrency > n {
+		concurrency = n
+	}
+	if concurrency <= 1 {
+		for i := 0; i < n; i++ {
+			fn(i)
+		}
+		return
+	}
+	panics := make([]interface{}, n)
+	call := func(i int) {
+		defer func() {
+			if r := recover(); r != nil {
+				panics[i] = r
+			}
+		}()
+		fn(i)
+	}
+	var next int64 = -1
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for w := 0; w < concurrency; w++ {
+		go func() {
+			defer wg.Done()
+			for i := int(atomic.AddInt64(&next, 1)); i < n; i = int(atomic.AddInt64(&next, 1)) {
+				call(i)
+			}
+		}()
+	}
+	wg.Wait()
+	for _, r := range panics {
+		if r != nil {
+			panic(r)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scparallel
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDo(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, concurrency := range []int{0, 1, 2, 7, 100} {
+		t.Run(fmt.Sprintf("concurrency=%d", concurrency), func(t *testing.T) {
+			const n = 50
+			results := make([]int, n)
+			Do(concurrency, n, func(i int) { results[i] = i * i })
+			for i, r := range results {
+				require.Equal(t, i*i, r)
+			}
+			Do(concurrency, 0, func(i int) { t.Fatal("unexpected call") })
+
+			// The panic with the lowest index is re-raised, regardless of
+			// the order in which the calls happen.
+			require.PanicsWithError(t, "boom 3", func() {
+				Do(concurrency, n, func(i int) {
+					if i%10 == 3 {
+						panic(errors.Newf("boom %d", i))
+					}
+				})
+			})
+		})
+	}
+}
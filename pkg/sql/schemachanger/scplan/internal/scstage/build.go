@@ -29,9 +29,14 @@ import (
 
 // BuildStages builds the plan's stages for this and all subsequent phases.
 // Note that the scJobIDSupplier function is idempotent, and must return the
-// same value for all calls.
+// same value for all calls. In safe mode, the post-commit stages are kept as
+// small as possible.
 func BuildStages(
-	init scpb.CurrentState, phase scop.Phase, g *scgraph.Graph, scJobIDSupplier func() jobspb.JobID,
+	init scpb.CurrentState,
+	phase scop.Phase,
+	g *scgraph.Graph,
+	scJobIDSupplier func() jobspb.JobID,
+	safeMode bool,
 ) []Stage {
 	c := buildContext{
 		rollback:               init.InRollback,
@@ -41,6 +46,7 @@ func BuildStages(
 		startingStatuses:       init.Current,
 		startingPhase:          phase,
 		descIDs:                screl.AllTargetDescIDs(init.TargetState),
+		safeMode:               safeMode,
 	}
 	// Try building stages while ignoring revertibility constraints.
 	// This is fine as long as there are no post-commit stages.
@@ -95,6 +101,7 @@ type buildContext struct {
 	startingStatuses       []scpb.Status
 	startingPhase          scop.Phase
 	descIDs                catalog.DescriptorIDSet
+	safeMode               bool
 }
 
 func buildStages(bc buildContext) (stages []Stage) {
@@ -195,7 +202,18 @@ func (bc buildContext) makeStageBuilder(bs buildState) (sb stageBuilder) {
 		opTypes = []scop.Type{scop.MutationType}
 	}
 	for _, opType := range opTypes {
-		sb = bc.makeStageBuilderForType(bs, opType)
+		// In safe mode, try to make progress on a single descriptor at a time
+		// in the post-commit phases before falling back to all of them, which
+		// is necessary when same-stage dependencies span several descriptors.
+		if bc.safeMode && bs.phase >= scop.PostCommitPhase {
+			for _, descID := range bc.descIDs.Ordered() {
+				sb = bc.makeStageBuilderForType(bs, opType, descID)
+				if sb.canMakeProgress() {
+					return sb
+				}
+			}
+		}
+		sb = bc.makeStageBuilderForType(bs, opType, descpb.InvalidID)
 		if sb.canMakeProgress() {
 			break
 		}
@@ -204,13 +222,17 @@ func (bc buildContext) makeStageBuilder(bs buildState) (sb stageBuilder) {
 }
 
 // makeStageBuilderForType creates and populates a stage builder for the given
-// op type.
-func (bc buildContext) makeStageBuilderForType(bs buildState, opType scop.Type) stageBuilder {
+// op type. If descID is set, the stage only includes op edges with ops for
+// the targets of that descriptor.
+func (bc buildContext) makeStageBuilderForType(
+	bs buildState, opType scop.Type, descID descpb.ID,
+) stageBuilder {
 	numTargets := len(bc.targetState.Targets)
 	sb := stageBuilder{
 		bc:         bc,
 		bs:         bs,
 		opType:     opType,
+		descID:     descID,
 		current:    make([]currentTargetState, numTargets),
 		fulfilling: map[*screl.Node]struct{}{},
 		lut:        make(map[*scpb.Target]*currentTargetState, numTargets),
@@ -267,6 +289,7 @@ type stageBuilder struct {
 	bc         buildContext
 	bs         buildState
 	opType     scop.Type
+	descID     descpb.ID
 	current    []currentTargetState
 	fulfilling map[*screl.Node]struct{}
 	opEdges    []*scgraph.OpEdge
@@ -325,6 +348,10 @@ func (sb stageBuilder) isOutgoingOpEdgeAllowed(e *scgraph.OpEdge) bool {
 	if !e.IsPhaseSatisfied(sb.bs.phase) {
 		return false
 	}
+	if sb.descID != descpb.InvalidID && !sb.bc.g.IsNoOp(e) &&
+		screl.GetDescID(e.To().Element()) != sb.descID {
+		return false
+	}
 	// We allow non-revertible ops to be included at stages preceding
 	// PostCommitNonRevertible if nothing left in the schema change at this
 	// point can fail. The caller is responsible for detecting whether any
@@ -337,8 +364,9 @@ func (sb stageBuilder) isOutgoingOpEdgeAllowed(e *scgraph.OpEdge) bool {
 		!e.Revertible() &&
 		// We can't act on the knowledge that nothing remaining can fail while in
 		// StatementPhase because we don't know about what future targets may
-		// show up which could fail.
-		(sb.bs.phase < scop.PostCommitPhase || sb.anyRemainingOpsCanFail) {
+		// show up which could fail. Nor do we in safe mode, which keeps the
+		// revertible and non-revertible operations in separate stages.
+		(sb.bs.phase < scop.PostCommitPhase || sb.anyRemainingOpsCanFail || sb.bc.safeMode) {
 		return false
 	}
 	return true
@@ -584,6 +612,7 @@ func (bc buildContext) createSchemaChangeJobOp(
 		DescriptorIDs: descIDsPresentAfter.Ordered(),
 		NonCancelable: !isRevertible(next),
 		RunningStatus: runningStatus(next),
+		SafeMode:      bc.safeMode,
 	}
 }
 
@@ -644,10 +673,12 @@ func (bc buildContext) makeDescriptorStates(cur, next *Stage) map[descpb.ID]*scp
 	ds := make(map[descpb.ID]*scpb.DescriptorState, bc.descIDs.Len())
 	bc.descIDs.ForEach(func(id descpb.ID) {
 		ds[id] = &scpb.DescriptorState{
-			Authorization: bc.targetState.Authorization,
-			JobID:         bc.scJobID,
-			InRollback:    bc.rollback,
-			Revertible:    isRevertible(next),
+			Authorization:            bc.targetState.Authorization,
+			JobID:                    bc.scJobID,
+			InRollback:               bc.rollback,
+			Revertible:               isRevertible(next),
+			DroppedIndexGCTTLSeconds: bc.targetState.DroppedIndexGCTTLSeconds,
+			SessionData:              bc.targetState.SessionData,
 		}
 	})
 	mkStmt := func(rank uint32) scpb.DescriptorState_Statement {
@@ -680,8 +711,9 @@ func (bc buildContext) makeDescriptorStates(cur, next *Stage) map[descpb.ID]*scp
 	for i, t := range bc.targetState.Targets {
 		descID := screl.GetDescID(t.Element())
 		state := ds[descID]
-		stmtID := t.Metadata.StatementID
-		noteRelevantStatement(state, stmtID)
+		for _, stmtID := range t.Metadata.StatementIDs() {
+			noteRelevantStatement(state, stmtID)
+		}
 		state.Targets = append(state.Targets, t)
 		state.TargetRanks = append(state.TargetRanks, uint32(i))
 		state.CurrentStatuses = append(state.CurrentStatuses, cur.After[i])
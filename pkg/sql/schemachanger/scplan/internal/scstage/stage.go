@@ -12,6 +12,7 @@ package scstage
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
@@ -46,6 +47,32 @@ type Stage struct {
 	// relation to the other stages in the same phase. Note that Ordinal starts
 	// counting at 1. This is because this data is mainly useful for debugging.
 	Ordinal, StagesInPhase int
+
+	// Estimate is the estimated cost of a backfill or validation stage, derived
+	// from table statistics. It is nil for the other stages, and when there are
+	// no statistics for the tables scanned by the stage.
+	Estimate *Estimate
+}
+
+// Estimate is the estimated cost of a stage.
+type Estimate struct {
+	// Rows and Bytes are the number and the total size of the rows read.
+	Rows, Bytes uint64
+
+	// IndexWrites is the number of index entries written.
+	IndexWrites uint64
+
+	// Duration is the estimated duration, derived from the throughput of the
+	// past stages of the same type. It is zero when there is no such history.
+	Duration time.Duration
+}
+
+// Add adds the other estimate to this one.
+func (e *Estimate) Add(other Estimate) {
+	e.Rows += other.Rows
+	e.Bytes += other.Bytes
+	e.IndexWrites += other.IndexWrites
+	e.Duration += other.Duration
 }
 
 // Type returns the type of the operations in this stage.
@@ -56,6 +83,12 @@ func (s Stage) Type() scop.Type {
 	return s.EdgeOps[0].Type()
 }
 
+// CostClass returns the cost class of the most expensive operation in this
+// stage.
+func (s Stage) CostClass() scop.CostClass {
+	return scop.CostClassOf(s.Ops())
+}
+
 // Ops returns the operations in this stage.
 func (s Stage) Ops() []scop.Op {
 	ops := make([]scop.Op, 0, len(s.EdgeOps)+len(s.ExtraOps))
@@ -13,6 +13,7 @@ package scplan
 import (
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
@@ -40,6 +41,46 @@ type Params struct {
 	// SchemaChangerJobIDSupplier is used to return the JobID for a
 	// job if one should exist.
 	SchemaChangerJobIDSupplier func() jobspb.JobID
+
+	// DescriptorStateResolver, if set, provides facts about the descriptors
+	// which are not captured by the elements. When it is not set, the
+	// planner assumes the worst and may emit operations which turn out to
+	// be no-ops.
+	DescriptorStateResolver DescriptorStateResolver
+
+	// ActiveVersion is the active cluster version, which determines the
+	// operations emitted for version-gated transitions. When it is not set,
+	// the planner emits the operations for the oldest supported version.
+	ActiveVersion clusterversion.ClusterVersion
+
+	// SafeMode biases the partitioning of the post-commit phases towards more,
+	// smaller stages, at the expense of a longer overall duration. Notably,
+	// each stage then only changes a single descriptor whenever possible.
+	SafeMode bool
+
+	// TableStatsResolver, if set, provides the table statistics from which the
+	// cost of the backfill and validation stages is estimated.
+	TableStatsResolver TableStatsResolver
+
+	// SequentialBackfillThreshold is the estimated size, in bytes, above which
+	// the index backfills of a stage run one after the other instead of
+	// concurrently. Zero disables this.
+	SequentialBackfillThreshold int64
+
+	// ThroughputHistory, if set, provides the throughput of the backfill and
+	// validation stages of past schema changes, from which the duration of
+	// the estimated stages is derived.
+	ThroughputHistory ThroughputHistory
+
+	// PlanRollbacks, if set, has the plan of the rollback of each
+	// post-commit stage computed along with the plan itself. See
+	// Plan.Rollbacks.
+	PlanRollbacks bool
+
+	// Concurrency is the maximum number of goroutines which generate the op
+	// edges and evaluate the rules when building the graph. Zero stands for
+	// GOMAXPROCS. The plan does not depend on it.
+	Concurrency int
 }
 
 // Exported internal types
@@ -49,6 +90,13 @@ type (
 
 	// Stage is an exported alias of scstage.Stage.
 	Stage = scstage.Stage
+
+	// Estimate is an exported alias of scstage.Estimate.
+	Estimate = scstage.Estimate
+
+	// DescriptorStateResolver is an exported alias of
+	// opgen.DescriptorStateResolver.
+	DescriptorStateResolver = opgen.DescriptorStateResolver
 )
 
 // A Plan is a schema change plan, primarily containing ops to be executed that
@@ -59,6 +107,15 @@ type Plan struct {
 	Graph  *scgraph.Graph
 	JobID  jobspb.JobID
 	Stages []Stage
+
+	// Rollbacks are the plans of the rollback of the schema change, were it
+	// to fail in each of the post-commit stages, when PlanRollbacks is set in
+	// the params.
+	Rollbacks []RollbackPlan
+
+	// Annotations record the transitions in the stages which are not
+	// implemented by any ops, see Annotation.
+	Annotations []Annotation
 }
 
 // StagesForCurrentPhase returns the stages in the execution phase specified in
@@ -79,6 +136,11 @@ func MakePlan(initial scpb.CurrentState, params Params) (p Plan, err error) {
 		CurrentState: initial,
 		Params:       params,
 	}
+	// The registries are frozen before the first plan gets made, once the
+	// plugins have been registered by the init functions of their packages.
+	if err = VerifyRegistries(); err != nil {
+		return p, err
+	}
 	err = makePlan(&p)
 	if err != nil {
 		err = p.DecorateErrorWithPlanDetails(err)
@@ -98,7 +160,9 @@ func makePlan(p *Plan) (err error) {
 	}()
 	{
 		start := timeutil.Now()
-		p.Graph = buildGraph(p.CurrentState)
+		p.Graph = buildGraph(
+			p.CurrentState, p.Params.DescriptorStateResolver, p.Params.ActiveVersion, p.Params.Concurrency,
+		)
 		if log.V(2) {
 			log.Infof(context.TODO(), "graph generation took %v", timeutil.Since(start))
 		}
@@ -107,6 +171,7 @@ func makePlan(p *Plan) (err error) {
 		start := timeutil.Now()
 		p.Stages = scstage.BuildStages(
 			p.CurrentState, p.Params.ExecutionPhase, p.Graph, p.Params.SchemaChangerJobIDSupplier,
+			p.Params.SafeMode,
 		)
 		if log.V(2) {
 			log.Infof(context.TODO(), "stage generation took %v", timeutil.Since(start))
@@ -116,18 +181,32 @@ func makePlan(p *Plan) (err error) {
 		// Only get the job ID if it's actually been assigned already.
 		p.JobID = p.Params.SchemaChangerJobIDSupplier()
 	}
+	if err := estimateStages(p); err != nil {
+		panic(errors.Wrapf(err, "estimate stage costs"))
+	}
+	chooseBackfillStrategies(p)
+	if err := runPostProcessors(p); err != nil {
+		panic(errors.Wrapf(err, "post-process stages"))
+	}
 	if err := scstage.ValidateStages(p.TargetState, p.Stages, p.Graph); err != nil {
 		panic(errors.Wrapf(err, "invalid execution plan"))
 	}
+	annotatePlan(p)
+	planRollbacks(p)
 	return nil
 }
 
-func buildGraph(cs scpb.CurrentState) *scgraph.Graph {
-	g, err := opgen.BuildGraph(cs)
+func buildGraph(
+	cs scpb.CurrentState,
+	resolver DescriptorStateResolver,
+	activeVersion clusterversion.ClusterVersion,
+	concurrency int,
+) *scgraph.Graph {
+	g, err := opgen.BuildGraph(cs, resolver, activeVersion, concurrency)
 	if err != nil {
 		panic(errors.Wrapf(err, "build graph op edges"))
 	}
-	err = rules.ApplyDepRules(g)
+	err = rules.ApplyDepRules(g, concurrency)
 	if err != nil {
 		panic(errors.Wrapf(err, "build graph dep edges"))
 	}
@@ -135,7 +214,7 @@ func buildGraph(cs scpb.CurrentState) *scgraph.Graph {
 	if err != nil {
 		panic(errors.Wrapf(err, "validate graph"))
 	}
-	g, err = rules.ApplyOpRules(g)
+	g, err = rules.ApplyOpRules(g, concurrency)
 	if err != nil {
 		panic(errors.Wrapf(err, "mark op edges as no-op"))
 	}
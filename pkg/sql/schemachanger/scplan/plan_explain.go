@@ -14,9 +14,11 @@ import (
 	gojson "encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 	"unicode/utf8"
 
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scfmt"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scplan/internal/scgraph"
@@ -24,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scplan/internal/scstage"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/screl"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/treeprinter"
 	"github.com/cockroachdb/errors"
 	"gopkg.in/yaml.v2"
@@ -76,6 +79,25 @@ func (p Plan) StagesURL() (string, error) {
 	return scgraphviz.StagesURL(p.CurrentState, p.Graph, p.Stages)
 }
 
+// StagesDOT returns a graphviz rendering of the stages in the Plan, in which
+// the label of each stage is followed by the annotation returned by annotate
+// for that stage, if any.
+func (p Plan) StagesDOT(annotate func(Stage) string) (string, error) {
+	return scgraphviz.DrawStages(p.CurrentState, p.Graph, p.Stages, annotate)
+}
+
+// DependenciesDOT returns a graphviz rendering of the dependency graph in the
+// Plan.
+func (p Plan) DependenciesDOT() (string, error) {
+	return scgraphviz.DrawDependencies(p.CurrentState, p.Graph)
+}
+
+// VizURL returns a URL to a rendering of a graphviz string such as those
+// returned by StagesDOT and DependenciesDOT.
+func VizURL(dot string) (string, error) {
+	return scgraphviz.BuildURL(dot)
+}
+
 // ExplainViz returns graphviz renderings for EXPLAIN (DDL, VIZ) statements.
 func (p Plan) ExplainViz() (stagesURL, depsURL string, err error) {
 	stagesURL, err = p.StagesURL()
@@ -101,21 +123,284 @@ func (p Plan) ExplainVerbose() (string, error) {
 	return p.explain(treeprinter.BulletStyle)
 }
 
+// ExplainRollbacks returns a human-readable rendering of the rollback plans
+// of the post-commit stages, for EXPLAIN (DDL, ROLLBACK) statements. The plan
+// must have been made with PlanRollbacks set in its params.
+func (p Plan) ExplainRollbacks(verbose bool) (string, error) {
+	style := treeprinter.DefaultStyle
+	if verbose {
+		style = treeprinter.BulletStyle
+	}
+	tp := treeprinter.NewWithStyle(style)
+	root := tp.Child(p.explainTitle("Rollback plans for "))
+	var pn treeprinter.Node
+	for i, rp := range p.Rollbacks {
+		s := p.Stages[rp.StageIdx]
+		if i == 0 || s.Phase != p.Stages[p.Rollbacks[i-1].StageIdx].Phase {
+			pn = root.Childf("%s", s.Phase)
+		}
+		sn := pn.Childf("Stage %d of %d in %s", s.Ordinal, s.StagesInPhase, s.Phase)
+		switch {
+		case !rp.Revertible:
+			sn.Child("not revertible")
+		case rp.Err != nil:
+			sn.Childf("rollback cannot be planned: %v", rp.Err)
+		case len(rp.Plan.Stages) == 0:
+			sn.Child("nothing to roll back")
+		default:
+			if err := rp.Plan.explainStages(sn, style); err != nil {
+				return "", err
+			}
+		}
+	}
+	return tp.String(), nil
+}
+
+// ExplainJSON returns a machine-readable plan rendering for
+// EXPLAIN (DDL, JSON) statements. It contains the targets of the plan along
+// with their elements and statuses, the status transitions, operations and
+// annotations in each stage, and the dependency edges between the nodes of the graph. Targets
+// are referred to by their index in the targets array, and the output is
+// deterministic for a given plan.
+func (p Plan) ExplainJSON() (string, error) {
+	jp := explainJSONPlan{
+		Statements:   make([]string, 0, len(p.Statements)),
+		InRollback:   p.InRollback,
+		Targets:      make([]explainJSONTarget, 0, len(p.TargetState.Targets)),
+		Stages:       make([]explainJSONStage, 0, len(p.Stages)),
+		Dependencies: []explainJSONDep{},
+	}
+	for _, stmt := range p.Statements {
+		jp.Statements = append(jp.Statements, stmt.RedactedStatement)
+	}
+	targetIdx := make(map[*scpb.Target]int, len(p.TargetState.Targets))
+	for i := range p.TargetState.Targets {
+		t := &p.TargetState.Targets[i]
+		targetIdx[t] = i
+		elem, err := scgraphviz.ToMap(t.Element())
+		if err != nil {
+			return "", err
+		}
+		jt := explainJSONTarget{
+			Index:         i,
+			ElementType:   strings.TrimPrefix(fmt.Sprintf("%T", t.Element()), "*scpb."),
+			Element:       elem,
+			TargetStatus:  t.TargetStatus.String(),
+			CurrentStatus: p.Current[i].String(),
+		}
+		jt.Metadata.SubWorkID = t.Metadata.SubWorkID
+		jt.Metadata.SourceElementID = uint32(t.Metadata.SourceElementID)
+		jt.Metadata.StatementID = t.Metadata.StatementID
+		jt.Metadata.AdditionalStatementIDs = t.Metadata.AdditionalStatementIDs
+		jp.Targets = append(jp.Targets, jt)
+	}
+	for i, s := range p.Stages {
+		js := explainJSONStage{
+			Phase:         s.Phase.String(),
+			Ordinal:       s.Ordinal,
+			StagesInPhase: s.StagesInPhase,
+			Type:          strings.TrimSuffix(s.Type().String(), "Type"),
+			Transitions:   []explainJSONTransition{},
+			Ops:           []explainJSONOp{},
+			Estimate:      makeExplainJSONEstimate(s.Estimate),
+		}
+		for j, before := range s.Before {
+			if after := s.After[j]; before != after {
+				js.Transitions = append(js.Transitions, explainJSONTransition{
+					Target: j,
+					From:   before.String(),
+					To:     after.String(),
+				})
+			}
+		}
+		for _, op := range s.Ops() {
+			fields, err := scgraphviz.ToMap(op)
+			if err != nil {
+				return "", err
+			}
+			js.Ops = append(js.Ops, explainJSONOp{
+				Type:   strings.TrimPrefix(fmt.Sprintf("%T", op), "*scop."),
+				Fields: fields,
+			})
+		}
+		for _, a := range p.AnnotationsForStage(i) {
+			js.Annotations = append(js.Annotations, explainJSONAnnotation{
+				Target: targetIdx[a.Target],
+				From:   a.From.String(),
+				To:     a.To.String(),
+				Reason: a.Reason,
+			})
+		}
+		jp.Stages = append(jp.Stages, js)
+	}
+	if total, ok := p.EstimatedTotal(); ok {
+		jp.Estimate = makeExplainJSONEstimate(&total)
+	}
+	if p.Graph != nil {
+		if err := p.Graph.ForEachEdge(func(e scgraph.Edge) error {
+			de, ok := e.(*scgraph.DepEdge)
+			if !ok {
+				return nil
+			}
+			jd := explainJSONDep{
+				From:  explainJSONNode{Target: targetIdx[de.From().Target], Status: de.From().CurrentStatus.String()},
+				To:    explainJSONNode{Target: targetIdx[de.To().Target], Status: de.To().CurrentStatus.String()},
+				Kind:  de.Kind().String(),
+				Rules: make([]string, 0, len(de.Rules())),
+			}
+			for _, r := range de.RuleNames() {
+				jd.Rules = append(jd.Rules, string(r))
+			}
+			jp.Dependencies = append(jp.Dependencies, jd)
+			return nil
+		}); err != nil {
+			return "", err
+		}
+	}
+	// Sort the dependencies, since the order in which the graph stores them
+	// is not deterministic.
+	sort.Slice(jp.Dependencies, func(i, j int) bool {
+		a, b := jp.Dependencies[i], jp.Dependencies[j]
+		if a.From != b.From {
+			return a.From.less(b.From)
+		}
+		if a.To != b.To {
+			return a.To.less(b.To)
+		}
+		return a.Kind < b.Kind
+	})
+	jb, err := gojson.Marshal(jp)
+	if err != nil {
+		return "", err
+	}
+	return string(jb), nil
+}
+
+// explainJSONPlan is the top-level object in the output of ExplainJSON.
+// The field names are part of the output format and should not be changed.
+type explainJSONPlan struct {
+	Statements   []string             `json:"statements"`
+	InRollback   bool                 `json:"in_rollback"`
+	Targets      []explainJSONTarget  `json:"targets"`
+	Stages       []explainJSONStage   `json:"stages"`
+	Dependencies []explainJSONDep     `json:"dependencies"`
+	Estimate     *explainJSONEstimate `json:"estimate,omitempty"`
+}
+
+type explainJSONTarget struct {
+	Index         int         `json:"index"`
+	ElementType   string      `json:"element_type"`
+	Element       interface{} `json:"element"`
+	TargetStatus  string      `json:"target_status"`
+	CurrentStatus string      `json:"current_status"`
+	Metadata      struct {
+		SubWorkID       uint32 `json:"sub_work_id"`
+		SourceElementID uint32 `json:"source_element_id"`
+		StatementID     uint32 `json:"statement_id"`
+		// AdditionalStatementIDs is only set for the targets produced by
+		// several statements.
+		AdditionalStatementIDs []uint32 `json:"additional_statement_ids,omitempty"`
+	} `json:"metadata"`
+}
+
+type explainJSONStage struct {
+	Phase         string                  `json:"phase"`
+	Ordinal       int                     `json:"ordinal"`
+	StagesInPhase int                     `json:"stages_in_phase"`
+	Type          string                  `json:"type"`
+	Transitions   []explainJSONTransition `json:"transitions"`
+	Ops           []explainJSONOp         `json:"ops"`
+	Estimate      *explainJSONEstimate    `json:"estimate,omitempty"`
+	Annotations   []explainJSONAnnotation `json:"annotations,omitempty"`
+}
+
+type explainJSONEstimate struct {
+	Rows            uint64  `json:"rows"`
+	Bytes           uint64  `json:"bytes"`
+	IndexWrites     uint64  `json:"index_writes"`
+	DurationSeconds float64 `json:"duration_seconds,omitempty"`
+}
+
+func makeExplainJSONEstimate(e *Estimate) *explainJSONEstimate {
+	if e == nil {
+		return nil
+	}
+	return &explainJSONEstimate{
+		Rows:            e.Rows,
+		Bytes:           e.Bytes,
+		IndexWrites:     e.IndexWrites,
+		DurationSeconds: e.Duration.Seconds(),
+	}
+}
+
+type explainJSONTransition struct {
+	Target int    `json:"target"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+}
+
+type explainJSONAnnotation struct {
+	Target int    `json:"target"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Reason string `json:"reason,omitempty"`
+}
+
+type explainJSONOp struct {
+	Type   string      `json:"type"`
+	Fields interface{} `json:"fields"`
+}
+
+type explainJSONDep struct {
+	From  explainJSONNode `json:"from"`
+	To    explainJSONNode `json:"to"`
+	Kind  string          `json:"kind"`
+	Rules []string        `json:"rules"`
+}
+
+type explainJSONNode struct {
+	Target int    `json:"target"`
+	Status string `json:"status"`
+}
+
+func (n explainJSONNode) less(o explainJSONNode) bool {
+	if n.Target != o.Target {
+		return n.Target < o.Target
+	}
+	return n.Status < o.Status
+}
+
 func (p Plan) explain(style treeprinter.Style) (string, error) {
 	// Generate root node.
 	tp := treeprinter.NewWithStyle(style)
+	root := tp.Child(p.explainTitle("Schema change plan for "))
+	if err := p.explainStages(root, style); err != nil {
+		return "", err
+	}
+	if total, ok := p.EstimatedTotal(); ok {
+		root.Childf("estimated total cost: %s", formatEstimate(total))
+	}
+	return tp.String(), nil
+}
+
+// explainTitle returns the title of the rendering of the plan, made of the
+// given prefix followed by the statements of the schema change.
+func (p Plan) explainTitle(prefix string) string {
 	var sb strings.Builder
-	{
-		sb.WriteString("Schema change plan for ")
-		if p.InRollback {
-			sb.WriteString("rolling back ")
-		}
-		for _, stmt := range p.Statements {
-			sb.WriteString(strings.TrimSuffix(stmt.RedactedStatement, ";"))
-			sb.WriteString("; ")
-		}
+	sb.WriteString(prefix)
+	if p.InRollback {
+		sb.WriteString("rolling back ")
+	}
+	for _, stmt := range p.Statements {
+		sb.WriteString(strings.TrimSuffix(stmt.RedactedStatement, ";"))
+		sb.WriteString("; ")
 	}
-	root := tp.Child(sb.String())
+	return sb.String()
+}
+
+// explainStages renders the stages of the plan under the given node, grouped
+// by phase.
+func (p Plan) explainStages(root treeprinter.Node, style treeprinter.Style) error {
 	var pn treeprinter.Node
 	for i, s := range p.Stages {
 		// Generate stage node, grouped by phase.
@@ -125,14 +410,28 @@ func (p Plan) explain(style treeprinter.Style) (string, error) {
 		sn := pn.Childf("Stage %d of %d in %s", s.Ordinal, s.StagesInPhase, s.Phase)
 		// Generate status transition nodes, grouped by target type.
 		if err := p.explainTargets(s, sn, style); err != nil {
-			return "", err
+			return err
 		}
+		// Generate annotation nodes.
+		p.explainAnnotations(i, sn, style)
 		// Generate operations nodes.
 		if err := p.explainOps(s, sn, style); err != nil {
-			return "", err
+			return err
+		}
+		if s.Estimate != nil {
+			sn.Childf("estimated cost: %s", formatEstimate(*s.Estimate))
 		}
 	}
-	return tp.String(), nil
+	return nil
+}
+
+func formatEstimate(e Estimate) string {
+	ret := fmt.Sprintf("%s rows read (%s), %s index entries written",
+		humanizeutil.Count(e.Rows), humanizeutil.IBytes(int64(e.Bytes)), humanizeutil.Count(e.IndexWrites))
+	if e.Duration > 0 {
+		ret += fmt.Sprintf(", estimated duration %s", humanizeutil.Duration(e.Duration))
+	}
+	return ret
 }
 
 func (p Plan) explainTargets(s scstage.Stage, sn treeprinter.Node, style treeprinter.Style) error {
@@ -167,7 +466,7 @@ func (p Plan) explainTargets(s scstage.Stage, sn treeprinter.Node, style treepri
 			n, nodeFound := p.Graph.GetNode(t, before)
 			if !nodeFound {
 				return errors.Errorf("could not find node [[%s, %s], %s] in graph",
-					screl.ElementString(t.Element()), t.TargetStatus, before)
+					scfmt.Element(t.Element()), t.TargetStatus, before)
 			}
 			for n.CurrentStatus != after {
 				oe, edgeFound := p.Graph.GetOpEdgeFrom(n)
@@ -210,15 +509,15 @@ func (p Plan) explainTargets(s scstage.Stage, sn treeprinter.Node, style treepri
 			// Add element node and child rule nodes.
 			var en treeprinter.Node
 			if style == treeprinter.BulletStyle {
-				en = tn.Child(screl.ElementString(t.Element()))
+				en = tn.Child(scfmt.Element(t.Element()))
 				en.AddLine(fmt.Sprintf("%s → %s", before, after))
 			} else {
-				en = tn.Childf(fmtCompactTransition, before, after, screl.ElementString(t.Element()))
+				en = tn.Childf(fmtCompactTransition, before, after, scfmt.Element(t.Element()))
 			}
 			depEdges := depEdgeByElement[t.Element()]
 			for _, de := range depEdges {
 				rn := en.Childf("%s dependency from %s %s",
-					de.Kind(), de.From().CurrentStatus, screl.ElementString(de.From().Element()))
+					de.Kind(), de.From().CurrentStatus, scfmt.Element(de.From().Element()))
 				for _, r := range de.Rules() {
 					rn.AddLine(fmt.Sprintf("rule: %q", r.Name))
 				}
@@ -240,6 +539,28 @@ func (p Plan) explainTargets(s scstage.Stage, sn treeprinter.Node, style treepri
 	return nil
 }
 
+// explainAnnotations renders the annotations of the transitions in the stage
+// at the given index under the stage node.
+func (p Plan) explainAnnotations(stageIdx int, sn treeprinter.Node, style treeprinter.Style) {
+	annotations := p.AnnotationsForStage(stageIdx)
+	if len(annotations) == 0 {
+		return
+	}
+	plural := "s"
+	if len(annotations) == 1 {
+		plural = ""
+	}
+	an := sn.Childf("%d annotation%s", len(annotations), plural)
+	for _, a := range annotations {
+		if style == treeprinter.BulletStyle {
+			n := an.Child(scfmt.Element(a.Target.Element()))
+			n.AddLine(fmt.Sprintf("%s → %s: %s", a.From, a.To, a.reason()))
+		} else {
+			an.Childf("%s → %s %s: %s", a.From, a.To, scfmt.Element(a.Target.Element()), a.reason())
+		}
+	}
+}
+
 func (p Plan) explainOps(s scstage.Stage, sn treeprinter.Node, style treeprinter.Style) error {
 	ops := s.Ops()
 	if len(ops) == 0 {
@@ -17,9 +17,11 @@ import (
 	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scbuild"
@@ -36,6 +38,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/datadriven"
@@ -92,7 +95,7 @@ func TestPlanDataDriven(t *testing.T) {
 				})
 
 				if d.Cmd == "ops" {
-					return marshalOps(t, plan.TargetState, plan.Stages)
+					return marshalOps(t, plan.TargetState, plan.Stages) + marshalAnnotations(&plan)
 				}
 				return marshalDeps(t, &plan)
 			case "unimplemented":
@@ -117,6 +120,500 @@ func TestPlanDataDriven(t *testing.T) {
 	})
 }
 
+// TestPlanConcurrently plans the schema changes in the data-driven test corpus
+// from many goroutines at once and checks that the plans are identical to
+// those obtained sequentially. Under the race detector, this flushes out any
+// hidden mutable state shared by the planner, such as in the opgen and rules
+// registries.
+func TestPlanConcurrently(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	defer utilccl.TestingEnableEnterprise()()
+	ctx := context.Background()
+
+	const numWorkers = 8
+	params := scplan.Params{
+		ExecutionPhase:             scop.EarliestPhase,
+		SchemaChangerJobIDSupplier: func() jobspb.JobID { return 1 },
+	}
+	planToString := func(t *testing.T, plan scplan.Plan) string {
+		sctestutils.TruncateJobOps(&plan)
+		return marshalOps(t, plan.TargetState, plan.Stages) + marshalDeps(t, &plan)
+	}
+
+	datadriven.Walk(t, testutils.TestDataPath(t), func(t *testing.T, path string) {
+		s, sqlDB, _ := serverutils.StartServer(t, base.TestServerArgs{
+			DisableDefaultTestTenant: true,
+		})
+		defer s.Stopper().Stop(ctx)
+
+		// Build the initial states of all the schema changes in the file, in
+		// the order in which they appear relative to the setup statements.
+		tdb := sqlutils.MakeSQLRunner(sqlDB)
+		var states []scpb.CurrentState
+		datadriven.RunTest(t, path, func(t *testing.T, d *datadriven.TestData) string {
+			switch d.Cmd {
+			case "setup":
+				stmts, err := parser.Parse(d.Input)
+				require.NoError(t, err)
+				for _, stmt := range stmts {
+					tdb.Exec(t, stmt.SQL)
+				}
+			case "ops", "deps":
+				sctestutils.WithBuilderDependenciesFromTestServer(s, func(deps scbuild.Dependencies) {
+					stmts, err := parser.Parse(d.Input)
+					require.NoError(t, err)
+					var state scpb.CurrentState
+					for i := range stmts {
+						state, err = scbuild.Build(ctx, deps, state, stmts[i].AST)
+						require.NoError(t, err)
+					}
+					states = append(states, state)
+				})
+			}
+			return d.Expected
+		})
+
+		expected := make([]string, len(states))
+		for i, state := range states {
+			plan, err := scplan.MakePlan(state.DeepCopy(), params)
+			require.NoError(t, err)
+			expected[i] = planToString(t, plan)
+		}
+
+		// Each worker plans its own copy of every state, starting at a
+		// different offset to vary the interleavings.
+		plans := make([][]scplan.Plan, numWorkers)
+		g := ctxgroup.WithContext(ctx)
+		for w := 0; w < numWorkers; w++ {
+			w := w
+			workerStates := make([]scpb.CurrentState, len(states))
+			for i, state := range states {
+				workerStates[i] = state.DeepCopy()
+			}
+			plans[w] = make([]scplan.Plan, len(states))
+			g.GoCtx(func(ctx context.Context) error {
+				for j := range workerStates {
+					i := (j + w) % len(workerStates)
+					plan, err := scplan.MakePlan(workerStates[i], params)
+					if err != nil {
+						return err
+					}
+					plans[w][i] = plan
+				}
+				return nil
+			})
+		}
+		require.NoError(t, g.Wait())
+		for w := range plans {
+			for i, plan := range plans[w] {
+				require.Equalf(t, expected[i], planToString(t, plan),
+					"plan mismatch for schema change %d in worker %d", i, w)
+			}
+		}
+	})
+}
+
+// TestPostProcessors checks that the registered post-processors may inject ops
+// into the stages of a plan and that planning fails when they do anything else.
+func TestPostProcessors(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	s, sqlDB, _ := serverutils.StartServer(t, base.TestServerArgs{
+		DisableDefaultTestTenant: true,
+	})
+	defer s.Stopper().Stop(ctx)
+	tdb := sqlutils.MakeSQLRunner(sqlDB)
+	tdb.Exec(t, `CREATE TABLE t (i INT PRIMARY KEY)`)
+
+	var state scpb.CurrentState
+	sctestutils.WithBuilderDependenciesFromTestServer(s, func(deps scbuild.Dependencies) {
+		stmt, err := parser.ParseOne(`ALTER TABLE t ADD COLUMN j INT DEFAULT 1`)
+		require.NoError(t, err)
+		state, err = scbuild.Build(ctx, deps, scpb.CurrentState{}, stmt.AST)
+		require.NoError(t, err)
+	})
+	params := scplan.Params{
+		ExecutionPhase:             scop.EarliestPhase,
+		SchemaChangerJobIDSupplier: func() jobspb.JobID { return 1 },
+	}
+
+	// Inject an op into every mutation stage.
+	injected := &scop.NotImplemented{ElementType: "injected"}
+	cleanup := scplan.TestingRegisterPostProcessor("inject", func(p *scplan.Plan) error {
+		for i := range p.Stages {
+			if s := &p.Stages[i]; s.Type() == scop.MutationType {
+				s.ExtraOps = append(s.ExtraOps, injected)
+			}
+		}
+		return nil
+	})
+	plan, err := scplan.MakePlan(state.DeepCopy(), params)
+	require.NoError(t, err)
+	var numBackfillStages int
+	for _, s := range plan.Stages {
+		if s.Type() == scop.MutationType {
+			require.Containsf(t, s.ExtraOps, scop.Op(injected), "%s", s)
+		} else {
+			require.NotContainsf(t, s.Ops(), scop.Op(injected), "%s", s)
+			numBackfillStages++
+		}
+	}
+	require.NotZero(t, numBackfillStages)
+	cleanup()
+
+	for _, tc := range []struct {
+		name   string
+		fn     scplan.PostProcessor
+		expErr string
+	}{
+		{
+			name: "error",
+			fn: func(p *scplan.Plan) error {
+				return errors.New("boom")
+			},
+			expErr: `post-processor error: boom`,
+		},
+		{
+			name: "inject op of wrong type",
+			fn: func(p *scplan.Plan) error {
+				s := &p.Stages[0]
+				s.ExtraOps = append(s.ExtraOps, &scop.BackfillIndex{})
+				return nil
+			},
+			expErr: `injected \*scop.BackfillIndex op of type BackfillType`,
+		},
+		{
+			name: "remove edge op",
+			fn: func(p *scplan.Plan) error {
+				s := &p.Stages[0]
+				s.EdgeOps = s.EdgeOps[1:]
+				return nil
+			},
+			expErr: `ops originating from op-edges changed`,
+		},
+		{
+			name: "remove stage",
+			fn: func(p *scplan.Plan) error {
+				p.Stages = p.Stages[1:]
+				return nil
+			},
+			expErr: `number of stages changed`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			defer scplan.TestingRegisterPostProcessor(tc.name, tc.fn)()
+			_, err := scplan.MakePlan(state.DeepCopy(), params)
+			require.Regexp(t, tc.expErr, err)
+		})
+	}
+}
+
+// TestSafeMode checks that planning in safe mode splits the post-commit work
+// affecting several descriptors into more stages, and that the choice is
+// recorded in the schema change job.
+func TestSafeMode(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	s, sqlDB, _ := serverutils.StartServer(t, base.TestServerArgs{
+		DisableDefaultTestTenant: true,
+	})
+	defer s.Stopper().Stop(ctx)
+	tdb := sqlutils.MakeSQLRunner(sqlDB)
+	tdb.Exec(t, `CREATE TABLE t1 (i INT PRIMARY KEY)`)
+	tdb.Exec(t, `CREATE TABLE t2 (i INT PRIMARY KEY)`)
+
+	var state scpb.CurrentState
+	sctestutils.WithBuilderDependenciesFromTestServer(s, func(deps scbuild.Dependencies) {
+		stmts, err := parser.Parse(`
+ALTER TABLE t1 ADD COLUMN j INT DEFAULT 1;
+ALTER TABLE t2 ADD COLUMN j INT DEFAULT 1;
+`)
+		require.NoError(t, err)
+		for i := range stmts {
+			state, err = scbuild.Build(ctx, deps, state, stmts[i].AST)
+			require.NoError(t, err)
+		}
+	})
+	params := scplan.Params{
+		ExecutionPhase:             scop.EarliestPhase,
+		SchemaChangerJobIDSupplier: func() jobspb.JobID { return 1 },
+	}
+	numPostCommitStages := func(p scplan.Plan) (n int) {
+		for _, stage := range p.Stages {
+			if stage.Phase >= scop.PostCommitPhase {
+				n++
+			}
+		}
+		return n
+	}
+	safeModeOf := func(p scplan.Plan) bool {
+		for _, stage := range p.Stages {
+			for _, op := range stage.ExtraOps {
+				if op, ok := op.(*scop.CreateSchemaChangerJob); ok {
+					return op.SafeMode
+				}
+			}
+		}
+		t.Fatal("no schema changer job created")
+		return false
+	}
+
+	plan, err := scplan.MakePlan(state.DeepCopy(), params)
+	require.NoError(t, err)
+	require.False(t, safeModeOf(plan))
+	params.SafeMode = true
+	safePlan, err := scplan.MakePlan(state.DeepCopy(), params)
+	require.NoError(t, err)
+	require.True(t, safeModeOf(safePlan))
+	require.Greater(t, numPostCommitStages(safePlan), numPostCommitStages(plan))
+	final := func(p scplan.Plan) []scpb.Status { return p.Stages[len(p.Stages)-1].After }
+	require.Equal(t, final(plan), final(safePlan))
+}
+
+// fakeTableStatsResolver returns the same statistics for every table.
+type fakeTableStatsResolver struct {
+	stats scplan.TableStats
+	ok    bool
+}
+
+func (r fakeTableStatsResolver) TableStats(descpb.ID) (scplan.TableStats, bool, error) {
+	return r.stats, r.ok, nil
+}
+
+// fakeThroughputHistory returns the same throughput for every stage.
+type fakeThroughputHistory struct {
+	rowsPerSecond float64
+}
+
+func (h fakeThroughputHistory) Throughput(scop.Type, uint64) (float64, bool, error) {
+	return h.rowsPerSecond, h.rowsPerSecond > 0, nil
+}
+
+// TestEstimate checks that the backfill and validation stages are annotated
+// with their estimated cost when table statistics are available, and with
+// their estimated duration when there is a throughput history, and that the
+// backfills of the stages deemed too large run sequentially.
+func TestEstimate(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	s, sqlDB, _ := serverutils.StartServer(t, base.TestServerArgs{
+		DisableDefaultTestTenant: true,
+	})
+	defer s.Stopper().Stop(ctx)
+	tdb := sqlutils.MakeSQLRunner(sqlDB)
+	tdb.Exec(t, `CREATE TABLE t (i INT PRIMARY KEY)`)
+
+	var state scpb.CurrentState
+	sctestutils.WithBuilderDependenciesFromTestServer(s, func(deps scbuild.Dependencies) {
+		stmt, err := parser.ParseOne(`ALTER TABLE t ADD COLUMN j INT DEFAULT 1`)
+		require.NoError(t, err)
+		state, err = scbuild.Build(ctx, deps, scpb.CurrentState{}, stmt.AST)
+		require.NoError(t, err)
+	})
+	var history scplan.ThroughputHistory
+	makePlan := func(resolver scplan.TableStatsResolver, threshold int64) scplan.Plan {
+		plan, err := scplan.MakePlan(state.DeepCopy(), scplan.Params{
+			ExecutionPhase:              scop.EarliestPhase,
+			SchemaChangerJobIDSupplier:  func() jobspb.JobID { return 1 },
+			TableStatsResolver:          resolver,
+			ThroughputHistory:           history,
+			SequentialBackfillThreshold: threshold,
+		})
+		require.NoError(t, err)
+		return plan
+	}
+	isSequential := func(p scplan.Plan) (ret bool) {
+		for _, stage := range p.Stages {
+			for _, op := range stage.EdgeOps {
+				if bf, ok := op.(*scop.BackfillIndex); ok {
+					ret = ret || bf.Sequential
+				}
+			}
+		}
+		return ret
+	}
+
+	// Without statistics, there are no estimates.
+	for _, resolver := range []scplan.TableStatsResolver{nil, fakeTableStatsResolver{}} {
+		plan := makePlan(resolver, 1 /* threshold */)
+		_, ok := plan.EstimatedTotal()
+		require.False(t, ok)
+		require.False(t, isSequential(plan))
+	}
+
+	resolver := fakeTableStatsResolver{
+		stats: scplan.TableStats{RowCount: 1000, AvgRowSize: 10},
+		ok:    true,
+	}
+	plan := makePlan(resolver, 0 /* threshold */)
+	var numBackfills, numValidations int
+	for _, stage := range plan.Stages {
+		switch stage.Type() {
+		case scop.BackfillType:
+			for _, op := range stage.EdgeOps {
+				if _, ok := op.(*scop.BackfillIndex); ok {
+					numBackfills++
+				}
+			}
+		case scop.ValidationType:
+			numValidations += len(stage.EdgeOps)
+		default:
+			require.Nil(t, stage.Estimate)
+		}
+	}
+	require.NotZero(t, numBackfills)
+	require.NotZero(t, numValidations)
+	total, ok := plan.EstimatedTotal()
+	require.True(t, ok)
+	require.Equal(t, scplan.Estimate{
+		Rows:        uint64(1000 * (numBackfills + numValidations)),
+		Bytes:       uint64(10000 * (numBackfills + numValidations)),
+		IndexWrites: uint64(1000 * numBackfills),
+	}, total)
+	explain, err := plan.ExplainCompact()
+	require.NoError(t, err)
+	require.Contains(t, explain, "estimated total cost: ")
+	require.NotContains(t, explain, "estimated duration")
+	require.False(t, isSequential(plan))
+
+	// The backfills run sequentially once they are estimated to be larger than
+	// the threshold.
+	require.False(t, isSequential(makePlan(resolver, 1<<30)))
+	require.True(t, isSequential(makePlan(resolver, 10000)))
+
+	// The duration of the stages is estimated from the throughput history.
+	history = fakeThroughputHistory{rowsPerSecond: 100}
+	plan = makePlan(resolver, 0 /* threshold */)
+	total, ok = plan.EstimatedTotal()
+	require.True(t, ok)
+	require.Equal(t, time.Duration(numBackfills+numValidations)*10*time.Second, total.Duration)
+	explain, err = plan.ExplainCompact()
+	require.NoError(t, err)
+	require.Contains(t, explain, "estimated duration")
+}
+
+// TestRollbackPlans checks that the rollback of each post-commit stage is
+// planned along with the plan when requested, and that it matches the plan of
+// the rollback made from scratch.
+func TestRollbackPlans(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	s, sqlDB, _ := serverutils.StartServer(t, base.TestServerArgs{
+		DisableDefaultTestTenant: true,
+	})
+	defer s.Stopper().Stop(ctx)
+	tdb := sqlutils.MakeSQLRunner(sqlDB)
+	tdb.Exec(t, `CREATE TABLE t (i INT PRIMARY KEY, j INT)`)
+
+	var state scpb.CurrentState
+	sctestutils.WithBuilderDependenciesFromTestServer(s, func(deps scbuild.Dependencies) {
+		stmt, err := parser.ParseOne(`ALTER TABLE t DROP COLUMN j`)
+		require.NoError(t, err)
+		state, err = scbuild.Build(ctx, deps, scpb.CurrentState{}, stmt.AST)
+		require.NoError(t, err)
+	})
+	makePlan := func(state scpb.CurrentState, planRollbacks bool) scplan.Plan {
+		plan, err := scplan.MakePlan(state, scplan.Params{
+			ExecutionPhase:             scop.EarliestPhase,
+			SchemaChangerJobIDSupplier: func() jobspb.JobID { return 1 },
+			PlanRollbacks:              planRollbacks,
+		})
+		require.NoError(t, err)
+		return plan
+	}
+
+	// Rollbacks are only planned when requested.
+	require.Empty(t, makePlan(state.DeepCopy(), false /* planRollbacks */).Rollbacks)
+
+	plan := makePlan(state.DeepCopy(), true /* planRollbacks */)
+	var numRevertible, numNonRevertible int
+	for i, stage := range plan.Stages {
+		rp, ok := plan.RollbackPlanForStage(i)
+		if stage.Phase < scop.PostCommitPhase {
+			require.False(t, ok)
+			continue
+		}
+		require.True(t, ok)
+		require.NoError(t, rp.Err)
+		if stage.Phase >= scop.PostCommitNonRevertiblePhase {
+			require.False(t, rp.Revertible)
+			numNonRevertible++
+			continue
+		}
+		require.True(t, rp.Revertible)
+		require.True(t, rp.Plan.InRollback)
+		require.Empty(t, rp.Plan.Rollbacks)
+		numRevertible++
+		// The rollback matches the one planned once the stage has failed.
+		fromScratch, err := scplan.MakePlan(plan.RollbackState(i), scplan.Params{
+			ExecutionPhase:             scop.PostCommitPhase,
+			SchemaChangerJobIDSupplier: func() jobspb.JobID { return 1 },
+		})
+		require.NoError(t, err)
+		expected, err := fromScratch.ExplainCompact()
+		require.NoError(t, err)
+		actual, err := rp.Plan.ExplainCompact()
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	}
+	require.NotZero(t, numRevertible)
+	require.NotZero(t, numNonRevertible)
+
+	explain, err := plan.ExplainRollbacks(false /* verbose */)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(explain, "Rollback plans for ALTER TABLE"), explain)
+	require.Contains(t, explain, "not revertible")
+}
+
+// TestPlanConcurrency checks that the plans do not depend on the number of
+// workers which build their graph.
+func TestPlanConcurrency(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	s, sqlDB, _ := serverutils.StartServer(t, base.TestServerArgs{
+		DisableDefaultTestTenant: true,
+	})
+	defer s.Stopper().Stop(ctx)
+	tdb := sqlutils.MakeSQLRunner(sqlDB)
+	tdb.Exec(t, `CREATE TABLE t (i INT PRIMARY KEY, j INT, k INT, INDEX (j))`)
+
+	var state scpb.CurrentState
+	sctestutils.WithBuilderDependenciesFromTestServer(s, func(deps scbuild.Dependencies) {
+		stmt, err := parser.ParseOne(`ALTER TABLE t DROP COLUMN j, ADD COLUMN l INT DEFAULT 1`)
+		require.NoError(t, err)
+		state, err = scbuild.Build(ctx, deps, scpb.CurrentState{}, stmt.AST)
+		require.NoError(t, err)
+	})
+	explain := func(concurrency int) string {
+		plan, err := scplan.MakePlan(state.DeepCopy(), scplan.Params{
+			ExecutionPhase:             scop.EarliestPhase,
+			SchemaChangerJobIDSupplier: func() jobspb.JobID { return 1 },
+			Concurrency:                concurrency,
+		})
+		require.NoError(t, err)
+		ret, err := plan.ExplainVerbose()
+		require.NoError(t, err)
+		return ret
+	}
+	expected := explain(1 /* concurrency */)
+	for _, concurrency := range []int{0, 2, 16} {
+		for i := 0; i < 5; i++ {
+			require.Equal(t, expected, explain(concurrency), "concurrency %d", concurrency)
+		}
+	}
+}
+
 // validatePlan takes an existing plan and re-plans using the starting state of
 // an arbitrary stage in the existing plan: the results should be the same as in
 // the original plan, minus the stages prior to the selected stage.
@@ -196,6 +693,19 @@ func marshalDeps(t *testing.T, plan *scplan.Plan) string {
 }
 
 // marshalOps marshals operations in scplan.Plan to a string.
+// marshalAnnotations marshals the annotations of the plan, if it has any.
+func marshalAnnotations(plan *scplan.Plan) string {
+	if len(plan.Annotations) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("annotations:\n")
+	for _, a := range plan.Annotations {
+		_, _ = fmt.Fprintf(&sb, "  %s: %s\n", plan.Stages[a.StageIdx], a)
+	}
+	return sb.String()
+}
+
 func marshalOps(t *testing.T, ts scpb.TargetState, stages []scstage.Stage) string {
 	var sb strings.Builder
 	for _, stage := range stages {
@@ -0,0 +1,207 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scplan
+
+import (
+	"sync"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/rel"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scplan/internal/opgen"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scplan/internal/rules"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scplan/internal/scgraph"
+	"github.com/cockroachdb/errors"
+)
+
+// Exported internal types for plugins.
+type (
+	// OpTarget is an exported alias of opgen.PluginTarget.
+	OpTarget = opgen.PluginTarget
+
+	// OpTransition is an exported alias of opgen.PluginTransition.
+	OpTransition = opgen.PluginTransition
+
+	// NodeVars is an exported alias of rules.NodeVars.
+	NodeVars = rules.NodeVars
+
+	// DepEdgeKind is an exported alias of scgraph.DepEdgeKind.
+	DepEdgeKind = scgraph.DepEdgeKind
+)
+
+// The kinds of dependency edges.
+const (
+	Precedence              = scgraph.Precedence
+	SameStagePrecedence     = scgraph.SameStagePrecedence
+	PreviousStagePrecedence = scgraph.PreviousStagePrecedence
+)
+
+// A Plugin contributes op specs and dependency rules to the planner, which
+// allows features living outside of this package, such as CCL or experimental
+// features, to define how their own element types get planned without editing
+// the in-tree op specs and rules.
+type Plugin struct {
+	// Name identifies the plugin.
+	Name string
+
+	// OpSpecs define the op edges of the element types of the plugin. No other
+	// plugin nor the in-tree op specs may define op edges for these types.
+	OpSpecs []OpSpec
+
+	// DepRules define additional dependency edges between the nodes of the
+	// graph.
+	DepRules []DepRule
+
+	// Samples are states exercising the element types and the rules of the
+	// plugin. They get planned when the plugin is verified, which is how
+	// cycles and conflicting dependency edge kinds introduced by the rules of
+	// the plugin are detected.
+	Samples []scpb.CurrentState
+}
+
+// OpSpec defines the op edges of an element type.
+type OpSpec struct {
+	// Element is a value of the element type, typically a nil pointer.
+	Element scpb.Element
+
+	// Targets define the op edges towards each of the target statuses.
+	Targets []OpTarget
+}
+
+// DepRule defines a set of dependency edges, from the nodes of the elements
+// bound to the From variable to the nodes of the elements bound to the To
+// variable.
+type DepRule struct {
+	Name     string
+	Kind     DepEdgeKind
+	From, To string
+	Def      func(from, to NodeVars) rel.Clauses
+}
+
+// plugins contains the registered plugins, which get verified and added to
+// the planner registries when the registries are frozen.
+var plugins struct {
+	registered []Plugin
+	freezeOnce sync.Once
+	frozen     bool
+	// err is the error which prevents any planning from taking place, if the
+	// registries or the plugins failed verification.
+	err error
+}
+
+// RegisterPlugin registers a plugin. The plugin is verified, along with the
+// in-tree op specs and rules, before the first schema change gets planned.
+// If the verification fails, no schema change can be planned.
+//
+// This function is not safe for concurrent use with MakePlan and should only
+// be called from an init function.
+func RegisterPlugin(p Plugin) {
+	if plugins.frozen {
+		panic(errors.AssertionFailedf("registering plugin %s: registries are frozen", p.Name))
+	}
+	if p.Name == "" {
+		panic(errors.AssertionFailedf("plugins must have a name"))
+	}
+	for _, other := range plugins.registered {
+		if other.Name == p.Name {
+			panic(errors.AssertionFailedf("plugin %s is already registered", p.Name))
+		}
+	}
+	plugins.registered = append(plugins.registered, p)
+}
+
+// VerifyRegistries verifies and freezes the planner registries, if that
+// hasn't happened yet, and returns the error which prevents any schema change
+// from being planned, if any. Such an error is the result of a bug in the
+// in-tree op specs and rules, or in a plugin. Rather than preventing the node
+// from starting, it disables the declarative schema changer, and the callers
+// are expected to fall back to the legacy schema changer.
+func VerifyRegistries() error {
+	if err := freezeRegistries(); err != nil {
+		return errors.Wrap(err, "planner registries failed verification")
+	}
+	return nil
+}
+
+// freezeRegistries adds the registered plugins to the planner registries and
+// freezes them, on the first call, after which planning may take place
+// concurrently. It returns an error if the registries or the plugins failed
+// verification.
+func freezeRegistries() error {
+	plugins.freezeOnce.Do(func() {
+		plugins.frozen = true
+		plugins.err = verifyAndFreezeRegistries()
+	})
+	return plugins.err
+}
+
+func verifyAndFreezeRegistries() error {
+	for _, p := range plugins.registered {
+		if err := registerPlugin(p); err != nil {
+			return errors.Wrapf(err, "plugin %s", p.Name)
+		}
+	}
+	if err := opgen.Freeze(); err != nil {
+		return err
+	}
+	if err := rules.Freeze(); err != nil {
+		return err
+	}
+	for _, p := range plugins.registered {
+		for i, cs := range p.Samples {
+			if err := verifySample(cs); err != nil {
+				return errors.Wrapf(err, "plugin %s: planning sample %d", p.Name, i)
+			}
+		}
+	}
+	return nil
+}
+
+func registerPlugin(p Plugin) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			rAsErr, ok := r.(error)
+			if !ok {
+				rAsErr = errors.Errorf("panic during registration: %v", r)
+			}
+			err = rAsErr
+		}
+	}()
+	for _, s := range p.OpSpecs {
+		if err := opgen.RegisterPluginTargets(p.Name, s.Element, s.Targets...); err != nil {
+			return err
+		}
+	}
+	for _, r := range p.DepRules {
+		if err := rules.RegisterPluginDepRule(
+			p.Name, scgraph.RuleName(r.Name), r.Kind, r.From, r.To, r.Def,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifySample plans the schema change for the sample state, from the
+// statement phase all the way to its completion.
+func verifySample(cs scpb.CurrentState) error {
+	p := Plan{
+		CurrentState: cs,
+		Params: Params{
+			ExecutionPhase: scop.EarliestPhase,
+			SchemaChangerJobIDSupplier: func() jobspb.JobID {
+				return jobspb.JobID(1)
+			},
+		},
+	}
+	return makePlan(&p)
+}
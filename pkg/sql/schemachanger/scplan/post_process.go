@@ -0,0 +1,125 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scplan
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+	"github.com/cockroachdb/errors"
+)
+
+// A PostProcessor is a plan post-processing pass. It runs once the plan has
+// been partitioned into stages and may inject additional ops into the stages
+// by appending them to their ExtraOps, for instance to notify some other
+// subsystem that a cache needs to be invalidated. The ops injected into a
+// stage must be of the same type as the stage. Everything else in the plan
+// must be left as is.
+type PostProcessor func(p *Plan) error
+
+type registeredPostProcessor struct {
+	name string
+	fn   PostProcessor
+}
+
+// postProcessors contains the registered post-processors, in the order in
+// which they run.
+var postProcessors []registeredPostProcessor
+
+// RegisterPostProcessor registers a plan post-processing pass, which allows
+// extensions to inject additional ops into the schema change plans without
+// having to define any rules. The post-processors run in registration order
+// and the resulting plan is validated like any other, so a post-processor
+// can't break the dependencies between the targets.
+//
+// This function is not safe for concurrent use with MakePlan and should only
+// be called from an init function.
+func RegisterPostProcessor(name string, fn PostProcessor) {
+	for _, pp := range postProcessors {
+		if pp.name == name {
+			panic(errors.AssertionFailedf("post-processor %s is already registered", name))
+		}
+	}
+	postProcessors = append(postProcessors, registeredPostProcessor{name: name, fn: fn})
+}
+
+// TestingRegisterPostProcessor is like RegisterPostProcessor but also returns
+// a function which unregisters the post-processor.
+func TestingRegisterPostProcessor(name string, fn PostProcessor) (cleanup func()) {
+	prev := postProcessors
+	RegisterPostProcessor(name, fn)
+	return func() { postProcessors = prev }
+}
+
+// runPostProcessors runs all the registered post-processors on the plan and
+// checks that each of them only injected ops into its stages.
+func runPostProcessors(p *Plan) error {
+	for _, pp := range postProcessors {
+		before := append([]Stage(nil), p.Stages...)
+		if err := pp.fn(p); err != nil {
+			return errors.Wrapf(err, "post-processor %s", pp.name)
+		}
+		if err := validatePostProcessedStages(before, p.Stages); err != nil {
+			return errors.Wrapf(err, "post-processor %s", pp.name)
+		}
+	}
+	return nil
+}
+
+func validatePostProcessedStages(before, after []Stage) error {
+	if len(before) != len(after) {
+		return errors.Errorf("number of stages changed from %d to %d", len(before), len(after))
+	}
+	for i := range before {
+		b, a := &before[i], &after[i]
+		if b.Phase != a.Phase || b.Ordinal != a.Ordinal || b.StagesInPhase != a.StagesInPhase {
+			return errors.Errorf("%s: changed into %s", b, a)
+		}
+		if !statusesEqual(b.Before, a.Before) || !statusesEqual(b.After, a.After) {
+			return errors.Errorf("%s: target statuses changed", b)
+		}
+		if !opsHavePrefix(a.EdgeOps, b.EdgeOps) || len(a.EdgeOps) != len(b.EdgeOps) {
+			return errors.Errorf("%s: ops originating from op-edges changed", b)
+		}
+		if !opsHavePrefix(a.ExtraOps, b.ExtraOps) {
+			return errors.Errorf("%s: extra ops were removed or reordered", b)
+		}
+		for _, op := range a.ExtraOps[len(b.ExtraOps):] {
+			if op.Type() != b.Type() {
+				return errors.Errorf("%s: injected %T op of type %s", b, op, op.Type())
+			}
+		}
+	}
+	return nil
+}
+
+func statusesEqual(a, b []scpb.Status) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func opsHavePrefix(ops, prefix []scop.Op) bool {
+	if len(ops) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if ops[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,88 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scplan
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+)
+
+// RollbackPlan is the plan of the rollback of a schema change, were it to fail
+// while executing one of the post-commit stages of its plan.
+type RollbackPlan struct {
+	// StageIdx is the index of the failing stage in the stages of the plan.
+	StageIdx int
+
+	// Revertible is false when the failing stage is non-revertible, in which
+	// case the schema change is not rolled back and Plan is empty.
+	Revertible bool
+
+	// Plan is the plan of the post-commit stages of the rollback.
+	Plan Plan
+
+	// Err is set when the rollback could not be planned.
+	Err error
+}
+
+// RollbackPlanForStage returns the rollback plan computed for the stage at
+// the given index, if any. Rollback plans are only computed for the
+// post-commit stages of a plan made with PlanRollbacks set in its params.
+func (p Plan) RollbackPlanForStage(stageIdx int) (RollbackPlan, bool) {
+	for _, rp := range p.Rollbacks {
+		if rp.StageIdx == stageIdx {
+			return rp, true
+		}
+	}
+	return RollbackPlan{}, false
+}
+
+// RollbackState returns the state from which the schema change is rolled back
+// when the stage at the given index fails: the stage does not take effect,
+// and the targets are reversed.
+func (p Plan) RollbackState(stageIdx int) scpb.CurrentState {
+	s := p.Stages[stageIdx]
+	state := scpb.CurrentState{
+		TargetState: p.TargetState,
+		Current:     s.Before,
+		Revertible:  s.Phase < scop.PostCommitNonRevertiblePhase,
+	}.DeepCopy()
+	state.Rollback()
+	return state
+}
+
+// planRollbacks computes the rollback plan of each post-commit stage of the
+// plan, so that un-revertible stages can be surfaced before the schema change
+// is executed, and so that a failing schema change can be rolled back without
+// planning it again. Rollbacks are not themselves revertible, hence this does
+// nothing for plans which are already in rollback.
+func planRollbacks(p *Plan) {
+	if !p.Params.PlanRollbacks || p.InRollback {
+		return
+	}
+	params := p.Params
+	params.ExecutionPhase = scop.PostCommitPhase
+	params.InRollback = true
+	params.PlanRollbacks = false
+	for i, s := range p.Stages {
+		if s.Phase < scop.PostCommitPhase {
+			continue
+		}
+		rp := RollbackPlan{
+			StageIdx:   i,
+			Revertible: s.Phase < scop.PostCommitNonRevertiblePhase,
+		}
+		if rp.Revertible {
+			rp.Plan = Plan{CurrentState: p.RollbackState(i), Params: params}
+			rp.Err = makePlan(&rp.Plan)
+		}
+		p.Rollbacks = append(p.Rollbacks, rp)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Command scprintplan prints the plan which the declarative schema changer
+// produces for DDL statements, such as EXPLAIN (DDL) would, against the
+// descriptors of an unzipped debug.zip. This allows the schema changes of
+// a cluster to be reproduced offline.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/cockroachdb/cockroach/pkg/cli/exit"
+	"github.com/spf13/cobra"
+)
+
+var (
+	app = cobra.Command{
+		Use:   "scprintplan --debug-zip-dir=<debug_zip_dir> <statements>",
+		Short: "print the declarative schema changer plan of DDL statements",
+		Long: `
+Print the stages and operations planned by the declarative schema changer for
+the given DDL statements, against the descriptors and namespace entries found
+in an unzipped debug.zip, as 'cockroach debug doctor examine zipdir' reads
+them. The statements are planned as in a single transaction, in the current
+database set by --database. The output is that of EXPLAIN (DDL), or of
+EXPLAIN (DDL, VERBOSE) with --verbose.
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return printPlan(context.Background(), os.Stdout, opts, args[0])
+		},
+	}
+	opts = options{database: "defaultdb"}
+)
+
+func init() {
+	f := app.Flags()
+	f.StringVar(&opts.debugZipDir, "debug-zip-dir", opts.debugZipDir,
+		"path of the unzipped 'debug' directory of a debug.zip")
+	f.StringVar(&opts.database, "database", opts.database,
+		"current database in which the statements are planned")
+	f.BoolVar(&opts.safeMode, "safe-mode", opts.safeMode,
+		"plan the schema change as with sql.schema_changer.safe_mode.enabled set")
+	f.BoolVar(&opts.verbose, "verbose", opts.verbose,
+		"print the plan as EXPLAIN (DDL, VERBOSE) does")
+	_ = app.MarkFlagRequired("debug-zip-dir")
+}
+
+func main() {
+	if err := app.Execute(); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		exit.WithCode(exit.UnspecifiedError())
+	}
+}
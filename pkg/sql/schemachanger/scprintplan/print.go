@@ -0,0 +1,123 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/doctor"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scbuild"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scdeps/sctestdeps"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scplan"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
+	"github.com/cockroachdb/errors"
+)
+
+// options configure the printing of a plan.
+type options struct {
+	debugZipDir string
+	database    string
+	safeMode    bool
+	verbose     bool
+}
+
+// printPlan builds the targets of the given statements against the
+// descriptors of the debug zip and prints their plan to out.
+//
+// The catalog is mocked by the schema changer test dependencies, whose
+// output is deterministic: the volatile timestamps of the descriptors are
+// scrubbed and the job ID is always 1. The table statistics are not read
+// from the debug zip, hence the plan doesn't account for them.
+func printPlan(ctx context.Context, out io.Writer, opts options, sql string) error {
+	// To make parsing user functions code happy.
+	_ = builtins.AllBuiltinNames
+
+	descTable, namespaceTable, _, err := doctor.FromZipDir(opts.debugZipDir, false /* verbose */)
+	if err != nil {
+		return err
+	}
+	cat, err := doctor.Catalog(descTable, namespaceTable, os.Stderr)
+	if err != nil {
+		return err
+	}
+	stmts, err := parser.Parse(sql)
+	if err != nil {
+		return err
+	}
+	if len(stmts) == 0 {
+		return errors.New("no statements to plan")
+	}
+	stmtsSQL := make([]string, len(stmts))
+	for i, stmt := range stmts {
+		stmtsSQL[i] = stmt.SQL
+	}
+	deps := sctestdeps.NewTestDependencies(
+		sctestdeps.WithDescriptors(cat),
+		sctestdeps.WithNamespace(cat),
+		sctestdeps.WithCurrentDatabase(opts.database),
+		sctestdeps.WithSessionData(makeSessionData(opts.database)),
+		sctestdeps.WithStatements(stmtsSQL...),
+	)
+	var state scpb.CurrentState
+	for _, stmt := range stmts {
+		state, err = scbuild.Build(ctx, deps, state, stmt.AST)
+		if err != nil {
+			return errors.Wrapf(err, "building %s", stmt.SQL)
+		}
+	}
+
+	// Plan the schema change like EXPLAIN (DDL) does.
+	plan, err := scplan.MakePlan(state, scplan.Params{
+		ExecutionPhase:             scop.StatementPhase,
+		SchemaChangerJobIDSupplier: func() jobspb.JobID { return 1 },
+		ActiveVersion:              deps.ClusterSettings().Version.ActiveVersion(ctx),
+		SafeMode:                   opts.safeMode,
+	})
+	if err != nil {
+		return err
+	}
+	explain := plan.ExplainCompact
+	if opts.verbose {
+		explain = plan.ExplainVerbose
+	}
+	s, err := explain()
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(out, s)
+	return err
+}
+
+// makeSessionData returns the session data of a root session in the given
+// database, in which the declarative schema changer is used for the
+// statements it fully supports, as by default.
+func makeSessionData(database string) sessiondata.SessionData {
+	return sessiondata.SessionData{
+		SessionData: sessiondatapb.SessionData{
+			Database:  database,
+			UserProto: username.RootUserName().EncodeProto(),
+		},
+		LocalOnlySessionData: sessiondatapb.LocalOnlySessionData{
+			NewSchemaChangerMode: sessiondatapb.UseNewSchemaChangerOn,
+		},
+		SearchPath: sessiondata.DefaultSearchPathForUser(username.RootUserName()),
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPrintPlan checks that the plan printed offline from the system tables
+// of a debug zip is that of EXPLAIN (DDL) in the cluster.
+func TestPrintPlan(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, sqlDB, _ := serverutils.StartServer(t, base.TestServerArgs{
+		DisableDefaultTestTenant: true,
+	})
+	defer s.Stopper().Stop(ctx)
+	tdb := sqlutils.MakeSQLRunner(sqlDB)
+	tdb.Exec(t, `CREATE DATABASE db`)
+	tdb.Exec(t, `CREATE TABLE db.public.tbl (i INT PRIMARY KEY)`)
+
+	// Dump the system tables read by the doctor like debug zip does.
+	dir := t.TempDir()
+	dump := func(fileName, header, query string) {
+		lines := []string{header}
+		for _, row := range tdb.QueryStr(t, query) {
+			lines = append(lines, strings.Join(row, "\t"))
+		}
+		require.NoError(t, os.WriteFile(
+			filepath.Join(dir, fileName), []byte(strings.Join(lines, "\n")+"\n"), 0644,
+		))
+	}
+	dump("system.descriptor.txt", "id\thex_descriptor",
+		`SELECT id, to_hex(descriptor) FROM system.descriptor ORDER BY id`)
+	dump("system.namespace.txt", "parentID\tparentSchemaID\tname\tid",
+		`SELECT "parentID", "parentSchemaID", name, id FROM system.namespace`)
+	dump("system.jobs.txt", "id\tstatus\tpayload\tprogress",
+		`SELECT id, status, to_hex(payload), to_hex(progress) FROM system.jobs WHERE false`)
+
+	const stmt = `ALTER TABLE tbl ADD COLUMN j INT NOT NULL DEFAULT 42`
+	tdb.Exec(t, `USE db`)
+	expected := tdb.QueryStr(t, `EXPLAIN (DDL) `+stmt)[0][0]
+
+	var out strings.Builder
+	opts := options{debugZipDir: dir, database: "db"}
+	require.NoError(t, printPlan(ctx, &out, opts, stmt))
+	require.Equal(t, expected+"\n", out.String())
+
+	// The statements which the declarative schema changer doesn't support
+	// can't be planned.
+	err := printPlan(ctx, &out, opts, `ALTER TABLE tbl RENAME TO foo`)
+	require.Error(t, err)
+}
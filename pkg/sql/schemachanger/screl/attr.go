@@ -68,6 +68,8 @@ const (
 	// SourceIndexID is the index ID of the source index for a newly created
 	// index.
 	SourceIndexID
+	// Privileges is the bitfield of privileges granted by the element.
+	Privileges
 
 	// TargetStatus is the target status of an element.
 	TargetStatus
@@ -208,6 +210,12 @@ var elementSchemaOptions = []rel.SchemaOption{
 		rel.EntityAttr(DescID, "TableID"),
 		rel.EntityAttr(ColumnID, "ColumnID"),
 	),
+	rel.EntityMapping(t((*scpb.ColumnPrivileges)(nil)),
+		rel.EntityAttr(DescID, "TableID"),
+		rel.EntityAttr(ColumnID, "ColumnID"),
+		rel.EntityAttr(Name, "UserName"),
+		rel.EntityAttr(Privileges, "Privileges"),
+	),
 	// Index elements.
 	rel.EntityMapping(t((*scpb.IndexName)(nil)),
 		rel.EntityAttr(DescID, "TableID"),
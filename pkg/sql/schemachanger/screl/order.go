@@ -0,0 +1,75 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package screl
+
+import (
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
+)
+
+// CreationDependencies returns the IDs of the descriptors which need to
+// exist before the descriptor owning the element can be created, e.g. the
+// relations and types used by a view query or the sequences used by a column
+// default expression.
+//
+// The following references are excluded:
+//   - the descriptor owning the element,
+//   - its parent database and schema, which are created separately,
+//   - foreign key constraints and sequence ownership, which are added once
+//     all the descriptors exist; this allows for reference cycles.
+func CreationDependencies(e scpb.Element) (ids catalog.DescriptorIDSet) {
+	switch e.(type) {
+	case *scpb.Namespace, *scpb.ObjectParent, *scpb.SchemaParent,
+		*scpb.ForeignKeyConstraint, *scpb.SequenceOwner:
+		return ids
+	}
+	ids = AllDescIDs(e)
+	ids.Remove(GetDescID(e))
+	return ids
+}
+
+// TopologicalOrder orders the descriptor IDs such that each descriptor
+// appears after the descriptors it depends on, as returned by dependsOn.
+// Dependencies on descriptors not in ids are ignored.
+//
+// The order is stable: descriptors are visited by increasing ID and their
+// dependencies in the order returned by dependsOn. Dependency cycles are
+// broken at the first descriptor visited.
+//
+// The ids slice is sorted in place.
+func TopologicalOrder(
+	ids []catid.DescID, dependsOn func(id catid.DescID) []catid.DescID,
+) []catid.DescID {
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	var toVisit, seen catalog.DescriptorIDSet
+	for _, id := range ids {
+		toVisit.Add(id)
+	}
+	ordered := make([]catid.DescID, 0, len(ids))
+	var visit func(id catid.DescID)
+	visit = func(id catid.DescID) {
+		if !toVisit.Contains(id) || seen.Contains(id) {
+			return
+		}
+		seen.Add(id)
+		for _, dep := range dependsOn(id) {
+			visit(dep)
+		}
+		ordered = append(ordered, id)
+	}
+	for _, id := range ids {
+		visit(id)
+	}
+	return ordered
+}
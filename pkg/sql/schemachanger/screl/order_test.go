@@ -0,0 +1,127 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package screl
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreationDependencies(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		input    scpb.Element
+		expected []catid.DescID
+	}{
+		{
+			name: "view",
+			input: &scpb.View{
+				ViewID:          1,
+				UsesTypeIDs:     []catid.DescID{2},
+				UsesRelationIDs: []catid.DescID{3, 4},
+			},
+			expected: []catid.DescID{2, 3, 4},
+		},
+		{
+			name: "default expr",
+			input: &scpb.ColumnDefaultExpression{
+				TableID:  1,
+				ColumnID: 10,
+				Expression: scpb.Expression{
+					Expr:            "foo",
+					UsesTypeIDs:     []catid.DescID{2, 3},
+					UsesSequenceIDs: []catid.DescID{4, 5},
+				},
+			},
+			expected: []catid.DescID{2, 3, 4, 5},
+		},
+		{
+			name: "foreign key",
+			input: &scpb.ForeignKeyConstraint{
+				TableID:           1,
+				ReferencedTableID: 2,
+			},
+		},
+		{
+			name: "sequence owner",
+			input: &scpb.SequenceOwner{
+				SequenceID: 1,
+				TableID:    2,
+			},
+		},
+		{
+			name: "parent",
+			input: &scpb.ObjectParent{
+				ObjectID:       1,
+				ParentSchemaID: 2,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.ElementsMatch(t, tc.expected, CreationDependencies(tc.input).Ordered())
+		})
+	}
+}
+
+func TestTopologicalOrder(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		dependsOn map[catid.DescID][]catid.DescID
+		expected  []catid.DescID
+	}{
+		{
+			name: "basic test",
+			dependsOn: map[catid.DescID][]catid.DescID{
+				1: {1, 2},
+				2: {},
+				3: nil,
+			},
+			expected: []catid.DescID{2, 1, 3},
+		},
+		{
+			name: "depends on references non-existent IDs",
+			dependsOn: map[catid.DescID][]catid.DescID{
+				1: {1, 2},
+				2: {},
+				3: nil,
+				4: {5, 6},
+				6: {2, 3},
+			},
+			expected: []catid.DescID{2, 1, 3, 6, 4},
+		},
+		{
+			name: "handles cycles",
+			dependsOn: map[catid.DescID][]catid.DescID{
+				1: {2},
+				2: {3},
+				3: {4},
+				4: {5},
+				5: {6},
+				6: {1},
+			},
+			expected: []catid.DescID{6, 5, 4, 3, 2, 1},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var ids []catid.DescID
+			for id := range tc.dependsOn {
+				ids = append(ids, id)
+			}
+			actual := TopologicalOrder(ids, func(id catid.DescID) []catid.DescID {
+				return tc.dependsOn[id]
+			})
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
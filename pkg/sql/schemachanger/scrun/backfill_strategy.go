@@ -0,0 +1,33 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scrun
+
+import "github.com/cockroachdb/cockroach/pkg/settings"
+
+// sequentialBackfillThreshold is the estimated size of the index backfills of
+// a stage above which they run one after the other. The estimate is derived
+// from table statistics, so the backfills of tables without statistics
+// always run concurrently.
+var sequentialBackfillThreshold = settings.RegisterByteSizeSetting(
+	settings.TenantWritable,
+	"sql.schema_changer.sequential_backfill_threshold",
+	"the estimated size, as derived from table statistics, above which the index "+
+		"backfills of a declarative schema change stage run one after the other "+
+		"rather than concurrently; 0 disables",
+	16<<30, /* 16 GiB */
+	settings.NonNegativeInt,
+)
+
+// SequentialBackfillThreshold returns the estimated size of the index
+// backfills of a stage above which they run one after the other.
+func SequentialBackfillThreshold(sv *settings.Values) int64 {
+	return sequentialBackfillThreshold.Get(sv)
+}
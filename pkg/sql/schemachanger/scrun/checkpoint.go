@@ -0,0 +1,84 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scrun
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/errors"
+)
+
+// EncodeCheckpoint returns the encoded checkpoint of the given state, once
+// the given number of post-commit stages have been completed.
+func EncodeCheckpoint(s scpb.CurrentState, completedStages int64) ([]byte, error) {
+	c := scpb.MakeCheckpoint(s, completedStages)
+	return protoutil.Marshal(&c)
+}
+
+// DecodeCheckpoint decodes a checkpoint written by EncodeCheckpoint. It
+// returns false if the job has not been checkpointed yet.
+func DecodeCheckpoint(buf []byte) (_ scpb.Checkpoint, ok bool, _ error) {
+	if len(buf) == 0 {
+		return scpb.Checkpoint{}, false, nil
+	}
+	var c scpb.Checkpoint
+	if err := protoutil.Unmarshal(buf, &c); err != nil {
+		return scpb.Checkpoint{}, false, errors.Wrap(err, "failed to decode checkpoint")
+	}
+	return c, true, nil
+}
+
+// CompletedStages returns the number of post-commit stages completed by the
+// schema change job with the given progress.
+func CompletedStages(p *jobspb.NewSchemaChangeProgress) (int64, error) {
+	c, ok, err := DecodeCheckpoint(p.Checkpoint)
+	if err != nil || !ok {
+		return 0, err
+	}
+	return c.CompletedStages, nil
+}
+
+// SetPauseAtStage requests that the schema change job with the given progress
+// pauses before executing the post-commit stage with the given number,
+// starting at 1. The stage must not have been executed yet.
+func SetPauseAtStage(p *jobspb.NewSchemaChangeProgress, stage int64) error {
+	if stage < 1 {
+		return errors.Newf("invalid stage %d: stages are numbered starting at 1", stage)
+	}
+	completed, err := CompletedStages(p)
+	if err != nil {
+		return err
+	}
+	if stage <= completed {
+		return errors.Newf(
+			"cannot pause at stage %d: %d stages have already been completed", stage, completed,
+		)
+	}
+	p.PauseAtStage = stage
+	return nil
+}
+
+// loadCheckpoint returns the state stored in the checkpoint of the job along
+// with the number of post-commit stages completed so far. It returns false if
+// there is no checkpoint which this binary can resume from, in which case the
+// state must be derived from the descriptors.
+func loadCheckpoint(buf []byte) (scpb.CurrentState, int64, bool, error) {
+	c, ok, err := DecodeCheckpoint(buf)
+	if err != nil || !ok {
+		return scpb.CurrentState{}, 0, false, err
+	}
+	state, err := c.CurrentState()
+	if err != nil {
+		return scpb.CurrentState{}, 0, false, err
+	}
+	return state, c.CompletedStages, true, nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scrun
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scfmt"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckpoint(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	// No checkpoint.
+	_, _, ok, err := loadCheckpoint(nil)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	state := scpb.CurrentState{
+		TargetState: scpb.TargetState{
+			Targets: []scpb.Target{
+				scpb.MakeTarget(scpb.ToPublic, &scpb.Namespace{DescriptorID: 104, Name: "t"}, nil),
+			},
+			Authorization: scpb.Authorization{UserName: "root"},
+		},
+		Current:    []scpb.Status{scpb.Status_ABSENT},
+		Revertible: true,
+	}
+	buf, err := EncodeCheckpoint(state, 3)
+	require.NoError(t, err)
+	loaded, completed, ok, err := loadCheckpoint(buf)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, int64(3), completed)
+	require.Equal(t, state.Current, loaded.Current)
+	diff, err := scfmt.Diff(state, loaded)
+	require.NoError(t, err)
+	require.Empty(t, diff)
+	require.Equal(t, state.Authorization, loaded.Authorization)
+	require.True(t, loaded.Revertible)
+	require.False(t, loaded.InRollback)
+
+	// Checkpoints written in another format are not resumed from.
+	c := scpb.MakeCheckpoint(state, 3)
+	c.Version = scpb.CheckpointVersion + 1
+	_, err = c.CurrentState()
+	require.Error(t, err)
+	c.Version = scpb.CheckpointVersion
+	c.Current = nil
+	_, err = c.CurrentState()
+	require.Error(t, err)
+	_, _, ok, err = loadCheckpoint([]byte("garbage"))
+	require.Error(t, err)
+	require.False(t, ok)
+
+	// Only the stages which haven't been executed yet can be paused at.
+	p := jobspb.NewSchemaChangeProgress{Checkpoint: buf}
+	require.Error(t, SetPauseAtStage(&p, 0))
+	require.Error(t, SetPauseAtStage(&p, 3))
+	require.Zero(t, p.PauseAtStage)
+	require.NoError(t, SetPauseAtStage(&p, 4))
+	require.Equal(t, int64(4), p.PauseAtStage)
+}
@@ -13,7 +13,9 @@ package scrun
 import (
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scexec"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scplan"
 )
 
 // JobTxnFunc is used to run a transactional stage of a schema change on
@@ -27,4 +29,41 @@ type JobRunDependencies interface {
 	// the execution of the callback. After committing the transaction, the job
 	// registry should be notified to adopt jobs.
 	WithTxnInJob(ctx context.Context, fn JobTxnFunc) error
+
+	// RecordStageError persists the error which occurred while executing a
+	// stage in the progress of the job, for introspection. The history of
+	// stage errors should be maintained using AppendStageError.
+	RecordStageError(ctx context.Context, stageErr jobspb.SchemaChangeStageError, err error) error
+
+	// UpdateStageProgress applies fn to the progress of the job and persists
+	// it, along with the fraction completed derived from the progress of the
+	// stages. The progress of the stages should be reset using
+	// ResetStageProgress once the schema change is planned.
+	UpdateStageProgress(ctx context.Context, fn func(p *jobspb.NewSchemaChangeProgress)) error
+
+	// Checkpoint returns the encoded checkpoint of the schema change found in
+	// the progress of the job when its execution started, if any. Checkpoints
+	// are written after each post-commit stage using
+	// scexec.TransactionalJobRegistry.CheckpointSchemaChangeJob.
+	Checkpoint() []byte
+
+	// TableStatsResolver returns the provider of the table statistics from
+	// which the cost of the backfill and validation stages is estimated, or
+	// nil if there is none.
+	TableStatsResolver() scplan.TableStatsResolver
+
+	// ThroughputHistory returns the store of the throughput of the backfill
+	// and validation stages of past schema changes, from which the duration of
+	// the stages is estimated, or nil if there is none.
+	ThroughputHistory() ThroughputHistory
+}
+
+// ThroughputHistory is the store of the throughput of the backfill and
+// validation stages of past schema changes. The throughput of each stage is
+// recorded using RecordThroughput once it completes.
+type ThroughputHistory interface {
+	scplan.ThroughputHistory
+
+	// RecordThroughput persists the throughput of a completed stage.
+	RecordThroughput(ctx context.Context, r ThroughputRecord) error
 }
@@ -0,0 +1,86 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scrun
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scplan"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/screl"
+)
+
+// elementStatusesEnabled controls whether the element status transitions of
+// declarative schema change jobs are recorded in the
+// system.schema_change_element_statuses table. Each element targeted by a
+// job has a row holding its latest status transition, which is deleted once
+// the job is done, so that external tools may follow the progress of schema
+// changes with a changefeed on that table.
+var elementStatusesEnabled = settings.RegisterBoolSetting(
+	settings.TenantWritable,
+	"sql.schema_changer.element_statuses.enabled",
+	"if set, the latest status transition of each element targeted by an "+
+		"in-flight declarative schema change job is recorded in the "+
+		"system.schema_change_element_statuses table",
+	false,
+)
+
+func recordElementStatuses(ctx context.Context, st *cluster.Settings) bool {
+	return elementStatusesEnabled.Get(&st.SV) &&
+		st.Version.IsActive(ctx, clusterversion.SystemSchemaChangeElementStatusesTable)
+}
+
+// makeElementStatusesOp returns the op recording the element status
+// transitions of the stage with the given index in the plan. In the last stage
+// of the plan, the op deletes the records pertaining to the job instead.
+func makeElementStatusesOp(
+	p scplan.Plan, stageIdx int, stage scplan.Stage,
+) *scop.UpdateSchemaChangeElementStatuses {
+	op := &scop.UpdateSchemaChangeElementStatuses{
+		JobID:   p.JobID,
+		JobDone: stage.Phase >= scop.PostCommitPhase && stageIdx == len(p.Stages)-1,
+	}
+	if op.JobDone {
+		return op
+	}
+	for i, t := range p.TargetState.Targets {
+		if before, after := stage.Before[i], stage.After[i]; before != after {
+			op.Transitions = append(op.Transitions, scop.ElementStatusTransition{
+				ElementID: i,
+				DescID:    screl.GetDescID(t.Element()),
+				Element:   screl.ElementString(t.Element()),
+				From:      before,
+				To:        after,
+			})
+		}
+	}
+	return op
+}
+
+// withElementStatusesOp adds the op recording the element status transitions
+// of the given stage to the ops to execute for it, or to the ops to execute
+// after them if the stage's ops are not mutation ops.
+func withElementStatusesOp(
+	p scplan.Plan, stageIdx int, stage scplan.Stage, ops, after []scop.Op,
+) ([]scop.Op, []scop.Op) {
+	if p.JobID == jobspb.InvalidJobID || stage.Phase < scop.PreCommitPhase {
+		return ops, after
+	}
+	op := makeElementStatusesOp(p, stageIdx, stage)
+	if stage.Type() == scop.MutationType {
+		return append(ops, op), after
+	}
+	return ops, append(after, op)
+}
@@ -0,0 +1,74 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scrun
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scplan"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithElementStatusesOp(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	var p scplan.Plan
+	p.Targets = []scpb.Target{
+		scpb.MakeTarget(scpb.ToPublic, &scpb.Column{TableID: 104, ColumnID: 2}, nil),
+		scpb.MakeTarget(scpb.ToPublic, &scpb.ColumnName{TableID: 104, ColumnID: 2, Name: "j"}, nil),
+	}
+	mutation := scplan.Stage{
+		Before:        []scpb.Status{scpb.Status_DELETE_ONLY, scpb.Status_PUBLIC},
+		After:         []scpb.Status{scpb.Status_WRITE_ONLY, scpb.Status_PUBLIC},
+		EdgeOps:       []scop.Op{&scop.MakeAddedColumnDeleteAndWriteOnly{TableID: 104, ColumnID: 2}},
+		Phase:         scop.PostCommitPhase,
+		Ordinal:       1,
+		StagesInPhase: 2,
+	}
+	backfill := mutation
+	backfill.EdgeOps = []scop.Op{&scop.BackfillIndex{TableID: 104, SourceIndexID: 1, IndexID: 2}}
+	backfill.Ordinal = 2
+	p.Stages = []scplan.Stage{mutation, backfill}
+
+	// Nothing is recorded for schema changes without a job.
+	ops, after := withElementStatusesOp(p, 0, mutation, mutation.Ops(), nil)
+	require.Equal(t, mutation.Ops(), ops)
+	require.Empty(t, after)
+
+	// The transitions are recorded along with the ops of mutation stages.
+	p.JobID = jobspb.JobID(42)
+	ops, after = withElementStatusesOp(p, 0, mutation, mutation.Ops(), nil)
+	require.Empty(t, after)
+	require.Len(t, ops, 2)
+	op := ops[1].(*scop.UpdateSchemaChangeElementStatuses)
+	require.Equal(t, jobspb.JobID(42), op.JobID)
+	require.False(t, op.JobDone)
+	require.Equal(t, []scop.ElementStatusTransition{{
+		ElementID: 0,
+		DescID:    104,
+		Element:   "Column:{DescID: 104, ColumnID: 2}",
+		From:      scpb.Status_DELETE_ONLY,
+		To:        scpb.Status_WRITE_ONLY,
+	}}, op.Transitions)
+
+	// They follow the ops of the other stages, and the records are deleted in
+	// the last stage of the job.
+	ops, after = withElementStatusesOp(p, 1, backfill, backfill.Ops(), nil)
+	require.Equal(t, backfill.Ops(), ops)
+	require.Len(t, after, 1)
+	op = after[0].(*scop.UpdateSchemaChangeElementStatuses)
+	require.True(t, op.JobDone)
+	require.Empty(t, op.Transitions)
+}
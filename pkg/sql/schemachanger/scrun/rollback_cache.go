@@ -0,0 +1,95 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scrun
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scplan"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// maxCachedRollbackPlans bounds the number of rollback plans cached by the
+// node. The plan of a job which is reverted on another node is never taken
+// out of the cache, hence the bound.
+const maxCachedRollbackPlans = 64
+
+// rollbackPlanCache holds the rollback plans of the schema change jobs whose
+// execution failed on this node, as computed when the jobs were planned, so
+// that they need not be planned again when the jobs are reverted.
+type rollbackPlanCache struct {
+	mu    syncutil.Mutex
+	plans map[jobspb.JobID]scplan.Plan
+}
+
+var rollbackPlans = rollbackPlanCache{}
+
+// put caches the rollback plan of the given job.
+func (c *rollbackPlanCache) put(jobID jobspb.JobID, p scplan.Plan) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.plans == nil {
+		c.plans = make(map[jobspb.JobID]scplan.Plan)
+	}
+	if _, found := c.plans[jobID]; !found && len(c.plans) >= maxCachedRollbackPlans {
+		for id := range c.plans {
+			delete(c.plans, id)
+			break
+		}
+	}
+	c.plans[jobID] = p
+}
+
+// take removes the rollback plan of the given job from the cache, and returns
+// it if it was planned from the given state.
+func (c *rollbackPlanCache) take(
+	jobID jobspb.JobID, state scpb.CurrentState,
+) (scplan.Plan, bool) {
+	c.mu.Lock()
+	p, found := c.plans[jobID]
+	delete(c.plans, jobID)
+	c.mu.Unlock()
+	if !found || !sameState(p.CurrentState, state) {
+		return scplan.Plan{}, false
+	}
+	return p, true
+}
+
+// cacheRollbackPlan caches the rollback plan of the stage at the given index
+// of the plan of the job, which failed with the given error, if any.
+func cacheRollbackPlan(
+	ctx context.Context, jobID jobspb.JobID, sc scplan.Plan, stageIdx int, err error,
+) {
+	// The job is not reverted when it is paused or canceled.
+	if ctx.Err() != nil || jobs.IsPauseSelfError(err) {
+		return
+	}
+	if rp, ok := sc.RollbackPlanForStage(stageIdx); ok && rp.Revertible && rp.Err == nil {
+		rollbackPlans.put(jobID, rp.Plan)
+	}
+}
+
+// sameState returns whether both states have the same targets, in the same
+// statuses.
+func sameState(a, b scpb.CurrentState) bool {
+	if a.InRollback != b.InRollback || len(a.Current) != len(b.Current) {
+		return false
+	}
+	for i := range a.Current {
+		if a.Current[i] != b.Current[i] {
+			return false
+		}
+	}
+	return a.TargetState.Equal(&b.TargetState)
+}
@@ -0,0 +1,60 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scrun
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scplan"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRollbackPlanCache(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	state := scpb.CurrentState{
+		TargetState: scpb.TargetState{
+			Targets: []scpb.Target{
+				scpb.MakeTarget(scpb.ToAbsent, &scpb.Namespace{DescriptorID: 104, Name: "t"}, nil),
+			},
+			Authorization: scpb.Authorization{UserName: "root"},
+		},
+		Current:    []scpb.Status{scpb.Status_PUBLIC},
+		InRollback: true,
+	}
+	var c rollbackPlanCache
+	c.put(1, scplan.Plan{CurrentState: state, JobID: 1})
+
+	// The plan is only returned for the job and state it was planned for, and
+	// is taken out of the cache either way.
+	_, ok := c.take(2, state)
+	require.False(t, ok)
+	other := state.DeepCopy()
+	other.Current[0] = scpb.Status_ABSENT
+	_, ok = c.take(1, other)
+	require.False(t, ok)
+	_, ok = c.take(1, state)
+	require.False(t, ok)
+
+	c.put(1, scplan.Plan{CurrentState: state, JobID: 1})
+	p, ok := c.take(1, state.DeepCopy())
+	require.True(t, ok)
+	require.Equal(t, jobspb.JobID(1), p.JobID)
+
+	// The size of the cache is bounded.
+	for i := 0; i < 2*maxCachedRollbackPlans; i++ {
+		c.put(jobspb.JobID(i), scplan.Plan{CurrentState: state})
+	}
+	require.Len(t, c.plans, maxCachedRollbackPlans)
+}
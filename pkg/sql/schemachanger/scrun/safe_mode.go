@@ -0,0 +1,87 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scrun
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scexec"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scplan"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/screl"
+	"github.com/cockroachdb/errors"
+)
+
+// safeModeEnabled controls whether new declarative schema changes are planned
+// in safe mode. The choice is recorded in the schema change job, which keeps
+// planning its remaining stages in the same mode regardless of later changes
+// to the setting.
+var safeModeEnabled = settings.RegisterBoolSetting(
+	settings.TenantWritable,
+	"sql.schema_changer.safe_mode.enabled",
+	"if set, declarative schema changes are executed in more, smaller stages, "+
+		"each of which is validated against the descriptors, at the expense of "+
+		"a longer overall duration",
+	false,
+)
+
+// SafeModeEnabled returns whether new declarative schema changes are planned
+// in safe mode.
+func SafeModeEnabled(sv *settings.Values) bool {
+	return safeModeEnabled.Get(sv)
+}
+
+// validateStageState checks that the schema changer state stored in the
+// descriptors which still take part in the schema change after the given
+// stage agrees with the statuses expected by the plan.
+func validateStageState(
+	ctx context.Context, cat scexec.Catalog, jobID jobspb.JobID, stage scplan.Stage,
+) error {
+	var ids []descpb.ID
+	for _, op := range stage.ExtraOps {
+		if op, ok := op.(*scop.SetJobStateOnDescriptor); ok {
+			ids = append(ids, op.DescriptorID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	descs, err := cat.MustReadImmutableDescriptors(ctx, ids...)
+	if err != nil {
+		return err
+	}
+	for _, desc := range descs {
+		ds := desc.GetDeclarativeSchemaChangerState()
+		if ds == nil || ds.JobID != jobID {
+			return errors.AssertionFailedf(
+				"descriptor %d has no state for schema change job %d", desc.GetID(), jobID,
+			)
+		}
+		for i, rank := range ds.TargetRanks {
+			if int(rank) >= len(stage.After) {
+				return errors.AssertionFailedf(
+					"descriptor %d has target rank %d out of %d targets",
+					desc.GetID(), rank, len(stage.After),
+				)
+			}
+			if actual, expected := ds.CurrentStatuses[i], stage.After[rank]; actual != expected {
+				return errors.AssertionFailedf(
+					"descriptor %d has status %s instead of %s for %s after %s",
+					desc.GetID(), actual, expected, screl.ElementString(ds.Targets[i].Element()), stage,
+				)
+			}
+		}
+	}
+	return nil
+}
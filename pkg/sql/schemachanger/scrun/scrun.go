@@ -26,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scplan"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 )
@@ -68,6 +69,8 @@ func runTransactionPhase(
 	sc, err := scplan.MakePlan(state, scplan.Params{
 		ExecutionPhase:             phase,
 		SchemaChangerJobIDSupplier: deps.TransactionalJobRegistry().SchemaChangerJobID,
+		ActiveVersion:              deps.ClusterSettings().Version.ActiveVersion(ctx),
+		SafeMode:                   safeModeEnabled.Get(&deps.ClusterSettings().SV),
 	})
 	if err != nil {
 		return scpb.CurrentState{}, jobspb.InvalidJobID, err
@@ -87,7 +90,8 @@ func runTransactionPhase(
 }
 
 // RunSchemaChangesInJob contains the business logic for the Resume method of a
-// declarative schema change job, with the dependencies abstracted away.
+// declarative schema change job, with the dependencies abstracted away. The
+// remaining stages are planned in safe mode if the job was created in it.
 func RunSchemaChangesInJob(
 	ctx context.Context,
 	knobs *scexec.TestingKnobs,
@@ -96,52 +100,173 @@ func RunSchemaChangesInJob(
 	jobID jobspb.JobID,
 	descriptorIDs []descpb.ID,
 	rollback bool,
+	safeMode bool,
 ) error {
-	state, err := makeState(ctx, jobID, descriptorIDs, rollback, func(
-		ctx context.Context, f catalogFunc,
-	) error {
-		return deps.WithTxnInJob(ctx, func(
-			ctx context.Context, txnDeps scexec.Dependencies,
+	// Resume from the checkpoint of the last completed stage, if any, rather
+	// than re-deriving the state from the descriptors.
+	state, completedStages, ok, err := loadCheckpoint(deps.Checkpoint())
+	if err != nil {
+		log.Warningf(ctx, "ignoring the checkpoint of job %d: %v", jobID, err)
+	}
+	if ok {
+		err = reconcileRollback(&state, rollback)
+	} else {
+		state, err = makeState(ctx, jobID, descriptorIDs, rollback, func(
+			ctx context.Context, f catalogFunc,
 		) error {
-			return f(ctx, txnDeps.Catalog())
+			return deps.WithTxnInJob(ctx, func(
+				ctx context.Context, txnDeps scexec.Dependencies,
+			) error {
+				return f(ctx, txnDeps.Catalog())
+			})
 		})
-	})
+	}
 	if err != nil {
 		if knobs != nil && knobs.OnPostCommitPlanError != nil {
 			return knobs.OnPostCommitPlanError(nil, err)
 		}
 		return errors.Wrapf(err, "failed to construct state for job %d", jobID)
 	}
-	sc, err := scplan.MakePlan(state, scplan.Params{
-		ExecutionPhase:             scop.PostCommitPhase,
-		SchemaChangerJobIDSupplier: func() jobspb.JobID { return jobID },
-	})
+	// Reuse the rollback plan computed when the failed stage was planned, if
+	// this node executed it.
+	sc, ok := rollbackPlans.take(jobID, state)
+	if !ok {
+		sc, err = scplan.MakePlan(state, scplan.Params{
+			ExecutionPhase:              scop.PostCommitPhase,
+			SchemaChangerJobIDSupplier:  func() jobspb.JobID { return jobID },
+			ActiveVersion:               settings.Version.ActiveVersion(ctx),
+			SafeMode:                    safeMode,
+			TableStatsResolver:          deps.TableStatsResolver(),
+			ThroughputHistory:           deps.ThroughputHistory(),
+			SequentialBackfillThreshold: SequentialBackfillThreshold(&settings.SV),
+			PlanRollbacks:               !state.InRollback,
+		})
+	}
 	if err != nil {
 		if knobs != nil && knobs.OnPostCommitPlanError != nil {
 			return knobs.OnPostCommitPlanError(&state, err)
 		}
 		return err
 	}
+	total, _ := sc.EstimatedTotal()
+	log.StructuredEvent(ctx, &eventpb.SchemaChangePlanned{
+		CommonSchemaChangeJobEventDetails: makeCommonSchemaChangeJobEventDetails(jobID, state),
+		DescriptorIDs:                     descIDsAsUint32(descriptorIDs),
+		NumStages:                         uint32(len(sc.Stages)),
+		SafeMode:                          safeMode,
+		EstimatedDuration:                 total.Duration.Nanoseconds(),
+	})
+	updateStageProgress(ctx, deps, func(p *jobspb.NewSchemaChangeProgress) {
+		p.Stages = ResetStageProgress(p.Stages, sc)
+	})
 
 	for i := range sc.Stages {
 		// Execute each stage in its own transaction.
+		start := timeutil.Now()
+		stageNum := completedStages + int64(i) + 1
+		var pause bool
+		updateStageProgress(ctx, deps, func(p *jobspb.NewSchemaChangeProgress) {
+			pause = p.PauseAtStage != 0 && stageNum >= p.PauseAtStage
+			if pause {
+				p.PauseAtStage = 0
+				return
+			}
+			if sp := findPendingStage(p, sc.Stages[i], sc.InRollback); sp != nil {
+				sp.StartedMicros = timeutil.ToUnixMicros(start)
+			}
+		})
+		if pause {
+			return jobs.MarkPauseRequestError(errors.Newf(
+				"paused before stage %d as requested", stageNum,
+			))
+		}
+		if err := logStageBegin(ctx, settings, deps, sc, sc.Stages[i]); err != nil {
+			return errors.Wrapf(err, "error logging events for %s", sc.Stages[i])
+		}
 		if err := deps.WithTxnInJob(ctx, func(ctx context.Context, td scexec.Dependencies) error {
 			if err := td.TransactionalJobRegistry().CheckPausepoint(
 				pausepointName(state, i),
 			); err != nil {
 				return err
 			}
-			return executeStage(ctx, knobs, td, sc, i, sc.Stages[i])
+			if err := executeStage(ctx, knobs, td, sc, i, sc.Stages[i]); err != nil {
+				return err
+			}
+			if safeMode {
+				if err := validateStageState(ctx, td.Catalog(), jobID, sc.Stages[i]); err != nil {
+					return err
+				}
+			}
+			// Checkpoint the state in the same transaction as the stage so that
+			// the job resumes at the next stage if it is paused or restarted.
+			buf, err := EncodeCheckpoint(stateAfterStage(state, sc, i), stageNum)
+			if err != nil {
+				return err
+			}
+			return td.TransactionalJobRegistry().CheckpointSchemaChangeJob(ctx, jobID, buf)
 		}); err != nil {
+			recordStageError(ctx, deps, jobID, state, sc.Stages[i], err)
+			cacheRollbackPlan(ctx, jobID, sc, i, err)
 			if knobs != nil && knobs.OnPostCommitError != nil {
 				return knobs.OnPostCommitError(sc, i, err)
 			}
 			return err
 		}
+		duration := timeutil.Since(start)
+		recordThroughput(ctx, deps, jobID, stageNum, sc.Stages[i], duration)
+		updateStageProgress(ctx, deps, func(p *jobspb.NewSchemaChangeProgress) {
+			if sp := findPendingStage(p, sc.Stages[i], sc.InRollback); sp != nil {
+				sp.CompletedMicros = timeutil.ToUnixMicros(timeutil.Now())
+				sp.FractionCompleted = 1
+			}
+		})
+		log.StructuredEvent(ctx, &eventpb.SchemaChangeStageCompleted{
+			CommonSchemaChangeJobEventDetails: makeCommonSchemaChangeJobEventDetails(jobID, state),
+			Phase:                             sc.Stages[i].Phase.String(),
+			StageOrdinal:                      uint32(sc.Stages[i].Ordinal),
+			StagesInPhase:                     uint32(sc.Stages[i].StagesInPhase),
+			NumOps:                            uint32(len(sc.Stages[i].Ops())),
+			Duration:                          duration.Nanoseconds(),
+		})
+	}
+	if state.InRollback {
+		log.StructuredEvent(ctx, &eventpb.SchemaChangeReverted{
+			CommonSchemaChangeJobEventDetails: makeCommonSchemaChangeJobEventDetails(jobID, state),
+		})
 	}
 	return nil
 }
 
+// stateAfterStage returns the state of the schema change once the stage at
+// the given index of the plan has been executed.
+func stateAfterStage(state scpb.CurrentState, sc scplan.Plan, stageIdx int) scpb.CurrentState {
+	next := stageIdx + 1
+	return scpb.CurrentState{
+		TargetState: state.TargetState,
+		Current:     sc.Stages[stageIdx].After,
+		InRollback:  sc.InRollback,
+		Revertible: next < len(sc.Stages) &&
+			sc.Stages[next].Phase < scop.PostCommitNonRevertiblePhase,
+	}
+}
+
+func makeCommonSchemaChangeJobEventDetails(
+	jobID jobspb.JobID, state scpb.CurrentState,
+) eventpb.CommonSchemaChangeJobEventDetails {
+	return eventpb.CommonSchemaChangeJobEventDetails{
+		JobID:      int64(jobID),
+		InRollback: state.InRollback,
+	}
+}
+
+func descIDsAsUint32(ids []descpb.ID) []uint32 {
+	ret := make([]uint32, len(ids))
+	for i, id := range ids {
+		ret[i] = uint32(id)
+	}
+	return ret
+}
+
 // pausepointName construct a name for the job execution phase pausepoint.
 func pausepointName(state scpb.CurrentState, i int) string {
 	return fmt.Sprintf(
@@ -172,7 +297,14 @@ func executeStage(
 				stage, p.InRollback, timeutil.Since(start), err)
 		}
 	}()
-	if err := scexec.ExecuteStage(ctx, deps, stage.Ops()); err != nil {
+	ops, eventOps := stage.Ops(), []scop.Op(nil)
+	if stage.Phase >= scop.PostCommitPhase && stageEventsEnabled.Get(&deps.ClusterSettings().SV) {
+		ops, eventOps = withStageEventOps(p, stage)
+	}
+	if recordElementStatuses(ctx, deps.ClusterSettings()) {
+		ops, eventOps = withElementStatusesOp(p, stageIdx, stage, ops, eventOps)
+	}
+	if err := scexec.ExecuteStage(ctx, deps, ops); err != nil {
 		// Don't go through the effort to wrap the error if it's a retry or it's a
 		// cancelation.
 		if !errors.HasType(err, (*roachpb.TransactionRetryWithProtoRefreshError)(nil)) &&
@@ -187,6 +319,11 @@ func executeStage(
 		}
 		return errors.Wrapf(err, "error executing %s", stage)
 	}
+	if len(eventOps) > 0 {
+		if err := scexec.ExecuteStage(ctx, deps, eventOps); err != nil {
+			return errors.Wrapf(err, "error logging events for %s", stage)
+		}
+	}
 	if knobs != nil && knobs.AfterStage != nil {
 		if err := knobs.AfterStage(p, stageIdx); err != nil {
 			return err
@@ -275,15 +412,24 @@ func makeState(
 	if err != nil {
 		return scpb.CurrentState{}, err
 	}
+	if err := reconcileRollback(&state, rollback); err != nil {
+		return scpb.CurrentState{}, err
+	}
+	return state, nil
+}
+
+// reconcileRollback reconciles the state of the schema change with whether
+// the job is reverting.
+func reconcileRollback(state *scpb.CurrentState, rollback bool) error {
 	if !rollback && state.InRollback {
 		// If we do not mark the error as permanent, but we've configured the job to
 		// be non-cancelable, we'll never make it to the reverting state.
-		return scpb.CurrentState{}, jobs.MarkAsPermanentJobError(errors.Errorf(
+		return jobs.MarkAsPermanentJobError(errors.Errorf(
 			"job in running state but schema change in rollback, " +
 				"returning an error to restart in the reverting state"))
 	}
 	if rollback && !state.InRollback {
 		state.Rollback()
 	}
-	return state, nil
+	return nil
 }
@@ -0,0 +1,95 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scrun
+
+import (
+	"context"
+	"encoding/binary"
+	"hash/fnv"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scplan"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// AppendStageError appends stageErr to the history of stage errors of a
+// schema change job and returns the updated history, keeping at most
+// maxEntries entries.
+//
+// The Attempt of stageErr is set based on the most recent error of the
+// same stage which is still in the history, if any.
+func AppendStageError(
+	history []jobspb.SchemaChangeStageError, stageErr jobspb.SchemaChangeStageError, maxEntries int,
+) []jobspb.SchemaChangeStageError {
+	stageErr.Attempt = 1
+	for i := len(history) - 1; i >= 0; i-- {
+		if prev := &history[i]; prev.Fingerprint == stageErr.Fingerprint &&
+			prev.Phase == stageErr.Phase && prev.Rollback == stageErr.Rollback {
+			stageErr.Attempt = prev.Attempt + 1
+			break
+		}
+	}
+	history = append(history, stageErr)
+	if len(history) > maxEntries {
+		history = history[len(history)-maxEntries:]
+	}
+	return history
+}
+
+// recordStageError records the error which occurred while executing the
+// stage of the job, and reports it on the SCHEMA_CHANGES logging channel.
+// Failing to record it is not fatal: the error is only logged.
+func recordStageError(
+	ctx context.Context,
+	deps JobRunDependencies,
+	jobID jobspb.JobID,
+	state scpb.CurrentState,
+	stage scplan.Stage,
+	err error,
+) {
+	// Don't record errors caused by the job being paused or canceled.
+	if ctx.Err() != nil || jobs.IsPauseSelfError(err) {
+		return
+	}
+	log.StructuredEvent(ctx, &eventpb.SchemaChangeFailed{
+		CommonSchemaChangeJobEventDetails: makeCommonSchemaChangeJobEventDetails(jobID, state),
+		Phase:                             stage.Phase.String(),
+		StageOrdinal:                      uint32(stage.Ordinal),
+		Error:                             err.Error(),
+	})
+	stageErr := jobspb.SchemaChangeStageError{
+		Phase:           stage.Phase.String(),
+		Ordinal:         int32(stage.Ordinal),
+		StagesInPhase:   int32(stage.StagesInPhase),
+		Rollback:        state.InRollback,
+		Fingerprint:     StageFingerprint(stage),
+		TimestampMicros: timeutil.ToUnixMicros(timeutil.Now()),
+	}
+	if recordErr := deps.RecordStageError(ctx, stageErr, err); recordErr != nil {
+		log.Warningf(ctx, "failed to record error for %s: %v", stage, recordErr)
+	}
+}
+
+// StageFingerprint hashes the statuses of the targets at the beginning of the
+// stage. Unlike its ordinal, this identifies the stage across re-plannings of
+// the schema change.
+func StageFingerprint(stage scplan.Stage) uint64 {
+	h := fnv.New64a()
+	var buf [binary.MaxVarintLen64]byte
+	for _, s := range stage.Before {
+		_, _ = h.Write(buf[:binary.PutUvarint(buf[:], uint64(s))])
+	}
+	return h.Sum64()
+}
@@ -0,0 +1,69 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scrun
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scplan"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendStageError(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	stageErr := func(fingerprint uint64, rollback bool) jobspb.SchemaChangeStageError {
+		return jobspb.SchemaChangeStageError{
+			Phase:       scop.PostCommitPhase.String(),
+			Rollback:    rollback,
+			Fingerprint: fingerprint,
+		}
+	}
+	attempts := func(history []jobspb.SchemaChangeStageError) (ret []int32) {
+		for _, se := range history {
+			ret = append(ret, se.Attempt)
+		}
+		return ret
+	}
+
+	var history []jobspb.SchemaChangeStageError
+	history = AppendStageError(history, stageErr(1, false), 5)
+	history = AppendStageError(history, stageErr(1, false), 5)
+	history = AppendStageError(history, stageErr(2, false), 5)
+	history = AppendStageError(history, stageErr(1, false), 5)
+	history = AppendStageError(history, stageErr(1, true), 5)
+	require.Equal(t, []int32{1, 2, 1, 3, 1}, attempts(history))
+
+	// The oldest entries are dropped.
+	history = AppendStageError(history, stageErr(2, false), 3)
+	require.Equal(t, []int32{3, 1, 2}, attempts(history))
+	require.Equal(t, uint64(1), history[0].Fingerprint)
+
+	// Attempts of stages no longer in the history start over.
+	history = AppendStageError(history, stageErr(3, false), 1)
+	require.Equal(t, []int32{1}, attempts(history))
+}
+
+func TestStageFingerprint(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	stage := func(before ...scpb.Status) scplan.Stage {
+		return scplan.Stage{Before: before}
+	}
+	a := StageFingerprint(stage(scpb.Status_ABSENT, scpb.Status_PUBLIC))
+	require.Equal(t, a, StageFingerprint(stage(scpb.Status_ABSENT, scpb.Status_PUBLIC)))
+	require.NotEqual(t, a, StageFingerprint(stage(scpb.Status_PUBLIC, scpb.Status_ABSENT)))
+	require.NotEqual(t, a, StageFingerprint(stage(scpb.Status_ABSENT)))
+}
@@ -0,0 +1,100 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scrun
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scexec"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scplan"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/screl"
+)
+
+// stageEventsEnabled controls whether the post-commit stages of declarative
+// schema change jobs are reported in the event log. It is disabled by default
+// because it writes two entries to the system.eventlog table per stage.
+var stageEventsEnabled = settings.RegisterBoolSetting(
+	settings.TenantWritable,
+	"sql.schema_changer.stage_events.enabled",
+	"if set, the beginning and the completion of each post-commit stage of a "+
+		"declarative schema change job are recorded in the event log and on "+
+		"the SQL_SCHEMA logging channel",
+	false,
+)
+
+// withStageEventOps returns the ops to execute for the given post-commit stage
+// of the plan, surrounded by the ops which log the beginning and the
+// completion of the stage. The ops of backfill and validation stages can't be
+// mixed with mutation ops, so for these stages the op logging the completion
+// is returned separately, to be executed after the stage's own ops, and the
+// beginning is logged beforehand by logStageBegin.
+func withStageEventOps(p scplan.Plan, stage scplan.Stage) (ops, after []scop.Op) {
+	begin := makeStageEventOp(p, stage)
+	finish := begin
+	finish.Completed = true
+	ops = stage.Ops()
+	if stage.Type() != scop.MutationType {
+		return ops, []scop.Op{&finish}
+	}
+	ret := make([]scop.Op, 0, len(ops)+2)
+	ret = append(ret, &begin)
+	ret = append(ret, ops...)
+	ret = append(ret, &finish)
+	return ret, nil
+}
+
+// logStageBegin logs the beginning of the given post-commit stage of the plan
+// in a transaction of its own if the stage is a backfill or validation stage.
+// Such stages can run for a long time, and the event would otherwise only be
+// recorded once the transaction of the stage commits, after the work is done.
+// The beginning of the mutation stages is logged in the transaction of the
+// stage by the ops returned by withStageEventOps.
+func logStageBegin(
+	ctx context.Context,
+	settings *cluster.Settings,
+	deps JobRunDependencies,
+	p scplan.Plan,
+	stage scplan.Stage,
+) error {
+	if stage.Phase < scop.PostCommitPhase || stage.Type() == scop.MutationType ||
+		!stageEventsEnabled.Get(&settings.SV) {
+		return nil
+	}
+	begin := makeStageEventOp(p, stage)
+	return deps.WithTxnInJob(ctx, func(ctx context.Context, td scexec.Dependencies) error {
+		return scexec.ExecuteStage(ctx, td, []scop.Op{&begin})
+	})
+}
+
+func makeStageEventOp(p scplan.Plan, stage scplan.Stage) scop.LogSchemaChangeStageEvent {
+	var transitions []string
+	for i, t := range p.TargetState.Targets {
+		if before, after := stage.Before[i], stage.After[i]; before != after {
+			transitions = append(transitions, fmt.Sprintf(
+				"%s: %s → %s", screl.ElementString(t.Element()), before, after,
+			))
+		}
+	}
+	return scop.LogSchemaChangeStageEvent{
+		JobID:              p.JobID,
+		Authorization:      p.Authorization,
+		Phase:              stage.Phase,
+		StageOrdinal:       stage.Ordinal,
+		StagesInPhase:      stage.StagesInPhase,
+		InRollback:         p.InRollback,
+		NumOps:             len(stage.Ops()),
+		ElementTransitions: transitions,
+	}
+}
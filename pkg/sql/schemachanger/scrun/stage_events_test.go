@@ -0,0 +1,99 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scrun
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scplan"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithStageEventOps(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	var p scplan.Plan
+	p.JobID = jobspb.JobID(42)
+	p.Authorization = scpb.Authorization{UserName: "root", AppName: "app"}
+	p.Targets = []scpb.Target{
+		scpb.MakeTarget(scpb.ToPublic, &scpb.Column{TableID: 104, ColumnID: 2}, nil),
+		scpb.MakeTarget(scpb.ToPublic, &scpb.ColumnName{TableID: 104, ColumnID: 2, Name: "j"}, nil),
+	}
+	mutation := scplan.Stage{
+		Before:        []scpb.Status{scpb.Status_DELETE_ONLY, scpb.Status_PUBLIC},
+		After:         []scpb.Status{scpb.Status_WRITE_ONLY, scpb.Status_PUBLIC},
+		EdgeOps:       []scop.Op{&scop.MakeAddedColumnDeleteAndWriteOnly{TableID: 104, ColumnID: 2}},
+		Phase:         scop.PostCommitPhase,
+		Ordinal:       1,
+		StagesInPhase: 2,
+	}
+
+	// The logging ops surround the ops of mutation stages.
+	ops, after := withStageEventOps(p, mutation)
+	require.Empty(t, after)
+	require.Len(t, ops, 3)
+	require.Equal(t, mutation.EdgeOps[0], ops[1])
+	begin, finish := ops[0].(*scop.LogSchemaChangeStageEvent), ops[2].(*scop.LogSchemaChangeStageEvent)
+	require.False(t, begin.Completed)
+	require.True(t, finish.Completed)
+	for _, op := range []*scop.LogSchemaChangeStageEvent{begin, finish} {
+		require.Equal(t, jobspb.JobID(42), op.JobID)
+		require.Equal(t, "app", op.Authorization.AppName)
+		require.Equal(t, scop.PostCommitPhase, op.Phase)
+		require.Equal(t, 1, op.StageOrdinal)
+		require.Equal(t, 2, op.StagesInPhase)
+		require.Equal(t, 1, op.NumOps)
+		require.Equal(t, []string{
+			"Column:{DescID: 104, ColumnID: 2}: DELETE_ONLY → WRITE_ONLY",
+		}, op.ElementTransitions)
+	}
+
+	// The completion of the other stages is logged after their ops, and their
+	// beginning in a separate transaction beforehand.
+	backfill := mutation
+	backfill.EdgeOps = []scop.Op{&scop.BackfillIndex{TableID: 104, SourceIndexID: 1, IndexID: 2}}
+	backfill.Ordinal = 2
+	ops, after = withStageEventOps(p, backfill)
+	require.Equal(t, backfill.EdgeOps, ops)
+	require.Len(t, after, 1)
+	require.Equal(t, scop.MutationType, after[0].Type())
+	require.Equal(t, 2, after[0].(*scop.LogSchemaChangeStageEvent).StageOrdinal)
+	require.True(t, after[0].(*scop.LogSchemaChangeStageEvent).Completed)
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	deps := &countingTxnDeps{}
+	require.NoError(t, logStageBegin(ctx, st, deps, p, backfill))
+	require.Zero(t, deps.txns, "stage events are disabled by default")
+	stageEventsEnabled.Override(ctx, &st.SV, true)
+	require.NoError(t, logStageBegin(ctx, st, deps, p, mutation))
+	require.Zero(t, deps.txns)
+	require.NoError(t, logStageBegin(ctx, st, deps, p, backfill))
+	require.Equal(t, 1, deps.txns)
+}
+
+// countingTxnDeps counts the transactions opened via WithTxnInJob, without
+// running them.
+type countingTxnDeps struct {
+	JobRunDependencies
+	txns int
+}
+
+func (d *countingTxnDeps) WithTxnInJob(context.Context, JobTxnFunc) error {
+	d.txns++
+	return nil
+}
@@ -0,0 +1,95 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scrun
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scplan"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// ResetStageProgress returns the progress of the stages of a schema change
+// job once it has been planned: the stages completed by previous executions
+// of the job are kept, and those of the plan are appended as pending.
+func ResetStageProgress(
+	history []jobspb.SchemaChangeStageProgress, p scplan.Plan,
+) []jobspb.SchemaChangeStageProgress {
+	ret := make([]jobspb.SchemaChangeStageProgress, 0, len(history)+len(p.Stages))
+	for _, sp := range history {
+		if sp.CompletedMicros != 0 {
+			ret = append(ret, sp)
+		}
+	}
+	for _, stage := range p.Stages {
+		ret = append(ret, jobspb.SchemaChangeStageProgress{
+			Phase:         stage.Phase.String(),
+			Ordinal:       int32(stage.Ordinal),
+			StagesInPhase: int32(stage.StagesInPhase),
+			Rollback:      p.InRollback,
+			Fingerprint:   StageFingerprint(stage),
+			Type:          stageTypeName(stage.Type()),
+			NumOps:        int32(len(stage.Ops())),
+		})
+	}
+	return ret
+}
+
+// StageProgressEstimatedCompletion estimates when the running stage will
+// complete, based on the fraction of it which is done so far. It returns
+// false if no estimate can be made.
+func StageProgressEstimatedCompletion(
+	sp *jobspb.SchemaChangeStageProgress, now time.Time,
+) (time.Time, bool) {
+	if sp.StartedMicros == 0 || sp.CompletedMicros != 0 ||
+		sp.FractionCompleted <= 0 || sp.FractionCompleted > 1 {
+		return time.Time{}, false
+	}
+	started := timeutil.FromUnixMicros(sp.StartedMicros)
+	elapsed := now.Sub(started)
+	return started.Add(time.Duration(float64(elapsed) / float64(sp.FractionCompleted))), true
+}
+
+// findPendingStage returns the first entry of the progress of the stages
+// which matches the stage and which isn't completed, or nil if there is none.
+func findPendingStage(
+	p *jobspb.NewSchemaChangeProgress, stage scplan.Stage, rollback bool,
+) *jobspb.SchemaChangeStageProgress {
+	fingerprint := StageFingerprint(stage)
+	for i := range p.Stages {
+		if sp := &p.Stages[i]; sp.CompletedMicros == 0 && sp.Fingerprint == fingerprint &&
+			sp.Phase == stage.Phase.String() && sp.Rollback == rollback {
+			return sp
+		}
+	}
+	return nil
+}
+
+// updateStageProgress updates the progress of the stages of the job. Failing
+// to do so is not fatal: the error is only logged.
+func updateStageProgress(
+	ctx context.Context, deps JobRunDependencies, fn func(p *jobspb.NewSchemaChangeProgress),
+) {
+	if err := deps.UpdateStageProgress(ctx, fn); err != nil {
+		log.Warningf(ctx, "failed to update the progress of the stages: %v", err)
+	}
+}
+
+// stageTypeName returns the name of the type of ops in the progress of a
+// stage, e.g. "backfill" for scop.BackfillType.
+func stageTypeName(t scop.Type) string {
+	return strings.ToLower(strings.TrimSuffix(t.String(), "Type"))
+}
@@ -0,0 +1,90 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scrun
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scplan"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResetStageProgress(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	stage := func(ordinal int, before ...scpb.Status) scplan.Stage {
+		return scplan.Stage{
+			Before:        before,
+			Phase:         scop.PostCommitPhase,
+			Ordinal:       ordinal,
+			StagesInPhase: 2,
+		}
+	}
+	var p scplan.Plan
+	p.Stages = []scplan.Stage{
+		stage(1, scpb.Status_ABSENT),
+		stage(2, scpb.Status_DELETE_ONLY),
+	}
+	history := ResetStageProgress(nil, p)
+	require.Len(t, history, 2)
+	require.Equal(t, int32(1), history[0].Ordinal)
+	require.Equal(t, int32(2), history[1].Ordinal)
+
+	// Complete the first stage and start the second one, then re-plan the
+	// remaining stage: only the completed stage is kept.
+	progress := jobspb.NewSchemaChangeProgress{Stages: history}
+	sp := findPendingStage(&progress, p.Stages[0], false /* rollback */)
+	require.NotNil(t, sp)
+	sp.StartedMicros, sp.CompletedMicros, sp.FractionCompleted = 1, 2, 1
+	sp = findPendingStage(&progress, p.Stages[1], false /* rollback */)
+	require.NotNil(t, sp)
+	sp.StartedMicros = 3
+	require.Equal(t, sp, progress.RunningStage())
+	require.Equal(t, float32(0.5), progress.FractionCompleted())
+	require.Nil(t, findPendingStage(&progress, p.Stages[1], true /* rollback */))
+
+	p.Stages = []scplan.Stage{stage(1, scpb.Status_DELETE_ONLY)}
+	p.Stages[0].StagesInPhase = 1
+	progress.Stages = ResetStageProgress(progress.Stages, p)
+	require.Len(t, progress.Stages, 2)
+	require.Equal(t, int64(2), progress.Stages[0].CompletedMicros)
+	require.Zero(t, progress.Stages[1].StartedMicros)
+	require.Equal(t, int32(1), progress.Stages[1].StagesInPhase)
+	require.Nil(t, progress.RunningStage())
+}
+
+func TestStageProgressEstimatedCompletion(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	started := timeutil.Unix(1000, 0)
+	now := started.Add(time.Minute)
+	sp := jobspb.SchemaChangeStageProgress{StartedMicros: timeutil.ToUnixMicros(started)}
+
+	// No estimate without progress.
+	_, ok := StageProgressEstimatedCompletion(&sp, now)
+	require.False(t, ok)
+
+	sp.FractionCompleted = 0.25
+	eta, ok := StageProgressEstimatedCompletion(&sp, now)
+	require.True(t, ok)
+	require.Equal(t, started.Add(4*time.Minute), eta)
+
+	// No estimate once the stage is completed.
+	sp.CompletedMicros = timeutil.ToUnixMicros(now)
+	_, ok = StageProgressEstimatedCompletion(&sp, now)
+	require.False(t, ok)
+}
@@ -0,0 +1,84 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scrun
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scplan"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// ThroughputRecord is the throughput of a completed backfill or validation
+// stage of a schema change job.
+type ThroughputRecord struct {
+	JobID jobspb.JobID
+	// Stage is the number of the stage in the job, starting at 1.
+	Stage     int64
+	StageType scop.Type
+	// Rows and Bytes are the estimated cost of the stage, as derived from the
+	// statistics of the tables which it scans.
+	Rows, Bytes uint64
+	// Duration is the time it took to execute the stage.
+	Duration time.Duration
+}
+
+// makeThroughputRecord returns the throughput of the given stage, which took
+// the given time to execute, or false if the stage has no estimated cost to
+// derive it from.
+func makeThroughputRecord(
+	jobID jobspb.JobID, stageNum int64, stage scplan.Stage, duration time.Duration,
+) (ThroughputRecord, bool) {
+	if stage.Estimate == nil || stage.Estimate.Rows == 0 || duration <= 0 {
+		return ThroughputRecord{}, false
+	}
+	switch stage.Type() {
+	case scop.BackfillType, scop.ValidationType:
+	default:
+		return ThroughputRecord{}, false
+	}
+	return ThroughputRecord{
+		JobID:     jobID,
+		Stage:     stageNum,
+		StageType: stage.Type(),
+		Rows:      stage.Estimate.Rows,
+		Bytes:     stage.Estimate.Bytes,
+		Duration:  duration,
+	}, true
+}
+
+// recordThroughput records the throughput of the given stage in the history
+// of the dependencies, if any. The history is best-effort: failing to record
+// it does not fail the schema change.
+func recordThroughput(
+	ctx context.Context,
+	deps JobRunDependencies,
+	jobID jobspb.JobID,
+	stageNum int64,
+	stage scplan.Stage,
+	duration time.Duration,
+) {
+	h := deps.ThroughputHistory()
+	if h == nil {
+		return
+	}
+	r, ok := makeThroughputRecord(jobID, stageNum, stage, duration)
+	if !ok {
+		return
+	}
+	if err := h.RecordThroughput(ctx, r); err != nil {
+		log.Warningf(ctx, "failed to record the throughput of stage %d of job %d: %v",
+			stageNum, jobID, err)
+	}
+}
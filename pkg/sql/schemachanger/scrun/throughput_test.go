@@ -0,0 +1,56 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scrun
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scplan"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMakeThroughputRecord(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	estimate := &scplan.Estimate{Rows: 1000, Bytes: 10000, IndexWrites: 1000}
+	backfill := scplan.Stage{
+		EdgeOps:  []scop.Op{&scop.BackfillIndex{TableID: 104, SourceIndexID: 1, IndexID: 2}},
+		Phase:    scop.PostCommitPhase,
+		Estimate: estimate,
+	}
+	r, ok := makeThroughputRecord(1, 3, backfill, 2*time.Second)
+	require.True(t, ok)
+	require.Equal(t, ThroughputRecord{
+		JobID:     1,
+		Stage:     3,
+		StageType: scop.BackfillType,
+		Rows:      1000,
+		Bytes:     10000,
+		Duration:  2 * time.Second,
+	}, r)
+
+	// Stages without an estimate are not recorded.
+	noEstimate := backfill
+	noEstimate.Estimate = nil
+	_, ok = makeThroughputRecord(1, 3, noEstimate, 2*time.Second)
+	require.False(t, ok)
+
+	// Neither are mutation stages.
+	mutation := scplan.Stage{
+		EdgeOps:  []scop.Op{&scop.MakeAddedColumnDeleteAndWriteOnly{TableID: 104, ColumnID: 2}},
+		Phase:    scop.PostCommitPhase,
+		Estimate: estimate,
+	}
+	_, ok = makeThroughputRecord(1, 3, mutation, 2*time.Second)
+	require.False(t, ok)
+}
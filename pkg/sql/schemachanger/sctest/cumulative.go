@@ -29,6 +29,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/corpus"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scbuild"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scexec"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scfmt"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scplan"
@@ -151,7 +152,11 @@ func Rollback(t *testing.T, relPath string, newCluster NewClusterFunc) {
 		db, cleanup := newCluster(t, &scexec.TestingKnobs{
 			BeforeStage: beforeStage,
 			OnPostCommitPlanError: func(state *scpb.CurrentState, err error) error {
-				panic(fmt.Sprintf("%+v", err))
+				s, fmtErr := scfmt.State(*state, true /* verbose */)
+				if fmtErr != nil {
+					s = fmtErr.Error()
+				}
+				panic(fmt.Sprintf("%+v\nstate:\n%s", err, s))
 			},
 			OnPostCommitError: func(p scplan.Plan, stageIdx int, err error) error {
 				if strings.Contains(err.Error(), "boom") {
@@ -25,6 +25,7 @@ import (
 	"testing"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/keys"
@@ -238,6 +239,7 @@ func checkExplainDiagrams(
 	params := scplan.Params{
 		ExecutionPhase:             scop.StatementPhase,
 		SchemaChangerJobIDSupplier: func() jobspb.JobID { return 1 },
+		ActiveVersion:              clusterversion.TestingClusterVersion,
 	}
 	if inRollback {
 		params.InRollback = true
@@ -296,6 +298,7 @@ func execStatementWithTestDeps(
 		const rollback = false
 		err = scrun.RunSchemaChangesInJob(
 			ctx, deps.TestingKnobs(), deps.ClusterSettings(), deps, jobID, job.DescriptorIDs, rollback,
+			job.Details.(jobspb.NewSchemaChangeDetails).SafeMode,
 		)
 		require.NoError(t, err, "error in mock schema change job execution")
 		deps.LogSideEffectf("# end %s", deps.Phase())
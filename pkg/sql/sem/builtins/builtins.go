@@ -5621,6 +5621,174 @@ value if you rely on the HLC for accuracy.`,
 		},
 	),
 
+	"crdb_internal.set_vmodule_for_channel": makeBuiltin(
+		tree.FunctionProperties{
+			Category: builtinconstants.CategorySystemInfo,
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"channel", types.String},
+				{"vmodule_string", types.String},
+			},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(ctx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				isAdmin, err := ctx.SessionAccessor.HasAdminRole(ctx.Context)
+				if err != nil {
+					return nil, err
+				}
+				if !isAdmin {
+					return nil, errInsufficientPriv
+				}
+
+				ch, err := log.ParseChannel(string(tree.MustBeDString(args[0])))
+				if err != nil {
+					return nil, err
+				}
+				return tree.DZero, log.SetVModuleForChannel(ch, string(tree.MustBeDString(args[1])))
+			},
+			Info: "Set the logging verbosity of different files for a single logging channel " +
+				"on the gateway node processing this request, using the syntax of the `--vmodule` flag. " +
+				"Unlike `crdb_internal.set_vmodule`, this only enables the entries logged to that channel. " +
+				"Example syntax: `crdb_internal.set_vmodule_for_channel('DEV', 'recordio=2,file=1,gfs*=3')`. " +
+				"Reset with: `crdb_internal.set_vmodule_for_channel('DEV', '')`. " +
+				"Raising the verbosity can severely affect performance.",
+			Volatility: volatility.Volatile,
+		},
+	),
+
+	"crdb_internal.get_vmodule_for_channel": makeBuiltin(
+		tree.FunctionProperties{
+			Category: builtinconstants.CategorySystemInfo,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"channel", types.String}},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(ctx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				// The user must be an admin to use this builtin.
+				isAdmin, err := ctx.SessionAccessor.HasAdminRole(ctx.Context)
+				if err != nil {
+					return nil, err
+				}
+				if !isAdmin {
+					return nil, errInsufficientPriv
+				}
+				ch, err := log.ParseChannel(string(tree.MustBeDString(args[0])))
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDString(log.GetVModuleForChannel(ch)), nil
+			},
+			Info:       "Returns the vmodule configuration specific to a logging channel on the gateway node processing this request.",
+			Volatility: volatility.Volatile,
+		},
+	),
+
+	"crdb_internal.set_vmodule_for_tags": makeBuiltin(
+		tree.FunctionProperties{
+			Category: builtinconstants.CategorySystemInfo,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"vmodule_string", types.String}},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(ctx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				isAdmin, err := ctx.SessionAccessor.HasAdminRole(ctx.Context)
+				if err != nil {
+					return nil, err
+				}
+				if !isAdmin {
+					return nil, errInsufficientPriv
+				}
+				return tree.DZero, log.SetVModuleForTags(string(tree.MustBeDString(args[0])))
+			},
+			Info: "Set the logging verbosity of the code paths operating under a given log tag, " +
+				"e.g. on a single range, on the gateway node processing this request. " +
+				"The tags are identified by their key and value, as they appear in the log entries. " +
+				"Unlike `crdb_internal.set_vmodule`, this only enables the entries logged under " +
+				"a context carrying one of the tags. " +
+				"Example syntax: `crdb_internal.set_vmodule_for_tags('r:53=2,job:123=1')`. " +
+				"Reset with: `crdb_internal.set_vmodule_for_tags('')`. " +
+				"Raising the verbosity can severely affect performance.",
+			Volatility: volatility.Volatile,
+		},
+	),
+
+	"crdb_internal.get_vmodule_for_tags": makeBuiltin(
+		tree.FunctionProperties{
+			Category: builtinconstants.CategorySystemInfo,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(ctx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				// The user must be an admin to use this builtin.
+				isAdmin, err := ctx.SessionAccessor.HasAdminRole(ctx.Context)
+				if err != nil {
+					return nil, err
+				}
+				if !isAdmin {
+					return nil, errInsufficientPriv
+				}
+				return tree.NewDString(log.GetVModuleForTags()), nil
+			},
+			Info:       "Returns the vmodule configuration specific to log tags on the gateway node processing this request.",
+			Volatility: volatility.Volatile,
+		},
+	),
+
+	"crdb_internal.set_log_channel_severities": makeBuiltin(
+		tree.FunctionProperties{
+			Category: builtinconstants.CategorySystemInfo,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"severities", types.String}},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(ctx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				isAdmin, err := ctx.SessionAccessor.HasAdminRole(ctx.Context)
+				if err != nil {
+					return nil, err
+				}
+				if !isAdmin {
+					return nil, errInsufficientPriv
+				}
+
+				s, ok := tree.AsDString(args[0])
+				if !ok {
+					return nil, errors.Newf("expected string value, got %T", args[0])
+				}
+				return tree.DZero, log.SetChannelSeverities(string(s))
+			},
+			Info: "Override the severity threshold of logging channels on the gateway node processing this request, " +
+				"for every sink the channels are connected to. " +
+				"Example syntax: `crdb_internal.set_log_channel_severities('SQL_EXEC=INFO,HEALTH=WARNING')`. " +
+				"Reset with: `crdb_internal.set_log_channel_severities('')`. " +
+				"Lowering the thresholds can severely affect performance.",
+			Volatility: volatility.Volatile,
+		},
+	),
+
+	"crdb_internal.get_log_channel_severities": makeBuiltin(
+		tree.FunctionProperties{
+			Category: builtinconstants.CategorySystemInfo,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(ctx *eval.Context, _ tree.Datums) (tree.Datum, error) {
+				// The user must be an admin to use this builtin.
+				isAdmin, err := ctx.SessionAccessor.HasAdminRole(ctx.Context)
+				if err != nil {
+					return nil, err
+				}
+				if !isAdmin {
+					return nil, errInsufficientPriv
+				}
+				return tree.NewDString(log.GetChannelSeverities()), nil
+			},
+			Info:       "Returns the logging channel severity overrides on the gateway node processing this request.",
+			Volatility: volatility.Volatile,
+		},
+	),
+
 	// Returns the number of distinct inverted index entries that would be
 	// generated for a value.
 	"crdb_internal.num_geo_inverted_index_entries": makeBuiltin(
@@ -6733,6 +6901,34 @@ table's zone configuration this will return NULL.`,
 		},
 	),
 
+	"crdb_internal.schema_change_plan_json": makeBuiltin(
+		tree.FunctionProperties{
+			Category: builtinconstants.CategorySystemInfo,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"job_id", types.Int}},
+			ReturnType: tree.FixedReturnType(types.Jsonb),
+			Fn: func(evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				isAdmin, err := evalCtx.SessionAccessor.HasAdminRole(evalCtx.Context)
+				if err != nil {
+					return nil, err
+				}
+				if !isAdmin {
+					return nil, errInsufficientPriv
+				}
+				jobID := int64(tree.MustBeDInt(args[0]))
+				plan, err := evalCtx.Planner.DeclarativeSchemaChangePlanJSON(evalCtx.Ctx(), jobID)
+				if err != nil {
+					return nil, err
+				}
+				return tree.ParseDJSON(plan)
+			},
+			Info: `Returns the plan for the remaining stages of the given declarative ` +
+				`schema change job, in the format of EXPLAIN (DDL, JSON).`,
+			Volatility: volatility.Volatile,
+		},
+	),
+
 	"crdb_internal.check_password_hash_format": makeBuiltin(
 		tree.FunctionProperties{
 			Category: builtinconstants.CategorySystemInfo,
@@ -13,11 +13,12 @@ package builtins
 import (
 	"context"
 	"fmt"
-	"sort"
 	"unsafe"
 
 	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/memsize"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/screl"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
@@ -105,45 +106,45 @@ func getTopologicallySortedTableIDs(
 		}
 	}
 
-	// First sort by ids to guarantee stable output.
-	sort.Slice(ids, func(i, j int) bool {
-		return ids[i] < ids[j]
-	})
-
-	// Collect transitive dependencies in topological order into collected.
-	// The topological order is essential here since it captures dependencies
-	// for views and sequences creation, hence simple alphabetical sort won't
-	// be enough.
-	var topologicallyOrderedIDs []int64
-
-	// The sort relies on creating a new array for the ids.
-	if err = acc.Grow(ctx, int64(len(ids))*memsize.Int64); err != nil {
+	// Collect transitive dependencies in topological order. The topological
+	// order is essential here since it captures dependencies for views and
+	// sequences creation, hence simple alphabetical sort won't be enough.
+	// Foreign keys are added by ALTER statements after all the tables have
+	// been created.
+	//
+	// The sort relies on creating a new array for the ids, and on a set of
+	// the visited ids.
+	sizeOfSort := int64(len(ids)) * (2*memsize.Int64 + mapEntryOverhead)
+	if err = acc.Grow(ctx, sizeOfSort); err != nil {
 		return nil, err
 	}
-	seen := make(map[int64]struct{})
-	for _, id := range ids {
-		if err := topologicalSort(
-			ctx, id, dependsOnIDs, seen, &topologicallyOrderedIDs, acc,
-		); err != nil {
-			return nil, err
-		}
+	descIDs := make([]descpb.ID, len(ids))
+	for i, id := range ids {
+		descIDs[i] = descpb.ID(id)
 	}
-
-	// Clear memory used by the seen map.
-	sizeOfSeen := len(seen)
-	seen = nil
-	acc.Shrink(ctx, int64(sizeOfSeen)*(memsize.Int64+mapEntryOverhead))
+	ordered := screl.TopologicalOrder(descIDs, func(id descpb.ID) []descpb.ID {
+		refs := dependsOnIDs[int64(id)]
+		deps := make([]descpb.ID, len(refs))
+		for i, ref := range refs {
+			deps[i] = descpb.ID(ref)
+		}
+		return deps
+	})
 
 	// The lengths should match. This is also important for memory accounting,
 	// the two arrays should have the same length.
-	if len(ids) != len(topologicallyOrderedIDs) {
+	if len(ids) != len(ordered) {
 		return nil, errors.AssertionFailedf("show_create_all_tables_builtin failed. "+
 			"len(ids):% d not equal to len(topologicallySortedIDs): %d",
-			len(ids), len(topologicallyOrderedIDs))
+			len(ids), len(ordered))
+	}
+	topologicallyOrderedIDs := ids[:0]
+	for _, id := range ordered {
+		topologicallyOrderedIDs = append(topologicallyOrderedIDs, int64(id))
 	}
 
-	// Shrink the memory we used for the original ids array.
-	acc.Shrink(ctx, int64(len(ids))*memsize.Int64)
+	// Shrink the memory we used for the sort and the dependencies.
+	acc.Shrink(ctx, sizeOfSort)
 	acc.Shrink(ctx, sizeOfMap)
 	return topologicallyOrderedIDs, nil
 }
@@ -190,54 +191,6 @@ func getTableIDs(
 	return tableIDs, nil
 }
 
-// topologicalSort sorts transitive dependencies in topological order into
-// collected.
-// The topological order is essential here since it captures dependencies
-// for views and sequences creation, hence simple alphabetical sort won't
-// be enough.
-func topologicalSort(
-	ctx context.Context,
-	tid int64,
-	dependsOnIDs map[int64][]int64,
-	seen map[int64]struct{},
-	collected *[]int64,
-	acc *mon.BoundAccount,
-) error {
-	// has this table already been collected previously?
-	// We need this check because a table could be traversed to multiple times
-	// if it is referenced.
-	// For example, if a table references itself, without this check
-	// collect would infinitely recurse.
-	if _, isPresent := seen[tid]; isPresent {
-		return nil
-	}
-
-	// Skip IDs that are not in the dependsOn set.
-	if _, exists := dependsOnIDs[tid]; !exists {
-		return nil
-	}
-
-	// Account for memory of map.
-	// The key value entry into the map is only the memory of an int64 since
-	// the value stuct{}{} uses no memory.
-	if err := acc.Grow(ctx, memsize.Int64+mapEntryOverhead); err != nil {
-		return err
-	}
-	seen[tid] = struct{}{}
-	for _, dep := range dependsOnIDs[tid] {
-		if err := topologicalSort(ctx, dep, dependsOnIDs, seen, collected, acc); err != nil {
-			return err
-		}
-	}
-
-	if err := acc.Grow(ctx, int64(unsafe.Sizeof(tid))); err != nil {
-		return err
-	}
-	*collected = append(*collected, tid)
-
-	return nil
-}
-
 // getCreateStatement gets the create statement to recreate a table (ignoring fks)
 // for a given table id in a database.
 func getCreateStatement(
@@ -84,6 +84,8 @@ const (
 	SystemPrivilegeTableName               SystemTableName = "privileges"
 	SystemExternalConnectionsTableName     SystemTableName = "external_connections"
 	RoleIDSequenceName                     SystemTableName = "role_id_seq"
+	SchemaChangeElementStatusesTableName   SystemTableName = "schema_change_element_statuses"
+	SchemaChangeThroughputTableName        SystemTableName = "schema_change_throughput"
 )
 
 // Oid for virtual database and table.
@@ -161,6 +163,11 @@ const (
 	CrdbInternalTenantUsageDetailsViewID
 	CrdbInternalPgCatalogTableIsImplementedTableID
 	CrdbInternalSuperRegions
+	CrdbInternalLoggingSinksTableID
+	CrdbInternalSchemaChangeStageErrorsTableID
+	CrdbInternalSchemaChangeStagesTableID
+	CrdbInternalClusterLoggingTableID
+	CrdbInternalNodeRecentLogEntriesTableID
 	InformationSchemaID
 	InformationSchemaAdministrableRoleAuthorizationsID
 	InformationSchemaApplicableRolesID
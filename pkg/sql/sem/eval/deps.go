@@ -316,6 +316,11 @@ type Planner interface {
 	// it is invalid.
 	RepairTTLScheduledJobForTable(ctx context.Context, tableID int64) error
 
+	// DeclarativeSchemaChangePlanJSON returns the plan for the remaining stages
+	// of the given declarative schema change job, in the format of
+	// EXPLAIN (DDL, JSON).
+	DeclarativeSchemaChangePlanJSON(ctx context.Context, jobID int64) (string, error)
+
 	// QueryRowEx executes the supplied SQL statement and returns a single row, or
 	// nil if no row is found, or an error if more that one row is returned.
 	//
@@ -116,18 +116,22 @@ const (
 	ExplainFlagMemo
 	ExplainFlagShape
 	ExplainFlagViz
+	ExplainFlagVerify
+	ExplainFlagRollback
 	numExplainFlags = iota
 )
 
 var explainFlagStrings = [...]string{
-	ExplainFlagVerbose: "VERBOSE",
-	ExplainFlagTypes:   "TYPES",
-	ExplainFlagEnv:     "ENV",
-	ExplainFlagCatalog: "CATALOG",
-	ExplainFlagJSON:    "JSON",
-	ExplainFlagMemo:    "MEMO",
-	ExplainFlagShape:   "SHAPE",
-	ExplainFlagViz:     "VIZ",
+	ExplainFlagVerbose:  "VERBOSE",
+	ExplainFlagTypes:    "TYPES",
+	ExplainFlagEnv:      "ENV",
+	ExplainFlagCatalog:  "CATALOG",
+	ExplainFlagJSON:     "JSON",
+	ExplainFlagMemo:     "MEMO",
+	ExplainFlagShape:    "SHAPE",
+	ExplainFlagViz:      "VIZ",
+	ExplainFlagVerify:   "VERIFY",
+	ExplainFlagRollback: "ROLLBACK",
 }
 
 var explainFlagStringMap = func() map[string]ExplainFlag {
@@ -253,14 +257,27 @@ func MakeExplain(options []string, stmt Statement) (Statement, error) {
 		opts.Mode = ExplainPlan
 	}
 	if opts.Flags[ExplainFlagJSON] {
-		if opts.Mode != ExplainDistSQL {
-			return nil, pgerror.Newf(pgcode.Syntax, "the JSON flag can only be used with DISTSQL")
+		if opts.Mode != ExplainDistSQL && opts.Mode != ExplainDDL {
+			return nil, pgerror.Newf(pgcode.Syntax, "the JSON flag can only be used with DISTSQL or DDL")
 		}
 		if analyze {
 			return nil, pgerror.Newf(pgcode.Syntax, "the JSON flag cannot be used with ANALYZE")
 		}
 	}
 
+	if opts.Flags[ExplainFlagVerify] && opts.Mode != ExplainDDL {
+		return nil, pgerror.Newf(pgcode.Syntax, "the VERIFY flag can only be used with DDL")
+	}
+
+	if opts.Flags[ExplainFlagRollback] {
+		if opts.Mode != ExplainDDL {
+			return nil, pgerror.Newf(pgcode.Syntax, "the ROLLBACK flag can only be used with DDL")
+		}
+		if opts.Flags[ExplainFlagJSON] || opts.Flags[ExplainFlagViz] || opts.Flags[ExplainFlagVerify] {
+			return nil, pgerror.Newf(pgcode.Syntax, "the ROLLBACK flag cannot be used with JSON, VIZ or VERIFY")
+		}
+	}
+
 	if analyze {
 		if opts.Mode != ExplainDistSQL && opts.Mode != ExplainDebug && opts.Mode != ExplainPlan {
 			return nil, pgerror.Newf(pgcode.Syntax, "EXPLAIN ANALYZE cannot be used with %s", opts.Mode)
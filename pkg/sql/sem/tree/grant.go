@@ -27,6 +27,10 @@ type Grant struct {
 	Targets         GrantTargetList
 	Grantees        RoleSpecList
 	WithGrantOption bool
+	// Columns is the list of columns on which the privileges are granted, if
+	// they are granted on the columns of the target table rather than on the
+	// table itself.
+	Columns NameList
 }
 
 // GrantTargetList represents a list of targets.
@@ -103,6 +107,11 @@ func (node *Grant) Format(ctx *FmtCtx) {
 		ctx.WriteString(" SYSTEM ")
 	}
 	node.Privileges.Format(&ctx.Buffer)
+	if node.Columns != nil {
+		ctx.WriteString(" (")
+		ctx.FormatNode(&node.Columns)
+		ctx.WriteByte(')')
+	}
 	if !node.Targets.System {
 		ctx.WriteString(" ON ")
 		ctx.FormatNode(&node.Targets)
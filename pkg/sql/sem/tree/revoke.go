@@ -28,6 +28,10 @@ type Revoke struct {
 	Targets        GrantTargetList
 	Grantees       RoleSpecList
 	GrantOptionFor bool
+	// Columns is the list of columns on which the privileges are revoked, if
+	// they are revoked on the columns of the target table rather than on the
+	// table itself.
+	Columns NameList
 }
 
 // Format implements the NodeFormatter interface.
@@ -40,6 +44,11 @@ func (node *Revoke) Format(ctx *FmtCtx) {
 	// not an AST node. This is OK, because a privilege list cannot
 	// contain sensitive information.
 	node.Privileges.Format(&ctx.Buffer)
+	if node.Columns != nil {
+		ctx.WriteString(" (")
+		ctx.FormatNode(&node.Columns)
+		ctx.WriteByte(')')
+	}
 	if !node.Targets.System {
 		ctx.WriteString(" ON ")
 		ctx.FormatNode(&node.Targets)
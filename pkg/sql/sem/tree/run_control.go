@@ -15,6 +15,9 @@ type ControlJobs struct {
 	Jobs    *Select
 	Command JobCommand
 	Reason  Expr
+	// AtStage, if non-zero, is the post-commit stage of a declarative schema
+	// change job at which a PauseJob command takes effect.
+	AtStage int64
 }
 
 // JobCommand determines which type of action to effect on the selected job(s).
@@ -37,8 +40,20 @@ var JobCommandToStatement = map[JobCommand]string{
 // Format implements the NodeFormatter interface.
 func (n *ControlJobs) Format(ctx *FmtCtx) {
 	ctx.WriteString(JobCommandToStatement[n.Command])
-	ctx.WriteString(" JOBS ")
-	ctx.FormatNode(n.Jobs)
+	if n.AtStage != 0 {
+		// AT STAGE is only supported by the single job form of the statement.
+		if v, ok := n.Jobs.Select.(*ValuesClause); ok && len(v.Rows) == 1 && len(v.Rows[0]) == 1 {
+			ctx.WriteString(" JOB ")
+			ctx.FormatNode(v.Rows[0][0])
+		} else {
+			ctx.WriteString(" JOBS ")
+			ctx.FormatNode(n.Jobs)
+		}
+		ctx.Printf(" AT STAGE %d", n.AtStage)
+	} else {
+		ctx.WriteString(" JOBS ")
+		ctx.FormatNode(n.Jobs)
+	}
 	if n.Reason != nil {
 		ctx.WriteString(" WITH REASON = ")
 		ctx.FormatNode(n.Reason)
@@ -248,6 +248,11 @@ const (
 	// UserLow denotes an end user QoS level lower than the default.
 	UserLow = QoSLevel(admissionpb.UserLowPri)
 
+	// BulkLow denotes a QoS level used internally by bulk operations, like the
+	// backfills and validations of schema changes, which is not settable as a
+	// session default_transaction_quality_of_service value.
+	BulkLow = QoSLevel(admissionpb.BulkNormalPri)
+
 	// Normal denotes an end user QoS level unchanged from the default.
 	Normal = QoSLevel(admissionpb.NormalPri)
 
@@ -287,6 +292,10 @@ const (
 	// TTLLowName is the string value to display indicating a TTLLow QoS level.
 	TTLLowName = "ttl_low"
 
+	// BulkLowName is the string value to display indicating a BulkLow QoS
+	// level.
+	BulkLowName = "bulk_low"
+
 	// LockingName is the string value to display indicating a Locking QoS level.
 	LockingName = "locking"
 )
@@ -295,6 +304,7 @@ var qosLevelsDict = map[QoSLevel]string{
 	SystemLow:  SystemLowName,
 	TTLLow:     TTLLowName,
 	UserLow:    UserLowName,
+	BulkLow:    BulkLowName,
 	Normal:     NormalName,
 	UserHigh:   UserHighName,
 	Locking:    LockingName,
@@ -115,6 +115,16 @@ var ExplainDDLVerbose = telemetry.GetCounterOnce("sql.plan.explain-ddl-verbose")
 // ExplainDDLViz is to be incremented whenever EXPLAIN (DDL, VIZ) is run.
 var ExplainDDLViz = telemetry.GetCounterOnce("sql.plan.explain-ddl-viz")
 
+// ExplainDDLJSON is to be incremented whenever EXPLAIN (DDL, JSON) is run.
+var ExplainDDLJSON = telemetry.GetCounterOnce("sql.plan.explain-ddl-json")
+
+// ExplainDDLVerify is to be incremented whenever EXPLAIN (DDL, VERIFY) is run.
+var ExplainDDLVerify = telemetry.GetCounterOnce("sql.plan.explain-ddl-verify")
+
+// ExplainDDLRollback is to be incremented whenever EXPLAIN (DDL, ROLLBACK) is
+// run.
+var ExplainDDLRollback = telemetry.GetCounterOnce("sql.plan.explain-ddl-rollback")
+
 // ExplainOptVerboseUseCounter is to be incremented whenever
 // EXPLAIN (OPT, VERBOSE) is run.
 var ExplainOptVerboseUseCounter = telemetry.GetCounterOnce("sql.plan.explain-opt-verbose")
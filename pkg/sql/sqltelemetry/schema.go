@@ -172,6 +172,13 @@ var CreateUnloggedTableCounter = telemetry.GetCounterOnce("sql.schema.create_unl
 // view is refreshed.
 var SchemaRefreshMaterializedView = telemetry.GetCounterOnce("sql.schema.refresh_materialized_view")
 
+// DeclarativeSchemaChangerFallbackCounter is to be incremented every time the
+// declarative schema changer falls back to the legacy schema changer because
+// it does not support a node of the statement, identified by its type.
+func DeclarativeSchemaChangerFallbackCounter(nodeType string) telemetry.Counter {
+	return telemetry.GetCounter("sql.schema.declarative_fallback." + nodeType)
+}
+
 // SchemaChangeErrorCounter is to be incremented for different types
 // of errors.
 func SchemaChangeErrorCounter(typ string) telemetry.Counter {
@@ -1858,6 +1858,25 @@ var varGen = map[string]sessionVar{
 		GlobalDefault: globalFalse,
 	},
 
+	// CockroachDB extension. Forwards the log entries with severity WARNING
+	// or higher emitted while executing the statements of the session to
+	// the client as notices.
+	`log_notices_enabled`: {
+		GetStringVal: makePostgresBoolGetStringValFn(`log_notices_enabled`),
+		Set: func(_ context.Context, m sessionDataMutator, s string) error {
+			b, err := paramparse.ParseBoolVar("log_notices_enabled", s)
+			if err != nil {
+				return err
+			}
+			m.SetLogNoticesEnabled(b)
+			return nil
+		},
+		Get: func(evalCtx *extendedEvalContext, _ *kv.Txn) (string, error) {
+			return formatBoolAsPostgresSetting(evalCtx.SessionData().LogNoticesEnabled), nil
+		},
+		GlobalDefault: globalFalse,
+	},
+
 	// CockroachDB extension.
 	`join_reader_ordering_strategy_batch_size`: {
 		Set: func(_ context.Context, m sessionDataMutator, s string) error {
@@ -17,6 +17,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/config/zonepb"
 	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/server"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/systemschema"
 	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
@@ -24,9 +25,12 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
 )
 
 func TestValidSetShowZones(t *testing.T) {
@@ -279,6 +283,55 @@ func TestZoneInheritField(t *testing.T) {
 	sqlutils.VerifyZoneConfigForTarget(t, sqlDB, "TABLE t", newTableRow)
 }
 
+// TestDroppedIndexGCTTLSurvivesZoneConfigChange checks that the GC TTL
+// override of an index dropped by the declarative schema changer keeps
+// applying to the index data when the zone config of the table is
+// changed, even though the index is no longer in the table descriptor.
+func TestDroppedIndexGCTTLSurvivesZoneConfigChange(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	params, _ := tests.CreateTestServerParams()
+	s, db, _ := serverutils.StartServer(t, params)
+	defer s.Stopper().Stop(ctx)
+
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `SET CLUSTER SETTING sql.schema.dropped_index_gc_ttl = '10m'`)
+	sqlDB.Exec(t, `SET CLUSTER SETTING sql.defaults.use_declarative_schema_changer = 'unsafe_always'`)
+	sqlDB.Exec(t, `CREATE DATABASE d; CREATE TABLE d.t (k INT PRIMARY KEY, v INT, INDEX v_idx (v))`)
+	tableID := sqlutils.QueryTableID(t, db, "d", "public", "t")
+	var indexID uint32
+	sqlDB.QueryRow(t, `SELECT index_id FROM crdb_internal.table_indexes
+WHERE descriptor_id = $1 AND index_name = 'v_idx'`, tableID).Scan(&indexID)
+	sqlDB.Exec(t, `DROP INDEX d.t@v_idx`)
+
+	getZone := func() zonepb.ZoneConfig {
+		var val []byte
+		sqlDB.QueryRow(t, `SELECT config FROM system.zones WHERE id = $1`, tableID).Scan(&val)
+		var zone zonepb.ZoneConfig
+		require.NoError(t, protoutil.Unmarshal(val, &zone))
+		return zone
+	}
+	requireIndexGCTTL := func(zone zonepb.ZoneConfig) {
+		subzone := zone.GetSubzoneExact(indexID, "")
+		require.NotNil(t, subzone)
+		require.Equal(t, &zonepb.GCPolicy{TTLSeconds: 600}, subzone.Config.GC)
+		require.Equal(t, []zonepb.SubzoneSpan{{
+			Key:          roachpb.Key(encoding.EncodeUvarintAscending(nil, uint64(indexID))),
+			SubzoneIndex: int32(len(zone.Subzones) - 1),
+		}}, zone.SubzoneSpans)
+	}
+	requireIndexGCTTL(getZone())
+
+	// Changing the zone config of the table regenerates the subzone spans
+	// from the table descriptor.
+	sqlDB.Exec(t, `ALTER TABLE d.t CONFIGURE ZONE USING gc.ttlseconds = 100000`)
+	zone := getZone()
+	require.Equal(t, &zonepb.GCPolicy{TTLSeconds: 100000}, zone.GC)
+	requireIndexGCTTL(zone)
+}
+
 func TestInvalidSetShowZones(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -63,6 +63,7 @@ var requireConstFmt = map[string]bool{
 	"github.com/cockroachdb/cockroach/pkg/util/log.logfDepth":              true,
 	"github.com/cockroachdb/cockroach/pkg/util/log.shoutfDepth":            true,
 	"github.com/cockroachdb/cockroach/pkg/util/log.logfDepthInternal":      true,
+	"github.com/cockroachdb/cockroach/pkg/util/log.logfEveryDepth":         true,
 	"github.com/cockroachdb/cockroach/pkg/util/log.makeStartLine":          true,
 
 	"github.com/cockroachdb/cockroach/pkg/util/log/logcrash.ReportOrPanic": true,
@@ -153,6 +154,8 @@ func init() {
 		requireConstFmt["github.com/cockroachdb/cockroach/pkg/util/log.V"+capsev+"f"] = true
 		// log.InfofDepth, log.WarningfDepth, etc.
 		requireConstFmt["github.com/cockroachdb/cockroach/pkg/util/log."+capsev+"fDepth"] = true
+		// log.InfofEvery, log.WarningfEvery, etc.
+		requireConstFmt["github.com/cockroachdb/cockroach/pkg/util/log."+capsev+"fEvery"] = true
 		// log.Info, log.Warning, etc.
 		requireConstMsg["github.com/cockroachdb/cockroach/pkg/util/log."+capsev] = true
 
@@ -164,6 +167,8 @@ func init() {
 			requireConstFmt["(github.com/cockroachdb/cockroach/pkg/util/log.logger"+capch+").V"+capsev+"f"] = true
 			// log.Ops.InfofDepth, log.Ops.WarningfDepth, etc.
 			requireConstFmt["(github.com/cockroachdb/cockroach/pkg/util/log.logger"+capch+")."+capsev+"fDepth"] = true
+			// log.Ops.InfofEvery, log.Ops.WarningfEvery, etc.
+			requireConstFmt["(github.com/cockroachdb/cockroach/pkg/util/log.logger"+capch+")."+capsev+"fEvery"] = true
 			// log.Ops.Info, logs.Ops.Warning, etc.
 			requireConstMsg["(github.com/cockroachdb/cockroach/pkg/util/log.logger"+capch+")."+capsev] = true
 		}
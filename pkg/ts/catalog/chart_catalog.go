@@ -124,6 +124,335 @@ var charts = []sectionDescription{
 			},
 		},
 	},
+	{
+		Organization: [][]string{{Process, "Logging", "Messages"}},
+		Charts: []chartDescription{
+			{
+				Title: "DEV",
+				Metrics: []string{
+					"log.messages.dev.debug2.count",
+					"log.messages.dev.debug1.count",
+					"log.messages.dev.info.count",
+					"log.messages.dev.warning.count",
+					"log.messages.dev.error.count",
+					"log.messages.dev.fatal.count",
+				},
+			},
+			{
+				Title: "OPS",
+				Metrics: []string{
+					"log.messages.ops.debug2.count",
+					"log.messages.ops.debug1.count",
+					"log.messages.ops.info.count",
+					"log.messages.ops.warning.count",
+					"log.messages.ops.error.count",
+					"log.messages.ops.fatal.count",
+				},
+			},
+			{
+				Title: "HEALTH",
+				Metrics: []string{
+					"log.messages.health.debug2.count",
+					"log.messages.health.debug1.count",
+					"log.messages.health.info.count",
+					"log.messages.health.warning.count",
+					"log.messages.health.error.count",
+					"log.messages.health.fatal.count",
+				},
+			},
+			{
+				Title: "STORAGE",
+				Metrics: []string{
+					"log.messages.storage.debug2.count",
+					"log.messages.storage.debug1.count",
+					"log.messages.storage.info.count",
+					"log.messages.storage.warning.count",
+					"log.messages.storage.error.count",
+					"log.messages.storage.fatal.count",
+				},
+			},
+			{
+				Title: "SESSIONS",
+				Metrics: []string{
+					"log.messages.sessions.debug2.count",
+					"log.messages.sessions.debug1.count",
+					"log.messages.sessions.info.count",
+					"log.messages.sessions.warning.count",
+					"log.messages.sessions.error.count",
+					"log.messages.sessions.fatal.count",
+				},
+			},
+			{
+				Title: "SQL_SCHEMA",
+				Metrics: []string{
+					"log.messages.sql_schema.debug2.count",
+					"log.messages.sql_schema.debug1.count",
+					"log.messages.sql_schema.info.count",
+					"log.messages.sql_schema.warning.count",
+					"log.messages.sql_schema.error.count",
+					"log.messages.sql_schema.fatal.count",
+				},
+			},
+			{
+				Title: "USER_ADMIN",
+				Metrics: []string{
+					"log.messages.user_admin.debug2.count",
+					"log.messages.user_admin.debug1.count",
+					"log.messages.user_admin.info.count",
+					"log.messages.user_admin.warning.count",
+					"log.messages.user_admin.error.count",
+					"log.messages.user_admin.fatal.count",
+				},
+			},
+			{
+				Title: "PRIVILEGES",
+				Metrics: []string{
+					"log.messages.privileges.debug2.count",
+					"log.messages.privileges.debug1.count",
+					"log.messages.privileges.info.count",
+					"log.messages.privileges.warning.count",
+					"log.messages.privileges.error.count",
+					"log.messages.privileges.fatal.count",
+				},
+			},
+			{
+				Title: "SENSITIVE_ACCESS",
+				Metrics: []string{
+					"log.messages.sensitive_access.debug2.count",
+					"log.messages.sensitive_access.debug1.count",
+					"log.messages.sensitive_access.info.count",
+					"log.messages.sensitive_access.warning.count",
+					"log.messages.sensitive_access.error.count",
+					"log.messages.sensitive_access.fatal.count",
+				},
+			},
+			{
+				Title: "SQL_EXEC",
+				Metrics: []string{
+					"log.messages.sql_exec.debug2.count",
+					"log.messages.sql_exec.debug1.count",
+					"log.messages.sql_exec.info.count",
+					"log.messages.sql_exec.warning.count",
+					"log.messages.sql_exec.error.count",
+					"log.messages.sql_exec.fatal.count",
+				},
+			},
+			{
+				Title: "SQL_PERF",
+				Metrics: []string{
+					"log.messages.sql_perf.debug2.count",
+					"log.messages.sql_perf.debug1.count",
+					"log.messages.sql_perf.info.count",
+					"log.messages.sql_perf.warning.count",
+					"log.messages.sql_perf.error.count",
+					"log.messages.sql_perf.fatal.count",
+				},
+			},
+			{
+				Title: "SQL_INTERNAL_PERF",
+				Metrics: []string{
+					"log.messages.sql_internal_perf.debug2.count",
+					"log.messages.sql_internal_perf.debug1.count",
+					"log.messages.sql_internal_perf.info.count",
+					"log.messages.sql_internal_perf.warning.count",
+					"log.messages.sql_internal_perf.error.count",
+					"log.messages.sql_internal_perf.fatal.count",
+				},
+			},
+			{
+				Title: "TELEMETRY",
+				Metrics: []string{
+					"log.messages.telemetry.debug2.count",
+					"log.messages.telemetry.debug1.count",
+					"log.messages.telemetry.info.count",
+					"log.messages.telemetry.warning.count",
+					"log.messages.telemetry.error.count",
+					"log.messages.telemetry.fatal.count",
+				},
+			},
+			{
+				Title: "SCHEMA_CHANGES",
+				Metrics: []string{
+					"log.messages.schema_changes.debug2.count",
+					"log.messages.schema_changes.debug1.count",
+					"log.messages.schema_changes.info.count",
+					"log.messages.schema_changes.warning.count",
+					"log.messages.schema_changes.error.count",
+					"log.messages.schema_changes.fatal.count",
+				},
+			},
+		},
+	},
+	{
+		Organization: [][]string{{Process, "Logging", "Redaction"}},
+		Charts: []chartDescription{
+			{
+				Title: "DEV",
+				Metrics: []string{
+					"log.dev.entries.redactable",
+					"log.dev.entries.unredactable",
+				},
+			},
+			{
+				Title: "OPS",
+				Metrics: []string{
+					"log.ops.entries.redactable",
+					"log.ops.entries.unredactable",
+				},
+			},
+			{
+				Title: "HEALTH",
+				Metrics: []string{
+					"log.health.entries.redactable",
+					"log.health.entries.unredactable",
+				},
+			},
+			{
+				Title: "STORAGE",
+				Metrics: []string{
+					"log.storage.entries.redactable",
+					"log.storage.entries.unredactable",
+				},
+			},
+			{
+				Title: "SESSIONS",
+				Metrics: []string{
+					"log.sessions.entries.redactable",
+					"log.sessions.entries.unredactable",
+				},
+			},
+			{
+				Title: "SQL_SCHEMA",
+				Metrics: []string{
+					"log.sql_schema.entries.redactable",
+					"log.sql_schema.entries.unredactable",
+				},
+			},
+			{
+				Title: "USER_ADMIN",
+				Metrics: []string{
+					"log.user_admin.entries.redactable",
+					"log.user_admin.entries.unredactable",
+				},
+			},
+			{
+				Title: "PRIVILEGES",
+				Metrics: []string{
+					"log.privileges.entries.redactable",
+					"log.privileges.entries.unredactable",
+				},
+			},
+			{
+				Title: "SENSITIVE_ACCESS",
+				Metrics: []string{
+					"log.sensitive_access.entries.redactable",
+					"log.sensitive_access.entries.unredactable",
+				},
+			},
+			{
+				Title: "SQL_EXEC",
+				Metrics: []string{
+					"log.sql_exec.entries.redactable",
+					"log.sql_exec.entries.unredactable",
+				},
+			},
+			{
+				Title: "SQL_PERF",
+				Metrics: []string{
+					"log.sql_perf.entries.redactable",
+					"log.sql_perf.entries.unredactable",
+				},
+			},
+			{
+				Title: "SQL_INTERNAL_PERF",
+				Metrics: []string{
+					"log.sql_internal_perf.entries.redactable",
+					"log.sql_internal_perf.entries.unredactable",
+				},
+			},
+			{
+				Title: "TELEMETRY",
+				Metrics: []string{
+					"log.telemetry.entries.redactable",
+					"log.telemetry.entries.unredactable",
+				},
+			},
+			{
+				Title: "SCHEMA_CHANGES",
+				Metrics: []string{
+					"log.schema_changes.entries.redactable",
+					"log.schema_changes.entries.unredactable",
+				},
+			},
+		},
+	},
+	{
+		Organization: [][]string{{Process, "Logging", "Sinks"}},
+		Charts: []chartDescription{
+			{
+				Title: "Delivery Failures",
+				Metrics: []string{
+					"log.sinks.file.delivery_failures",
+					"log.sinks.fluent.delivery_failures",
+					"log.sinks.http.delivery_failures",
+					"log.sinks.syslog.delivery_failures",
+					"log.sinks.journald.delivery_failures",
+					"log.sinks.eventlog.delivery_failures",
+				},
+			},
+			{
+				Title: "Dropped Entries",
+				Metrics: []string{
+					"log.sinks.file.dropped_entries",
+					"log.sinks.fluent.dropped_entries",
+					"log.sinks.http.dropped_entries",
+					"log.sinks.syslog.dropped_entries",
+					"log.sinks.journald.dropped_entries",
+					"log.sinks.eventlog.dropped_entries",
+				},
+			},
+			{
+				Title: "Truncated Entries",
+				Metrics: []string{
+					"log.sinks.file.truncated_entries",
+					"log.sinks.fluent.truncated_entries",
+					"log.sinks.http.truncated_entries",
+					"log.sinks.syslog.truncated_entries",
+					"log.sinks.journald.truncated_entries",
+					"log.sinks.eventlog.truncated_entries",
+				},
+			},
+			{
+				Title: "Queued Entries",
+				Metrics: []string{
+					"log.sinks.file.queued_entries",
+					"log.sinks.fluent.queued_entries",
+					"log.sinks.http.queued_entries",
+					"log.sinks.syslog.queued_entries",
+					"log.sinks.journald.queued_entries",
+					"log.sinks.eventlog.queued_entries",
+				},
+			},
+			{
+				Title: "Queued Bytes",
+				Metrics: []string{
+					"log.sinks.file.queued_bytes",
+					"log.sinks.fluent.queued_bytes",
+					"log.sinks.http.queued_bytes",
+					"log.sinks.syslog.queued_bytes",
+					"log.sinks.journald.queued_bytes",
+					"log.sinks.eventlog.queued_bytes",
+				},
+			},
+			{
+				Title: "Counted Entries",
+				Metrics: []string{
+					"log.count_sinks.entries",
+					"log.count_sinks.overflow_entries",
+				},
+			},
+		},
+	},
 	{
 		Organization: [][]string{{Process, "Network"}},
 		Charts: []chartDescription{
@@ -2086,6 +2415,17 @@ var charts = []sectionDescription{
 				},
 				AxisLabel: "Schema Change Executions",
 			},
+			{
+				Title:       "Placement Decisions",
+				Downsampler: DescribeAggregator_MAX,
+				Aggregator:  DescribeAggregator_SUM,
+				Metrics: []string{
+					"sql.schema_changer.placement.deferred",
+					"sql.schema_changer.placement.fallback",
+					"sql.schema_changer.placement.preferred",
+				},
+				AxisLabel: "Schema Change Executions",
+			},
 		},
 	},
 	{
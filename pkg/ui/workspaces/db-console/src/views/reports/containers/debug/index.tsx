@@ -435,6 +435,11 @@ export default function Debug() {
             url="debug/logspy?count=100&amp;duration=10s&amp;grep=.&flatten=1&vmodule=*=2"
             note="debug/logspy?count=[count]&amp;duration=[duration]&amp;grep=[regexp]&amp;flatten=[0/1]&amp;vmodule=[vmodule]"
           />
+          <DebugTableLink
+            name="Logs (live tail)"
+            url="debug/logtail?flatten=1"
+            note="debug/logtail?channels=[channels]&amp;severity=[severity]&amp;grep=[regexp]&amp;flatten=[0/1]&amp;redact=[0/1]"
+          />
           <DebugTableLink
             name="VModule setting"
             url="debug/vmodule"
@@ -159,6 +159,18 @@ var upgrades = []upgrade.Upgrade{
 		NoPrecondition,
 		updateInvalidColumnIDsInSequenceBackReferences,
 	),
+	upgrade.NewTenantUpgrade(
+		"add the system.schema_change_element_statuses table",
+		toCV(clusterversion.SystemSchemaChangeElementStatusesTable),
+		NoPrecondition,
+		systemSchemaChangeElementStatusesTableMigration,
+	),
+	upgrade.NewTenantUpgrade(
+		"add the system.schema_change_throughput table",
+		toCV(clusterversion.SystemSchemaChangeThroughputTable),
+		NoPrecondition,
+		systemSchemaChangeThroughputTableMigration,
+	),
 }
 
 func init() {
@@ -0,0 +1,193 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/envutil"
+	"github.com/cockroachdb/errors"
+)
+
+// The headers and formats of AWS Signature Version 4, with which the
+// HTTP sinks configured with aws-sigv4 sign their requests. See
+// https://docs.aws.amazon.com/general/latest/gr/sigv4_signing.html.
+const (
+	awsSigV4Algorithm      = "AWS4-HMAC-SHA256"
+	awsDateHeader          = "X-Amz-Date"
+	awsSecurityTokenHeader = "X-Amz-Security-Token"
+	awsContentSHA256Header = "X-Amz-Content-Sha256"
+	awsTimeFormat          = "20060102T150405Z"
+	awsDateFormat          = "20060102"
+)
+
+// awsCredentials are the AWS credentials with which requests are
+// signed.
+type awsCredentials struct {
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+}
+
+// awsCredentialsFromEnv reads the AWS credentials from the standard
+// environment variables.
+func awsCredentialsFromEnv() (awsCredentials, error) {
+	var c awsCredentials
+	c.accessKeyID, _ = envutil.ExternalEnvString("AWS_ACCESS_KEY_ID", 1)
+	c.secretAccessKey, _ = envutil.ExternalEnvString("AWS_SECRET_ACCESS_KEY", 1)
+	c.sessionToken, _ = envutil.ExternalEnvString("AWS_SESSION_TOKEN", 1)
+	if c.accessKeyID == "" || c.secretAccessKey == "" {
+		return awsCredentials{}, errors.New(
+			"AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set to sign requests with aws-sigv4")
+	}
+	return c, nil
+}
+
+// awsSigner signs requests with AWS Signature Version 4.
+type awsSigner struct {
+	creds   awsCredentials
+	region  string
+	service string
+}
+
+// sign signs the request, whose body is the given payload, as of the
+// given time, and sets the Authorization header accordingly.
+func (s *awsSigner) sign(req *http.Request, payload []byte, now time.Time) {
+	now = now.UTC()
+	timestamp := now.Format(awsTimeFormat)
+	date := now.Format(awsDateFormat)
+	payloadHash := sha256.Sum256(payload)
+	hexPayloadHash := hex.EncodeToString(payloadHash[:])
+
+	req.Header.Set(awsDateHeader, timestamp)
+	headers := map[string]string{
+		"host":       req.Host,
+		"x-amz-date": timestamp,
+	}
+	if headers["host"] == "" {
+		headers["host"] = req.URL.Host
+	}
+	// S3 requires the hash of the payload to be sent along with the
+	// request.
+	if s.service == "s3" {
+		req.Header.Set(awsContentSHA256Header, hexPayloadHash)
+		headers["x-amz-content-sha256"] = hexPayloadHash
+	}
+	if s.creds.sessionToken != "" {
+		req.Header.Set(awsSecurityTokenHeader, s.creds.sessionToken)
+		headers["x-amz-security-token"] = s.creds.sessionToken
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&canonicalHeaders, "%s:%s\n", name, strings.TrimSpace(headers[name]))
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		s.canonicalURI(req),
+		awsCanonicalQuery(req),
+		canonicalHeaders.String(),
+		signedHeaders,
+		hexPayloadHash,
+	}, "\n")
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+	scope := strings.Join([]string{date, s.region, s.service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		awsSigV4Algorithm,
+		timestamp,
+		scope,
+		hex.EncodeToString(canonicalRequestHash[:]),
+	}, "\n")
+
+	key := []byte("AWS4" + s.creds.secretAccessKey)
+	for _, part := range []string{date, s.region, s.service, "aws4_request"} {
+		key = awsHMAC(key, part)
+	}
+	signature := hex.EncodeToString(awsHMAC(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		awsSigV4Algorithm, s.creds.accessKeyID, scope, signedHeaders, signature))
+}
+
+// canonicalURI returns the URI-encoded path of the request. The
+// segments of the path are encoded twice, except for S3.
+func (s *awsSigner) canonicalURI(req *http.Request) string {
+	path := req.URL.EscapedPath()
+	if path == "" {
+		return "/"
+	}
+	if s.service == "s3" {
+		return path
+	}
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = awsURIEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// awsCanonicalQuery returns the query string of the request, with the
+// parameters sorted by name and value.
+func awsCanonicalQuery(req *http.Request) string {
+	type param struct{ name, value string }
+	var params []param
+	for name, values := range req.URL.Query() {
+		for _, value := range values {
+			params = append(params, param{name: awsURIEscape(name), value: awsURIEscape(value)})
+		}
+	}
+	sort.Slice(params, func(i, j int) bool {
+		if params[i].name != params[j].name {
+			return params[i].name < params[j].name
+		}
+		return params[i].value < params[j].value
+	})
+	encoded := make([]string, len(params))
+	for i, p := range params {
+		encoded[i] = p.name + "=" + p.value
+	}
+	return strings.Join(encoded, "&")
+}
+
+// awsURIEscape encodes every byte of the string except for the
+// unreserved characters of RFC 3986.
+func awsURIEscape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func awsHMAC(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -0,0 +1,202 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/log/logconfig"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/redact"
+)
+
+// channelBudgets caps the volume of the entries emitted on each
+// channel, using one budget per channel which is accounted over
+// consecutive intervals. The budgets are configured in ApplyConfig().
+type channelBudgets struct {
+	// perChannel contains the budget of each channel. A nil value
+	// indicates that the channel has no budget.
+	perChannel [logpb.Channel_CHANNEL_MAX]*channelBudget
+}
+
+// channelBudget accounts the entries emitted on a channel against its
+// budget, and summarizes the entries suppressed once it is exceeded.
+type channelBudget struct {
+	ch     Channel
+	config *logconfig.Budget
+
+	// limit is the maximum size of the entries emitted in an interval.
+	limit int64
+
+	mu struct {
+		syncutil.Mutex
+
+		// start is the beginning of the current interval, in
+		// nanoseconds since the epoch.
+		start int64
+
+		// used is the size of the entries emitted in the current
+		// interval.
+		used int64
+
+		// suppressed counts the entries suppressed since the last
+		// summary, per severity.
+		suppressed map[Severity]uint64
+
+		// timer emits the summary of the suppressed entries. It is set
+		// when the first entry gets suppressed after the last summary,
+		// and fires at the end of the interval of that entry.
+		timer *time.Timer
+	}
+}
+
+// applyConfig configures the budgets from the logging configuration.
+// The configuration is expected to be validated already. The entries
+// suppressed under the previous configuration are summarized right
+// away.
+func (c *channelBudgets) applyConfig(config logconfig.BudgetConfig) {
+	for chi := range c.perChannel {
+		if b := c.perChannel[chi]; b != nil {
+			b.reportSuppressed()
+		}
+		c.perChannel[chi] = nil
+		bc := config[Channel(chi).String()]
+		if bc == nil {
+			continue
+		}
+		c.perChannel[chi] = &channelBudget{
+			ch:     Channel(chi),
+			config: bc,
+			limit:  int64(float64(bc.BytesPerSecond) * bc.Interval.Seconds()),
+		}
+	}
+}
+
+// describeAppliedConfig reports the configuration that was applied.
+func (c *channelBudgets) describeAppliedConfig() (res logconfig.BudgetConfig) {
+	for chi, b := range c.perChannel {
+		if b == nil {
+			continue
+		}
+		if res == nil {
+			res = make(logconfig.BudgetConfig)
+		}
+		res[Channel(chi).String()] = b.config
+	}
+	return res
+}
+
+// allow returns true if the given entry fits in the budget of its
+// channel, or if its severity is above the suppressed severities.
+func (c *channelBudgets) allow(entry *logEntry) bool {
+	if entry.ch < 0 || int(entry.ch) >= len(c.perChannel) {
+		return true
+	}
+	b := c.perChannel[entry.ch]
+	if b == nil {
+		return true
+	}
+	return b.allow(entry.sev, int64(len(entry.payload.message)+len(entry.stacks)), entry.ts)
+}
+
+// allow accounts an entry of the given severity and size emitted at
+// the given time, in nanoseconds since the epoch, and returns false if
+// it is suppressed.
+func (b *channelBudget) allow(sev Severity, size int64, now int64) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	interval := *b.config.Interval
+	if now-b.mu.start >= int64(interval) || now < b.mu.start {
+		b.mu.start = now
+		b.mu.used = 0
+	}
+	b.mu.used += size
+	if b.mu.used <= b.limit || sev > *b.config.Suppress || sev >= severity.FATAL {
+		return true
+	}
+	if b.mu.suppressed == nil {
+		b.mu.suppressed = make(map[Severity]uint64)
+	}
+	b.mu.suppressed[sev]++
+	if b.mu.timer == nil {
+		b.mu.timer = time.AfterFunc(time.Duration(b.mu.start+int64(interval)-now), b.reportSuppressed)
+	}
+	return false
+}
+
+// reportSuppressed emits a single entry summarizing the entries
+// suppressed since the last summary, if any. The summary is not
+// subject to the budget.
+func (b *channelBudget) reportSuppressed() {
+	b.mu.Lock()
+	suppressed := b.mu.suppressed
+	b.mu.suppressed = nil
+	if b.mu.timer != nil {
+		b.mu.timer.Stop()
+		b.mu.timer = nil
+	}
+	b.mu.Unlock()
+	if len(suppressed) == 0 {
+		return
+	}
+
+	ctx := context.Background()
+	entry := makeUnstructuredEntry(ctx, severity.WARNING, b.ch, 0, true, /* redactable */
+		"suppressed %s on %s in the last %s",
+		redact.Safe(formatSuppressedCounts(suppressed)), redact.Safe(b.ch), redact.Safe(*b.config.Interval))
+	logging.getLogger(b.ch).outputLogEntry(ctx, entry)
+}
+
+// formatSuppressedCounts describes the number of suppressed entries per
+// severity, from the highest severity to the lowest, e.g. "3 WARNING
+// entries, 12,345 INFO entries".
+func formatSuppressedCounts(suppressed map[Severity]uint64) string {
+	sevs := make([]Severity, 0, len(suppressed))
+	for sev := range suppressed {
+		sevs = append(sevs, sev)
+	}
+	sort.Slice(sevs, func(i, j int) bool { return sevs[i] > sevs[j] })
+	var buf strings.Builder
+	for i, sev := range sevs {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		n := suppressed[sev]
+		buf.WriteString(formatCount(n))
+		buf.WriteByte(' ')
+		buf.WriteString(sev.String())
+		if n == 1 {
+			buf.WriteString(" entry")
+		} else {
+			buf.WriteString(" entries")
+		}
+	}
+	return buf.String()
+}
+
+// formatCount formats n with thousands separators, e.g. "12,345".
+func formatCount(n uint64) string {
+	s := strconv.FormatUint(n, 10)
+	var buf strings.Builder
+	for i := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteByte(s[i])
+	}
+	return buf.String()
+}
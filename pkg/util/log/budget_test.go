@@ -0,0 +1,89 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log/channel"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logconfig"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChannelBudget(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer ScopeWithoutShowLogs(t).Close(t)
+	defer logging.budgets.applyConfig(nil)
+
+	ctx := context.Background()
+	defer capture()()
+
+	// A budget of 3600 bytes per interval, which fits 3 of the entries
+	// below. The interval is long enough for the summary not to be
+	// emitted by its timer during the test.
+	interval := time.Hour
+	suppress := severity.INFO
+	logging.budgets.applyConfig(logconfig.BudgetConfig{
+		"DEV": {BytesPerSecond: 1, Interval: &interval, Suppress: &suppress},
+	})
+
+	msg := strings.Repeat("x", 1000)
+	for i := 0; i < 10; i++ {
+		Infof(ctx, "noisy %s", msg)
+	}
+	// The entries above the suppressed severity are not suppressed.
+	Warningf(ctx, "important")
+	// The other channels are not affected.
+	Ops.Infof(ctx, "unrelated %s", msg)
+
+	cont := contents()
+	require.Equal(t, 3, strings.Count(cont, "noisy"))
+	require.Equal(t, 1, strings.Count(cont, "important"))
+	require.Equal(t, 1, strings.Count(cont, "unrelated"))
+	require.NotContains(t, cont, "suppressed")
+
+	// The suppressed entries are summarized in a single entry.
+	resetCaptured()
+	logging.budgets.perChannel[channel.DEV].reportSuppressed()
+	require.Equal(t, 1, strings.Count(contents(), "suppressed 7 INFO entries on DEV in the last 1h0m0s"))
+
+	// Once summarized, the suppressed entries are not reported again.
+	resetCaptured()
+	logging.budgets.perChannel[channel.DEV].reportSuppressed()
+	require.Empty(t, contents())
+
+	// The budget is replenished in the next interval.
+	b := logging.budgets.perChannel[channel.DEV]
+	now := b.mu.start + int64(interval)
+	require.True(t, b.allow(severity.INFO, 1000, now))
+	require.False(t, b.allow(severity.INFO, 3000, now))
+	b.reportSuppressed()
+}
+
+func TestFormatSuppressedCounts(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	require.Equal(t, "1 INFO entry", formatSuppressedCounts(map[Severity]uint64{
+		severity.INFO: 1,
+	}))
+	require.Equal(t, "3 WARNING entries, 12,345 INFO entries", formatSuppressedCounts(map[Severity]uint64{
+		severity.INFO:    12345,
+		severity.WARNING: 3,
+	}))
+	require.Equal(t, "1,234,567 INFO entries", formatSuppressedCounts(map[Severity]uint64{
+		severity.INFO: 1234567,
+	}))
+}
@@ -12,6 +12,8 @@ package log
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/cli/exit"
@@ -27,7 +29,8 @@ import (
 //
 // bufferedSink's output() method never blocks on the child (except when the
 // forceSync option is used). Instead, old messages are dropped if the buffer is
-// overflowing a configured limit.
+// overflowing a configured limit, unless blockOnOverflow is set, in which case
+// output() waits for the buffer to be flushed.
 //
 // Should an error occur in the child sink, it's forwarded to the provided
 // onAsyncFlushErr (unless forceSync is requested, in which case the error is
@@ -47,6 +50,13 @@ type bufferedSink struct {
 	// There's also sync flushes, which have the opportunity to deliver their
 	// errors to the caller, so those are not subject to this crash.
 	crashOnAsyncFlushFailure bool
+	// blockOnOverflow, if set, causes output() to wait until the buffer has
+	// been flushed instead of dropping old messages when a new message does
+	// not fit in the buffer.
+	blockOnOverflow bool
+	// health, if set, is where delivery failures and dropped messages are
+	// reported.
+	health *sinkHealth
 
 	// flushC is a channel on which requests to flush the buffer are sent to the
 	// runFlusher goroutine. Each request to flush comes with a channel (can be nil)
@@ -60,6 +70,13 @@ type bufferedSink struct {
 		// timer is set when a flushAsync() call is scheduled to happen in the
 		// future.
 		timer *time.Timer
+		// flushed is signaled every time the runFlusher goroutine takes the
+		// contents of the buffer, and when it terminates. It is used to wake
+		// up output() calls waiting for space in the buffer when
+		// blockOnOverflow is set.
+		flushed sync.Cond
+		// stopped is set when the runFlusher goroutine has terminated.
+		stopped bool
 	}
 }
 
@@ -119,6 +136,7 @@ func newBufferedSink(
 		crashOnAsyncFlushFailure: crashOnAsyncFlushErr,
 	}
 	sink.mu.buf.maxSizeBytes = maxBufferSize
+	sink.mu.flushed.L = &sink.mu.Mutex
 	return sink
 }
 
@@ -173,8 +191,20 @@ func (bs *bufferedSink) output(b []byte, opts sinkOutputOptions) error {
 	}
 
 	bs.mu.Lock()
+	if bs.blockOnOverflow {
+		// Wait until the message fits without dropping older messages.
+		for !bs.mu.stopped && bs.mu.buf.wouldDrop(uint64(msg.Len())) {
+			bs.flushAsyncLocked()
+			bs.mu.flushed.Wait()
+		}
+	}
 	// Append the message to the buffer.
-	if err := bs.mu.buf.appendMsg(msg, errC); err != nil {
+	dropped, err := bs.mu.buf.appendMsg(msg, errC)
+	if errors.Is(err, errMsgTooLarge) {
+		dropped++
+	}
+	bs.health.recordDrops(dropped)
+	if err != nil {
 		bs.mu.Unlock()
 		return err
 	}
@@ -225,6 +255,30 @@ func (bs *bufferedSink) flushAsyncLocked() {
 	}
 }
 
+// queued returns the number of messages currently in the buffer, and
+// their total size in bytes.
+func (bs *bufferedSink) queued() (msgs int, bytes uint64) {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	return len(bs.mu.buf.messages), bs.mu.buf.sizeBytes
+}
+
+// capacity returns the size limit of the buffer in bytes, or zero if
+// it is unbounded.
+func (bs *bufferedSink) capacity() uint64 {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	return bs.mu.buf.maxSizeBytes
+}
+
+// underPressure returns true if the buffer is bounded and more than half
+// full, i.e. if the flushes are not keeping up with the messages.
+func (bs *bufferedSink) underPressure() bool {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	return bs.mu.buf.maxSizeBytes != 0 && bs.mu.buf.size() > bs.mu.buf.maxSizeBytes/2
+}
+
 // exitCode returns the exit code to use if the logger decides
 // to terminate because of an error in output().
 func (bs *bufferedSink) exitCode() exit.Code {
@@ -237,6 +291,14 @@ func (bs *bufferedSink) exitCode() exit.Code {
 // TODO(knz): How does this interact with the runFlusher logic in log_flush.go?
 // See: https://github.com/cockroachdb/cockroach/issues/72458
 func (bs *bufferedSink) runFlusher(stopC <-chan struct{}) {
+	defer func() {
+		// Wake up the output() calls waiting for space in the buffer; they
+		// won't get any.
+		bs.mu.Lock()
+		bs.mu.stopped = true
+		bs.mu.flushed.Broadcast()
+		bs.mu.Unlock()
+	}()
 	buf := &bs.mu.buf
 	for {
 		done := false
@@ -248,6 +310,7 @@ func (bs *bufferedSink) runFlusher(stopC <-chan struct{}) {
 		}
 		bs.mu.Lock()
 		msg, errC := buf.flush()
+		bs.mu.flushed.Broadcast()
 		bs.mu.Unlock()
 		if msg == nil {
 			// Nothing to flush.
@@ -264,7 +327,15 @@ func (bs *bufferedSink) runFlusher(stopC <-chan struct{}) {
 		if errC != nil {
 			errC <- err
 		} else if err != nil {
-			Ops.Errorf(context.Background(), "logging error from %T: %v", bs.child, err)
+			bs.health.recordFailure(err)
+			if bs.blockOnOverflow {
+				// The logging call below could wait on this very sink, or on
+				// a logger whose outputMu is held by an output() call waiting
+				// on this sink. Report the error on stderr instead.
+				fmt.Fprintf(OrigStderr, "logging error from %T: %v\n", bs.child, err)
+			} else {
+				Ops.Errorf(context.Background(), "logging error from %T: %v", bs.child, err)
+			}
 			if bs.crashOnAsyncFlushFailure {
 				logging.mu.Lock()
 				f := logging.mu.exitOverride.f
@@ -308,9 +379,20 @@ func (b *msgBuf) size() uint64 {
 
 var errMsgTooLarge = errors.New("message dropped because it is too large")
 
+// wouldDrop returns true if appending a message of the given length would
+// cause older messages to be dropped.
+func (b *msgBuf) wouldDrop(msgLen uint64) bool {
+	// The +1 accounts for a trailing newline.
+	return b.maxSizeBytes > 0 && msgLen <= b.maxSizeBytes &&
+		len(b.messages) > 0 && b.size()+msgLen+1 > b.maxSizeBytes
+}
+
 // appendMsg appends msg to the buffer. If errC is not nil, then this channel
 // will be signaled when the buffer is flushed.
-func (b *msgBuf) appendMsg(msg *buffer, errC chan<- error) error {
+//
+// The number of older messages dropped to make room for msg is returned. If
+// msg itself is dropped because it does not fit, errMsgTooLarge is returned.
+func (b *msgBuf) appendMsg(msg *buffer, errC chan<- error) (dropped int, _ error) {
 	msgLen := uint64(msg.Len())
 
 	// Make room for the new message, potentially by dropping the oldest messages
@@ -318,12 +400,13 @@ func (b *msgBuf) appendMsg(msg *buffer, errC chan<- error) error {
 	if b.maxSizeBytes > 0 {
 		if msgLen > b.maxSizeBytes {
 			// This message will never fit.
-			return errMsgTooLarge
+			return 0, errMsgTooLarge
 		}
 
 		// The +1 accounts for a trailing newline.
 		for b.size()+msgLen+1 > b.maxSizeBytes {
 			b.dropFirstMsg()
+			dropped++
 		}
 	}
 
@@ -341,7 +424,7 @@ func (b *msgBuf) appendMsg(msg *buffer, errC chan<- error) error {
 		panic(errors.AssertionFailedf("unexpected errC already set"))
 	}
 	b.errC = errC
-	return nil
+	return dropped, nil
 }
 
 // flush resets b, returning its contents in concatenated form. If b is empty, a
@@ -386,8 +469,6 @@ func (b *msgBuf) concatMessages() *buffer {
 }
 
 func (b *msgBuf) dropFirstMsg() {
-	// TODO(knz): This needs to get reported somehow, see
-	// https://github.com/cockroachdb/cockroach/issues/72453
 	firstMsg := b.messages[0]
 	b.messages = b.messages[1:]
 	b.sizeBytes -= uint64(firstMsg.Len())
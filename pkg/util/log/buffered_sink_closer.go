@@ -38,10 +38,11 @@ type bufferedSinkCloser struct {
 	wg sync.WaitGroup
 	mu struct {
 		syncutil.Mutex
-		// sinkRegistry acts as a set and stores references to all bufferSink's
-		// registered with this bufferedSinkCloser instance. Only useful for debugging
+		// sinkRegistry stores references to all bufferSink's (and async
+		// file writers) registered with this bufferedSinkCloser instance,
+		// mapped to the sink they write to. Only useful for debugging
 		// purposes.
-		sinkRegistry map[*bufferedSink]struct{}
+		sinkRegistry map[interface{}]logSink
 	}
 }
 
@@ -50,7 +51,7 @@ func newBufferedSinkCloser() *bufferedSinkCloser {
 	closer := &bufferedSinkCloser{
 		stopC: make(chan struct{}),
 	}
-	closer.mu.sinkRegistry = make(map[*bufferedSink]struct{})
+	closer.mu.sinkRegistry = make(map[interface{}]logSink)
 	return closer
 }
 
@@ -65,33 +66,83 @@ func newBufferedSinkCloser() *bufferedSinkCloser {
 // needs to be called once the bufferedSink has shutdown.
 func (closer *bufferedSinkCloser) RegisterBufferedSink(
 	bs *bufferedSink,
+) (shutdown <-chan (struct{}), cleanup func()) {
+	return closer.register(bs, bs.child)
+}
+
+// registerAsyncFileWriter is like RegisterBufferedSink, for the
+// writer goroutine of a file sink configured with async-writes.
+func (closer *bufferedSinkCloser) registerAsyncFileWriter(
+	w *asyncFileWriter,
+) (shutdown <-chan (struct{}), cleanup func()) {
+	return closer.register(w, w.sink)
+}
+
+// registerSpoolSink is like RegisterBufferedSink, for the delivery
+// goroutine of a spoolSink.
+func (closer *bufferedSinkCloser) registerSpoolSink(
+	s *spoolSink,
+) (shutdown <-chan (struct{}), cleanup func()) {
+	return closer.register(s, s.child)
+}
+
+// registerFluentSink is like RegisterBufferedSink, for the flusher
+// goroutine of a fluentSink which uses the forward protocol.
+func (closer *bufferedSinkCloser) registerFluentSink(
+	s *fluentSink,
+) (shutdown <-chan (struct{}), cleanup func()) {
+	return closer.register(s, s)
+}
+
+// registerEventLogSink is like RegisterBufferedSink, for the goroutine
+// of an eventLogSink which closes its handle to the event log.
+func (closer *bufferedSinkCloser) registerEventLogSink(
+	s *eventLogSink,
+) (shutdown <-chan (struct{}), cleanup func()) {
+	return closer.register(s, s)
+}
+
+// register registers a goroutine-owning sink component with closer.
+// child is the sink reported in error messages.
+func (closer *bufferedSinkCloser) register(
+	key interface{}, child logSink,
 ) (shutdown <-chan (struct{}), cleanup func()) {
 	closer.mu.Lock()
 	defer closer.mu.Unlock()
 
-	if _, ok := closer.mu.sinkRegistry[bs]; ok {
-		panic(errors.AssertionFailedf("buffered log sink registered more than once within log.bufferedSinkCloser: %T", bs.child))
+	if _, ok := closer.mu.sinkRegistry[key]; ok {
+		panic(errors.AssertionFailedf("buffered log sink registered more than once within log.bufferedSinkCloser: %T", child))
 	}
 
-	closer.mu.sinkRegistry[bs] = struct{}{}
+	closer.mu.sinkRegistry[key] = child
 	closer.wg.Add(1)
-	return closer.stopC, func() { closer.bufferedSinkDone(bs) }
+	return closer.stopC, func() { closer.sinkDone(key) }
 }
 
 // bufferedSinkDone notifies the bufferedSinkCloser that one of the buffered
 // log sinks registered via RegisterBufferedSink has finished processing
 // & has terminated.
 func (closer *bufferedSinkCloser) bufferedSinkDone(bs *bufferedSink) {
+	closer.sinkDone(bs)
+}
+
+// sinkDone is the implementation of the cleanup functions returned
+// by register.
+func (closer *bufferedSinkCloser) sinkDone(key interface{}) {
 	closer.mu.Lock()
 	defer closer.mu.Unlock()
 	// If we don't have the sink in the registry, then the sink is not accounted for
 	// in the WaitGroup. Warn and return early - to signal the WaitGroup could prematurely
 	// end the shutdown sequence of a different bufferSink that is registered.
-	if _, ok := closer.mu.sinkRegistry[bs]; !ok {
+	child, ok := closer.mu.sinkRegistry[key]
+	if !ok {
+		if bs, isBuffered := key.(*bufferedSink); isBuffered {
+			child = bs.child
+		}
 		panic(errors.AssertionFailedf(
-			"log shutdown sequence has detected an unregistered log sink: %T\n", bs.child))
+			"log shutdown sequence has detected an unregistered log sink: %T\n", child))
 	}
-	delete(closer.mu.sinkRegistry, bs)
+	delete(closer.mu.sinkRegistry, key)
 	closer.wg.Done()
 }
 
@@ -116,8 +167,8 @@ func (closer *bufferedSinkCloser) Close(timeout time.Duration) error {
 		closer.mu.Lock()
 		defer closer.mu.Unlock()
 		leakedSinks := make([]string, 0, len(closer.mu.sinkRegistry))
-		for bs := range closer.mu.sinkRegistry {
-			leakedSinks = append(leakedSinks, fmt.Sprintf("%T", bs.child))
+		for _, child := range closer.mu.sinkRegistry {
+			leakedSinks = append(leakedSinks, fmt.Sprintf("%T", child))
 		}
 		return errors.Newf(
 			"log shutdown sequence has detected a deadlock & has timed out. Hanging log sink(s): %v",
@@ -383,3 +383,149 @@ func (m sinkOutputOptionsMatcher) String() string {
 	}
 	return strings.Join(acc, ", ")
 }
+
+// Test that the messages dropped because of an overflow are accounted for.
+func TestBufferedSinkReportsDrops(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	closer := newBufferedSinkCloser()
+	defer func() { require.NoError(t, closer.Close(defaultCloserTimeout)) }()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mock := NewMockLogSink(ctrl)
+	sink := newBufferedSink(mock, noMaxStaleness, noSizeTrigger, 10 /* maxBufferSize */, false /* crashOnAsyncFlushErr */)
+	sink.health = &sinkHealth{}
+	sink.Start(closer)
+
+	// Each message takes 3 bytes in the buffer, including the trailing
+	// newline, so only the last 3 messages fit.
+	for i := 0; i < 5; i++ {
+		require.NoError(t, sink.output([]byte(fmt.Sprintf("a%d", i)), sinkOutputOptions{}))
+	}
+	require.Equal(t, int64(2), atomic.LoadInt64(&sink.health.droppedEntries))
+	msgs, bytes := sink.queued()
+	require.Equal(t, 3, msgs)
+	require.Equal(t, uint64(6), bytes)
+
+	// A message that can never fit is dropped too.
+	require.Equal(t, errMsgTooLarge,
+		sink.output([]byte("this message is too large"), sinkOutputOptions{}))
+	require.Equal(t, int64(3), atomic.LoadInt64(&sink.health.droppedEntries))
+
+	// Flush the buffer; this drops one more message to make room.
+	mock.EXPECT().output(gomock.Eq([]byte("a3\na4\nx")), gomock.Any())
+	require.NoError(t, sink.output([]byte("x"), sinkOutputOptions{forceSync: true}))
+	require.Equal(t, int64(4), atomic.LoadInt64(&sink.health.droppedEntries))
+}
+
+// Test that the sink reports pressure once its buffer is more than half full,
+// and that an unbounded buffer never does.
+func TestBufferedSinkUnderPressure(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	sink, mock, cleanup := getMockBufferedSync(t, noMaxStaleness, noSizeTrigger, 12 /* maxBufferSize */)
+	defer cleanup()
+
+	// Each message takes 3 bytes in the buffer, including the trailing
+	// newline.
+	require.False(t, sink.underPressure())
+	for i := 0; i < 2; i++ {
+		require.NoError(t, sink.output([]byte(fmt.Sprintf("a%d", i)), sinkOutputOptions{}))
+	}
+	require.False(t, sink.underPressure())
+	require.NoError(t, sink.output([]byte("a2"), sinkOutputOptions{}))
+	require.True(t, sink.underPressure())
+
+	// Flushing relieves the pressure.
+	mock.EXPECT().output(gomock.Eq([]byte("a0\na1\na2\na3")), gomock.Any())
+	require.NoError(t, sink.output([]byte("a3"), sinkOutputOptions{forceSync: true}))
+	require.False(t, sink.underPressure())
+
+	unbounded, unboundedMock, cleanupUnbounded := getMockBufferedSync(t, noMaxStaleness, noSizeTrigger, noMaxBufferSize)
+	defer cleanupUnbounded()
+	unboundedMock.EXPECT().output(gomock.Any(), gomock.Any()).AnyTimes()
+	for i := 0; i < 10; i++ {
+		require.NoError(t, unbounded.output([]byte(fmt.Sprintf("a%d", i)), sinkOutputOptions{}))
+	}
+	require.False(t, unbounded.underPressure())
+}
+
+// Test that output() waits for the buffer to be flushed instead of
+// dropping messages when blockOnOverflow is set.
+func TestBufferedSinkBlockOnOverflow(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	closer := newBufferedSinkCloser()
+	defer func() { require.NoError(t, closer.Close(defaultCloserTimeout)) }()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mock := NewMockLogSink(ctrl)
+	bufferMaxSize := uint64(20)
+	triggerSize := uint64(10)
+	sink := newBufferedSink(mock, noMaxStaleness, triggerSize, bufferMaxSize, false /* crashOnAsyncFlushErr */)
+	sink.blockOnOverflow = true
+	sink.health = &sinkHealth{}
+	sink.Start(closer)
+
+	// Block the first flush, as in TestBufferedSinkBlockedFlush.
+	firstFlushSem := make(chan struct{})
+	largeMsg := bytes.Repeat([]byte("a"), int(triggerSize))
+	mock.EXPECT().
+		output(gomock.Any(), gomock.Any()).
+		Do(func([]byte, sinkOutputOptions) {
+			firstFlushSem <- struct{}{}
+			<-firstFlushSem
+		})
+	require.NoError(t, sink.output(largeMsg, sinkOutputOptions{}))
+	select {
+	case <-firstFlushSem:
+	case <-time.After(10 * time.Second):
+		t.Fatal("expected flush didn't happen")
+	}
+
+	var flushed [][]byte
+	var flushedMu sync.Mutex
+	mock.EXPECT().
+		output(gomock.Any(), gomock.Any()).
+		Do(func(logs []byte, _ sinkOutputOptions) {
+			flushedMu.Lock()
+			defer flushedMu.Unlock()
+			flushed = append(flushed, append([]byte(nil), logs...))
+		}).
+		AnyTimes()
+
+	// Fill the buffer, then send more messages from a separate goroutine.
+	// These must wait until the first flush completes.
+	for i := 0; i < 6; i++ {
+		require.NoError(t, sink.output([]byte(fmt.Sprintf("a%d", i)), sinkOutputOptions{}))
+	}
+	doneC := make(chan error)
+	go func() {
+		for i := 0; i < 4; i++ {
+			if err := sink.output([]byte(fmt.Sprintf("b%d", i)), sinkOutputOptions{}); err != nil {
+				doneC <- err
+				return
+			}
+		}
+		doneC <- nil
+	}()
+	select {
+	case err := <-doneC:
+		t.Fatalf("unexpected output() completion while the flush is in-flight: %v", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	// Unblock the first flush. The waiting messages can now go through.
+	firstFlushSem <- struct{}{}
+	select {
+	case err := <-doneC:
+		require.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("output() still blocked after the flush completed")
+	}
+	require.NoError(t, sink.output([]byte("c"), sinkOutputOptions{forceSync: true}))
+
+	// No message was dropped.
+	require.Equal(t, int64(0), atomic.LoadInt64(&sink.health.droppedEntries))
+	flushedMu.Lock()
+	defer flushedMu.Unlock()
+	require.Equal(t, "a0\na1\na2\na3\na4\na5\nb0\nb1\nb2\nb3\nc",
+		string(bytes.Join(flushed, []byte("\n"))))
+}
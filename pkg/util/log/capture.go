@@ -0,0 +1,363 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"sync/atomic"
+
+	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+)
+
+// defaultCaptureMaxBytes is the maximum size of the entries retained
+// by a log capture, when not specified in the CaptureOptions.
+const defaultCaptureMaxBytes = 1 << 20
+
+// CaptureOptions configures a log capture. See CaptureForContext.
+type CaptureOptions struct {
+	// Threshold is the minimum severity of the captured entries.
+	// Defaults to INFO.
+	Threshold Severity
+
+	// MaxBytes is the maximum size of the formatted entries retained by
+	// the capture. When it is exceeded, the oldest entries are
+	// discarded. Defaults to 1MiB.
+	MaxBytes int64
+
+	// Dir, if non-empty, is the directory where the captured entries are
+	// stored, in temporary files removed when the capture is closed.
+	// Otherwise, the captured entries are stored in memory.
+	Dir string
+
+	// EditMode describes how the sensitive data in the captured entries
+	// is edited. Defaults to WithMarkedSensitiveData, so that the
+	// captured entries can be redacted later on when they are reported.
+	EditMode EditSensitiveData
+}
+
+// Capture accumulates the log entries emitted under a context
+// returned by CaptureForContext, across all channels, regardless of
+// the filtering configured on the log sinks. This enables background
+// jobs to attach their own log excerpt to their progress or error
+// payloads.
+type Capture struct {
+	// parent is the capture of the enclosing context, if any. The
+	// entries captured by a capture are also captured by its parent.
+	parent *Capture
+
+	threshold Severity
+	editor    redactEditor
+
+	mu struct {
+		syncutil.Mutex
+		closed bool
+		// err is the first error encountered while storing an entry,
+		// after which no further entries are stored.
+		err error
+		// dropped is the number of entries discarded because of the size
+		// limit.
+		dropped int64
+		buf     captureBuffer
+	}
+}
+
+// ctxCaptureKey is an empty type for the handle associated with the
+// Capture value (see context.Value).
+type ctxCaptureKey struct{}
+
+// activeCaptures is the number of open captures. It is used to avoid
+// looking up the capture in the context of every log entry when no
+// capture is open.
+var activeCaptures int32
+
+// CaptureForContext starts capturing the log entries emitted under the
+// returned context, or any context derived from it. Captures can be
+// nested, in which case the entries are captured by all the enclosing
+// captures.
+//
+// The returned Capture must be closed once the entries are no longer
+// needed.
+func CaptureForContext(ctx context.Context, opts CaptureOptions) (context.Context, *Capture, error) {
+	if opts.Threshold == severity.UNKNOWN {
+		opts.Threshold = severity.INFO
+	}
+	if opts.MaxBytes <= 0 {
+		opts.MaxBytes = defaultCaptureMaxBytes
+	}
+	if opts.EditMode == 0 {
+		opts.EditMode = WithMarkedSensitiveData
+	}
+	c := &Capture{
+		parent:    captureFromContext(ctx),
+		threshold: opts.Threshold,
+		editor:    getEditor(opts.EditMode),
+	}
+	if opts.Dir != "" {
+		buf, err := newFileCaptureBuffer(opts.Dir, opts.MaxBytes)
+		if err != nil {
+			return ctx, nil, err
+		}
+		c.mu.buf = buf
+	} else {
+		c.mu.buf = &memCaptureBuffer{maxBytes: opts.MaxBytes}
+	}
+	atomic.AddInt32(&activeCaptures, 1)
+	return context.WithValue(ctx, ctxCaptureKey{}, c), c, nil
+}
+
+// captureFromContext returns the innermost capture for the context, if
+// any.
+func captureFromContext(ctx context.Context) *Capture {
+	if atomic.LoadInt32(&activeCaptures) == 0 {
+		return nil
+	}
+	c, _ := ctx.Value(ctxCaptureKey{}).(*Capture)
+	return c
+}
+
+// capturesSeverity returns whether entries at the given severity are
+// captured for the context.
+func capturesSeverity(ctx context.Context, sev Severity) bool {
+	for c := captureFromContext(ctx); c != nil; c = c.parent {
+		if sev >= c.threshold {
+			return true
+		}
+	}
+	return false
+}
+
+// maybeCapture stores the entry in the captures of the context, if
+// any.
+func maybeCapture(ctx context.Context, entry logEntry) {
+	for c := captureFromContext(ctx); c != nil; c = c.parent {
+		if entry.sev >= c.threshold {
+			c.record(entry)
+		}
+	}
+}
+
+func (c *Capture) record(entry logEntry) {
+	entry.payload = maybeRedactEntry(entry.payload, c.editor)
+	buf := formatCrdbV2{}.formatEntry(entry)
+	defer putBuffer(buf)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.mu.closed || c.mu.err != nil {
+		return
+	}
+	dropped, err := c.mu.buf.write(buf.Bytes())
+	c.mu.dropped += int64(dropped)
+	c.mu.err = err
+}
+
+// Excerpt returns the captured entries, oldest first, in the crdb-v2
+// format.
+func (c *Capture) Excerpt() ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.mu.closed {
+		return nil, errors.AssertionFailedf("log capture is closed")
+	}
+	if c.mu.err != nil {
+		return nil, c.mu.err
+	}
+	return c.mu.buf.contents()
+}
+
+// Entries returns the captured entries, oldest first.
+func (c *Capture) Entries() ([]logpb.Entry, error) {
+	excerpt, err := c.Excerpt()
+	if err != nil {
+		return nil, err
+	}
+	// The entries have already been edited when they were captured.
+	d, err := NewEntryDecoderWithFormat(bytes.NewReader(excerpt), WithMarkedSensitiveData, "crdb-v2")
+	if err != nil {
+		return nil, err
+	}
+	var entries []logpb.Entry
+	for {
+		var e logpb.Entry
+		if err := d.Decode(&e); err != nil {
+			if err == io.EOF {
+				return entries, nil
+			}
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+}
+
+// Dropped returns the number of entries discarded because the captured
+// entries exceeded the maximum size.
+func (c *Capture) Dropped() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.mu.dropped
+}
+
+// Close stops the capture and releases the captured entries. The
+// entries emitted under the context of the capture after it is closed
+// are ignored.
+func (c *Capture) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.mu.closed {
+		return nil
+	}
+	c.mu.closed = true
+	atomic.AddInt32(&activeCaptures, -1)
+	return c.mu.buf.close()
+}
+
+// captureBuffer stores the formatted entries of a log capture.
+type captureBuffer interface {
+	// write appends an entry to the buffer, discarding the oldest entries
+	// as needed to respect the size limit. It returns the number of
+	// entries discarded.
+	write(b []byte) (dropped int, err error)
+	// contents returns the entries in the buffer, oldest first.
+	contents() ([]byte, error)
+	// close releases the resources of the buffer.
+	close() error
+}
+
+// memCaptureBuffer is a captureBuffer which stores the entries in memory.
+type memCaptureBuffer struct {
+	maxBytes int64
+	size     int64
+	entries  [][]byte
+}
+
+var _ captureBuffer = (*memCaptureBuffer)(nil)
+
+func (m *memCaptureBuffer) write(b []byte) (dropped int, _ error) {
+	if int64(len(b)) > m.maxBytes {
+		return 1, nil
+	}
+	m.entries = append(m.entries, append([]byte(nil), b...))
+	m.size += int64(len(b))
+	for m.size > m.maxBytes {
+		m.size -= int64(len(m.entries[0]))
+		m.entries[0] = nil
+		m.entries = m.entries[1:]
+		dropped++
+	}
+	return dropped, nil
+}
+
+func (m *memCaptureBuffer) contents() ([]byte, error) {
+	return bytes.Join(m.entries, nil), nil
+}
+
+func (m *memCaptureBuffer) close() error {
+	m.entries = nil
+	return nil
+}
+
+// fileCaptureBuffer is a captureBuffer which stores the entries in
+// temporary files. The entries are appended to the current file until
+// it reaches half the size limit, at which point the previous file is
+// removed and the current file becomes the previous one. This retains
+// at least half the size limit worth of the most recent entries.
+type fileCaptureBuffer struct {
+	dir      string
+	maxBytes int64
+
+	cur        *os.File
+	curSize    int64
+	curEntries int
+
+	// prevPath is the path of the previous file, if any.
+	prevPath    string
+	prevEntries int
+}
+
+var _ captureBuffer = (*fileCaptureBuffer)(nil)
+
+func newFileCaptureBuffer(dir string, maxBytes int64) (*fileCaptureBuffer, error) {
+	f := &fileCaptureBuffer{dir: dir, maxBytes: maxBytes}
+	if err := f.rotate(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// rotate starts a new current file.
+func (f *fileCaptureBuffer) rotate() error {
+	cur, err := os.CreateTemp(f.dir, "log-capture-*.log")
+	if err != nil {
+		return errors.Wrap(err, "creating log capture file")
+	}
+	if f.cur != nil {
+		if err := f.cur.Close(); err != nil {
+			_ = cur.Close()
+			_ = os.Remove(cur.Name())
+			return err
+		}
+		f.prevPath, f.prevEntries = f.cur.Name(), f.curEntries
+	}
+	f.cur, f.curSize, f.curEntries = cur, 0, 0
+	return nil
+}
+
+func (f *fileCaptureBuffer) write(b []byte) (dropped int, _ error) {
+	if int64(len(b)) > f.maxBytes/2 {
+		return 1, nil
+	}
+	if f.curSize+int64(len(b)) > f.maxBytes/2 {
+		if f.prevPath != "" {
+			if err := os.Remove(f.prevPath); err != nil {
+				return 0, err
+			}
+			dropped = f.prevEntries
+			f.prevPath, f.prevEntries = "", 0
+		}
+		if err := f.rotate(); err != nil {
+			return dropped, err
+		}
+	}
+	n, err := f.cur.Write(b)
+	f.curSize += int64(n)
+	f.curEntries++
+	return dropped, err
+}
+
+func (f *fileCaptureBuffer) contents() ([]byte, error) {
+	var buf bytes.Buffer
+	for _, path := range []string{f.prevPath, f.cur.Name()} {
+		if path == "" {
+			continue
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(b)
+	}
+	return buf.Bytes(), nil
+}
+
+func (f *fileCaptureBuffer) close() error {
+	err := f.cur.Close()
+	err = errors.CombineErrors(err, os.Remove(f.cur.Name()))
+	if f.prevPath != "" {
+		err = errors.CombineErrors(err, os.Remove(f.prevPath))
+	}
+	return err
+}
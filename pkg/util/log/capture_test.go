@@ -0,0 +1,117 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+	"github.com/cockroachdb/redact"
+	"github.com/stretchr/testify/require"
+)
+
+func capturedMessages(t *testing.T, c *Capture) []string {
+	t.Helper()
+	entries, err := c.Entries()
+	require.NoError(t, err)
+	var msgs []string
+	for _, e := range entries {
+		msgs = append(msgs, fmt.Sprintf("%s %s %s",
+			e.Severity, e.Channel, redact.RedactableString(e.Message).StripMarkers()))
+	}
+	return msgs
+}
+
+func TestCaptureForContext(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer ScopeWithoutShowLogs(t).Close(t)
+
+	ctx := context.Background()
+	captureCtx, c, err := CaptureForContext(ctx, CaptureOptions{})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, c.Close()) }()
+
+	// Entries are captured across channels, but only under the context
+	// of the capture.
+	Infof(captureCtx, "hello")
+	Ops.Warningf(captureCtx, "world")
+	Infof(ctx, "not captured")
+	// Entries below the threshold are not captured.
+	VEventf(captureCtx, 1, "not captured either")
+
+	// The entries of a nested capture are also captured by the enclosing
+	// capture.
+	nestedCtx, nested, err := CaptureForContext(captureCtx, CaptureOptions{
+		Threshold: severity.WARNING,
+	})
+	require.NoError(t, err)
+	Infof(nestedCtx, "nested info")
+	Errorf(nestedCtx, "nested error")
+	require.Equal(t, []string{"ERROR DEV nested error"}, capturedMessages(t, nested))
+	require.NoError(t, nested.Close())
+	_, err = nested.Excerpt()
+	require.Error(t, err)
+	Errorf(nestedCtx, "after close")
+
+	require.Equal(t, []string{
+		"INFO DEV hello",
+		"WARNING OPS world",
+		"INFO DEV nested info",
+		"ERROR DEV nested error",
+		"ERROR DEV after close",
+	}, capturedMessages(t, c))
+	require.Zero(t, c.Dropped())
+}
+
+func TestCaptureMaxBytes(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer ScopeWithoutShowLogs(t).Close(t)
+
+	for _, toFile := range []bool{false, true} {
+		t.Run(fmt.Sprint(toFile), func(t *testing.T) {
+			opts := CaptureOptions{MaxBytes: 4096}
+			if toFile {
+				opts.Dir = t.TempDir()
+			}
+			ctx, c, err := CaptureForContext(context.Background(), opts)
+			require.NoError(t, err)
+
+			const numEntries = 1000
+			for i := 0; i < numEntries; i++ {
+				Infof(ctx, "message %03d", i)
+			}
+			excerpt, err := c.Excerpt()
+			require.NoError(t, err)
+			require.LessOrEqual(t, len(excerpt), int(opts.MaxBytes))
+
+			// The most recent entries are retained.
+			msgs := capturedMessages(t, c)
+			require.NotEmpty(t, msgs)
+			require.Equal(t, numEntries, len(msgs)+int(c.Dropped()))
+			for i, m := range msgs {
+				require.True(t, strings.HasSuffix(m, fmt.Sprintf("message %03d", numEntries-len(msgs)+i)), m)
+			}
+
+			require.NoError(t, c.Close())
+			if toFile {
+				// The temporary files have been removed.
+				files, err := os.ReadDir(opts.Dir)
+				require.NoError(t, err)
+				require.Empty(t, files)
+			}
+		})
+	}
+}
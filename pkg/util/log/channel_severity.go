@@ -0,0 +1,127 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"strings"
+	"sync/atomic"
+
+	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+	"github.com/cockroachdb/errors"
+)
+
+// channelSeverityOverrides maintains the run-time overrides of the
+// severity thresholds, one per channel. The overrides are read
+// with atomic loads so that the check remains cheap on the logging
+// hot path.
+//
+// An override set to SEVERITY_UNKNOWN means that the thresholds
+// from the logging configuration apply.
+type channelSeverityOverrides struct {
+	sevPerChannel [logpb.Channel_CHANNEL_MAX]int32
+}
+
+func (o *channelSeverityOverrides) get(ch Channel) Severity {
+	return Severity(atomic.LoadInt32(&o.sevPerChannel[int(ch)]))
+}
+
+func (o *channelSeverityOverrides) set(ch Channel, sev Severity) {
+	atomic.StoreInt32(&o.sevPerChannel[int(ch)], int32(sev))
+}
+
+// SetChannelSeverity overrides the severity threshold of the given
+// channel on every sink that the channel is connected to, until the
+// override is reset. This makes it possible to e.g. temporarily make
+// a channel more verbose on a running node without changing the
+// logging configuration.
+//
+// Sinks where the channel is configured with severity NONE are not
+// affected. Passing severity.UNKNOWN removes the override.
+func SetChannelSeverity(ch Channel, sev Severity) error {
+	if ch < 0 || ch >= logpb.Channel_CHANNEL_MAX {
+		return errors.Newf("invalid channel: %d", ch)
+	}
+	if sev < severity.DEBUG2 || sev > severity.NONE {
+		return errors.Newf("invalid severity: %d", sev)
+	}
+	before := GetChannelSeverities()
+	logging.severityOverrides.set(ch, sev)
+	reportRuntimeConfigChange(ConfigSourceChannelSeverities, before, GetChannelSeverities())
+	return nil
+}
+
+// GetChannelSeverity returns the severity override for the given
+// channel, or severity.UNKNOWN if there is none.
+func GetChannelSeverity(ch Channel) Severity {
+	return logging.severityOverrides.get(ch)
+}
+
+// SetChannelSeverities replaces all the severity overrides using the
+// given specification, a comma-separated list of CHANNEL=SEVERITY
+// pairs, e.g. "SQL_EXEC=INFO,HEALTH=WARNING". Channels not mentioned
+// in the specification have their override removed; in particular, an
+// empty specification removes all overrides.
+func SetChannelSeverities(spec string) error {
+	overrides, err := ParseChannelSeverities(spec)
+	if err != nil {
+		return err
+	}
+	before := GetChannelSeverities()
+	for chi := 0; chi < int(logpb.Channel_CHANNEL_MAX); chi++ {
+		ch := Channel(chi)
+		logging.severityOverrides.set(ch, overrides[ch])
+	}
+	reportRuntimeConfigChange(ConfigSourceChannelSeverities, before, GetChannelSeverities())
+	return nil
+}
+
+// GetChannelSeverities returns the current severity overrides in the
+// format accepted by SetChannelSeverities.
+func GetChannelSeverities() string {
+	var parts []string
+	for chi := 0; chi < int(logpb.Channel_CHANNEL_MAX); chi++ {
+		ch := Channel(chi)
+		if sev := logging.severityOverrides.get(ch); sev.IsSet() {
+			parts = append(parts, ch.String()+"="+sev.String())
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// ParseChannelSeverities parses a severity override specification
+// in the format accepted by SetChannelSeverities.
+func ParseChannelSeverities(spec string) (map[Channel]Severity, error) {
+	overrides := make(map[Channel]Severity)
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return overrides, nil
+	}
+	for _, part := range strings.Split(spec, ",") {
+		kv := strings.Split(strings.TrimSpace(part), "=")
+		if len(kv) != 2 {
+			return nil, errors.Newf("syntax error: expect comma-separated list of CHANNEL=SEVERITY, found %q", part)
+		}
+		ch, err := ParseChannel(kv[0])
+		if err != nil {
+			return nil, err
+		}
+		sev, ok := logpb.SeverityByName(strings.TrimSpace(kv[1]))
+		if !ok || !sev.IsSet() {
+			return nil, errors.Newf("unknown severity: %q", kv[1])
+		}
+		if _, ok := overrides[ch]; ok {
+			return nil, errors.Newf("channel %s specified multiple times", ch)
+		}
+		overrides[ch] = sev
+	}
+	return overrides, nil
+}
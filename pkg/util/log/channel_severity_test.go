@@ -0,0 +1,93 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log/channel"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChannelSeverities(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testData := []struct {
+		spec     string
+		expected map[Channel]Severity
+		expErr   string
+	}{
+		{"", map[Channel]Severity{}, ""},
+		{"sql_exec=info", map[Channel]Severity{channel.SQL_EXEC: severity.INFO}, ""},
+		{" OPS = WARNING , HEALTH=error", map[Channel]Severity{
+			channel.OPS:    severity.WARNING,
+			channel.HEALTH: severity.ERROR,
+		}, ""},
+		{"OPS", nil, "syntax error"},
+		{"OPS=INFO=WARNING", nil, "syntax error"},
+		{"UNKNOWN_CHAN=INFO", nil, "unknown channel"},
+		{"CHANNEL_MAX=INFO", nil, "unknown channel"},
+		{"OPS=LOUD", nil, "unknown severity"},
+		{"OPS=UNKNOWN", nil, "unknown severity"},
+		{"OPS=INFO,ops=ERROR", nil, "specified multiple times"},
+	}
+
+	for _, tc := range testData {
+		t.Run(tc.spec, func(t *testing.T) {
+			res, err := ParseChannelSeverities(tc.spec)
+			if tc.expErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, res)
+		})
+	}
+}
+
+func TestChannelSeverityOverride(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer ScopeWithoutShowLogs(t).Close(t)
+	defer func() { require.NoError(t, SetChannelSeverities("")) }()
+
+	ctx := context.Background()
+	defer capture()()
+
+	// Raise the threshold of the DEV channel: INFO entries are
+	// now filtered out.
+	require.NoError(t, SetChannelSeverity(channel.DEV, severity.WARNING))
+	require.Equal(t, severity.WARNING, GetChannelSeverity(channel.DEV))
+	require.Equal(t, "DEV=WARNING", GetChannelSeverities())
+	Info(ctx, "filtered out")
+	Warning(ctx, "let through")
+	require.False(t, contains("filtered out", t))
+	require.True(t, contains("let through", t))
+
+	// Removing the override restores the configured threshold.
+	resetCaptured()
+	require.NoError(t, SetChannelSeverity(channel.DEV, severity.UNKNOWN))
+	require.Equal(t, "", GetChannelSeverities())
+	Info(ctx, "visible again")
+	require.True(t, contains("visible again", t))
+
+	// The same via the specification API.
+	resetCaptured()
+	require.NoError(t, SetChannelSeverities("DEV=ERROR"))
+	Warning(ctx, "also filtered out")
+	require.False(t, contains("also filtered out", t))
+	require.NoError(t, SetChannelSeverities(""))
+	Warning(ctx, "also visible again")
+	require.True(t, contains("also visible again", t))
+}
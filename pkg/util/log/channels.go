@@ -24,13 +24,28 @@ import (
 )
 
 //go:generate go run gen/main.go logpb/log.proto logging.md ../../../docs/generated/logging.md
+//go:generate go run gen/main.go logpb/log.proto log_channels.json ../../../docs/generated/log_channels.json
+//go:generate go run gen/main.go logpb/log.proto log_channels.ts ../../../docs/generated/log_channels.ts
 //go:generate go run gen/main.go logpb/log.proto severity.go severity/severity_generated.go
 //go:generate go run gen/main.go logpb/log.proto channel.go channel/channel_generated.go
 //go:generate go run gen/main.go logpb/log.proto log_channels.go log_channels_generated.go
+//go:generate go run gen/main.go logpb/log.proto log_metrics.go log_metrics_generated.go
+//go:generate go run gen/main.go logpb/log.proto zap_adapter.go logzap/zap_adapter_generated.go
+//go:generate go run gen/main.go logpb/log.proto test_log_capture.go test_log_capture_generated.go
 
 // Channel aliases a type.
 type Channel = logpb.Channel
 
+// ParseChannel returns the channel with the given name, which is
+// case-insensitive.
+func ParseChannel(name string) (Channel, error) {
+	chi, ok := logpb.Channel_value[strings.ToUpper(strings.TrimSpace(name))]
+	if !ok || Channel(chi) == logpb.Channel_CHANNEL_MAX {
+		return 0, errors.Newf("unknown channel: %q", name)
+	}
+	return Channel(chi), nil
+}
+
 // logfDepth emits a log entry on the specified channel at specified
 // severity.
 func logfDepth(
@@ -73,10 +88,11 @@ func logfDepthInternal(
 		})
 		defer t.Stop()
 
+		err := errors.NewWithDepthf(depth+1, "log.Fatal: "+format, args...)
 		if MaybeSendCrashReport != nil {
-			err := errors.NewWithDepthf(depth+1, "log.Fatal: "+format, args...)
 			MaybeSendCrashReport(ctx, err)
 		}
+		MaybeDeliverCrashBundle(ctx, err)
 		if ch != channel.OPS {
 			// Tell the OPS channel about this termination.
 			logfDepth(ctx, depth+1, severity.INFO, channel.OPS,
@@ -95,6 +111,14 @@ func logfDepthInternal(
 	}
 
 	logger := logging.getLogger(ch)
+	if sev < severity.INFO && !logger.acceptsSeverity(ch, sev) {
+		// The DEBUG severities are meant for verbose diagnostics which
+		// are filtered out by default. Avoid the cost of building the
+		// entry if it is not going to be output anywhere.
+		if _, _, ok := getSpanOrEventLog(ctx); !ok && !capturesSeverity(ctx, sev) {
+			return
+		}
+	}
 	entry := makeUnstructuredEntry(
 		ctx, sev, ch,
 		depth+1, true /* redactable */, format, args...)
@@ -103,7 +127,22 @@ func logfDepthInternal(
 		heapEntry := entry
 		eventInternal(sp, el, sev >= severity.ERROR, &heapEntry)
 	}
-	logger.outputLogEntry(entry)
+	maybeCapture(ctx, entry)
+	if !logging.rateLimiter.allow(sev, ch, entry.file, entry.line, entry.ts) {
+		// Too many entries from this call site recently. The entry
+		// was still reported to the trace and captures above, if any.
+		logger.recordRateLimited(&entry)
+		return
+	}
+	if !logging.budgets.allow(&entry) {
+		// The budget of the channel is exhausted. The entry will be
+		// accounted for in the summary of the suppressed entries.
+		return
+	}
+	// Only count the entries which made it past the rate limiter and
+	// budgets above.
+	countEntry(ch, sev)
+	logger.outputLogEntry(ctx, entry)
 }
 
 // shoutfDepth shouts to the specified channel.
@@ -45,10 +45,39 @@ type loggingT struct {
 	// interceptor contains the configured InterceptorFn callbacks, if any.
 	interceptor interceptorSink
 
+	// tail contains the active subscriptions created by Tail(), if any.
+	tail tailSink
+
+	// recent retains the last entries emitted on each channel. See
+	// RecentEntries().
+	recent recentSink
+
 	// vmoduleConfig maintains the configuration for the log.V and vmodule
 	// facilities.
 	vmoduleConfig vmoduleConfig
 
+	// severityOverrides maintains the run-time overrides of the
+	// per-channel severity thresholds. See SetChannelSeverity().
+	severityOverrides channelSeverityOverrides
+
+	// stackTraces determines which goroutine stacks are included in
+	// log entries, per severity.
+	stackTraces stackTraceConfig
+
+	// rateLimiter caps the rate of the entries emitted from each
+	// location in the source code. See the rate-limits configuration
+	// section.
+	rateLimiter rateLimiter
+
+	// budgets caps the volume of the low-severity entries emitted on
+	// each channel. See the budgets configuration section.
+	budgets channelBudgets
+
+	// redactionCoverage counts the entries emitted on each channel
+	// depending on whether they are redactable. See
+	// RedactionCoverage().
+	redactionCoverage redactionCoverage
+
 	// The common stderr sink.
 	stderrSink stderrSink
 	// The template for the stderr sink info. This is where the configuration
@@ -135,6 +164,15 @@ type sinkInfo struct {
 	// entries.
 	msgCount uint64
 
+	// lastEntryTS is the timestamp of the previous entry emitted to
+	// this sink, from which the delta reported by the timestamp options
+	// is computed. See entryDelta().
+	lastEntryTS int64
+
+	// timestamps, if set, are the rendering options of the timestamps
+	// of the entries.
+	timestamps *timestampOptions
+
 	// criticality indicates whether a failure to output some log
 	// entries should incur the process to terminate.
 	criticality bool
@@ -142,6 +180,78 @@ type sinkInfo struct {
 	// redact and redactable memorize the input configuration
 	// that was used to create the editor above.
 	redact, redactable bool
+
+	// ignoreSeverityOverrides, when set, indicates that the run-time
+	// severity overrides set via SetChannelSeverity() do not apply to
+	// this sink.
+	ignoreSeverityOverrides bool
+
+	// name is the name of the sink in the logging configuration. It is
+	// used by SinkHealth().
+	name string
+
+	// health tracks the delivery problems of this sink. It is nil for
+	// the stderr, interceptor and tail sinks.
+	health *sinkHealth
+
+	// processors are applied to the entries before they are formatted
+	// for this sink. processorNames are the names under which they were
+	// registered.
+	processors     []EntryProcessor
+	processorNames []string
+
+	// tenants, if not nil, is the set of tenant IDs whose entries are
+	// accepted by this sink. tenantFilter is the configuration it was
+	// derived from.
+	tenants      map[string]struct{}
+	tenantFilter []string
+
+	// maxEntrySize, if positive, is the maximum size of the messages of
+	// the entries. Longer messages are truncated.
+	maxEntrySize int
+
+	// goroutineTags, when set, adds the ID and the profiler labels of
+	// the emitting goroutine to the tags of the entries.
+	goroutineTags bool
+
+	// eventsOnly, when set, restricts this sink to the structured
+	// events. It is set for the sinks of the events section of the
+	// configuration.
+	eventsOnly bool
+
+	// excludedEvents, when set for a channel, removes the structured
+	// events of the channel from this sink. It is set for the regular
+	// sinks when the events section of the configuration is exclusive.
+	excludedEvents [logpb.Channel_CHANNEL_MAX]bool
+}
+
+// thresholdFor returns the severity threshold for the given channel,
+// taking into account the provided run-time override.
+func (l *sinkInfo) thresholdFor(ch logpb.Channel, override Severity) Severity {
+	threshold := l.threshold.get(ch)
+	if override.IsSet() && threshold != severity.NONE && !l.ignoreSeverityOverrides {
+		return override
+	}
+	return threshold
+}
+
+// acceptsTenant returns whether the entries emitted on behalf of the
+// given tenant are accepted by this sink.
+func (l *sinkInfo) acceptsTenant(tenantID string) bool {
+	if l.tenants == nil {
+		return true
+	}
+	_, ok := l.tenants[tenantID]
+	return ok
+}
+
+// acceptsEntryKind returns whether this sink accepts the structured,
+// respectively free-form, entries of the given channel.
+func (l *sinkInfo) acceptsEntryKind(ch logpb.Channel, structured bool) bool {
+	if structured {
+		return !l.excludedEvents[ch]
+	}
+	return !l.eventsOnly
 }
 
 type channelThresholds struct {
@@ -195,6 +305,19 @@ func (l *loggerT) getFileSink() *fileSink {
 	return nil
 }
 
+// acceptsSeverity returns whether any of the active sinks of the
+// logger outputs the entries at the given severity on the given
+// channel.
+func (l *loggerT) acceptsSeverity(ch Channel, sev Severity) bool {
+	sevOverride := logging.severityOverrides.get(ch)
+	for _, s := range l.sinkInfos {
+		if sev >= s.thresholdFor(ch, sevOverride) && s.sink.active() {
+			return true
+		}
+	}
+	return false
+}
+
 // FatalChan is closed when Fatal is called. This can be used to make
 // the process stop handling requests while the final log messages and
 // crash report are being written.
@@ -220,10 +343,11 @@ func (l *loggingT) signalFatalCh() {
 // outputLogEntry marshals a log entry proto into bytes, and writes
 // the data to the log files. If a trace location is set, stack traces
 // are added to the entry before marshaling.
-func (l *loggerT) outputLogEntry(entry logEntry) {
+func (l *loggerT) outputLogEntry(ctx context.Context, entry logEntry) {
 	// Mark the logger as active, so that further configuration changes
 	// are disabled. See IsActive() and its callers for details.
 	setActive()
+	logging.redactionCoverage.record(&entry)
 	var fatalTrigger chan struct{}
 	extraFlush := false
 	isFatal := entry.sev == severity.FATAL
@@ -232,12 +356,7 @@ func (l *loggerT) outputLogEntry(entry logEntry) {
 		extraFlush = true
 		logging.signalFatalCh()
 
-		switch traceback {
-		case tracebackSingle:
-			entry.stacks = getStacks(false)
-		case tracebackAll:
-			entry.stacks = getStacks(true)
-		}
+		entry.stacks = l.getStacksForEntry(entry.sev)
 
 		for _, s := range l.sinkInfos {
 			entry.stacks = s.sink.attachHints(entry.stacks)
@@ -283,6 +402,10 @@ func (l *loggerT) outputLogEntry(entry logEntry) {
 			exitFunc(exit.FatalError(), nil)
 			close(exitCalled)
 		}()
+	} else if entry.stacks == nil && entry.sev >= severity.WARNING {
+		// Non-fatal entries only include stacks if the configuration
+		// asks for it. See the stack-traces configuration section.
+		entry.stacks = l.getStacksForEntry(entry.sev)
 	}
 
 	// The following buffers contain the formatted entry before it enters the sink.
@@ -297,20 +420,50 @@ func (l *loggerT) outputLogEntry(entry logEntry) {
 	// We only do the work if the sink is active and the filtering does
 	// not eliminate the event.
 	someSinkActive := false
+	sevOverride := logging.severityOverrides.get(entry.ch)
 	for i, s := range l.sinkInfos {
-		if entry.sev < s.threshold.get(entry.ch) || !s.sink.active() {
+		if entry.sev < s.thresholdFor(entry.ch, sevOverride) || !s.sink.active() ||
+			!s.acceptsTenant(entry.tenantID) || !s.acceptsEntryKind(entry.ch, entry.structured) {
+			continue
+		}
+		// Run the processors configured for this sink, if any.
+		editedEntry, ok := s.processEntry(ctx, entry)
+		if !ok {
 			continue
 		}
-		editedEntry := entry
+		if s.goroutineTags {
+			editedEntry.payload.tags = editedEntry.payload.tags.withGoroutineTags(
+				ctx, editedEntry.gid, editedEntry.payload.redactable)
+		}
 
 		// Add a counter. This is important for e.g. the SQL audit logs.
 		// Note: whether the counter is displayed or not depends on
 		// the formatter.
 		editedEntry.counter = atomic.AddUint64(&s.msgCount, 1)
 
+		// Add the timestamp options, if any, and the time elapsed since
+		// the previous entry if requested.
+		if s.timestamps != nil {
+			editedEntry.timestamps = s.timestamps
+			if s.timestamps.delta {
+				editedEntry.delta = s.entryDelta(editedEntry.ts)
+			}
+		}
+
 		// Process the redaction spec.
 		editedEntry.payload = maybeRedactEntry(editedEntry.payload, s.editor)
 
+		// Truncate oversized messages. This is done after redaction, so
+		// that the size of the messages is bounded as emitted.
+		if s.maxEntrySize > 0 {
+			var truncated bool
+			editedEntry.payload, truncated = truncateMessage(
+				editedEntry.payload, editedEntry.structured, s.maxEntrySize)
+			if truncated {
+				s.health.recordTruncation()
+			}
+		}
+
 		// Format the entry for this sink.
 		bufs.b[i] = s.formatter.formatEntry(editedEntry)
 		someSinkActive = true
@@ -334,6 +487,11 @@ func (l *loggerT) outputLogEntry(entry logEntry) {
 				continue
 			}
 			if err := s.sink.output(bufs.b[i].Bytes(), sinkOutputOptions{extraFlush: extraFlush, forceSync: isFatal}); err != nil {
+				if !errors.Is(err, errMsgTooLarge) {
+					// Dropped entries are accounted for by the buffering
+					// wrapper.
+					s.health.recordFailure(err)
+				}
 				if !s.criticality {
 					// An error on this sink is not critical. Just report
 					// the error and move on.
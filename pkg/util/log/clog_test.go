@@ -19,6 +19,7 @@ import (
 	"context"
 	"fmt"
 	stdLog "log"
+	"math"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -311,6 +312,84 @@ func TestVmoduleGlob(t *testing.T) {
 	}
 }
 
+// Test that a channel-specific vmodule only enables the logs of that
+// channel.
+func TestVmoduleForChannel(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer ScopeWithoutShowLogs(t).Close(t)
+
+	defer capture()()
+
+	ctx := context.Background()
+	require.NoError(t, SetVModuleForChannel(channel.OPS, "clog_*=2"))
+	defer func() { require.NoError(t, SetVModuleForChannel(channel.OPS, "")) }()
+	require.Equal(t, "clog_*=2", GetVModuleForChannel(channel.OPS))
+	require.Equal(t, "", GetVModuleForChannel(channel.DEV))
+	require.Equal(t, "", GetVModule())
+
+	Ops.VInfof(ctx, 2, "ops enabled")
+	Ops.VInfof(ctx, 3, "ops disabled")
+	Dev.VInfof(ctx, 2, "dev disabled")
+	VInfof(ctx, 2, "dev disabled too")
+	require.False(t, V(2))
+	require.True(t, contains("ops enabled", t))
+	require.False(t, contains("ops disabled", t))
+	require.False(t, contains("dev disabled", t))
+
+	// The vmodule configuration common to all channels still applies.
+	resetCaptured()
+	require.NoError(t, SetVModule("clog_test=3"))
+	defer func() { require.NoError(t, SetVModule("")) }()
+	Ops.VInfof(ctx, 3, "ops enabled again")
+	Dev.VInfof(ctx, 3, "dev enabled")
+	require.True(t, contains("ops enabled again", t))
+	require.True(t, contains("dev enabled", t))
+
+	require.Error(t, SetVModuleForChannel(channel.OPS, "clog_test=x"))
+	require.Equal(t, "clog_*=2", GetVModuleForChannel(channel.OPS))
+}
+
+// Test that a tag-specific vmodule only enables the logs under a context
+// carrying the tag.
+func TestVmoduleForTags(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer ScopeWithoutShowLogs(t).Close(t)
+
+	defer capture()()
+
+	require.NoError(t, SetVModuleForTags("r:53=2,job:123=1"))
+	defer func() { require.NoError(t, SetVModuleForTags("")) }()
+	require.Equal(t, "r:53=2,job:123=1", GetVModuleForTags())
+
+	ctx := context.Background()
+	ctx53 := logtags.AddTag(ctx, "r", "53/1:/Table/5{3-4}")
+	ctx530 := logtags.AddTag(ctx, "r", "530/1:/Table/5{30-31}")
+	ctxJob := logtags.AddTag(logtags.AddTag(ctx, "n", 1), "job", 123)
+
+	VEventf(ctx53, 2, "range enabled")
+	Ops.VInfof(ctx53, 2, "ops enabled")
+	VEventf(ctx53, 3, "range disabled")
+	VEventf(ctx530, 2, "other range disabled")
+	VEventf(ctx, 2, "untagged disabled")
+	VEventf(ctxJob, 1, "job enabled")
+	VEventf(ctxJob, 2, "job disabled")
+	require.True(t, ExpensiveLogEnabled(ctx53, 2))
+	require.False(t, ExpensiveLogEnabled(ctx530, 2))
+	require.False(t, V(1))
+	require.True(t, contains("range enabled", t))
+	require.True(t, contains("ops enabled", t))
+	require.False(t, contains("range disabled", t))
+	require.False(t, contains("other range disabled", t))
+	require.False(t, contains("untagged disabled", t))
+	require.True(t, contains("job enabled", t))
+	require.False(t, contains("job disabled", t))
+
+	for _, invalid := range []string{"r=2", ":53=2", "r:=2", "r:53", "r:53=x", "r:53=-1"} {
+		require.Error(t, SetVModuleForTags(invalid), invalid)
+	}
+	require.Equal(t, "r:53=2,job:123=1", GetVModuleForTags())
+}
+
 func TestListLogFiles(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer ScopeWithoutShowLogs(t).Close(t)
@@ -588,6 +667,64 @@ func TestFatalStacktraceStderr(t *testing.T) {
 	}
 }
 
+func TestStackTracesPerSeverity(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer ScopeWithoutShowLogs(t).Close(t)
+	defer logging.stackTraces.applyConfig(logging.stackTraces.describeAppliedConfig())
+
+	ctx := context.Background()
+	defer capture()()
+
+	// By default, non-fatal entries do not include stacks.
+	Errorf(ctx, "no stack")
+	if cont := contents(); strings.Count(cont, "goroutine ") > 0 {
+		t.Fatalf("unexpected stack trace:\n%s", cont)
+	}
+
+	bt := true
+	single, all := logconfig.StackTraceSingle, logconfig.StackTraceAll
+	logging.stackTraces.applyConfig(logconfig.StackTraceConfig{
+		Error:                     &single,
+		Fatal:                     &all,
+		AllGoroutinesSeparateFile: &bt,
+	})
+
+	resetCaptured()
+	Warningf(ctx, "still no stack")
+	if cont := contents(); strings.Count(cont, "goroutine ") > 0 {
+		t.Fatalf("unexpected stack trace:\n%s", cont)
+	}
+
+	resetCaptured()
+	Errorf(ctx, "single stack")
+	cont := contents()
+	if strings.Count(cont, "goroutine ") != 1 || !strings.Contains(cont, "clog_test") {
+		t.Fatalf("expected stack trace of one goroutine:\n%s", cont)
+	}
+
+	// The stacks of all goroutines go to a separate file, referenced
+	// from the fatal entry.
+	resetCaptured()
+	SetExitFunc(false /* hideStack */, func(exit.Code) {})
+	Fatalf(ctx, "all stacks")
+	cont = contents()
+	if strings.Count(cont, "goroutine ") != 1 {
+		t.Fatalf("expected stack trace of one goroutine:\n%s", cont)
+	}
+	re := regexp.MustCompile(`stacks of all goroutines written to: (\S+)`)
+	m := re.FindStringSubmatch(cont)
+	if m == nil {
+		t.Fatalf("expected reference to stacks file:\n%s", cont)
+	}
+	stacks, err := os.ReadFile(m[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(string(stacks), "goroutine ") < 2 {
+		t.Fatalf("stacks file contains less than two goroutines:\n%s", stacks)
+	}
+}
+
 func TestFd2Capture(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	s := ScopeWithoutShowLogs(t)
@@ -650,6 +787,44 @@ func TestFileSeverityFilter(t *testing.T) {
 	}
 }
 
+func TestDebugSeverityFilter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer ScopeWithoutShowLogs(t).Close(t)
+
+	var debugFileSinkInfo *sinkInfo
+	for _, si := range debugLog.sinkInfos {
+		if _, ok := si.sink.(*fileSink); ok {
+			debugFileSinkInfo = si
+		}
+	}
+
+	// With the default configuration, the DEBUG severities are
+	// filtered out.
+	Debug1f(context.Background(), "debug1 filtered")
+	Debug2f(context.Background(), "debug2 filtered")
+	Infof(context.Background(), "info shown")
+
+	debugFileSinkInfo.threshold.set(channel.DEV, severity.DEBUG1)
+	Debug1f(context.Background(), "debug1 shown")
+	Debug2f(context.Background(), "debug2 still filtered")
+
+	Flush()
+
+	debugFileSink := debugFileSinkInfo.sink.(*fileSink)
+	contents, err := os.ReadFile(debugFileSink.getFileName(t))
+	require.NoError(t, err)
+	require.Contains(t, string(contents), "info shown")
+	require.Contains(t, string(contents), "debug1 shown")
+	require.NotContains(t, string(contents), "filtered")
+
+	// The entry can be parsed back with its severity.
+	entries, err := FetchEntriesFromFiles(0, math.MaxInt64, 100,
+		regexp.MustCompile("debug1 shown"), WithFlattenedSensitiveData)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, severity.DEBUG1, entries[0].Severity)
+}
+
 type outOfSpaceWriter struct{}
 
 func (w *outOfSpaceWriter) Write([]byte) (int, error) {
@@ -806,3 +981,142 @@ func BenchmarkEventf_WithVerboseTraceSpan(b *testing.B) {
 		})
 	}
 }
+
+// testTenantIdentity is a ServerIdentificationPayload which only
+// reports a tenant ID.
+type testTenantIdentity string
+
+func (t testTenantIdentity) ServerIdentityString(key ServerIdentificationKey) string {
+	if key == IdentifyTenantID {
+		return string(t)
+	}
+	return ""
+}
+
+// TestTenantFilter checks that entries are routed to the sinks
+// according to the tenant ID reported by their context.
+func TestTenantFilter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	sc := ScopeWithoutShowLogs(t)
+	defer sc.Close(t)
+
+	config := logconfig.DefaultConfig()
+	config.Sinks.FileGroups = map[string]*logconfig.FileSinkConfig{
+		"tenant-5": {
+			FileDefaults: logconfig.FileDefaults{
+				CommonSinkConfig: logconfig.CommonSinkConfig{
+					TenantFilter: []string{"5"},
+				},
+			},
+			Channels: logconfig.SelectChannels(channel.DEV),
+		},
+		"system": {
+			FileDefaults: logconfig.FileDefaults{
+				CommonSinkConfig: logconfig.CommonSinkConfig{
+					TenantFilter: []string{logconfig.SystemTenantFilter},
+				},
+			},
+			Channels: logconfig.SelectChannels(channel.DEV),
+		},
+	}
+	config.CaptureFd2.Enable = false
+	require.NoError(t, config.Validate(&sc.logDir))
+	TestingResetActive()
+	cleanupFn, err := ApplyConfig(config)
+	require.NoError(t, err)
+	defer cleanupFn()
+
+	withTenant := func(tenantID string) context.Context {
+		return context.WithValue(context.Background(),
+			ServerIdentificationContextKey{}, testTenantIdentity(tenantID))
+	}
+	Infof(withTenant(""), "from the kv layer")
+	Infof(withTenant("1"), "from the system tenant")
+	Infof(withTenant("5"), "from tenant 5")
+	Infof(withTenant("6"), "from tenant 6")
+	Flush()
+
+	contents := func(name string) string {
+		for _, s := range debugLog.sinkInfos {
+			if s.name != name {
+				continue
+			}
+			b, err := os.ReadFile(s.sink.(*fileSink).getFileName(t))
+			require.NoError(t, err)
+			return string(b)
+		}
+		t.Fatalf("sink %q not found", name)
+		return ""
+	}
+	system := contents("system")
+	require.Contains(t, system, "from the kv layer")
+	require.Contains(t, system, "from the system tenant")
+	require.NotContains(t, system, "from tenant")
+	tenant5 := contents("tenant-5")
+	require.Contains(t, tenant5, "from tenant 5")
+	require.NotContains(t, tenant5, "from the")
+	require.NotContains(t, tenant5, "from tenant 6")
+
+	// The applied configuration reports the filter.
+	for _, s := range debugLog.sinkInfos {
+		if s.name == "tenant-5" {
+			require.Equal(t, []string{"5"}, s.describeAppliedConfig().TenantFilter)
+		}
+	}
+}
+
+// TestEventsSection checks that the structured events are routed to the
+// sinks of the events section, and removed from the regular sinks when
+// the section is exclusive.
+func TestEventsSection(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	sc := ScopeWithoutShowLogs(t)
+	defer sc.Close(t)
+
+	config := logconfig.DefaultConfig()
+	exclusive := true
+	config.Events = logconfig.EventsConfig{
+		Channels:  logconfig.ChannelList{Channels: []logpb.Channel{channel.DEV}},
+		Exclusive: &exclusive,
+		Sinks: logconfig.EventSinks{
+			FileGroups: map[string]*logconfig.FileSinkConfig{"events": {}},
+		},
+	}
+	config.CaptureFd2.Enable = false
+	require.NoError(t, config.Validate(&sc.logDir))
+	TestingResetActive()
+	cleanupFn, err := ApplyConfig(config)
+	require.NoError(t, err)
+	defer cleanupFn()
+
+	Infof(context.Background(), "a free-form entry")
+	StructuredEvent(context.Background(), &logpb.TestingStructuredLogEvent{
+		Channel: channel.DEV,
+		Event:   "a structured event",
+	})
+	Flush()
+
+	contents := func(name string) string {
+		for _, s := range debugLog.sinkInfos {
+			if s.name != name {
+				continue
+			}
+			b, err := os.ReadFile(s.sink.(*fileSink).getFileName(t))
+			require.NoError(t, err)
+			return string(b)
+		}
+		t.Fatalf("sink %q not found", name)
+		return ""
+	}
+	regular := contents("default")
+	require.Contains(t, regular, "a free-form entry")
+	require.NotContains(t, regular, "a structured event")
+	events := contents("events")
+	require.Contains(t, events, "a structured event")
+	require.NotContains(t, events, "a free-form entry")
+
+	// The applied configuration reports the events section.
+	require.Contains(t, DescribeAppliedConfig(), "exclusive: true")
+}
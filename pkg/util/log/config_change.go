@@ -0,0 +1,189 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/redact"
+)
+
+// ConfigChangeReporter is called when the logging configuration
+// changes, to report the change as a structured event. It is injected
+// by package server, since this package cannot depend on the event
+// definitions.
+//
+// The diff argument is a line-by-line diff between the previous and
+// the new configuration, where the configuration values are marked as
+// unsafe for reporting.
+var ConfigChangeReporter func(ctx context.Context, source string, diff redact.RedactableString)
+
+// Sources of logging configuration changes, reported alongside the
+// change.
+//
+// The configuration applied at process start-up is reported
+// separately, via ReportStartup().
+const (
+	// ConfigSourceVModule is a change to the vmodule configuration.
+	ConfigSourceVModule = "vmodule"
+	// ConfigSourceChannelSeverities is a change to the per-channel
+	// severity overrides.
+	ConfigSourceChannelSeverities = "channel_severities"
+	// ConfigSourceChannelVModule is a change to the vmodule
+	// configuration specific to a channel.
+	ConfigSourceChannelVModule = "channel_vmodule"
+	// ConfigSourceTagVModule is a change to the vmodule configuration
+	// specific to log tags.
+	ConfigSourceTagVModule = "tag_vmodule"
+)
+
+// ReportConfigChange reports a change to the logging configuration
+// via ConfigChangeReporter, if the configuration actually changed.
+// The before and after arguments describe the configuration in YAML
+// format, e.g. as produced by DescribeAppliedConfig().
+func ReportConfigChange(ctx context.Context, source string, before, after string) {
+	if ConfigChangeReporter == nil || before == after {
+		return
+	}
+	ConfigChangeReporter(ctx, source, diffConfig(before, after))
+}
+
+// reportRuntimeConfigChange is like ReportConfigChange for a
+// single-valued run-time configuration parameter.
+func reportRuntimeConfigChange(source string, before, after string) {
+	if ConfigChangeReporter == nil || before == after {
+		return
+	}
+	ReportConfigChange(context.Background(), source,
+		source+": "+before, source+": "+after)
+}
+
+// diffConfig computes a line-by-line diff between two descriptions of
+// the logging configuration. Removed lines are prefixed by "-" and
+// added lines by "+". Unchanged lines are omitted, except for the
+// enclosing YAML keys of the changed lines, which are included with
+// prefix " " for context.
+//
+// In each line of the form "key: value", the key is considered safe
+// for reporting and the value is marked as unsafe, since it may
+// contain e.g. network addresses or file paths. Lines that are neither
+// keys nor key-value pairs are marked as unsafe entirely.
+func diffConfig(before, after string) redact.RedactableString {
+	a := splitConfigLines(before)
+	b := splitConfigLines(after)
+
+	// Compute the longest common subsequence of lines. The
+	// configurations are small, so the quadratic cost is acceptable.
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var buf redact.StringBuilder
+	// shownContext is the list of enclosing keys most recently
+	// emitted for context.
+	var shownContext []string
+	emitChange := func(prefix redact.SafeString, lines []string, idx int) {
+		enclosing := enclosingConfigKeys(lines, idx)
+		for k, key := range enclosing {
+			if k < len(shownContext) && shownContext[k] == key {
+				continue
+			}
+			shownContext = append(shownContext[:k], key)
+			writeConfigLine(&buf, " ", key)
+		}
+		// The changed line also provides context for the lines that
+		// follow it.
+		shownContext = append(shownContext[:len(enclosing)], lines[idx])
+		writeConfigLine(&buf, prefix, lines[idx])
+	}
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			emitChange("-", a, i)
+			i++
+		default:
+			emitChange("+", b, j)
+			j++
+		}
+	}
+	return buf.RedactableString()
+}
+
+// writeConfigLine writes a line of the description of the logging
+// configuration, with the given prefix. In lines of the form
+// "key: value", the key is considered safe for reporting and the value
+// is marked as unsafe. Lines that are neither keys nor key-value pairs
+// are marked as unsafe entirely.
+func writeConfigLine(buf *redact.StringBuilder, prefix redact.SafeString, line string) {
+	buf.SafeString(prefix)
+	indent := configLineIndent(line)
+	buf.SafeString(redact.SafeString(line[:indent]))
+	line = line[indent:]
+	if colon := strings.Index(line, ": "); colon >= 0 {
+		buf.SafeString(redact.SafeString(line[:colon+2]))
+		if value := line[colon+2:]; value != "" {
+			buf.Print(value)
+		}
+	} else if strings.HasSuffix(line, ":") {
+		buf.SafeString(redact.SafeString(line))
+	} else {
+		buf.Print(line)
+	}
+	buf.SafeRune('\n')
+}
+
+// enclosingConfigKeys returns the lines that enclose the line at the
+// given index in an indented YAML description, from the outermost to
+// the innermost.
+func enclosingConfigKeys(lines []string, idx int) []string {
+	var res []string
+	indent := configLineIndent(lines[idx])
+	for k := idx - 1; k >= 0 && indent > 0; k-- {
+		if ind := configLineIndent(lines[k]); ind < indent {
+			res = append(res, lines[k])
+			indent = ind
+		}
+	}
+	// Reverse the list to start with the outermost key.
+	for l, r := 0, len(res)-1; l < r; l, r = l+1, r-1 {
+		res[l], res[r] = res[r], res[l]
+	}
+	return res
+}
+
+func configLineIndent(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+func splitConfigLines(s string) []string {
+	s = strings.TrimRight(s, "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
@@ -0,0 +1,86 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log/channel"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+	"github.com/cockroachdb/redact"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffConfig(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testData := []struct {
+		before, after string
+		expected      redact.RedactableString
+	}{
+		{"a: 1\n", "a: 1\n", ""},
+		{"", "a: 1\nb:\n  - x\n", "+a: ‹1›\n+b:\n+  ‹- x›\n"},
+		{"vmodule: ", "vmodule: foo=2", "-vmodule: \n+vmodule: ‹foo=2›\n"},
+		{
+			"sinks:\n  x:\n    filter: INFO\n    dir: /a\n  y:\n    filter: INFO\n",
+			"sinks:\n  x:\n    filter: WARNING\n    dir: /a\n  y:\n    filter: ERROR\n",
+			" sinks:\n   x:\n-    filter: ‹INFO›\n+    filter: ‹WARNING›\n" +
+				"   y:\n-    filter: ‹INFO›\n+    filter: ‹ERROR›\n",
+		},
+	}
+
+	for _, tc := range testData {
+		require.Equal(t, tc.expected, diffConfig(tc.before, tc.after))
+	}
+}
+
+func TestReportRuntimeConfigChange(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer ScopeWithoutShowLogs(t).Close(t)
+
+	type change struct {
+		source string
+		diff   redact.RedactableString
+	}
+	var changes []change
+	defer func(prev func(context.Context, string, redact.RedactableString)) {
+		ConfigChangeReporter = prev
+	}(ConfigChangeReporter)
+	ConfigChangeReporter = func(_ context.Context, source string, diff redact.RedactableString) {
+		changes = append(changes, change{source, diff})
+	}
+
+	defer func(prev string) { require.NoError(t, SetVModule(prev)) }(GetVModule())
+	require.NoError(t, SetVModule("foo=2"))
+	// Setting the same value again is not a change.
+	require.NoError(t, SetVModule("foo=2"))
+
+	require.NoError(t, SetChannelSeverity(channel.OPS, severity.WARNING))
+	require.NoError(t, SetChannelSeverities(""))
+
+	require.NoError(t, SetVModuleForChannel(channel.OPS, "bar=1"))
+	require.NoError(t, SetVModuleForChannel(channel.OPS, ""))
+
+	require.NoError(t, SetVModuleForTags("r:53=2"))
+	require.NoError(t, SetVModuleForTags(""))
+
+	require.Equal(t, []change{
+		{ConfigSourceVModule, "-vmodule: \n+vmodule: ‹foo=2›\n"},
+		{ConfigSourceChannelSeverities, "-channel_severities: \n+channel_severities: ‹OPS=WARNING›\n"},
+		{ConfigSourceChannelSeverities, "-channel_severities: ‹OPS=WARNING›\n+channel_severities: \n"},
+		{ConfigSourceChannelVModule, "-channel_vmodule: ‹OPS[]›\n+channel_vmodule: ‹OPS[bar=1]›\n"},
+		{ConfigSourceChannelVModule, "-channel_vmodule: ‹OPS[bar=1]›\n+channel_vmodule: ‹OPS[]›\n"},
+		{ConfigSourceTagVModule, "-tag_vmodule: \n+tag_vmodule: ‹r:53=2›\n"},
+		{ConfigSourceTagVModule, "-tag_vmodule: ‹r:53=2›\n+tag_vmodule: \n"},
+	}, changes)
+}
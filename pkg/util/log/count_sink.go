@@ -0,0 +1,193 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/cockroachdb/cockroach/pkg/cli/exit"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logconfig"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// countKeySeparator separates the label values in the keys produced by
+// the countFormatter.
+const countKeySeparator = '\x00'
+
+// countSink does not persist the log entries. Instead, it counts them
+// by key, where the key is the combination of label values extracted
+// from every entry by a countFormatter.
+//
+// The number of keys is bounded: once maxKeys keys have been seen, the
+// entries with new keys are only accounted for in overflow.
+type countSink struct {
+	labels  []logconfig.CountLabel
+	maxKeys int
+
+	// config is the configuration this sink was created with. It is
+	// used by DescribeAppliedConfig().
+	config *logconfig.CountSinkConfig
+
+	// overflow counts the entries whose key could not be tracked.
+	// Accessed atomically.
+	overflow int64
+
+	mu struct {
+		syncutil.Mutex
+		// counts maps the keys to their counts. The counts are
+		// referenced by pointer so that the hot path doesn't need to
+		// convert the key to a string.
+		counts map[string]*int64
+	}
+}
+
+func newCountSink(c logconfig.CountSinkConfig) *countSink {
+	s := &countSink{
+		labels:  c.Labels,
+		maxKeys: *c.MaxKeys,
+		config:  &c,
+	}
+	s.mu.counts = make(map[string]*int64)
+	return s
+}
+
+// active implements the logSink interface.
+func (l *countSink) active() bool { return true }
+
+// attachHints implements the logSink interface.
+func (l *countSink) attachHints(stacks []byte) []byte {
+	return stacks
+}
+
+// exitCode implements the logSink interface.
+func (l *countSink) exitCode() exit.Code {
+	return exit.UnspecifiedError()
+}
+
+// output implements the logSink interface.
+func (l *countSink) output(b []byte, _ sinkOutputOptions) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if n, ok := l.mu.counts[string(b)]; ok {
+		*n++
+		return nil
+	}
+	if len(l.mu.counts) >= l.maxKeys {
+		atomic.AddInt64(&l.overflow, 1)
+		return nil
+	}
+	n := int64(1)
+	l.mu.counts[string(b)] = &n
+	return nil
+}
+
+// countFormatter formats the entries as the keys by which they are
+// counted in a countSink: the values of the selected labels, separated
+// by countKeySeparator.
+type countFormatter struct {
+	labels []logconfig.CountLabel
+}
+
+// formatterName implements the logFormatter interface.
+func (countFormatter) formatterName() string { return "count" }
+
+// doc implements the logFormatter interface.
+func (countFormatter) doc() string { return "Internal format used by count sinks." }
+
+// contentType implements the logFormatter interface.
+func (countFormatter) contentType() string { return "text/plain" }
+
+// formatEntry implements the logFormatter interface.
+func (f countFormatter) formatEntry(entry logEntry) *buffer {
+	buf := getBuffer()
+	for i, l := range f.labels {
+		if i > 0 {
+			buf.WriteByte(countKeySeparator)
+		}
+		switch l {
+		case logconfig.CountLabelChannel:
+			buf.WriteString(entry.ch.String())
+		case logconfig.CountLabelSeverity:
+			buf.WriteString(entry.sev.String())
+		case logconfig.CountLabelFileLine:
+			buf.WriteString(entry.file)
+			buf.WriteByte(':')
+			buf.WriteString(strconv.Itoa(entry.line))
+		}
+	}
+	return buf
+}
+
+// CountSinkEntry is the number of log entries counted by a count sink
+// for one combination of label values.
+type CountSinkEntry struct {
+	// Values are the label values, in the order of the labels of the
+	// sink.
+	Values []string
+	// Count is the number of entries with these label values.
+	Count int64
+}
+
+// CountSinkInfo describes the state of one count sink, as reported by
+// CountSinks().
+type CountSinkInfo struct {
+	// Name is the name of the sink in the logging configuration.
+	Name string
+	// Labels are the labels by which the entries are counted.
+	Labels []logconfig.CountLabel
+	// Counts are the numbers of entries counted for each combination of
+	// label values, sorted by label values.
+	Counts []CountSinkEntry
+	// Overflow is the number of entries which were not counted in
+	// Counts, because the maximum number of combinations was reached.
+	Overflow int64
+}
+
+// CountSinks reports the counts maintained by the count sinks
+// configured via ApplyConfig().
+func CountSinks() []CountSinkInfo {
+	var res []CountSinkInfo
+	_ = logging.allSinkInfos.iter(func(l *sinkInfo) error {
+		s, ok := l.sink.(*countSink)
+		if !ok {
+			return nil
+		}
+		info := CountSinkInfo{
+			Name:     l.name,
+			Labels:   s.labels,
+			Overflow: atomic.LoadInt64(&s.overflow),
+		}
+		s.mu.Lock()
+		info.Counts = make([]CountSinkEntry, 0, len(s.mu.counts))
+		for k, n := range s.mu.counts {
+			info.Counts = append(info.Counts, CountSinkEntry{
+				Values: strings.Split(k, string(countKeySeparator)),
+				Count:  *n,
+			})
+		}
+		s.mu.Unlock()
+		sort.Slice(info.Counts, func(i, j int) bool {
+			a, b := info.Counts[i].Values, info.Counts[j].Values
+			for k := range a {
+				if a[k] != b[k] {
+					return a[k] < b[k]
+				}
+			}
+			return false
+		})
+		res = append(res, info)
+		return nil
+	})
+	return res
+}
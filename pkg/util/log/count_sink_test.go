@@ -0,0 +1,79 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log/channel"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logconfig"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	sc := ScopeWithoutShowLogs(t)
+	defer sc.Close(t)
+
+	maxKeys := 2
+	cfg := logconfig.DefaultConfig()
+	cfg.Sinks.CountSinks = map[string]*logconfig.CountSinkConfig{
+		"noisy": {
+			Channels: logconfig.SelectChannels(channel.OPS, channel.HEALTH),
+			MaxKeys:  &maxKeys,
+		},
+	}
+	require.NoError(t, cfg.Validate(&sc.logDir))
+
+	TestingResetActive()
+	cleanupCfg, err := ApplyConfig(cfg)
+	require.NoError(t, err)
+	defer cleanupCfg()
+
+	ctx := context.Background()
+	Ops.Infof(ctx, "hello")
+	Ops.Infof(ctx, "hello again")
+	Ops.Warningf(ctx, "careful")
+	// The third combination of label values overflows.
+	Health.Infof(ctx, "ok")
+	// Channels not selected by the sink are not counted.
+	Dev.Infof(ctx, "not counted")
+
+	require.Equal(t, []CountSinkInfo{{
+		Name:   "noisy",
+		Labels: []logconfig.CountLabel{logconfig.CountLabelChannel, logconfig.CountLabelSeverity},
+		Counts: []CountSinkEntry{
+			{Values: []string{"OPS", "INFO"}, Count: 2},
+			{Values: []string{"OPS", "WARNING"}, Count: 1},
+		},
+		Overflow: 1,
+	}}, CountSinks())
+}
+
+func TestCountFormatter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	entry := logEntry{
+		sev:  severity.ERROR,
+		ch:   channel.SQL_SCHEMA,
+		file: "util/log/count_sink_test.go",
+		line: 42,
+	}
+	f := countFormatter{labels: []logconfig.CountLabel{
+		logconfig.CountLabelFileLine, logconfig.CountLabelChannel, logconfig.CountLabelSeverity,
+	}}
+	buf := f.formatEntry(entry)
+	defer putBuffer(buf)
+	require.Equal(t, "util/log/count_sink_test.go:42\x00SQL_SCHEMA\x00ERROR", buf.String())
+}
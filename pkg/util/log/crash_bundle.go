@@ -0,0 +1,186 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/build"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/redact"
+)
+
+// CrashBundleDeliveryTimeout bounds the time spent delivering a crash
+// bundle to all its destinations. It is shorter than
+// ExitTimeoutOnFatalLog, so that a slow destination does not prevent
+// the fatal error from being logged.
+var CrashBundleDeliveryTimeout = 10 * time.Second
+
+// CrashBundle is the structured report assembled when the process
+// crashes, on a call to Fatal or on a panic. It is delivered to the
+// destinations configured with SetCrashBundleDestinations.
+//
+// The sensitive data is redacted from the bundle, so that it can be
+// sent outside of the cluster.
+type CrashBundle struct {
+	// Time is the time at which the bundle was assembled.
+	Time time.Time `json:"time"`
+	// Reason describes the fatal error or the panic.
+	Reason string `json:"reason"`
+	// Build describes the executable which crashed.
+	Build build.Info `json:"build"`
+	// Entries are the last entries of severity RecentEntriesSeverity or
+	// above logged on each channel, ordered by timestamp. See
+	// RecentEntries().
+	Entries []logpb.Entry `json:"entries"`
+	// Stacks is the dump of the stacks of all the goroutines.
+	Stacks string `json:"stacks"`
+}
+
+// CrashBundleDestination is the interface implemented by the
+// destinations of crash bundles.
+type CrashBundleDestination interface {
+	// Deliver delivers the bundle. It is called on the crashing
+	// goroutine, hence it must not log.
+	Deliver(ctx context.Context, b *CrashBundle) error
+	// String describes the destination, to report delivery errors.
+	String() string
+}
+
+// crashBundleDestinations are the destinations of crash bundles. There
+// are none unless the operator opted into crash bundles.
+var crashBundleDestinations struct {
+	syncutil.Mutex
+	dests []CrashBundleDestination
+}
+
+// SetCrashBundleDestinations sets the destinations of the crash bundles
+// assembled on Fatal or on panics. Crash bundles are disabled when
+// there are no destinations, as by default.
+func SetCrashBundleDestinations(dests ...CrashBundleDestination) {
+	crashBundleDestinations.Lock()
+	defer crashBundleDestinations.Unlock()
+	crashBundleDestinations.dests = dests
+}
+
+func getCrashBundleDestinations() []CrashBundleDestination {
+	crashBundleDestinations.Lock()
+	defer crashBundleDestinations.Unlock()
+	return crashBundleDestinations.dests
+}
+
+// MakeCrashBundle assembles a crash bundle for the given fatal error or
+// panic. The sensitive data is redacted from the reason and from the
+// log entries.
+func MakeCrashBundle(reason error) *CrashBundle {
+	return &CrashBundle{
+		Time:    timeutil.Now(),
+		Reason:  string(redact.Sprint(reason).Redact()),
+		Build:   build.GetInfo(),
+		Entries: RecentEntries(RecentEntriesSeverity, WithoutSensitiveData),
+		Stacks:  string(getStacks(true /* all */)),
+	}
+}
+
+// MaybeDeliverCrashBundle assembles a crash bundle for the given fatal
+// error or panic, and delivers it to the configured destinations, if
+// any. It is called on Fatal, and by the panic reporting code in
+// package logcrash. Delivery errors are reported on the process'
+// external stderr, since the logging output may not be available
+// during a crash.
+func MaybeDeliverCrashBundle(ctx context.Context, reason error) {
+	dests := getCrashBundleDestinations()
+	if len(dests) == 0 {
+		return
+	}
+	b := MakeCrashBundle(reason)
+	ctx, cancel := context.WithTimeout(ctx, CrashBundleDeliveryTimeout)
+	defer cancel()
+	for _, d := range dests {
+		if err := d.Deliver(ctx, b); err != nil {
+			fmt.Fprintf(OrigStderr, "unable to deliver crash bundle to %s: %v\n", d, err)
+		}
+	}
+}
+
+// fileCrashBundleDestination writes the crash bundles as JSON files in
+// a directory.
+type fileCrashBundleDestination struct {
+	dir string
+}
+
+// NewFileCrashBundleDestination returns a destination which writes the
+// crash bundles as JSON files in the given directory.
+func NewFileCrashBundleDestination(dir string) CrashBundleDestination {
+	return &fileCrashBundleDestination{dir: dir}
+}
+
+// Deliver implements the CrashBundleDestination interface.
+func (d *fileCrashBundleDestination) Deliver(_ context.Context, b *CrashBundle) error {
+	j, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(d.dir, 0755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("crash-bundle.%s.%d.json", b.Time.Format(FileTimeFormat), os.Getpid())
+	return os.WriteFile(filepath.Join(d.dir, name), j, 0644)
+}
+
+func (d *fileCrashBundleDestination) String() string { return "file://" + d.dir }
+
+// httpCrashBundleDestination posts the crash bundles in JSON format to
+// an HTTP endpoint.
+type httpCrashBundleDestination struct {
+	url    string
+	client http.Client
+}
+
+// NewHTTPCrashBundleDestination returns a destination which posts the
+// crash bundles in JSON format to the given HTTP(S) URL.
+func NewHTTPCrashBundleDestination(url string) CrashBundleDestination {
+	return &httpCrashBundleDestination{url: url}
+}
+
+// Deliver implements the CrashBundleDestination interface.
+func (d *httpCrashBundleDestination) Deliver(ctx context.Context, b *CrashBundle) error {
+	j, err := json.Marshal(b)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.url, bytes.NewReader(j))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return errors.Newf("unexpected status: %s", resp.Status)
+	}
+	return nil
+}
+
+func (d *httpCrashBundleDestination) String() string { return d.url }
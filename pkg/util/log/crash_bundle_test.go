@@ -0,0 +1,79 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCrashBundle(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer Scope(t).Close(t)
+
+	ctx := context.Background()
+	Ops.Warningf(ctx, "crash bundle: %s", "secret")
+
+	// An HTTP endpoint and a directory.
+	received := make(chan []byte, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		received <- b
+	}))
+	defer srv.Close()
+	dir := t.TempDir()
+	SetCrashBundleDestinations(
+		NewFileCrashBundleDestination(dir),
+		NewHTTPCrashBundleDestination(srv.URL),
+	)
+	defer SetCrashBundleDestinations()
+
+	MaybeDeliverCrashBundle(ctx, errors.Newf("crash: %s", "secret"))
+
+	check := func(j []byte) {
+		var b CrashBundle
+		require.NoError(t, json.Unmarshal(j, &b))
+		// The sensitive data is redacted.
+		require.Equal(t, "crash: ‹×›", b.Reason)
+		require.NotContains(t, string(j), "secret")
+		var found bool
+		for _, e := range b.Entries {
+			found = found || e.Message == "crash bundle: ‹×›"
+		}
+		require.True(t, found, "missing entry in %+v", b.Entries)
+		require.Contains(t, b.Stacks, "TestCrashBundle")
+	}
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	j, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	require.NoError(t, err)
+	check(j)
+	check(<-received)
+
+	// Without destinations, no bundle is assembled.
+	SetCrashBundleDestinations()
+	MaybeDeliverCrashBundle(ctx, errors.New("crash"))
+	files, err = os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+}
@@ -0,0 +1,108 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"net/http"
+
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+)
+
+// The headers of the requests signed by the network sinks configured
+// with sign-with-node-cert.
+const (
+	signatureHeader            = "X-Cockroach-Signature"
+	signatureAlgorithmHeader   = "X-Cockroach-Signature-Algorithm"
+	signatureCertificateHeader = "X-Cockroach-Signature-Certificate"
+)
+
+var nodeCert struct {
+	syncutil.RWMutex
+	// fn retrieves the node certificate and its private key. It is nil
+	// until set by SetNodeCertificateFn().
+	fn func() (*tls.Certificate, error)
+}
+
+// SetNodeCertificateFn configures the function used to retrieve the
+// node certificate, with which the network sinks configured with
+// sign-with-node-cert sign their requests. The function is called
+// for every request, so that it may return a reloaded certificate. It
+// can be reset with a nil argument.
+func SetNodeCertificateFn(fn func() (*tls.Certificate, error)) {
+	nodeCert.Lock()
+	defer nodeCert.Unlock()
+	nodeCert.fn = fn
+}
+
+// signRequest signs the log entries contained in the given request
+// with the node certificate, and sets the signature headers
+// accordingly.
+func signRequest(req *http.Request, entries []byte) error {
+	nodeCert.RLock()
+	fn := nodeCert.fn
+	nodeCert.RUnlock()
+	if fn == nil {
+		return errors.New("cannot sign log entries: node certificate not available")
+	}
+	cert, err := fn()
+	if err != nil {
+		return errors.Wrap(err, "cannot sign log entries")
+	}
+	if len(cert.Certificate) == 0 {
+		return errors.New("cannot sign log entries: empty node certificate")
+	}
+	sig, alg, err := signEntries(cert.PrivateKey, entries)
+	if err != nil {
+		return errors.Wrap(err, "cannot sign log entries")
+	}
+	req.Header.Set(signatureHeader, base64.StdEncoding.EncodeToString(sig))
+	req.Header.Set(signatureAlgorithmHeader, alg.String())
+	req.Header.Set(signatureCertificateHeader, base64.StdEncoding.EncodeToString(cert.Certificate[0]))
+	return nil
+}
+
+// signEntries signs the log entries with the given private key. The
+// returned algorithm is the one to pass to the CheckSignature()
+// method of the corresponding x509.Certificate to verify the
+// signature.
+func signEntries(
+	key crypto.PrivateKey, entries []byte,
+) (sig []byte, alg x509.SignatureAlgorithm, err error) {
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, 0, errors.Newf("unsupported private key type %T", key)
+	}
+	switch signer.Public().(type) {
+	case *rsa.PublicKey:
+		alg = x509.SHA256WithRSA
+	case *ecdsa.PublicKey:
+		alg = x509.ECDSAWithSHA256
+	case ed25519.PublicKey:
+		// Ed25519 signs the message itself, not a digest.
+		sig, err = signer.Sign(rand.Reader, entries, crypto.Hash(0))
+		return sig, x509.PureEd25519, err
+	default:
+		return nil, 0, errors.Newf("unsupported public key type %T", signer.Public())
+	}
+	digest := sha256.Sum256(entries)
+	sig, err = signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	return sig, alg, err
+}
@@ -0,0 +1,93 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/redact"
+)
+
+// maxElisionMarkerSize is an upper bound on the size of the elision
+// marker inserted in truncated messages, including the redaction
+// markers added around it to keep the message well-formed.
+var maxElisionMarkerSize = len(" [... 18446744073709551615 bytes elided ...] ") +
+	2*len(redact.StartMarker()) + 2*len(redact.EndMarker())
+
+// truncateMessage truncates the message of the given payload to at most
+// maxSize bytes, by eliding its middle. The beginning and the end of
+// the message are preserved around an elision marker, which reports
+// the number of bytes elided. The returned bool is false if the
+// message was not truncated.
+//
+// If the payload is redactable, the redaction markers cut by the
+// elision are balanced, so that the sensitive parts of the message
+// remain enclosed. The payloads of structured entries are never
+// truncated, since that would make them invalid JSON.
+func truncateMessage(
+	payload entryPayload, structured bool, maxSize int,
+) (entryPayload, bool) {
+	msg := payload.message
+	if structured || maxSize <= 0 || len(msg) <= maxSize {
+		return payload, false
+	}
+	keep := maxSize - maxElisionMarkerSize
+	if keep < 0 {
+		keep = 0
+	}
+	// The message is cut at the boundaries of UTF-8 sequences.
+	head := runeStart(msg, keep/2)
+	tail := runeStart(msg, len(msg)-(keep-keep/2))
+	if tail < head {
+		tail = head
+	}
+
+	var buf strings.Builder
+	buf.Grow(maxSize)
+	buf.WriteString(msg[:head])
+	if payload.redactable && insideRedactionMarkers(msg[:head]) {
+		buf.Write(redact.EndMarker())
+	}
+	buf.WriteString(" [... ")
+	buf.WriteString(strconv.Itoa(tail - head))
+	buf.WriteString(" bytes elided ...] ")
+	if payload.redactable && insideRedactionMarkers(msg[:tail]) {
+		buf.Write(redact.StartMarker())
+	}
+	buf.WriteString(msg[tail:])
+	payload.message = buf.String()
+	return payload, true
+}
+
+// runeStart returns the largest index no greater than i which is the
+// start of a UTF-8 sequence in s.
+func runeStart(s string, i int) int {
+	if i <= 0 {
+		return 0
+	}
+	if i >= len(s) {
+		return len(s)
+	}
+	for i > 0 && !utf8.RuneStart(s[i]) {
+		i--
+	}
+	return i
+}
+
+// insideRedactionMarkers returns whether the end of the given prefix
+// of a redactable message is enclosed in redaction markers.
+func insideRedactionMarkers(prefix string) bool {
+	start := strings.LastIndex(prefix, string(redact.StartMarker()))
+	end := strings.LastIndex(prefix, string(redact.EndMarker()))
+	return start > end
+}
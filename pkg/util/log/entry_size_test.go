@@ -0,0 +1,88 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/redact"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTruncateMessage(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const maxSize = 256
+
+	t.Run("short", func(t *testing.T) {
+		p := entryPayload{message: strings.Repeat("a", maxSize)}
+		res, truncated := truncateMessage(p, false /* structured */, maxSize)
+		require.False(t, truncated)
+		require.Equal(t, p, res)
+	})
+
+	t.Run("structured", func(t *testing.T) {
+		p := entryPayload{message: `"Event":"` + strings.Repeat("a", 2*maxSize) + `"`}
+		res, truncated := truncateMessage(p, true /* structured */, maxSize)
+		require.False(t, truncated)
+		require.Equal(t, p, res)
+	})
+
+	t.Run("head and tail", func(t *testing.T) {
+		msg := "head" + strings.Repeat("a", 1000) + strings.Repeat("b", 1000) + "error: tail"
+		res, truncated := truncateMessage(entryPayload{message: msg}, false /* structured */, maxSize)
+		require.True(t, truncated)
+		require.LessOrEqual(t, len(res.message), maxSize)
+		require.True(t, strings.HasPrefix(res.message, "headaaa"), res.message)
+		require.True(t, strings.HasSuffix(res.message, "bbberror: tail"), res.message)
+		i := strings.Index(res.message, " [... ")
+		j := strings.Index(res.message, " bytes elided ...] ")
+		require.True(t, i >= 0 && j > i, res.message)
+		var elided int
+		_, err := fmt.Sscanf(res.message[i:], " [... %d", &elided)
+		require.NoError(t, err)
+		require.Equal(t, len(msg), len(res.message)-(j+len(" bytes elided ...] ")-i)+elided)
+	})
+
+	t.Run("utf8", func(t *testing.T) {
+		msg := strings.Repeat("héllo wörld ", 100)
+		res, truncated := truncateMessage(entryPayload{message: msg}, false /* structured */, maxSize)
+		require.True(t, truncated)
+		require.LessOrEqual(t, len(res.message), maxSize)
+		require.True(t, utf8.ValidString(res.message), res.message)
+	})
+
+	t.Run("redactable", func(t *testing.T) {
+		// The elision cuts through the sensitive part of the message: the
+		// redaction markers must remain balanced around both of its
+		// remaining ends.
+		msg := redact.Sprintf("safe %s safe", strings.Repeat("x", 1000))
+		res, truncated := truncateMessage(
+			entryPayload{message: string(msg), redactable: true}, false /* structured */, maxSize)
+		require.True(t, truncated)
+		require.LessOrEqual(t, len(res.message), maxSize)
+		redacted := string(redact.RedactableString(res.message).Redact())
+		require.NotContains(t, redacted, "x", redacted)
+		require.True(t, strings.HasPrefix(redacted, "safe ‹×› [... "), redacted)
+		require.True(t, strings.HasSuffix(redacted, " bytes elided ...] ‹×› safe"), redacted)
+
+		// Without redaction markers, none are added.
+		res, truncated = truncateMessage(
+			entryPayload{message: string(msg.StripMarkers()), redactable: false}, false /* structured */, maxSize)
+		require.True(t, truncated)
+		require.NotContains(t, res.message, string(redact.StartMarker()))
+		require.NotContains(t, res.message, string(redact.EndMarker()))
+	})
+}
@@ -43,7 +43,11 @@ func StructuredEvent(ctx context.Context, event logpb.EventPayload) {
 		heapEntry := entry
 		eventInternal(sp, el, entry.sev >= severity.ERROR, &heapEntry)
 	}
+	maybeCapture(ctx, entry)
+	if !logging.budgets.allow(&entry) {
+		return
+	}
 
 	logger := logging.getLogger(entry.ch)
-	logger.outputLogEntry(entry)
+	logger.outputLogEntry(ctx, entry)
 }
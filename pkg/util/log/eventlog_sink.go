@@ -0,0 +1,204 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"github.com/cockroachdb/cockroach/pkg/cli/exit"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logconfig"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+)
+
+// eventLogSink represents the Windows Event Log of the local host.
+//
+// The entries are formatted by an eventLogFormatter, which prefixes
+// them with the type and ID of the event under which they are
+// reported.
+type eventLogSink struct {
+	source string
+
+	// config is the configuration this sink was created with. It is
+	// used by DescribeAppliedConfig().
+	config *logconfig.EventLogSinkConfig
+
+	mu struct {
+		syncutil.Mutex
+		w eventLogWriter
+		// closed is set once w has been closed by closeOnShutdown().
+		closed bool
+	}
+}
+
+// eventLogWriter reports events to the Windows Event Log. It is
+// implemented by the handles returned by openEventLog().
+type eventLogWriter interface {
+	Info(eid uint32, msg string) error
+	Warning(eid uint32, msg string) error
+	Error(eid uint32, msg string) error
+	Close() error
+}
+
+func newEventLogSink(c logconfig.EventLogSinkConfig) (*eventLogSink, error) {
+	w, err := openEventLog(*c.Source)
+	if err != nil {
+		return nil, err
+	}
+	s := &eventLogSink{
+		source: *c.Source,
+		config: &c,
+	}
+	s.mu.w = w
+	return s, nil
+}
+
+// closeOnShutdown starts a goroutine which closes the handle to the
+// Windows Event Log when the provided closer is closed, i.e. when the
+// logging configuration is replaced or torn down.
+func (l *eventLogSink) closeOnShutdown(closer *bufferedSinkCloser) {
+	stopC, unregister := closer.registerEventLogSink(l)
+	go func() {
+		defer unregister()
+		<-stopC
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.mu.closed = true
+		if err := l.mu.w.Close(); err != nil {
+			fmt.Fprintf(OrigStderr, "%s: error closing the event log: %v\n", l, err)
+		}
+	}()
+}
+
+func (l *eventLogSink) String() string {
+	return "eventlog:" + l.source
+}
+
+// active implements the logSink interface.
+func (l *eventLogSink) active() bool { return true }
+
+// attachHints implements the logSink interface.
+func (l *eventLogSink) attachHints(stacks []byte) []byte {
+	return stacks
+}
+
+// exitCode implements the logSink interface.
+func (l *eventLogSink) exitCode() exit.Code {
+	return exit.LoggingNetCollectorUnavailable()
+}
+
+// output implements the logSink interface.
+func (l *eventLogSink) output(b []byte, opts sinkOutputOptions) error {
+	etype, eid, msg := splitEventLogMessage(b)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.mu.closed {
+		return errors.Newf("%s: event log is closed", l)
+	}
+	var err error
+	switch etype {
+	case eventLogError:
+		err = l.mu.w.Error(eid, msg)
+	case eventLogWarning:
+		err = l.mu.w.Warning(eid, msg)
+	default:
+		err = l.mu.w.Info(eid, msg)
+	}
+	if err != nil {
+		fmt.Fprintf(OrigStderr, "%s: logging error: %v\n%s", l, err, msg)
+	}
+	return err
+}
+
+// eventLogType is the type of an event reported to the Windows Event
+// Log, as encoded by the eventLogFormatter.
+type eventLogType byte
+
+const (
+	eventLogInfo    eventLogType = 'I'
+	eventLogWarning eventLogType = 'W'
+	eventLogError   eventLogType = 'E'
+)
+
+// eventLogFormatter wraps another formatter to prefix its output with
+// the type and ID of the event under which it is reported to the
+// Windows Event Log, separated by a space: e.g. "W1 " for a warning on
+// the OPS channel.
+type eventLogFormatter struct {
+	inner logFormatter
+}
+
+// formatterName implements the logFormatter interface. The name of the
+// wrapped formatter is reported, since this is the format selected in
+// the configuration.
+func (f eventLogFormatter) formatterName() string { return f.inner.formatterName() }
+
+// doc implements the logFormatter interface.
+func (f eventLogFormatter) doc() string { return f.inner.doc() }
+
+// contentType implements the logFormatter interface.
+func (f eventLogFormatter) contentType() string { return f.inner.contentType() }
+
+// formatEntry implements the logFormatter interface.
+func (f eventLogFormatter) formatEntry(entry logEntry) *buffer {
+	etype, eid := eventLogInfo, uint32(0)
+	if !entry.header {
+		etype, eid = eventLogSeverity(entry.sev), uint32(entry.ch)
+	}
+
+	msg := f.inner.formatEntry(entry)
+	defer putBuffer(msg)
+
+	buf := getBuffer()
+	buf.WriteByte(byte(etype))
+	buf.WriteString(strconv.FormatUint(uint64(eid), 10))
+	buf.WriteByte(' ')
+	buf.Write(bytes.TrimRight(msg.Bytes(), "\n"))
+	return buf
+}
+
+// eventLogSeverity maps a logging severity to the type of the event
+// reported to the Windows Event Log.
+func eventLogSeverity(sev Severity) eventLogType {
+	switch sev {
+	case severity.FATAL, severity.ERROR:
+		return eventLogError
+	case severity.WARNING:
+		return eventLogWarning
+	default:
+		return eventLogInfo
+	}
+}
+
+// splitEventLogMessage extracts the type and ID of the event from the
+// output of the eventLogFormatter. If the input is not prefixed as
+// expected, it is reported in full as an informational event with ID
+// 0.
+func splitEventLogMessage(b []byte) (etype eventLogType, eid uint32, msg string) {
+	sp := bytes.IndexByte(b, ' ')
+	if sp < 2 {
+		return eventLogInfo, 0, string(b)
+	}
+	etype = eventLogType(b[0])
+	switch etype {
+	case eventLogInfo, eventLogWarning, eventLogError:
+	default:
+		return eventLogInfo, 0, string(b)
+	}
+	id, err := strconv.ParseUint(string(b[1:sp]), 10, 32)
+	if err != nil {
+		return eventLogInfo, 0, string(b)
+	}
+	return etype, uint32(id), string(b[sp+1:])
+}
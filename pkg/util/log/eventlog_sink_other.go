@@ -0,0 +1,22 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+//go:build !windows
+// +build !windows
+
+package log
+
+import "github.com/cockroachdb/errors"
+
+// openEventLog opens the Windows Event Log of the local host, which is
+// not available on this platform.
+func openEventLog(source string) (eventLogWriter, error) {
+	return nil, errors.Newf("eventlog sinks are only supported on Windows")
+}
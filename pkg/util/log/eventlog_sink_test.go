@@ -0,0 +1,107 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log/channel"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEventLog records the events reported to it.
+type fakeEventLog struct {
+	events []string
+	closed bool
+}
+
+var _ eventLogWriter = (*fakeEventLog)(nil)
+
+func (f *fakeEventLog) report(etype string, eid uint32, msg string) error {
+	f.events = append(f.events, fmt.Sprintf("%s %d %s", etype, eid, msg))
+	return nil
+}
+
+func (f *fakeEventLog) Info(eid uint32, msg string) error    { return f.report("info", eid, msg) }
+func (f *fakeEventLog) Warning(eid uint32, msg string) error { return f.report("warning", eid, msg) }
+func (f *fakeEventLog) Error(eid uint32, msg string) error   { return f.report("error", eid, msg) }
+func (f *fakeEventLog) Close() error                         { f.closed = true; return nil }
+
+func TestEventLogSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	w := &fakeEventLog{}
+	s := &eventLogSink{source: "CockroachDB"}
+	s.mu.w = w
+	f := eventLogFormatter{inner: formatCrdbV2{}}
+
+	ctx := context.Background()
+	for _, entry := range []logEntry{
+		makeUnstructuredEntry(ctx, severity.INFO, channel.OPS, 0, false, "hello world"),
+		makeUnstructuredEntry(ctx, severity.WARNING, channel.HEALTH, 0, false, "hello again"),
+		makeUnstructuredEntry(ctx, severity.FATAL, channel.DEV, 0, false, "goodbye"),
+	} {
+		b := f.formatEntry(entry)
+		require.NoError(t, s.output(b.Bytes(), sinkOutputOptions{}))
+		putBuffer(b)
+	}
+
+	require.Len(t, w.events, 3)
+	for i, exp := range []struct {
+		prefix string
+		msg    string
+	}{
+		{fmt.Sprintf("info %d ", channel.OPS), "hello world"},
+		{fmt.Sprintf("warning %d ", channel.HEALTH), "hello again"},
+		{fmt.Sprintf("error %d ", channel.DEV), "goodbye"},
+	} {
+		require.Regexp(t, "^"+exp.prefix+"[IWEF]\\d{6} ", w.events[i])
+		require.Contains(t, w.events[i], exp.msg)
+		require.NotContains(t, w.events[i], "\n")
+	}
+}
+
+func TestEventLogSinkClose(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	w := &fakeEventLog{}
+	s := &eventLogSink{source: "CockroachDB"}
+	s.mu.w = w
+	closer := newBufferedSinkCloser()
+	s.closeOnShutdown(closer)
+	require.NoError(t, s.output([]byte("I1 hello"), sinkOutputOptions{}))
+
+	// Closing the closer closes the event log.
+	require.NoError(t, closer.Close(time.Second))
+	require.True(t, w.closed)
+	require.Error(t, s.output([]byte("I1 goodbye"), sinkOutputOptions{}))
+	require.Len(t, w.events, 1)
+}
+
+func TestSplitEventLogMessage(t *testing.T) {
+	etype, eid, msg := splitEventLogMessage([]byte("W12 hello world"))
+	require.Equal(t, eventLogWarning, etype)
+	require.Equal(t, uint32(12), eid)
+	require.Equal(t, "hello world", msg)
+
+	// Input which is not prefixed as expected is reported as-is.
+	for _, in := range []string{"hello world", "X1 hello", "Wx hello", "W hello"} {
+		etype, eid, msg = splitEventLogMessage([]byte(in))
+		require.Equal(t, eventLogInfo, etype)
+		require.Equal(t, uint32(0), eid)
+		require.Equal(t, in, msg)
+	}
+}
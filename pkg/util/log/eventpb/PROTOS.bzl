@@ -18,6 +18,7 @@ EVENTPB_PROTOS = [
     "job_events.proto",
     "health_events.proto",
     "telemetry.proto",
+    "schema_change_events.proto",
 ]
 
 EVENTPB_PROTO_DEPS = [ "//pkg/util/log/logpb:event.proto", ] + EVENTPB_PROTOS
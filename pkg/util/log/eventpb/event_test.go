@@ -11,11 +11,16 @@
 package eventpb
 
 import (
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
 	"github.com/cockroachdb/redact"
+	"github.com/gogo/protobuf/proto"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestEventJSON(t *testing.T) {
@@ -66,3 +71,62 @@ func TestEventJSON(t *testing.T) {
 		assert.Equal(t, tc.exp, string(b))
 	}
 }
+
+// TestEventTypes checks that the generated EventTypes map agrees with the
+// event payloads: each event type must be the one reported by the payload of
+// the corresponding Go type, which must be logged to the same channel.
+func TestEventTypes(t *testing.T) {
+	require.NotEmpty(t, EventTypes)
+	for eventType, info := range EventTypes {
+		msgType := proto.MessageType("cockroach.util.log.eventpb." + info.GoType)
+		require.NotNilf(t, msgType, "unknown message type %s", info.GoType)
+		ev, ok := reflect.New(msgType.Elem()).Interface().(logpb.EventPayload)
+		require.Truef(t, ok, "%s is not an event payload", info.GoType)
+		require.Equal(t, eventType, logpb.GetEventTypeName(ev))
+		require.Equalf(t, info.Channel, ev.LoggingChannel(),
+			"event type %s logged to unexpected channel", eventType)
+	}
+}
+
+func TestTruncatePayload(t *testing.T) {
+	const maxBytes = 200
+	long := strings.Repeat("x", 2*maxBytes)
+
+	// Events within the budget are left untouched.
+	ev := &CreateDatabase{DatabaseName: "hello"}
+	require.False(t, TruncatePayload(ev, maxBytes))
+	require.Equal(t, "hello", ev.DatabaseName)
+
+	// The longest fields are shortened first.
+	ev = &CreateDatabase{
+		CommonEventDetails:    logpb.CommonEventDetails{EventType: "create_database"},
+		CommonSQLEventDetails: CommonSQLEventDetails{Statement: "CREATE DATABASE ‹foo›", User: "root"},
+		DatabaseName:          long,
+	}
+	require.True(t, TruncatePayload(ev, maxBytes))
+	require.LessOrEqual(t, jsonSize(ev), maxBytes)
+	require.True(t, strings.HasPrefix(long, ev.DatabaseName))
+	require.NotEmpty(t, ev.DatabaseName)
+	require.Equal(t, redact.RedactableString("CREATE DATABASE ‹foo›"), ev.Statement)
+	require.Equal(t, "root", ev.User)
+	require.Equal(t, "create_database", ev.EventType)
+
+	// Redactable strings are not cut in the middle of a sensitive value.
+	ev = &CreateDatabase{CommonSQLEventDetails: CommonSQLEventDetails{
+		Statement: redact.RedactableString("CREATE DATABASE ‹" + long + "›"),
+	}}
+	require.True(t, TruncatePayload(ev, maxBytes))
+	require.Equal(t, redact.RedactableString("CREATE DATABASE "), ev.Statement)
+
+	// String arrays are shortened by dropping their trailing elements.
+	var objects []string
+	for i := 0; i < 100; i++ {
+		objects = append(objects, fmt.Sprintf("db.public.t%d", i))
+	}
+	dropDB := &DropDatabase{DatabaseName: "db", DroppedSchemaObjects: objects}
+	require.True(t, TruncatePayload(dropDB, maxBytes))
+	require.LessOrEqual(t, jsonSize(dropDB), maxBytes)
+	require.NotEmpty(t, dropDB.DroppedSchemaObjects)
+	require.Equal(t, objects[:len(dropDB.DroppedSchemaObjects)], dropDB.DroppedSchemaObjects)
+	require.Equal(t, "db", dropDB.DatabaseName)
+}
@@ -52,6 +52,11 @@ type catInfo struct {
 	Events     []*eventInfo
 }
 
+type chanInfo struct {
+	Name   string
+	Events []*eventInfo
+}
+
 type enumInfo struct {
 	Comment string
 	GoType  string
@@ -67,6 +72,7 @@ type enumValInfo struct {
 type eventInfo struct {
 	Comment         string
 	LogChannel      string
+	Category        string
 	GoType          string
 	Type            string
 	Fields          []fieldInfo
@@ -166,6 +172,25 @@ func run() error {
 		sortedCats = append(sortedCats, cat)
 	}
 
+	chans := map[string]*chanInfo{}
+	keys = nil
+	for _, ev := range sortedInfos {
+		ch, ok := chans[ev.LogChannel]
+		if !ok {
+			ch = &chanInfo{Name: ev.LogChannel}
+			chans[ev.LogChannel] = ch
+			keys = append(keys, ev.LogChannel)
+		}
+		ch.Events = append(ch.Events, ev)
+	}
+	sort.Strings(keys)
+	var sortedChans []*chanInfo
+	for _, k := range keys {
+		ch := chans[k]
+		sort.Slice(ch.Events, func(i, j int) bool { return ch.Events[i].Type < ch.Events[j].Type })
+		sortedChans = append(sortedChans, ch)
+	}
+
 	keys = nil
 	for k := range info {
 		if excludedEvents != nil && excludedEvents.MatchString(k) {
@@ -195,6 +220,7 @@ func run() error {
 		Package    string
 		AllRegexps []reInfo
 		Categories []*catInfo
+		Channels   []*chanInfo
 		Events     []*eventInfo
 		AllEvents  []*eventInfo
 		Enums      []*enumInfo
@@ -202,6 +228,7 @@ func run() error {
 		*packageFlag,
 		regexps.infos,
 		sortedCats,
+		sortedChans,
 		sortedInfos,
 		allSortedInfos,
 		allSortedEnums,
@@ -343,6 +370,9 @@ func readInput(
 				Type:       snakeType,
 				LogChannel: channel,
 			}
+			if curCat != nil {
+				curMsg.Category = curCat.Title
+			}
 			comment = ""
 			infos[typ] = curMsg
 			if !strings.HasPrefix(typ, "Common") {
@@ -689,6 +719,37 @@ import "github.com/cockroachdb/cockroach/pkg/util/log/logpb"
 // LoggingChannel implements the EventPayload interface.
 func (m *{{.GoType}}) LoggingChannel() logpb.Channel { return logpb.Channel_{{.LogChannel}} }
 {{end}}
+`,
+
+	"event_types_go": `// Code generated by gen.go. DO NOT EDIT.
+
+package {{ .Package }}
+
+import "github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+
+// EventTypes describes the notable event types, keyed by the value of the
+// EventType field of their common details.
+var EventTypes = map[string]EventTypeInfo{
+{{range .Events -}}
+  "{{.Type}}": {GoType: "{{.GoType}}", Channel: logpb.Channel_{{.LogChannel}}},
+{{end -}}
+}
+`,
+
+	"eventlog_channels.md": `The notable events documented in [Notable Event Types](eventlog.html)
+are copied to the external logging channels listed below. For each channel,
+the table lists the event types which are logged to it, along with their
+category.
+
+{{range .Channels -}}
+## ` + "`" + `{{.Name}}` + "`" + `
+
+| Event type | Category |
+|--|--|
+{{range .Events -}}
+| [` + "`" + `{{.Type}}` + "`" + `](eventlog.html#{{.Type}}) | {{.Category}} |
+{{end}}
+{{end -}}
 `,
 
 	"eventlog.md": `Certain notable events are reported using a structured format.
@@ -12,6 +12,14 @@ package eventpb
 
 import "github.com/cockroachdb/cockroach/pkg/util/log/logpb"
 
+// EventTypeInfo describes a notable event type. See EventTypes.
+type EventTypeInfo struct {
+	// GoType is the name of the Go type of the event payload.
+	GoType string
+	// Channel is the logging channel the event is emitted on.
+	Channel logpb.Channel
+}
+
 // EventWithCommonSQLPayload is implemented by CommonSQLEventDetails.
 type EventWithCommonSQLPayload interface {
 	logpb.EventPayload
@@ -35,6 +43,8 @@ func (m *CommonSchemaChangeEventDetails) CommonSchemaChangeDetails() *CommonSche
 var _ EventWithCommonSchemaChangePayload = (*FinishSchemaChange)(nil)
 var _ EventWithCommonSchemaChangePayload = (*ReverseSchemaChange)(nil)
 var _ EventWithCommonSchemaChangePayload = (*FinishSchemaChangeRollback)(nil)
+var _ EventWithCommonSchemaChangePayload = (*BeginSchemaChangeStage)(nil)
+var _ EventWithCommonSchemaChangePayload = (*FinishSchemaChangeStage)(nil)
 
 // EventWithCommonJobPayload is implemented by CommonSQLEventDetails.
 type EventWithCommonJobPayload interface {
@@ -0,0 +1,130 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package eventpb
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+	"github.com/cockroachdb/redact"
+)
+
+// TruncatePayload shortens the string fields of the event, longest
+// first, until the size of its JSON representation does not exceed
+// maxBytes or there is nothing left to shorten. It returns whether any
+// field was shortened.
+//
+// String arrays are shortened by dropping their trailing elements.
+// Redactable strings are never cut in the middle of a sensitive value,
+// so that their redaction markers remain balanced. The fields of the
+// CommonEventDetails are left untouched.
+func TruncatePayload(event logpb.EventPayload, maxBytes int) (truncated bool) {
+	fields := truncatableFields(reflect.ValueOf(event).Elem(), nil)
+	for {
+		excess := jsonSize(event) - maxBytes
+		if excess <= 0 {
+			return truncated
+		}
+		var longest reflect.Value
+		var longestSize int
+		for _, f := range fields {
+			if size := fieldSize(f); size > longestSize {
+				longest, longestSize = f, size
+			}
+		}
+		if longestSize == 0 {
+			return truncated
+		}
+		shortenField(event, longest, maxBytes, excess)
+		truncated = true
+	}
+}
+
+var (
+	commonEventDetailsType = reflect.TypeOf(logpb.CommonEventDetails{})
+	redactableStringType   = reflect.TypeOf(redact.RedactableString(""))
+	startRedactable        = string(redact.StartMarker())
+	endRedactable          = string(redact.EndMarker())
+)
+
+// truncatableFields appends the string and string array fields of the
+// struct, including those of its embedded structs, to fields.
+func truncatableFields(v reflect.Value, fields []reflect.Value) []reflect.Value {
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+		if !f.CanSet() {
+			continue
+		}
+		switch f.Kind() {
+		case reflect.String:
+			fields = append(fields, f)
+		case reflect.Slice:
+			if f.Type().Elem().Kind() == reflect.String {
+				fields = append(fields, f)
+			}
+		case reflect.Struct:
+			if v.Type().Field(i).Anonymous && f.Type() != commonEventDetailsType {
+				fields = truncatableFields(f, fields)
+			}
+		}
+	}
+	return fields
+}
+
+func jsonSize(event logpb.EventPayload) int {
+	_, b := event.AppendJSONFields(false /* printComma */, nil)
+	return len(b)
+}
+
+func fieldSize(f reflect.Value) (size int) {
+	if f.Kind() == reflect.String {
+		return f.Len()
+	}
+	for i := 0; i < f.Len(); i++ {
+		size += f.Index(i).Len()
+	}
+	return size
+}
+
+// shortenField shortens the field of the event, which exceeds maxBytes
+// by excess bytes. Strings lose at least excess bytes, while arrays are
+// cut down to their longest prefix that fits within maxBytes, if any.
+func shortenField(event logpb.EventPayload, f reflect.Value, maxBytes, excess int) {
+	if f.Kind() == reflect.Slice {
+		all := f.Slice(0, f.Len())
+		n := sort.Search(all.Len(), func(n int) bool {
+			f.Set(all.Slice(0, n+1))
+			return jsonSize(event) > maxBytes
+		})
+		f.Set(all.Slice(0, n))
+		return
+	}
+	s := f.String()
+	n := len(s) - excess
+	if n <= 0 {
+		f.SetString("")
+		return
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	s = s[:n]
+	if f.Type() == redactableStringType {
+		// Drop the sensitive value which was cut, if any.
+		if start := strings.LastIndex(s, startRedactable); start > strings.LastIndex(s, endRedactable) {
+			s = s[:start]
+		}
+	}
+	f.SetString(s)
+}
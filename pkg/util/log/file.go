@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/cli/exit"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logconfig"
 	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
@@ -83,6 +84,21 @@ type fileSink struct {
 	// temporarily be up to logFileMaxSize larger.
 	logFilesCombinedMaxSize int64
 
+	// logFilesMaxAge is the maximum age of the log files generated by
+	// one logger. Older files are removed by the GC daemon. If zero,
+	// files are not removed based on their age.
+	logFilesMaxAge time.Duration
+
+	// minFreeDisk is the amount of free disk space in bytes that the GC
+	// daemon preserves on the filesystem of the log directory, by
+	// removing the oldest log files. If zero, the free disk space is
+	// not checked.
+	minFreeDisk int64
+
+	// compression is the algorithm used by the GC daemon to compress
+	// the log files once they are rotated out.
+	compression logconfig.FileCompression
+
 	// notify GC daemon that a new log file was created.
 	gcNotify chan struct{}
 
@@ -92,6 +108,10 @@ type fileSink struct {
 
 	filePermissions fs.FileMode
 
+	// async, if set, is the writer goroutine to which the file I/O is
+	// delegated. See startAsyncWriter().
+	async *asyncFileWriter
+
 	// mu protects the remaining elements of this structure and is
 	// used to synchronize output to this file sink..
 	mu struct {
@@ -158,6 +178,22 @@ func (l *fileSink) active() bool {
 	return l.enabled.Get()
 }
 
+// probe implements the sinkProber interface. It checks that files can
+// be created in the log directory.
+func (l *fileSink) probe() error {
+	l.mu.Lock()
+	dir := l.mu.logDir
+	l.mu.Unlock()
+	if dir == "" {
+		return nil
+	}
+	f, err := os.CreateTemp(dir, ".probe-*")
+	if err != nil {
+		return err
+	}
+	return errors.CombineErrors(f.Close(), os.Remove(f.Name()))
+}
+
 // attachHints implements the logSink interface.
 func (l *fileSink) attachHints(stacks []byte) []byte {
 	// The Fatal output will be copied across multiple sinks, so it may
@@ -184,6 +220,13 @@ func (l *fileSink) output(b []byte, opts sinkOutputOptions) error {
 		return nil
 	}
 
+	if l.async != nil {
+		if queued, err := l.async.output(b, opts); queued {
+			return err
+		}
+		// The writer goroutine has terminated. Write synchronously.
+	}
+
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
@@ -211,6 +254,11 @@ func (l *fileSink) emergencyOutput(b []byte) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
+	if l.async != nil {
+		// Write the entries queued before this one first.
+		l.async.writePendingLocked()
+	}
+
 	if err := l.ensureFileLocked(); err != nil {
 		return //nolint:returnerrcheck
 	}
@@ -230,6 +278,9 @@ func (l *fileSink) lockAndFlushAndMaybeSync(doSync bool) {
 	if l == nil {
 		return
 	}
+	if l.async != nil && l.async.flush(doSync) {
+		return
+	}
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	l.flushAndMaybeSyncLocked(doSync)
@@ -18,6 +18,7 @@ import (
 	"regexp"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
@@ -37,10 +38,12 @@ import (
 // underscores and all periods are escaped to an underscore.
 // For compatibility with Windows filenames, all colons from the timestamp
 // (RFC3339) are converted from underscores (see FileTimePattern).
+// Files compressed after rotation carry an additional `.gz` or `.zst`
+// extension.
 // Note this pattern is unanchored and becomes anchored through its use in
 // LogFilePattern.
 const FileNamePattern = `(?P<program>[^/.]+)\.(?P<host>[^/\.]+)\.` +
-	`(?P<user>[^/\.]+)\.(?P<ts>[^/\.]+)\.(?P<pid>\d+)\.log`
+	`(?P<user>[^/\.]+)\.(?P<ts>[^/\.]+)\.(?P<pid>\d+)\.log(?:\.gz|\.zst)?`
 
 // FilePattern matches log file paths.
 const FilePattern = "^(?:.*/)?" + FileNamePattern + "$"
@@ -88,6 +91,15 @@ func ParseLogFilename(filename string) (logpb.FileDetails, error) {
 	}, nil
 }
 
+// parseStacksFilename is like ParseLogFilename, for the names of the
+// goroutine stack dumps produced by fileNameGenerator.stacksName.
+func parseStacksFilename(filename string) (logpb.FileDetails, error) {
+	if !strings.HasSuffix(filename, stacksFileSuffix) {
+		return logpb.FileDetails{}, errMalformedName
+	}
+	return ParseLogFilename(strings.TrimSuffix(filename, stacksFileSuffix) + ".log")
+}
+
 // listLogGroups returns slices of logpb.FileInfo structs.
 // There is one logpb.FileInfo slice per file sink.
 func listLogGroups() (logGroups [][]logpb.FileInfo, err error) {
@@ -151,6 +163,21 @@ func ListLogFiles() (logFiles []logpb.FileInfo, err error) {
 // sink are ignored. This makes it possible to share directories
 // across multiple sinks.
 func (l *fileSink) listLogFiles() (string, []logpb.FileInfo, error) {
+	return l.listFilesOwnedBy(ParseLogFilename)
+}
+
+// listStacksFiles lists the goroutine stack dumps written by
+// writeAllStacksToFile in the sink's target directory.
+func (l *fileSink) listStacksFiles() (string, []logpb.FileInfo, error) {
+	return l.listFilesOwnedBy(parseStacksFilename)
+}
+
+// listFilesOwnedBy returns the log directory of the sink and the
+// files in it which are recognized by the given parser and whose
+// names were produced by the sink.
+func (l *fileSink) listFilesOwnedBy(
+	parse func(filename string) (logpb.FileDetails, error),
+) (string, []logpb.FileInfo, error) {
 	var results []logpb.FileInfo
 	l.mu.Lock()
 	dir := l.mu.logDir
@@ -170,7 +197,7 @@ func (l *fileSink) listLogFiles() (string, []logpb.FileInfo, error) {
 	// below.
 	for _, info := range infos {
 		if info.Mode().IsRegular() {
-			details, err := ParseLogFilename(info.Name())
+			details, err := parse(info.Name())
 			if err == nil && l.nameGenerator.ownsFileByPrefix(details.Program) {
 				results = append(results, MakeFileInfo(details, info))
 			}
@@ -179,7 +206,9 @@ func (l *fileSink) listLogFiles() (string, []logpb.FileInfo, error) {
 	return dir, results, nil
 }
 
-// GetLogReader returns a reader for the specified filename.
+// GetLogReader returns a reader for the specified filename. If the
+// file was compressed after rotation, the reader returns the
+// decompressed contents.
 // The filename must be the base name of a file in
 // this process's log directory (this is safe for cases when the
 // filename comes from external sources, such as the admin UI via
@@ -244,6 +273,15 @@ func GetLogReader(filename string) (io.ReadCloser, error) {
 	if err != nil {
 		return nil, err
 	}
+	if IsCompressedLogFile(baseFileName) {
+		// Compressed files are not written to any more.
+		r, err := NewDecompressingReader(file, baseFileName)
+		if err != nil {
+			_ = file.Close()
+			return nil, err
+		}
+		return r, nil
+	}
 
 	fs.mu.Lock()
 	defer fs.mu.Unlock()
@@ -300,6 +338,9 @@ func FetchEntriesFromFiles(
 }
 
 // FetchEntriesFromFilesWithFormat is like FetchEntriesFromFiles but the caller can specify the format of the log file.
+// If the format is empty, it is detected separately for each file, so that
+// log groups containing files written with different formats can be read
+// together.
 func FetchEntriesFromFilesWithFormat(
 	startTimestamp, endTimestamp int64,
 	maxEntries int,
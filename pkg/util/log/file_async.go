@@ -0,0 +1,263 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"sync"
+
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// asyncFileWriterCapacity is the maximum number of entries queued in
+// the ring buffer of an asyncFileWriter. When the ring buffer is full,
+// output() waits for the writer goroutine to catch up.
+const asyncFileWriterCapacity = 4096
+
+// asyncFileWriter moves the file I/O of a fileSink off the goroutines
+// that emit log entries. Entries are copied into a bounded ring buffer
+// and written in batches by a dedicated goroutine, which flushes the
+// file after every batch.
+//
+// Requests to sync the file to disk are coalesced: all the requests
+// that arrive while a batch is being written are served by a single
+// sync after the next batch (group commit).
+//
+// Entries emitted with the forceSync or extraFlush output options
+// (e.g. FATAL entries), and calls to Flush(), wait until all the
+// entries queued before them have been written.
+type asyncFileWriter struct {
+	// sink is the file sink written to.
+	sink *fileSink
+
+	// workC is used to wake up the writer goroutine when entries or sync
+	// requests are queued.
+	workC chan struct{}
+
+	mu struct {
+		syncutil.Mutex
+
+		// ring holds the entries not yet written. The entries are stored
+		// at ring[(head+i)%len(ring)], for 0 <= i < n.
+		ring    []*buffer
+		head, n int
+
+		// enqueued is the sequence number of the last entry added to the
+		// ring buffer.
+		enqueued uint64
+		// written is the sequence number of the last entry written and
+		// flushed to the log file.
+		written uint64
+		// syncRequested is the sequence number of the last entry which
+		// must be synced to disk.
+		syncRequested uint64
+		// synced is the sequence number of the last entry synced to disk.
+		synced uint64
+
+		// err is the first error encountered while writing entries. It is
+		// reported by the next call to output().
+		err error
+
+		// progress is signaled every time entries are taken from the ring
+		// buffer or written, and when the writer goroutine terminates.
+		progress sync.Cond
+
+		// stopped is set when the writer goroutine has been asked to
+		// terminate. No entries are queued afterwards; the output() calls
+		// write synchronously instead.
+		stopped bool
+		// exited is set after the writer goroutine has written the last
+		// queued entries and terminated.
+		exited bool
+	}
+}
+
+// startAsyncWriter starts a writer goroutine for l, which runs until
+// the provided closer is closed. From then on, the entries sent to l
+// are written asynchronously.
+//
+// This must be called before l is attached to any logger.
+func (l *fileSink) startAsyncWriter(closer *bufferedSinkCloser) {
+	w := &asyncFileWriter{
+		sink:  l,
+		workC: make(chan struct{}, 1),
+	}
+	w.mu.ring = make([]*buffer, asyncFileWriterCapacity)
+	w.mu.progress.L = &w.mu.Mutex
+	l.async = w
+
+	stopC, unregister := closer.registerAsyncFileWriter(w)
+	go func() {
+		defer unregister()
+		w.run(stopC)
+	}()
+}
+
+// output queues b to be written by the writer goroutine. It returns
+// false if the writer goroutine has terminated, in which case b must
+// be written synchronously by the caller.
+//
+// An error is returned if a previous asynchronous write failed.
+func (w *asyncFileWriter) output(b []byte, opts sinkOutputOptions) (bool, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for w.mu.n == len(w.mu.ring) && !w.mu.stopped {
+		w.mu.progress.Wait()
+	}
+	if w.mu.stopped {
+		return false, nil
+	}
+
+	buf := getBuffer()
+	buf.Write(b)
+	w.mu.ring[(w.mu.head+w.mu.n)%len(w.mu.ring)] = buf
+	w.mu.n++
+	w.mu.enqueued++
+	seq := w.mu.enqueued
+
+	if opts.forceSync && w.mu.syncRequested < seq {
+		w.mu.syncRequested = seq
+	}
+	w.signalLocked()
+
+	if opts.forceSync || opts.extraFlush || logging.flushWrites.Get() {
+		w.waitLocked(seq, opts.forceSync)
+	}
+
+	err := w.mu.err
+	w.mu.err = nil
+	return true, err
+}
+
+// flush waits until all the entries queued so far have been written
+// and, if doSync is set, synced to disk. It returns false if the
+// writer goroutine has terminated.
+func (w *asyncFileWriter) flush(doSync bool) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.mu.exited {
+		return false
+	}
+	seq := w.mu.enqueued
+	if doSync && w.mu.syncRequested < seq {
+		w.mu.syncRequested = seq
+	}
+	w.signalLocked()
+	w.waitLocked(seq, doSync)
+	return true
+}
+
+// signalLocked wakes up the writer goroutine.
+//
+// w.mu is held.
+func (w *asyncFileWriter) signalLocked() {
+	select {
+	case w.workC <- struct{}{}:
+	default:
+		// A wake up is already pending.
+	}
+}
+
+// waitLocked waits until the entry with sequence number seq has been
+// written and, if doSync is set, synced to disk.
+//
+// w.mu is held.
+func (w *asyncFileWriter) waitLocked(seq uint64, doSync bool) {
+	for !w.mu.exited && (w.mu.written < seq || (doSync && w.mu.synced < seq)) {
+		w.mu.progress.Wait()
+	}
+}
+
+// run is the main loop of the writer goroutine. It returns after
+// stopC has been closed and all the queued entries have been written.
+func (w *asyncFileWriter) run(stopC <-chan struct{}) {
+	for {
+		select {
+		case <-w.workC:
+			w.writeBatch()
+		case <-stopC:
+			w.mu.Lock()
+			w.mu.stopped = true
+			// Wake up the output() calls waiting for space in the ring
+			// buffer; they will write synchronously.
+			w.mu.progress.Broadcast()
+			w.mu.Unlock()
+
+			// No entries can be queued any more; write the remaining ones.
+			w.writeBatch()
+
+			w.mu.Lock()
+			w.mu.exited = true
+			w.mu.progress.Broadcast()
+			w.mu.Unlock()
+			return
+		}
+	}
+}
+
+// writeBatch writes all the queued entries to the log file, then
+// flushes it and, if requested, syncs it to disk.
+func (w *asyncFileWriter) writeBatch() {
+	l := w.sink
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	w.writePendingLocked()
+}
+
+// writePendingLocked is the implementation of writeBatch. It is also
+// used by emergencyOutput() to preserve the order of the entries.
+//
+// l.mu is held, where l is w.sink.
+func (w *asyncFileWriter) writePendingLocked() {
+	l := w.sink
+
+	w.mu.Lock()
+	batch := make([]*buffer, 0, w.mu.n)
+	for ; w.mu.n > 0; w.mu.n-- {
+		batch = append(batch, w.mu.ring[w.mu.head])
+		w.mu.ring[w.mu.head] = nil
+		w.mu.head = (w.mu.head + 1) % len(w.mu.ring)
+	}
+	seq := w.mu.enqueued
+	doSync := w.mu.syncRequested > w.mu.synced
+	// There is space in the ring buffer again.
+	w.mu.progress.Broadcast()
+	w.mu.Unlock()
+
+	if len(batch) == 0 && !doSync {
+		return
+	}
+
+	var err error
+	for _, buf := range batch {
+		// NB: we need to check filesink.enabled here in case a test Scope()
+		// has disabled it asynchronously.
+		if err == nil && l.enabled.Get() {
+			if err = l.ensureFileLocked(); err == nil {
+				err = l.writeToFileLocked(buf.Bytes())
+			}
+		}
+		putBuffer(buf)
+	}
+	l.flushAndMaybeSyncLocked(doSync)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.mu.written = seq
+	if doSync {
+		w.mu.synced = seq
+	}
+	if err != nil && w.mu.err == nil {
+		w.mu.err = err
+	}
+	w.mu.progress.Broadcast()
+}
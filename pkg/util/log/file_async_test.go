@@ -0,0 +1,157 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+// memSyncWriter is an in-memory flushSyncWriter which counts syncs.
+// If syncStartedC is set, Sync() blocks until unblockC is closed.
+type memSyncWriter struct {
+	bytes.Buffer
+	syncs        int
+	syncStartedC chan struct{}
+	unblockC     chan struct{}
+}
+
+func (w *memSyncWriter) Flush() error { return nil }
+
+func (w *memSyncWriter) Sync() error {
+	w.syncs++
+	if w.syncStartedC != nil {
+		w.syncStartedC <- struct{}{}
+		w.syncStartedC = nil
+		<-w.unblockC
+	}
+	return nil
+}
+
+func newTestAsyncFileSink(t *testing.T) (*fileSink, *memSyncWriter, *bufferedSinkCloser) {
+	s := newFileSink(
+		t.TempDir(), "test-group",
+		true,   /* bufferedWrites */
+		1<<20,  /* fileMaxSize */
+		10<<20, /* combinedMaxSize */
+		nil,    /* getStartLines */
+		0644,   /* filePermissions */
+	)
+	w := &memSyncWriter{}
+	s.mu.file = w
+	closer := newBufferedSinkCloser()
+	s.startAsyncWriter(closer)
+	return s, w, closer
+}
+
+func TestAsyncFileWriter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s, w, closer := newTestAsyncFileSink(t)
+
+	const numWriters, numEntries = 10, 1000
+	var wg sync.WaitGroup
+	for i := 0; i < numWriters; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < numEntries; j++ {
+				require.NoError(t, s.output([]byte(fmt.Sprintf("%d-%d\n", i, j)), sinkOutputOptions{}))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// Flush waits for all the queued entries, and syncs them.
+	s.lockAndFlushAndMaybeSync(true /* doSync */)
+	s.mu.Lock()
+	lines := strings.Split(strings.TrimSpace(w.String()), "\n")
+	syncs := w.syncs
+	s.mu.Unlock()
+	require.Len(t, lines, numWriters*numEntries)
+	require.Equal(t, 1, syncs)
+
+	// The entries of each goroutine are written in order.
+	next := make([]int, numWriters)
+	for _, line := range lines {
+		var i, j int
+		_, err := fmt.Sscanf(line, "%d-%d", &i, &j)
+		require.NoError(t, err)
+		require.Equal(t, next[i], j)
+		next[i]++
+	}
+
+	// After the writer goroutine terminates, the entries are written
+	// synchronously.
+	require.NoError(t, closer.Close(defaultCloserTimeout))
+	require.NoError(t, s.output([]byte("after close\n"), sinkOutputOptions{}))
+	s.mu.Lock()
+	require.True(t, strings.HasSuffix(w.String(), "after close\n"))
+	s.mu.Unlock()
+}
+
+func TestAsyncFileWriterGroupCommit(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s, w, closer := newTestAsyncFileSink(t)
+	defer func() { require.NoError(t, closer.Close(defaultCloserTimeout)) }()
+
+	// Block the first sync.
+	syncStartedC := make(chan struct{})
+	w.syncStartedC = syncStartedC
+	w.unblockC = make(chan struct{})
+
+	require.NoError(t, s.output([]byte("a\n"), sinkOutputOptions{}))
+	firstDone := make(chan struct{})
+	go func() {
+		defer close(firstDone)
+		s.lockAndFlushAndMaybeSync(true /* doSync */)
+	}()
+	<-syncStartedC
+
+	// While the first sync is in progress, more entries requiring a sync
+	// are emitted. They must be served by a single additional sync.
+	const numWriters = 10
+	var wg sync.WaitGroup
+	for i := 0; i < numWriters; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			require.NoError(t, s.output([]byte(fmt.Sprintf("%d\n", i)), sinkOutputOptions{forceSync: true}))
+		}(i)
+	}
+	// Wait for all the entries to be queued.
+	require.Eventually(t, func() bool {
+		s.async.mu.Lock()
+		defer s.async.mu.Unlock()
+		return s.async.mu.enqueued == numWriters+1
+	}, 10*time.Second, time.Millisecond)
+	close(w.unblockC)
+	<-firstDone
+	wg.Wait()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	require.Equal(t, 2, w.syncs)
+	lines := strings.Split(strings.TrimSpace(w.String()), "\n")
+	require.Equal(t, "a", lines[0])
+	lines = lines[1:]
+	sort.Strings(lines)
+	require.Equal(t, []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9"}, lines)
+}
@@ -0,0 +1,171 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"compress/gzip"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/util/log/logconfig"
+	"github.com/cockroachdb/errors"
+	"github.com/klauspost/compress/zstd"
+)
+
+// The extensions appended to the name of log files when they are
+// compressed after rotation.
+const (
+	gzipFileExtension = ".gz"
+	zstdFileExtension = ".zst"
+)
+
+// compressedFileExtension returns the extension of the files compressed
+// with the given algorithm, or an empty string if the files are not
+// compressed.
+func compressedFileExtension(c logconfig.FileCompression) string {
+	switch c {
+	case logconfig.FileCompressionGzip:
+		return gzipFileExtension
+	case logconfig.FileCompressionZstd:
+		return zstdFileExtension
+	default:
+		return ""
+	}
+}
+
+// IsCompressedLogFile returns whether the named log file was compressed
+// after rotation.
+func IsCompressedLogFile(name string) bool {
+	return strings.HasSuffix(name, gzipFileExtension) ||
+		strings.HasSuffix(name, zstdFileExtension)
+}
+
+// UncompressedLogFileName returns the name of the log file before it
+// was compressed after rotation.
+func UncompressedLogFileName(name string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(name, gzipFileExtension), zstdFileExtension)
+}
+
+// NewDecompressingReader returns a reader for the contents of the named
+// log file, read from f. If the file was compressed after rotation, the
+// returned reader decompresses its contents; otherwise f is returned
+// as-is. Closing the returned reader closes f.
+func NewDecompressingReader(f io.ReadCloser, name string) (io.ReadCloser, error) {
+	switch {
+	case strings.HasSuffix(name, gzipFileExtension):
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, errors.Wrapf(err, "decompressing %s", name)
+		}
+		return &decompressingReader{Reader: zr, closeDecoder: zr.Close, f: f}, nil
+	case strings.HasSuffix(name, zstdFileExtension):
+		zr, err := zstd.NewReader(f, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, errors.Wrapf(err, "decompressing %s", name)
+		}
+		return &decompressingReader{
+			Reader:       zr,
+			closeDecoder: func() error { zr.Close(); return nil },
+			f:            f,
+		}, nil
+	default:
+		return f, nil
+	}
+}
+
+// decompressingReader reads the decompressed contents of a log file.
+type decompressingReader struct {
+	io.Reader
+	closeDecoder func() error
+	f            io.Closer
+}
+
+var _ io.ReadCloser = (*decompressingReader)(nil)
+
+// Close implements the io.Closer interface.
+func (r *decompressingReader) Close() error {
+	return errors.CombineErrors(r.closeDecoder(), r.f.Close())
+}
+
+// compressLogFile compresses the log file at the given path with the
+// given algorithm. The compressed data is written to a temporary file
+// first, which is renamed into place once complete; only then is the
+// original file removed. This ensures that a crash midway does not
+// lose log data. The modification time of the original file is
+// preserved, so that compression does not reset the age of the file.
+// It returns the information about the compressed file.
+func compressLogFile(
+	path string, compression logconfig.FileCompression, perm fs.FileMode,
+) (_ os.FileInfo, resErr error) {
+	ext := compressedFileExtension(compression)
+	if ext == "" {
+		return nil, errors.AssertionFailedf("unsupported compression: %q", compression)
+	}
+	dst := path + ext
+	// The temporary file does not match the log file name pattern, so
+	// that it is ignored by the other users of the log directory.
+	tmp := dst + ".tmp"
+
+	in, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = in.Close() }()
+	srcInfo, err := in.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if resErr != nil {
+			_ = out.Close()
+			_ = os.Remove(tmp)
+		}
+	}()
+
+	var w io.WriteCloser
+	if compression == logconfig.FileCompressionGzip {
+		w = gzip.NewWriter(out)
+	} else {
+		if w, err = zstd.NewWriter(out, zstd.WithEncoderConcurrency(1)); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := io.Copy(w, in); err != nil {
+		_ = w.Close()
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	if err := out.Sync(); err != nil {
+		return nil, err
+	}
+	if err := out.Close(); err != nil {
+		return nil, err
+	}
+	if err := os.Chtimes(tmp, srcInfo.ModTime(), srcInfo.ModTime()); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		return nil, err
+	}
+	if err := os.Remove(path); err != nil {
+		return nil, err
+	}
+	return os.Stat(dst)
+}
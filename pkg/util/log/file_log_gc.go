@@ -17,15 +17,31 @@ import (
 	"os"
 	"path/filepath"
 	"sync/atomic"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 )
 
+// gcPeriod is the interval at which the GC daemon checks the age of the
+// log files and the free disk space, for the sinks configured to do so.
+// Otherwise, the GC only runs upon the creation of a new log file.
+var gcPeriod = time.Minute
+
 // gcDaemon runs the GC loop for the given logger.
 func (l *fileSink) gcDaemon(ctx context.Context) {
+	var tick <-chan time.Time
+	if l.logFilesMaxAge > 0 || l.minFreeDisk > 0 {
+		t := time.NewTicker(gcPeriod)
+		defer t.Stop()
+		tick = t.C
+	}
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case <-l.gcNotify:
+		case <-tick:
 		}
 
 		logging.mu.Lock()
@@ -38,9 +54,12 @@ func (l *fileSink) gcDaemon(ctx context.Context) {
 	}
 }
 
-// gcOldFiles removes the "old" files that do not match
-// the configured size and number threshold.
+// gcOldFiles compresses the log files that were rotated out, if the sink
+// is configured to do so, then removes the "old" files that do not match
+// the configured size, age and free disk space thresholds.
 func (l *fileSink) gcOldFiles() {
+	l.gcOldStacks()
+
 	// This only lists the log files for the current logger (sharing the
 	// prefix).
 	dir, allFiles, err := l.listLogFiles()
@@ -49,27 +68,76 @@ func (l *fileSink) gcOldFiles() {
 		return
 	}
 
-	if len(allFiles) == 0 {
+	files := selectFilesInGroup(allFiles, math.MaxInt64)
+	if len(files) == 0 {
 		// Nothing to do.
 		return
 	}
+	// files is sorted with the newest log files first. Note that we
+	// always keep the most recent log file, which is the one being
+	// written to, as-is.
+	l.compressOldFiles(dir, files[1:])
 
 	logFilesCombinedMaxSize := atomic.LoadInt64(&l.logFilesCombinedMaxSize)
-	if logFilesCombinedMaxSize == 0 {
-		// Nothing to do.
+	now := timeutil.Now()
+	sum := files[0].SizeBytes
+	kept := files[:1]
+	for _, f := range files[1:] {
+		sum += f.SizeBytes
+		tooLarge := logFilesCombinedMaxSize > 0 && sum >= logFilesCombinedMaxSize
+		tooOld := l.logFilesMaxAge > 0 &&
+			now.Sub(timeutil.Unix(0, f.ModTimeNanos)) > l.logFilesMaxAge
+		if !tooLarge && !tooOld {
+			kept = append(kept, f)
+			continue
+		}
+		path := filepath.Join(dir, f.Name)
+		if err := os.Remove(path); err != nil {
+			fmt.Fprintln(OrigStderr, err)
+		}
+	}
+
+	if l.minFreeDisk <= 0 {
 		return
 	}
+	free, err := freeDiskSpace(dir)
+	if err != nil {
+		fmt.Fprintf(OrigStderr, "unable to check free disk space for log files: %s\n", err)
+		return
+	}
+	// Remove the oldest files first until enough space is available.
+	for i := len(kept) - 1; i > 0 && free < l.minFreeDisk; i-- {
+		path := filepath.Join(dir, kept[i].Name)
+		if err := os.Remove(path); err != nil {
+			fmt.Fprintln(OrigStderr, err)
+			continue
+		}
+		free += kept[i].SizeBytes
+	}
+}
 
-	files := selectFilesInGroup(allFiles, math.MaxInt64)
-	if len(files) == 0 {
+// gcOldStacks removes the goroutine stack dumps written by
+// writeAllStacksToFile that do not match the configured age and size
+// thresholds. The dumps are accounted for separately from the log
+// files, so that they do not push the log files out of the group.
+func (l *fileSink) gcOldStacks() {
+	dir, allFiles, err := l.listStacksFiles()
+	if err != nil {
+		fmt.Fprintf(OrigStderr, "unable to GC goroutine stack dumps: %s\n", err)
 		return
 	}
-	// files is sorted with the newest log files first (which we want
-	// to keep). Note that we always keep the most recent log file.
-	sum := files[0].SizeBytes
-	for _, f := range files[1:] {
+
+	logFilesCombinedMaxSize := atomic.LoadInt64(&l.logFilesCombinedMaxSize)
+	now := timeutil.Now()
+	var sum int64
+	// In contrast to the log files, no dump is being written to, so
+	// even the most recent one is subject to the thresholds.
+	for _, f := range selectFilesInGroup(allFiles, math.MaxInt64) {
 		sum += f.SizeBytes
-		if sum < logFilesCombinedMaxSize {
+		tooLarge := logFilesCombinedMaxSize > 0 && sum >= logFilesCombinedMaxSize
+		tooOld := l.logFilesMaxAge > 0 &&
+			now.Sub(timeutil.Unix(0, f.ModTimeNanos)) > l.logFilesMaxAge
+		if !tooLarge && !tooOld {
 			continue
 		}
 		path := filepath.Join(dir, f.Name)
@@ -78,3 +146,25 @@ func (l *fileSink) gcOldFiles() {
 		}
 	}
 }
+
+// compressOldFiles compresses the given log files with the algorithm
+// configured for the sink, if any. The files which are already
+// compressed are left alone. The information about the compressed
+// files is updated in place.
+func (l *fileSink) compressOldFiles(dir string, files []logpb.FileInfo) {
+	if compressedFileExtension(l.compression) == "" {
+		return
+	}
+	for i := range files {
+		f := &files[i]
+		if IsCompressedLogFile(f.Name) {
+			continue
+		}
+		info, err := compressLogFile(filepath.Join(dir, f.Name), l.compression, l.filePermissions)
+		if err != nil {
+			fmt.Fprintf(OrigStderr, "unable to compress log file %s: %s\n", f.Name, err)
+			continue
+		}
+		*f = MakeFileInfo(f.Details, info)
+	}
+}
@@ -0,0 +1,22 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+//go:build !linux && !darwin && !freebsd && !dragonfly && !windows
+// +build !linux,!darwin,!freebsd,!dragonfly,!windows
+
+package log
+
+import "github.com/cockroachdb/errors"
+
+// freeDiskSpace returns the number of bytes available to unprivileged
+// users on the filesystem containing the given directory.
+func freeDiskSpace(dir string) (int64, error) {
+	return 0, errors.New("checking the free disk space is not supported on this platform")
+}
@@ -13,6 +13,7 @@ package log
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"math"
 	"os"
 	"path/filepath"
@@ -177,6 +178,108 @@ func testLogGC(t *testing.T, fileSink *fileSink, logFn func(ctx context.Context,
 	})
 }
 
+func TestGCCompressionAndRetention(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, compression := range []logconfig.FileCompression{
+		logconfig.FileCompressionGzip, logconfig.FileCompressionZstd,
+	} {
+		t.Run(string(compression), func(t *testing.T) {
+			dir := t.TempDir()
+			fs := newFileSink(dir, "gctest", true /* bufferedWrites */, 0, 0, nil, 0o644)
+			fs.compression = compression
+			fs.logFilesMaxAge = time.Hour
+
+			now := timeutil.Now().Truncate(time.Second)
+			writeFile := func(age time.Duration, contents string) string {
+				ts := now.Add(-age)
+				name, _ := fs.nameGenerator.logName(ts)
+				path := filepath.Join(dir, name)
+				require.NoError(t, os.WriteFile(path, []byte(contents), 0o644))
+				require.NoError(t, os.Chtimes(path, ts, ts))
+				return name
+			}
+			current := writeFile(0, "current")
+			rotated := writeFile(time.Minute, "rotated")
+			writeFile(2*time.Hour, "expired")
+
+			fs.gcOldFiles()
+
+			// The current file is left alone, the rotated file is
+			// compressed and the expired file is removed.
+			compressed := rotated + compressedFileExtension(compression)
+			_, files, err := fs.listLogFiles()
+			require.NoError(t, err)
+			var names []string
+			for _, f := range files {
+				names = append(names, f.Name)
+				if f.Name == compressed {
+					// The age of the compressed file is preserved.
+					require.Equal(t, now.Add(-time.Minute).UnixNano(), f.ModTimeNanos)
+				}
+			}
+			require.ElementsMatch(t, []string{current, compressed}, names)
+
+			f, err := os.Open(filepath.Join(dir, compressed))
+			require.NoError(t, err)
+			r, err := NewDecompressingReader(f, compressed)
+			require.NoError(t, err)
+			contents, err := ioutil.ReadAll(r)
+			require.NoError(t, err)
+			require.NoError(t, r.Close())
+			require.Equal(t, "rotated", string(contents))
+			require.Equal(t, rotated, UncompressedLogFileName(compressed))
+
+			// Already compressed files are not compressed again.
+			fs.gcOldFiles()
+			_, files, err = fs.listLogFiles()
+			require.NoError(t, err)
+			require.Len(t, files, 2)
+		})
+	}
+}
+
+func TestGCStacks(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	dir := t.TempDir()
+	fs := newFileSink(dir, "gctest", true /* bufferedWrites */, 0, 0, nil, 0o644)
+	fs.logFilesMaxAge = time.Hour
+
+	now := timeutil.Now().Truncate(time.Second)
+	writeFile := func(name string, ts time.Time) {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte("contents"), 0o644))
+		require.NoError(t, os.Chtimes(path, ts, ts))
+	}
+	logFile, _ := fs.nameGenerator.logName(now)
+	writeFile(logFile, now)
+	recent := fs.nameGenerator.stacksName(now.Add(-time.Minute))
+	writeFile(recent, now.Add(-time.Minute))
+	old := now.Add(-2 * time.Hour)
+	writeFile(fs.nameGenerator.stacksName(old), old)
+	// The files of a group with a similar name are left alone.
+	otherGroup := makeFileNameGenerator("gctest-stacks")
+	otherLogFile, _ := otherGroup.logName(old)
+	writeFile(otherLogFile, old)
+	writeFile(otherGroup.stacksName(old), old)
+
+	// The stack dumps are not listed as log files.
+	_, files, err := fs.listLogFiles()
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Equal(t, logFile, files[0].Name)
+
+	// The expired stack dump is removed.
+	fs.gcOldFiles()
+	_, files, err = fs.listStacksFiles()
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Equal(t, recent, files[0].Name)
+	_, err = os.Stat(filepath.Join(dir, otherGroup.stacksName(old)))
+	require.NoError(t, err)
+}
+
 // succeedsSoon is a simplified version of testutils.SucceedsSoon.
 // The main implementation cannot be used here because of
 // an import cycle.
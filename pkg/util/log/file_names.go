@@ -121,6 +121,19 @@ func (g fileNameGenerator) logName(t time.Time) (name, link string) {
 	return name, g.fileNamePrefix + ".log"
 }
 
+// stacksFileSuffix is the extension of the files containing the
+// goroutine stack dumps of a file group. It differs from that of the
+// log files, so that the dumps are never mistaken for log files.
+const stacksFileSuffix = ".stacks"
+
+// stacksName returns a new name for a goroutine stack dump taken at
+// time t. It is the name of a log file started at the same time, with
+// a different extension.
+func (g fileNameGenerator) stacksName(t time.Time) string {
+	name, _ := g.logName(t)
+	return strings.TrimSuffix(name, ".log") + stacksFileSuffix
+}
+
 // ownsFileByPrefix returns true iff this generator is responsible
 // for generating file names starting with the given prefix.
 // For example, if the generator is for files named "cockroach-xx",
@@ -15,6 +15,7 @@ import (
 	"fmt"
 	"io/fs"
 	"math"
+	"path/filepath"
 
 	"github.com/cockroachdb/cockroach/pkg/util/log/channel"
 	"github.com/cockroachdb/cockroach/pkg/util/log/logconfig"
@@ -200,7 +201,7 @@ func ApplyConfig(config logconfig.Config) (logShutdownFn func(), err error) {
 			// it's going to be followed by junk printed by the go runtime.
 			false, /* redactable */
 			"stderr capture started")
-		secLogger.outputLogEntry(entry)
+		secLogger.outputLogEntry(secLoggersCtx, entry)
 
 		// Now tell this logger to capture internal stderr writes.
 		if err := fileSink.takeOverInternalStderr(secLogger); err != nil {
@@ -230,6 +231,15 @@ func ApplyConfig(config logconfig.Config) (logShutdownFn func(), err error) {
 		}
 	}
 
+	// Apply the stack trace configuration.
+	logging.stackTraces.applyConfig(config.StackTraces)
+
+	// Apply the rate limits.
+	logging.rateLimiter.applyConfig(config.RateLimits)
+
+	// Apply the budgets.
+	logging.budgets.applyConfig(config.Budgets)
+
 	// Apply the stderr sink configuration.
 	logging.stderrSink.noColor.Set(config.Sinks.Stderr.NoColor)
 	if err := logging.stderrSinkInfoTemplate.applyConfig(config.Sinks.Stderr.CommonSinkConfig); err != nil {
@@ -237,6 +247,16 @@ func ApplyConfig(config logconfig.Config) (logShutdownFn func(), err error) {
 	}
 	logging.stderrSinkInfoTemplate.applyFilters(config.Sinks.Stderr.Channels)
 
+	// The structured events of the channels selected by an exclusive
+	// events section are removed from the regular sinks.
+	var excludedEvents [logpb.Channel_CHANNEL_MAX]bool
+	if config.Events.Exclusive != nil && *config.Events.Exclusive {
+		for _, ch := range config.Events.Channels.Channels {
+			excludedEvents[ch] = true
+		}
+	}
+	logging.stderrSinkInfoTemplate.excludedEvents = excludedEvents
+
 	// Create the per-channel loggers.
 	chans := make(map[Channel]*loggerT, len(logpb.Channel_name)-1)
 	for chi := range logpb.Channel_name {
@@ -263,6 +283,9 @@ func ApplyConfig(config logconfig.Config) (logShutdownFn func(), err error) {
 	}
 
 	attachSinkInfo := func(si *sinkInfo, chs *logconfig.ChannelFilters) {
+		if !si.eventsOnly {
+			si.excludedEvents = excludedEvents
+		}
 		sinkInfos = append(sinkInfos, si)
 		logging.allSinkInfos.put(si)
 
@@ -273,17 +296,22 @@ func ApplyConfig(config logconfig.Config) (logShutdownFn func(), err error) {
 		}
 	}
 
-	// Create the file sinks.
-	for fileGroupName, fc := range config.Sinks.FileGroups {
+	addFileSink := func(fileGroupName string, fc *logconfig.FileSinkConfig, eventsOnly bool) error {
 		if fc.Filter == severity.NONE || fc.Dir == nil {
-			continue
+			return nil
 		}
+		fileSinkName := fileGroupName
 		if fileGroupName == "default" {
 			fileGroupName = ""
 		}
 		fileSinkInfo, fileSink, err := newFileSinkInfo(fileGroupName, *fc)
 		if err != nil {
-			return nil, err
+			return err
+		}
+		fileSinkInfo.name = fileSinkName
+		fileSinkInfo.eventsOnly = eventsOnly
+		if fc.AsyncWrites != nil && *fc.AsyncWrites {
+			fileSink.startAsyncWriter(closer)
 		}
 		attachBufferWrapper(fileSinkInfo, fc.CommonSinkConfig.Buffering, closer)
 		attachSinkInfo(fileSinkInfo, &fc.Channels)
@@ -291,40 +319,148 @@ func ApplyConfig(config logconfig.Config) (logShutdownFn func(), err error) {
 		// Start the GC process. This ensures that old capture files get
 		// erased as new files get created.
 		go fileSink.gcDaemon(secLoggersCtx)
+		return nil
 	}
 
-	// Create the fluent sinks.
-	for _, fc := range config.Sinks.FluentServers {
+	addFluentSink := func(sinkName string, fc *logconfig.FluentSinkConfig, eventsOnly bool) error {
 		if fc.Filter == severity.NONE {
-			continue
+			return nil
 		}
-		fluentSinkInfo, err := newFluentSinkInfo(*fc)
+		fluentSinkInfo, fluentSink, err := newFluentSinkInfo(*fc)
 		if err != nil {
-			return nil, err
+			return err
+		}
+		fluentSinkInfo.name = sinkName
+		fluentSinkInfo.eventsOnly = eventsOnly
+		if fluentSink.forward != nil {
+			fluentSink.startFlusher(closer)
+		}
+		if err := attachSpool(fluentSinkInfo, "fluent-"+sinkName, fc.Spool, closer); err != nil {
+			return err
 		}
 		attachBufferWrapper(fluentSinkInfo, fc.CommonSinkConfig.Buffering, closer)
 		attachSinkInfo(fluentSinkInfo, &fc.Channels)
+		return nil
 	}
 
-	// Create the HTTP sinks.
-	for _, fc := range config.Sinks.HTTPServers {
+	addHTTPSink := func(sinkName string, fc *logconfig.HTTPSinkConfig, eventsOnly bool) error {
 		if fc.Filter == severity.NONE {
-			continue
+			return nil
 		}
 		httpSinkInfo, err := newHTTPSinkInfo(*fc)
 		if err != nil {
-			return nil, err
+			return err
+		}
+		httpSinkInfo.name = sinkName
+		httpSinkInfo.eventsOnly = eventsOnly
+		if err := attachSpool(httpSinkInfo, "http-"+sinkName, fc.Spool, closer); err != nil {
+			return err
 		}
 		attachBufferWrapper(httpSinkInfo, fc.CommonSinkConfig.Buffering, closer)
 		attachSinkInfo(httpSinkInfo, &fc.Channels)
+		return nil
+	}
+
+	// Create the file sinks.
+	for fileGroupName, fc := range config.Sinks.FileGroups {
+		if err := addFileSink(fileGroupName, fc, false /* eventsOnly */); err != nil {
+			return nil, err
+		}
+	}
+
+	// Create the fluent sinks.
+	for sinkName, fc := range config.Sinks.FluentServers {
+		if err := addFluentSink(sinkName, fc, false /* eventsOnly */); err != nil {
+			return nil, err
+		}
+	}
+
+	// Create the HTTP sinks.
+	for sinkName, fc := range config.Sinks.HTTPServers {
+		if err := addHTTPSink(sinkName, fc, false /* eventsOnly */); err != nil {
+			return nil, err
+		}
+	}
+
+	// Create the sinks of the events section.
+	for fileGroupName, fc := range config.Events.Sinks.FileGroups {
+		if err := addFileSink(fileGroupName, fc, true /* eventsOnly */); err != nil {
+			return nil, err
+		}
+	}
+	for sinkName, fc := range config.Events.Sinks.FluentServers {
+		if err := addFluentSink(sinkName, fc, true /* eventsOnly */); err != nil {
+			return nil, err
+		}
+	}
+	for sinkName, fc := range config.Events.Sinks.HTTPServers {
+		if err := addHTTPSink(sinkName, fc, true /* eventsOnly */); err != nil {
+			return nil, err
+		}
+	}
+
+	// Create the syslog sinks.
+	for sinkName, fc := range config.Sinks.SyslogServers {
+		if fc.Filter == severity.NONE {
+			continue
+		}
+		syslogSinkInfo, err := newSyslogSinkInfo(*fc)
+		if err != nil {
+			return nil, err
+		}
+		syslogSinkInfo.name = sinkName
+		attachBufferWrapper(syslogSinkInfo, fc.CommonSinkConfig.Buffering, closer)
+		attachSinkInfo(syslogSinkInfo, &fc.Channels)
+	}
+
+	// Create the journald sinks.
+	for sinkName, fc := range config.Sinks.JournaldSinks {
+		if fc.Filter == severity.NONE {
+			continue
+		}
+		journaldSinkInfo, err := newJournaldSinkInfo(*fc)
+		if err != nil {
+			return nil, err
+		}
+		journaldSinkInfo.name = sinkName
+		attachSinkInfo(journaldSinkInfo, &fc.Channels)
+	}
+
+	// Create the Windows Event Log sinks.
+	for sinkName, fc := range config.Sinks.EventLogSinks {
+		if fc.Filter == severity.NONE {
+			continue
+		}
+		eventLogSinkInfo, eventLogSink, err := newEventLogSinkInfo(*fc)
+		if err != nil {
+			return nil, err
+		}
+		eventLogSinkInfo.name = sinkName
+		eventLogSink.closeOnShutdown(closer)
+		attachSinkInfo(eventLogSinkInfo, &fc.Channels)
+	}
+
+	// Create the count sinks.
+	for sinkName, fc := range config.Sinks.CountSinks {
+		if fc.Filter == severity.NONE {
+			continue
+		}
+		countSinkInfo, err := newCountSinkInfo(*fc)
+		if err != nil {
+			return nil, err
+		}
+		countSinkInfo.name = sinkName
+		attachSinkInfo(countSinkInfo, &fc.Channels)
 	}
 
 	// Prepend the interceptor sink to all channels.
 	// We prepend it because we want the interceptors
 	// to see every event before they make their way to disk/network.
 	interceptorSinkInfo := logging.newInterceptorSinkInfo()
+	tailSinkInfo := logging.newTailSinkInfo()
+	recentSinkInfo := logging.newRecentSinkInfo()
 	for _, l := range chans {
-		l.sinkInfos = append([]*sinkInfo{interceptorSinkInfo}, l.sinkInfos...)
+		l.sinkInfos = append([]*sinkInfo{interceptorSinkInfo, tailSinkInfo, recentSinkInfo}, l.sinkInfos...)
 	}
 
 	logging.setChannelLoggers(chans, &stderrSinkInfo)
@@ -338,7 +474,7 @@ func ApplyConfig(config logconfig.Config) (logShutdownFn func(), err error) {
 func newFileSinkInfo(
 	fileGroupName string, c logconfig.FileSinkConfig,
 ) (*sinkInfo, *fileSink, error) {
-	info := &sinkInfo{}
+	info := &sinkInfo{health: &sinkHealth{}}
 	if err := info.applyConfig(c.CommonSinkConfig); err != nil {
 		return nil, nil, err
 	}
@@ -352,25 +488,47 @@ func newFileSinkInfo(
 		info.getStartLines,
 		fs.FileMode(*c.FilePermissions),
 	)
+	if c.MaxAge != nil {
+		fileSink.logFilesMaxAge = *c.MaxAge
+	}
+	if c.MinFreeDisk != nil {
+		fileSink.minFreeDisk = int64(*c.MinFreeDisk)
+	}
+	if c.Compression != nil {
+		fileSink.compression = *c.Compression
+	}
 	info.sink = fileSink
 	return info, fileSink, nil
 }
 
 // newFluentSinkInfo creates a new fluentSink and its accompanying sinkInfo
 // from the provided configuration.
-func newFluentSinkInfo(c logconfig.FluentSinkConfig) (*sinkInfo, error) {
-	info := &sinkInfo{}
+func newFluentSinkInfo(c logconfig.FluentSinkConfig) (*sinkInfo, *fluentSink, error) {
+	info := &sinkInfo{health: &sinkHealth{}}
 	if err := info.applyConfig(c.CommonSinkConfig); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	info.applyFilters(c.Channels)
-	fluentSink := newFluentSink(c.Net, c.Address)
+	var forward *fluentForwardOptions
+	if c.IsForward() {
+		forward = &fluentForwardOptions{
+			compression:   *c.Compression,
+			batchSize:     *c.BatchSize,
+			flushInterval: *c.FlushInterval,
+			requireAck:    *c.RequireAck,
+		}
+	}
+	fluentSink, err := newFluentSink(c.Net, c.Address, forward)
+	if err != nil {
+		return nil, nil, err
+	}
+	fluentSink.health = info.health
 	info.sink = fluentSink
-	return info, nil
+	return info, fluentSink, nil
 }
 
 func newHTTPSinkInfo(c logconfig.HTTPSinkConfig) (*sinkInfo, error) {
-	info := &sinkInfo{}
+	info := &sinkInfo{health: &sinkHealth{}}
 
 	if err := info.applyConfig(c.CommonSinkConfig); err != nil {
 		return nil, err
@@ -385,6 +543,79 @@ func newHTTPSinkInfo(c logconfig.HTTPSinkConfig) (*sinkInfo, error) {
 	return info, nil
 }
 
+// newSyslogSinkInfo creates a new syslogSink and its accompanying
+// sinkInfo from the provided configuration.
+func newSyslogSinkInfo(c logconfig.SyslogSinkConfig) (*sinkInfo, error) {
+	info := &sinkInfo{health: &sinkHealth{}}
+	if err := info.applyConfig(c.CommonSinkConfig); err != nil {
+		return nil, err
+	}
+	info.applyFilters(c.Channels)
+	// Wrap the configured formatter to add the syslog header.
+	info.formatter = newSyslogFormatter(info.formatter, *c.Facility)
+	info.sink = newSyslogSink(c)
+	return info, nil
+}
+
+// newJournaldSinkInfo creates a new journaldSink and its accompanying
+// sinkInfo from the provided configuration.
+func newJournaldSinkInfo(c logconfig.JournaldSinkConfig) (*sinkInfo, error) {
+	info := &sinkInfo{health: &sinkHealth{}}
+	if err := info.applyConfig(c.CommonSinkConfig); err != nil {
+		return nil, err
+	}
+	info.applyFilters(c.Channels)
+	journaldSink, err := newJournaldSink(c)
+	if err != nil {
+		return nil, err
+	}
+	// Wrap the configured formatter to produce the fields of the
+	// journal entries.
+	info.formatter = newJournaldFormatter(info.formatter, *c.Identifier)
+	info.sink = journaldSink
+	return info, nil
+}
+
+// newEventLogSinkInfo creates a new eventLogSink and its accompanying
+// sinkInfo from the provided configuration.
+func newEventLogSinkInfo(c logconfig.EventLogSinkConfig) (*sinkInfo, *eventLogSink, error) {
+	info := &sinkInfo{health: &sinkHealth{}}
+	if err := info.applyConfig(c.CommonSinkConfig); err != nil {
+		return nil, nil, err
+	}
+	info.applyFilters(c.Channels)
+	eventLogSink, err := newEventLogSink(c)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Wrap the configured formatter to report the type and ID of the
+	// events.
+	info.formatter = eventLogFormatter{inner: info.formatter}
+	info.sink = eventLogSink
+	return info, eventLogSink, nil
+}
+
+// newCountSinkInfo creates a new countSink and its accompanying
+// sinkInfo from the provided configuration. The entries are not
+// persisted, so the redaction and formatting options do not apply:
+// the entries are formatted as the keys by which they are counted.
+func newCountSinkInfo(c logconfig.CountSinkConfig) (*sinkInfo, error) {
+	info := &sinkInfo{redactable: true}
+	info.threshold.setAll(severity.NONE)
+	info.editor = getEditor(SelectEditMode(false /* redact */, true /* keepRedactable */))
+	procs, err := lookupProcessors(c.Processors)
+	if err != nil {
+		return nil, err
+	}
+	info.processors = procs
+	info.processorNames = c.Processors
+	info.applyTenantFilter(c.TenantFilter)
+	info.applyFilters(c.Channels)
+	info.formatter = countFormatter{labels: c.Labels}
+	info.sink = newCountSink(c)
+	return info, nil
+}
+
 // applyFilters applies the channel filters to a sinkInfo.
 func (l *sinkInfo) applyFilters(chs logconfig.ChannelFilters) {
 	for ch, threshold := range chs.ChannelFilters {
@@ -408,10 +639,36 @@ func attachBufferWrapper(
 		uint64(*bufConfig.FlushTriggerSize),
 		uint64(*bufConfig.MaxBufferSize),
 		s.criticality /* crashOnAsyncFlushErr */)
+	bs.blockOnOverflow = bufConfig.Backpressure != nil &&
+		*bufConfig.Backpressure == logconfig.BackpressureBlock
+	bs.health = s.health
 	bs.Start(closer)
 	s.sink = bs
 }
 
+// attachSpool modifies s, wrapping its sink in a spoolSink if the spool
+// is enabled. The spool files are stored in a sub-directory of the
+// configured directory named after the sink.
+//
+// The provided closer needs to be closed to stop the spoolSink internal
+// goroutine.
+func attachSpool(
+	s *sinkInfo, subDir string, spoolConfig logconfig.SpoolConfig, closer *bufferedSinkCloser,
+) error {
+	if !spoolConfig.IsEnabled() {
+		return nil
+	}
+	ss, err := newSpoolSink(s.sink,
+		filepath.Join(*spoolConfig.Dir, subDir), int64(*spoolConfig.MaxSize))
+	if err != nil {
+		return err
+	}
+	ss.health = s.health
+	ss.Start(closer)
+	s.sink = ss
+	return nil
+}
+
 // applyConfig applies a common sink configuration to a sinkInfo.
 func (l *sinkInfo) applyConfig(c logconfig.CommonSinkConfig) error {
 	l.threshold.setAll(severity.NONE)
@@ -424,9 +681,52 @@ func (l *sinkInfo) applyConfig(c logconfig.CommonSinkConfig) error {
 		return errors.Newf("unknown format: %q", *c.Format)
 	}
 	l.formatter = f
+	timestamps, err := newTimestampOptions(c.Timestamps)
+	if err != nil {
+		return err
+	}
+	if _, ok := f.(timestampOptionsFormatter); timestamps != nil && !ok {
+		return errors.Newf("format %q does not support timestamp options", *c.Format)
+	}
+	l.timestamps = timestamps
+	procs, err := lookupProcessors(c.Processors)
+	if err != nil {
+		return err
+	}
+	l.processors = procs
+	l.processorNames = c.Processors
+	l.applyTenantFilter(c.TenantFilter)
+	l.goroutineTags = c.GoroutineTags != nil && *c.GoroutineTags
+	l.maxEntrySize = 0
+	if c.MaxEntrySize != nil {
+		l.maxEntrySize = int(*c.MaxEntrySize)
+	}
 	return nil
 }
 
+// systemTenantID is the tenant ID reported by the SQL servers of the
+// system tenant, when they report one.
+const systemTenantID = "1"
+
+// applyTenantFilter restricts a sinkInfo to the given tenants. An
+// empty filter accepts the entries of all the tenants.
+func (l *sinkInfo) applyTenantFilter(filter []string) {
+	l.tenantFilter = filter
+	l.tenants = nil
+	if len(filter) == 0 {
+		return
+	}
+	l.tenants = make(map[string]struct{}, len(filter))
+	for _, t := range filter {
+		if t == logconfig.SystemTenantFilter {
+			// The KV nodes do not report a tenant ID.
+			l.tenants[""] = struct{}{}
+			t = systemTenantID
+		}
+		l.tenants[t] = struct{}{}
+	}
+}
+
 // describeAppliedConfig reports a sinkInfo's configuration as a
 // CommonSinkConfig. Note that the returned config object
 // holds into the sinkInfo parameters by reference and thus should
@@ -437,6 +737,16 @@ func (l *sinkInfo) describeAppliedConfig() (c logconfig.CommonSinkConfig) {
 	c.Criticality = &l.criticality
 	f := l.formatter.formatterName()
 	c.Format = &f
+	c.Processors = l.processorNames
+	c.TenantFilter = l.tenantFilter
+	if l.goroutineTags {
+		c.GoroutineTags = &l.goroutineTags
+	}
+	c.Timestamps = l.timestamps.describe()
+	if l.maxEntrySize > 0 {
+		m := logconfig.ByteSize(l.maxEntrySize)
+		c.MaxEntrySize = &m
+	}
 	bufferedSink, ok := l.sink.(*bufferedSink)
 	if ok {
 		c.Buffering.MaxStaleness = &bufferedSink.maxStaleness
@@ -446,6 +756,10 @@ func (l *sinkInfo) describeAppliedConfig() (c logconfig.CommonSinkConfig) {
 		maxBufferSize := logconfig.ByteSize(bufferedSink.mu.buf.maxSizeBytes)
 		c.Buffering.MaxBufferSize = &maxBufferSize
 		bufferedSink.mu.Unlock()
+		if bufferedSink.blockOnOverflow {
+			bp := logconfig.BackpressureBlock
+			c.Buffering.Backpressure = &bp
+		}
 	}
 	return c
 }
@@ -476,6 +790,15 @@ func DescribeAppliedConfig() string {
 		config.CaptureFd2.MaxGroupSize = &m
 	}
 
+	// Describe the stack trace configuration.
+	config.StackTraces = logging.stackTraces.describeAppliedConfig()
+
+	// Describe the rate limits.
+	config.RateLimits = logging.rateLimiter.describeAppliedConfig()
+
+	// Describe the budgets.
+	config.Budgets = logging.budgets.describeAppliedConfig()
+
 	// Describe the stderr sink.
 	config.Sinks.Stderr.NoColor = logging.stderrSink.noColor.Get()
 	config.Sinks.Stderr.CommonSinkConfig = logging.stderrSinkInfoTemplate.describeAppliedConfig()
@@ -503,6 +826,7 @@ func DescribeAppliedConfig() string {
 
 	// Describe the file sinks.
 	config.Sinks.FileGroups = make(map[string]*logconfig.FileSinkConfig)
+	config.Events.Sinks.FileGroups = make(map[string]*logconfig.FileSinkConfig)
 	_ = logging.allSinkInfos.iter(func(l *sinkInfo) error {
 		if cl := logging.testingFd2CaptureLogger; cl != nil && cl.sinkInfos[0] == l {
 			// Not a real sink. Omit.
@@ -519,11 +843,27 @@ func DescribeAppliedConfig() string {
 		fc.MaxFileSize = &mf
 		mg := logconfig.ByteSize(fileSink.logFilesCombinedMaxSize)
 		fc.MaxGroupSize = &mg
+		if fileSink.logFilesMaxAge != 0 {
+			ma := fileSink.logFilesMaxAge
+			fc.MaxAge = &ma
+		}
+		if fileSink.minFreeDisk != 0 {
+			mfd := logconfig.ByteSize(fileSink.minFreeDisk)
+			fc.MinFreeDisk = &mfd
+		}
+		if fileSink.compression != "" {
+			c := fileSink.compression
+			fc.Compression = &c
+		}
 		fileSink.mu.Lock()
 		dir := fileSink.mu.logDir
 		fileSink.mu.Unlock()
 		fc.Dir = &dir
 		fc.BufferedWrites = &fileSink.bufferedWrites
+		if fileSink.async != nil {
+			bt := true
+			fc.AsyncWrites = &bt
+		}
 
 		// Describe the connections to this file sink.
 		for ch, logger := range chans {
@@ -534,23 +874,34 @@ func DescribeAppliedConfig() string {
 		if prefix == "" {
 			prefix = "default"
 		}
-		if prev, ok := config.Sinks.FileGroups[prefix]; ok {
+		fileGroups := config.Sinks.FileGroups
+		if l.eventsOnly {
+			fileGroups = config.Events.Sinks.FileGroups
+		}
+		if prev, ok := fileGroups[prefix]; ok {
 			fmt.Fprintf(OrigStderr,
 				"warning: multiple file loggers with prefix %q, previous: %+v\n",
 				prefix, prev)
 		}
-		config.Sinks.FileGroups[prefix] = fc
+		fileGroups[prefix] = fc
 		return nil
 	})
 
 	// Describe the fluent sinks.
 	config.Sinks.FluentServers = make(map[string]*logconfig.FluentSinkConfig)
+	config.Events.Sinks.FluentServers = make(map[string]*logconfig.FluentSinkConfig)
 	sIdx := 1
 	_ = logging.allSinkInfos.iter(func(l *sinkInfo) error {
-		flSink, ok := l.sink.(*fluentSink)
+		s := l.sink
+		// Check to see if it's a fluentSink wrapped in a spoolSink.
+		spool, spooled := s.(*spoolSink)
+		if spooled {
+			s = spool.child
+		}
+		flSink, ok := s.(*fluentSink)
 		if !ok {
 			// Check to see if it's a fluentSink wrapped in a bufferedSink.
-			bufferedSink, ok := l.sink.(*bufferedSink)
+			bufferedSink, ok := s.(*bufferedSink)
 			if !ok {
 				return nil
 			}
@@ -562,8 +913,21 @@ func DescribeAppliedConfig() string {
 
 		fc := &logconfig.FluentSinkConfig{}
 		fc.CommonSinkConfig = l.describeAppliedConfig()
+		if spooled {
+			dir := filepath.Dir(spool.dir)
+			maxSize := logconfig.ByteSize(spool.maxSize)
+			fc.Spool = logconfig.SpoolConfig{Dir: &dir, MaxSize: &maxSize}
+		}
 		fc.Net = flSink.network
 		fc.Address = flSink.addr
+		if f := flSink.forward; f != nil {
+			protocol := logconfig.FluentProtocolForward
+			fc.Protocol = &protocol
+			fc.Compression = &f.compression
+			fc.BatchSize = &f.batchSize
+			fc.FlushInterval = &f.flushInterval
+			fc.RequireAck = &f.requireAck
+		}
 
 		// Describe the connections to this fluent sink.
 		for ch, logger := range chans {
@@ -571,18 +935,27 @@ func DescribeAppliedConfig() string {
 		}
 		skey := fmt.Sprintf("s%d", sIdx)
 		sIdx++
-		config.Sinks.FluentServers[skey] = fc
+		if l.eventsOnly {
+			config.Events.Sinks.FluentServers[skey] = fc
+		} else {
+			config.Sinks.FluentServers[skey] = fc
+		}
 		return nil
 	})
 
 	// Describe the http sinks.
 	config.Sinks.HTTPServers = make(map[string]*logconfig.HTTPSinkConfig)
+	config.Events.Sinks.HTTPServers = make(map[string]*logconfig.HTTPSinkConfig)
 	sIdx = 1
 	_ = logging.allSinkInfos.iter(func(l *sinkInfo) error {
-		netSink, ok := l.sink.(*httpSink)
+		s := l.sink
+		if spool, ok := s.(*spoolSink); ok {
+			s = spool.child
+		}
+		netSink, ok := s.(*httpSink)
 		if !ok {
 			// Check to see if it's a httpSink wrapped in a bufferedSink.
-			bufferedSink, ok := l.sink.(*bufferedSink)
+			bufferedSink, ok := s.(*bufferedSink)
 			if !ok {
 				return nil
 			}
@@ -593,10 +966,82 @@ func DescribeAppliedConfig() string {
 		}
 		skey := fmt.Sprintf("s%d", sIdx)
 		sIdx++
-		config.Sinks.HTTPServers[skey] = netSink.config
+		if l.eventsOnly {
+			config.Events.Sinks.HTTPServers[skey] = netSink.config
+		} else {
+			config.Sinks.HTTPServers[skey] = netSink.config
+		}
 		return nil
 	})
 
+	// Describe the syslog sinks.
+	config.Sinks.SyslogServers = make(map[string]*logconfig.SyslogSinkConfig)
+	sIdx = 1
+	_ = logging.allSinkInfos.iter(func(l *sinkInfo) error {
+		netSink, ok := l.sink.(*syslogSink)
+		if !ok {
+			// Check to see if it's a syslogSink wrapped in a bufferedSink.
+			bufferedSink, ok := l.sink.(*bufferedSink)
+			if !ok {
+				return nil
+			}
+			netSink, ok = bufferedSink.child.(*syslogSink)
+			if !ok {
+				return nil
+			}
+		}
+		skey := fmt.Sprintf("s%d", sIdx)
+		sIdx++
+		config.Sinks.SyslogServers[skey] = netSink.config
+		return nil
+	})
+
+	// Describe the journald sinks.
+	config.Sinks.JournaldSinks = make(map[string]*logconfig.JournaldSinkConfig)
+	_ = logging.allSinkInfos.iter(func(l *sinkInfo) error {
+		if journaldSink, ok := l.sink.(*journaldSink); ok {
+			config.Sinks.JournaldSinks[l.name] = journaldSink.config
+		}
+		return nil
+	})
+
+	// Describe the Windows Event Log sinks.
+	config.Sinks.EventLogSinks = make(map[string]*logconfig.EventLogSinkConfig)
+	_ = logging.allSinkInfos.iter(func(l *sinkInfo) error {
+		if eventLogSink, ok := l.sink.(*eventLogSink); ok {
+			config.Sinks.EventLogSinks[l.name] = eventLogSink.config
+		}
+		return nil
+	})
+
+	// Describe the count sinks.
+	config.Sinks.CountSinks = make(map[string]*logconfig.CountSinkConfig)
+	_ = logging.allSinkInfos.iter(func(l *sinkInfo) error {
+		if countSink, ok := l.sink.(*countSink); ok {
+			config.Sinks.CountSinks[l.name] = countSink.config
+		}
+		return nil
+	})
+
+	// Describe the channels of the events section, and whether it is
+	// exclusive.
+	if config.Events.IsEnabled() {
+		for ch, logger := range chans {
+			for _, s := range logger.sinkInfos {
+				if s.eventsOnly {
+					config.Events.Channels.Channels = append(config.Events.Channels.Channels, ch)
+					break
+				}
+			}
+		}
+		config.Events.Channels.Sort()
+		exclusive := false
+		for _, ch := range config.Events.Channels.Channels {
+			exclusive = exclusive || stderrSinkInfo.excludedEvents[ch]
+		}
+		config.Events.Exclusive = &exclusive
+	}
+
 	// Note: we cannot return 'config' directly, because this captures
 	// certain variables from the loggers by reference and thus could be
 	// invalidated by concurrent uses of ApplyConfig().
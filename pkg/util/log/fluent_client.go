@@ -16,9 +16,11 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/cli/exit"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logconfig"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
+	"github.com/klauspost/compress/zstd"
 )
 
 // fluentSink represents a Fluentd-compatible network collector.
@@ -27,23 +29,48 @@ type fluentSink struct {
 	network string
 	addr    string
 
+	// forward is set when the log entries are sent in batches with the
+	// Fluentd Forward protocol, instead of one JSON object at a time.
+	forward *fluentForwardOptions
+	// zstdEncoder compresses the batches of log entries, if the forward
+	// protocol is used with zstd compression.
+	zstdEncoder *zstd.Encoder
+
+	// health, if set, is where the delivery failures of the batches sent
+	// in the background are reported.
+	health *sinkHealth
+
 	mu struct {
 		syncutil.RWMutex
 		// good indicates that the connection can be used.
 		good bool
 		conn net.Conn
+
+		// batches holds the log entries not yet sent with the forward
+		// protocol, by tag.
+		batches map[string]*fluentBatch
+		// stopped is set when the flusher goroutine has terminated. The
+		// log entries are sent as soon as they are output from then on.
+		stopped bool
 	}
 }
 
 const fluentDialTimeout = 5 * time.Second
 const fluentWriteTimeout = time.Second
 
-func newFluentSink(network, addr string) *fluentSink {
+func newFluentSink(network, addr string, forward *fluentForwardOptions) (*fluentSink, error) {
 	f := &fluentSink{
 		addr:    addr,
 		network: network,
+		forward: forward,
+	}
+	if forward != nil && forward.compression == logconfig.FluentCompressionZstd {
+		var err error
+		if f.zstdEncoder, err = newFluentZstdEncoder(); err != nil {
+			return nil, err
+		}
 	}
-	return f
+	return f, nil
 }
 
 func (l *fluentSink) String() string {
@@ -53,6 +80,11 @@ func (l *fluentSink) String() string {
 // activeAtSeverity implements the logSink interface.
 func (l *fluentSink) active() bool { return true }
 
+// probe implements the sinkProber interface.
+func (l *fluentSink) probe() error {
+	return probeDial(l.network, l.addr, nil /* tlsConf */)
+}
+
 // attachHints implements the logSink interface.
 func (l *fluentSink) attachHints(stacks []byte) []byte {
 	return stacks
@@ -65,6 +97,9 @@ func (l *fluentSink) exitCode() exit.Code {
 
 // output implements the logSink interface.
 func (l *fluentSink) output(b []byte, opts sinkOutputOptions) (err error) {
+	if l.forward != nil {
+		return l.outputForward(b, opts)
+	}
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	// Try to write and reconnect immediately if the first write fails.
@@ -12,8 +12,15 @@ package log
 
 import (
 	"bufio"
+	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/binary"
 	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
 	"net"
 	"sync"
 	"testing"
@@ -23,7 +30,10 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log/channel"
 	"github.com/cockroachdb/cockroach/pkg/util/log/logconfig"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/require"
 )
 
@@ -180,3 +190,317 @@ func servePseudoFluent(t *testing.T) (serverAddr string, cleanup func(), fluentD
 	}
 	return l.Addr().String(), cleanup, fluentData
 }
+
+func TestFluentClientForward(t *testing.T) {
+	defer build.TestingOverrideTag("v999.0.0")()
+
+	defer leaktest.AfterTest(t)()
+	sc := ScopeWithoutShowLogs(t)
+	defer sc.Close(t)
+
+	for _, compression := range []logconfig.FluentCompression{
+		logconfig.FluentCompressionNone,
+		logconfig.FluentCompressionGzip,
+		logconfig.FluentCompressionZstd,
+	} {
+		t.Run(string(compression), func(t *testing.T) {
+			serverAddr, cleanup, messages := servePseudoFluentForward(t)
+			defer cleanup()
+
+			// The entries are only sent in batches of two: the flush
+			// interval is never reached during the test.
+			cfg := logconfig.DefaultConfig()
+			protocol := logconfig.FluentProtocolForward
+			batchSize := 2
+			flushInterval := time.Hour
+			requireAck := true
+			cfg.Sinks.FluentServers = map[string]*logconfig.FluentSinkConfig{
+				"ops": {
+					Address:  serverAddr,
+					Channels: logconfig.SelectChannels(channel.OPS),
+					FluentDefaults: logconfig.FluentDefaults{
+						Protocol:      &protocol,
+						Compression:   &compression,
+						BatchSize:     &batchSize,
+						FlushInterval: &flushInterval,
+						RequireAck:    &requireAck,
+					},
+				},
+			}
+			require.NoError(t, cfg.Validate(&sc.logDir))
+
+			TestingResetActive()
+			cleanup, err := ApplyConfig(cfg)
+			require.NoError(t, err)
+			defer cleanup()
+
+			Ops.Infof(context.Background(), "hello")
+			select {
+			case msg := <-messages:
+				t.Fatalf("unexpected message before the batch is full: %+v", msg)
+			case <-time.After(100 * time.Millisecond):
+			}
+			Ops.Infof(context.Background(), "world")
+
+			var msg fluentForwardMessage
+			select {
+			case <-time.After(5 * time.Second):
+				t.Fatal("timeout")
+			case msg = <-messages:
+			}
+			require.Equal(t, "logtest.ops", msg.tag)
+			require.Equal(t, int64(2), msg.options["size"])
+			if compression != logconfig.FluentCompressionNone {
+				require.Equal(t, string(compression), msg.options["compressed"])
+			}
+			require.NotEmpty(t, msg.options["chunk"])
+			require.Len(t, msg.records, 2)
+			for i, expected := range []string{"hello", "world"} {
+				require.Equal(t, expected, msg.records[i]["message"])
+				require.Equal(t, "I", msg.records[i]["sev"])
+				require.NotContains(t, msg.records[i], "tag")
+				require.False(t, msg.times[i].IsZero())
+			}
+		})
+	}
+}
+
+// fluentForwardMessage is a decoded message of the Fluentd Forward
+// protocol in PackedForward or CompressedPackedForward mode.
+type fluentForwardMessage struct {
+	tag     string
+	times   []time.Time
+	records []map[string]interface{}
+	options map[string]interface{}
+}
+
+// servePseudoFluentForward creates an in-memory TCP listener which
+// accepts messages of the Fluentd Forward protocol, acknowledges them
+// when requested, and reports them over the returned channel.
+func servePseudoFluentForward(
+	t *testing.T,
+) (serverAddr string, cleanup func(), messages chan fluentForwardMessage) {
+	l, err := net.ListenTCP("tcp", nil)
+	require.NoError(t, err)
+
+	messages = make(chan fluentForwardMessage, 1)
+
+	var wg sync.WaitGroup
+	var mu syncutil.Mutex
+	var conns []net.Conn
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				t.Logf("accept error: %v", err)
+				return
+			}
+			mu.Lock()
+			conns = append(conns, conn)
+			mu.Unlock()
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				r := bufio.NewReader(conn)
+				for {
+					msg, err := readFluentForwardMessage(r)
+					if err != nil {
+						t.Logf("read error: %v", err)
+						return
+					}
+					if chunk, ok := msg.options["chunk"].(string); ok {
+						ack := appendMsgpackMapHeader(nil, 1)
+						ack = appendMsgpackString(ack, "ack")
+						ack = appendMsgpackString(ack, chunk)
+						if _, err := conn.Write(ack); err != nil {
+							t.Logf("write error: %v", err)
+							return
+						}
+					}
+					messages <- msg
+				}
+			}()
+		}
+	}()
+	cleanup = func() {
+		// Close the listen socket and the connections. This breaks the
+		// calls to Accept() and the reads of the servers.
+		require.NoError(t, l.Close())
+		mu.Lock()
+		for _, conn := range conns {
+			_ = conn.Close()
+		}
+		mu.Unlock()
+		wg.Wait()
+	}
+	return l.Addr().String(), cleanup, messages
+}
+
+// readFluentForwardMessage reads one message of the forward protocol.
+func readFluentForwardMessage(r *bufio.Reader) (msg fluentForwardMessage, _ error) {
+	v, err := readMsgpackValue(r)
+	if err != nil {
+		return msg, err
+	}
+	a, ok := v.([]interface{})
+	if !ok || len(a) != 3 {
+		return msg, errors.Newf("unexpected message: %v", v)
+	}
+	msg.tag, _ = a[0].(string)
+	entries, _ := a[1].([]byte)
+	msg.options, _ = a[2].(map[string]interface{})
+	switch msg.options["compressed"] {
+	case "gzip":
+		zr, err := gzip.NewReader(bytes.NewReader(entries))
+		if err != nil {
+			return msg, err
+		}
+		if entries, err = ioutil.ReadAll(zr); err != nil {
+			return msg, err
+		}
+	case "zstd":
+		zr, err := zstd.NewReader(nil)
+		if err != nil {
+			return msg, err
+		}
+		defer zr.Close()
+		if entries, err = zr.DecodeAll(entries, nil); err != nil {
+			return msg, err
+		}
+	}
+	er := bufio.NewReader(bytes.NewReader(entries))
+	for {
+		if _, err := er.Peek(1); err == io.EOF {
+			return msg, nil
+		}
+		v, err := readMsgpackValue(er)
+		if err != nil {
+			return msg, err
+		}
+		e, ok := v.([]interface{})
+		if !ok || len(e) != 2 {
+			return msg, errors.Newf("unexpected entry: %v", v)
+		}
+		ts, _ := e[0].(time.Time)
+		record, _ := e[1].(map[string]interface{})
+		msg.times = append(msg.times, ts)
+		msg.records = append(msg.records, record)
+	}
+}
+
+// readMsgpackValue decodes the subset of msgpack produced by the fluent
+// sink. EventTime values are decoded as time.Time.
+func readMsgpackValue(r *bufio.Reader) (interface{}, error) {
+	c, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	readN := func(n int) ([]byte, error) {
+		b := make([]byte, n)
+		_, err := io.ReadFull(r, b)
+		return b, err
+	}
+	readLen := func(size int) (int, error) {
+		b, err := readN(size)
+		if err != nil {
+			return 0, err
+		}
+		n := 0
+		for _, c := range b {
+			n = n<<8 | int(c)
+		}
+		return n, nil
+	}
+	readArray := func(n int) (interface{}, error) {
+		a := make([]interface{}, n)
+		for i := range a {
+			if a[i], err = readMsgpackValue(r); err != nil {
+				return nil, err
+			}
+		}
+		return a, nil
+	}
+	readMap := func(n int) (interface{}, error) {
+		m := make(map[string]interface{}, n)
+		for i := 0; i < n; i++ {
+			k, err := readMsgpackValue(r)
+			if err != nil {
+				return nil, err
+			}
+			if m[fmt.Sprint(k)], err = readMsgpackValue(r); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	}
+	switch {
+	case c < 0x80:
+		return int64(c), nil
+	case c >= 0xe0:
+		return int64(int8(c)), nil
+	case c&0xf0 == 0x80:
+		return readMap(int(c & 0x0f))
+	case c&0xf0 == 0x90:
+		return readArray(int(c & 0x0f))
+	case c&0xe0 == 0xa0:
+		b, err := readN(int(c & 0x1f))
+		return string(b), err
+	}
+	var n int
+	switch c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6:
+		if n, err = readLen(1 << (c - 0xc4)); err != nil {
+			return nil, err
+		}
+		return readN(n)
+	case 0xcb:
+		b, err := readN(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+	case 0xd3:
+		b, err := readN(8)
+		if err != nil {
+			return nil, err
+		}
+		return int64(binary.BigEndian.Uint64(b)), nil
+	case 0xd7:
+		b, err := readN(9)
+		if err != nil {
+			return nil, err
+		}
+		if b[0] != 0 {
+			return nil, errors.Newf("unexpected extension type %d", b[0])
+		}
+		return timeutil.Unix(
+			int64(binary.BigEndian.Uint32(b[1:5])), int64(binary.BigEndian.Uint32(b[5:9])),
+		), nil
+	case 0xd9, 0xda, 0xdb:
+		if n, err = readLen(1 << (c - 0xd9)); err != nil {
+			return nil, err
+		}
+		b, err := readN(n)
+		return string(b), err
+	case 0xdc, 0xdd:
+		if n, err = readLen(2 << (c - 0xdc)); err != nil {
+			return nil, err
+		}
+		return readArray(n)
+	case 0xde, 0xdf:
+		if n, err = readLen(2 << (c - 0xde)); err != nil {
+			return nil, err
+		}
+		return readMap(n)
+	}
+	return nil, errors.Newf("unexpected msgpack type 0x%x", c)
+}
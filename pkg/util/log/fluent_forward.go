@@ -0,0 +1,526 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/log/logconfig"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/klauspost/compress/zstd"
+)
+
+// fluentAckTimeout is the maximum amount of time that a fluent sink
+// waits for the collector to acknowledge a message of the forward
+// protocol.
+const fluentAckTimeout = 5 * time.Second
+
+// fluentForwardOptions configures a fluentSink to send the log entries
+// in batches with the Fluentd Forward protocol, in PackedForward or
+// CompressedPackedForward mode. See:
+// https://github.com/fluent/fluentd/wiki/Forward-Protocol-Specification-v1
+type fluentForwardOptions struct {
+	compression   logconfig.FluentCompression
+	batchSize     int
+	flushInterval time.Duration
+	requireAck    bool
+}
+
+// fluentBatch is a batch of log entries which share the same tag,
+// encoded as a msgpack stream of [time, record] entries.
+type fluentBatch struct {
+	entries []byte
+	n       int
+}
+
+// startFlusher starts a goroutine which sends the batches of log
+// entries of l at the configured flush interval, until the provided
+// closer is closed. From then on, the log entries are sent as soon as
+// they are output.
+func (l *fluentSink) startFlusher(closer *bufferedSinkCloser) {
+	stopC, unregister := closer.registerFluentSink(l)
+	go func() {
+		defer unregister()
+		ticker := time.NewTicker(l.forward.flushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				l.mu.Lock()
+				err := l.flushBatchesLocked()
+				l.mu.Unlock()
+				if err != nil {
+					l.health.recordFailure(err)
+				}
+			case <-stopC:
+				l.mu.Lock()
+				defer l.mu.Unlock()
+				l.mu.stopped = true
+				if err := l.flushBatchesLocked(); err != nil {
+					l.health.recordFailure(err)
+				}
+				return
+			}
+		}
+	}()
+}
+
+// outputForward adds the log entries in b, which are separated by
+// newlines, to the batches of l, and sends the batches when one of
+// them is full or when a flush is requested.
+func (l *fluentSink) outputForward(b []byte, opts sinkOutputOptions) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.mu.batches == nil {
+		l.mu.batches = make(map[string]*fluentBatch)
+	}
+	full := false
+	for len(b) > 0 {
+		var line []byte
+		if i := bytes.IndexByte(b, '\n'); i >= 0 {
+			line, b = b[:i], b[i+1:]
+		} else {
+			line, b = b, nil
+		}
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		tag, entry, err := encodeFluentEntry(line)
+		if err != nil {
+			fmt.Fprintf(OrigStderr, "%s: unable to encode log entry: %v\n%s\n", l, err, line)
+			return err
+		}
+		batch := l.mu.batches[tag]
+		if batch == nil {
+			batch = &fluentBatch{}
+			l.mu.batches[tag] = batch
+		}
+		batch.entries = append(batch.entries, entry...)
+		batch.n++
+		full = full || batch.n >= l.forward.batchSize
+	}
+	if full || opts.extraFlush || opts.forceSync || l.mu.stopped || logging.flushWrites.Get() {
+		return l.flushBatchesLocked()
+	}
+	return nil
+}
+
+// flushBatchesLocked sends all the batches of l, and returns the first
+// error encountered. The batches are discarded even if they could not
+// be sent.
+//
+// l.mu is held.
+func (l *fluentSink) flushBatchesLocked() error {
+	tags := make([]string, 0, len(l.mu.batches))
+	for tag := range l.mu.batches {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	var firstErr error
+	for _, tag := range tags {
+		batch := l.mu.batches[tag]
+		delete(l.mu.batches, tag)
+		if err := l.sendBatchLocked(tag, batch); err != nil {
+			fmt.Fprintf(OrigStderr, "%s: %d log entries dropped\n", l, batch.n)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+// sendBatchLocked sends one batch of log entries, reconnecting once if
+// needed.
+//
+// l.mu is held.
+func (l *fluentSink) sendBatchLocked(tag string, batch *fluentBatch) error {
+	msg, chunk, err := l.makeForwardMessage(tag, batch)
+	if err != nil {
+		fmt.Fprintf(OrigStderr, "%s: unable to encode log entries: %v\n", l, err)
+		return err
+	}
+	if err := l.tryForwardLocked(msg, chunk); err == nil {
+		return nil
+	}
+	if err := l.ensureConnLocked(nil); err != nil {
+		return err
+	}
+	return l.tryForwardLocked(msg, chunk)
+}
+
+// makeForwardMessage encodes a batch of log entries as a message of
+// the forward protocol in PackedForward mode, or CompressedPackedForward
+// mode if compression is configured. If acknowledgments are required,
+// the chunk ID of the message is also returned.
+func (l *fluentSink) makeForwardMessage(
+	tag string, batch *fluentBatch,
+) (msg []byte, chunk string, _ error) {
+	entries := batch.entries
+	switch l.forward.compression {
+	case logconfig.FluentCompressionGzip:
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write(entries); err != nil {
+			return nil, "", err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, "", err
+		}
+		entries = buf.Bytes()
+	case logconfig.FluentCompressionZstd:
+		entries = l.zstdEncoder.EncodeAll(entries, nil)
+	}
+
+	options := 1
+	compressed := l.forward.compression != "" && l.forward.compression != logconfig.FluentCompressionNone
+	if compressed {
+		options++
+	}
+	if l.forward.requireAck {
+		var id [16]byte
+		if _, err := rand.Read(id[:]); err != nil {
+			return nil, "", err
+		}
+		chunk = base64.StdEncoding.EncodeToString(id[:])
+		options++
+	}
+
+	msg = make([]byte, 0, len(entries)+len(tag)+64)
+	msg = appendMsgpackArrayHeader(msg, 3)
+	msg = appendMsgpackString(msg, tag)
+	msg = appendMsgpackBin(msg, entries)
+	msg = appendMsgpackMapHeader(msg, options)
+	msg = appendMsgpackString(msg, "size")
+	msg = appendMsgpackInt(msg, int64(batch.n))
+	if compressed {
+		msg = appendMsgpackString(msg, "compressed")
+		msg = appendMsgpackString(msg, string(l.forward.compression))
+	}
+	if chunk != "" {
+		msg = appendMsgpackString(msg, "chunk")
+		msg = appendMsgpackString(msg, chunk)
+	}
+	return msg, chunk, nil
+}
+
+// tryForwardLocked writes one message of the forward protocol, and
+// waits for the collector to acknowledge it if chunk is set.
+//
+// l.mu is held.
+func (l *fluentSink) tryForwardLocked(msg []byte, chunk string) error {
+	if !l.mu.good {
+		return errNoConn
+	}
+	if err := l.mu.conn.SetWriteDeadline(timeutil.Now().Add(fluentWriteTimeout)); err != nil {
+		// An error here is suggestive of a bug in the Go runtime.
+		fmt.Fprintf(OrigStderr, "%s: set write deadline error: %v\n", l, err)
+		l.mu.good = false
+		return err
+	}
+	n, err := l.mu.conn.Write(msg)
+	if err != nil || n < len(msg) {
+		fmt.Fprintf(OrigStderr, "%s: logging error: %v or short write (%d/%d)\n",
+			l, err, n, len(msg))
+		l.mu.good = false
+		if err == nil {
+			err = io.ErrShortWrite
+		}
+		return err
+	}
+	if chunk == "" {
+		return nil
+	}
+	if err := l.mu.conn.SetReadDeadline(timeutil.Now().Add(fluentAckTimeout)); err != nil {
+		fmt.Fprintf(OrigStderr, "%s: set read deadline error: %v\n", l, err)
+		l.mu.good = false
+		return err
+	}
+	ack, err := readFluentAck(l.mu.conn)
+	if err == nil && ack != chunk {
+		err = errors.Newf("unexpected acknowledgment %q, expected %q", ack, chunk)
+	}
+	if err != nil {
+		fmt.Fprintf(OrigStderr, "%s: acknowledgment error: %v\n", l, err)
+		// The state of the connection is unknown: the acknowledgment may
+		// still be in flight.
+		l.closeLocked()
+		return err
+	}
+	return nil
+}
+
+// encodeFluentEntry converts a log entry formatted as a JSON object by
+// one of the json-fluent formats into the tag of the entry and a
+// msgpack-encoded [time, record] entry of the forward protocol. The
+// tag is removed from the record, which otherwise contains all the
+// fields of the JSON object.
+func encodeFluentEntry(line []byte) (tag string, entry []byte, _ error) {
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+	var record map[string]interface{}
+	if err := dec.Decode(&record); err != nil {
+		return "", nil, err
+	}
+	tag, _ = record["tag"].(string)
+	if tag == "" {
+		return "", nil, errors.New("missing tag")
+	}
+	delete(record, "tag")
+
+	entry = appendMsgpackArrayHeader(nil, 2)
+	entry = appendMsgpackEventTime(entry, fluentEntryTime(record))
+	entry = appendMsgpackValue(entry, record)
+	return tag, entry, nil
+}
+
+// fluentEntryTime extracts the time of a log entry from its timestamp
+// field, formatted by the JSON formats as a number of seconds and
+// nanoseconds since the Unix epoch, separated by a period. The current
+// time is returned if the timestamp cannot be parsed.
+func fluentEntryTime(record map[string]interface{}) time.Time {
+	for _, key := range []string{"t", "timestamp"} {
+		s, ok := record[key].(string)
+		if !ok {
+			continue
+		}
+		i := strings.IndexByte(s, '.')
+		if i < 0 {
+			continue
+		}
+		sec, err1 := strconv.ParseInt(s[:i], 10, 64)
+		nsec, err2 := strconv.ParseInt(s[i+1:], 10, 64)
+		if err1 == nil && err2 == nil {
+			return timeutil.Unix(sec, nsec)
+		}
+	}
+	return timeutil.Now()
+}
+
+// readFluentAck reads the response of the collector to a message of
+// the forward protocol, a msgpack map whose "ack" key holds the chunk
+// ID of the acknowledged message.
+func readFluentAck(r io.Reader) (string, error) {
+	var hdr [1]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return "", err
+	}
+	var n int
+	switch {
+	case hdr[0]&0xf0 == 0x80:
+		n = int(hdr[0] & 0x0f)
+	case hdr[0] == 0xde:
+		var l [2]byte
+		if _, err := io.ReadFull(r, l[:]); err != nil {
+			return "", err
+		}
+		n = int(binary.BigEndian.Uint16(l[:]))
+	default:
+		return "", errors.Newf("unexpected response type 0x%x", hdr[0])
+	}
+	var ack string
+	for i := 0; i < n; i++ {
+		key, err := readMsgpackString(r)
+		if err != nil {
+			return "", err
+		}
+		val, err := readMsgpackString(r)
+		if err != nil {
+			return "", err
+		}
+		if key == "ack" {
+			ack = val
+		}
+	}
+	return ack, nil
+}
+
+// readMsgpackString reads a msgpack string.
+func readMsgpackString(r io.Reader) (string, error) {
+	var hdr [1]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return "", err
+	}
+	var n int
+	switch {
+	case hdr[0]&0xe0 == 0xa0:
+		n = int(hdr[0] & 0x1f)
+	case hdr[0] == 0xd9:
+		var l [1]byte
+		if _, err := io.ReadFull(r, l[:]); err != nil {
+			return "", err
+		}
+		n = int(l[0])
+	case hdr[0] == 0xda:
+		var l [2]byte
+		if _, err := io.ReadFull(r, l[:]); err != nil {
+			return "", err
+		}
+		n = int(binary.BigEndian.Uint16(l[:]))
+	default:
+		return "", errors.Newf("unexpected string type 0x%x", hdr[0])
+	}
+	s := make([]byte, n)
+	if _, err := io.ReadFull(r, s); err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+// The functions below append the msgpack encoding of values to a
+// buffer. Only the subset of msgpack needed by the forward protocol is
+// supported. See: https://github.com/msgpack/msgpack/blob/master/spec.md
+
+func appendMsgpackArrayHeader(b []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(b, 0x90|byte(n))
+	case n <= math.MaxUint16:
+		return append(b, 0xdc, byte(n>>8), byte(n))
+	default:
+		return append(b, 0xdd, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	}
+}
+
+func appendMsgpackMapHeader(b []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(b, 0x80|byte(n))
+	case n <= math.MaxUint16:
+		return append(b, 0xde, byte(n>>8), byte(n))
+	default:
+		return append(b, 0xdf, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	}
+}
+
+func appendMsgpackString(b []byte, s string) []byte {
+	n := len(s)
+	switch {
+	case n < 32:
+		b = append(b, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		b = append(b, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		b = append(b, 0xda, byte(n>>8), byte(n))
+	default:
+		b = append(b, 0xdb, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	}
+	return append(b, s...)
+}
+
+func appendMsgpackBin(b []byte, p []byte) []byte {
+	n := len(p)
+	switch {
+	case n <= math.MaxUint8:
+		b = append(b, 0xc4, byte(n))
+	case n <= math.MaxUint16:
+		b = append(b, 0xc5, byte(n>>8), byte(n))
+	default:
+		b = append(b, 0xc6, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	}
+	return append(b, p...)
+}
+
+func appendMsgpackInt(b []byte, i int64) []byte {
+	switch {
+	case i >= 0 && i < 128:
+		return append(b, byte(i))
+	case i < 0 && i >= -32:
+		return append(b, byte(i))
+	default:
+		return appendUint64(append(b, 0xd3), uint64(i))
+	}
+}
+
+func appendMsgpackFloat(b []byte, f float64) []byte {
+	return appendUint64(append(b, 0xcb), math.Float64bits(f))
+}
+
+// appendMsgpackEventTime appends t as an EventTime, the msgpack
+// extension type 0 defined by the forward protocol.
+func appendMsgpackEventTime(b []byte, t time.Time) []byte {
+	b = append(b, 0xd7, 0x00)
+	b = appendUint32(b, uint32(t.Unix()))
+	return appendUint32(b, uint32(t.Nanosecond()))
+}
+
+// appendUint32 appends the big-endian encoding of v.
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+// appendUint64 appends the big-endian encoding of v.
+func appendUint64(b []byte, v uint64) []byte {
+	return appendUint32(appendUint32(b, uint32(v>>32)), uint32(v))
+}
+
+// appendMsgpackValue appends a value decoded from JSON with
+// json.Decoder.UseNumber. The keys of maps are sorted.
+func appendMsgpackValue(b []byte, v interface{}) []byte {
+	switch v := v.(type) {
+	case nil:
+		return append(b, 0xc0)
+	case bool:
+		if v {
+			return append(b, 0xc3)
+		}
+		return append(b, 0xc2)
+	case string:
+		return appendMsgpackString(b, v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return appendMsgpackInt(b, i)
+		}
+		if f, err := v.Float64(); err == nil {
+			return appendMsgpackFloat(b, f)
+		}
+		return appendMsgpackString(b, v.String())
+	case []interface{}:
+		b = appendMsgpackArrayHeader(b, len(v))
+		for _, e := range v {
+			b = appendMsgpackValue(b, e)
+		}
+		return b
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b = appendMsgpackMapHeader(b, len(v))
+		for _, k := range keys {
+			b = appendMsgpackString(b, k)
+			b = appendMsgpackValue(b, v[k])
+		}
+		return b
+	default:
+		return appendMsgpackString(b, fmt.Sprint(v))
+	}
+}
+
+// newFluentZstdEncoder returns the zstd encoder used to compress the
+// batches of log entries.
+func newFluentZstdEncoder() (*zstd.Encoder, error) {
+	return zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+}
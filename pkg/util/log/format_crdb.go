@@ -14,16 +14,31 @@ import (
 	"hash/adler32"
 	"io"
 
+	"github.com/cockroachdb/cockroach/pkg/util/log/logdecoder"
 	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/ttycolor"
 )
 
-const severityChar = "IWEF"
+// severityChar contains the characters representing the severities
+// in the crdb-v1 and crdb-v2 formats, from DEBUG2 to FATAL.
+const severityChar = logdecoder.SeverityChar
+
+// severityToChar returns the character representing the given
+// severity, which must be between DEBUG2 and FATAL and not UNKNOWN.
+func severityToChar(sev Severity) byte {
+	i := int(sev - severity.DEBUG2)
+	if sev > severity.UNKNOWN {
+		// UNKNOWN has no character.
+		i--
+	}
+	return severityChar[i]
+}
 
 // MessageTimeFormat is the format of the timestamp in log message headers of crdb formatted logs.
 // as used in time.Parse and time.Format.
-const MessageTimeFormat = "060102 15:04:05.999999"
+const MessageTimeFormat = logdecoder.MessageTimeFormat
 
 // FormatLegacyEntry writes the contents of the legacy log entry struct to the specified writer.
 func FormatLegacyEntry(e logpb.Entry, w io.Writer) error {
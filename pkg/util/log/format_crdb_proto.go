@@ -0,0 +1,56 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"encoding/binary"
+
+	"github.com/cockroachdb/errors"
+)
+
+// formatCrdbProto emits entries as length-prefixed logpb.Entry
+// protobufs. It avoids the cost of text formatting and is only
+// suitable for file sinks.
+type formatCrdbProto struct{}
+
+func (formatCrdbProto) formatterName() string { return "crdb-proto" }
+
+func (formatCrdbProto) formatEntry(entry logEntry) *buffer {
+	e := entry.convertToLegacy()
+	data, err := e.Marshal()
+	if err != nil {
+		// The entry only contains scalars and strings; this cannot
+		// happen in practice.
+		panic(errors.NewAssertionErrorWithWrappedErrf(err, "marshaling log entry"))
+	}
+	buf := getBuffer()
+	var prefix [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(prefix[:], uint64(len(data)))
+	buf.Write(prefix[:n])
+	buf.Write(data)
+	return buf
+}
+
+func (formatCrdbProto) doc() string {
+	return `This format emits each log entry as a binary protobuf message of type
+` + "`cockroach.util.log.Entry`" + `, prefixed by its length encoded as a
+varint.
+
+It is cheaper to produce than the text formats and results in smaller
+files, which makes it suitable for high-throughput channels such as
+` + "`SQL_EXEC`" + `. It is only supported by file sinks.
+
+The files can be read with ` + "`cockroach debug merge-logs`" + `, or
+converted to another format with ` + "`cockroach debug decode-log`" + `.
+`
+}
+
+func (formatCrdbProto) contentType() string { return "application/octet-stream" }
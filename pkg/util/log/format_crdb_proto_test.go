@@ -0,0 +1,107 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/log/channel"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+	"github.com/cockroachdb/logtags"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatCrdbProto(t *testing.T) {
+	ctx := logtags.AddTag(context.Background(), "n", 1)
+	ctx = logtags.AddTag(ctx, "client", "foo")
+	entries := []logEntry{
+		makeUnstructuredEntry(ctx, severity.INFO, channel.DEV, 0,
+			true /* redactable */, "hello %s", "world"),
+		makeUnstructuredEntry(ctx, severity.WARNING, channel.OPS, 0,
+			true /* redactable */, "multi\nline"),
+		makeStructuredEntry(ctx, severity.INFO, channel.SQL_EXEC, 0,
+			&logpb.TestingStructuredLogEvent{
+				CommonEventDetails: logpb.CommonEventDetails{
+					Timestamp: 123,
+					EventType: "rename_database",
+				},
+				Event: "rename from `hello` to `world`",
+			}),
+	}
+	entries[1].stacks = []byte("stack trace")
+
+	f := formatCrdbProto{}
+	var file bytes.Buffer
+	for _, buf := range (&sinkInfo{formatter: f}).getStartLines(time.Now()) {
+		file.Write(buf.Bytes())
+		putBuffer(buf)
+	}
+	for i := range entries {
+		entries[i].counter = uint64(i + 1)
+		buf := f.formatEntry(entries[i])
+		file.Write(buf.Bytes())
+		putBuffer(buf)
+	}
+
+	// The format is detected from the header entries.
+	_, format, err := ReadFormatFromLogFile(bytes.NewReader(file.Bytes()))
+	require.NoError(t, err)
+	require.Equal(t, "crdb-proto", format)
+
+	d, err := NewEntryDecoder(bytes.NewReader(file.Bytes()), WithMarkedSensitiveData)
+	require.NoError(t, err)
+	var decoded []logpb.Entry
+	for {
+		var e logpb.Entry
+		if err := d.Decode(&e); err == io.EOF {
+			break
+		} else {
+			require.NoError(t, err)
+		}
+		if e.Severity == severity.UNKNOWN {
+			// Header entry.
+			continue
+		}
+		decoded = append(decoded, e)
+	}
+	require.Len(t, decoded, len(entries))
+
+	for i, entry := range entries {
+		require.Equal(t, entry.convertToLegacy(), decoded[i])
+
+		// The entries can be converted back to a text format.
+		var expected, actual bytes.Buffer
+		buf := formatCrdbV2{}.formatEntry(entry)
+		expected.Write(buf.Bytes())
+		putBuffer(buf)
+		require.NoError(t, FormatLegacyEntryWithFormat(decoded[i], "crdb-v2", &actual))
+		require.Equal(t, expected.String(), actual.String())
+	}
+}
+
+func TestDecodeCrdbProtoTruncated(t *testing.T) {
+	entry := makeUnstructuredEntry(context.Background(), severity.INFO, channel.DEV, 0,
+		true /* redactable */, "hello")
+	buf := formatCrdbProto{}.formatEntry(entry)
+	defer putBuffer(buf)
+	data := buf.Bytes()
+
+	d, err := NewEntryDecoderWithFormat(
+		bytes.NewReader(data[:len(data)-1]), WithMarkedSensitiveData, "crdb-proto")
+	require.NoError(t, err)
+	var e logpb.Entry
+	require.Equal(t, io.ErrUnexpectedEOF, d.Decode(&e))
+}
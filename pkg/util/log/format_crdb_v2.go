@@ -11,20 +11,12 @@
 package log
 
 import (
-	"bufio"
-	"bytes"
-	"io"
-	"regexp"
-	"strconv"
 	"strings"
-	"time"
 	"unicode/utf8"
 
 	"github.com/cockroachdb/cockroach/pkg/util/log/channel"
-	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
 	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
-	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/redact"
 	"github.com/cockroachdb/ttycolor"
 )
@@ -42,6 +34,8 @@ func (formatCrdbV2) doc() string { return formatCrdbV2CommonDoc() }
 
 func (formatCrdbV2) contentType() string { return "text/plain" }
 
+func (formatCrdbV2) supportsTimestampOptions() {}
+
 func formatCrdbV2CommonDoc() string {
 	var buf strings.Builder
 
@@ -145,6 +139,22 @@ I210116 21:49:17.080713 14 1@util/log/event_log.go:32 ⋮ [-] 32 ={"Timestamp":1
 I210116 21:49:17.080713 14 1@util/log/event_log.go:32 ⋮ [-] 32 |pe":"node_restart"}
 ~~~
 
+### Timestamp options
+
+The rendering of the timestamps can be changed with the ` + "`timestamps`" + `
+attribute of the sink configuration. With ` + "`format: rfc3339`" + `,
+the date and time are replaced by an RFC 3339 timestamp with
+microsecond precision and an explicit time zone offset, in the time
+zone selected with ` + "`zone`" + ` (UTC by default). With ` + "`delta: true`" + `,
+the timestamp is followed by the number of seconds elapsed since the
+previous entry emitted to the same sink, prefixed with ` + "`+`" + `:
+
+~~~
+I2021-01-16T16:49:17.073282-05:00 +0.000211 14 server/node.go:464 ⋮ [-] 23  started with engine type ‹2›
+~~~
+
+Entries rendered with these options cannot be read by the log parsers.
+
 ### Backward-compatibility notes
 
 Entries in this format can be read by most ` + "`crdb-v1`" + ` log parsers,
@@ -192,6 +202,8 @@ func (formatCrdbV2TTY) doc() string {
 
 func (formatCrdbV2TTY) contentType() string { return "text/plain" }
 
+func (formatCrdbV2TTY) supportsTimestampOptions() {}
+
 // formatEntryInternalV2 renders a log entry.
 // Log lines are colorized depending on severity.
 // It uses a newly allocated *buffer. The caller is responsible
@@ -206,7 +218,7 @@ func formatLogEntryInternalV2(entry logEntry, cp ttycolor.Profile) *buffer {
 	if entry.line < 0 {
 		entry.line = 0 // not a real line number, but acceptable to someDigits
 	}
-	if entry.sev > severity.FATAL || entry.sev <= severity.UNKNOWN {
+	if entry.sev > severity.FATAL || entry.sev < severity.DEBUG2 || entry.sev == severity.UNKNOWN {
 		entry.sev = severity.INFO // for safety.
 	}
 
@@ -225,30 +237,46 @@ func formatLogEntryInternalV2(entry logEntry, cp ttycolor.Profile) *buffer {
 	// Avoid Fprintf, for speed. The format is so simple that we can do it quickly by hand.
 	// It's worth about 3X. Fprintf is hard.
 	now := timeutil.Unix(0, entry.ts)
-	year, month, day := now.Date()
-	hour, minute, second := now.Clock()
-	// Lyymmdd hh:mm:ss.uuuuuu file:line
-	tmp[n] = severityChar[entry.sev-1]
+	tmp[n] = severityToChar(entry.sev)
 	n++
-	if year < 2000 {
-		year = 2000
+	if entry.timestamps != nil && entry.timestamps.loc != nil {
+		// L2006-01-02T15:04:05.000000Z07:00 file:line
+		n += copy(tmp[n:], cp[ttycolor.Gray]) // gray for time, file & line
+		buf.Write(tmp[:n])
+		buf.WriteString(now.In(entry.timestamps.loc).Format(rfc3339MicroFormat))
+		n = 0
+	} else {
+		year, month, day := now.Date()
+		hour, minute, second := now.Clock()
+		// Lyymmdd hh:mm:ss.uuuuuu file:line
+		if year < 2000 {
+			year = 2000
+		}
+		n += buf.twoDigits(n, year-2000)
+		n += buf.twoDigits(n, int(month))
+		n += buf.twoDigits(n, day)
+		n += copy(tmp[n:], cp[ttycolor.Gray]) // gray for time, file & line
+		tmp[n] = ' '
+		n++
+		n += buf.twoDigits(n, hour)
+		tmp[n] = ':'
+		n++
+		n += buf.twoDigits(n, minute)
+		tmp[n] = ':'
+		n++
+		n += buf.twoDigits(n, second)
+		tmp[n] = '.'
+		n++
+		n += buf.nDigits(6, n, now.Nanosecond()/1000, '0')
+	}
+	if entry.timestamps != nil && entry.timestamps.delta {
+		// The delta since the previous entry, e.g. +0.000123.
+		buf.Write(tmp[:n])
+		tmp[0] = ' '
+		tmp[1] = '+'
+		n = 2
+		n += buf.formatDelta(n, entry.delta, 6)
 	}
-	n += buf.twoDigits(n, year-2000)
-	n += buf.twoDigits(n, int(month))
-	n += buf.twoDigits(n, day)
-	n += copy(tmp[n:], cp[ttycolor.Gray]) // gray for time, file & line
-	tmp[n] = ' '
-	n++
-	n += buf.twoDigits(n, hour)
-	tmp[n] = ':'
-	n++
-	n += buf.twoDigits(n, minute)
-	tmp[n] = ':'
-	n++
-	n += buf.twoDigits(n, second)
-	tmp[n] = '.'
-	n++
-	n += buf.nDigits(6, n, now.Nanosecond()/1000, '0')
 	tmp[n] = ' '
 	n++
 	n += buf.someDigits(n, int(entry.gid))
@@ -469,256 +497,3 @@ func (buf *buffer) maybeMultiLine(
 var startRedactionMarker = string(redact.StartMarker())
 var endRedactionMarker = string(redact.EndMarker())
 var markersStartWithMultiByteRune = startRedactionMarker[0] >= utf8.RuneSelf && endRedactionMarker[0] >= utf8.RuneSelf
-
-var (
-	entryREV2 = regexp.MustCompile(
-		`(?m)^` +
-			/* Severity                 */ `(?P<severity>[` + severityChar + `])` +
-			/* Date and time            */ `(?P<datetime>\d{6} \d{2}:\d{2}:\d{2}.\d{6}) ` +
-			/* Goroutine ID             */ `(?:(?P<goroutine>\d+) )` +
-			/* Go standard library flag */ `(\(gostd\) )?` +
-			/* Channel                  */ `(?:(?P<channel>\d+)@)?` +
-			/* File                     */ `(?P<file>[^:]+):` +
-			/* Line                     */ `(?:(?P<line>\d+) )` +
-			/* Redactable flag          */ `(?P<redactable>(?:` + redactableIndicator + `)?) ` +
-			/* Context tags             */ `\[(?P<tags>(?:[^]]|\][^ ])+)\] ` +
-			/* Counter                  */ `(?P<counter>(?:\d+)?) ` +
-			/* Continuation marker      */ `(?P<continuation>[ =!+|])` +
-			/* Message                  */ `(?P<msg>.*)$`,
-	)
-	v2SeverityIdx     = entryREV2.SubexpIndex("severity")
-	v2DateTimeIdx     = entryREV2.SubexpIndex("datetime")
-	v2GoroutineIdx    = entryREV2.SubexpIndex("goroutine")
-	v2ChannelIdx      = entryREV2.SubexpIndex("channel")
-	v2FileIdx         = entryREV2.SubexpIndex("file")
-	v2LineIdx         = entryREV2.SubexpIndex("line")
-	v2RedactableIdx   = entryREV2.SubexpIndex("redactable")
-	v2TagsIdx         = entryREV2.SubexpIndex("tags")
-	v2CounterIdx      = entryREV2.SubexpIndex("counter")
-	v2ContinuationIdx = entryREV2.SubexpIndex("continuation")
-	v2MsgIdx          = entryREV2.SubexpIndex("msg")
-)
-
-type entryDecoderV2 struct {
-	lines           int // number of lines read from reader
-	reader          *bufio.Reader
-	nextFragment    entryDecoderV2Fragment
-	sensitiveEditor redactEditor
-}
-
-// Decode decodes the next log entry into the provided protobuf message.
-func (d *entryDecoderV2) Decode(entry *logpb.Entry) (err error) {
-	defer func() {
-		switch r := recover().(type) {
-		case nil: // do nothing
-		case error:
-			err = errors.Wrapf(r, "decoding on line %d", d.lines)
-		default:
-			panic(r)
-		}
-	}()
-	frag, atEOF := d.peekNextFragment()
-	if atEOF {
-		return io.EOF
-	}
-	d.popFragment()
-	if err := d.initEntryFromFirstLine(entry, frag); err != nil {
-		return err
-	}
-
-	// Process the message.
-	var entryMsg bytes.Buffer
-	entryMsg.Write(frag.getMsg())
-
-	// While the entry has additional lines, collect the full message.
-	for {
-		frag, atEOF := d.peekNextFragment()
-		if atEOF || !frag.isContinuation() {
-			break
-		}
-		d.popFragment()
-		d.addContinuationFragmentToEntry(entry, &entryMsg, frag)
-	}
-
-	r := redactablePackage{
-		msg:        entryMsg.Bytes(),
-		redactable: entry.Redactable,
-	}
-	r = d.sensitiveEditor(r)
-	entry.Message = string(r.msg)
-	entry.Redactable = r.redactable
-
-	return nil
-}
-
-func (d *entryDecoderV2) addContinuationFragmentToEntry(
-	entry *logpb.Entry, entryMsg *bytes.Buffer, frag entryDecoderV2Fragment,
-) {
-	switch frag.getContinuation() {
-	case '+':
-		entryMsg.WriteByte('\n')
-		entryMsg.Write(frag.getMsg())
-	case '|':
-		entryMsg.Write(frag.getMsg())
-		if entry.StructuredEnd != 0 {
-			entry.StructuredEnd = uint32(entryMsg.Len())
-		}
-	case '!':
-		if entry.StackTraceStart == 0 {
-			entry.StackTraceStart = uint32(entryMsg.Len()) + 1
-			entryMsg.WriteString("\nstack trace:\n")
-			entryMsg.Write(frag.getMsg())
-		} else {
-			entryMsg.WriteString("\n")
-			entryMsg.Write(frag.getMsg())
-		}
-	default:
-		panic(errors.Errorf("unexpected continuation character %c", frag.getContinuation()))
-	}
-}
-
-// peekNextFragment populates the nextFragment buffer by reading from the
-// underlying reader a line at a time until a valid line is reached.
-// It will panic if a malformed log line is discovered. It permits the first
-// line in the decoder to be malformed and it will skip that line. Upon EOF,
-// if there is no text left to consume, the atEOF return value will be true.
-func (d *entryDecoderV2) peekNextFragment() (_ entryDecoderV2Fragment, atEOF bool) {
-	for d.nextFragment == nil {
-		d.lines++
-		nextLine, err := d.reader.ReadBytes('\n')
-		if isEOF := errors.Is(err, io.EOF); isEOF {
-			if len(nextLine) == 0 {
-				return nil, true
-			}
-		} else if err != nil {
-			panic(err)
-		}
-		nextLine = bytes.TrimSuffix(nextLine, []byte{'\n'})
-		m := entryREV2.FindSubmatch(nextLine)
-		if m == nil {
-			if d.lines == 1 { // allow non-matching lines if we've never seen a line
-				continue
-			}
-			panic(errors.New("malformed log entry"))
-		}
-		d.nextFragment = m
-	}
-	return d.nextFragment, false
-}
-
-func (d *entryDecoderV2) popFragment() {
-	if d.nextFragment == nil {
-		panic(errors.AssertionFailedf("cannot pop unpopulated fragment"))
-	}
-	d.nextFragment = nil
-}
-
-func (d *entryDecoderV2) initEntryFromFirstLine(
-	entry *logpb.Entry, m entryDecoderV2Fragment,
-) (err error) {
-	// Erase all the fields, to be sure.
-	*entry = logpb.Entry{
-		Severity:   m.getSeverity(),
-		Time:       m.getTimestamp(),
-		Goroutine:  m.getGoroutine(),
-		Channel:    m.getChannel(),
-		File:       m.getFile(),
-		Line:       m.getLine(),
-		Redactable: m.isRedactable(),
-		Tags:       m.getTags(d.sensitiveEditor),
-		Counter:    m.getCounter(),
-	}
-	if m.isStructured() {
-		entry.StructuredStart = 0
-		entry.StructuredEnd = uint32(len(m.getMsg()))
-	}
-	return nil
-}
-
-// entryDecoderV2Fragment is a line which is part of a v2 log entry.
-// It is the output of entryV2RE.FindSubmatch.
-type entryDecoderV2Fragment [][]byte
-
-func (f entryDecoderV2Fragment) getSeverity() logpb.Severity {
-	return Severity(strings.IndexByte(severityChar, f[v2SeverityIdx][0]) + 1)
-}
-
-func (f entryDecoderV2Fragment) getMsg() []byte {
-	return f[v2MsgIdx]
-}
-
-func (f entryDecoderV2Fragment) getContinuation() byte {
-	return f[v2ContinuationIdx][0]
-}
-
-func (f entryDecoderV2Fragment) isContinuation() bool {
-	switch f.getContinuation() {
-	case '|', '+', '!':
-		return true
-	default:
-		return false
-	}
-}
-
-func (f entryDecoderV2Fragment) getGoroutine() int64 {
-	return parseInt(f[v2GoroutineIdx], "goroutine")
-}
-
-func (f entryDecoderV2Fragment) getTimestamp() (unixNano int64) {
-	t, err := time.Parse(MessageTimeFormat, string(f[v2DateTimeIdx]))
-	if err != nil {
-		panic(err)
-	}
-	return t.UnixNano()
-}
-
-func (f entryDecoderV2Fragment) getChannel() logpb.Channel {
-	if len(f[v2ChannelIdx]) == 0 {
-		return Channel(0)
-	}
-	return Channel(parseInt(f[v2ChannelIdx], "channel"))
-}
-
-func (f entryDecoderV2Fragment) getFile() string {
-	return string(f[v2FileIdx])
-}
-
-func (f entryDecoderV2Fragment) getLine() int64 {
-	return parseInt(f[v2LineIdx], "line")
-}
-
-func (f entryDecoderV2Fragment) isRedactable() bool {
-	return len(f[v2RedactableIdx]) > 0
-}
-
-func (f entryDecoderV2Fragment) getTags(editor redactEditor) string {
-	switch tagsStr := string(f[v2TagsIdx]); tagsStr {
-	case "-":
-		return tagsStr
-	default:
-		r := editor(redactablePackage{
-			msg:        f[v2TagsIdx],
-			redactable: f.isRedactable(),
-		})
-		return string(r.msg)
-	}
-}
-
-func (f entryDecoderV2Fragment) getCounter() uint64 {
-	if len(f[v2CounterIdx]) == 0 {
-		return 0
-	}
-	return uint64(parseInt(f[v2CounterIdx], "counter"))
-}
-
-func (f entryDecoderV2Fragment) isStructured() bool {
-	return f.getContinuation() == '='
-}
-
-func parseInt(data []byte, name string) int64 {
-	i, err := strconv.ParseInt(string(data), 10, 64)
-	if err != nil {
-		panic(errors.Wrapf(err, "parsing %s", name))
-	}
-	return i
-}
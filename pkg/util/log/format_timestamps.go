@@ -0,0 +1,106 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/log/logconfig"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// rfc3339MicroFormat is the layout of RFC 3339 timestamps with
+// microsecond precision.
+const rfc3339MicroFormat = "2006-01-02T15:04:05.000000Z07:00"
+
+// timestampOptions are the rendering options of the timestamps of the
+// entries emitted to a sink. See logconfig.TimestampConfig.
+type timestampOptions struct {
+	// loc, if set, is the time zone in which the timestamps are
+	// rendered in RFC 3339 format.
+	loc *time.Location
+	// delta indicates whether to report the time elapsed since the
+	// previous entry emitted to the sink.
+	delta bool
+}
+
+// timestampOptionsFormatter is implemented by the formatters which
+// honor the timestamp options of their sink.
+type timestampOptionsFormatter interface {
+	supportsTimestampOptions()
+}
+
+// newTimestampOptions returns the timestamp options for the given
+// configuration, or nil if the native timestamps of the entry format
+// are used.
+func newTimestampOptions(c logconfig.TimestampConfig) (*timestampOptions, error) {
+	if !c.IsEnabled() {
+		return nil, nil
+	}
+	opts := &timestampOptions{delta: c.Delta != nil && *c.Delta}
+	if c.IsRFC3339() {
+		zone := "UTC"
+		if c.Zone != nil {
+			zone = *c.Zone
+		}
+		loc, err := timeutil.LoadLocation(zone)
+		if err != nil {
+			return nil, err
+		}
+		opts.loc = loc
+	}
+	return opts, nil
+}
+
+// describe reports the timestamp options as a TimestampConfig.
+func (o *timestampOptions) describe() (c logconfig.TimestampConfig) {
+	if o == nil {
+		return c
+	}
+	if o.loc != nil {
+		f, zone := logconfig.RFC3339TimestampFormat, o.loc.String()
+		c.Format, c.Zone = &f, &zone
+	}
+	if o.delta {
+		c.Delta = &o.delta
+	}
+	return c
+}
+
+// entryDelta returns the time elapsed between the timestamp of the
+// previous entry emitted to the sink and the given timestamp, or zero
+// for the first entry. Entries are formatted concurrently and the
+// system clock may step backwards, so the timestamps of successive
+// entries are not necessarily ordered: the delta is then zero instead
+// of negative.
+func (l *sinkInfo) entryDelta(ts int64) int64 {
+	prev := atomic.SwapInt64(&l.lastEntryTS, ts)
+	if prev == 0 || ts < prev {
+		return 0
+	}
+	return ts - prev
+}
+
+// formatDelta renders a delta as a number of seconds, with the
+// given number of fractional digits, into buf.tmp starting at
+// position i. It returns the number of bytes written.
+func (buf *buffer) formatDelta(i int, delta int64, fracDigits int) int {
+	n := buf.someDigits(i, int(delta/int64(time.Second)))
+	buf.tmp[i+n] = '.'
+	n++
+	frac := int(delta % int64(time.Second))
+	for d := fracDigits; d < 9; d++ {
+		frac /= 10
+	}
+	n += buf.nDigits(fracDigits, i+n, frac, '0')
+	return n
+}
@@ -0,0 +1,70 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/log/channel"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logconfig"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimestampOptions(t *testing.T) {
+	tm, err := time.Parse(MessageTimeFormat, "060102 15:04:05.654321")
+	require.NoError(t, err)
+
+	f, zone, delta := logconfig.RFC3339TimestampFormat, "America/New_York", true
+	opts, err := newTimestampOptions(logconfig.TimestampConfig{Format: &f, Zone: &zone, Delta: &delta})
+	require.NoError(t, err)
+	require.Equal(t, logconfig.TimestampConfig{Format: &f, Zone: &zone, Delta: &delta}, opts.describe())
+
+	var s sinkInfo
+	format := func(formatter logFormatter, ts time.Time) string {
+		entry := makeUnstructuredEntry(context.Background(), severity.INFO, channel.DEV, 0, false, "hello")
+		entry.ts = ts.UnixNano()
+		entry.gid = 11
+		entry.timestamps = opts
+		entry.delta = s.entryDelta(entry.ts)
+		b := formatter.formatEntry(entry)
+		defer putBuffer(b)
+		return b.String()
+	}
+
+	// The first entry has no predecessor.
+	require.Regexp(t, `^I2006-01-02T10:04:05\.654321-05:00 \+0\.000000 11 `,
+		format(formatCrdbV2{}, tm))
+	require.Regexp(t, `^I2006-01-02T10:04:05\.656321-05:00 \+0\.002000 11 `,
+		format(formatCrdbV2{}, tm.Add(2*time.Millisecond)))
+	require.Contains(t,
+		format(formatJSONCompact{}, tm.Add(3*time.Millisecond+500*time.Nanosecond)),
+		`"t":"1136214245.657321500","d":"2006-01-02T10:04:05.657321-05:00","dt":0.001000500,`)
+
+	// The delta is never negative.
+	require.Contains(t,
+		format(formatJSONFull{}, tm),
+		`"timestamp":"1136214245.654321000","datetime":"2006-01-02T10:04:05.654321-05:00","delta":0.000000000,`)
+
+	// Only the delta is reported without RFC 3339 timestamps.
+	opts, err = newTimestampOptions(logconfig.TimestampConfig{Delta: &delta})
+	require.NoError(t, err)
+	require.Regexp(t, `^I060102 15:04:05\.655321 \+0\.001000 11 `,
+		format(formatCrdbV2{}, tm.Add(time.Millisecond)))
+
+	// The native timestamps don't need options.
+	f = logconfig.DefaultTimestampFormat
+	opts, err = newTimestampOptions(logconfig.TimestampConfig{Format: &f})
+	require.NoError(t, err)
+	require.Nil(t, opts)
+}
@@ -10,6 +10,13 @@
 
 package log
 
+import (
+	"io"
+
+	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+	"github.com/cockroachdb/errors"
+)
+
 type logFormatter interface {
 	formatterName() string
 	// doc is used to generate the formatter documentation.
@@ -23,24 +30,12 @@ type logFormatter interface {
 	contentType() string
 }
 
-var formatParsers = map[string]string{
-	"crdb-v1":             "v1",
-	"crdb-v1-count":       "v1",
-	"crdb-v1-tty":         "v1",
-	"crdb-v1-tty-count":   "v1",
-	"crdb-v2":             "v2",
-	"crdb-v2-tty":         "v2",
-	"json":                "json",
-	"json-compact":        "json-compact",
-	"json-fluent":         "json",
-	"json-fluent-compact": "json-compact",
-}
-
 var formatters = func() map[string]logFormatter {
 	m := make(map[string]logFormatter)
 	r := func(f logFormatter) {
 		m[f.formatterName()] = f
 	}
+	r(formatCrdbProto{})
 	r(formatCrdbV1{})
 	r(formatCrdbV1WithCounter{})
 	r(formatCrdbV1TTY{})
@@ -63,3 +58,17 @@ func GetFormatterDocs() map[string]string {
 	}
 	return m
 }
+
+// FormatLegacyEntryWithFormat writes the legacy log entry to the
+// specified writer using the named format. This is used to convert
+// log files from one format to another.
+func FormatLegacyEntryWithFormat(e logpb.Entry, format string, w io.Writer) error {
+	f, ok := formatters[format]
+	if !ok {
+		return errors.Newf("unknown log format: %q", format)
+	}
+	buf := f.formatEntry(makeEntryFromLegacy(&e))
+	defer putBuffer(buf)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
@@ -23,6 +23,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log/channel"
 	"github.com/cockroachdb/cockroach/pkg/util/log/logconfig"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logdecoder"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
 	"github.com/cockroachdb/logtags"
 	"github.com/cockroachdb/redact"
 	"github.com/stretchr/testify/require"
@@ -37,6 +39,11 @@ func TestFormatRedaction(t *testing.T) {
 	// Make the test below deterministic.
 	formatNames := make([]string, 0, len(formatters))
 	for n := range formatters {
+		if n == "crdb-proto" {
+			// The binary format is not line-oriented; it is tested
+			// separately.
+			continue
+		}
 		formatNames = append(formatNames, n)
 	}
 	sort.Strings(formatNames)
@@ -138,3 +145,23 @@ func TestFormatRedaction(t *testing.T) {
 		})
 	}
 }
+
+func TestSeverityChar(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	seen := make(map[byte]Severity)
+	for sev := severity.DEBUG2; sev <= severity.FATAL; sev++ {
+		if sev == severity.UNKNOWN {
+			continue
+		}
+		c := severityToChar(sev)
+		if prev, ok := seen[c]; ok {
+			t.Fatalf("%s and %s both use %q", prev, sev, c)
+		}
+		seen[c] = sev
+		require.Equal(t, sev, logdecoder.SeverityFromChar(c))
+	}
+	require.Equal(t, byte('I'), severityToChar(severity.INFO))
+	require.Equal(t, byte('F'), severityToChar(severity.FATAL))
+	require.Equal(t, severity.UNKNOWN, logdecoder.SeverityFromChar('X'))
+}
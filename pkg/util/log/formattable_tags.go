@@ -14,6 +14,9 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"runtime/pprof"
+	"sort"
+	"strconv"
 
 	"github.com/cockroachdb/logtags"
 	"github.com/cockroachdb/redact"
@@ -86,6 +89,43 @@ func escapeNulBytes(res []byte, s string) []byte {
 	return res
 }
 
+// withGoroutineTags returns a copy of the tags extended with the ID of
+// the goroutine which emitted the entry, as `goid`, and the profiler
+// labels found in the context of the logging call. The labels are
+// sorted by key for stable output; their values are considered unsafe.
+func (f formattableTags) withGoroutineTags(
+	ctx context.Context, gid int64, redactable bool,
+) formattableTags {
+	var labels [][2]string
+	if ctx != nil {
+		pprof.ForLabels(ctx, func(key, value string) bool {
+			labels = append(labels, [2]string{key, value})
+			return true
+		})
+	}
+	sort.Slice(labels, func(i, j int) bool { return labels[i][0] < labels[j][0] })
+
+	res := make(formattableTags, len(f), len(f)+24+len(labels)*16)
+	copy(res, f)
+	res = append(res, "goid"...)
+	res = append(res, 0)
+	res = strconv.AppendInt(res, gid, 10)
+	res = append(res, 0)
+	for _, l := range labels {
+		res = escapeNulBytes(res, l[0])
+		res = append(res, 0)
+		if l[1] != "" {
+			if !redactable {
+				res = escapeNulBytes(res, l[1])
+			} else {
+				res = escapeNulBytes(res, string(redact.Sprint(l[1])))
+			}
+		}
+		res = append(res, 0)
+	}
+	return res
+}
+
 // formattableTagsIterator is a helper for the various formatting functions below.
 type formattableTagsIterator struct {
 	tags []byte
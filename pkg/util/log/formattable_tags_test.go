@@ -12,6 +12,7 @@ package log
 
 import (
 	"context"
+	"runtime/pprof"
 	"testing"
 
 	"github.com/cockroachdb/logtags"
@@ -107,3 +108,26 @@ func TestEscapeNulBytes(t *testing.T) {
 	assert.Equal(t, string(escapeNulBytes(nil, "\x00abc")), "?abc")
 	assert.Equal(t, string(escapeNulBytes(nil, "aa\x00bb\x00\x00cc")), "aa?bb??cc")
 }
+
+func TestWithGoroutineTags(t *testing.T) {
+	ctx := logtags.AddTag(context.Background(), "n", 1)
+	ctx = pprof.WithLabels(ctx, pprof.Labels("range", "r12", "op", "scan"))
+
+	for _, redactable := range []bool{false, true} {
+		tags := makeFormattableTags(ctx, redactable)
+		gtags := tags.withGoroutineTags(ctx, 42, redactable)
+
+		var buf buffer
+		gtags.formatToBuffer(&buf)
+		if redactable {
+			assert.Equal(t, "n1,goid=42,op=‹scan›,range=‹r12›", buf.String())
+		} else {
+			assert.Equal(t, "n1,goid=42,op=scan,range=r12", buf.String())
+		}
+
+		// The original tags are left unchanged.
+		buf = buffer{}
+		tags.formatToBuffer(&buf)
+		assert.Equal(t, "n1", buf.String())
+	}
+}
@@ -12,8 +12,10 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"text/template"
 
@@ -42,7 +44,12 @@ func run() error {
 	if !ok {
 		return errors.Newf("unknown template: %q", tmplName)
 	}
-	tmpl, err := template.New(tmplName).Parse(tmplSrc)
+	tmpl, err := template.New(tmplName).Funcs(template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			b, err := json.MarshalIndent(v, "", "  ")
+			return string(b), err
+		},
+	}).Parse(tmplSrc)
 	if err != nil {
 		return errors.Wrapf(err, "%s", tmplName)
 	}
@@ -58,7 +65,8 @@ func run() error {
 	if err := tmpl.Execute(&src, struct {
 		Severities []info
 		Channels   []info
-	}{sevs, chans}); err != nil {
+		Metadata   metadata
+	}{sevs, chans, makeMetadata(chans, sevs)}); err != nil {
 		return err
 	}
 
@@ -70,6 +78,10 @@ func run() error {
 			return errors.Wrap(err, "gofmt")
 		}
 	}
+	// If we are generating a .json file, check that it is well-formed.
+	if strings.HasSuffix(tmplName, ".json") && !json.Valid(newBytes) {
+		return errors.Newf("%s: invalid JSON output", tmplName)
+	}
 
 	// Write the output file.
 	w := os.Stdout
@@ -95,6 +107,84 @@ type info struct {
 	Name       string
 	NAME       string
 	NameLower  string
+	Value      int
+	// MetricIndex is the index of a severity in the second dimension of
+	// log.Metrics.Count, or -1 if the entries with that severity are
+	// not counted.
+	MetricIndex int
+}
+
+// metadata is the machine-readable description of the severities and
+// channels, for consumption by the DB Console and the docs pipeline.
+type metadata struct {
+	Severities []severityMetadata `json:"severities"`
+	Channels   []channelMetadata  `json:"channels"`
+}
+
+type severityMetadata struct {
+	Name        string `json:"name"`
+	Value       int    `json:"value"`
+	Description string `json:"description"`
+}
+
+type channelMetadata struct {
+	Name        string `json:"name"`
+	Value       int    `json:"value"`
+	Description string `json:"description"`
+	// DefaultSink is the name of the file group which the channel is
+	// routed to in the default logging configuration.
+	DefaultSink string `json:"defaultSink"`
+}
+
+// defaultFileGroups maps the channels to the file group that they are
+// routed to by the default logging configuration, i.e. the predefined
+// file groups in pkg/cli/log_flags.go. The channels not listed here
+// are routed to the "default" file group.
+//
+// This must be kept in sync with predefinedLogFiles in pkg/cli.
+var defaultFileGroups = map[string]string{
+	"HEALTH":            "health",
+	"STORAGE":           "pebble",
+	"PRIVILEGES":        "security",
+	"USER_ADMIN":        "security",
+	"SESSIONS":          "sql-auth",
+	"SENSITIVE_ACCESS":  "sql-audit",
+	"SQL_EXEC":          "sql-exec",
+	"SQL_SCHEMA":        "sql-schema",
+	"SCHEMA_CHANGES":    "sql-schema",
+	"SQL_PERF":          "sql-slow",
+	"SQL_INTERNAL_PERF": "sql-slow-internal-only",
+	"TELEMETRY":         "telemetry",
+}
+
+func makeMetadata(chans []info, sevs []info) (m metadata) {
+	m.Severities = []severityMetadata{}
+	for _, s := range sevs {
+		switch s.NAME {
+		case "NONE", "UNKNOWN", "DEFAULT":
+			// Not actual severities of log entries.
+			continue
+		}
+		m.Severities = append(m.Severities, severityMetadata{
+			Name:        s.NAME,
+			Value:       s.Value,
+			Description: strings.TrimSpace(s.PComment),
+		})
+	}
+	m.Channels = []channelMetadata{}
+	for _, c := range chans {
+		sink, ok := defaultFileGroups[c.NAME]
+		if !ok {
+			sink = "default"
+		}
+		m.Channels = append(m.Channels, channelMetadata{
+			Name:        c.NAME,
+			Value:       c.Value,
+			Description: strings.TrimSpace(c.PComment),
+			DefaultSink: sink,
+		})
+	}
+	return m
 }
 
 func readInput(protoName string) (chans []info, sevs []info, err error) {
@@ -105,6 +195,7 @@ func readInput(protoName string) (chans []info, sevs []info, err error) {
 	inSevs := false
 	inChans := false
 	rawComment := ""
+	metricIndex := 0
 	for _, line := range strings.Split(string(protoData), "\n") {
 		line = strings.TrimSpace(line)
 		if line == "" {
@@ -138,28 +229,44 @@ func readInput(protoName string) (chans []info, sevs []info, err error) {
 			continue
 		}
 		key := strings.Split(line, " ")[0]
+		value, err := parseEnumValue(line)
+		if err != nil {
+			return nil, nil, err
+		}
 		title := strings.ReplaceAll(cases.Title(language.English, cases.NoLower).String(
 			strings.ReplaceAll(strings.ToLower(key), "_", " ")), " ", "")
 		if inSevs {
 			comment := "// The `" + key + "` severity" + strings.TrimPrefix(rawComment, "// "+key)
+			idx := -1
+			switch strings.ToUpper(key) {
+			case "NONE", "UNKNOWN", "DEFAULT":
+				// Not actual severities of log entries.
+			default:
+				idx = metricIndex
+				metricIndex++
+			}
 			sevs = append(sevs, info{
-				RawComment: rawComment,
-				Comment:    comment,
-				PComment:   strings.ReplaceAll(strings.ReplaceAll(comment, "// ", ""), "//", ""),
-				Name:       title,
-				NAME:       strings.ToUpper(key),
-				NameLower:  strings.ToLower(key),
+				RawComment:  rawComment,
+				Comment:     comment,
+				PComment:    strings.ReplaceAll(strings.ReplaceAll(comment, "// ", ""), "//", ""),
+				Name:        title,
+				NAME:        strings.ToUpper(key),
+				NameLower:   strings.ToLower(key),
+				Value:       value,
+				MetricIndex: idx,
 			})
 		}
 		if inChans && key != "CHANNEL_MAX" {
 			comment := "// The `" + key + "` channel" + strings.TrimPrefix(rawComment, "// "+key)
 			chans = append(chans, info{
-				RawComment: rawComment,
-				Comment:    comment,
-				PComment:   strings.ReplaceAll(strings.ReplaceAll(comment, "// ", ""), "//", ""),
-				Name:       title,
-				NAME:       strings.ToUpper(key),
-				NameLower:  strings.ToLower(key),
+				RawComment:  rawComment,
+				Comment:     comment,
+				PComment:    strings.ReplaceAll(strings.ReplaceAll(comment, "// ", ""), "//", ""),
+				Name:        title,
+				NAME:        strings.ToUpper(key),
+				NameLower:   strings.ToLower(key),
+				Value:       value,
+				MetricIndex: -1,
 			})
 		}
 		rawComment = ""
@@ -168,6 +275,17 @@ func readInput(protoName string) (chans []info, sevs []info, err error) {
 	return chans, sevs, nil
 }
 
+// parseEnumValue extracts the numeric value from a line of the
+// form "NAME = value;".
+func parseEnumValue(line string) (int, error) {
+	i := strings.IndexByte(line, '=')
+	j := strings.IndexByte(line, ';')
+	if i < 0 || j < i {
+		return 0, errors.Newf("unable to parse enum value: %q", line)
+	}
+	return strconv.Atoi(strings.TrimSpace(line[i+1 : j]))
+}
+
 var templates = map[string]string{
 	"logging.md": `## Logging levels (severities)
 {{range .Severities}}{{if eq .NAME "NONE" "UNKNOWN" "DEFAULT"|not}}
@@ -182,6 +300,39 @@ var templates = map[string]string{
 
 {{.PComment}}
 {{- end}}
+`,
+
+	"log_channels.json": `{{json .Metadata}}
+`,
+
+	"log_channels.ts": `// Code generated by gen/main.go. DO NOT EDIT.
+
+// LogSeverity describes a logging level (severity).
+export interface LogSeverity {
+  name: string;
+  value: number;
+  description: string;
+}
+
+// LogChannel describes a logging channel.
+export interface LogChannel {
+  name: string;
+  value: number;
+  description: string;
+  // defaultSink is the name of the file group which the channel is
+  // routed to in the default logging configuration.
+  defaultSink: string;
+}
+
+export type LogSeverityName ={{range .Metadata.Severities}}
+  | "{{.Name}}"{{end}};
+
+export type LogChannelName ={{range .Metadata.Channels}}
+  | "{{.Name}}"{{end}};
+
+export const logSeverities: LogSeverity[] = {{json .Metadata.Severities}};
+
+export const logChannels: LogChannel[] = {{json .Metadata.Channels}};
 `,
 
 	"severity.go": `// Code generated by gen/main.go. DO NOT EDIT.
@@ -215,6 +366,7 @@ package log
 
 import (
   "context"
+  "time"
 
   "github.com/cockroachdb/cockroach/pkg/util/log/channel"
   "github.com/cockroachdb/cockroach/pkg/util/log/severity"
@@ -238,7 +390,9 @@ type ChannelLogger interface {
 
   // V{{.Name}}f logs to the channel with severity {{.NAME}},
   // if logging has been enabled for the source file where the call is
-  // performed at the provided verbosity level, via the vmodule setting.
+  // performed at the provided verbosity level, via the vmodule setting
+  // of all channels or that of the channel, or for one of the log tags
+  // of the context.
   // It extracts log tags from the context and logs them along with the given
   // message. Arguments are handled in the manner of fmt.Printf.
   V{{.Name}}f(ctx context.Context, level Level, format string, args ...interface{})
@@ -254,7 +408,15 @@ type ChannelLogger interface {
   // message. Arguments are handled in the manner of fmt.Printf.
   {{.Name}}fDepth(ctx context.Context, depth int, format string, args ...interface{})
 
-  {{end}}{{end}}{{- /* end range severities */ -}}
+  {{if eq .NAME "INFO" "WARNING" "ERROR" -}}
+  // {{.Name}}fEvery logs to the channel with severity {{.NAME}},
+  // unless an entry was already logged from the same location in the
+  // source code in the last 'every' duration.
+  // It extracts log tags from the context and logs them along with the given
+  // message. Arguments are handled in the manner of fmt.Printf.
+  {{.Name}}fEvery(ctx context.Context, every time.Duration, format string, args ...interface{})
+
+  {{end}}{{end}}{{end}}{{- /* end range severities */ -}}
 
   // Shout logs to the channel, and also to the real stderr if logging
   // is currently redirected to a file.
@@ -297,7 +459,8 @@ func (logger{{.Name}}) {{with $sev}}{{.Name}}{{end}}f(ctx context.Context, forma
 
 // V{{with $sev}}{{.Name}}{{end}}f logs to the {{.NAME}} channel with severity {{with $sev}}{{.NAME}}{{end}},
 // if logging has been enabled for the source file where the call is
-// performed at the provided verbosity level, via the vmodule setting.
+// performed at the provided verbosity level, via the vmodule setting
+// of all channels or that of the {{.NAME}} channel.
 // It extracts log tags from the context and logs them along with the given
 // message. Arguments are handled in the manner of fmt.Printf.
 //
@@ -305,7 +468,7 @@ func (logger{{.Name}}) {{with $sev}}{{.Name}}{{end}}f(ctx context.Context, forma
 //
 {{with $sev}}{{.Comment}}{{end -}}
 func (logger{{.Name}}) V{{with $sev}}{{.Name}}{{end}}f(ctx context.Context, level Level, format string, args ...interface{}) {
-  if VDepth(level, 1) {
+  if vDepthForChannel(ctx, level, 1, channel.{{.NAME}}) {
     logfDepth(ctx, 1, severity.{{with $sev}}{{.NAME}}{{end}}, channel.{{.NAME}}, format, args...)
   }
 }
@@ -333,6 +496,21 @@ func (logger{{.Name}}) {{with $sev}}{{.Name}}{{end}}fDepth(ctx context.Context,
   logfDepth(ctx, depth+1, severity.{{with $sev}}{{.NAME}}{{end}}, channel.{{.NAME}}, format, args...)
 }
 
+{{if eq $sev.NAME "INFO" "WARNING" "ERROR"}}
+// {{with $sev}}{{.Name}}{{end}}fEvery logs to the {{.NAME}} channel with severity {{with $sev}}{{.NAME}}{{end}},
+// unless an entry was already logged from the same location in the
+// source code in the last 'every' duration.
+// It extracts log tags from the context and logs them along with the given
+// message. Arguments are handled in the manner of fmt.Printf.
+//
+{{.Comment -}}
+//
+{{with $sev}}{{.Comment}}{{end -}}
+func (logger{{.Name}}) {{with $sev}}{{.Name}}{{end}}fEvery(ctx context.Context, every time.Duration, format string, args ...interface{}) {
+  logfEveryDepth(ctx, 1, severity.{{with $sev}}{{.NAME}}{{end}}, channel.{{.NAME}}, every, format, args...)
+}
+{{end}}{{- /* end severity in INFO, WARNING, ERROR */ -}}
+
 {{if .NAME|eq "DEV"}}
 // {{with $sev}}{{.Name}}{{end}}f logs to the {{.NAME}} channel with severity {{with $sev}}{{.NAME}}{{end}},
 // if logging has been enabled for the source file where the call is
@@ -355,7 +533,7 @@ func {{with $sev}}{{.Name}}{{end}}f(ctx context.Context, format string, args ...
 //
 {{with $sev}}{{.Comment}}{{end -}}
 func V{{with $sev}}{{.Name}}{{end}}f(ctx context.Context, level Level, format string, args ...interface{}) {
-  if VDepth(level, 1) {
+  if vDepthForChannel(ctx, level, 1, channel.{{.NAME}}) {
     logfDepth(ctx, 1, severity.{{with $sev}}{{.NAME}}{{end}}, channel.{{.NAME}}, format, args...)
   }
 }
@@ -382,6 +560,21 @@ func {{with $sev}}{{.Name}}{{end}}(ctx context.Context, msg string) {
 func {{with $sev}}{{.Name}}{{end}}fDepth(ctx context.Context, depth int, format string, args ...interface{}) {
   logfDepth(ctx, depth+1, severity.{{with $sev}}{{.NAME}}{{end}}, channel.{{.NAME}}, format, args...)
 }
+
+{{if eq $sev.NAME "INFO" "WARNING" "ERROR"}}
+// {{with $sev}}{{.Name}}{{end}}fEvery logs to the {{.NAME}} channel with severity {{with $sev}}{{.NAME}}{{end}},
+// unless an entry was already logged from the same location in the
+// source code in the last 'every' duration.
+// It extracts log tags from the context and logs them along with the given
+// message. Arguments are handled in the manner of fmt.Printf.
+//
+{{.Comment -}}
+//
+{{with $sev}}{{.Comment}}{{end -}}
+func {{with $sev}}{{.Name}}{{end}}fEvery(ctx context.Context, every time.Duration, format string, args ...interface{}) {
+  logfEveryDepth(ctx, 1, severity.{{with $sev}}{{.NAME}}{{end}}, channel.{{.NAME}}, every, format, args...)
+}
+{{end}}{{- /* end severity in INFO, WARNING, ERROR */ -}}
 {{end}}{{- /* end channel name = DEV */ -}}
 
 {{end}}{{end}}{{end}}{{- /* end range severities */ -}}
@@ -425,5 +618,129 @@ func Shoutf(ctx context.Context, sev Severity, format string, args ...interface{
 {{end}}{{- /* end channel name = DEV */ -}}
 
 {{end}}{{- /* end range channels */ -}}
+`,
+
+	"log_metrics.go": `// Code generated by gen/main.go. DO NOT EDIT.
+
+package log
+
+import (
+  "sync/atomic"
+
+  "github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+  "github.com/cockroachdb/cockroach/pkg/util/log/severity"
+)
+
+// MetricSeverities lists the severities of the log entries counted in
+// Metrics, in the order of the second dimension of Metrics.Count.
+var MetricSeverities = [...]Severity{
+  {{range .Severities}}{{if ge .MetricIndex 0 -}}
+  severity.{{.NAME}},
+  {{end}}{{end}}
+}
+
+// Metrics counts the log entries passed on to the sinks since the
+// process started, per channel and severity. The entries discarded by
+// the rate limiter or by the budget of their channel are not counted.
+// The counts are exported as the log.messages.<channel>.<severity>.count
+// metrics of the nodes.
+var Metrics struct {
+  // Count is indexed by channel, and by the index of the severity in
+  // MetricSeverities. Its items must be accessed atomically.
+  Count [logpb.Channel_CHANNEL_MAX][len(MetricSeverities)]int64
+}
+
+// MetricSeverityIndex returns the index of the given severity in
+// MetricSeverities, or -1 if the log entries with that severity are not
+// counted.
+func MetricSeverityIndex(sev Severity) int {
+  switch sev {
+  {{range .Severities}}{{if ge .MetricIndex 0 -}}
+  case severity.{{.NAME}}:
+    return {{.MetricIndex}}
+  {{end}}{{end -}}
+  default:
+    return -1
+  }
+}
+
+// EntryCount returns the number of log entries passed on to the sinks
+// on the given channel with the given severity since the process
+// started.
+func EntryCount(ch Channel, sev Severity) int64 {
+  i := MetricSeverityIndex(sev)
+  if i < 0 || ch < 0 || ch >= logpb.Channel_CHANNEL_MAX {
+    return 0
+  }
+  return atomic.LoadInt64(&Metrics.Count[ch][i])
+}
+
+// countEntry accounts for a log entry passed on to the sinks. It is
+// called by logfDepthInternal, once the entry has gone through the rate
+// limiter and the budget of its channel.
+func countEntry(ch Channel, sev Severity) {
+  i := MetricSeverityIndex(sev)
+  if i < 0 || ch < 0 || ch >= logpb.Channel_CHANNEL_MAX {
+    return
+  }
+  atomic.AddInt64(&Metrics.Count[ch][i], 1)
+}
+`,
+
+	"zap_adapter.go": `// Code generated by gen/main.go. DO NOT EDIT.
+
+package logzap
+
+import (
+  "context"
+
+  "github.com/cockroachdb/cockroach/pkg/util/log"
+  "github.com/cockroachdb/cockroach/pkg/util/log/channel"
+  "github.com/cockroachdb/cockroach/pkg/util/log/severity"
+)
+
+// channelLoggers maps each logging channel to its logger.
+var channelLoggers = [...]log.ChannelLogger{
+  {{range .Channels -}}
+  channel.{{.NAME}}: log.{{.Name}},
+  {{end}}
+}
+
+// logfDepth logs to the given channel with the given severity.
+// Severities that do not correspond to a logging function are
+// reported as INFO.
+func logfDepth(
+  ctx context.Context, depth int, ch log.Channel, sev log.Severity, format string, args ...interface{},
+) {
+  l := channelLoggers[ch]
+  switch sev {
+  {{range .Severities}}{{if eq .NAME "NONE" "UNKNOWN" "DEFAULT" "INFO"|not -}}
+  case severity.{{.NAME}}:
+    l.{{.Name}}fDepth(ctx, depth+1, format, args...)
+  {{end}}{{end -}}
+  default:
+    l.InfofDepth(ctx, depth+1, format, args...)
+  }
+}
+`,
+
+	"test_log_capture.go": `// Code generated by gen/main.go. DO NOT EDIT.
+
+package log
+
+import (
+  "github.com/cockroachdb/cockroach/pkg/util/log/channel"
+  "github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+)
+
+{{range .Channels -}}
+// Captured{{.Name}} returns the entries logged to the {{.NAME}} channel
+// since the scope was created with ScopeWithCapture. The {{.NAME}}
+// channel must have been passed to ScopeWithCapture.
+func (l *TestLogScope) Captured{{.Name}}() []logpb.Entry {
+  return l.Captured(channel.{{.NAME}})
+}
+
+{{end}}
 `,
 }
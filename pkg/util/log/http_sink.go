@@ -14,11 +14,13 @@ import (
 	"bytes"
 	"crypto/tls"
 	"fmt"
+	"net"
 	"net/http"
 	"net/url"
 
 	"github.com/cockroachdb/cockroach/pkg/cli/exit"
 	"github.com/cockroachdb/cockroach/pkg/util/log/logconfig"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 )
 
@@ -39,10 +41,37 @@ func newHTTPSink(c logconfig.HTTPSinkConfig) (*httpSink, error) {
 		address:     *c.Address,
 		doRequest:   doPost,
 		contentType: "application/octet-stream",
+		sign:        *c.SignWithNodeCert,
 	}
 
-	if *c.UnsafeTLS {
-		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	if *c.UnsafeTLS || c.ClientCert != nil {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: *c.UnsafeTLS}
+	}
+	if c.ClientCert != nil {
+		cert, err := tls.LoadX509KeyPair(*c.ClientCert, *c.ClientKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "loading client certificate")
+		}
+		transport.TLSClientConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if len(c.Headers) > 0 {
+		hs.headers = make(http.Header, len(c.Headers))
+		for name, value := range c.Headers {
+			hs.headers.Set(name, value)
+		}
+	}
+
+	if c.AWSSigV4.IsEnabled() {
+		creds, err := awsCredentialsFromEnv()
+		if err != nil {
+			return nil, err
+		}
+		hs.awsSigner = &awsSigner{
+			creds:   creds,
+			region:  *c.AWSSigV4.Region,
+			service: *c.AWSSigV4.Service,
+		}
 	}
 
 	if string(*c.Method) == http.MethodGet {
@@ -68,6 +97,17 @@ type httpSink struct {
 	contentType string
 	doRequest   func(sink *httpSink, logEntry []byte) (*http.Response, error)
 	config      *logconfig.HTTPSinkConfig
+
+	// sign indicates whether the requests are signed with the node
+	// certificate. See signRequest().
+	sign bool
+
+	// headers are the static headers added to every request.
+	headers http.Header
+
+	// awsSigner, if set, signs the requests with AWS Signature Version
+	// 4.
+	awsSigner *awsSigner
 }
 
 // output emits some formatted bytes to this sink.
@@ -94,16 +134,39 @@ func (hs *httpSink) output(b []byte, opt sinkOutputOptions) (err error) {
 }
 
 func doPost(hs *httpSink, b []byte) (*http.Response, error) {
-	resp, err := hs.client.Post(hs.address, hs.contentType, bytes.NewReader(b))
+	req, err := http.NewRequest(http.MethodPost, hs.address, bytes.NewReader(b))
 	if err != nil {
 		return nil, err
 	}
-	resp.Body.Close() // don't care about content
-	return resp, nil
+	req.Header.Set("Content-Type", hs.contentType)
+	return hs.do(req, b, b)
 }
 
 func doGet(hs *httpSink, b []byte) (*http.Response, error) {
-	resp, err := hs.client.Get(hs.address + "?" + url.QueryEscape(string(b)))
+	req, err := http.NewRequest(http.MethodGet, hs.address+"?"+url.QueryEscape(string(b)), nil)
+	if err != nil {
+		return nil, err
+	}
+	return hs.do(req, b, nil /* body */)
+}
+
+// do sends the request carrying the given log entries, with the given
+// body, after adding the static headers and signing it if configured
+// to do so. The AWS signature comes last, so that it covers the other
+// headers.
+func (hs *httpSink) do(req *http.Request, b []byte, body []byte) (*http.Response, error) {
+	for name, values := range hs.headers {
+		req.Header[name] = values
+	}
+	if hs.sign {
+		if err := signRequest(req, b); err != nil {
+			return nil, err
+		}
+	}
+	if hs.awsSigner != nil {
+		hs.awsSigner.sign(req, body, timeutil.Now())
+	}
+	resp, err := hs.client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -116,6 +179,25 @@ func (*httpSink) active() bool {
 	return true
 }
 
+// probe implements the sinkProber interface. It checks that a
+// connection can be established with the HTTP server, without sending
+// any request.
+func (hs *httpSink) probe() error {
+	u, err := url.Parse(hs.address)
+	if err != nil {
+		return err
+	}
+	addr := u.Host
+	if u.Port() == "" {
+		port := "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+		addr = net.JoinHostPort(u.Hostname(), port)
+	}
+	return probeDial("tcp", addr, nil /* tlsConf */)
+}
+
 // attachHints attaches some hints about the location of the message
 // to the stack message.
 func (*httpSink) attachHints(stacks []byte) []byte {
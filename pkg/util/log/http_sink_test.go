@@ -12,13 +12,25 @@ package log
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
 	"io"
+	"io/ioutil"
+	"math/big"
 	"net"
 	"net/http"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/util/envutil"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log/channel"
 	"github.com/cockroachdb/cockroach/pkg/util/log/logconfig"
@@ -293,3 +305,181 @@ func TestHTTPSinkContentTypePlainText(t *testing.T) {
 
 	testBase(t, defaults, testFn, false /* hangServer */, time.Duration(0))
 }
+
+// TestHTTPSinkSignature verifies that the requests of an HTTP sink
+// configured with sign-with-node-cert carry a valid signature of the
+// log entries.
+func TestHTTPSinkSignature(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "node"},
+		NotBefore:    timeutil.Now(),
+		NotAfter:     timeutil.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+	SetNodeCertificateFn(func() (*tls.Certificate, error) {
+		return &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+	})
+	defer SetNodeCertificateFn(nil)
+
+	address := "http://localhost" // testBase appends the port
+	timeout := 5 * time.Second
+	tb := true
+	defaults := logconfig.HTTPDefaults{
+		Address:          &address,
+		Timeout:          &timeout,
+		SignWithNodeCert: &tb,
+
+		// We need to disable keepalives otherwise the HTTP server in the
+		// test will let an async goroutine run waiting for more requests.
+		DisableKeepAlives: &tb,
+		CommonSinkConfig: logconfig.CommonSinkConfig{
+			Buffering: disabledBufferingCfg,
+		},
+	}
+
+	testFn := func(header http.Header, body string) error {
+		t.Log(body)
+		if alg := header.Get(signatureAlgorithmHeader); alg != x509.ECDSAWithSHA256.String() {
+			return errors.Newf("unexpected signature algorithm: %s", alg)
+		}
+		certDER, err := base64.StdEncoding.DecodeString(header.Get(signatureCertificateHeader))
+		if err != nil {
+			return err
+		}
+		cert, err := x509.ParseCertificate(certDER)
+		if err != nil {
+			return err
+		}
+		sig, err := base64.StdEncoding.DecodeString(header.Get(signatureHeader))
+		if err != nil {
+			return err
+		}
+		return cert.CheckSignature(x509.ECDSAWithSHA256, []byte(body), sig)
+	}
+
+	testBase(t, defaults, testFn, false /* hangServer */, time.Duration(0))
+}
+
+// TestHTTPSinkHeadersAndAWSSignature verifies that the requests carry
+// the static headers and are signed with AWS Signature Version 4 when
+// configured to do so.
+func TestHTTPSinkHeadersAndAWSSignature(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	defer envutil.TestSetEnv(t, "AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")()
+	defer envutil.TestSetEnv(t, "AWS_SECRET_ACCESS_KEY", "secret")()
+	defer envutil.TestSetEnv(t, "AWS_SESSION_TOKEN", "token")()
+
+	address := "http://localhost" // testBase appends the port
+	timeout := 5 * time.Second
+	tb := true
+	region, service := "us-east-1", "es"
+	defaults := logconfig.HTTPDefaults{
+		Address: &address,
+		Timeout: &timeout,
+		Headers: map[string]string{"Authorization-Token": "Bearer foo"},
+		AWSSigV4: logconfig.AWSSigV4Config{
+			Region:  &region,
+			Service: &service,
+		},
+
+		// We need to disable keepalives otherwise the HTTP server in the
+		// test will let an async goroutine run waiting for more requests.
+		DisableKeepAlives: &tb,
+		CommonSinkConfig: logconfig.CommonSinkConfig{
+			Buffering: disabledBufferingCfg,
+		},
+	}
+
+	testFn := func(header http.Header, body string) error {
+		t.Log(body)
+		if v := header.Get("Authorization-Token"); v != "Bearer foo" {
+			return errors.Newf("unexpected Authorization-Token header: %q", v)
+		}
+		if v := header.Get(awsSecurityTokenHeader); v != "token" {
+			return errors.Newf("unexpected security token: %q", v)
+		}
+		now, err := time.Parse(awsTimeFormat, header.Get(awsDateHeader))
+		if err != nil {
+			return err
+		}
+		// Sign the same request again, and check that the signatures match.
+		req, err := http.NewRequest(http.MethodPost, address, strings.NewReader(body))
+		if err != nil {
+			return err
+		}
+		s := &awsSigner{
+			creds:   awsCredentials{accessKeyID: "AKIDEXAMPLE", secretAccessKey: "secret", sessionToken: "token"},
+			region:  region,
+			service: service,
+		}
+		s.sign(req, []byte(body), now)
+		if expected, actual := req.Header.Get("Authorization"), header.Get("Authorization"); expected != actual {
+			return errors.Newf("expected Authorization header %q, got %q", expected, actual)
+		}
+		return nil
+	}
+
+	testBase(t, defaults, testFn, false /* hangServer */, time.Duration(0))
+}
+
+// TestHTTPSinkClientCert verifies that the client certificate of the
+// sink is loaded into its TLS configuration.
+func TestHTTPSinkClientCert(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	sc := ScopeWithoutShowLogs(t)
+	defer sc.Close(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "client"},
+		NotBefore:    timeutil.Now(),
+		NotAfter:     timeutil.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	certPath := filepath.Join(sc.logDir, "client.crt")
+	keyPath := filepath.Join(sc.logDir, "client.key")
+	require.NoError(t, ioutil.WriteFile(certPath,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, ioutil.WriteFile(keyPath,
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+
+	address := "https://localhost"
+	cfg := logconfig.DefaultConfig()
+	cfg.Sinks.HTTPServers = map[string]*logconfig.HTTPSinkConfig{
+		"ops": {
+			HTTPDefaults: logconfig.HTTPDefaults{
+				Address:    &address,
+				ClientCert: &certPath,
+				ClientKey:  &keyPath,
+			},
+			Channels: logconfig.SelectChannels(channel.OPS)},
+	}
+	require.NoError(t, cfg.Validate(&sc.logDir))
+
+	hs, err := newHTTPSink(*cfg.Sinks.HTTPServers["ops"])
+	require.NoError(t, err)
+	tlsConfig := hs.client.Transport.(*http.Transport).TLSClientConfig
+	require.False(t, tlsConfig.InsecureSkipVerify)
+	require.Len(t, tlsConfig.Certificates, 1)
+	require.Equal(t, der, tlsConfig.Certificates[0].Certificate[0])
+
+	// The sink can't be created if the certificate can't be loaded.
+	missing := filepath.Join(sc.logDir, "missing.crt")
+	c := *cfg.Sinks.HTTPServers["ops"]
+	c.ClientCert = &missing
+	_, err = newHTTPSink(c)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "loading client certificate")
+}
@@ -62,6 +62,8 @@ func (l *loggingT) newInterceptorSinkInfo() *sinkInfo {
 		formatter:  formatInterceptor{},
 		redact:     false, // do not redact sensitive information
 		redactable: true,  // keep redaction markers
+		// Interceptors see all events regardless of filtering.
+		ignoreSeverityOverrides: true,
 	}
 	// Ensure all events are collected across all channels.
 	si.threshold.setAll(severity.INFO)
@@ -0,0 +1,221 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/cli/exit"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logconfig"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// journaldSink represents the systemd journal of the local host.
+//
+// The entries are formatted by a journaldFormatter, which produces
+// messages in the native protocol of the journal. Every entry is sent
+// in a separate datagram over the socket of the journal.
+//
+// The entries larger than the maximum datagram size of the host, which
+// the native protocol transfers via memfds instead, are not supported.
+// max-entry-size can be used to bound the size of the entries.
+type journaldSink struct {
+	socket string
+
+	// config is the configuration this sink was created with. It is
+	// used by DescribeAppliedConfig().
+	config *logconfig.JournaldSinkConfig
+
+	mu struct {
+		syncutil.Mutex
+		// good indicates that the connection can be used.
+		good bool
+		conn net.Conn
+	}
+}
+
+const journaldWriteTimeout = time.Second
+
+func newJournaldSink(c logconfig.JournaldSinkConfig) (*journaldSink, error) {
+	if runtime.GOOS != "linux" {
+		return nil, errors.Newf("journald sinks are only supported on Linux")
+	}
+	return &journaldSink{
+		socket: *c.Socket,
+		config: &c,
+	}, nil
+}
+
+func (l *journaldSink) String() string {
+	return "journald:" + l.socket
+}
+
+// active implements the logSink interface.
+func (l *journaldSink) active() bool { return true }
+
+// probe implements the sinkProber interface.
+func (l *journaldSink) probe() error {
+	return probeDial("unixgram", l.socket, nil /* tlsConf */)
+}
+
+// attachHints implements the logSink interface.
+func (l *journaldSink) attachHints(stacks []byte) []byte {
+	return stacks
+}
+
+// exitCode implements the logSink interface.
+func (l *journaldSink) exitCode() exit.Code {
+	return exit.LoggingNetCollectorUnavailable()
+}
+
+// output implements the logSink interface.
+func (l *journaldSink) output(b []byte, opts sinkOutputOptions) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_ = l.tryWriteLocked(b)
+	if l.mu.good {
+		return nil
+	}
+	if err := l.ensureConnLocked(b); err != nil {
+		return err
+	}
+	return l.tryWriteLocked(b)
+}
+
+func (l *journaldSink) closeLocked() {
+	l.mu.good = false
+	if l.mu.conn != nil {
+		if err := l.mu.conn.Close(); err != nil {
+			fmt.Fprintf(OrigStderr, "error closing journald connection: %v\n", err)
+		}
+		l.mu.conn = nil
+	}
+}
+
+func (l *journaldSink) ensureConnLocked(b []byte) error {
+	if l.mu.good {
+		return nil
+	}
+	l.closeLocked()
+	var err error
+	l.mu.conn, err = net.Dial("unixgram", l.socket)
+	if err != nil {
+		fmt.Fprintf(OrigStderr, "%s: error connecting to the journal: %v\n%s", l, err, b)
+		return err
+	}
+	fmt.Fprintf(OrigStderr, "%s: connection to the journal resumed\n", l)
+	l.mu.good = true
+	return nil
+}
+
+func (l *journaldSink) tryWriteLocked(b []byte) error {
+	if !l.mu.good {
+		return errNoConn
+	}
+	if err := l.mu.conn.SetWriteDeadline(timeutil.Now().Add(journaldWriteTimeout)); err != nil {
+		// An error here is suggestive of a bug in the Go runtime.
+		fmt.Fprintf(OrigStderr, "%s: set write deadline error: %v\n%s",
+			l, err, b)
+		l.mu.good = false
+		return err
+	}
+	n, err := l.mu.conn.Write(b)
+	if err != nil || n < len(b) {
+		fmt.Fprintf(OrigStderr, "%s: logging error: %v or short write (%d/%d)\n%s",
+			l, err, n, len(b), b)
+		l.mu.good = false
+	}
+	return err
+}
+
+// journaldFormatter wraps another formatter to produce messages in the
+// native protocol of the journal. The output of the wrapped formatter
+// is used as the MESSAGE field.
+type journaldFormatter struct {
+	inner logFormatter
+	// header holds the fields that are common to all messages:
+	// SYSLOG_IDENTIFIER and SYSLOG_PID.
+	header []byte
+}
+
+func newJournaldFormatter(inner logFormatter, identifier string) *journaldFormatter {
+	var hdr bytes.Buffer
+	appendJournaldField(&hdr, "SYSLOG_IDENTIFIER", []byte(identifier))
+	appendJournaldField(&hdr, "SYSLOG_PID", []byte(strconv.Itoa(fileNameConstants.pid)))
+	return &journaldFormatter{
+		inner:  inner,
+		header: hdr.Bytes(),
+	}
+}
+
+// formatterName implements the logFormatter interface. The name of the
+// wrapped formatter is reported, since this is the format selected in
+// the configuration.
+func (f *journaldFormatter) formatterName() string { return f.inner.formatterName() }
+
+// doc implements the logFormatter interface.
+func (f *journaldFormatter) doc() string { return f.inner.doc() }
+
+// contentType implements the logFormatter interface.
+func (f *journaldFormatter) contentType() string { return f.inner.contentType() }
+
+// formatEntry implements the logFormatter interface.
+func (f *journaldFormatter) formatEntry(entry logEntry) *buffer {
+	msg := f.inner.formatEntry(entry)
+	defer putBuffer(msg)
+
+	buf := getBuffer()
+	sev := severity.INFO
+	if !entry.header {
+		sev = entry.sev
+	}
+	appendJournaldField(&buf.Buffer, "PRIORITY", []byte(strconv.Itoa(syslogSeverity(sev))))
+	buf.Write(f.header)
+	if !entry.header {
+		appendJournaldField(&buf.Buffer, "CRDB_CHANNEL", []byte(entry.ch.String()))
+		appendJournaldField(&buf.Buffer, "CRDB_SEVERITY", []byte(sev.String()))
+		if entry.file != "" {
+			appendJournaldField(&buf.Buffer, "CODE_FILE", []byte(entry.file))
+			appendJournaldField(&buf.Buffer, "CODE_LINE", []byte(strconv.Itoa(entry.line)))
+		}
+	}
+	appendJournaldField(&buf.Buffer, "MESSAGE", bytes.TrimRight(msg.Bytes(), "\n"))
+	return buf
+}
+
+// appendJournaldField appends a field to a message in the native
+// protocol of the journal. The values which contain newlines are
+// serialized in the binary form of the protocol, where the value is
+// preceded by its length.
+func appendJournaldField(buf *bytes.Buffer, name string, value []byte) {
+	buf.WriteString(name)
+	if bytes.IndexByte(value, '\n') < 0 {
+		buf.WriteByte('=')
+		buf.Write(value)
+		buf.WriteByte('\n')
+		return
+	}
+	var size [8]byte
+	binary.LittleEndian.PutUint64(size[:], uint64(len(value)))
+	buf.WriteByte('\n')
+	buf.Write(size[:])
+	buf.Write(value)
+	buf.WriteByte('\n')
+}
@@ -0,0 +1,147 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"net"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log/channel"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logconfig"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJournaldSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	if runtime.GOOS != "linux" {
+		t.Skip("journald sinks are only supported on Linux")
+	}
+
+	sc := ScopeWithoutShowLogs(t)
+	defer sc.Close(t)
+
+	// Serve the native protocol in the log directory, which is
+	// short-lived and whose path is short enough for a socket.
+	socket := filepath.Join(sc.logDir, "journal.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, conn.Close()) }()
+	recv := func() map[string]string {
+		require.NoError(t, conn.SetReadDeadline(timeutil.Now().Add(5*time.Second)))
+		buf := make([]byte, 65536)
+		n, err := conn.Read(buf)
+		require.NoError(t, err)
+		return parseJournaldFields(t, buf[:n])
+	}
+
+	identifier := "crdb-test"
+	cfg := logconfig.DefaultConfig()
+	cfg.Sinks.JournaldSinks = map[string]*logconfig.JournaldSinkConfig{
+		"ops": {
+			Channels:   logconfig.SelectChannels(channel.OPS),
+			Identifier: &identifier,
+			Socket:     &socket,
+		},
+	}
+	require.NoError(t, cfg.Validate(&sc.logDir))
+
+	TestingResetActive()
+	cleanupCfg, err := ApplyConfig(cfg)
+	require.NoError(t, err)
+	defer cleanupCfg()
+
+	ctx := context.Background()
+	Ops.Infof(ctx, "hello world")
+	Ops.Warningf(ctx, "hello again")
+
+	for _, exp := range []struct {
+		priority int
+		sev      string
+		msg      string
+	}{
+		{6, "INFO", "hello world"},
+		{4, "WARNING", "hello again"},
+	} {
+		fields := recv()
+		require.Equal(t, strconv.Itoa(exp.priority), fields["PRIORITY"])
+		require.Equal(t, identifier, fields["SYSLOG_IDENTIFIER"])
+		require.Equal(t, strconv.Itoa(fileNameConstants.pid), fields["SYSLOG_PID"])
+		require.Equal(t, "OPS", fields["CRDB_CHANNEL"])
+		require.Equal(t, exp.sev, fields["CRDB_SEVERITY"])
+		require.Equal(t, "util/log/journald_sink_test.go", fields["CODE_FILE"])
+		require.Contains(t, fields["MESSAGE"], exp.msg)
+	}
+}
+
+func TestJournaldFormatter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	f := newJournaldFormatter(formatCrdbV2{}, "cockroach")
+	entry := makeUnstructuredEntry(ctx, severity.ERROR, channel.HEALTH, 0, true, "hello %s", "world")
+	entry.file, entry.line = "util/log/journald_sink_test.go", 123
+	b := f.formatEntry(entry)
+	defer putBuffer(b)
+
+	fields := parseJournaldFields(t, b.Bytes())
+	require.Equal(t, "3", fields["PRIORITY"])
+	require.Equal(t, "cockroach", fields["SYSLOG_IDENTIFIER"])
+	require.Equal(t, "HEALTH", fields["CRDB_CHANNEL"])
+	require.Equal(t, "ERROR", fields["CRDB_SEVERITY"])
+	require.Equal(t, "util/log/journald_sink_test.go", fields["CODE_FILE"])
+	require.Equal(t, "123", fields["CODE_LINE"])
+	require.Contains(t, fields["MESSAGE"], "hello ‹world›")
+	require.NotContains(t, fields["MESSAGE"], "\n")
+}
+
+func TestAppendJournaldField(t *testing.T) {
+	var buf bytes.Buffer
+	appendJournaldField(&buf, "A", []byte("one line"))
+	appendJournaldField(&buf, "B", []byte("two\nlines"))
+	require.Equal(t, "A=one line\nB\n\x09\x00\x00\x00\x00\x00\x00\x00two\nlines\n", buf.String())
+	require.Equal(t,
+		map[string]string{"A": "one line", "B": "two\nlines"},
+		parseJournaldFields(t, buf.Bytes()))
+}
+
+// parseJournaldFields parses a message in the native protocol of the
+// journal.
+func parseJournaldFields(t *testing.T, b []byte) map[string]string {
+	fields := make(map[string]string)
+	for len(b) > 0 {
+		nl := bytes.IndexByte(b, '\n')
+		require.True(t, nl >= 0, "unterminated field: %q", b)
+		line := b[:nl]
+		b = b[nl+1:]
+		if eq := bytes.IndexByte(line, '='); eq >= 0 {
+			fields[string(line[:eq])] = string(line[eq+1:])
+			continue
+		}
+		// Binary field: the name is followed by the length of the value.
+		require.True(t, len(b) >= 8, "truncated field: %q", line)
+		n := int(binary.LittleEndian.Uint64(b[:8]))
+		require.True(t, len(b) >= 8+n+1, "truncated field: %q", line)
+		fields[string(line)] = string(b[8 : 8+n])
+		require.Equal(t, byte('\n'), b[8+n])
+		b = b[8+n+1:]
+	}
+	return fields
+}
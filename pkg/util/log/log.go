@@ -39,7 +39,8 @@ func V(level Level) bool {
 // ExpensiveLogEnabled is used to test whether effort should be used to produce
 // log messages whose construction has a measurable cost. It returns true if
 // either the current context is recording the trace, or if the caller's
-// verbosity is above level.
+// verbosity is above level, including the verbosity configured for the log
+// tags of the context by SetVModuleForTags.
 //
 // NOTE: This doesn't take into consideration whether tracing is generally
 // enabled or whether a trace.EventLog or a trace.Trace (i.e. sp.netTr) is
@@ -62,7 +63,7 @@ func ExpensiveLogEnabled(ctx context.Context, level Level) bool {
 			return true
 		}
 	}
-	if VDepth(level, 1 /* depth */) {
+	if vDepthForContext(ctx, level, 1 /* depth */) {
 		return true
 	}
 	return false
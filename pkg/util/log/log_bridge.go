@@ -14,6 +14,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	stdLog "log"
 	"regexp"
 	"strconv"
@@ -21,6 +22,9 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/util/log/channel"
 	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/logtags"
 	"github.com/cockroachdb/redact"
 )
 
@@ -106,6 +110,162 @@ func (lb logBridge) Write(b []byte) (n int, err error) {
 		}
 		entry.line = int(lineno)
 	}
-	debugLog.outputLogEntry(entry)
+	debugLog.outputLogEntry(ctx, entry)
 	return len(b), nil
 }
+
+// NewThirdPartyLogWriter returns an io.Writer which re-emits the lines
+// written to it by a third-party library to the DEV channel. It is
+// meant to be passed to the libraries which let their log output be
+// redirected, e.g. via a *stdLog.Logger or flag.CommandLine.Output(),
+// so that this output obeys the logging configuration.
+//
+// The log tags in the provided context are included in every entry,
+// along with a "lib" tag set to the given library name.
+//
+// Each line is parsed to extract its severity and source location:
+//
+//   - glog-style headers (e.g. "W0102 15:04:05.123456 1234 foo.go:12] ")
+//     determine both.
+//   - headers produced by the Go "log" package, with any combination of
+//     the date, time and file flags, determine the source location.
+//   - level prefixes such as "[WARN]", used by many libraries, determine
+//     the severity.
+//
+// FATAL lines are reported as ERROR, since the library terminates the
+// process by itself. The lines with no severity information are
+// reported with severity defaultSev. Since their contents are unknown,
+// all the lines are considered unsafe for reporting.
+func NewThirdPartyLogWriter(ctx context.Context, lib string, defaultSev Severity) io.Writer {
+	return &thirdPartyLogWriter{
+		ctx:        logtags.AddTag(ctx, "lib", redact.Safe(lib)),
+		lib:        lib,
+		defaultSev: defaultSev,
+	}
+}
+
+// NewThirdPartyStdLogger creates a *stdLog.Logger which re-emits its
+// output to the DEV channel. See NewThirdPartyLogWriter for details.
+func NewThirdPartyStdLogger(ctx context.Context, lib string, defaultSev Severity) *stdLog.Logger {
+	return stdLog.New(NewThirdPartyLogWriter(ctx, lib, defaultSev), "", stdLog.Lshortfile)
+}
+
+// maxThirdPartyLineLength is the length above which an incomplete line
+// written to a thirdPartyLogWriter is emitted without waiting for its
+// end.
+const maxThirdPartyLineLength = 64 << 10
+
+type thirdPartyLogWriter struct {
+	ctx        context.Context
+	lib        string
+	defaultSev Severity
+
+	mu struct {
+		syncutil.Mutex
+		// buf contains the last incomplete line.
+		buf []byte
+	}
+}
+
+// Write implements the io.Writer interface.
+func (w *thirdPartyLogWriter) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.mu.buf = append(w.mu.buf, b...)
+	rest := w.mu.buf
+	for {
+		i := bytes.IndexByte(rest, '\n')
+		if i < 0 {
+			break
+		}
+		w.emit(rest[:i])
+		rest = rest[i+1:]
+	}
+	if len(rest) > maxThirdPartyLineLength {
+		w.emit(rest)
+		rest = nil
+	}
+	w.mu.buf = append(w.mu.buf[:0], rest...)
+	return len(b), nil
+}
+
+// emit parses and emits a single line.
+func (w *thirdPartyLogWriter) emit(line []byte) {
+	line = bytes.TrimRight(line, "\r")
+	if len(bytes.TrimSpace(line)) == 0 {
+		return
+	}
+	sev, file, lineno, msg := parseThirdPartyLine(line, w.defaultSev)
+	entry := makeUnstructuredEntry(w.ctx, sev, channel.DEV, 0 /* depth */, true /* redactable */, "")
+	if file != "" {
+		// We use a prefix with the library name so that these log lines
+		// do not point to our own source directory.
+		entry.file = "(" + w.lib + ") " + file
+		entry.line = lineno
+	}
+	entry.payload = makeRedactablePayload(w.ctx, redact.Sprintf("%s", msg))
+	debugLog.outputLogEntry(w.ctx, entry)
+}
+
+var (
+	// glogHeaderRe matches the header of the glog (and klog) lines:
+	//   Lmmdd hh:mm:ss.uuuuuu threadid file:line] msg
+	glogHeaderRe = regexp.MustCompile(
+		`^([dDIWEF])\d{4} \d{2}:\d{2}:\d{2}\.\d{6}\s+\d+ ([^:\s\]]+):(\d+)\] ?`)
+	// stdLogHeaderRe matches the header of the lines produced by the Go
+	// "log" package, with any combination of the Ldate, Ltime,
+	// Lmicroseconds and Lshortfile or Llongfile flags.
+	stdLogHeaderRe = regexp.MustCompile(
+		`^(?:\d{4}/\d{2}/\d{2} )?(?:\d{2}:\d{2}:\d{2}(?:\.\d{6})? )?(?:([^:\s]+\.go):(\d+): )?`)
+	// levelPrefixRe matches the level prefixes used by many libraries.
+	levelPrefixRe = regexp.MustCompile(
+		`^\[(?i)(TRACE|DEBUG|INFO|WARN|WARNING|ERR|ERROR|FATAL)\]:? ?`)
+)
+
+// parseThirdPartyLine extracts the severity, the source location if any,
+// and the message from a line of third-party log output.
+func parseThirdPartyLine(
+	line []byte, defaultSev Severity,
+) (sev Severity, file string, lineno int, msg []byte) {
+	sev = defaultSev
+	if m := glogHeaderRe.FindSubmatchIndex(line); m != nil {
+		switch line[m[2]] {
+		case 'd':
+			sev = severity.DEBUG2
+		case 'D':
+			sev = severity.DEBUG1
+		case 'I':
+			sev = severity.INFO
+		case 'W':
+			sev = severity.WARNING
+		default:
+			sev = severity.ERROR
+		}
+		file = string(line[m[4]:m[5]])
+		lineno, _ = strconv.Atoi(string(line[m[6]:m[7]]))
+		return sev, file, lineno, line[m[1]:]
+	}
+	if m := stdLogHeaderRe.FindSubmatchIndex(line); m != nil {
+		if m[2] >= 0 {
+			file = string(line[m[2]:m[3]])
+			lineno, _ = strconv.Atoi(string(line[m[4]:m[5]]))
+		}
+		line = line[m[1]:]
+	}
+	if m := levelPrefixRe.FindSubmatchIndex(line); m != nil {
+		switch strings.ToUpper(string(line[m[2]:m[3]])) {
+		case "TRACE":
+			sev = severity.DEBUG2
+		case "DEBUG":
+			sev = severity.DEBUG1
+		case "INFO":
+			sev = severity.INFO
+		case "WARN", "WARNING":
+			sev = severity.WARNING
+		default:
+			sev = severity.ERROR
+		}
+		line = line[m[1]:]
+	}
+	return sev, file, lineno, line
+}
@@ -0,0 +1,105 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log/channel"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+	"github.com/cockroachdb/logtags"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseThirdPartyLine(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testCases := []struct {
+		line   string
+		sev    Severity
+		file   string
+		lineno int
+		msg    string
+	}{
+		{"hello world", severity.INFO, "", 0, "hello world"},
+		// glog.
+		{"I0102 15:04:05.123456    1234 foo.go:12] hello", severity.INFO, "foo.go", 12, "hello"},
+		{"W0102 15:04:05.123456 1234 foo.go:12] hello", severity.WARNING, "foo.go", 12, "hello"},
+		{"E0102 15:04:05.123456 1234 foo.go:12] hello", severity.ERROR, "foo.go", 12, "hello"},
+		{"F0102 15:04:05.123456 1234 foo.go:12] hello", severity.ERROR, "foo.go", 12, "hello"},
+		{"D0102 15:04:05.123456 1234 foo.go:12] hello", severity.DEBUG1, "foo.go", 12, "hello"},
+		{"d0102 15:04:05.123456 1234 foo.go:12] hello", severity.DEBUG2, "foo.go", 12, "hello"},
+		// Go "log" package.
+		{"foo.go:12: hello", severity.INFO, "foo.go", 12, "hello"},
+		{"2009/01/23 01:23:23 hello", severity.INFO, "", 0, "hello"},
+		{"2009/01/23 01:23:23.123123 /a/b/foo.go:12: hello", severity.INFO, "/a/b/foo.go", 12, "hello"},
+		// Level prefixes.
+		{"[WARN] hello", severity.WARNING, "", 0, "hello"},
+		{"[debug] hello", severity.DEBUG1, "", 0, "hello"},
+		{"[TRACE] hello", severity.DEBUG2, "", 0, "hello"},
+		{"[ERROR]: hello", severity.ERROR, "", 0, "hello"},
+		{"foo.go:12: [WARNING] hello", severity.WARNING, "foo.go", 12, "hello"},
+		{"[UNKNOWN] hello", severity.INFO, "", 0, "[UNKNOWN] hello"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.line, func(t *testing.T) {
+			sev, file, lineno, msg := parseThirdPartyLine([]byte(tc.line), severity.INFO)
+			require.Equal(t, tc.sev, sev)
+			require.Equal(t, tc.file, file)
+			require.Equal(t, tc.lineno, lineno)
+			require.Equal(t, tc.msg, string(msg))
+		})
+	}
+}
+
+func TestThirdPartyLogWriter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	sc := ScopeWithCapture(t, channel.DEV)
+	defer sc.Close(t)
+
+	ctx := logtags.AddTag(context.Background(), "n", 1)
+	w := NewThirdPartyLogWriter(ctx, "somelib", severity.INFO)
+	// The lines can be split across writes.
+	_, err := fmt.Fprint(w, "W0102 15:04:05.123456 1234 foo.go:12] first ")
+	require.NoError(t, err)
+	_, err = fmt.Fprint(w, "warning\n[ERROR] an error\n\n")
+	require.NoError(t, err)
+	NewThirdPartyStdLogger(ctx, "somelib", severity.INFO).Print("an info")
+	Flush()
+
+	var found int
+	for _, e := range sc.CapturedDev() {
+		if !strings.Contains(e.Tags, "lib=somelib") {
+			continue
+		}
+		found++
+		switch found {
+		case 1:
+			require.Contains(t, e.Tags, "n1")
+			require.Equal(t, severity.WARNING, e.Severity)
+			require.Equal(t, "(somelib) foo.go", e.File)
+			require.Equal(t, int64(12), e.Line)
+			require.Equal(t, "‹first warning›", e.Message)
+		case 2:
+			require.Equal(t, severity.ERROR, e.Severity)
+			require.Equal(t, "‹an error›", e.Message)
+		case 3:
+			require.Equal(t, severity.INFO, e.Severity)
+			require.Equal(t, "(somelib) log_bridge_test.go", e.File)
+			require.Equal(t, "‹an info›", e.Message)
+		}
+	}
+	require.Equal(t, 3, found)
+}
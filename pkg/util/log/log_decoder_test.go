@@ -1,4 +1,4 @@
-// Copyright 2021 The Cockroach Authors.
+// Copyright 2022 The Cockroach Authors.
 //
 // Use of this software is governed by the Business Source License
 // included in the file licenses/BSL.txt.
@@ -11,26 +11,137 @@
 package log
 
 import (
+	"context"
+	"io"
 	"strings"
 	"testing"
+	"time"
 
-	"github.com/cockroachdb/datadriven"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log/channel"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/logtags"
+	"github.com/stretchr/testify/require"
 )
 
-func TestReadLogFormat(t *testing.T) {
-	datadriven.RunTest(t, "testdata/read_header",
-		func(t *testing.T, td *datadriven.TestData) string {
-			switch td.Cmd {
-			case "log":
-				_, format, err := ReadFormatFromLogFile(strings.NewReader(td.Input))
-				if err != nil {
-					td.Fatalf(t, "error while reading format from the log file: %v", err)
+// TestEntryDecoderRoundTrip checks that the decoders restore the
+// fields emitted by the formatters.
+func TestEntryDecoderRoundTrip(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	tm, err := time.Parse(MessageTimeFormat, "060102 15:04:05.654321")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	ctx = logtags.AddTag(ctx, "noval", nil)
+	ctx = logtags.AddTag(ctx, "s", "1")
+	ctx = logtags.AddTag(ctx, "long", "2")
+
+	ids := idPayload{clusterID: "abc", nodeID: "1", tenantID: "2", sqlInstanceID: "3"}
+	testCases := []logEntry{
+		makeUnstructuredEntry(ctx, severity.INFO, channel.DEV, 0, true, "hello %s", "world"),
+		makeUnstructuredEntry(ctx, severity.WARNING, channel.OPS, 0, true, "hello\n%s", "world"),
+		makeStructuredEntry(ctx, severity.INFO, channel.SQL_SCHEMA, 0, &logpb.TestingStructuredLogEvent{
+			CommonEventDetails: logpb.CommonEventDetails{
+				Timestamp: 123,
+				EventType: "rename_database",
+			},
+			Channel: logpb.Channel_SQL_SCHEMA,
+			Event:   "rename from `hello` to `world`",
+		}),
+	}
+
+	formats := []logFormatter{
+		formatCrdbV2{},
+		formatFluentJSONCompact{},
+		formatFluentJSONFull{},
+		formatJSONCompact{},
+		formatJSONFull{},
+	}
+
+	timestamps := []*timestampOptions{
+		nil,
+		{loc: time.UTC},
+		{loc: time.FixedZone("", -5*60*60), delta: true},
+	}
+
+	for _, f := range formats {
+		for _, ts := range timestamps {
+			for _, tc := range testCases {
+				tc.idPayload = ids
+				tc.ts = tm.UnixNano()
+				tc.line = 123
+				tc.gid = 11
+				tc.counter = 42
+				tc.timestamps = ts
+				tc.delta = int64(1500 * time.Microsecond)
+
+				b := f.formatEntry(tc)
+				formatted := b.String()
+				putBuffer(b)
+
+				d, err := NewEntryDecoderWithFormat(
+					strings.NewReader(formatted), WithMarkedSensitiveData, f.formatterName())
+				require.NoError(t, err)
+				var e logpb.Entry
+				require.NoError(t, d.Decode(&e), formatted)
+
+				expected := tc.convertToLegacy()
+				if tc.structured {
+					// The decoders do not restore the prefix of the legacy format.
+					expected.Message = "{" + tc.payload.message + "}"
+					expected.StructuredStart = 0
+					expected.StructuredEnd = uint32(len(expected.Message))
+				}
+				if _, ok := f.(formatCrdbV2); ok {
+					// The server identifiers and the version are only reported
+					// in the header of crdb-v2 files.
+					expected.ClusterID, expected.NodeID, expected.TenantID = "", "", ""
+					expected.SQLInstanceID, expected.Version = "", ""
 				}
-				return format
-			default:
-				t.Fatalf("unknown directive: %q", td.Cmd)
+				require.Equal(t, expected, e, formatted)
+
+				require.Equal(t, io.EOF, d.Decode(&e))
 			}
-			// unreachable
-			return ""
-		})
+		}
+	}
+}
+
+// TestEntryDecoderDebugSeverities checks that the entries with the
+// DEBUG severities are read back from a crdb-v2 log file, including its
+// header lines.
+func TestEntryDecoderDebugSeverities(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	l := &sinkInfo{formatter: formatCrdbV2{}}
+	var buf strings.Builder
+	for _, b := range l.getStartLines(timeutil.Now()) {
+		buf.Write(b.Bytes())
+		putBuffer(b)
+	}
+	sevs := []Severity{severity.DEBUG2, severity.DEBUG1, severity.INFO}
+	for _, sev := range sevs {
+		b := formatCrdbV2{}.formatEntry(makeUnstructuredEntry(ctx, sev, channel.DEV, 0, true, "entry at %s", sev))
+		buf.Write(b.Bytes())
+		putBuffer(b)
+	}
+
+	d, err := NewEntryDecoder(strings.NewReader(buf.String()), WithMarkedSensitiveData)
+	require.NoError(t, err)
+	var decoded []Severity
+	for {
+		var e logpb.Entry
+		err := d.Decode(&e)
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		if strings.Contains(e.Message, "entry at") {
+			decoded = append(decoded, e.Severity)
+		}
+	}
+	require.Equal(t, sevs, decoded)
 }
@@ -20,6 +20,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/build"
 	"github.com/cockroachdb/cockroach/pkg/util/caller"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logdecoder"
 	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
 	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
@@ -74,6 +75,13 @@ type logEntry struct {
 	// The entry counter. Populated by outputLogEntry().
 	counter uint64
 
+	// The rendering options of the timestamp, and the time elapsed
+	// since the previous entry emitted to the same sink. Populated by
+	// outputLogEntry() for the sinks configured with timestamp options.
+	// Whether they are honored depends on the formatter.
+	timestamps *timestampOptions
+	delta      int64
+
 	// The stack trace(s), when processing e.g. a fatal event.
 	stacks []byte
 
@@ -233,6 +241,7 @@ func makeUnstructuredEntry(
 	res.structured = false
 
 	if redactable {
+		args = applySafeStringers(args)
 		var buf redact.StringBuilder
 		if len(args) == 0 {
 			// TODO(knz): Remove this legacy case.
@@ -288,7 +297,7 @@ func (l *sinkInfo) getStartLines(now time.Time) []*buffer {
 	if strings.HasPrefix(f.formatterName(), "crdb-") {
 		// For the crdb file formats, suggest the structure of each log line.
 		messages = append(messages,
-			makeStartLine(f, `line format: [IWEF]yymmdd hh:mm:ss.uuuuuu goid [chan@]file:line redactionmark \[tags\] [counter] msg`))
+			makeStartLine(f, `line format: [dDIWEF]yymmdd hh:mm:ss.uuuuuu goid [chan@]file:line redactionmark \[tags\] [counter] msg`))
 	}
 	return messages
 }
@@ -305,6 +314,12 @@ func (e logEntry) convertToLegacy() (res logpb.Entry) {
 		Counter:    e.counter,
 		Redactable: e.payload.redactable,
 		Message:    e.payload.message,
+
+		ClusterID:     e.clusterID,
+		NodeID:        e.nodeID,
+		TenantID:      e.tenantID,
+		SQLInstanceID: e.sqlInstanceID,
+		Version:       e.version,
 	}
 
 	if e.payload.tags != nil {
@@ -330,7 +345,17 @@ func (e logEntry) convertToLegacy() (res logpb.Entry) {
 	return res
 }
 
-const structuredEntryPrefix = "Structured entry: "
+const structuredEntryPrefix = logdecoder.StructuredEntryPrefix
+
+// makeEntryFromLegacy is the converse of convertToLegacy. Entries
+// with an unknown severity are considered to be header entries.
+func makeEntryFromLegacy(e *logpb.Entry) logEntry {
+	res := logEntry{
+		header:  e.Severity == severity.UNKNOWN,
+		counter: e.Counter,
+	}
+	return res.updateFromLegacy(&logpb.Entry{}, e)
+}
 
 // MakeLegacyEntry creates an logpb.Entry.
 func MakeLegacyEntry(
@@ -0,0 +1,65 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log/channel"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logconfig"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntryCount(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer ScopeWithoutShowLogs(t).Close(t)
+
+	ctx := context.Background()
+	opsInfo := EntryCount(channel.OPS, severity.INFO)
+	opsWarning := EntryCount(channel.OPS, severity.WARNING)
+	healthError := EntryCount(channel.HEALTH, severity.ERROR)
+	healthWarning := EntryCount(channel.HEALTH, severity.WARNING)
+
+	Ops.Infof(ctx, "hello %s", "world")
+	Ops.Info(ctx, "hello again")
+	Ops.Warningf(ctx, "careful")
+	Health.Shoutf(ctx, severity.ERROR, "broken")
+	// Only the first of the entries which are rate limited is counted.
+	for i := 0; i < 3; i++ {
+		Health.WarningfEvery(ctx, time.Hour, "often")
+	}
+
+	require.Equal(t, opsInfo+2, EntryCount(channel.OPS, severity.INFO))
+	require.Equal(t, opsWarning+1, EntryCount(channel.OPS, severity.WARNING))
+	require.Equal(t, healthError+1, EntryCount(channel.HEALTH, severity.ERROR))
+	require.Equal(t, healthWarning+1, EntryCount(channel.HEALTH, severity.WARNING))
+
+	// Neither are the entries discarded by the configured rate limits.
+	burst := 1
+	logging.rateLimiter.applyConfig(logconfig.RateLimitConfig{
+		"OPS": {EntriesPerSecond: 0.001, Burst: &burst},
+	})
+	defer logging.rateLimiter.applyConfig(nil)
+	for i := 0; i < 3; i++ {
+		Ops.Infof(ctx, "noisy")
+	}
+	require.Equal(t, opsInfo+3, EntryCount(channel.OPS, severity.INFO))
+
+	// Severities and channels which are not counted report nothing.
+	require.Equal(t, -1, MetricSeverityIndex(severity.NONE))
+	require.Zero(t, EntryCount(channel.OPS, severity.NONE))
+	require.Zero(t, EntryCount(logpb.Channel_CHANNEL_MAX, severity.INFO))
+}
@@ -41,6 +41,26 @@ const DefaultFluentFormat = `json-fluent-compact`
 // when not specified in a configuration.
 const DefaultHTTPFormat = `json-compact`
 
+// DefaultSyslogFormat is the entry format for syslog sinks
+// when not specified in a configuration.
+const DefaultSyslogFormat = `json-compact`
+
+// DefaultJournaldFormat is the entry format for journald sinks
+// when not specified in a configuration.
+const DefaultJournaldFormat = `crdb-v2`
+
+// DefaultEventLogFormat is the entry format for Windows Event Log
+// sinks when not specified in a configuration.
+const DefaultEventLogFormat = `crdb-v2`
+
+// ProtoFileFormat is the binary entry format. It is only supported
+// by file sinks.
+const ProtoFileFormat = `crdb-proto`
+
+// SystemTenantFilter is the item of a tenant-filter that selects
+// the log events of the system tenant.
+const SystemTenantFilter = `system`
+
 // DefaultConfig returns a suitable default configuration when logging
 // is meant to primarily go to files.
 func DefaultConfig() (c Config) {
@@ -126,6 +146,11 @@ type Config struct {
 	// configuration value.
 	HTTPDefaults HTTPDefaults `yaml:"http-defaults,omitempty"`
 
+	// SyslogDefaults represents the default configuration for syslog
+	// sinks, inherited when a specific syslog sink config does not
+	// provide a configuration value.
+	SyslogDefaults SyslogDefaults `yaml:"syslog-defaults,omitempty"`
+
 	// Sinks represents the sink configurations.
 	Sinks SinkConfig `yaml:",omitempty"`
 
@@ -133,6 +158,22 @@ type Config struct {
 	// internal writes to file descriptor 2 (incl that done internally
 	// by the go runtime).
 	CaptureFd2 CaptureFd2Config `yaml:"capture-stray-errors,omitempty"`
+
+	// StackTraces represents the configuration of the goroutine stacks
+	// included in log entries.
+	StackTraces StackTraceConfig `yaml:"stack-traces,omitempty"`
+
+	// RateLimits caps the rate of the entries emitted from any single
+	// location in the source code, per channel.
+	RateLimits RateLimitConfig `yaml:"rate-limits,omitempty"`
+
+	// Budgets caps the volume of the low-severity entries emitted on
+	// each channel.
+	Budgets BudgetConfig `yaml:"budgets,omitempty"`
+
+	// Events configures the routing of the structured events
+	// independently from the free-form log entries.
+	Events EventsConfig `yaml:",omitempty"`
 }
 
 // CaptureFd2Config represents the configuration for the fd2 capture sink.
@@ -151,6 +192,182 @@ type CaptureFd2Config struct {
 	MaxGroupSize *ByteSize `yaml:"max-group-size,omitempty"`
 }
 
+// StackTraceConfig represents the configuration of the goroutine
+// stacks included in log entries, per severity.
+//
+// The `stack-traces` section determines which goroutine stacks are
+// attached to log entries of a given severity. The possible values
+// are `none`, `single` (the goroutine that emitted the entry) and
+// `all` (every goroutine in the process). Example configuration:
+//
+//     stack-traces:
+//        error: single
+//        fatal: all
+//        all-goroutines-separate-file: true
+//
+// By default, no stack is included for WARNING and ERROR entries,
+// and the stacks included for FATAL entries are determined by the
+// `GOTRACEBACK` environment variable.
+type StackTraceConfig struct {
+	// Warning determines the stacks included in WARNING entries.
+	Warning *StackTraceMode `yaml:",omitempty"`
+
+	// Error determines the stacks included in ERROR entries.
+	Error *StackTraceMode `yaml:",omitempty"`
+
+	// Fatal determines the stacks included in FATAL entries.
+	Fatal *StackTraceMode `yaml:",omitempty"`
+
+	// AllGoroutinesSeparateFile, when set, causes the stacks of all
+	// goroutines to be written to a separate file in the log
+	// directory instead of the log entry. The entry then includes the
+	// stack of the current goroutine and the path to that file.
+	AllGoroutinesSeparateFile *bool `yaml:"all-goroutines-separate-file,omitempty"`
+}
+
+// RateLimitConfig represents the per-channel rate limits on log
+// entries. The map keys are channel names.
+//
+// The rate limits are applied separately to each location in the
+// source code (file and line) where entries are emitted, using a token
+// bucket. This protects the logging sinks against noisy call sites,
+// e.g. retry loops, without affecting the other entries on the same
+// channel. FATAL entries are never rate limited. Example configuration:
+//
+//     rate-limits:
+//        health:
+//           entries-per-second: 1
+//           burst: 10
+//        dev:
+//           entries-per-second: 100
+//
+type RateLimitConfig map[string]*RateLimit
+
+// RateLimit caps the rate of the entries emitted from a single
+// location in the source code.
+type RateLimit struct {
+	// EntriesPerSecond is the maximum sustained rate of entries.
+	EntriesPerSecond float64 `yaml:"entries-per-second"`
+
+	// Burst is the maximum number of entries that can be emitted at
+	// once above the sustained rate. Defaults to the sustained rate,
+	// rounded up.
+	Burst *int `yaml:",omitempty"`
+}
+
+// BudgetConfig represents the per-channel budgets on the volume of
+// log entries. The map keys are channel names.
+//
+// The entries emitted on a channel are accounted against its budget
+// over consecutive intervals. Once the budget of an interval is
+// exceeded, the further entries at or below the `suppress` severity
+// are dropped until the end of the interval, at which point a single
+// WARNING entry summarizes them instead, e.g. "suppressed 12,345 INFO
+// entries on SQL_EXEC in the last 10s". This protects the disks and
+// the downstream log collectors against logging storms, without
+// losing their signal entirely. The size of an entry is approximated
+// by the size of its message and stack trace before formatting.
+// FATAL entries are never suppressed. Example configuration:
+//
+//     budgets:
+//        sql_exec:
+//           bytes-per-second: 1MiB
+//           interval: 10s
+//        dev:
+//           bytes-per-second: 100KiB
+//           suppress: WARNING
+//
+type BudgetConfig map[string]*Budget
+
+// Budget caps the volume of the entries emitted on a channel.
+type Budget struct {
+	// BytesPerSecond is the sustained volume of entries allowed on
+	// the channel. The budget of an interval is this volume multiplied
+	// by the duration of the interval.
+	BytesPerSecond ByteSize `yaml:"bytes-per-second"`
+
+	// Interval is the duration over which the budget is accounted,
+	// and the period of the summaries of the suppressed entries.
+	// Defaults to 10s.
+	Interval *time.Duration `yaml:",omitempty"`
+
+	// Suppress is the maximum severity of the entries dropped once
+	// the budget is exceeded. Defaults to INFO.
+	Suppress *logpb.Severity `yaml:",omitempty"`
+}
+
+// EventsConfig represents the configuration of the pipeline of
+// structured events, i.e. the log entries which report the events
+// documented in eventlog.md, as opposed to the free-form log
+// entries.
+//
+// The structured events of the selected channels are emitted to the
+// sinks of the `events` section, in addition to the regular sinks of
+// the `sinks` section. When `exclusive` is set, they are emitted to
+// the sinks of the `events` section only, and the regular sinks only
+// receive the free-form log entries of the selected channels.
+//
+// The format and buffering options of the section apply to its
+// sinks, unless overridden per sink, and take precedence over the
+// `file-defaults`, `fluent-defaults` and `http-defaults` sections.
+// Buffering only applies to the network sinks. The sinks of the
+// section cannot select channels: they receive the events of the
+// channels selected by the section. Example configuration, which
+// sends all the structured events as JSON to an HTTP server while the
+// free-form log entries stay in files:
+//
+//     events:
+//        channels: all
+//        exclusive: true
+//        format: json
+//        buffering:
+//           max-staleness: 1s
+//        sinks:
+//           http-servers:
+//              collector:
+//                 address: https://events.example.com/ingest
+//
+type EventsConfig struct {
+	// Channels selects the channels whose structured events are
+	// routed to the sinks of this section. Defaults to all the
+	// channels.
+	Channels ChannelList `yaml:",omitempty,flow"`
+
+	// Exclusive indicates whether the structured events of the
+	// selected channels are removed from the regular sinks.
+	Exclusive *bool `yaml:",omitempty"`
+
+	// Format is the entry format of the sinks of this section, unless
+	// overridden per sink. Defaults to the default format of each type
+	// of sink.
+	Format *string `yaml:",omitempty"`
+
+	// Buffering is the buffering configuration of the network sinks of
+	// this section, unless overridden per sink.
+	Buffering CommonBufferSinkConfigWrapper `yaml:",omitempty"`
+
+	// Sinks represents the sinks of this section. Their names must be
+	// distinct from those of the sinks of the same type in the `sinks`
+	// section.
+	Sinks EventSinks `yaml:",omitempty"`
+}
+
+// EventSinks represents the sinks of the events section.
+type EventSinks struct {
+	// FileGroups represents the list of configured file sinks.
+	FileGroups map[string]*FileSinkConfig `yaml:"file-groups,omitempty"`
+	// FluentServers represents the list of configured fluent sinks.
+	FluentServers map[string]*FluentSinkConfig `yaml:"fluent-servers,omitempty"`
+	// HTTPServers represents the list of configured http sinks.
+	HTTPServers map[string]*HTTPSinkConfig `yaml:"http-servers,omitempty"`
+}
+
+// IsEnabled returns true iff the events section has sinks.
+func (e EventsConfig) IsEnabled() bool {
+	return len(e.Sinks.FileGroups) > 0 || len(e.Sinks.FluentServers) > 0 ||
+		len(e.Sinks.HTTPServers) > 0
+}
+
 // CommonBufferSinkConfig represents the common buffering configuration for sinks.
 //
 // User-facing documentation follows.
@@ -188,6 +405,14 @@ type CommonBufferSinkConfig struct {
 	// FlushTriggerSize is reached, and a new buffer is created once the flushing
 	// is started. Only one flushing operation is active at a time.
 	MaxBufferSize *ByteSize `yaml:"max-buffer-size"`
+
+	// Backpressure determines what happens when a new message does not
+	// fit in the buffer. With `drop` (the default), the oldest messages
+	// in the buffer are dropped to make room for it. With `block`, the
+	// logging call waits until the buffer has been flushed, which
+	// guarantees delivery to the sink at the expense of slowing down
+	// the process when the sink cannot keep up.
+	Backpressure *BackpressurePolicy `yaml:"backpressure,omitempty"`
 }
 
 // CommonBufferSinkConfigWrapper is a BufferSinkConfig with a special value represented in YAML by
@@ -199,6 +424,73 @@ type CommonBufferSinkConfigWrapper struct {
 	CommonBufferSinkConfig
 }
 
+// SpoolConfig configures the write-ahead spool of a network sink.
+type SpoolConfig struct {
+	// Dir is the directory under which the spool files are stored.
+	// Each sink uses a separate sub-directory. The spool is disabled
+	// if unspecified.
+	Dir *string `yaml:",omitempty"`
+
+	// MaxSize is the maximum amount of disk space used by the spool.
+	MaxSize *ByteSize `yaml:"max-size,omitempty"`
+}
+
+// IsEnabled returns whether the spool is enabled.
+func (s SpoolConfig) IsEnabled() bool {
+	return s.Dir != nil
+}
+
+// TimestampConfig configures the rendering of the timestamps of the
+// log events emitted to a sink.
+type TimestampConfig struct {
+	// Format is either `default` or `rfc3339`.
+	Format *string `yaml:",omitempty"`
+
+	// Zone is the time zone of RFC 3339 timestamps.
+	Zone *string `yaml:",omitempty"`
+
+	// Delta indicates whether to report the time elapsed since the
+	// previous log event.
+	Delta *bool `yaml:",omitempty"`
+}
+
+// The timestamp formats.
+const (
+	// DefaultTimestampFormat selects the native timestamps of the entry
+	// format.
+	DefaultTimestampFormat = "default"
+	// RFC3339TimestampFormat selects RFC 3339 timestamps with
+	// microsecond precision and an explicit time zone offset.
+	RFC3339TimestampFormat = "rfc3339"
+)
+
+// IsRFC3339 returns whether the timestamps are rendered in RFC 3339
+// format.
+func (c TimestampConfig) IsRFC3339() bool {
+	return c.Format != nil && *c.Format == RFC3339TimestampFormat
+}
+
+// IsEnabled returns whether the timestamps are rendered differently
+// from the native timestamps of the entry format.
+func (c TimestampConfig) IsEnabled() bool {
+	return c.IsRFC3339() || (c.Delta != nil && *c.Delta)
+}
+
+// AWSSigV4Config configures the AWS Signature Version 4 signing of
+// the requests of an HTTP sink.
+type AWSSigV4Config struct {
+	// Region is the AWS region of the service, e.g. us-east-1.
+	Region *string `yaml:",omitempty"`
+
+	// Service is the name of the AWS service, e.g. es.
+	Service *string `yaml:",omitempty"`
+}
+
+// IsEnabled returns whether the requests are signed.
+func (c AWSSigV4Config) IsEnabled() bool {
+	return c.Region != nil || c.Service != nil
+}
+
 // CommonSinkConfig represents the common configuration shared across all sinks.
 type CommonSinkConfig struct {
 	// Filter specifies the default minimum severity for log events to
@@ -229,6 +521,52 @@ type CommonSinkConfig struct {
 	// from `crdb-v1` to `crdb-v1-count`.
 	Auditable *bool `yaml:",omitempty"`
 
+	// Processors lists the names of the entry processors to apply,
+	// in order, to the log events before they are emitted to this
+	// sink. Processors are registered by the server at start-up;
+	// they can inject fields into the events or drop them.
+	Processors []string `yaml:",omitempty,flow"`
+
+	// TenantFilter restricts this sink to the log events emitted on
+	// behalf of the listed tenants. Each item is either a tenant ID or
+	// `system` for the system tenant. By default, the events of all the
+	// tenants are accepted. This can be used to route the logs of each
+	// tenant to separate sinks in multi-tenant deployments.
+	TenantFilter []string `yaml:"tenant-filter,omitempty,flow"`
+
+	// GoroutineTags indicates whether to add the ID of the goroutine
+	// which emitted each log event, and the profiler labels set with
+	// `pprof.Do()` on the context of the logging call, to the tags of
+	// the event. This helps correlate the interleaved events of
+	// concurrent operations when debugging.
+	GoroutineTags *bool `yaml:"goroutine-tags,omitempty"`
+
+	// Timestamps configures how the timestamps of the log events are
+	// rendered. The sub-field `format` is either `default`, for the
+	// native timestamps of the entry format, or `rfc3339`, for RFC 3339
+	// timestamps with microsecond precision and an explicit time zone
+	// offset. The sub-field `zone` is the time zone, by name in the
+	// IANA time zone database, in which RFC 3339 timestamps are
+	// rendered (default UTC). The sub-field `delta`, when set, adds to
+	// each log event the time elapsed since the previous event emitted
+	// to this sink; the delta is never negative, even when the system
+	// clock steps backwards. This facilitates the manual analysis of
+	// latencies across sequences of events. Only supported by the
+	// `crdb-v2` and `json` families of formats; the resulting logs
+	// cannot be parsed by `cockroach debug merge-logs`.
+	Timestamps TimestampConfig `yaml:",omitempty"`
+
+	// MaxEntrySize is the maximum size of the messages of the log
+	// events emitted to this sink. Longer messages are truncated in
+	// their middle: their beginning and their end, which often holds
+	// the details of an error, are preserved around a marker which
+	// reports the number of bytes elided. The number of truncated
+	// events is reported in the `log.sinks.<type>.truncated_entries`
+	// metrics. The stack traces and the payloads of structured events
+	// are not truncated. If zero or unspecified, the messages are not
+	// truncated; otherwise it cannot be smaller than 256B.
+	MaxEntrySize *ByteSize `yaml:"max-entry-size,omitempty"`
+
 	// Buffering configures buffering for this log sink, or NONE to explicitly disable.
 	Buffering CommonBufferSinkConfigWrapper `yaml:",omitempty"`
 }
@@ -241,6 +579,15 @@ type SinkConfig struct {
 	FluentServers map[string]*FluentSinkConfig `yaml:"fluent-servers,omitempty"`
 	// HTTPServers represents the list of configured http sinks.
 	HTTPServers map[string]*HTTPSinkConfig `yaml:"http-servers,omitempty"`
+	// SyslogServers represents the list of configured syslog sinks.
+	SyslogServers map[string]*SyslogSinkConfig `yaml:"syslog-servers,omitempty"`
+	// JournaldSinks represents the list of configured journald sinks.
+	JournaldSinks map[string]*JournaldSinkConfig `yaml:"journald,omitempty"`
+	// EventLogSinks represents the list of configured Windows Event
+	// Log sinks.
+	EventLogSinks map[string]*EventLogSinkConfig `yaml:"eventlog,omitempty"`
+	// CountSinks represents the list of configured count sinks.
+	CountSinks map[string]*CountSinkConfig `yaml:"count-sinks,omitempty"`
 	// Stderr represents the configuration for the stderr sink.
 	Stderr StderrSinkConfig `yaml:",omitempty"`
 }
@@ -298,9 +645,61 @@ type StderrSinkConfig struct {
 
 // FluentDefaults represent configuration defaults for fluent sinks.
 type FluentDefaults struct {
+	// Spool configures a write-ahead spool on local disk for this
+	// sink. When enabled, log entries are written to the spool before
+	// they are sent over the network, so that they survive process
+	// restarts and network outages; they are redelivered until the
+	// sink accepts them. The sub-field `dir` is the directory under
+	// which the spool files are stored, in a sub-directory named after
+	// the sink; the spool is disabled if it is not specified. The
+	// sub-field `max-size` bounds the disk usage of the spool (default
+	// 1GiB); when it is exceeded, the oldest undelivered entries are
+	// dropped. In-memory buffering is disabled when the spool is
+	// enabled.
+	Spool SpoolConfig `yaml:",omitempty"`
+
+	// Protocol is the protocol used to send the log entries to the
+	// collector. With `json` (the default), every log entry is sent as
+	// a JSON object, for collectors which parse their input as JSON
+	// (e.g. the `in_tcp` input plugin of Fluentd). With `forward`, the
+	// log entries are sent in batches in the Fluentd Forward protocol,
+	// for collectors configured with the `in_forward` input plugin. The
+	// `forward` protocol requires one of the `json-fluent` formats and
+	// a stream-oriented network (e.g. `tcp`), and disables in-memory
+	// buffering: the batching options below take its role.
+	Protocol *FluentProtocol `yaml:",omitempty"`
+
+	// Compression is the compression algorithm applied to the batches
+	// of log entries sent with the `forward` protocol. Accepted values
+	// are `none` (the default), `gzip` and `zstd`. The collector must
+	// support the chosen algorithm.
+	Compression *FluentCompression `yaml:",omitempty"`
+
+	// BatchSize is the maximum number of log entries sent in one
+	// message of the `forward` protocol. Defaults to 100.
+	BatchSize *int `yaml:"batch-size,omitempty"`
+
+	// FlushInterval is the maximum amount of time that a log entry is
+	// held by a sink using the `forward` protocol before it is sent,
+	// when its batch is not full. Defaults to 1s.
+	FlushInterval *time.Duration `yaml:"flush-interval,omitempty"`
+
+	// RequireAck causes a sink using the `forward` protocol to wait
+	// for the collector to acknowledge every batch of log entries, and
+	// to consider the batches which are not acknowledged as failed to
+	// be delivered. Combined with the `spool` option, this provides
+	// at-least-once delivery of the log entries. Defaults to false.
+	RequireAck *bool `yaml:"require-ack,omitempty"`
+
 	CommonSinkConfig `yaml:",inline"`
 }
 
+// IsForward returns whether the log entries are sent with the Fluentd
+// Forward protocol.
+func (d FluentDefaults) IsForward() bool {
+	return d.Protocol != nil && *d.Protocol == FluentProtocolForward
+}
+
 // FluentSinkConfig represents the configuration for one fluentd sink.
 //
 // User-facing documentation follows.
@@ -327,6 +726,22 @@ type FluentDefaults struct {
 // process's standard error output with a copy of the logging event and
 // the logging event is dropped.
 //
+// For audit-quality delivery, a write-ahead spool on local disk can be
+// enabled with the `spool` field. The log entries are then written to
+// the spool first, and delivered to the collector from there, with
+// retries, until it accepts them. The entries survive restarts of the
+// process and outages of the collector, and may be delivered more than
+// once. For example:
+//
+//     sinks:
+//        fluent-servers:
+//           audit:
+//              channels: [SENSITIVE_ACCESS, SQL_EXEC]
+//              address: 127.0.0.1:5170
+//              spool:
+//                 dir: /mnt/spool
+//                 max-size: 10GiB
+//
 // The configuration key under the `sinks` key in the YAML
 // configuration is `fluent-servers`. Example configuration:
 //
@@ -355,6 +770,23 @@ type FluentDefaults struct {
 // include a `tag` field as required by the Fluentd protocol, which
 // the non-`fluent` JSON [format variants](log-formats.html) do not include.
 //
+// High-volume clusters can use the Fluentd Forward protocol instead,
+// with the `protocol` field. The log entries are then sent in batches,
+// optionally compressed, to a collector configured with the
+// `in_forward` input plugin. The collector can also be required to
+// acknowledge every batch with `require-ack`. For example:
+//
+//     sinks:
+//        fluent-servers:
+//           audit:
+//              channels: [SENSITIVE_ACCESS, SQL_EXEC]
+//              address: 127.0.0.1:24224
+//              protocol: forward
+//              compression: zstd
+//              batch-size: 500
+//              flush-interval: 2s
+//              require-ack: true
+//
 // {{site.data.alerts.callout_info}}
 // Run `cockroach debug check-log-config` to verify the effect of defaults inheritance.
 // {{site.data.alerts.end}}
@@ -392,9 +824,31 @@ type FileDefaults struct {
 	// MaxGroupSize is the approximate maximum combined size of all files
 	// to be preserved for this sink. An asynchronous garbage collection
 	// removes files that cause the file set to grow beyond this specified
-	// size. If zero, old files are not removed.
+	// size. If zero, old files are not removed. The size of compressed
+	// files is counted after compression.
 	MaxGroupSize *ByteSize `yaml:"max-group-size,omitempty"`
 
+	// MaxAge is the maximum age of the files preserved for this sink,
+	// determined by their last modification time. The garbage
+	// collection removes the files older than this. If zero or
+	// unspecified, files are not removed based on their age.
+	MaxAge *time.Duration `yaml:"max-age,omitempty"`
+
+	// MinFreeDisk is the minimum amount of free disk space to preserve
+	// on the filesystem of the output directory. When less space is
+	// available, the garbage collection removes the oldest files of
+	// this sink until enough space is freed up. If zero or
+	// unspecified, the free disk space is not checked.
+	MinFreeDisk *ByteSize `yaml:"min-free-disk,omitempty"`
+
+	// Compression is the compression algorithm applied to the files
+	// of this sink once they are rotated out, in the background. The
+	// compressed files are named after the original file with an
+	// additional `.gz` or `.zst` extension. Accepted values are
+	// `none` (the default), `gzip` and `zstd`. The file currently
+	// being written to is never compressed.
+	Compression *FileCompression `yaml:",omitempty"`
+
 	// FilePermissions is the "chmod-style" permissions the log files are
 	// created with as a 3-digit octal number. The executable bit must not
 	// be set. Defaults to 644 (readable by all, writable by owner).
@@ -404,6 +858,15 @@ type FileDefaults struct {
 	// Setting this to false flushes log writes upon every entry.
 	BufferedWrites *bool `yaml:"buffered-writes,omitempty"`
 
+	// AsyncWrites specifies whether log entries are handed over to a
+	// background writer for this sink. The writer batches the writes to
+	// the log files and coalesces the requests to sync them to disk,
+	// which removes the file I/O from the code paths that emit log
+	// entries. All the pending entries are still written before the
+	// process terminates on a fatal error. This option is disabled by
+	// default, and ignored for auditable sinks.
+	AsyncWrites *bool `yaml:"async-writes,omitempty"`
+
 	// CommonSinkConfig is the configuration common to all sinks. Note
 	// that although the idiom in Go is to place embedded fields at the
 	// beginning of a struct, we purposefully deviate from the idiom
@@ -439,6 +902,19 @@ type FileDefaults struct {
 //
 // A symlink (e.g. `cockroach-health.log`) for each group points to the latest generated log file.
 //
+// The files that were rotated out can be compressed in the background
+// using the `compression` option, and removed based on their combined
+// size (`max-group-size`), their age (`max-age`) or the free disk space
+// remaining (`min-free-disk`). For example:
+//
+//     sinks:
+//        file-groups:
+//           health:
+//              channels: HEALTH
+//              compression: zstd  # produces cockroach-health.XXX.log.zst
+//              max-age: 168h      # keep one week of logs
+//              min-free-disk: 1GiB
+//
 // Every new file group sink configured automatically inherits
 // the configurations set in the `file-defaults` section.
 //
@@ -499,6 +975,58 @@ type HTTPDefaults struct {
 	// overhead in production systems.
 	DisableKeepAlives *bool `yaml:"disable-keep-alives,omitempty"`
 
+	// SignWithNodeCert causes every request to be signed with the key
+	// of the node certificate, so that the server can authenticate the
+	// origin of the log entries. The signature covers the log entries
+	// in the request, i.e. the request body for POST and the unescaped
+	// query string for GET. It is sent base64-encoded in the
+	// `X-Cockroach-Signature` header, alongside the name of the
+	// signature algorithm in `X-Cockroach-Signature-Algorithm` and the
+	// base64-encoded DER form of the node certificate in
+	// `X-Cockroach-Signature-Certificate`. The requests fail when the
+	// node certificate is not available, for example in insecure mode.
+	// Defaults to false.
+	SignWithNodeCert *bool `yaml:"sign-with-node-cert,omitempty"`
+
+	// ClientCert is the path to a PEM-encoded TLS client certificate
+	// presented to the server, for servers which authenticate their
+	// clients with mutual TLS. It must be set together with
+	// `client-key`.
+	ClientCert *string `yaml:"client-cert,omitempty"`
+
+	// ClientKey is the path to the PEM-encoded private key of the TLS
+	// client certificate configured with `client-cert`.
+	ClientKey *string `yaml:"client-key,omitempty"`
+
+	// Headers are static HTTP headers added to every request, for
+	// example to authenticate with a bearer token:
+	// `Authorization: Bearer <token>`.
+	Headers map[string]string `yaml:",omitempty"`
+
+	// AWSSigV4 causes every request to be signed with AWS Signature
+	// Version 4, for servers such as Amazon OpenSearch Service which
+	// authenticate their clients with AWS credentials. The sub-fields
+	// `region` and `service` (e.g. `es`) are the region and the name
+	// of the service which the requests are signed for; both must be
+	// specified. The credentials are read from the
+	// `AWS_ACCESS_KEY_ID`, `AWS_SECRET_ACCESS_KEY` and, optionally,
+	// `AWS_SESSION_TOKEN` environment variables when the sink is
+	// created.
+	AWSSigV4 AWSSigV4Config `yaml:"aws-sigv4,omitempty"`
+
+	// Spool configures a write-ahead spool on local disk for this
+	// sink. When enabled, log entries are written to the spool before
+	// they are sent over the network, so that they survive process
+	// restarts and network outages; they are redelivered until the
+	// server accepts them. The sub-field `dir` is the directory under
+	// which the spool files are stored, in a sub-directory named after
+	// the sink; the spool is disabled if it is not specified. The
+	// sub-field `max-size` bounds the disk usage of the spool (default
+	// 1GiB); when it is exceeded, the oldest undelivered entries are
+	// dropped. In-memory buffering is disabled when the spool is
+	// enabled.
+	Spool SpoolConfig `yaml:",omitempty"`
+
 	CommonSinkConfig `yaml:",inline"`
 }
 
@@ -533,6 +1061,38 @@ type HTTPDefaults struct {
 //             # as the setting is inherited from fluent-defaults
 //             # unless overridden here.
 //
+// As for the Fluent sinks, a write-ahead spool on local disk can be
+// enabled with the `spool` field, so that the log entries survive
+// restarts of the process and outages of the server. For example:
+//
+//      sinks:
+//         http-servers:
+//            audit:
+//               channels: [SENSITIVE_ACCESS, SQL_EXEC]
+//               address: http://127.0.0.1
+//               spool:
+//                  dir: /mnt/spool
+//
+// Servers which authenticate their clients can be reached by
+// configuring a TLS client certificate, static headers or AWS
+// Signature Version 4 signing. For example:
+//
+//      sinks:
+//         http-servers:
+//            signed:
+//               channels: [SENSITIVE_ACCESS]
+//               address: https://logs.us-east-1.example.com
+//               aws-sigv4:
+//                  region: us-east-1
+//                  service: es
+//            authenticated:
+//               channels: [OPS]
+//               address: https://logs.example.com
+//               client-cert: /certs/client.crt
+//               client-key: /certs/client.key
+//               headers:
+//                  Authorization: Bearer <token>
+//
 // The default output format for HTTP sinks is
 // `json-compact`. [Other supported formats.](log-formats.html)
 //
@@ -550,6 +1110,261 @@ type HTTPSinkConfig struct {
 	sinkName string
 }
 
+// SyslogDefaults represents the configuration defaults for syslog
+// sinks.
+type SyslogDefaults struct {
+	// Address is the network address of the syslog server. The
+	// host/address and port parts are separated with a colon. IPv6
+	// numeric addresses should be included within square brackets,
+	// e.g.: [::1]:514.
+	Address *string `yaml:",omitempty"`
+
+	// Net is the protocol used to reach the syslog server: `udp`,
+	// `tcp` or `tls`. Defaults to `tcp`.
+	Net *SyslogNetwork `yaml:",omitempty"`
+
+	// Facility is the syslog facility reported alongside every
+	// message, e.g. `user`, `daemon` or `local0`. Defaults to `user`.
+	Facility *SyslogFacility `yaml:",omitempty"`
+
+	// UnsafeTLS enables certificate authentication to be bypassed
+	// when connecting over TLS. Defaults to false.
+	UnsafeTLS *bool `yaml:"unsafe-tls,omitempty"`
+
+	CommonSinkConfig `yaml:",inline"`
+}
+
+// SyslogSinkConfig represents the configuration for one syslog sink.
+//
+// User-facing documentation follows.
+// TITLE: Output to syslog servers
+//
+// This sink type causes logging data to be sent over the network
+// to a syslog server, using the message format defined in
+// [RFC 5424](https://datatracker.ietf.org/doc/html/rfc5424).
+//
+// The transport can be UDP ([RFC 5426](https://datatracker.ietf.org/doc/html/rfc5426)),
+// TCP or TLS ([RFC 5425](https://datatracker.ietf.org/doc/html/rfc5425)).
+// Over UDP, every logging event is sent in a separate datagram; over
+// TCP and TLS, logging events are framed using octet counting.
+//
+// The configuration key under the `sinks` key in the YAML
+// configuration is `syslog-servers`. Example configuration:
+//
+//      sinks:
+//         syslog-servers:
+//            health:
+//               channels: HEALTH
+//               address: 127.0.0.1:514
+//               net: udp
+//               facility: local0
+//
+// The severity of each logging event is mapped to a syslog
+// severity as follows: `INFO` to `informational`, `WARNING` to
+// `warning`, `ERROR` to `error` and `FATAL` to `critical`. The
+// logging channel is reported as the syslog MSGID field.
+//
+// Every new server sink configured automatically inherits the configuration set in the `syslog-defaults` section.
+//
+// For example:
+//
+//      syslog-defaults:
+//          facility: local0 # default: use the local0 facility
+//      sinks:
+//        syslog-servers:
+//          health:
+//             channels: HEALTH
+//             address: 127.0.0.1:514
+//             # This sink uses the local0 facility,
+//             # as the setting is inherited from syslog-defaults
+//             # unless overridden here.
+//
+// The default format for the message part of syslog entries is
+// `json-compact`. [Other supported formats.](log-formats.html)
+//
+// {{site.data.alerts.callout_info}}
+// Run `cockroach debug check-log-config` to verify the effect of defaults inheritance.
+// {{site.data.alerts.end}}
+//
+type SyslogSinkConfig struct {
+	// Channels is the list of logging channels that use this sink.
+	Channels ChannelFilters `yaml:",omitempty,flow"`
+
+	SyslogDefaults `yaml:",inline"`
+
+	// sinkName is populated during validation.
+	sinkName string
+}
+
+// JournaldSinkConfig represents the configuration for one journald
+// sink.
+//
+// User-facing documentation follows.
+// TITLE: Output to the systemd journal
+//
+// This sink type causes logging data to be sent to the local
+// systemd journal, using the journal's
+// [native protocol](https://systemd.io/JOURNAL_NATIVE_PROTOCOL/).
+// It is only supported on Linux.
+//
+// Every logging event is sent as a separate journal entry. The
+// formatted logging event is reported as the `MESSAGE` field;
+// the entry also carries the structured fields `PRIORITY`,
+// `SYSLOG_IDENTIFIER`, `SYSLOG_PID`, `CODE_FILE` and `CODE_LINE`,
+// as well as `CRDB_CHANNEL` and `CRDB_SEVERITY`, which can be used
+// to filter the output of `journalctl`, e.g.
+// `journalctl CRDB_CHANNEL=HEALTH`.
+//
+// The configuration key under the `sinks` key in the YAML
+// configuration is `journald`. Example configuration:
+//
+//      sinks:
+//         journald:
+//            health:
+//               channels: HEALTH
+//               identifier: crdb-health
+//
+// The severity of each logging event is mapped to a journal
+// priority as follows: `INFO` to `informational` (6), `WARNING` to
+// `warning` (4), `ERROR` to `error` (3) and `FATAL` to `critical` (2).
+//
+// The default format for the `MESSAGE` field of journal entries is
+// `crdb-v2`. [Other supported formats.](log-formats.html)
+//
+// Buffering is not supported on journald sinks: the journal is a
+// local service, which is expected to accept entries promptly.
+//
+type JournaldSinkConfig struct {
+	// Channels is the list of logging channels that use this sink.
+	Channels ChannelFilters `yaml:",omitempty,flow"`
+
+	// Identifier is reported as the `SYSLOG_IDENTIFIER` field of the
+	// journal entries. Defaults to `cockroach`.
+	Identifier *string `yaml:",omitempty"`
+
+	// Socket is the path to the socket of the journal. Defaults to
+	// `/run/systemd/journal/socket`.
+	Socket *string `yaml:",omitempty"`
+
+	CommonSinkConfig `yaml:",inline"`
+
+	// sinkName is populated during validation.
+	sinkName string
+}
+
+// EventLogSinkConfig represents the configuration for one Windows
+// Event Log sink.
+//
+// User-facing documentation follows.
+// TITLE: Output to the Windows Event Log
+//
+// This sink type causes logging data to be reported to the
+// Application log of the Windows Event Log. It is only supported
+// on Windows.
+//
+// Every logging event is reported as a separate event, whose
+// message is the formatted logging event. The severity of each
+// logging event is mapped to an event type as follows: `INFO` to
+// `Information`, `WARNING` to `Warning`, and `ERROR` and `FATAL` to
+// `Error`. The event ID is the number of the logging channel.
+//
+// The configuration key under the `sinks` key in the YAML
+// configuration is `eventlog`. Example configuration:
+//
+//      sinks:
+//         eventlog:
+//            ops:
+//               channels: [OPS, HEALTH]
+//               source: CockroachDB
+//
+// The events are reported under the configured source, which
+// should be registered beforehand, e.g. with the PowerShell command
+// `New-EventLog -LogName Application -Source CockroachDB`. Otherwise,
+// the Event Viewer reports that the description of the events
+// cannot be found, alongside their message.
+//
+// The default format for the message of the events is `crdb-v2`.
+// [Other supported formats.](log-formats.html)
+//
+// Buffering is not supported on Windows Event Log sinks.
+//
+type EventLogSinkConfig struct {
+	// Channels is the list of logging channels that use this sink.
+	Channels ChannelFilters `yaml:",omitempty,flow"`
+
+	// Source is the event source under which the events are reported.
+	// Defaults to `CockroachDB`.
+	Source *string `yaml:",omitempty"`
+
+	CommonSinkConfig `yaml:",inline"`
+
+	// sinkName is populated during validation.
+	sinkName string
+}
+
+// CountSinkConfig represents the configuration for one count sink.
+//
+// User-facing documentation follows.
+// TITLE: Entry counters
+//
+// This sink type does not persist the logging events. Instead, it
+// counts them, keyed by a configurable set of labels extracted from
+// every event, and exposes the counts as metrics. This makes it
+// possible to quantify the noisy sources of logging events cheaply,
+// for instance on busy clusters where the channels are not otherwise
+// collected.
+//
+// The configuration key under the `sinks` key in the YAML
+// configuration is `count-sinks`. Example configuration:
+//
+//      sinks:
+//         count-sinks:
+//            noisy:
+//               channels: all
+//               labels: [channel, file:line]
+//               max-keys: 500
+//
+// The supported labels are `channel`, `severity` and `file:line`,
+// the latter being the location in the source code where the event
+// was emitted. The counts are reported by the `log.count_sinks.entries`
+// metric; the count for every combination of label values is only
+// exported to Prometheus, and only when the cluster setting
+// `server.child_metrics.enabled` is set.
+//
+// The amount of memory used by the sink is bounded by `max-keys`:
+// once that many distinct combinations of label values have been
+// seen, the events with new combinations are only counted by the
+// `log.count_sinks.overflow_entries` metric.
+//
+type CountSinkConfig struct {
+	// Channels is the list of logging channels that use this sink.
+	Channels ChannelFilters `yaml:",omitempty,flow"`
+
+	// Labels is the list of labels by which the events are counted:
+	// `channel`, `severity` or `file:line`. Defaults to `channel` and
+	// `severity`.
+	Labels []CountLabel `yaml:",omitempty,flow"`
+
+	// MaxKeys is the maximum number of distinct combinations of label
+	// values for which the events are counted. Defaults to 1000.
+	MaxKeys *int `yaml:"max-keys,omitempty"`
+
+	// Filter specifies the default minimum severity for log events to
+	// be counted by this sink, when not otherwise specified by the
+	// 'channels' sink attribute.
+	Filter logpb.Severity `yaml:",omitempty"`
+
+	// Processors lists the names of the entry processors to apply,
+	// in order, to the log events before they are counted by this
+	// sink. See the other sink types for details.
+	Processors []string `yaml:",omitempty,flow"`
+
+	// TenantFilter restricts this sink to the log events emitted on
+	// behalf of the listed tenants. See the other sink types for
+	// details.
+	TenantFilter []string `yaml:"tenant-filter,omitempty,flow"`
+}
+
 // IterateDirectories calls the provided fn on every directory linked to
 // by the configuration.
 func (c *Config) IterateDirectories(fn func(d string) error) error {
@@ -562,6 +1377,11 @@ func (c *Config) IterateDirectories(fn func(d string) error) error {
 			dirs[*fc.Dir] = struct{}{}
 		}
 	}
+	for _, fc := range c.Events.Sinks.FileGroups {
+		if fc.Dir != nil {
+			dirs[*fc.Dir] = struct{}{}
+		}
+	}
 	for d := range dirs {
 		if err := fn(d); err != nil {
 			return err
@@ -1126,6 +1946,342 @@ func (hsm *HTTPSinkMethod) UnmarshalYAML(fn func(interface{}) error) error {
 	return unmarshalYAMLConstrainedString(hsm, fn)
 }
 
+// StackTraceMode is a string restricted to "none", "single" and "all".
+type StackTraceMode string
+
+// Accepted values for StackTraceMode.
+const (
+	StackTraceNone   StackTraceMode = "none"
+	StackTraceSingle StackTraceMode = "single"
+	StackTraceAll    StackTraceMode = "all"
+)
+
+var _ constrainedString = (*StackTraceMode)(nil)
+
+// Accept implements the constrainedString interface.
+func (m *StackTraceMode) Accept(s string) {
+	*m = StackTraceMode(s)
+}
+
+// Canonicalize implements the constrainedString interface.
+func (StackTraceMode) Canonicalize(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// AllowedSet implements the constrainedString interface.
+func (StackTraceMode) AllowedSet() []string {
+	return []string{
+		string(StackTraceNone),
+		string(StackTraceSingle),
+		string(StackTraceAll),
+	}
+}
+
+// MarshalYAML implements yaml.Marshaler interface.
+func (m StackTraceMode) MarshalYAML() (interface{}, error) {
+	return string(m), nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (m *StackTraceMode) UnmarshalYAML(fn func(interface{}) error) error {
+	return unmarshalYAMLConstrainedString(m, fn)
+}
+
+// SyslogNetwork is a string restricted to "udp", "tcp" and "tls".
+type SyslogNetwork string
+
+// Accepted values for SyslogNetwork.
+const (
+	SyslogNetworkUDP SyslogNetwork = "udp"
+	SyslogNetworkTCP SyslogNetwork = "tcp"
+	SyslogNetworkTLS SyslogNetwork = "tls"
+)
+
+var _ constrainedString = (*SyslogNetwork)(nil)
+
+// Accept implements the constrainedString interface.
+func (n *SyslogNetwork) Accept(s string) {
+	*n = SyslogNetwork(s)
+}
+
+// Canonicalize implements the constrainedString interface.
+func (SyslogNetwork) Canonicalize(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// AllowedSet implements the constrainedString interface.
+func (SyslogNetwork) AllowedSet() []string {
+	return []string{
+		string(SyslogNetworkUDP),
+		string(SyslogNetworkTCP),
+		string(SyslogNetworkTLS),
+	}
+}
+
+// MarshalYAML implements yaml.Marshaler interface.
+func (n SyslogNetwork) MarshalYAML() (interface{}, error) {
+	return string(n), nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (n *SyslogNetwork) UnmarshalYAML(fn func(interface{}) error) error {
+	return unmarshalYAMLConstrainedString(n, fn)
+}
+
+// BackpressurePolicy is a string restricted to "drop" and "block".
+type BackpressurePolicy string
+
+// Accepted values for BackpressurePolicy.
+const (
+	BackpressureDrop  BackpressurePolicy = "drop"
+	BackpressureBlock BackpressurePolicy = "block"
+)
+
+var _ constrainedString = (*BackpressurePolicy)(nil)
+
+// Accept implements the constrainedString interface.
+func (p *BackpressurePolicy) Accept(s string) {
+	*p = BackpressurePolicy(s)
+}
+
+// Canonicalize implements the constrainedString interface.
+func (BackpressurePolicy) Canonicalize(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// AllowedSet implements the constrainedString interface.
+func (BackpressurePolicy) AllowedSet() []string {
+	return []string{
+		string(BackpressureDrop),
+		string(BackpressureBlock),
+	}
+}
+
+// MarshalYAML implements yaml.Marshaler interface.
+func (p BackpressurePolicy) MarshalYAML() (interface{}, error) {
+	return string(p), nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (p *BackpressurePolicy) UnmarshalYAML(fn func(interface{}) error) error {
+	return unmarshalYAMLConstrainedString(p, fn)
+}
+
+// SyslogFacility is a string restricted to the facility names
+// defined in RFC 5424.
+type SyslogFacility string
+
+// syslogFacilities lists the facility names in the order of their
+// numerical codes.
+var syslogFacilities = []string{
+	"kern", "user", "mail", "daemon", "auth", "syslog", "lpr", "news",
+	"uucp", "cron", "authpriv", "ftp", "ntp", "audit", "alert", "clock",
+	"local0", "local1", "local2", "local3", "local4", "local5", "local6", "local7",
+}
+
+var _ constrainedString = (*SyslogFacility)(nil)
+
+// Code returns the numerical code of the facility, as used to compute
+// the priority of syslog messages.
+func (f SyslogFacility) Code() int {
+	for i, name := range syslogFacilities {
+		if string(f) == name {
+			return i
+		}
+	}
+	// Unreachable for validated configurations; default to "user".
+	return 1
+}
+
+// Accept implements the constrainedString interface.
+func (f *SyslogFacility) Accept(s string) {
+	*f = SyslogFacility(s)
+}
+
+// Canonicalize implements the constrainedString interface.
+func (SyslogFacility) Canonicalize(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// AllowedSet implements the constrainedString interface.
+func (SyslogFacility) AllowedSet() []string {
+	return syslogFacilities
+}
+
+// MarshalYAML implements yaml.Marshaler interface.
+func (f SyslogFacility) MarshalYAML() (interface{}, error) {
+	return string(f), nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (f *SyslogFacility) UnmarshalYAML(fn func(interface{}) error) error {
+	return unmarshalYAMLConstrainedString(f, fn)
+}
+
+// FileCompression is a string restricted to "none", "gzip" and "zstd".
+type FileCompression string
+
+// Accepted values for FileCompression.
+const (
+	FileCompressionNone FileCompression = "none"
+	FileCompressionGzip FileCompression = "gzip"
+	FileCompressionZstd FileCompression = "zstd"
+)
+
+var _ constrainedString = (*FileCompression)(nil)
+
+// Accept implements the constrainedString interface.
+func (c *FileCompression) Accept(s string) {
+	*c = FileCompression(s)
+}
+
+// Canonicalize implements the constrainedString interface.
+func (FileCompression) Canonicalize(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// AllowedSet implements the constrainedString interface.
+func (FileCompression) AllowedSet() []string {
+	return []string{
+		string(FileCompressionNone),
+		string(FileCompressionGzip),
+		string(FileCompressionZstd),
+	}
+}
+
+// MarshalYAML implements yaml.Marshaler interface.
+func (c FileCompression) MarshalYAML() (interface{}, error) {
+	return string(c), nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (c *FileCompression) UnmarshalYAML(fn func(interface{}) error) error {
+	return unmarshalYAMLConstrainedString(c, fn)
+}
+
+// FluentProtocol is a string restricted to "json" and "forward".
+type FluentProtocol string
+
+// Accepted values for FluentProtocol.
+const (
+	FluentProtocolJSON    FluentProtocol = "json"
+	FluentProtocolForward FluentProtocol = "forward"
+)
+
+var _ constrainedString = (*FluentProtocol)(nil)
+
+// Accept implements the constrainedString interface.
+func (p *FluentProtocol) Accept(s string) {
+	*p = FluentProtocol(s)
+}
+
+// Canonicalize implements the constrainedString interface.
+func (FluentProtocol) Canonicalize(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// AllowedSet implements the constrainedString interface.
+func (FluentProtocol) AllowedSet() []string {
+	return []string{
+		string(FluentProtocolJSON),
+		string(FluentProtocolForward),
+	}
+}
+
+// MarshalYAML implements yaml.Marshaler interface.
+func (p FluentProtocol) MarshalYAML() (interface{}, error) {
+	return string(p), nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (p *FluentProtocol) UnmarshalYAML(fn func(interface{}) error) error {
+	return unmarshalYAMLConstrainedString(p, fn)
+}
+
+// FluentCompression is a string restricted to "none", "gzip" and
+// "zstd".
+type FluentCompression string
+
+// Accepted values for FluentCompression.
+const (
+	FluentCompressionNone FluentCompression = "none"
+	FluentCompressionGzip FluentCompression = "gzip"
+	FluentCompressionZstd FluentCompression = "zstd"
+)
+
+var _ constrainedString = (*FluentCompression)(nil)
+
+// Accept implements the constrainedString interface.
+func (c *FluentCompression) Accept(s string) {
+	*c = FluentCompression(s)
+}
+
+// Canonicalize implements the constrainedString interface.
+func (FluentCompression) Canonicalize(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// AllowedSet implements the constrainedString interface.
+func (FluentCompression) AllowedSet() []string {
+	return []string{
+		string(FluentCompressionNone),
+		string(FluentCompressionGzip),
+		string(FluentCompressionZstd),
+	}
+}
+
+// MarshalYAML implements yaml.Marshaler interface.
+func (c FluentCompression) MarshalYAML() (interface{}, error) {
+	return string(c), nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (c *FluentCompression) UnmarshalYAML(fn func(interface{}) error) error {
+	return unmarshalYAMLConstrainedString(c, fn)
+}
+
+// CountLabel is a string restricted to "channel", "severity" and
+// "file:line".
+type CountLabel string
+
+// Accepted values for CountLabel.
+const (
+	CountLabelChannel  CountLabel = "channel"
+	CountLabelSeverity CountLabel = "severity"
+	CountLabelFileLine CountLabel = "file:line"
+)
+
+var _ constrainedString = (*CountLabel)(nil)
+
+// Accept implements the constrainedString interface.
+func (l *CountLabel) Accept(s string) {
+	*l = CountLabel(s)
+}
+
+// Canonicalize implements the constrainedString interface.
+func (CountLabel) Canonicalize(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// AllowedSet implements the constrainedString interface.
+func (CountLabel) AllowedSet() []string {
+	return []string{
+		string(CountLabelChannel),
+		string(CountLabelSeverity),
+		string(CountLabelFileLine),
+	}
+}
+
+// MarshalYAML implements yaml.Marshaler interface.
+func (l CountLabel) MarshalYAML() (interface{}, error) {
+	return string(l), nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (l *CountLabel) UnmarshalYAML(fn func(interface{}) error) error {
+	return unmarshalYAMLConstrainedString(l, fn)
+}
+
 // constrainedString is an interface to make it easy to unmarshal
 // a string constrained to a small set of accepted values.
 type constrainedString interface {
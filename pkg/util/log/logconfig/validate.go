@@ -13,16 +13,19 @@ package logconfig
 import (
 	"bytes"
 	"fmt"
+	"math"
 	"net/http"
 	"path/filepath"
 	"reflect"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/build"
 	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 )
 
@@ -105,17 +108,46 @@ func (c *Config) Validate(defaultLogDir *string) (resErr error) {
 		},
 		UnsafeTLS:         &bf,
 		DisableKeepAlives: &bf,
+		SignWithNodeCert:  &bf,
 		Method:            func() *HTTPSinkMethod { m := HTTPSinkMethod(http.MethodPost); return &m }(),
 		Timeout:           &zeroDuration,
 	}
+	baseSyslogDefaults := SyslogDefaults{
+		CommonSinkConfig: CommonSinkConfig{
+			Format: func() *string { s := DefaultSyslogFormat; return &s }(),
+			Buffering: CommonBufferSinkConfigWrapper{
+				CommonBufferSinkConfig: CommonBufferSinkConfig{
+					MaxStaleness:     &defaultBufferedStaleness,
+					FlushTriggerSize: &defaultFlushTriggerSize,
+					MaxBufferSize:    &defaultMaxBufferSize,
+				},
+			},
+		},
+		Net:       func() *SyslogNetwork { n := SyslogNetworkTCP; return &n }(),
+		Facility:  func() *SyslogFacility { f := SyslogFacility("user"); return &f }(),
+		UnsafeTLS: &bf,
+	}
+	// The journald and Windows Event Log sinks have no defaults
+	// section: they report to a local service, so there is little to
+	// share across them.
+	baseJournaldConfig := CommonSinkConfig{
+		Format: func() *string { s := DefaultJournaldFormat; return &s }(),
+	}
+	baseEventLogConfig := CommonSinkConfig{
+		Format: func() *string { s := DefaultEventLogFormat; return &s }(),
+	}
 
 	propagateCommonDefaults(&baseFileDefaults.CommonSinkConfig, baseCommonSinkConfig)
 	propagateCommonDefaults(&baseFluentDefaults.CommonSinkConfig, baseCommonSinkConfig)
 	propagateCommonDefaults(&baseHTTPDefaults.CommonSinkConfig, baseCommonSinkConfig)
+	propagateCommonDefaults(&baseSyslogDefaults.CommonSinkConfig, baseCommonSinkConfig)
+	propagateCommonDefaults(&baseJournaldConfig, baseCommonSinkConfig)
+	propagateCommonDefaults(&baseEventLogConfig, baseCommonSinkConfig)
 
 	propagateFileDefaults(&c.FileDefaults, baseFileDefaults)
 	propagateFluentDefaults(&c.FluentDefaults, baseFluentDefaults)
 	propagateHTTPDefaults(&c.HTTPDefaults, baseHTTPDefaults)
+	propagateSyslogDefaults(&c.SyslogDefaults, baseSyslogDefaults)
 
 	// Normalize the directory.
 	if err := normalizeDir(&c.FileDefaults.Dir); err != nil {
@@ -157,6 +189,49 @@ func (c *Config) Validate(defaultLogDir *string) (resErr error) {
 		}
 	}
 
+	for sinkName, fc := range c.Sinks.SyslogServers {
+		if fc == nil {
+			fc = &SyslogSinkConfig{Channels: SelectChannels()}
+			c.Sinks.SyslogServers[sinkName] = fc
+		}
+		fc.sinkName = sinkName
+		if err := c.validateSyslogSinkConfig(fc); err != nil {
+			fmt.Fprintf(&errBuf, "syslog server %q: %v\n", sinkName, err)
+		}
+	}
+
+	for sinkName, fc := range c.Sinks.JournaldSinks {
+		if fc == nil {
+			fc = &JournaldSinkConfig{Channels: SelectChannels()}
+			c.Sinks.JournaldSinks[sinkName] = fc
+		}
+		fc.sinkName = sinkName
+		if err := c.validateJournaldSinkConfig(fc, baseJournaldConfig); err != nil {
+			fmt.Fprintf(&errBuf, "journald sink %q: %v\n", sinkName, err)
+		}
+	}
+
+	for sinkName, fc := range c.Sinks.EventLogSinks {
+		if fc == nil {
+			fc = &EventLogSinkConfig{Channels: SelectChannels()}
+			c.Sinks.EventLogSinks[sinkName] = fc
+		}
+		fc.sinkName = sinkName
+		if err := c.validateEventLogSinkConfig(fc, baseEventLogConfig); err != nil {
+			fmt.Fprintf(&errBuf, "eventlog sink %q: %v\n", sinkName, err)
+		}
+	}
+
+	for sinkName, fc := range c.Sinks.CountSinks {
+		if fc == nil {
+			fc = &CountSinkConfig{Channels: SelectChannels()}
+			c.Sinks.CountSinks[sinkName] = fc
+		}
+		if err := c.validateCountSinkConfig(fc); err != nil {
+			fmt.Fprintf(&errBuf, "count sink %q: %v\n", sinkName, err)
+		}
+	}
+
 	// Defaults for stderr.
 	if c.Sinks.Stderr.Filter == logpb.Severity_UNKNOWN {
 		c.Sinks.Stderr.Filter = logpb.Severity_NONE
@@ -170,7 +245,9 @@ func (c *Config) Validate(defaultLogDir *string) (resErr error) {
 		c.Sinks.Stderr.Criticality = &bt
 	}
 	c.Sinks.Stderr.Auditable = nil
-	if err := c.ValidateCommonSinkConfig(c.Sinks.Stderr.CommonSinkConfig); err != nil {
+	if err := checkTextFormat(c.Sinks.Stderr.CommonSinkConfig); err != nil {
+		fmt.Fprintf(&errBuf, "stderr sink: %v\n", err)
+	} else if err := c.ValidateCommonSinkConfig(c.Sinks.Stderr.CommonSinkConfig); err != nil {
 		fmt.Fprintf(&errBuf, "stderr sink: %v\n", err)
 	}
 
@@ -234,6 +311,54 @@ func (c *Config) Validate(defaultLogDir *string) (resErr error) {
 		}
 	}
 
+	for sinkName, fc := range c.Sinks.SyslogServers {
+		if len(fc.Channels.Filters) == 0 {
+			fmt.Fprintf(&errBuf, "syslog server %q: no channel selected\n", sinkName)
+		}
+		// Propagate the sink-wide default filter to all channels that don't
+		// have a filter yet.
+		if err := fc.Channels.Validate(fc.Filter); err != nil {
+			fmt.Fprintf(&errBuf, "syslog server %q: %v\n", sinkName, err)
+			continue
+		}
+	}
+
+	for sinkName, fc := range c.Sinks.JournaldSinks {
+		if len(fc.Channels.Filters) == 0 {
+			fmt.Fprintf(&errBuf, "journald sink %q: no channel selected\n", sinkName)
+		}
+		// Propagate the sink-wide default filter to all channels that don't
+		// have a filter yet.
+		if err := fc.Channels.Validate(fc.Filter); err != nil {
+			fmt.Fprintf(&errBuf, "journald sink %q: %v\n", sinkName, err)
+			continue
+		}
+	}
+
+	for sinkName, fc := range c.Sinks.EventLogSinks {
+		if len(fc.Channels.Filters) == 0 {
+			fmt.Fprintf(&errBuf, "eventlog sink %q: no channel selected\n", sinkName)
+		}
+		// Propagate the sink-wide default filter to all channels that don't
+		// have a filter yet.
+		if err := fc.Channels.Validate(fc.Filter); err != nil {
+			fmt.Fprintf(&errBuf, "eventlog sink %q: %v\n", sinkName, err)
+			continue
+		}
+	}
+
+	for sinkName, fc := range c.Sinks.CountSinks {
+		if len(fc.Channels.Filters) == 0 {
+			fmt.Fprintf(&errBuf, "count sink %q: no channel selected\n", sinkName)
+		}
+		// Propagate the sink-wide default filter to all channels that don't
+		// have a filter yet.
+		if err := fc.Channels.Validate(fc.Filter); err != nil {
+			fmt.Fprintf(&errBuf, "count sink %q: %v\n", sinkName, err)
+			continue
+		}
+	}
+
 	// If capture-stray-errors was enabled, then perform some additional
 	// validation on it.
 	if c.CaptureFd2.Enable {
@@ -264,6 +389,76 @@ func (c *Config) Validate(defaultLogDir *string) (resErr error) {
 		c.CaptureFd2 = CaptureFd2Config{}
 	}
 
+	// Canonicalize the channel names in the rate limits and propagate
+	// the default burst sizes.
+	if len(c.RateLimits) > 0 {
+		rateLimits := make(RateLimitConfig, len(c.RateLimits))
+		for chName, rl := range c.RateLimits {
+			canonicalName, ok := canonicalChannelName(chName)
+			if !ok {
+				fmt.Fprintf(&errBuf, "rate limits: unknown channel: %q\n", chName)
+				continue
+			}
+			if _, ok := rateLimits[canonicalName]; ok {
+				fmt.Fprintf(&errBuf, "rate limits: channel %s specified multiple times\n", canonicalName)
+				continue
+			}
+			if rl == nil || rl.EntriesPerSecond <= 0 {
+				fmt.Fprintf(&errBuf, "rate limits: channel %s: entries-per-second must be positive\n", canonicalName)
+				continue
+			}
+			if rl.Burst == nil {
+				burst := int(math.Ceil(rl.EntriesPerSecond))
+				rl.Burst = &burst
+			} else if *rl.Burst < 1 {
+				fmt.Fprintf(&errBuf, "rate limits: channel %s: burst must be at least 1\n", canonicalName)
+				continue
+			}
+			rateLimits[canonicalName] = rl
+		}
+		c.RateLimits = rateLimits
+	}
+
+	// Canonicalize the channel names in the budgets and propagate the
+	// default intervals and severities.
+	if len(c.Budgets) > 0 {
+		budgets := make(BudgetConfig, len(c.Budgets))
+		for chName, b := range c.Budgets {
+			canonicalName, ok := canonicalChannelName(chName)
+			if !ok {
+				fmt.Fprintf(&errBuf, "budgets: unknown channel: %q\n", chName)
+				continue
+			}
+			if _, ok := budgets[canonicalName]; ok {
+				fmt.Fprintf(&errBuf, "budgets: channel %s specified multiple times\n", canonicalName)
+				continue
+			}
+			if b == nil || b.BytesPerSecond == 0 {
+				fmt.Fprintf(&errBuf, "budgets: channel %s: bytes-per-second must be positive\n", canonicalName)
+				continue
+			}
+			if b.Interval == nil {
+				interval := defaultBudgetInterval
+				b.Interval = &interval
+			} else if *b.Interval <= 0 {
+				fmt.Fprintf(&errBuf, "budgets: channel %s: interval must be positive\n", canonicalName)
+				continue
+			}
+			if b.Suppress == nil {
+				sev := logpb.Severity_INFO
+				b.Suppress = &sev
+			} else if *b.Suppress == logpb.Severity_UNKNOWN || *b.Suppress >= logpb.Severity_FATAL {
+				fmt.Fprintf(&errBuf, "budgets: channel %s: cannot suppress %s entries\n", canonicalName, *b.Suppress)
+				continue
+			}
+			budgets[canonicalName] = b
+		}
+		c.Budgets = budgets
+	}
+
+	// Validate the events section and fill in the defaults of its sinks.
+	c.validateEventsConfig(&errBuf)
+
 	// If there is no file group for DEV yet, create one.
 	// We'll target the "default" group.
 	// If the "default" group already exists, we'll use that. Otherwise, we create it.
@@ -339,9 +534,145 @@ func (c *Config) Validate(defaultLogDir *string) (resErr error) {
 		}
 	}
 
+	// Elide all the syslog sinks where all channels have
+	// severity set to NONE.
+	for serverName, fc := range c.Sinks.SyslogServers {
+		if fc.Channels.noChannelsSelected() {
+			delete(c.Sinks.SyslogServers, serverName)
+		}
+	}
+
+	// Elide all the journald sinks where all channels have
+	// severity set to NONE.
+	for sinkName, fc := range c.Sinks.JournaldSinks {
+		if fc.Channels.noChannelsSelected() {
+			delete(c.Sinks.JournaldSinks, sinkName)
+		}
+	}
+
+	// Elide all the Windows Event Log sinks where all channels have
+	// severity set to NONE.
+	for sinkName, fc := range c.Sinks.EventLogSinks {
+		if fc.Channels.noChannelsSelected() {
+			delete(c.Sinks.EventLogSinks, sinkName)
+		}
+	}
+
+	// Elide all the count sinks where all channels have
+	// severity set to NONE.
+	for sinkName, fc := range c.Sinks.CountSinks {
+		if fc.Channels.noChannelsSelected() {
+			delete(c.Sinks.CountSinks, sinkName)
+		}
+	}
+
 	return nil
 }
 
+// validateEventsConfig validates the events section. The sinks of the
+// section receive the channels selected by the section, and inherit
+// its format and buffering options before the defaults of their type.
+func (c *Config) validateEventsConfig(errBuf *bytes.Buffer) {
+	e := &c.Events
+	if !e.IsEnabled() {
+		if e.Exclusive != nil && *e.Exclusive {
+			fmt.Fprintln(errBuf, "events: exclusive requires at least one sink")
+		}
+		// Not enabled: erase all fields so it gets omitted when pretty-printing.
+		*e = EventsConfig{}
+		return
+	}
+	if len(e.Channels.Channels) == 0 {
+		e.Channels.Channels = AllChannels()
+	}
+	e.Channels.Sort()
+	if e.Exclusive == nil {
+		bf := false
+		e.Exclusive = &bf
+	}
+
+	// selectChannels connects a sink of the section to the channels
+	// selected by the section.
+	selectChannels := func(chs *ChannelFilters, defSev logpb.Severity) error {
+		if len(chs.Filters) > 0 {
+			return errors.New("channels cannot be selected per sink in the events section")
+		}
+		*chs = SelectChannels(e.Channels.Channels...)
+		return chs.Validate(defSev)
+	}
+	// Buffering is not supported on file sinks (#72452), so the file
+	// sinks only inherit the format of the section.
+	fileDefaults := CommonSinkConfig{Format: e.Format}
+	networkDefaults := CommonSinkConfig{Format: e.Format, Buffering: e.Buffering}
+
+	for prefix, fc := range e.Sinks.FileGroups {
+		if fc == nil {
+			fc = &FileSinkConfig{}
+			e.Sinks.FileGroups[prefix] = fc
+		}
+		fc.prefix = prefix
+		if _, ok := c.Sinks.FileGroups[prefix]; ok {
+			fmt.Fprintf(errBuf, "events file group %q: name already used in the sinks section\n", prefix)
+			continue
+		}
+		propagateCommonDefaults(&fc.CommonSinkConfig, fileDefaults)
+		if err := c.validateFileSinkConfig(fc); err != nil {
+			fmt.Fprintf(errBuf, "events file group %q: %v\n", prefix, err)
+			continue
+		}
+		if err := selectChannels(&fc.Channels, fc.Filter); err != nil {
+			fmt.Fprintf(errBuf, "events file group %q: %v\n", prefix, err)
+		}
+	}
+
+	for serverName, fc := range e.Sinks.FluentServers {
+		if fc == nil {
+			fc = &FluentSinkConfig{}
+			e.Sinks.FluentServers[serverName] = fc
+		}
+		fc.serverName = serverName
+		if _, ok := c.Sinks.FluentServers[serverName]; ok {
+			fmt.Fprintf(errBuf, "events fluent server %q: name already used in the sinks section\n", serverName)
+			continue
+		}
+		propagateCommonDefaults(&fc.CommonSinkConfig, networkDefaults)
+		if err := c.validateFluentSinkConfig(fc); err != nil {
+			fmt.Fprintf(errBuf, "events fluent server %q: %v\n", serverName, err)
+			continue
+		}
+		if err := selectChannels(&fc.Channels, fc.Filter); err != nil {
+			fmt.Fprintf(errBuf, "events fluent server %q: %v\n", serverName, err)
+		}
+	}
+
+	for sinkName, fc := range e.Sinks.HTTPServers {
+		if fc == nil {
+			fc = &HTTPSinkConfig{}
+			e.Sinks.HTTPServers[sinkName] = fc
+		}
+		fc.sinkName = sinkName
+		if _, ok := c.Sinks.HTTPServers[sinkName]; ok {
+			fmt.Fprintf(errBuf, "events http server %q: name already used in the sinks section\n", sinkName)
+			continue
+		}
+		propagateCommonDefaults(&fc.CommonSinkConfig, networkDefaults)
+		if err := c.validateHTTPSinkConfig(fc); err != nil {
+			fmt.Fprintf(errBuf, "events http server %q: %v\n", sinkName, err)
+			continue
+		}
+		if err := selectChannels(&fc.Channels, fc.Filter); err != nil {
+			fmt.Fprintf(errBuf, "events http server %q: %v\n", sinkName, err)
+		}
+	}
+
+	// Elide the file sinks without a directory.
+	for prefix, fc := range e.Sinks.FileGroups {
+		if fc.Dir == nil {
+			delete(e.Sinks.FileGroups, prefix)
+		}
+	}
+}
+
 func (c *Config) newFileSinkConfig(groupName string) *FileSinkConfig {
 	fc := &FileSinkConfig{
 		Channels: SelectChannels(),
@@ -383,6 +714,7 @@ func (c *Config) validateFileSinkConfig(fc *FileSinkConfig) error {
 	if *fc.Auditable {
 		bf, bt := false, true
 		fc.BufferedWrites = &bf
+		fc.AsyncWrites = nil
 		fc.Criticality = &bt
 		if *fc.Format == "crdb-v1" {
 			s := "crdb-v1-count"
@@ -391,11 +723,39 @@ func (c *Config) validateFileSinkConfig(fc *FileSinkConfig) error {
 	}
 	fc.Auditable = nil
 
+	if fc.MaxAge != nil && *fc.MaxAge < 0 {
+		return errors.Newf("max-age cannot be negative: %s", *fc.MaxAge)
+	}
+
 	return c.ValidateCommonSinkConfig(fc.CommonSinkConfig)
 }
 
 // ValidateCommonSinkConfig validates a CommonSinkConfig.
 func (c *Config) ValidateCommonSinkConfig(conf CommonSinkConfig) error {
+	for _, p := range conf.Processors {
+		if strings.TrimSpace(p) == "" {
+			return errors.New("processor name cannot be empty")
+		}
+	}
+
+	for _, t := range conf.TenantFilter {
+		if t == SystemTenantFilter {
+			continue
+		}
+		if id, err := strconv.ParseUint(t, 10, 64); err != nil || id == 0 {
+			return errors.WithHint(errors.Newf("invalid tenant in tenant-filter: %q", t),
+				"Use a tenant ID or \"system\".")
+		}
+	}
+
+	if err := validateTimestampConfig(conf.Timestamps); err != nil {
+		return err
+	}
+
+	if m := conf.MaxEntrySize; m != nil && *m != 0 && *m < minMaxEntrySize {
+		return errors.Newf("max-entry-size (%s) cannot be smaller than %s", *m, minMaxEntrySize)
+	}
+
 	b := conf.Buffering
 	if b.IsNone() {
 		return nil
@@ -441,17 +801,328 @@ func (c *Config) validateFluentSinkConfig(fc *FluentSinkConfig) error {
 	}
 	fc.Auditable = nil
 
+	if err := checkTextFormat(fc.CommonSinkConfig); err != nil {
+		return err
+	}
+	if err := validateSpoolConfig(&fc.Spool, &fc.CommonSinkConfig); err != nil {
+		return err
+	}
+	if err := validateFluentForwardConfig(fc); err != nil {
+		return err
+	}
 	return c.ValidateCommonSinkConfig(fc.CommonSinkConfig)
 }
 
+// The defaults of the options of the fluent sinks which use the
+// forward protocol.
+const (
+	defaultFluentBatchSize     = 100
+	defaultFluentFlushInterval = time.Second
+)
+
+// validateFluentForwardConfig validates the options of the forward
+// protocol of a fluent sink and fills in their defaults. When the
+// forward protocol is used, in-memory buffering is disabled: the sink
+// batches the log entries itself.
+func validateFluentForwardConfig(fc *FluentSinkConfig) error {
+	if !fc.IsForward() {
+		// The options may have been inherited from the defaults; they
+		// are meaningless with the json protocol.
+		fc.Compression = nil
+		fc.BatchSize = nil
+		fc.FlushInterval = nil
+		fc.RequireAck = nil
+		return nil
+	}
+	if strings.HasPrefix(fc.Net, "udp") {
+		return errors.Newf("protocol %q requires a stream-oriented network, not %q",
+			FluentProtocolForward, fc.Net)
+	}
+	if fc.Format == nil || !strings.HasPrefix(*fc.Format, "json-fluent") {
+		return errors.WithHint(
+			errors.Newf("protocol %q requires a json-fluent format, not %q",
+				FluentProtocolForward, *fc.Format),
+			"Use format: "+DefaultFluentFormat+".")
+	}
+	if fc.Compression == nil {
+		c := FluentCompressionNone
+		fc.Compression = &c
+	}
+	if fc.BatchSize == nil {
+		n := defaultFluentBatchSize
+		fc.BatchSize = &n
+	} else if *fc.BatchSize <= 0 {
+		return errors.New("batch-size must be positive")
+	}
+	if fc.FlushInterval == nil {
+		d := defaultFluentFlushInterval
+		fc.FlushInterval = &d
+	} else if *fc.FlushInterval <= 0 {
+		return errors.New("flush-interval must be positive")
+	}
+	if fc.RequireAck == nil {
+		bf := false
+		fc.RequireAck = &bf
+	}
+	zeroDuration := time.Duration(0)
+	zeroByteSize := ByteSize(0)
+	fc.Buffering = CommonBufferSinkConfigWrapper{
+		CommonBufferSinkConfig: CommonBufferSinkConfig{
+			MaxStaleness:     &zeroDuration,
+			FlushTriggerSize: &zeroByteSize,
+			MaxBufferSize:    &zeroByteSize,
+		},
+	}
+	return nil
+}
+
 func (c *Config) validateHTTPSinkConfig(hsc *HTTPSinkConfig) error {
 	propagateHTTPDefaults(&hsc.HTTPDefaults, c.HTTPDefaults)
 	if hsc.Address == nil || len(*hsc.Address) == 0 {
 		return errors.New("address cannot be empty")
 	}
+	if (hsc.ClientCert == nil) != (hsc.ClientKey == nil) {
+		return errors.New("client-cert and client-key must be specified together")
+	}
+	for name := range hsc.Headers {
+		if strings.TrimSpace(name) == "" {
+			return errors.New("header names cannot be empty")
+		}
+	}
+	if hsc.AWSSigV4.IsEnabled() {
+		if hsc.AWSSigV4.Region == nil || *hsc.AWSSigV4.Region == "" {
+			return errors.New("aws-sigv4: region cannot be empty")
+		}
+		if hsc.AWSSigV4.Service == nil || *hsc.AWSSigV4.Service == "" {
+			return errors.New("aws-sigv4: service cannot be empty")
+		}
+	}
+	if err := checkTextFormat(hsc.CommonSinkConfig); err != nil {
+		return err
+	}
+	if err := validateSpoolConfig(&hsc.Spool, &hsc.CommonSinkConfig); err != nil {
+		return err
+	}
 	return c.ValidateCommonSinkConfig(hsc.CommonSinkConfig)
 }
 
+func (c *Config) validateSyslogSinkConfig(ssc *SyslogSinkConfig) error {
+	propagateSyslogDefaults(&ssc.SyslogDefaults, c.SyslogDefaults)
+	if ssc.Address == nil || len(strings.TrimSpace(*ssc.Address)) == 0 {
+		return errors.New("address cannot be empty")
+	}
+	addr := strings.TrimSpace(*ssc.Address)
+	ssc.Address = &addr
+
+	// Apply the auditable flag if set.
+	if *ssc.Auditable {
+		bt := true
+		ssc.Criticality = &bt
+	}
+	ssc.Auditable = nil
+
+	if err := checkTextFormat(ssc.CommonSinkConfig); err != nil {
+		return err
+	}
+	return c.ValidateCommonSinkConfig(ssc.CommonSinkConfig)
+}
+
+// defaultJournaldIdentifier is the default SYSLOG_IDENTIFIER of the
+// entries sent to the journal.
+const defaultJournaldIdentifier = "cockroach"
+
+// defaultJournaldSocket is the path to the socket on which journald
+// accepts entries using its native protocol.
+const defaultJournaldSocket = "/run/systemd/journal/socket"
+
+func (c *Config) validateJournaldSinkConfig(
+	jsc *JournaldSinkConfig, defaults CommonSinkConfig,
+) error {
+	propagateCommonDefaults(&jsc.CommonSinkConfig, defaults)
+	if jsc.Identifier == nil {
+		s := defaultJournaldIdentifier
+		jsc.Identifier = &s
+	} else if strings.TrimSpace(*jsc.Identifier) == "" {
+		return errors.New("identifier cannot be empty")
+	}
+	if jsc.Socket == nil {
+		s := defaultJournaldSocket
+		jsc.Socket = &s
+	} else if strings.TrimSpace(*jsc.Socket) == "" {
+		return errors.New("socket cannot be empty")
+	}
+	if err := checkUnbuffered("journald", jsc.CommonSinkConfig); err != nil {
+		return err
+	}
+	if err := validateLocalSinkConfig(&jsc.CommonSinkConfig); err != nil {
+		return err
+	}
+	return c.ValidateCommonSinkConfig(jsc.CommonSinkConfig)
+}
+
+// defaultEventLogSource is the default source of the events reported
+// to the Windows Event Log.
+const defaultEventLogSource = "CockroachDB"
+
+func (c *Config) validateEventLogSinkConfig(
+	esc *EventLogSinkConfig, defaults CommonSinkConfig,
+) error {
+	propagateCommonDefaults(&esc.CommonSinkConfig, defaults)
+	if esc.Source == nil {
+		s := defaultEventLogSource
+		esc.Source = &s
+	} else if strings.TrimSpace(*esc.Source) == "" {
+		return errors.New("source cannot be empty")
+	}
+	if err := checkUnbuffered("eventlog", esc.CommonSinkConfig); err != nil {
+		return err
+	}
+	if err := validateLocalSinkConfig(&esc.CommonSinkConfig); err != nil {
+		return err
+	}
+	return c.ValidateCommonSinkConfig(esc.CommonSinkConfig)
+}
+
+// validateLocalSinkConfig applies the auditable flag and checks the
+// format of the sinks which report to a local service of the OS.
+func validateLocalSinkConfig(conf *CommonSinkConfig) error {
+	if *conf.Auditable {
+		bt := true
+		conf.Criticality = &bt
+	}
+	conf.Auditable = nil
+	return checkTextFormat(*conf)
+}
+
+// checkUnbuffered rejects buffering on the sinks of the given type,
+// which send every log event separately.
+func checkUnbuffered(sinkType string, conf CommonSinkConfig) error {
+	if !conf.Buffering.IsNone() {
+		return errors.Newf("buffering is not supported for %s sinks", sinkType)
+	}
+	return nil
+}
+
+// defaultCountSinkMaxKeys is the default limit on the number of
+// distinct combinations of label values tracked by a count sink.
+const defaultCountSinkMaxKeys = 1000
+
+// defaultBudgetInterval is the default interval over which the
+// per-channel budgets are accounted.
+const defaultBudgetInterval = 10 * time.Second
+
+// canonicalChannelName returns the canonical name of the channel with
+// the given case-insensitive name, if there is one.
+func canonicalChannelName(name string) (string, bool) {
+	ch, ok := logpb.Channel_value[strings.ToUpper(strings.TrimSpace(name))]
+	if !ok || logpb.Channel(ch) == logpb.Channel_CHANNEL_MAX {
+		return "", false
+	}
+	return logpb.Channel(ch).String(), true
+}
+
+func (c *Config) validateCountSinkConfig(csc *CountSinkConfig) error {
+	if csc.Filter == logpb.Severity_UNKNOWN {
+		csc.Filter = logpb.Severity_INFO
+	}
+	if len(csc.Labels) == 0 {
+		csc.Labels = []CountLabel{CountLabelChannel, CountLabelSeverity}
+	}
+	seen := make(map[CountLabel]struct{}, len(csc.Labels))
+	for _, l := range csc.Labels {
+		if _, ok := seen[l]; ok {
+			return errors.Newf("duplicate label: %q", l)
+		}
+		seen[l] = struct{}{}
+	}
+	if csc.MaxKeys == nil {
+		n := defaultCountSinkMaxKeys
+		csc.MaxKeys = &n
+	} else if *csc.MaxKeys <= 0 {
+		return errors.New("max-keys must be positive")
+	}
+	return c.ValidateCommonSinkConfig(CommonSinkConfig{
+		Processors:   csc.Processors,
+		TenantFilter: csc.TenantFilter,
+	})
+}
+
+// minMaxEntrySize is the smallest acceptable limit on the size of the
+// messages of the log events emitted to a sink. Smaller limits would
+// leave little of the messages around the elision marker.
+const minMaxEntrySize = ByteSize(256)
+
+// defaultSpoolMaxSize is the default limit on the disk usage of the
+// spool of a network sink.
+const defaultSpoolMaxSize = ByteSize(1 << 30) // 1GiB
+
+// minSpoolMaxSize is the smallest acceptable limit on the disk usage
+// of the spool of a network sink.
+const minSpoolMaxSize = ByteSize(1 << 20) // 1MiB
+
+// validateSpoolConfig validates the spool configuration of a network
+// sink and fills in its defaults. When the spool is enabled, in-memory
+// buffering is disabled: the spool takes its role.
+func validateSpoolConfig(sc *SpoolConfig, common *CommonSinkConfig) error {
+	if !sc.IsEnabled() {
+		// The max-size may have been inherited from the defaults; it is
+		// meaningless without a directory.
+		sc.MaxSize = nil
+		return nil
+	}
+	if err := normalizeDir(&sc.Dir); err != nil {
+		return errors.Wrap(err, "spool")
+	}
+	if sc.MaxSize == nil {
+		m := defaultSpoolMaxSize
+		sc.MaxSize = &m
+	}
+	if *sc.MaxSize < minSpoolMaxSize {
+		return errors.Newf("spool max-size (%s) cannot be smaller than %s", *sc.MaxSize, minSpoolMaxSize)
+	}
+	zeroDuration := time.Duration(0)
+	zeroByteSize := ByteSize(0)
+	common.Buffering = CommonBufferSinkConfigWrapper{
+		CommonBufferSinkConfig: CommonBufferSinkConfig{
+			MaxStaleness:     &zeroDuration,
+			FlushTriggerSize: &zeroByteSize,
+			MaxBufferSize:    &zeroByteSize,
+		},
+	}
+	return nil
+}
+
+// validateTimestampConfig validates the timestamp rendering options of
+// a sink.
+func validateTimestampConfig(tc TimestampConfig) error {
+	if tc.Format != nil {
+		switch *tc.Format {
+		case DefaultTimestampFormat, RFC3339TimestampFormat:
+		default:
+			return errors.WithHint(errors.Newf("unknown timestamp format: %q", *tc.Format),
+				"Use \""+DefaultTimestampFormat+"\" or \""+RFC3339TimestampFormat+"\".")
+		}
+	}
+	if tc.Zone != nil {
+		if !tc.IsRFC3339() {
+			return errors.Newf("timestamp zone requires the %q timestamp format", RFC3339TimestampFormat)
+		}
+		if _, err := timeutil.LoadLocation(*tc.Zone); err != nil {
+			return errors.Wrap(err, "timestamp zone")
+		}
+	}
+	return nil
+}
+
+// checkTextFormat rejects the binary format, which is only supported
+// by file sinks.
+func checkTextFormat(conf CommonSinkConfig) error {
+	if conf.Format != nil && *conf.Format == ProtoFileFormat {
+		return errors.Newf("format %q is only supported by file sinks", ProtoFileFormat)
+	}
+	return nil
+}
+
 func normalizeDir(dir **string) error {
 	if *dir == nil {
 		return nil
@@ -486,6 +1157,10 @@ func propagateHTTPDefaults(target *HTTPDefaults, source HTTPDefaults) {
 	propagateDefaults(target, source)
 }
 
+func propagateSyslogDefaults(target *SyslogDefaults, source SyslogDefaults) {
+	propagateDefaults(target, source)
+}
+
 // propagateDefaults takes (target *T, source T) where T is a struct
 // and sets zero-valued exported fields in target to the values
 // from source (recursively for struct-valued fields).
@@ -63,37 +63,40 @@ func clearExpectedValues(c *Config) {
 	c.FileDefaults = FileDefaults{}
 	c.FluentDefaults = FluentDefaults{}
 	c.HTTPDefaults = HTTPDefaults{}
+	c.SyslogDefaults = SyslogDefaults{}
 
-	for _, f := range c.Sinks.FileGroups {
-		if *f.Dir == "/default-dir" {
-			f.Dir = nil
-		}
-		if *f.MaxFileSize == ByteSize(10<<20) {
-			f.MaxFileSize = nil
-		}
-		if *f.MaxGroupSize == ByteSize(100<<20) {
-			f.MaxGroupSize = nil
-		}
-		if *f.FilePermissions == FilePermissions(0644) {
-			f.FilePermissions = nil
-		}
-		if *f.BufferedWrites == true {
-			f.BufferedWrites = nil
-		}
-		if *f.Format == "crdb-v2" {
-			f.Format = nil
-		}
-		if *f.Redact == false {
-			f.Redact = nil
-		}
-		if *f.Redactable == true {
-			f.Redactable = nil
-		}
-		if *f.Criticality == true {
-			f.Criticality = nil
-		}
-		if f.Buffering.IsNone() {
-			f.Buffering = CommonBufferSinkConfigWrapper{}
+	for _, fileGroups := range []map[string]*FileSinkConfig{c.Sinks.FileGroups, c.Events.Sinks.FileGroups} {
+		for _, f := range fileGroups {
+			if *f.Dir == "/default-dir" {
+				f.Dir = nil
+			}
+			if *f.MaxFileSize == ByteSize(10<<20) {
+				f.MaxFileSize = nil
+			}
+			if *f.MaxGroupSize == ByteSize(100<<20) {
+				f.MaxGroupSize = nil
+			}
+			if *f.FilePermissions == FilePermissions(0644) {
+				f.FilePermissions = nil
+			}
+			if *f.BufferedWrites == true {
+				f.BufferedWrites = nil
+			}
+			if *f.Format == "crdb-v2" {
+				f.Format = nil
+			}
+			if *f.Redact == false {
+				f.Redact = nil
+			}
+			if *f.Redactable == true {
+				f.Redactable = nil
+			}
+			if *f.Criticality == true {
+				f.Criticality = nil
+			}
+			if f.Buffering.IsNone() {
+				f.Buffering = CommonBufferSinkConfigWrapper{}
+			}
 		}
 	}
 
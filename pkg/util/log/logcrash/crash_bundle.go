@@ -0,0 +1,151 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package logcrash
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/build"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	sentry "github.com/getsentry/sentry-go"
+)
+
+// CrashBundleDestinations wraps "diagnostics.crash_bundle.destinations".
+//
+// The crash bundles are delivered in addition to the crash reports sent
+// to Cockroach Labs, to destinations chosen by the operator. They are
+// disabled by default.
+var CrashBundleDestinations = settings.RegisterValidatedStringSetting(
+	settings.TenantWritable,
+	"diagnostics.crash_bundle.destinations",
+	"comma-separated list of destinations of the redacted diagnostics bundle assembled "+
+		"when the process crashes: file://DIRECTORY, http(s)://ENDPOINT or "+
+		"sentry+DSN for a Sentry-compatible DSN; empty to disable",
+	"",
+	func(_ *settings.Values, s string) error {
+		_, err := parseCrashBundleDestinations(s)
+		return err
+	},
+).WithPublic()
+
+// sentryDestinationPrefix is the prefix of the Sentry DSNs in the
+// crash bundle destinations.
+const sentryDestinationPrefix = "sentry+"
+
+// setupCrashBundleDestinations configures the crash bundle destinations
+// of the process from the given settings, and keeps them up to date.
+func setupCrashBundleDestinations(sv *settings.Values) {
+	apply := func(ctx context.Context) {
+		dests, err := parseCrashBundleDestinations(CrashBundleDestinations.Get(sv))
+		if err != nil {
+			// The setting is validated, hence this is unexpected.
+			log.Warningf(ctx, "invalid crash bundle destinations: %v", err)
+			return
+		}
+		log.SetCrashBundleDestinations(dests...)
+	}
+	CrashBundleDestinations.SetOnChange(sv, apply)
+	apply(context.Background())
+}
+
+// parseCrashBundleDestinations parses the value of the
+// diagnostics.crash_bundle.destinations setting.
+func parseCrashBundleDestinations(s string) ([]log.CrashBundleDestination, error) {
+	var dests []log.CrashBundleDestination
+	for _, d := range strings.Split(s, ",") {
+		d = strings.TrimSpace(d)
+		switch {
+		case d == "":
+			// Empty strings such as from a trailing comma can be ignored.
+			continue
+		case strings.HasPrefix(d, "file://"):
+			dir := strings.TrimPrefix(d, "file://")
+			if dir == "" {
+				return nil, errors.Newf("missing directory in crash bundle destination %q", d)
+			}
+			dests = append(dests, log.NewFileCrashBundleDestination(dir))
+		case strings.HasPrefix(d, "http://"), strings.HasPrefix(d, "https://"):
+			if _, err := url.Parse(d); err != nil {
+				return nil, errors.Wrapf(err, "invalid crash bundle destination %q", d)
+			}
+			dests = append(dests, log.NewHTTPCrashBundleDestination(d))
+		case strings.HasPrefix(d, sentryDestinationPrefix):
+			dest, err := newSentryCrashBundleDestination(strings.TrimPrefix(d, sentryDestinationPrefix))
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid crash bundle destination %q", d)
+			}
+			dests = append(dests, dest)
+		default:
+			return nil, errors.Newf("unsupported crash bundle destination %q", d)
+		}
+	}
+	return dests, nil
+}
+
+// sentryCrashBundleDestination sends the crash bundles as events to a
+// Sentry-compatible endpoint.
+type sentryCrashBundleDestination struct {
+	// host is the host of the DSN, which unlike the DSN itself does not
+	// contain the key of the project.
+	host   string
+	client *sentry.Client
+}
+
+func newSentryCrashBundleDestination(dsn string) (*sentryCrashBundleDestination, error) {
+	if _, err := sentry.NewDsn(dsn); err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, err
+	}
+	info := build.GetInfo()
+	client, err := sentry.NewClient(sentry.ClientOptions{
+		Dsn:         dsn,
+		Environment: crashReportEnv,
+		Release:     info.Tag,
+		Dist:        info.Distribution,
+		// The bundle must be delivered before the process terminates.
+		Transport: sentry.NewHTTPSyncTransport(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &sentryCrashBundleDestination{host: u.Host, client: client}, nil
+}
+
+// Deliver implements the log.CrashBundleDestination interface.
+func (d *sentryCrashBundleDestination) Deliver(_ context.Context, b *log.CrashBundle) error {
+	event := sentry.NewEvent()
+	event.Level = sentry.LevelFatal
+	event.Message = b.Reason
+	event.Timestamp = b.Time
+	event.Tags = map[string]string{
+		"platform": b.Build.Platform,
+		"rev":      b.Build.Revision,
+	}
+	event.Extra = map[string]interface{}{
+		"entries": b.Entries,
+		"stacks":  b.Stacks,
+	}
+	if d.client.CaptureEvent(event, nil /* hint */, nil /* scope */) == nil {
+		return errors.New("event dropped")
+	}
+	return nil
+}
+
+func (d *sentryCrashBundleDestination) String() string {
+	return sentryDestinationPrefix + d.host
+}
@@ -0,0 +1,50 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package logcrash
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCrashBundleDestinations(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testCases := []struct {
+		value    string
+		expected string
+	}{
+		{"", "[]"},
+		{" , ", "[]"},
+		{"file:///tmp/crashes", "[file:///tmp/crashes]"},
+		{"https://example.com/crashes,file://crashes",
+			"[https://example.com/crashes file://crashes]"},
+		// The key of the Sentry project is not shown.
+		{"sentry+https://key@sentry.example.com/42", "[sentry+sentry.example.com]"},
+		{"file://", `missing directory in crash bundle destination "file://"`},
+		{"sentry+https://sentry.example.com/42",
+			`invalid crash bundle destination "sentry+https://sentry.example.com/42": [Sentry] DsnParseError: empty username`},
+		{"ftp://example.com", `unsupported crash bundle destination "ftp://example.com"`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			dests, err := parseCrashBundleDestinations(tc.value)
+			if err != nil {
+				assert.Equal(t, tc.expected, err.Error())
+				return
+			}
+			assert.Equal(t, tc.expected, fmt.Sprint(dests))
+		})
+	}
+}
@@ -94,6 +94,7 @@ var (
 // around, as they will be stale / lies.
 func SetGlobalSettings(v *settings.Values) {
 	globalSettings.Store(v)
+	setupCrashBundleDestinations(v)
 }
 
 func getGlobalSettings() *settings.Values {
@@ -175,6 +176,8 @@ func ReportPanic(ctx context.Context, sv *settings.Values, r interface{}, depth
 
 	// In addition to informing the user, also report the details to telemetry.
 	sendCrashReport(ctx, sv, panicErr, ReportTypePanic)
+	// And to the destinations chosen by the operator, if any.
+	log.MaybeDeliverCrashBundle(ctx, panicErr)
 
 	// Ensure that the logs are flushed before letting a panic
 	// terminate the server.
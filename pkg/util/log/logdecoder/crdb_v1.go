@@ -0,0 +1,208 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package logdecoder
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+)
+
+// We don't include a capture group for the log message here, just for the
+// preamble, because a capture group that handles multiline messages is very
+// slow when running on the large buffers passed to EntryDecoder.split.
+var entryREV1 = regexp.MustCompile(
+	`(?m)^` +
+		/* Severity         */ `([` + SeverityChar + `])` +
+		/* Date and time    */ `(\d{6} \d{2}:\d{2}:\d{2}.\d{6}) ` +
+		/* Goroutine ID     */ `(?:(\d+) )?` +
+		/* Channel/File/Line*/ `([^:]+):(\d+) ` +
+		/* Redactable flag  */ `((?:` + RedactableIndicator + `)?) ` +
+		/* Context tags     */ `(?:\[((?:[^]]|\][^ ])+)\] )?`,
+)
+
+type entryDecoderV1 struct {
+	scanner            *bufio.Scanner
+	sensitiveEditor    redactEditor
+	truncatedLastEntry bool
+}
+
+// Decode decodes the next log entry into the provided protobuf message.
+func (d *entryDecoderV1) Decode(entry *logpb.Entry) error {
+	for {
+		if !d.scanner.Scan() {
+			if err := d.scanner.Err(); err != nil {
+				return err
+			}
+			return io.EOF
+		}
+		b := d.scanner.Bytes()
+		m := entryREV1.FindSubmatch(b)
+		if m == nil {
+			continue
+		}
+
+		// Erase all the fields, to be sure.
+		*entry = logpb.Entry{}
+
+		// Process the severity.
+		entry.Severity = SeverityFromChar(m[1][0])
+
+		// Process the timestamp.
+		t, err := time.Parse(MessageTimeFormat, string(m[2]))
+		if err != nil {
+			return err
+		}
+		entry.Time = t.UnixNano()
+
+		// Process the goroutine ID.
+		if len(m[3]) > 0 {
+			goroutine, err := strconv.Atoi(string(m[3]))
+			if err != nil {
+				return err
+			}
+			entry.Goroutine = int64(goroutine)
+		}
+
+		// Process the channel/file/line details.
+		entry.File = string(m[4])
+		if idx := strings.IndexByte(entry.File, '@'); idx != -1 {
+			ch, err := strconv.Atoi(entry.File[:idx])
+			if err != nil {
+				return err
+			}
+			entry.Channel = logpb.Channel(ch)
+			entry.File = entry.File[idx+1:]
+		}
+
+		line, err := strconv.Atoi(string(m[5]))
+		if err != nil {
+			return err
+		}
+		entry.Line = int64(line)
+
+		// Process the context tags.
+		redactable := len(m[6]) != 0
+		if len(m[7]) != 0 {
+			r := redactablePackage{
+				msg:        m[7],
+				redactable: redactable,
+			}
+			r = d.sensitiveEditor(r)
+			entry.Tags = string(r.msg)
+		}
+
+		// If there's an entry counter at the start of the message, process it.
+		msg := b[len(m[0]):]
+		i := 0
+		for ; i < len(msg) && msg[i] >= '0' && msg[i] <= '9'; i++ {
+			entry.Counter = entry.Counter*10 + uint64(msg[i]-'0')
+		}
+		if i > 0 && i < len(msg) && msg[i] == ' ' {
+			// Only accept the entry counter if followed by a space. In all
+			// other cases, the number was part of the message string.
+			msg = msg[i+1:]
+		} else {
+			// This was not truly an entry counter. Ignore the work done previously.
+			entry.Counter = 0
+		}
+
+		// Process the remainder of the log message.
+		r := redactablePackage{
+			msg:        trimFinalNewLines(msg),
+			redactable: redactable,
+		}
+		r = d.sensitiveEditor(r)
+		entry.Message = string(r.msg)
+		entry.Redactable = r.redactable
+
+		if strings.HasPrefix(entry.Message, StructuredEntryPrefix+"{") /* crdb-v1 prefix */ {
+			// Note: we do not recognize the v2 marker here (" ={") because
+			// v2 entries can be split across multiple lines.
+			entry.StructuredStart = uint32(len(StructuredEntryPrefix))
+
+			if nl := strings.IndexByte(entry.Message, '\n'); nl != -1 {
+				entry.StructuredEnd = uint32(nl)
+				entry.StackTraceStart = uint32(nl + 1)
+			} else {
+				entry.StructuredEnd = uint32(len(entry.Message))
+			}
+		}
+		// Note: we only know how to populate entry.StackTraceStart upon
+		// parse if the entry was structured (see above). If it is not
+		// structured, we cannot distinguish where the message ends and
+		// where the stack trace starts. This is another reason why the
+		// crdb-v1 format is lossy.
+
+		return nil
+	}
+}
+
+// split function for the crdb-v1 entry decoder scanner.
+func (d *entryDecoderV1) split(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if d.truncatedLastEntry {
+		i := entryREV1.FindIndex(data)
+		if i == nil {
+			// If there's no entry that starts in this chunk, advance past it, since
+			// we've truncated the entry it was originally part of.
+			return len(data), nil, nil
+		}
+		d.truncatedLastEntry = false
+		if i[0] > 0 {
+			// If an entry starts anywhere other than the first index, advance to it
+			// to maintain the invariant that entries start at the beginning of data.
+			// This isn't necessary, but simplifies the code below.
+			return i[0], nil, nil
+		}
+		// If i[0] == 0, then a new entry starts at the beginning of data, so fall
+		// through to the normal logic.
+	}
+	// From this point on, we assume we're currently positioned at a log entry.
+	// We want to find the next one so we start our search at data[1].
+	i := entryREV1.FindIndex(data[1:])
+	if i == nil {
+		if atEOF {
+			return len(data), data, nil
+		}
+		if len(data) >= bufio.MaxScanTokenSize {
+			// If there's no room left in the buffer, return the current truncated
+			// entry.
+			d.truncatedLastEntry = true
+			return len(data), data, nil
+		}
+		// If there is still room to read more, ask for more before deciding whether
+		// to truncate the entry.
+		return 0, nil, nil
+	}
+	// i[0] is the start of the next log entry, but we need to adjust the value
+	// to account for using data[1:] above.
+	i[0]++
+	return i[0], data[:i[0]], nil
+}
+
+func trimFinalNewLines(s []byte) []byte {
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i] == '\n' {
+			s = s[:i]
+		} else {
+			break
+		}
+	}
+	return s
+}
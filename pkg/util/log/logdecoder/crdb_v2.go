@@ -0,0 +1,283 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package logdecoder
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+	"github.com/cockroachdb/errors"
+)
+
+var (
+	entryREV2 = regexp.MustCompile(
+		`(?m)^` +
+			/* Severity                 */ `(?P<severity>[` + SeverityChar + `])` +
+			/* Date and time            */ `(?P<datetime>\d{6} \d{2}:\d{2}:\d{2}.\d{6}|` +
+			/* ... or RFC 3339 time     */ `\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\.\d{6}(?:Z|[+-]\d{2}:\d{2}))` +
+			/* Time delta               */ `(?: \+\d+\.\d{6})? ` +
+			/* Goroutine ID             */ `(?:(?P<goroutine>\d+) )` +
+			/* Go standard library flag */ `(\(gostd\) )?` +
+			/* Channel                  */ `(?:(?P<channel>\d+)@)?` +
+			/* File                     */ `(?P<file>[^:]+):` +
+			/* Line                     */ `(?:(?P<line>\d+) )` +
+			/* Redactable flag          */ `(?P<redactable>(?:` + RedactableIndicator + `)?) ` +
+			/* Context tags             */ `\[(?P<tags>(?:[^]]|\][^ ])+)\] ` +
+			/* Counter                  */ `(?P<counter>(?:\d+)?) ` +
+			/* Continuation marker      */ `(?P<continuation>[ =!+|])` +
+			/* Message                  */ `(?P<msg>.*)$`,
+	)
+	v2SeverityIdx     = entryREV2.SubexpIndex("severity")
+	v2DateTimeIdx     = entryREV2.SubexpIndex("datetime")
+	v2GoroutineIdx    = entryREV2.SubexpIndex("goroutine")
+	v2ChannelIdx      = entryREV2.SubexpIndex("channel")
+	v2FileIdx         = entryREV2.SubexpIndex("file")
+	v2LineIdx         = entryREV2.SubexpIndex("line")
+	v2RedactableIdx   = entryREV2.SubexpIndex("redactable")
+	v2TagsIdx         = entryREV2.SubexpIndex("tags")
+	v2CounterIdx      = entryREV2.SubexpIndex("counter")
+	v2ContinuationIdx = entryREV2.SubexpIndex("continuation")
+	v2MsgIdx          = entryREV2.SubexpIndex("msg")
+)
+
+type entryDecoderV2 struct {
+	lines           int // number of lines read from reader
+	reader          *bufio.Reader
+	nextFragment    entryDecoderV2Fragment
+	sensitiveEditor redactEditor
+}
+
+// Decode decodes the next log entry into the provided protobuf message.
+func (d *entryDecoderV2) Decode(entry *logpb.Entry) (err error) {
+	defer func() {
+		switch r := recover().(type) {
+		case nil: // do nothing
+		case error:
+			err = errors.Wrapf(r, "decoding on line %d", d.lines)
+		default:
+			panic(r)
+		}
+	}()
+	frag, atEOF := d.peekNextFragment()
+	if atEOF {
+		return io.EOF
+	}
+	d.popFragment()
+	if err := d.initEntryFromFirstLine(entry, frag); err != nil {
+		return err
+	}
+
+	// Process the message.
+	var entryMsg bytes.Buffer
+	entryMsg.Write(frag.getMsg())
+
+	// While the entry has additional lines, collect the full message.
+	for {
+		frag, atEOF := d.peekNextFragment()
+		if atEOF || !frag.isContinuation() {
+			break
+		}
+		d.popFragment()
+		d.addContinuationFragmentToEntry(entry, &entryMsg, frag)
+	}
+
+	r := redactablePackage{
+		msg:        entryMsg.Bytes(),
+		redactable: entry.Redactable,
+	}
+	r = d.sensitiveEditor(r)
+	entry.Message = string(r.msg)
+	entry.Redactable = r.redactable
+
+	return nil
+}
+
+func (d *entryDecoderV2) addContinuationFragmentToEntry(
+	entry *logpb.Entry, entryMsg *bytes.Buffer, frag entryDecoderV2Fragment,
+) {
+	switch frag.getContinuation() {
+	case '+':
+		entryMsg.WriteByte('\n')
+		entryMsg.Write(frag.getMsg())
+	case '|':
+		entryMsg.Write(frag.getMsg())
+		if entry.StructuredEnd != 0 {
+			entry.StructuredEnd = uint32(entryMsg.Len())
+		}
+	case '!':
+		if entry.StackTraceStart == 0 {
+			entry.StackTraceStart = uint32(entryMsg.Len()) + 1
+			entryMsg.WriteString("\nstack trace:\n")
+			entryMsg.Write(frag.getMsg())
+		} else {
+			entryMsg.WriteString("\n")
+			entryMsg.Write(frag.getMsg())
+		}
+	default:
+		panic(errors.Errorf("unexpected continuation character %c", frag.getContinuation()))
+	}
+}
+
+// peekNextFragment populates the nextFragment buffer by reading from the
+// underlying reader a line at a time until a valid line is reached.
+// It will panic if a malformed log line is discovered. It permits the first
+// line in the decoder to be malformed and it will skip that line. Upon EOF,
+// if there is no text left to consume, the atEOF return value will be true.
+func (d *entryDecoderV2) peekNextFragment() (_ entryDecoderV2Fragment, atEOF bool) {
+	for d.nextFragment == nil {
+		d.lines++
+		nextLine, err := d.reader.ReadBytes('\n')
+		if isEOF := errors.Is(err, io.EOF); isEOF {
+			if len(nextLine) == 0 {
+				return nil, true
+			}
+		} else if err != nil {
+			panic(err)
+		}
+		nextLine = bytes.TrimSuffix(nextLine, []byte{'\n'})
+		m := entryREV2.FindSubmatch(nextLine)
+		if m == nil {
+			if d.lines == 1 { // allow non-matching lines if we've never seen a line
+				continue
+			}
+			panic(errors.New("malformed log entry"))
+		}
+		d.nextFragment = m
+	}
+	return d.nextFragment, false
+}
+
+func (d *entryDecoderV2) popFragment() {
+	if d.nextFragment == nil {
+		panic(errors.AssertionFailedf("cannot pop unpopulated fragment"))
+	}
+	d.nextFragment = nil
+}
+
+func (d *entryDecoderV2) initEntryFromFirstLine(
+	entry *logpb.Entry, m entryDecoderV2Fragment,
+) (err error) {
+	// Erase all the fields, to be sure.
+	*entry = logpb.Entry{
+		Severity:   m.getSeverity(),
+		Time:       m.getTimestamp(),
+		Goroutine:  m.getGoroutine(),
+		Channel:    m.getChannel(),
+		File:       m.getFile(),
+		Line:       m.getLine(),
+		Redactable: m.isRedactable(),
+		Tags:       m.getTags(d.sensitiveEditor),
+		Counter:    m.getCounter(),
+	}
+	if m.isStructured() {
+		entry.StructuredStart = 0
+		entry.StructuredEnd = uint32(len(m.getMsg()))
+	}
+	return nil
+}
+
+// entryDecoderV2Fragment is a line which is part of a v2 log entry.
+// It is the output of entryV2RE.FindSubmatch.
+type entryDecoderV2Fragment [][]byte
+
+func (f entryDecoderV2Fragment) getSeverity() logpb.Severity {
+	return SeverityFromChar(f[v2SeverityIdx][0])
+}
+
+func (f entryDecoderV2Fragment) getMsg() []byte {
+	return f[v2MsgIdx]
+}
+
+func (f entryDecoderV2Fragment) getContinuation() byte {
+	return f[v2ContinuationIdx][0]
+}
+
+func (f entryDecoderV2Fragment) isContinuation() bool {
+	switch f.getContinuation() {
+	case '|', '+', '!':
+		return true
+	default:
+		return false
+	}
+}
+
+func (f entryDecoderV2Fragment) getGoroutine() int64 {
+	return parseInt(f[v2GoroutineIdx], "goroutine")
+}
+
+func (f entryDecoderV2Fragment) getTimestamp() (unixNano int64) {
+	layout := MessageTimeFormat
+	if bytes.IndexByte(f[v2DateTimeIdx], 'T') >= 0 {
+		// The sink was configured with `timestamps: {format: rfc3339}`.
+		layout = time.RFC3339Nano
+	}
+	t, err := time.Parse(layout, string(f[v2DateTimeIdx]))
+	if err != nil {
+		panic(err)
+	}
+	return t.UnixNano()
+}
+
+func (f entryDecoderV2Fragment) getChannel() logpb.Channel {
+	if len(f[v2ChannelIdx]) == 0 {
+		return logpb.Channel(0)
+	}
+	return logpb.Channel(parseInt(f[v2ChannelIdx], "channel"))
+}
+
+func (f entryDecoderV2Fragment) getFile() string {
+	return string(f[v2FileIdx])
+}
+
+func (f entryDecoderV2Fragment) getLine() int64 {
+	return parseInt(f[v2LineIdx], "line")
+}
+
+func (f entryDecoderV2Fragment) isRedactable() bool {
+	return len(f[v2RedactableIdx]) > 0
+}
+
+func (f entryDecoderV2Fragment) getTags(editor redactEditor) string {
+	switch tagsStr := string(f[v2TagsIdx]); tagsStr {
+	case "-":
+		return tagsStr
+	default:
+		r := editor(redactablePackage{
+			msg:        f[v2TagsIdx],
+			redactable: f.isRedactable(),
+		})
+		return string(r.msg)
+	}
+}
+
+func (f entryDecoderV2Fragment) getCounter() uint64 {
+	if len(f[v2CounterIdx]) == 0 {
+		return 0
+	}
+	return uint64(parseInt(f[v2CounterIdx], "counter"))
+}
+
+func (f entryDecoderV2Fragment) isStructured() bool {
+	return f.getContinuation() == '='
+}
+
+func parseInt(data []byte, name string) int64 {
+	i, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		panic(errors.Wrapf(err, "parsing %s", name))
+	}
+	return i
+}
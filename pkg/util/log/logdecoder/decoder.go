@@ -0,0 +1,241 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package logdecoder decodes the log files produced by package log.
+//
+// This package is meant to be usable outside of a CockroachDB server
+// process, for example compiled to WebAssembly for a log viewer running
+// in the browser. For this reason, it must not depend on package log
+// nor on OS facilities (files, environment, etc.). The dependencies which
+// do not satisfy this constraint are excluded from js builds (GOOS=js,
+// as used by the WebAssembly targets of the Go and TinyGo compilers).
+package logdecoder
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"regexp"
+
+	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+	"github.com/cockroachdb/errors"
+)
+
+var (
+	formatRE = regexp.MustCompile(
+		`(?m)^` +
+			/* Prefix */ `(?:.*\[config\][ ]+log format \(utf8=.+\): )` +
+			/* Format */ `(.*)$`,
+	)
+	v2IndicatorRE = regexp.MustCompile(
+		`(?m)^` +
+			/* crdb-v2 indicator */ `(?:.*line format: \[(?:dD)?IWEF\]yymmdd hh:mm:ss.uuuuuu goid \[chan@\]file:line.*)$`,
+	)
+	v1IndicatorRE = regexp.MustCompile(
+		`(?m)^` +
+			/* crdb-v1 indicator */ `(?:.*line format: \[(?:dD)?IWEF\]yymmdd hh:mm:ss.uuuuuu goid file:line.*)$`,
+	)
+	jsonIndicatorRE = regexp.MustCompile(
+		`(?m)^` + `(?:.*\"config\".+log format \(utf8=.+\): )json\".+$`)
+	jsonCompactIndicatorRE = regexp.MustCompile(
+		`(?m)^` + `(?:.*\"config\".+log format \(utf8=.+\): )json-compact\".+$`)
+	jsonFluentIndicatorRE = regexp.MustCompile(
+		`(?m)^` + `(?:.*\"config\".+log format \(utf8=.+\): )json-fluent\".+$`)
+	jsonFluentCompactIndicatorRE = regexp.MustCompile(
+		`(?m)^` + `(?:.*\"config\".+log format \(utf8=.+\): )json-fluent-compact\".+$`)
+)
+
+// EntryDecoder is used to decode log entries.
+type EntryDecoder interface {
+	Decode(entry *logpb.Entry) error
+}
+
+// NewEntryDecoder creates a new instance of EntryDecoder.
+// The format of the log file determines how the decoder is constructed.
+func NewEntryDecoder(in io.Reader, editMode EditSensitiveData) (EntryDecoder, error) {
+	return NewEntryDecoderWithFormat(in, editMode, "" /*format*/)
+}
+
+// NewEntryDecoderWithFormat is like NewEntryDecoder but the caller can specify the format of the log file.
+// The header lines do not need to be searched for the log entry format when 'format' is non-empty.
+func NewEntryDecoderWithFormat(
+	in io.Reader, editMode EditSensitiveData, format string,
+) (EntryDecoder, error) {
+	var d EntryDecoder
+
+	// If the log format has not been specified, get the format from the first few header lines of the log file.
+	if format == "" {
+		var read io.Reader
+		var err error
+		read, format, err = ReadFormatFromLogFile(in)
+		if err != nil {
+			return nil, err
+		}
+		in = io.MultiReader(read, in)
+	}
+	f, ok := formatParsers[format]
+	if !ok {
+		return nil, errors.Newf("unknown log file format: %s", format)
+	}
+	format = f
+
+	switch format {
+	case "v2":
+		d = &entryDecoderV2{
+			reader:          bufio.NewReader(in),
+			sensitiveEditor: getEditor(editMode),
+		}
+	case "v1":
+		decoder := &entryDecoderV1{
+			scanner:         bufio.NewScanner(in),
+			sensitiveEditor: getEditor(editMode),
+		}
+		decoder.scanner.Split(decoder.split)
+		d = decoder
+	case "proto":
+		d = &entryDecoderProto{
+			reader:          bufio.NewReader(in),
+			sensitiveEditor: getEditor(editMode),
+		}
+	case "json":
+		d = &entryDecoderJSON{
+			decoder:         json.NewDecoder(in),
+			sensitiveEditor: getEditor(editMode),
+		}
+	case "json-compact":
+		d = &entryDecoderJSON{
+			decoder:         json.NewDecoder(in),
+			sensitiveEditor: getEditor(editMode),
+			compact:         true,
+		}
+	default:
+		return nil, unsupportedFormatError()
+	}
+	return d, nil
+}
+
+// formatParsers maps the names of the log formats to the decoder able
+// to parse them.
+var formatParsers = map[string]string{
+	"crdb-proto":          "proto",
+	"crdb-v1":             "v1",
+	"crdb-v1-count":       "v1",
+	"crdb-v1-tty":         "v1",
+	"crdb-v1-tty-count":   "v1",
+	"crdb-v2":             "v2",
+	"crdb-v2-tty":         "v2",
+	"json":                "json",
+	"json-compact":        "json-compact",
+	"json-fluent":         "json",
+	"json-fluent-compact": "json-compact",
+}
+
+// ReadFormatFromLogFile attempts to read the format from the header data of
+// in. It returns the data consumed from input in the read return value.
+func ReadFormatFromLogFile(in io.Reader) (read io.Reader, format string, err error) {
+	var buf bytes.Buffer
+	rest := bufio.NewReader(in)
+	r := io.TeeReader(rest, &buf)
+	const headerBytes = 8096
+	header := make([]byte, headerBytes)
+	n, err := r.Read(header)
+	if err != nil {
+		return nil, "", err
+	}
+	header = header[:n]
+	format, err = getLogFormat(header)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "decoding format")
+	}
+	return &buf, format, nil
+}
+
+// getLogFormat retrieves the log format recorded at the top of a log.
+func getLogFormat(data []byte) (string, error) {
+	// The binary format does not use text header lines.
+	if format, ok := getProtoLogFormat(data); ok {
+		return format, nil
+	}
+
+	if m := formatRE.FindSubmatch(data); m != nil {
+		return string(m[1]), nil
+	}
+
+	// If the log format is not specified in the log, determine the format based on the line format entry.
+	if v1IndicatorRE.Match(data) {
+		return "crdb-v1", nil
+	}
+
+	if v2IndicatorRE.Match(data) {
+		return "crdb-v2", nil
+	}
+
+	if jsonIndicatorRE.Match(data) {
+		return "json", nil
+	}
+	if jsonCompactIndicatorRE.Match(data) {
+		return "json-compact", nil
+	}
+	if jsonFluentIndicatorRE.Match(data) {
+		return "json-fluent", nil
+	}
+	if jsonFluentCompactIndicatorRE.Match(data) {
+		return "json-fluent-compact", nil
+	}
+
+	// If there are no header lines at all (e.g. the file was truncated,
+	// or produced by a different tool), try to recognize the format from
+	// the entries themselves.
+	if format, ok := guessLogFormatFromEntries(data); ok {
+		return format, nil
+	}
+	return "", errors.New("failed to extract log file format from the log")
+}
+
+// guessLogFormatFromEntries attempts to determine the format of a log
+// file from the shape of its first entries. This makes it possible to
+// read directories where files were written using different formats
+// (e.g. after a logging configuration change) without requiring the
+// caller to specify the format of each file.
+//
+// The JSON fluent variants are reported as their non-fluent
+// counterparts, since they share the same decoder.
+func guessLogFormatFromEntries(data []byte) (string, bool) {
+	for _, line := range bytes.Split(data, []byte{'\n'}) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		if line[0] == '{' {
+			var fields map[string]json.RawMessage
+			if err := json.Unmarshal(line, &fields); err != nil {
+				// The line may have been cut short at the end of the header
+				// buffer. Try the next one.
+				continue
+			}
+			if _, ok := fields["timestamp"]; ok {
+				return "json", true
+			}
+			if _, ok := fields["t"]; ok {
+				return "json-compact", true
+			}
+			continue
+		}
+		// The crdb-v1 regexp also matches crdb-v2 entries, so the v2
+		// check must come first.
+		if entryREV2.Match(line) {
+			return "crdb-v2", true
+		}
+		if entryREV1.Match(line) {
+			return "crdb-v1", true
+		}
+	}
+	return "", false
+}
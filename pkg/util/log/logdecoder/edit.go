@@ -0,0 +1,138 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package logdecoder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/redact"
+)
+
+// EditSensitiveData describes how the messages in log entries should
+// be edited through the API.
+type EditSensitiveData int
+
+const (
+	// The 4 reference values below require the first bit to be
+	// set. This ensures the API is not mistakenly used with an
+	// uninitialized mode parameter.
+	confValid       = 1
+	withKeepMarkers = 2
+	withRedaction   = 4
+
+	// WithFlattenedSensitiveData is the log including sensitive data,
+	// but markers stripped.
+	WithFlattenedSensitiveData EditSensitiveData = confValid
+	// WithMarkedSensitiveData is the "raw" log with sensitive data markers included.
+	WithMarkedSensitiveData EditSensitiveData = confValid | withKeepMarkers
+	// WithoutSensitiveDataNorMarkers is the log with the sensitive data
+	// redacted, and markers stripped.
+	WithoutSensitiveDataNorMarkers EditSensitiveData = confValid | withRedaction
+	// WithoutSensitiveData is the log with the sensitive data redacted,
+	// but markers included.
+	WithoutSensitiveData EditSensitiveData = confValid | withKeepMarkers | withRedaction
+)
+
+// KeepRedactable can be used as an argument to SelectEditMode to indicate that
+// the logs should retain their sensitive data markers so that they can be
+// redacted later.
+const KeepRedactable = true
+
+// SelectEditMode returns an EditSensitiveData value that's suitable
+// for use with NewDecoder depending on client-side desired
+// "redact" and "keep redactable" flags.
+// (See the documentation for the Logs and LogFile RPCs
+// and that of the 'merge-logs' CLI command.)
+func SelectEditMode(redact, keepRedactable bool) EditSensitiveData {
+	var editMode EditSensitiveData
+	if redact {
+		editMode = editMode | withRedaction
+	}
+	if keepRedactable {
+		editMode = editMode | withKeepMarkers
+	}
+	editMode = editMode | confValid
+	return editMode
+}
+
+// redactablePackage is a piece of a decoded entry (its message or its
+// tags) subject to the edit mode of the decoder.
+type redactablePackage struct {
+	msg        []byte
+	redactable bool
+}
+
+type redactEditor func(redactablePackage) redactablePackage
+
+// getEditor returns the editor implementing the given edit mode. This
+// mirrors the editors used by the sinks in package log, which also
+// edit the tags of the entries separately.
+func getEditor(editMode EditSensitiveData) redactEditor {
+	switch editMode {
+	case WithMarkedSensitiveData:
+		return func(r redactablePackage) redactablePackage {
+			if !r.redactable {
+				r.msg = []byte(redact.EscapeBytes(r.msg))
+				r.redactable = true
+			}
+			return r
+		}
+	case WithFlattenedSensitiveData:
+		return func(r redactablePackage) redactablePackage {
+			if r.redactable {
+				r.msg = redact.RedactableBytes(r.msg).StripMarkers()
+				r.redactable = false
+			}
+			return r
+		}
+	case WithoutSensitiveData:
+		return func(r redactablePackage) redactablePackage {
+			if r.redactable {
+				r.msg = []byte(redact.RedactableBytes(r.msg).Redact())
+			} else {
+				r.msg = redact.RedactedMarker()
+				r.redactable = true
+			}
+			return r
+		}
+	case WithoutSensitiveDataNorMarkers:
+		return func(r redactablePackage) redactablePackage {
+			if r.redactable {
+				r.msg = redact.RedactableBytes(r.msg).Redact().StripMarkers()
+				r.redactable = false
+			} else {
+				r.msg = strippedMarker
+			}
+			return r
+		}
+	default:
+		panic(errors.AssertionFailedf("unrecognized mode: %v", editMode))
+	}
+}
+
+var strippedMarker = redact.RedactableBytes(redact.RedactedMarker()).StripMarkers()
+
+// editEntry applies the editor to the sensitive data of the entry.
+func editEntry(entry *logpb.Entry, editor redactEditor) {
+	if entry.Tags != "" {
+		r := editor(redactablePackage{
+			msg:        []byte(entry.Tags),
+			redactable: entry.Redactable,
+		})
+		entry.Tags = string(r.msg)
+	}
+	r := editor(redactablePackage{
+		msg:        []byte(entry.Message),
+		redactable: entry.Redactable,
+	})
+	entry.Message = string(r.msg)
+	entry.Redactable = r.redactable
+}
@@ -0,0 +1,52 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package logdecoder
+
+import (
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+)
+
+// The constants below are shared with the formatters in package log,
+// which produce the entries parsed by the decoders.
+
+// MessageTimeFormat is the format of the timestamp in log message headers of crdb formatted logs.
+const MessageTimeFormat = "060102 15:04:05.999999"
+
+// SeverityChar contains the characters representing the severities
+// in the crdb-v1 and crdb-v2 formats, from DEBUG2 to FATAL.
+const SeverityChar = "dDIWEF"
+
+// RedactableIndicator is the marker which indicates that the remainder
+// of an entry in the crdb-v1 and crdb-v2 formats is redactable.
+const RedactableIndicator = "⋮"
+
+// StructuredEntryPrefix is the prefix of the structured entries in the
+// crdb-v1 format.
+const StructuredEntryPrefix = "Structured entry: "
+
+// SeverityFromChar is the inverse of the mapping defined by
+// SeverityChar. It returns UNKNOWN if the character does not represent
+// a severity.
+func SeverityFromChar(c byte) logpb.Severity {
+	i := strings.IndexByte(SeverityChar, c)
+	if i < 0 {
+		return severity.UNKNOWN
+	}
+	sev := severity.DEBUG2 + logpb.Severity(i)
+	if sev >= severity.UNKNOWN {
+		// UNKNOWN has no character.
+		sev++
+	}
+	return sev
+}
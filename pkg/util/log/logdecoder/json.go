@@ -0,0 +1,260 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package logdecoder
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/logtags"
+)
+
+type entryDecoderJSON struct {
+	decoder         *json.Decoder
+	sensitiveEditor redactEditor
+	compact         bool
+}
+
+type jsonCommon struct {
+	Header  int    `json:"header,omitempty"`
+	Message string `json:"message"`
+	Stacks  string `json:"stacks"`
+	// Tags and Event are kept in their original form, so that the
+	// order of the tags and the fields of the event is preserved.
+	Tags  json.RawMessage `json:"tags"`
+	Event json.RawMessage `json:"event"`
+}
+
+// JSONEntry represents a JSON log entry.
+type JSONEntry struct {
+	jsonCommon
+
+	//Channel         Channel  `json:"channel,omitempty"`
+	ChannelNumeric int64  `json:"channel_numeric,omitempty"`
+	Timestamp      string `json:"timestamp,omitempty"`
+	//Severity        Severity `json:"severity,omitempty"`
+	SeverityNumeric int64  `json:"severity_numeric,omitempty"`
+	Goroutine       int64  `json:"goroutine,omitempty"`
+	File            string `json:"file,omitempty"`
+	Line            int64  `json:"line,omitempty"`
+	EntryCounter    uint64 `json:"entry_counter,omitempty"`
+	Redactable      int    `json:"redactable,omitempty"`
+	NodeID          int64  `json:"node_id,omitempty"`
+	ClusterID       string `json:"cluster_id,omitempty"`
+	Version         string `json:"version,omitempty"`
+	InstanceID      int64  `json:"instance_id,omitempty"`
+	TenantID        int64  `json:"tenant_id,omitempty"`
+}
+
+// JSONCompactEntry represents a JSON log entry in the compact format.
+type JSONCompactEntry struct {
+	jsonCommon
+
+	//Channel         Channel  `json:"C,omitempty"`
+	ChannelNumeric int64  `json:"c,omitempty"`
+	Timestamp      string `json:"t,omitempty"`
+	//Severity        Severity `json:"sev,omitempty"`
+	SeverityNumeric int64  `json:"s,omitempty"`
+	Goroutine       int64  `json:"g,omitempty"`
+	File            string `json:"f,omitempty"`
+	Line            int64  `json:"l,omitempty"`
+	EntryCounter    uint64 `json:"n,omitempty"`
+	Redactable      int    `json:"r,omitempty"`
+	NodeID          int64  `json:"N,omitempty"`
+	ClusterID       string `json:"x,omitempty"`
+	Version         string `json:"v,omitempty"`
+	InstanceID      int64  `json:"q,omitempty"`
+	TenantID        int64  `json:"T,omitempty"`
+}
+
+// populate is a method that populates fields from the source JSONEntry
+// into the `logpb.Entry`. Redactability is applied to the tags,
+// message, stacks, and event fields if it's missing.
+func (e *JSONEntry) populate(entry *logpb.Entry, d *entryDecoderJSON) (*redactablePackage, error) {
+	ts, err := fromFluent(e.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+	entry.Time = ts
+
+	entry.Goroutine = e.Goroutine
+	entry.File = e.File
+	entry.Line = e.Line
+	entry.Redactable = e.Redactable == 1
+
+	entry.ClusterID = e.ClusterID
+	entry.NodeID = formatServerID(e.NodeID)
+	entry.TenantID = formatServerID(e.TenantID)
+	entry.SQLInstanceID = formatServerID(e.InstanceID)
+	entry.Version = e.Version
+
+	if e.Header == 0 {
+		entry.Severity = logpb.Severity(e.SeverityNumeric)
+		entry.Channel = logpb.Channel(e.ChannelNumeric)
+		entry.Counter = e.EntryCounter
+	}
+
+	var entryMsg bytes.Buffer
+	if isJSONValue(e.Event) {
+		if err := json.Compact(&entryMsg, e.Event); err != nil {
+			return nil, err
+		}
+		entry.StructuredStart = 0
+		entry.StructuredEnd = uint32(entryMsg.Len())
+	} else {
+		entryMsg.Write([]byte(e.Message))
+	}
+
+	if isJSONValue(e.Tags) {
+		tags, err := decodeJSONTags(e.Tags)
+		if err != nil {
+			return nil, err
+		}
+		r := redactablePackage{
+			msg:        []byte(tags),
+			redactable: entry.Redactable,
+		}
+		r = d.sensitiveEditor(r)
+		entry.Tags = string(r.msg)
+	}
+
+	if e.Stacks != "" {
+		entry.StackTraceStart = uint32(entryMsg.Len()) + 1
+		entryMsg.Write([]byte("\nstack trace:\n"))
+		entryMsg.Write([]byte(e.Stacks))
+	}
+
+	return &redactablePackage{
+		msg:        entryMsg.Bytes(),
+		redactable: entry.Redactable,
+	}, nil
+}
+
+// isJSONValue returns whether the given field was present in the
+// entry with a non-null value.
+func isJSONValue(v json.RawMessage) bool {
+	return len(v) > 0 && !bytes.Equal(v, []byte("null"))
+}
+
+// decodeJSONTags converts the tags of a JSON entry to the
+// comma-separated format of logpb.Entry. The tags are kept in the
+// order in which they were emitted.
+func decodeJSONTags(data json.RawMessage) (string, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if tok, err := dec.Token(); err != nil {
+		return "", err
+	} else if tok != json.Delim('{') {
+		return "", errors.Newf("expected tags object, found %v", tok)
+	}
+	var t *logtags.Buffer
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return "", err
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return "", errors.Newf("expected tag name, found %v", tok)
+		}
+		var val interface{}
+		if err := dec.Decode(&val); err != nil {
+			return "", err
+		}
+		t = t.Add(key, val)
+	}
+	var s strings.Builder
+	t.FormatToString(&s)
+	return s.String(), nil
+}
+
+// formatServerID converts a numeric server identifier to the string
+// form used in logpb.Entry. Zero values indicate that the identifier
+// was not reported.
+func formatServerID(id int64) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.FormatInt(id, 10)
+}
+
+func (e *JSONCompactEntry) toEntry(entry *JSONEntry) {
+	entry.jsonCommon = e.jsonCommon
+	entry.ChannelNumeric = e.ChannelNumeric
+	entry.Timestamp = e.Timestamp
+	entry.SeverityNumeric = e.SeverityNumeric
+	entry.Goroutine = e.Goroutine
+	entry.File = e.File
+	entry.Line = e.Line
+	entry.EntryCounter = e.EntryCounter
+	entry.Redactable = e.Redactable
+	entry.NodeID = e.NodeID
+	entry.ClusterID = e.ClusterID
+	entry.Version = e.Version
+	entry.InstanceID = e.InstanceID
+	entry.TenantID = e.TenantID
+}
+
+// Decode decodes the next log entry into the provided protobuf message.
+func (d *entryDecoderJSON) Decode(entry *logpb.Entry) error {
+	var rp *redactablePackage
+	var e JSONEntry
+	if d.compact {
+		var compact JSONCompactEntry
+		err := d.decoder.Decode(&compact)
+		if err != nil {
+			return err
+		}
+		compact.toEntry(&e)
+	} else {
+		err := d.decoder.Decode(&e)
+		if err != nil {
+			return err
+		}
+	}
+	rp, err := e.populate(entry, d)
+	if err != nil {
+		return err
+	}
+
+	r := d.sensitiveEditor(*rp)
+	entry.Message = string(r.msg)
+	entry.Redactable = r.redactable
+
+	return nil
+}
+
+// fromFluent parses a fluentbit timestamp format into nanoseconds since
+// the epoch. The fluentbit format is a string consisting of two
+// concatenanted integers joined by a `.`. The left-hand side is the
+// number of seconds since the epich, the right hand side is the
+// additional number of nanoseconds of precision.
+//
+// For example: `"1136214245.654321000"` parses into `1136214245654321000`.
+func fromFluent(timestamp string) (int64, error) {
+	parts := strings.Split(timestamp, ".")
+	if len(parts) != 2 {
+		return 0, errors.New("bad timestamp format")
+	}
+	left, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	right, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return left*1000000000 + right, nil
+}
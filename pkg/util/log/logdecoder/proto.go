@@ -0,0 +1,83 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package logdecoder
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+	"github.com/cockroachdb/errors"
+)
+
+// maxProtoEntrySize bounds the size of the entries accepted by the
+// decoder, so that a corrupted length prefix does not cause an
+// arbitrarily large allocation.
+const maxProtoEntrySize = 64 << 20
+
+// protoFormatPrefix is the message of the header entry that
+// identifies the format of a log file.
+const protoFormatPrefix = "log format (utf8=✓): "
+
+// getProtoLogFormat attempts to decode the header entries of a file
+// in the crdb-proto format, and returns the format recorded therein.
+func getProtoLogFormat(data []byte) (string, bool) {
+	for len(data) > 0 {
+		size, n := binary.Uvarint(data)
+		if n <= 0 || size > uint64(len(data)-n) {
+			return "", false
+		}
+		var e logpb.Entry
+		if err := e.Unmarshal(data[n : n+int(size)]); err != nil {
+			return "", false
+		}
+		if e.Tags != "config" {
+			return "", false
+		}
+		if strings.HasPrefix(e.Message, protoFormatPrefix) {
+			return strings.TrimPrefix(e.Message, protoFormatPrefix), true
+		}
+		data = data[n+int(size):]
+	}
+	return "", false
+}
+
+// entryDecoderProto decodes entries in the crdb-proto format.
+type entryDecoderProto struct {
+	reader          *bufio.Reader
+	sensitiveEditor redactEditor
+}
+
+// Decode decodes the next log entry into the provided protobuf message.
+func (d *entryDecoderProto) Decode(entry *logpb.Entry) error {
+	size, err := binary.ReadUvarint(d.reader)
+	if err != nil {
+		return err
+	}
+	if size > maxProtoEntrySize {
+		return errors.Newf("log entry too large: %d bytes", size)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(d.reader, data); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	*entry = logpb.Entry{}
+	if err := entry.Unmarshal(data); err != nil {
+		return errors.Wrap(err, "decoding log entry")
+	}
+	editEntry(entry, d.sensitiveEditor)
+	return nil
+}
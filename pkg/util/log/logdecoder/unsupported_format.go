@@ -0,0 +1,32 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+//go:build !js
+// +build !js
+
+package logdecoder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/build"
+	"github.com/cockroachdb/errors"
+)
+
+// unsupportedFormatError is returned for the log formats which have no
+// decoder.
+func unsupportedFormatError() error {
+	// The unimplemented.WithIssue function is not used here because it results in circular dependency issues.
+	return errors.WithTelemetry(
+		errors.UnimplementedError(
+			errors.IssueLink{IssueURL: build.MakeIssueURL(66684)},
+			"unable to decode this log file format",
+		),
+		"#66684",
+	)
+}
@@ -0,0 +1,23 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package logdecoder
+
+import "github.com/cockroachdb/errors"
+
+// unsupportedFormatError does not use build.MakeIssueURL in js builds
+// (i.e. WebAssembly running in a browser), since package build depends
+// on the process environment. There is no telemetry to report to either.
+func unsupportedFormatError() error {
+	return errors.UnimplementedError(
+		errors.IssueLink{IssueURL: "https://github.com/cockroachdb/cockroach/issues/66684"},
+		"unable to decode this log file format",
+	)
+}
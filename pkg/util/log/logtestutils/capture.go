@@ -0,0 +1,57 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package logtestutils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+)
+
+// RequireLogContains fails the test unless an entry with a severity of
+// at least sev and a message matching the regular expression re was
+// logged to the channel ch. The channel must be captured by the scope,
+// which is created with log.ScopeWithCapture.
+func RequireLogContains(
+	t testing.TB, sc *log.TestLogScope, ch log.Channel, sev log.Severity, re string,
+) {
+	t.Helper()
+	if len(sc.CapturedMatching(ch, sev, regexp.MustCompile(re))) == 0 {
+		t.Fatalf("no %s+ entry matching %q on channel %s; captured:\n%s",
+			sev, re, ch, formatEntries(sc.Captured(ch)))
+	}
+}
+
+// RequireLogNotContains fails the test if an entry with a severity of
+// at least sev and a message matching the regular expression re was
+// logged to the channel ch. The channel must be captured by the scope,
+// which is created with log.ScopeWithCapture.
+func RequireLogNotContains(
+	t testing.TB, sc *log.TestLogScope, ch log.Channel, sev log.Severity, re string,
+) {
+	t.Helper()
+	if matches := sc.CapturedMatching(ch, sev, regexp.MustCompile(re)); len(matches) > 0 {
+		t.Fatalf("unexpected %s+ entries matching %q on channel %s:\n%s",
+			sev, re, ch, formatEntries(matches))
+	}
+}
+
+func formatEntries(entries []logpb.Entry) string {
+	var sb strings.Builder
+	for _, e := range entries {
+		fmt.Fprintf(&sb, "%s %s\n", e.Severity, e.Message)
+	}
+	return sb.String()
+}
@@ -0,0 +1,83 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package logtestutils
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// SafeStringerCase is a value checked by CheckSafeStringers.
+type SafeStringerCase struct {
+	// Value is the value passed as argument to a logging call. Its type
+	// must have a registered log.SafeStringer.
+	Value interface{}
+	// Sensitive lists substrings of the value which must not remain in
+	// the log entry once redacted.
+	Sensitive []string
+	// Safe lists substrings of the value which must remain in the log
+	// entry once redacted.
+	Safe []string
+}
+
+// CheckSafeStringers logs each value of the cases and asserts that,
+// once the log entry is redacted, none of the sensitive substrings
+// escape and all the safe ones remain.
+func CheckSafeStringers(t *testing.T, cases ...SafeStringerCase) {
+	t.Helper()
+	ctx := context.Background()
+	const prefix = "safe stringer check"
+	sub, cleanup := log.Tail(ctx, log.TailOptions{
+		Pattern:  regexp.MustCompile(`^` + prefix),
+		EditMode: log.WithoutSensitiveDataNorMarkers,
+	})
+	defer cleanup()
+
+	for i, c := range cases {
+		if !log.HasSafeStringer(c.Value) {
+			t.Errorf("%d: no SafeStringer registered for %T", i, c.Value)
+			continue
+		}
+		// Ensure the test case is meaningful: the sensitive data must
+		// be visible without redaction.
+		unredacted := fmt.Sprint(c.Value)
+		for _, s := range c.Sensitive {
+			if !strings.Contains(unredacted, s) {
+				t.Errorf("%d: %q not found in %q", i, s, unredacted)
+			}
+		}
+
+		log.Infof(ctx, prefix+" %d: %v", i, c.Value)
+		var msg string
+		select {
+		case e := <-sub.Entries():
+			msg = e.Message
+		case <-time.After(10 * time.Second):
+			t.Fatalf("%d: timed out waiting for log entry", i)
+		}
+		for _, s := range c.Sensitive {
+			if strings.Contains(msg, s) {
+				t.Errorf("%d: %T: sensitive data %q escaped redaction: %q", i, c.Value, s, msg)
+			}
+		}
+		for _, s := range c.Safe {
+			if !strings.Contains(msg, s) {
+				t.Errorf("%d: %T: safe data %q redacted: %q", i, c.Value, s, msg)
+			}
+		}
+	}
+}
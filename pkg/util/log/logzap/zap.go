@@ -0,0 +1,158 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package logzap provides an adapter that forwards the log entries
+// emitted via go.uber.org/zap, e.g. by third-party libraries, to the
+// CockroachDB logging channels. This way these entries are subject to
+// the logging configuration, including redaction and sink selection.
+package logzap
+
+import (
+	"context"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+	"github.com/cockroachdb/logtags"
+	"go.uber.org/zap/zapcore"
+)
+
+// NewCore returns a zapcore.Core which forwards log entries to the
+// given logging channel. The log tags in the provided context are
+// included in every entry. For example:
+//
+//	logger := zap.New(logzap.NewCore(ctx, channel.DEV))
+//
+// The zap levels are mapped to logging severities as follows:
+//
+//   - debug entries are reported as INFO, but only when verbose logging
+//     at level 2 is enabled for the call site (e.g. via --vmodule).
+//   - info entries are reported as INFO.
+//   - warn entries are reported as WARNING.
+//   - error, dpanic and panic entries are reported as ERROR.
+//   - fatal entries are reported as FATAL.
+//
+// The message and the field values are considered unsafe for
+// reporting, and are enclosed in redaction markers, except for values
+// of types that are always safe, e.g. integers. The field names and
+// the name of the zap logger, if any, are considered safe.
+func NewCore(ctx context.Context, ch log.Channel) zapcore.Core {
+	return &core{ctx: ctx, ch: ch}
+}
+
+type core struct {
+	ctx context.Context
+	ch  log.Channel
+	// fields are the fields added via With().
+	fields []zapcore.Field
+}
+
+var _ zapcore.Core = (*core)(nil)
+
+// Enabled implements the zapcore.LevelEnabler interface.
+//
+// Debug entries are filtered in Check() instead, since their
+// verbosity depends on the call site.
+func (c *core) Enabled(zapcore.Level) bool { return true }
+
+// With implements the zapcore.Core interface.
+func (c *core) With(fields []zapcore.Field) zapcore.Core {
+	newFields := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	newFields = append(newFields, c.fields...)
+	newFields = append(newFields, fields...)
+	return &core{ctx: c.ctx, ch: c.ch, fields: newFields}
+}
+
+// Check implements the zapcore.Core interface.
+func (c *core) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if ent.Level <= zapcore.DebugLevel && !log.VDepth(2, callerDepth()) {
+		return ce
+	}
+	return ce.AddCore(ent, c)
+}
+
+// Write implements the zapcore.Core interface.
+func (c *core) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	ctx := c.ctx
+	if ent.LoggerName != "" {
+		ctx = logtags.AddTag(ctx, ent.LoggerName, nil)
+	}
+
+	var format strings.Builder
+	args := make([]interface{}, 0, 1+len(c.fields)+len(fields))
+	format.WriteString("%s")
+	args = append(args, ent.Message)
+	addFields := func(fields []zapcore.Field) {
+		for _, f := range fields {
+			enc := zapcore.NewMapObjectEncoder()
+			f.AddTo(enc)
+			keys := make([]string, 0, len(enc.Fields))
+			for k := range enc.Fields {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				format.WriteByte(' ')
+				format.WriteString(strings.ReplaceAll(k, "%", "%%"))
+				format.WriteString("=%v")
+				args = append(args, enc.Fields[k])
+			}
+		}
+	}
+	addFields(c.fields)
+	addFields(fields)
+
+	logfDepth(ctx, callerDepth(), c.ch, severityForLevel(ent.Level), format.String(), args...)
+	return nil
+}
+
+// Sync implements the zapcore.Core interface.
+func (c *core) Sync() error { return nil }
+
+// severityForLevel maps a zap level to a logging severity.
+func severityForLevel(lvl zapcore.Level) log.Severity {
+	switch {
+	case lvl >= zapcore.FatalLevel:
+		return severity.FATAL
+	case lvl >= zapcore.ErrorLevel:
+		return severity.ERROR
+	case lvl == zapcore.WarnLevel:
+		return severity.WARNING
+	default:
+		return severity.INFO
+	}
+}
+
+// zapPackagePrefix is the prefix of the function names in the zap
+// packages.
+const zapPackagePrefix = "go.uber.org/zap"
+
+// callerDepth returns the depth of the first caller outside of the
+// zap packages, relative to the function calling callerDepth. This
+// is the function that called the zap logger.
+func callerDepth() int {
+	var pcs [16]uintptr
+	// Skip runtime.Callers and callerDepth itself.
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for depth := 0; ; depth++ {
+		f, more := frames.Next()
+		if depth > 0 && !strings.HasPrefix(f.Function, zapPackagePrefix) {
+			return depth
+		}
+		if !more {
+			// The call stack is deeper than expected. Report the
+			// outermost frame we know about.
+			return depth
+		}
+	}
+}
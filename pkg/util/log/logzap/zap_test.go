@@ -0,0 +1,78 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package logzap_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/channel"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logzap"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type opsInterceptor struct {
+	syncutil.Mutex
+	entries []logpb.Entry
+}
+
+func (i *opsInterceptor) Intercept(b []byte) {
+	var e logpb.Entry
+	if err := json.Unmarshal(b, &e); err != nil {
+		panic(err)
+	}
+	if e.Channel != channel.OPS {
+		return
+	}
+	i.Lock()
+	defer i.Unlock()
+	i.entries = append(i.entries, e)
+}
+
+func TestZapCore(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	var interceptor opsInterceptor
+	defer log.InterceptWith(ctx, &interceptor)()
+
+	logger := zap.New(logzap.NewCore(ctx, channel.OPS)).With(zap.String("user", "bob"))
+	logger.Info("hello", zap.Int("count", 3))
+	logger.Debug("not logged without vmodule")
+	logger.Sugar().Warnw("hello again", "key", "value")
+	logger.Error("oops")
+
+	interceptor.Lock()
+	defer interceptor.Unlock()
+	require.Len(t, interceptor.entries, 3)
+	for i, exp := range []struct {
+		sev logpb.Severity
+		msg string
+	}{
+		{severity.INFO, "‹hello› user=‹bob› count=3"},
+		{severity.WARNING, "‹hello again› user=‹bob› key=‹value›"},
+		{severity.ERROR, "‹oops› user=‹bob›"},
+	} {
+		e := interceptor.entries[i]
+		require.Equal(t, exp.sev, e.Severity)
+		require.Equal(t, exp.msg, e.Message)
+		// The entries are attributed to the caller of the zap logger.
+		require.Equal(t, "util/log/logzap/zap_test.go", e.File)
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+)
+
+// EntryProcessor is a function that transforms the log entries
+// emitted to a sink, before they are formatted. It can be used e.g.
+// to inject additional tags (pod name, region, tenant ID, etc.) into
+// every entry.
+//
+// The context is that of the logging call. The processor may modify
+// the entry in-place or return another one. If it returns nil, the
+// entry is not emitted to the sink.
+//
+// Processors are invoked synchronously on every log call and must not
+// log themselves.
+type EntryProcessor func(ctx context.Context, entry *logpb.Entry) *logpb.Entry
+
+var processorRegistry struct {
+	syncutil.RWMutex
+	m map[string]EntryProcessor
+}
+
+// RegisterProcessor makes an entry processor available under the
+// given name. Processors are enabled per sink via the `processors`
+// attribute in the logging configuration.
+//
+// This is meant to be called during initialization, before the
+// logging configuration is applied.
+func RegisterProcessor(name string, fn EntryProcessor) {
+	processorRegistry.Lock()
+	defer processorRegistry.Unlock()
+	if _, ok := processorRegistry.m[name]; ok {
+		panic(errors.AssertionFailedf("log entry processor %q already registered", name))
+	}
+	if processorRegistry.m == nil {
+		processorRegistry.m = make(map[string]EntryProcessor)
+	}
+	processorRegistry.m[name] = fn
+}
+
+// lookupProcessors resolves processor names into the registered
+// processors.
+func lookupProcessors(names []string) ([]EntryProcessor, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	processorRegistry.RLock()
+	defer processorRegistry.RUnlock()
+	res := make([]EntryProcessor, len(names))
+	for i, name := range names {
+		fn, ok := processorRegistry.m[name]
+		if !ok {
+			known := make([]string, 0, len(processorRegistry.m))
+			for k := range processorRegistry.m {
+				known = append(known, k)
+			}
+			sort.Strings(known)
+			return nil, errors.WithHintf(
+				errors.Newf("unknown log entry processor: %q", name),
+				"Registered processors: %s", strings.Join(known, ", "))
+		}
+		res[i] = fn
+	}
+	return res, nil
+}
+
+// processEntry runs the sink's processors over the entry. The second
+// return value is false if a processor dropped the entry.
+func (l *sinkInfo) processEntry(ctx context.Context, entry logEntry) (logEntry, bool) {
+	if len(l.processors) == 0 {
+		return entry, true
+	}
+	orig := entry.convertToLegacy()
+	cur := orig
+	res := &cur
+	for _, fn := range l.processors {
+		if res = fn(ctx, res); res == nil {
+			return entry, false
+		}
+	}
+	return entry.updateFromLegacy(&orig, res), true
+}
+
+// updateFromLegacy applies to e the changes that were made to orig,
+// its legacy representation, to produce res.
+func (e logEntry) updateFromLegacy(orig, res *logpb.Entry) logEntry {
+	e.sev = res.Severity
+	e.ch = res.Channel
+	e.ts = res.Time
+	e.file = res.File
+	e.line = int(res.Line)
+	e.gid = res.Goroutine
+	e.clusterID = res.ClusterID
+	e.nodeID = res.NodeID
+	e.tenantID = res.TenantID
+	e.sqlInstanceID = res.SQLInstanceID
+	e.version = res.Version
+	if res.Tags != orig.Tags {
+		e.payload.tags = parseLegacyTags(res.Tags)
+	}
+	if res.Message != orig.Message || res.Redactable != orig.Redactable ||
+		res.StructuredStart != orig.StructuredStart || res.StructuredEnd != orig.StructuredEnd ||
+		res.StackTraceStart != orig.StackTraceStart {
+		msg := res.Message
+		e.stacks = nil
+		if res.StackTraceStart > 0 && int(res.StackTraceStart) <= len(msg) {
+			e.stacks = []byte(msg[res.StackTraceStart:])
+			msg = msg[:res.StackTraceStart-1]
+		}
+		e.structured = false
+		if res.StructuredEnd > res.StructuredStart && int(res.StructuredEnd) <= len(msg) {
+			// Strip the decoration added by convertToLegacy().
+			e.structured = true
+			msg = strings.TrimSuffix(strings.TrimPrefix(
+				msg[res.StructuredStart:res.StructuredEnd], "{"), "}")
+		}
+		e.payload.message = msg
+		e.payload.redactable = res.Redactable
+	}
+	return e
+}
+
+// parseLegacyTags converts tags in the comma-separated format produced
+// by formatToBuffer() back to formattableTags. Tags without a '='
+// sign are considered to have no value.
+func parseLegacyTags(s string) (res formattableTags) {
+	if s == "" {
+		return nil
+	}
+	for _, t := range strings.Split(s, ",") {
+		key, val := t, ""
+		if i := strings.IndexByte(t, '='); i >= 0 {
+			key, val = t[:i], t[i+1:]
+		}
+		res = escapeNulBytes(res, key)
+		res = append(res, 0)
+		res = escapeNulBytes(res, val)
+		res = append(res, 0)
+	}
+	return res
+}
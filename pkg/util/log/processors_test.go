@@ -0,0 +1,114 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log/channel"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logconfig"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+	"github.com/cockroachdb/logtags"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	RegisterProcessor("test-region", func(ctx context.Context, e *logpb.Entry) *logpb.Entry {
+		if e.Tags != "" {
+			e.Tags += ","
+		}
+		e.Tags += "region=us-east1"
+		return e
+	})
+	RegisterProcessor("test-drop", func(ctx context.Context, e *logpb.Entry) *logpb.Entry {
+		if strings.Contains(e.Message, "drop me") {
+			return nil
+		}
+		return e
+	})
+}
+
+func TestProcessors(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	sc := ScopeWithoutShowLogs(t)
+	defer sc.Close(t)
+
+	config := logconfig.DefaultConfig()
+	config.Sinks.FileGroups = map[string]*logconfig.FileSinkConfig{
+		"test": {
+			FileDefaults: logconfig.FileDefaults{
+				CommonSinkConfig: logconfig.CommonSinkConfig{
+					Processors: []string{"test-drop", "test-region"},
+				},
+			},
+			Channels: logconfig.SelectChannels(channel.DEV),
+		},
+	}
+	config.CaptureFd2.Enable = false
+	require.NoError(t, config.Validate(&sc.logDir))
+	TestingResetActive()
+	cleanupFn, err := ApplyConfig(config)
+	require.NoError(t, err)
+	defer cleanupFn()
+
+	ctx := logtags.AddTag(context.Background(), "n", 1)
+	Infof(ctx, "hello")
+	Infof(ctx, "please drop me")
+	Flush()
+
+	contents, err := os.ReadFile(getDebugLogFileName(t))
+	require.NoError(t, err)
+	require.Contains(t, string(contents), "[n1,region=us-east1] 1 hello")
+	require.NotContains(t, string(contents), "drop me")
+
+	// The applied configuration reports the processors.
+	require.Equal(t, []string{"test-drop", "test-region"},
+		debugLog.sinkInfos[debugLog.getFileSinkIndex()].describeAppliedConfig().Processors)
+}
+
+func TestUnknownProcessor(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	_, err := lookupProcessors([]string{"test-region", "unknown"})
+	require.EqualError(t, err, `unknown log entry processor: "unknown"`)
+}
+
+func TestUpdateFromLegacy(t *testing.T) {
+	ctx := logtags.AddTag(context.Background(), "n", 1)
+	ctx = logtags.AddTag(ctx, "client", "foo")
+	entry := makeUnstructuredEntry(ctx, severity.INFO, channel.DEV, 0,
+		true /* redactable */, "hello %s", "world")
+	entry.stacks = []byte("stack")
+
+	// An identity transformation preserves the entry.
+	orig := entry.convertToLegacy()
+	res := orig
+	require.Equal(t, entry, entry.updateFromLegacy(&orig, &res))
+
+	// Changes to the legacy entry are reflected.
+	res.Severity = severity.WARNING
+	res.Tags += ",region=us-east1"
+	res.Message = strings.Replace(res.Message, "hello", "hi", 1)
+	res.StackTraceStart = uint32(len("hi ‹world›")) + 1
+	updated := entry.updateFromLegacy(&orig, &res)
+	require.Equal(t, severity.WARNING, updated.sev)
+	require.Equal(t, "hi ‹world›", updated.payload.message)
+	require.Equal(t, "stack", string(updated.stacks))
+	var buf buffer
+	updated.payload.tags.formatToBuffer(&buf)
+	require.Equal(t, "n1,client=foo,region=us-east1", buf.String())
+}
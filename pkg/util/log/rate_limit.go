@@ -0,0 +1,148 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/caller"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logconfig"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"golang.org/x/time/rate"
+)
+
+// callSite identifies a location in the source code where log
+// entries are emitted, on a given channel.
+type callSite struct {
+	ch   Channel
+	file string
+	line int
+}
+
+// rateLimiter caps the rate of the entries emitted from each call
+// site, using one token bucket per call site. The limits are
+// configured per channel in ApplyConfig().
+type rateLimiter struct {
+	// perChannel contains the limit for each channel. A nil value
+	// indicates that the channel is not rate limited.
+	perChannel [logpb.Channel_CHANNEL_MAX]*logconfig.RateLimit
+
+	// buckets maps each callSite to its *rate.Limiter.
+	buckets sync.Map
+
+	// every maps each callSite to the *util.EveryN used by the
+	// *fEvery() APIs.
+	every sync.Map
+}
+
+// applyConfig configures the rate limiter from the logging
+// configuration. The configuration is expected to be validated
+// already.
+func (r *rateLimiter) applyConfig(config logconfig.RateLimitConfig) {
+	for chi := range r.perChannel {
+		r.perChannel[chi] = config[Channel(chi).String()]
+	}
+	// The maps are cleared in place, as they can be accessed
+	// concurrently by the logging calls.
+	clearMap(&r.buckets)
+	clearMap(&r.every)
+}
+
+// clearMap removes all the entries from the given map.
+func clearMap(m *sync.Map) {
+	m.Range(func(key, _ interface{}) bool {
+		m.Delete(key)
+		return true
+	})
+}
+
+// describeAppliedConfig reports the configuration that was applied.
+func (r *rateLimiter) describeAppliedConfig() (res logconfig.RateLimitConfig) {
+	for chi, rl := range r.perChannel {
+		if rl == nil {
+			continue
+		}
+		if res == nil {
+			res = make(logconfig.RateLimitConfig)
+		}
+		res[Channel(chi).String()] = rl
+	}
+	return res
+}
+
+// allow returns true if an entry at the given severity emitted from
+// the given file and line can be output at the given time.
+func (r *rateLimiter) allow(sev Severity, ch Channel, file string, line int, now int64) bool {
+	if sev >= severity.FATAL {
+		return true
+	}
+	rl := r.perChannel[ch]
+	if rl == nil {
+		return true
+	}
+	key := callSite{ch: ch, file: file, line: line}
+	b, ok := r.buckets.Load(key)
+	if !ok {
+		b, _ = r.buckets.LoadOrStore(key, rate.NewLimiter(rate.Limit(rl.EntriesPerSecond), *rl.Burst))
+	}
+	return b.(*rate.Limiter).AllowN(timeutil.Unix(0, now), 1)
+}
+
+// logfEveryDepth emits an entry like logfDepth, if no entry was
+// emitted from the same call site and channel in the last 'every'
+// duration. Like EveryN.ShouldLog(), it always emits the entry when
+// high verbosity is enabled for the caller.
+func logfEveryDepth(
+	ctx context.Context,
+	depth int,
+	sev Severity,
+	ch Channel,
+	every time.Duration,
+	format string,
+	args ...interface{},
+) {
+	if !VDepth(2 /* level */, depth+1) {
+		file, line, _ := caller.Lookup(depth + 1)
+		key := callSite{ch: ch, file: file, line: line}
+		e, ok := logging.rateLimiter.every.Load(key)
+		if !ok {
+			e, _ = logging.rateLimiter.every.LoadOrStore(key, &util.EveryN{N: every})
+		}
+		en := e.(*util.EveryN)
+		// The call site may pass a different duration every time; the
+		// most recent one applies.
+		en.Lock()
+		en.N = every
+		en.Unlock()
+		if !en.ShouldProcess(timeutil.Now()) {
+			return
+		}
+	}
+	logfDepth(ctx, depth+1, sev, ch, format, args...)
+}
+
+// recordRateLimited accounts for an entry discarded by the rate
+// limiter in the health of the sinks that would have output it.
+func (l *loggerT) recordRateLimited(entry *logEntry) {
+	sevOverride := logging.severityOverrides.get(entry.ch)
+	for _, s := range l.sinkInfos {
+		if entry.sev < s.thresholdFor(entry.ch, sevOverride) || !s.sink.active() ||
+			!s.acceptsTenant(entry.tenantID) || !s.acceptsEntryKind(entry.ch, entry.structured) {
+			continue
+		}
+		s.health.recordDrops(1)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log/channel"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logconfig"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimit(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer ScopeWithoutShowLogs(t).Close(t)
+	defer logging.rateLimiter.applyConfig(nil)
+
+	ctx := context.Background()
+	defer capture()()
+
+	burst := 2
+	logging.rateLimiter.applyConfig(logconfig.RateLimitConfig{
+		"DEV": {EntriesPerSecond: 0.001, Burst: &burst},
+	})
+
+	for i := 0; i < 5; i++ {
+		Infof(ctx, "noisy")
+	}
+	// A different call site has its own token bucket.
+	Infof(ctx, "quiet")
+	// Fatal entries are never rate limited.
+	require.True(t, logging.rateLimiter.allow(severity.FATAL, channel.DEV, "file.go", 1, 0))
+
+	cont := contents()
+	require.Equal(t, burst, strings.Count(cont, "noisy"))
+	require.Equal(t, 1, strings.Count(cont, "quiet"))
+
+	// The discarded entries are accounted for in the health of the sink.
+	fileSinkInfo := debugLog.sinkInfos[debugLog.getFileSinkIndex()]
+	require.Equal(t, int64(5-burst), atomic.LoadInt64(&fileSinkInfo.health.droppedEntries))
+
+	// Without a configured limit, all entries are emitted.
+	resetCaptured()
+	logging.rateLimiter.applyConfig(nil)
+	for i := 0; i < 5; i++ {
+		Infof(ctx, "noisy")
+	}
+	require.Equal(t, 5, strings.Count(contents(), "noisy"))
+}
+
+func TestLogfEvery(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer ScopeWithoutShowLogs(t).Close(t)
+
+	ctx := context.Background()
+	defer capture()()
+
+	for i := 0; i < 5; i++ {
+		WarningfEvery(ctx, time.Hour, "retrying %d", i)
+	}
+	cont := contents()
+	require.Equal(t, 1, strings.Count(cont, "retrying 0"))
+	require.Equal(t, 0, strings.Count(cont, "retrying 1"))
+
+	// A call site keeps a single state when its duration changes, and
+	// the most recent duration applies.
+	resetCaptured()
+	numStates := func() (n int) {
+		logging.rateLimiter.every.Range(func(_, _ interface{}) bool {
+			n++
+			return true
+		})
+		return n
+	}
+	before := numStates()
+	for i, every := range []time.Duration{time.Hour, time.Minute, 0, time.Hour} {
+		WarningfEvery(ctx, every, "every %d", i)
+	}
+	cont = contents()
+	require.Equal(t, 2, strings.Count(cont, "every "))
+	require.Equal(t, 1, strings.Count(cont, "every 2"))
+	require.Equal(t, before+1, numStates())
+
+	// With a zero duration, every entry is emitted.
+	resetCaptured()
+	for i := 0; i < 3; i++ {
+		ErrorfEvery(ctx, 0, "failing %d", i)
+	}
+	require.Equal(t, 3, strings.Count(contents(), "failing "))
+}
@@ -0,0 +1,129 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"encoding/binary"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/cli/exit"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// RecentEntriesPerChannel is the number of entries retained in memory
+// per channel by the recent entries sink.
+const RecentEntriesPerChannel = 100
+
+// RecentEntriesSeverity is the minimum severity of the entries
+// retained by the recent entries sink.
+const RecentEntriesSeverity = severity.WARNING
+
+// RecentEntries returns the entries retained in memory with at least
+// the given severity, ordered by timestamp. The last
+// RecentEntriesPerChannel entries of severity RecentEntriesSeverity or
+// above emitted on each channel are retained, regardless of the
+// logging configuration, so that they remain available when the file
+// sinks are misconfigured or the disk is full.
+func RecentEntries(sev Severity, editMode EditSensitiveData) []logpb.Entry {
+	entries := logging.recent.entries(sev)
+	editor := getEditor(editMode)
+	for i := range entries {
+		editEntry(&entries[i], editor)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Time < entries[j].Time
+	})
+	return entries
+}
+
+func (l *loggingT) newRecentSinkInfo() *sinkInfo {
+	si := &sinkInfo{
+		sink:       &l.recent,
+		editor:     getEditor(WithMarkedSensitiveData),
+		formatter:  formatCrdbProto{},
+		redact:     false, // RecentEntries() applies the edit mode
+		redactable: true,  // keep redaction markers
+		// The recent entries are retained regardless of filtering.
+		ignoreSeverityOverrides: true,
+	}
+	si.threshold.setAll(RecentEntriesSeverity)
+	return si
+}
+
+// recentSink retains the last RecentEntriesPerChannel entries emitted
+// on each channel, in a ring buffer per channel. The entries are
+// received in the crdb-proto format.
+type recentSink struct {
+	mu struct {
+		syncutil.Mutex
+		rings [logpb.Channel_CHANNEL_MAX]recentRing
+	}
+}
+
+// recentRing is a fixed-size ring buffer of entries.
+type recentRing struct {
+	entries []logpb.Entry
+	// next is the position of the entry to overwrite once the ring is
+	// full.
+	next int
+}
+
+func (r *recentRing) add(entry logpb.Entry) {
+	if len(r.entries) < RecentEntriesPerChannel {
+		r.entries = append(r.entries, entry)
+		return
+	}
+	r.entries[r.next] = entry
+	r.next = (r.next + 1) % len(r.entries)
+}
+
+func (s *recentSink) entries(sev Severity) []logpb.Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var entries []logpb.Entry
+	for i := range s.mu.rings {
+		r := &s.mu.rings[i]
+		for j := range r.entries {
+			// Start with the oldest entry.
+			e := r.entries[(r.next+j)%len(r.entries)]
+			if e.Severity >= sev {
+				entries = append(entries, e)
+			}
+		}
+	}
+	return entries
+}
+
+func (s *recentSink) active() bool { return true }
+
+func (s *recentSink) output(b []byte, _ sinkOutputOptions) error {
+	size, n := binary.Uvarint(b)
+	if n <= 0 || size > uint64(len(b)-n) {
+		// formatCrdbProto always produces a valid length prefix.
+		return nil
+	}
+	var entry logpb.Entry
+	if err := entry.Unmarshal(b[n : n+int(size)]); err != nil {
+		return err
+	}
+	if entry.Channel < 0 || entry.Channel >= logpb.Channel_CHANNEL_MAX {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mu.rings[entry.Channel].add(entry)
+	return nil
+}
+
+func (s *recentSink) attachHints(stacks []byte) []byte { return stacks }
+func (s *recentSink) exitCode() exit.Code              { return exit.UnspecifiedError() }
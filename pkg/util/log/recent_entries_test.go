@@ -0,0 +1,57 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log/channel"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecentEntries(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer Scope(t).Close(t)
+
+	ctx := context.Background()
+
+	Ops.Infof(ctx, "recent: too low")
+	Ops.Warningf(ctx, "recent: %s", "secret")
+	for i := 0; i < RecentEntriesPerChannel+1; i++ {
+		Health.Errorf(ctx, "recent: overflow %d", i)
+	}
+
+	// The entries emitted by other tests are ignored.
+	var messages []string
+	for _, e := range RecentEntries(RecentEntriesSeverity, WithoutSensitiveData) {
+		if strings.HasPrefix(e.Message, "recent: ") {
+			messages = append(messages, fmt.Sprintf("%s %s %s", e.Channel, e.Severity, e.Message))
+		}
+	}
+	// The oldest entry of the HEALTH channel was evicted.
+	expected := []string{"OPS WARNING recent: ‹×›"}
+	for i := 1; i < RecentEntriesPerChannel+1; i++ {
+		expected = append(expected, fmt.Sprintf("HEALTH ERROR recent: overflow %d", i))
+	}
+	require.Equal(t, expected, messages)
+
+	// The severity filter applies to the retained entries.
+	for _, e := range RecentEntries(severity.ERROR, WithMarkedSensitiveData) {
+		if strings.HasPrefix(e.Message, "recent: ") {
+			require.Equal(t, channel.HEALTH, e.Channel, "unexpected entry: %+v", e)
+		}
+	}
+}
@@ -16,39 +16,33 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/util/encoding/encodingtype"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logdecoder"
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/redact"
 )
 
 // EditSensitiveData describes how the messages in log entries should
 // be edited through the API.
-type EditSensitiveData int
+type EditSensitiveData = logdecoder.EditSensitiveData
 
 const (
-	// The 4 reference values below require the first bit to be
-	// set. This ensures the API is not mistakenly used with an
-	// uninitialized mode parameter.
-	confValid       = 1
-	withKeepMarkers = 2
-	withRedaction   = 4
-
 	// WithFlattenedSensitiveData is the log including sensitive data,
 	// but markers stripped.
-	WithFlattenedSensitiveData EditSensitiveData = confValid
+	WithFlattenedSensitiveData = logdecoder.WithFlattenedSensitiveData
 	// WithMarkedSensitiveData is the "raw" log with sensitive data markers included.
-	WithMarkedSensitiveData EditSensitiveData = confValid | withKeepMarkers
+	WithMarkedSensitiveData = logdecoder.WithMarkedSensitiveData
 	// WithoutSensitiveDataNorMarkers is the log with the sensitive data
 	// redacted, and markers stripped.
-	WithoutSensitiveDataNorMarkers EditSensitiveData = confValid | withRedaction
+	WithoutSensitiveDataNorMarkers = logdecoder.WithoutSensitiveDataNorMarkers
 	// WithoutSensitiveData is the log with the sensitive data redacted,
 	// but markers included.
-	WithoutSensitiveData EditSensitiveData = confValid | withKeepMarkers | withRedaction
+	WithoutSensitiveData = logdecoder.WithoutSensitiveData
 )
 
 // KeepRedactable can be used as an argument to SelectEditMode to indicate that
 // the logs should retain their sensitive data markers so that they can be
 // redacted later.
-const KeepRedactable = true
+const KeepRedactable = logdecoder.KeepRedactable
 
 // SelectEditMode returns an EditSensitiveData value that's suitable
 // for use with NewDecoder depending on client-side desired
@@ -56,15 +50,7 @@ const KeepRedactable = true
 // (See the documentation for the Logs and LogFile RPCs
 // and that of the 'merge-logs' CLI command.)
 func SelectEditMode(redact, keepRedactable bool) EditSensitiveData {
-	var editMode EditSensitiveData
-	if redact {
-		editMode = editMode | withRedaction
-	}
-	if keepRedactable {
-		editMode = editMode | withKeepMarkers
-	}
-	editMode = editMode | confValid
-	return editMode
+	return logdecoder.SelectEditMode(redact, keepRedactable)
 }
 
 type redactEditor func(redactablePackage) redactablePackage
@@ -173,7 +159,7 @@ type redactablePackage struct {
 	redactable bool
 }
 
-const redactableIndicator = "⋮"
+const redactableIndicator = logdecoder.RedactableIndicator
 
 var redactableIndicatorBytes = []byte(redactableIndicator)
 
@@ -0,0 +1,50 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"sync/atomic"
+
+	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+)
+
+// redactionCoverage counts the log entries emitted on each channel,
+// depending on whether their payload is redactable.
+type redactionCoverage struct {
+	redactable   [logpb.Channel_CHANNEL_MAX]int64
+	unredactable [logpb.Channel_CHANNEL_MAX]int64
+}
+
+// record accounts for the given entry.
+func (r *redactionCoverage) record(entry *logEntry) {
+	if entry.header || entry.ch < 0 || int(entry.ch) >= len(r.redactable) {
+		return
+	}
+	if entry.payload.redactable {
+		atomic.AddInt64(&r.redactable[entry.ch], 1)
+	} else {
+		atomic.AddInt64(&r.unredactable[entry.ch], 1)
+	}
+}
+
+// RedactionCoverage reports the number of log entries emitted on the
+// given channel since the process started, split by redactability:
+// redactable entries enclose their sensitive information in redaction
+// markers, whereas unredactable entries contain segments that cannot
+// be told apart as safe or unsafe, and thus must be redacted as a
+// whole.
+func RedactionCoverage(ch Channel) (redactable, unredactable int64) {
+	r := &logging.redactionCoverage
+	if ch < 0 || int(ch) >= len(r.redactable) {
+		return 0, 0
+	}
+	return atomic.LoadInt64(&r.redactable[ch]), atomic.LoadInt64(&r.unredactable[ch])
+}
@@ -0,0 +1,51 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log/channel"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactionCoverage(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer ScopeWithoutShowLogs(t).Close(t)
+
+	ctx := context.Background()
+	opsRedactable, opsUnredactable := RedactionCoverage(channel.OPS)
+	healthRedactable, healthUnredactable := RedactionCoverage(channel.HEALTH)
+
+	Ops.Infof(ctx, "hello %s", "world")
+	Ops.Warningf(ctx, "hello again")
+	entry := makeUnstructuredEntry(ctx, severity.INFO, channel.OPS, 0,
+		false /* redactable */, "unsafe %s", "world")
+	logging.getLogger(channel.OPS).outputLogEntry(ctx, entry)
+
+	r, u := RedactionCoverage(channel.OPS)
+	require.Equal(t, opsRedactable+2, r)
+	require.Equal(t, opsUnredactable+1, u)
+
+	// The other channels are not affected.
+	r, u = RedactionCoverage(channel.HEALTH)
+	require.Equal(t, healthRedactable, r)
+	require.Equal(t, healthUnredactable, u)
+
+	// Out-of-range channels report nothing.
+	r, u = RedactionCoverage(logpb.Channel_CHANNEL_MAX)
+	require.Zero(t, r)
+	require.Zero(t, u)
+}
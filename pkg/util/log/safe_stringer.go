@@ -0,0 +1,180 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"reflect"
+	"sort"
+	"sync/atomic"
+
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/redact"
+)
+
+// SafeStringer is a function that prints a value in a redaction-aware
+// way. It is meant for types defined outside of CockroachDB, which
+// cannot implement redact.SafeFormatter themselves: without a
+// SafeStringer, such values are considered unsafe as a whole when
+// passed as arguments to logging calls.
+//
+// The function should print the parts of the value that are safe for
+// reporting using w.SafeString() or redact.Safe(), and the rest using
+// w.Print() or w.Printf(), which encloses them in redaction markers.
+type SafeStringer func(w redact.SafePrinter, v interface{})
+
+// SafeStringerInfo describes a SafeStringer registration.
+type SafeStringerInfo struct {
+	// Type is the name of the registered type.
+	Type string
+	// Interface is true if the registered type is an interface type.
+	Interface bool
+	// Description explains what is considered safe in the values of
+	// the type.
+	Description string
+}
+
+type safeStringerEntry struct {
+	typ  reflect.Type
+	desc string
+	fn   SafeStringer
+}
+
+var safeStringerRegistry struct {
+	// count is the number of registrations. We keep it out of the
+	// mutex to avoid locking it on every logging call while no
+	// SafeStringer is registered.
+	count int32
+	syncutil.RWMutex
+	// types contains the registrations of concrete types.
+	types map[reflect.Type]*safeStringerEntry
+	// interfaces contains the registrations of interface types, in
+	// registration order.
+	interfaces []*safeStringerEntry
+}
+
+// RegisterSafeStringer registers fn as the SafeStringer for the values
+// of the given type, when they are passed as arguments to the logging
+// calls. The description explains what is considered safe in the values
+// of the type.
+//
+// The registered types are listed in docs/generated/redact_safe.md,
+// so the registration call must mention the type on its first line.
+//
+// If typ is an interface type, fn applies to the values whose dynamic
+// type implements it and does not have a registration of its own. The
+// interface registrations are considered in registration order.
+//
+// The values of types which implement redact.SafeFormatter are never
+// passed to a SafeStringer.
+//
+// This is meant to be called during initialization.
+func RegisterSafeStringer(typ reflect.Type, description string, fn SafeStringer) {
+	r := &safeStringerRegistry
+	r.Lock()
+	defer r.Unlock()
+	if _, ok := r.types[typ]; ok {
+		panic(errors.AssertionFailedf("SafeStringer for %s already registered", typ))
+	}
+	for _, e := range r.interfaces {
+		if e.typ == typ {
+			panic(errors.AssertionFailedf("SafeStringer for %s already registered", typ))
+		}
+	}
+	e := &safeStringerEntry{typ: typ, desc: description, fn: fn}
+	if typ.Kind() == reflect.Interface {
+		r.interfaces = append(r.interfaces, e)
+	} else {
+		if r.types == nil {
+			r.types = make(map[reflect.Type]*safeStringerEntry)
+		}
+		r.types[typ] = e
+	}
+	atomic.AddInt32(&r.count, 1)
+}
+
+// RegisteredSafeStringers returns the SafeStringer registrations,
+// sorted by type name.
+func RegisteredSafeStringers() []SafeStringerInfo {
+	r := &safeStringerRegistry
+	r.RLock()
+	defer r.RUnlock()
+	res := make([]SafeStringerInfo, 0, len(r.types)+len(r.interfaces))
+	for _, e := range r.types {
+		res = append(res, SafeStringerInfo{Type: e.typ.String(), Description: e.desc})
+	}
+	for _, e := range r.interfaces {
+		res = append(res, SafeStringerInfo{Type: e.typ.String(), Interface: true, Description: e.desc})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Type < res[j].Type })
+	return res
+}
+
+// HasSafeStringer returns whether the value is printed by a registered
+// SafeStringer when passed as argument to the logging calls.
+func HasSafeStringer(v interface{}) bool {
+	return lookupSafeStringer(v) != nil
+}
+
+func lookupSafeStringer(v interface{}) SafeStringer {
+	if v == nil {
+		return nil
+	}
+	if _, ok := v.(redact.SafeFormatter); ok {
+		return nil
+	}
+	r := &safeStringerRegistry
+	r.RLock()
+	defer r.RUnlock()
+	t := reflect.TypeOf(v)
+	if e, ok := r.types[t]; ok {
+		return e.fn
+	}
+	for _, e := range r.interfaces {
+		if t.Implements(e.typ) {
+			return e.fn
+		}
+	}
+	return nil
+}
+
+// safeStringerArg adapts a value and its SafeStringer to
+// redact.SafeFormatter.
+type safeStringerArg struct {
+	v  interface{}
+	fn SafeStringer
+}
+
+// SafeFormat implements redact.SafeFormatter.
+func (a safeStringerArg) SafeFormat(w redact.SafePrinter, _ rune) { a.fn(w, a.v) }
+
+// applySafeStringers returns the arguments of a logging call, with the
+// values which have a registered SafeStringer wrapped so that they are
+// printed by it. The slice is only copied if some values are wrapped.
+func applySafeStringers(args []interface{}) []interface{} {
+	if atomic.LoadInt32(&safeStringerRegistry.count) == 0 {
+		return args
+	}
+	res := args
+	copied := false
+	for i, arg := range args {
+		fn := lookupSafeStringer(arg)
+		if fn == nil {
+			continue
+		}
+		if !copied {
+			res = append([]interface{}(nil), args...)
+			copied = true
+		}
+		res[i] = safeStringerArg{v: arg, fn: fn}
+	}
+	return res
+}
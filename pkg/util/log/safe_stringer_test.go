@@ -0,0 +1,104 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log/channel"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+	"github.com/cockroachdb/redact"
+	"github.com/stretchr/testify/require"
+)
+
+// thirdPartyMsg stands for a type defined in an external library.
+type thirdPartyMsg struct {
+	Kind string
+	User string
+}
+
+// thirdPartyErr stands for an error interface defined in an external
+// library.
+type thirdPartyErr interface {
+	error
+	Code() string
+}
+
+type thirdPartyErrImpl struct{ code, msg string }
+
+func (e thirdPartyErrImpl) Error() string { return e.code + ": " + e.msg }
+func (e thirdPartyErrImpl) Code() string  { return e.code }
+
+type alreadySafe struct{}
+
+func (alreadySafe) SafeFormat(w redact.SafePrinter, _ rune) { w.SafeString("already safe") }
+func (alreadySafe) Code() string                            { return "" }
+func (alreadySafe) Error() string                           { return "" }
+
+func TestSafeStringers(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer ScopeWithoutShowLogs(t).Close(t)
+
+	defer func(types map[reflect.Type]*safeStringerEntry, interfaces []*safeStringerEntry, count int32) {
+		safeStringerRegistry.types = types
+		safeStringerRegistry.interfaces = interfaces
+		safeStringerRegistry.count = count
+	}(safeStringerRegistry.types, safeStringerRegistry.interfaces, safeStringerRegistry.count)
+	safeStringerRegistry.types = nil
+	safeStringerRegistry.interfaces = nil
+	safeStringerRegistry.count = 0
+
+	ctx := context.Background()
+	format := func(args ...interface{}) string {
+		entry := makeUnstructuredEntry(ctx, severity.INFO, channel.DEV, 0,
+			true /* redactable */, "%v %v", args...)
+		return entry.payload.message
+	}
+	msg := thirdPartyMsg{Kind: "startup", User: "alice"}
+	err := thirdPartyErrImpl{code: "E42", msg: "no such user alice"}
+
+	// Without registrations, the values are considered unsafe as a
+	// whole.
+	require.Equal(t, "‹{startup alice}› ‹E42: no such user alice›", format(msg, err))
+	require.False(t, HasSafeStringer(msg))
+
+	RegisterSafeStringer(reflect.TypeOf(thirdPartyMsg{}), "the message kind",
+		func(w redact.SafePrinter, v interface{}) {
+			m := v.(thirdPartyMsg)
+			w.Printf("%s message for %s", redact.Safe(m.Kind), m.User)
+		})
+	RegisterSafeStringer(reflect.TypeOf((*thirdPartyErr)(nil)).Elem(), "the error code",
+		func(w redact.SafePrinter, v interface{}) {
+			e := v.(thirdPartyErr)
+			w.Printf("error %s", redact.Safe(e.Code()))
+		})
+	require.True(t, HasSafeStringer(msg))
+	require.True(t, HasSafeStringer(err))
+	// Types implementing redact.SafeFormatter are printed on their own.
+	require.False(t, HasSafeStringer(alreadySafe{}))
+
+	require.Equal(t, "startup message for ‹alice› error E42", format(msg, err))
+	require.Equal(t, "already safe ‹unrelated›", format(alreadySafe{}, "unrelated"))
+
+	require.Equal(t, []SafeStringerInfo{
+		{Type: "log.thirdPartyErr", Interface: true, Description: "the error code"},
+		{Type: "log.thirdPartyMsg", Description: "the message kind"},
+	}, RegisteredSafeStringers())
+
+	require.PanicsWithError(t, fmt.Sprintf("SafeStringer for %s already registered", reflect.TypeOf(msg)),
+		func() {
+			RegisterSafeStringer(reflect.TypeOf(thirdPartyMsg{}), "", nil)
+		})
+}
@@ -0,0 +1,229 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/log/logconfig"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// sinkHealth tracks the problems encountered while delivering log
+// entries to a sink.
+//
+// The methods are safe to call on a nil *sinkHealth; they do nothing.
+type sinkHealth struct {
+	// deliveryFailures counts the errors reported by the sink.
+	// Accessed atomically.
+	deliveryFailures int64
+	// droppedEntries counts the entries that were discarded before
+	// they reached the sink, e.g. because of a buffer overflow or
+	// because of the rate limit of the channel.
+	// Accessed atomically.
+	droppedEntries int64
+	// truncatedEntries counts the entries whose message was truncated
+	// because of the max-entry-size option of the sink. Accessed
+	// atomically.
+	truncatedEntries int64
+
+	mu struct {
+		syncutil.Mutex
+		// lastErr is the last error reported by the sink, and lastErrTime
+		// the time at which it was reported.
+		lastErr     error
+		lastErrTime time.Time
+	}
+}
+
+// recordFailure accounts for a delivery error.
+func (h *sinkHealth) recordFailure(err error) {
+	if h == nil {
+		return
+	}
+	atomic.AddInt64(&h.deliveryFailures, 1)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.mu.lastErr = err
+	h.mu.lastErrTime = timeutil.Now()
+}
+
+// recordDrops accounts for n dropped entries.
+func (h *sinkHealth) recordDrops(n int) {
+	if h == nil || n == 0 {
+		return
+	}
+	atomic.AddInt64(&h.droppedEntries, int64(n))
+}
+
+// recordTruncation accounts for an entry whose message was truncated.
+func (h *sinkHealth) recordTruncation() {
+	if h == nil {
+		return
+	}
+	atomic.AddInt64(&h.truncatedEntries, 1)
+}
+
+// SinkHealthInfo describes the state of one log sink, as reported by
+// SinkHealth().
+type SinkHealthInfo struct {
+	// Type is the type of sink: "file", "fluent", "http", "syslog",
+	// "journald" or "eventlog".
+	Type string
+	// Name is the name of the sink in the logging configuration.
+	Name string
+	// Target is the destination of the log entries, e.g. a directory
+	// or a network address.
+	Target string
+	// Channels lists the channels connected to the sink.
+	Channels []Channel
+	// Buffered indicates whether the sink is buffered. Backpressure is
+	// only meaningful if it is.
+	Buffered bool
+	// Spooled indicates whether the sink has a write-ahead spool on
+	// local disk.
+	Spooled bool
+	// Backpressure is the policy applied when the buffer is full.
+	Backpressure logconfig.BackpressurePolicy
+	// QueuedEntries and QueuedBytes report the contents of the buffer,
+	// or the undelivered contents of the spool. They are only
+	// meaningful if the sink is buffered or spooled.
+	QueuedEntries int64
+	QueuedBytes   int64
+	// MaxQueuedBytes is the capacity of the buffer, or of the spool if
+	// the sink is spooled. It is zero if the capacity is unbounded.
+	MaxQueuedBytes int64
+	// DeliveryFailures counts the errors reported by the sink.
+	DeliveryFailures int64
+	// DroppedEntries counts the entries discarded before they reached
+	// the sink, including those discarded by the rate limiter.
+	DroppedEntries int64
+	// TruncatedEntries counts the entries whose message was truncated
+	// because it exceeded the max-entry-size of the sink.
+	TruncatedEntries int64
+	// LastError is the last error reported by the sink, if any, and
+	// LastErrorTime the time at which it was reported.
+	LastError     error
+	LastErrorTime time.Time
+}
+
+// SinkHealth reports the state of the file and network sinks
+// configured via ApplyConfig(). The stderr sink is not included.
+func SinkHealth() []SinkHealthInfo {
+	var res []SinkHealthInfo
+	_ = logging.allSinkInfos.iter(func(l *sinkInfo) error {
+		if cl := logging.testingFd2CaptureLogger; cl != nil && cl.sinkInfos[0] == l {
+			// Not a real sink. Omit.
+			return nil
+		}
+		if l.health == nil {
+			return nil
+		}
+		info := SinkHealthInfo{
+			Name:             l.name,
+			DeliveryFailures: atomic.LoadInt64(&l.health.deliveryFailures),
+			DroppedEntries:   atomic.LoadInt64(&l.health.droppedEntries),
+			TruncatedEntries: atomic.LoadInt64(&l.health.truncatedEntries),
+		}
+		l.health.mu.Lock()
+		info.LastError = l.health.mu.lastErr
+		info.LastErrorTime = l.health.mu.lastErrTime
+		l.health.mu.Unlock()
+
+		s := l.sink
+		if ss, ok := s.(*spoolSink); ok {
+			info.Spooled = true
+			msgs, bytes := ss.queued()
+			info.QueuedEntries, info.QueuedBytes = int64(msgs), bytes
+			info.MaxQueuedBytes = ss.maxSize
+			s = ss.child
+		}
+		if bs, ok := s.(*bufferedSink); ok {
+			info.Buffered = true
+			info.Backpressure = logconfig.BackpressureDrop
+			if bs.blockOnOverflow {
+				info.Backpressure = logconfig.BackpressureBlock
+			}
+			msgs, bytes := bs.queued()
+			info.QueuedEntries, info.QueuedBytes = int64(msgs), int64(bytes)
+			info.MaxQueuedBytes = int64(bs.capacity())
+			s = bs.child
+		}
+		var ok bool
+		if info.Type, info.Target, ok = describeSink(s); !ok {
+			return nil
+		}
+
+		for ch := Channel(0); ch < logpb.Channel_CHANNEL_MAX; ch++ {
+			if l.threshold.get(ch) != severity.NONE {
+				info.Channels = append(info.Channels, ch)
+			}
+		}
+		res = append(res, info)
+		return nil
+	})
+	return res
+}
+
+// describeSink returns the type and the destination of a file,
+// network or OS logging sink. The returned bool is false for the other
+// sinks.
+func describeSink(s logSink) (typ, target string, ok bool) {
+	switch t := s.(type) {
+	case *fileSink:
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		return "file", t.mu.logDir, true
+	case *fluentSink:
+		return "fluent", t.network + "://" + t.addr, true
+	case *httpSink:
+		return "http", t.address, true
+	case *syslogSink:
+		return "syslog", string(t.network) + "://" + t.addr, true
+	case *journaldSink:
+		return "journald", t.socket, true
+	case *eventLogSink:
+		return "eventlog", t.source, true
+	default:
+		return "", "", false
+	}
+}
+
+// UnderPressure returns true if any of the buffered or spooled sinks that
+// the entries of the given channel are sent to is congested, i.e. holds
+// more than half as many undelivered bytes as it can. Extremely verbose,
+// best-effort producers, such as bridges from traces to logs, can use it
+// to voluntarily shed their output while the logging pipeline catches up.
+//
+// The sinks whose buffer is unbounded are never under pressure.
+func UnderPressure(ch Channel) bool {
+	l := logging.getLogger(ch)
+	for _, s := range l.sinkInfos {
+		if s.threshold.get(ch) == severity.NONE {
+			continue
+		}
+		sink := s.sink
+		if ss, ok := sink.(*spoolSink); ok {
+			if ss.underPressure() {
+				return true
+			}
+			sink = ss.child
+		}
+		if bs, ok := sink.(*bufferedSink); ok && bs.underPressure() {
+			return true
+		}
+	}
+	return false
+}
@@ -72,4 +72,9 @@ var _ logSink = (*stderrSink)(nil)
 var _ logSink = (*fileSink)(nil)
 var _ logSink = (*fluentSink)(nil)
 var _ logSink = (*httpSink)(nil)
+var _ logSink = (*syslogSink)(nil)
+var _ logSink = (*journaldSink)(nil)
+var _ logSink = (*eventLogSink)(nil)
+var _ logSink = (*countSink)(nil)
 var _ logSink = (*bufferedSink)(nil)
+var _ logSink = (*spoolSink)(nil)
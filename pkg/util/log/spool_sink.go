@@ -0,0 +1,612 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/cli/exit"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/errors/oserror"
+)
+
+// spoolSink wraps a network sink to add a write-ahead spool on local
+// disk. Messages are appended to the spool by output(), and delivered
+// to the child sink from there by a background goroutine, which
+// retries until the child sink accepts them. This provides
+// at-least-once delivery across process restarts and network outages:
+// a message may be delivered more than once if the process terminates
+// between its delivery and the recording of the delivery on disk.
+//
+// The spool is a sequence of segment files, which contain the
+// messages as length-prefixed, checksummed records. The position of
+// the first undelivered record is stored in a separate cursor file.
+// Segments are removed once all their records have been delivered.
+//
+// The disk usage of the spool is bounded by maxSize. When a new
+// message does not fit, the oldest segments are removed, regardless
+// of whether their records have been delivered. The number of
+// undelivered records dropped that way is reported to the sink's
+// health.
+//
+// Messages are written to the segment files without buffering, so
+// that they survive a restart of the process as soon as output()
+// returns. They are synced to disk only when output() is called with
+// the extraFlush or forceSync option.
+type spoolSink struct {
+	// child is the wrapped network sink.
+	child logSink
+	// dir is the directory containing the spool files.
+	dir string
+	// maxSize is the limit on the total size of the segment files.
+	maxSize int64
+	// segmentSize is the size after which a new segment is started.
+	segmentSize int64
+	// health, if set, is where delivery failures and dropped messages
+	// are reported.
+	health *sinkHealth
+
+	// wakeC is signaled when new messages are appended to the spool, to
+	// wake up the delivery goroutine.
+	wakeC chan struct{}
+
+	mu struct {
+		syncutil.Mutex
+		// segments lists the segments of the spool, oldest first. The
+		// last one is the one being appended to.
+		segments []spoolSegment
+		// w is the file of the last segment, open for appending.
+		w *os.File
+		// size is the total size of the segments.
+		size int64
+		// cursor is the position of the first undelivered record.
+		cursor spoolCursor
+	}
+}
+
+// spoolSegment describes one segment file of the spool.
+type spoolSegment struct {
+	seq     uint64
+	size    int64
+	records int
+}
+
+// spoolCursor is a position in the spool.
+type spoolCursor struct {
+	// seq is the sequence number of the segment.
+	seq uint64
+	// offset is the position of the record in the segment.
+	offset int64
+	// records is the number of records before offset in the segment.
+	// It is not persisted; it is only used to account for the records
+	// dropped when the segment is removed before it has been fully
+	// delivered.
+	records int
+}
+
+const (
+	spoolSegmentSuffix = ".spool"
+	spoolCursorFile    = "cursor"
+	// spoolRecordHeaderSize is the size of the header of every record:
+	// the length of the message followed by its checksum.
+	spoolRecordHeaderSize = 8
+	// spoolMaxSegmentSize caps the size of the segments.
+	spoolMaxSegmentSize = 64 << 20 // 64MiB
+	// spoolMaxBatchSize is the approximate maximum amount of data
+	// passed to the child sink at once.
+	spoolMaxBatchSize = 1 << 20 // 1MiB
+)
+
+// The backoff applied when the child sink fails to accept the
+// messages. Variables for testing.
+var (
+	spoolRetryInitialBackoff = 100 * time.Millisecond
+	spoolRetryMaxBackoff     = 10 * time.Second
+)
+
+var spoolCRCTable = crc32.MakeTable(crc32.Castagnoli)
+
+var errSpoolCorrupted = errors.New("corrupted spool record")
+
+// newSpoolSink creates a spoolSink that wraps child, storing its files
+// in dir. The records left over in dir by a previous process are
+// recovered, to be delivered once Start() is called.
+func newSpoolSink(child logSink, dir string, maxSize int64) (*spoolSink, error) {
+	segmentSize := maxSize / 8
+	if segmentSize > spoolMaxSegmentSize {
+		segmentSize = spoolMaxSegmentSize
+	}
+	s := &spoolSink{
+		child:       child,
+		dir:         dir,
+		maxSize:     maxSize,
+		segmentSize: segmentSize,
+		// wakeC is a buffered channel, so that signaling it while the
+		// delivery goroutine is busy does not block.
+		wakeC: make(chan struct{}, 1),
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Wrap(err, "creating spool directory")
+	}
+	if err := s.recover(); err != nil {
+		return nil, errors.Wrapf(err, "recovering spool in %s", dir)
+	}
+	if err := s.startSegmentLocked(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// recover loads the segments and the cursor left over by a previous
+// process. Records that were only partially written, for example
+// because of a crash, are truncated.
+func (s *spoolSink) recover() error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+	var seqs []uint64
+	for _, e := range entries {
+		name := e.Name()
+		if !strings.HasSuffix(name, spoolSegmentSuffix) {
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(name, spoolSegmentSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		seqs = append(seqs, seq)
+	}
+	sort.Slice(seqs, func(i, j int) bool { return seqs[i] < seqs[j] })
+
+	cursor, err := s.readCursor()
+	if err != nil {
+		return err
+	}
+	for _, seq := range seqs {
+		if seq < cursor.seq {
+			// Fully delivered already.
+			if err := os.Remove(s.segmentPath(seq)); err != nil {
+				return err
+			}
+			continue
+		}
+		seg, delivered, err := s.scanSegment(seq, cursor)
+		if err != nil {
+			return err
+		}
+		if seq == cursor.seq {
+			cursor.records = delivered
+		}
+		s.mu.segments = append(s.mu.segments, seg)
+		s.mu.size += seg.size
+	}
+	if len(s.mu.segments) > 0 && s.mu.segments[0].seq != cursor.seq {
+		// The segment containing the cursor is gone; start at the
+		// beginning of the oldest remaining segment.
+		cursor = spoolCursor{seq: s.mu.segments[0].seq}
+	}
+	s.mu.cursor = cursor
+	return nil
+}
+
+// scanSegment checks the records of the given segment and truncates it
+// after the last valid record. It returns the segment and the number
+// of records before the cursor.
+func (s *spoolSink) scanSegment(
+	seq uint64, cursor spoolCursor,
+) (seg spoolSegment, delivered int, _ error) {
+	seg.seq = seq
+	path := s.segmentPath(seq)
+	f, err := os.Open(path)
+	if err != nil {
+		return seg, 0, err
+	}
+	r := bufio.NewReader(f)
+	for {
+		msg, err := readSpoolRecord(r)
+		if err != nil {
+			break
+		}
+		if seq == cursor.seq && seg.size < cursor.offset {
+			delivered++
+		}
+		seg.size += int64(spoolRecordHeaderSize + len(msg))
+		seg.records++
+	}
+	if err := f.Close(); err != nil {
+		return seg, 0, err
+	}
+	// Discard the partial or corrupted records, if any.
+	if err := os.Truncate(path, seg.size); err != nil {
+		return seg, 0, err
+	}
+	return seg, delivered, nil
+}
+
+// readSpoolRecord reads one record. It returns io.EOF if there are no
+// more records, and errSpoolCorrupted if the record is incomplete or
+// its checksum does not match.
+func readSpoolRecord(r io.Reader) ([]byte, error) {
+	var hdr [spoolRecordHeaderSize]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		return nil, errSpoolCorrupted
+	}
+	msg := make([]byte, binary.LittleEndian.Uint32(hdr[:4]))
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return nil, errSpoolCorrupted
+	}
+	if crc32.Checksum(msg, spoolCRCTable) != binary.LittleEndian.Uint32(hdr[4:]) {
+		return nil, errSpoolCorrupted
+	}
+	return msg, nil
+}
+
+func (s *spoolSink) segmentPath(seq uint64) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%020d%s", seq, spoolSegmentSuffix))
+}
+
+// readCursor reads the cursor file. A missing file results in a zero
+// cursor, i.e. the beginning of the spool.
+func (s *spoolSink) readCursor() (spoolCursor, error) {
+	b, err := os.ReadFile(filepath.Join(s.dir, spoolCursorFile))
+	if oserror.IsNotExist(err) {
+		return spoolCursor{}, nil
+	}
+	if err != nil {
+		return spoolCursor{}, err
+	}
+	if len(b) != 16 {
+		return spoolCursor{}, errors.Newf("invalid spool cursor file of size %d", len(b))
+	}
+	return spoolCursor{
+		seq:    binary.LittleEndian.Uint64(b[:8]),
+		offset: int64(binary.LittleEndian.Uint64(b[8:])),
+	}, nil
+}
+
+// writeCursor persists the cursor. The file is replaced atomically, so
+// that a crash leaves either the old or the new cursor.
+func (s *spoolSink) writeCursor(c spoolCursor) error {
+	var b [16]byte
+	binary.LittleEndian.PutUint64(b[:8], c.seq)
+	binary.LittleEndian.PutUint64(b[8:], uint64(c.offset))
+	path := filepath.Join(s.dir, spoolCursorFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b[:], 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// startSegmentLocked closes the current segment, if any, and starts a
+// new one.
+func (s *spoolSink) startSegmentLocked() error {
+	var seq uint64
+	if n := len(s.mu.segments); n > 0 {
+		seq = s.mu.segments[n-1].seq + 1
+	} else {
+		// The spool is empty: start after the cursor.
+		seq = s.mu.cursor.seq
+		if s.mu.cursor.offset > 0 {
+			seq++
+		}
+		s.mu.cursor = spoolCursor{seq: seq}
+	}
+	if s.mu.w != nil {
+		if err := s.mu.w.Close(); err != nil {
+			return err
+		}
+		s.mu.w = nil
+	}
+	w, err := os.OpenFile(s.segmentPath(seq), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	s.mu.w = w
+	s.mu.segments = append(s.mu.segments, spoolSegment{seq: seq})
+	return nil
+}
+
+// Start starts the delivery goroutine, which runs until the provided
+// closer is closed.
+func (s *spoolSink) Start(closer *bufferedSinkCloser) {
+	stopC, unregister := closer.registerSpoolSink(s)
+	go func() {
+		defer unregister()
+		s.runDeliverer(stopC)
+	}()
+}
+
+// active implements the logSink interface.
+func (s *spoolSink) active() bool {
+	return s.child.active()
+}
+
+// attachHints implements the logSink interface.
+func (s *spoolSink) attachHints(b []byte) []byte {
+	return s.child.attachHints(b)
+}
+
+// exitCode implements the logSink interface.
+func (s *spoolSink) exitCode() exit.Code {
+	return exit.LoggingFileUnavailable()
+}
+
+// output implements the logSink interface. It appends the message to
+// the spool; an error is returned only if the message could not be
+// written to disk.
+func (s *spoolSink) output(b []byte, opts sinkOutputOptions) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.appendLocked(b); err != nil {
+		return err
+	}
+	if opts.extraFlush || opts.forceSync {
+		if err := s.mu.w.Sync(); err != nil {
+			return err
+		}
+	}
+	select {
+	case s.wakeC <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// appendLocked appends a record containing msg to the spool, making
+// room for it if necessary.
+func (s *spoolSink) appendLocked(msg []byte) error {
+	recLen := int64(spoolRecordHeaderSize + len(msg))
+	if recLen > s.maxSize {
+		s.health.recordDrops(1)
+		return errMsgTooLarge
+	}
+	if cur := &s.mu.segments[len(s.mu.segments)-1]; cur.size > 0 && cur.size+recLen > s.segmentSize {
+		if err := s.startSegmentLocked(); err != nil {
+			return err
+		}
+	}
+	for s.mu.size+recLen > s.maxSize && len(s.mu.segments) > 1 {
+		s.dropOldestSegmentLocked()
+	}
+
+	rec := make([]byte, recLen)
+	binary.LittleEndian.PutUint32(rec[:4], uint32(len(msg)))
+	binary.LittleEndian.PutUint32(rec[4:8], crc32.Checksum(msg, spoolCRCTable))
+	copy(rec[spoolRecordHeaderSize:], msg)
+	cur := &s.mu.segments[len(s.mu.segments)-1]
+	n, err := s.mu.w.Write(rec)
+	if err != nil {
+		if n > 0 {
+			// Do not leave a partial record behind: the records after it
+			// would be unreadable.
+			if terr := s.mu.w.Truncate(cur.size); terr != nil {
+				err = errors.CombineErrors(err, terr)
+			}
+		}
+		return err
+	}
+	cur.size += recLen
+	cur.records++
+	s.mu.size += recLen
+	return nil
+}
+
+// dropOldestSegmentLocked removes the oldest segment to make room for
+// new records, accounting for its undelivered records as dropped.
+func (s *spoolSink) dropOldestSegmentLocked() {
+	seg := s.mu.segments[0]
+	s.mu.segments = s.mu.segments[1:]
+	s.mu.size -= seg.size
+	dropped := seg.records
+	if seg.seq == s.mu.cursor.seq {
+		dropped -= s.mu.cursor.records
+		s.mu.cursor = spoolCursor{seq: s.mu.segments[0].seq}
+	}
+	s.health.recordDrops(dropped)
+	if err := os.Remove(s.segmentPath(seg.seq)); err != nil {
+		s.health.recordFailure(err)
+	}
+}
+
+// queued returns the number of undelivered messages in the spool, and
+// their total size in bytes, including the record headers.
+func (s *spoolSink) queued() (msgs int, bytes int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	msgs = -s.mu.cursor.records
+	bytes = -s.mu.cursor.offset
+	for _, seg := range s.mu.segments {
+		msgs += seg.records
+		bytes += seg.size
+	}
+	return msgs, bytes
+}
+
+// underPressure returns true if the undelivered contents of the spool
+// exceed half its maximum size.
+func (s *spoolSink) underPressure() bool {
+	_, bytes := s.queued()
+	return bytes > s.maxSize/2
+}
+
+// runDeliverer delivers the spooled messages to the child sink until
+// stopC is closed. Upon shutdown, one last attempt is made to deliver
+// the remaining messages; the messages that could not be delivered
+// remain in the spool for the next process.
+func (s *spoolSink) runDeliverer(stopC <-chan struct{}) {
+	defer func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.mu.w != nil {
+			_ = s.mu.w.Close()
+			s.mu.w = nil
+		}
+	}()
+	var backoff time.Duration
+	done := false
+	for {
+		delivered, err := s.deliverBatch()
+		if err != nil {
+			s.health.recordFailure(err)
+			if backoff == 0 {
+				// Report the first failure of an outage only.
+				Ops.Errorf(context.Background(),
+					"logging error from %T: %v; entries remain spooled for redelivery", s.child, err)
+				backoff = spoolRetryInitialBackoff
+			} else if backoff *= 2; backoff > spoolRetryMaxBackoff {
+				backoff = spoolRetryMaxBackoff
+			}
+			if done {
+				return
+			}
+			select {
+			case <-time.After(backoff):
+			case <-stopC:
+				done = true
+			}
+			continue
+		}
+		backoff = 0
+		if delivered {
+			continue
+		}
+		if done {
+			return
+		}
+		// Nothing to deliver; wait for new messages.
+		select {
+		case <-s.wakeC:
+		case <-stopC:
+			done = true
+		}
+	}
+}
+
+// deliverBatch passes the next batch of undelivered records to the
+// child sink, and advances the cursor past them if the child sink
+// accepts them. It returns false if there was nothing to deliver.
+func (s *spoolSink) deliverBatch() (delivered bool, _ error) {
+	s.mu.Lock()
+	cursor := s.mu.cursor
+	seg := s.mu.segments[0]
+	last := len(s.mu.segments) == 1
+	s.mu.Unlock()
+
+	if cursor.offset >= seg.size {
+		if last {
+			return false, nil
+		}
+		// The segment is fully delivered; move on to the next one.
+		return true, s.advance(cursor, spoolCursor{}, true /* segDone */)
+	}
+
+	batch, next, err := s.readBatch(cursor, seg.size)
+	if errors.Is(err, errSpoolCorrupted) {
+		// The rest of the segment is unreadable; skip it.
+		s.health.recordDrops(seg.records - cursor.records)
+		if last {
+			s.mu.Lock()
+			err = errors.CombineErrors(err, s.startSegmentLocked())
+			s.mu.Unlock()
+		}
+		return true, errors.CombineErrors(err,
+			s.advance(cursor, spoolCursor{}, true /* segDone */))
+	}
+	if err != nil {
+		return false, err
+	}
+	if err := s.child.output(batch, sinkOutputOptions{extraFlush: true}); err != nil {
+		return false, err
+	}
+	return true, s.advance(cursor, next, false /* segDone */)
+}
+
+// readBatch reads the records from the cursor up to the given limit,
+// or until about spoolMaxBatchSize bytes have been read. The messages
+// are returned concatenated, separated by newlines, alongside the
+// position following the last record read.
+func (s *spoolSink) readBatch(
+	cursor spoolCursor, limit int64,
+) (batch []byte, next spoolCursor, _ error) {
+	f, err := os.Open(s.segmentPath(cursor.seq))
+	if err != nil {
+		return nil, cursor, err
+	}
+	defer func() { _ = f.Close() }()
+	if _, err := f.Seek(cursor.offset, io.SeekStart); err != nil {
+		return nil, cursor, err
+	}
+	r := bufio.NewReader(io.LimitReader(f, limit-cursor.offset))
+	next = cursor
+	for next.offset < limit && len(batch) < spoolMaxBatchSize {
+		msg, err := readSpoolRecord(r)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = errSpoolCorrupted
+			}
+			if len(batch) > 0 {
+				// Deliver what we have; the error will be encountered again
+				// with the next batch.
+				break
+			}
+			return nil, cursor, err
+		}
+		if len(batch) > 0 {
+			batch = append(batch, '\n')
+		}
+		batch = append(batch, msg...)
+		next.offset += int64(spoolRecordHeaderSize + len(msg))
+		next.records++
+	}
+	return batch, next, nil
+}
+
+// advance moves the cursor from prev to next, unless the spool was
+// modified concurrently in a way that invalidated prev, i.e. the
+// segment was dropped to make room for new records. If segDone is
+// set, the segment at prev is removed instead, and the cursor moves
+// to the beginning of the next segment.
+func (s *spoolSink) advance(prev, next spoolCursor, segDone bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.mu.cursor.seq != prev.seq {
+		// The segment was dropped while the records were delivered.
+		return nil
+	}
+	if segDone {
+		seg := s.mu.segments[0]
+		s.mu.segments = s.mu.segments[1:]
+		s.mu.size -= seg.size
+		next = spoolCursor{seq: s.mu.segments[0].seq}
+		if err := os.Remove(s.segmentPath(seg.seq)); err != nil {
+			return err
+		}
+	}
+	s.mu.cursor = next
+	return s.writeCursor(next)
+}
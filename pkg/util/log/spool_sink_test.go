@@ -0,0 +1,232 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/cli/exit"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// spoolTestChild is a logSink which records the messages it receives,
+// and which can be made to fail.
+type spoolTestChild struct {
+	fail int32 // accessed atomically
+
+	mu struct {
+		syncutil.Mutex
+		msgs []string
+	}
+}
+
+var _ logSink = (*spoolTestChild)(nil)
+
+func (c *spoolTestChild) active() bool                { return true }
+func (c *spoolTestChild) attachHints(b []byte) []byte { return b }
+func (c *spoolTestChild) exitCode() exit.Code         { return exit.UnspecifiedError() }
+
+func (c *spoolTestChild) output(b []byte, _ sinkOutputOptions) error {
+	if atomic.LoadInt32(&c.fail) != 0 {
+		return errors.New("collector unavailable")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mu.msgs = append(c.mu.msgs, strings.Split(string(b), "\n")...)
+	return nil
+}
+
+func (c *spoolTestChild) setFailing(fail bool) {
+	v := int32(0)
+	if fail {
+		v = 1
+	}
+	atomic.StoreInt32(&c.fail, v)
+}
+
+func (c *spoolTestChild) received() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.mu.msgs...)
+}
+
+func spoolTestMessages(n int) []string {
+	msgs := make([]string, n)
+	for i := range msgs {
+		msgs[i] = fmt.Sprintf("message %03d", i)
+	}
+	return msgs
+}
+
+func spoolSegmentFiles(t *testing.T, dir string) []string {
+	matches, err := filepath.Glob(filepath.Join(dir, "*"+spoolSegmentSuffix))
+	require.NoError(t, err)
+	return matches
+}
+
+func TestSpoolSinkRedelivery(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer ScopeWithoutShowLogs(t).Close(t)
+	defer func(d time.Duration) { spoolRetryInitialBackoff = d }(spoolRetryInitialBackoff)
+	spoolRetryInitialBackoff = time.Millisecond
+
+	dir := t.TempDir()
+	child := &spoolTestChild{}
+	child.setFailing(true)
+	s, err := newSpoolSink(child, dir, 1<<20)
+	require.NoError(t, err)
+	s.health = &sinkHealth{}
+	closer := newBufferedSinkCloser()
+	s.Start(closer)
+
+	// While the collector is unavailable, the messages accumulate in the
+	// spool.
+	msgs := spoolTestMessages(100)
+	for _, m := range msgs {
+		require.NoError(t, s.output([]byte(m), sinkOutputOptions{}))
+	}
+	succeedsSoon(t, func() error {
+		if atomic.LoadInt64(&s.health.deliveryFailures) == 0 {
+			return errors.New("no delivery attempt yet")
+		}
+		return nil
+	})
+	n, _ := s.queued()
+	require.Equal(t, len(msgs), n)
+	require.Empty(t, child.received())
+
+	// Once it becomes available, they are all delivered, in order.
+	child.setFailing(false)
+	succeedsSoon(t, func() error {
+		if n, _ := s.queued(); n != 0 {
+			return errors.Newf("%d messages still queued", n)
+		}
+		return nil
+	})
+	require.Equal(t, msgs, child.received())
+	require.Zero(t, atomic.LoadInt64(&s.health.droppedEntries))
+	require.NoError(t, closer.Close(defaultCloserTimeout))
+}
+
+func TestSpoolSinkRecovery(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer ScopeWithoutShowLogs(t).Close(t)
+
+	dir := t.TempDir()
+	msgs := spoolTestMessages(10)
+
+	// Spool some messages without delivering them, as if the process
+	// had terminated during an outage of the collector.
+	s, err := newSpoolSink(&spoolTestChild{}, dir, 1<<20)
+	require.NoError(t, err)
+	for _, m := range msgs {
+		require.NoError(t, s.output([]byte(m), sinkOutputOptions{}))
+	}
+	// Deliver the first few messages.
+	s.mu.Lock()
+	cursor := s.mu.cursor
+	seg := s.mu.segments[0]
+	s.mu.Unlock()
+	batch, next, err := s.readBatch(cursor, seg.size)
+	require.NoError(t, err)
+	require.Equal(t, strings.Join(msgs, "\n"), string(batch))
+	next.offset, next.records = 0, 0
+	for i := 0; i < 3; i++ {
+		next.offset += int64(spoolRecordHeaderSize + len(msgs[i]))
+		next.records++
+	}
+	require.NoError(t, s.advance(cursor, next, false /* segDone */))
+	require.NoError(t, s.mu.w.Close())
+
+	// Simulate a partial write of a record at the time of the crash.
+	f, err := os.OpenFile(s.segmentPath(seg.seq), os.O_WRONLY|os.O_APPEND, 0600)
+	require.NoError(t, err)
+	_, err = f.Write([]byte{42, 0, 0, 0, 1, 2})
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	// The next process delivers the remaining messages.
+	child := &spoolTestChild{}
+	s, err = newSpoolSink(child, dir, 1<<20)
+	require.NoError(t, err)
+	n, _ := s.queued()
+	require.Equal(t, len(msgs)-3, n)
+	closer := newBufferedSinkCloser()
+	s.Start(closer)
+	succeedsSoon(t, func() error {
+		if n, _ := s.queued(); n != 0 {
+			return errors.Newf("%d messages still queued", n)
+		}
+		return nil
+	})
+	require.Equal(t, msgs[3:], child.received())
+	require.NoError(t, closer.Close(defaultCloserTimeout))
+
+	// Only the segment of the last process remains, which is empty.
+	files := spoolSegmentFiles(t, dir)
+	require.Len(t, files, 1)
+	fi, err := os.Stat(files[0])
+	require.NoError(t, err)
+	require.Zero(t, fi.Size())
+}
+
+func TestSpoolSinkMaxSize(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	dir := t.TempDir()
+	const maxSize = 4096
+	s, err := newSpoolSink(&spoolTestChild{}, dir, maxSize)
+	require.NoError(t, err)
+	s.health = &sinkHealth{}
+	defer func() { require.NoError(t, s.mu.w.Close()) }()
+
+	// Spool more messages than fit, without delivering them.
+	msgs := spoolTestMessages(1000)
+	for _, m := range msgs {
+		require.NoError(t, s.output([]byte(m), sinkOutputOptions{}))
+	}
+
+	// The oldest messages have been dropped to respect the size limit.
+	var total int64
+	for _, f := range spoolSegmentFiles(t, dir) {
+		fi, err := os.Stat(f)
+		require.NoError(t, err)
+		total += fi.Size()
+	}
+	require.LessOrEqual(t, total, int64(maxSize))
+	n, bytes := s.queued()
+	require.Equal(t, total, bytes)
+	dropped := atomic.LoadInt64(&s.health.droppedEntries)
+	require.NotZero(t, dropped)
+	require.Equal(t, len(msgs), n+int(dropped))
+
+	// The remaining messages are the most recent ones.
+	s.mu.Lock()
+	cursor := s.mu.cursor
+	seg := s.mu.segments[0]
+	s.mu.Unlock()
+	batch, _, err := s.readBatch(cursor, seg.size)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(batch), msgs[dropped]))
+
+	// A message larger than the spool is rejected.
+	require.True(t, errors.Is(
+		s.output(make([]byte, maxSize), sinkOutputOptions{}), errMsgTooLarge))
+}
@@ -0,0 +1,139 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/redact"
+)
+
+// StartupReporter is called once the logging configuration has been
+// applied at process start-up, to report it as a structured event. It
+// is injected by package server, like ConfigChangeReporter.
+var StartupReporter func(ctx context.Context, report StartupReport)
+
+// StartupReport summarizes how logging was set up at process start-up.
+type StartupReport struct {
+	// Config is the resolved logging configuration in YAML format,
+	// where the configuration values are marked as unsafe for
+	// reporting.
+	Config redact.RedactableString
+	// Sinks reports the results of the initial probes of the file and
+	// network sinks.
+	Sinks []SinkProbeResult
+}
+
+// SinkProbeResult is the result of the initial probe of a sink.
+type SinkProbeResult struct {
+	// Type is the type of sink: "file", "fluent", "http", "syslog",
+	// "journald" or "eventlog".
+	Type string
+	// Name is the name of the sink in the logging configuration.
+	Name string
+	// Target is the destination of the log entries, e.g. a directory
+	// or a network address.
+	Target string
+	// Err is the error encountered by the probe, if any.
+	Err error
+}
+
+// sinkProber is implemented by the sinks which can check that their
+// destination is usable, without emitting any log entry to it.
+type sinkProber interface {
+	probe() error
+}
+
+// sinkProbeTimeout is the maximum amount of time spent establishing a
+// connection to a network sink during a probe.
+const sinkProbeTimeout = 2 * time.Second
+
+// probeDial checks that a connection can be established with the given
+// network address.
+func probeDial(network, addr string, tlsConf *tls.Config) error {
+	dialer := &net.Dialer{Timeout: sinkProbeTimeout}
+	var conn net.Conn
+	var err error
+	if tlsConf != nil {
+		conn, err = tls.DialWithDialer(dialer, network, addr, tlsConf)
+	} else {
+		conn, err = dialer.Dial(network, addr)
+	}
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// ReportStartup probes the file and network sinks configured via
+// ApplyConfig(), and reports the result alongside the applied
+// configuration via StartupReporter. The sinks are probed
+// concurrently, so that unreachable network sinks delay the start-up
+// by at most sinkProbeTimeout.
+func ReportStartup(ctx context.Context) {
+	if StartupReporter == nil {
+		return
+	}
+	report := StartupReport{
+		Config: redactConfig(DescribeAppliedConfig()),
+	}
+	var sinks []sinkProber
+	_ = logging.allSinkInfos.iter(func(l *sinkInfo) error {
+		if cl := logging.testingFd2CaptureLogger; cl != nil && cl.sinkInfos[0] == l {
+			// Not a real sink. Omit.
+			return nil
+		}
+		s := l.sink
+		if ss, ok := s.(*spoolSink); ok {
+			s = ss.child
+		}
+		if bs, ok := s.(*bufferedSink); ok {
+			s = bs.child
+		}
+		typ, target, ok := describeSink(s)
+		if !ok {
+			return nil
+		}
+		p, ok := s.(sinkProber)
+		if !ok {
+			return nil
+		}
+		report.Sinks = append(report.Sinks, SinkProbeResult{Type: typ, Name: l.name, Target: target})
+		sinks = append(sinks, p)
+		return nil
+	})
+
+	var wg sync.WaitGroup
+	for i := range sinks {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			report.Sinks[i].Err = sinks[i].probe()
+		}(i)
+	}
+	wg.Wait()
+
+	StartupReporter(ctx, report)
+}
+
+// redactConfig marks the values in a description of the logging
+// configuration as unsafe for reporting, like diffConfig.
+func redactConfig(config string) redact.RedactableString {
+	var buf redact.StringBuilder
+	for _, line := range splitConfigLines(config) {
+		writeConfigLine(&buf, "", line)
+	}
+	return buf.RedactableString()
+}
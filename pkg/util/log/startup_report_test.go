@@ -0,0 +1,77 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log/channel"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logconfig"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportStartup(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	sc := ScopeWithoutShowLogs(t)
+	defer sc.Close(t)
+
+	// A collector that accepts connections, and the address of one
+	// that does not.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = l.Close() }()
+	l2, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	unreachableAddr := l2.Addr().String()
+	require.NoError(t, l2.Close())
+
+	cfg := logconfig.DefaultConfig()
+	cfg.Sinks.FluentServers = map[string]*logconfig.FluentSinkConfig{
+		"good": {Address: l.Addr().String(), Channels: logconfig.SelectChannels(channel.OPS)},
+		"bad":  {Address: unreachableAddr, Channels: logconfig.SelectChannels(channel.HEALTH)},
+	}
+	require.NoError(t, cfg.Validate(&sc.logDir))
+
+	TestingResetActive()
+	cleanup, err := ApplyConfig(cfg)
+	require.NoError(t, err)
+	defer cleanup()
+
+	var reports []StartupReport
+	defer func(prev func(context.Context, StartupReport)) {
+		StartupReporter = prev
+	}(StartupReporter)
+	StartupReporter = func(_ context.Context, report StartupReport) {
+		reports = append(reports, report)
+	}
+
+	ReportStartup(context.Background())
+	require.Len(t, reports, 1)
+	report := reports[0]
+
+	require.Contains(t, string(report.Config), "fluent-servers:")
+	// The configuration values are marked as unsafe.
+	require.NotContains(t, string(report.Config.Redact()), unreachableAddr)
+
+	results := make(map[string]SinkProbeResult)
+	for _, r := range report.Sinks {
+		results[r.Type+":"+r.Name] = r
+	}
+	require.Contains(t, results, "file:default")
+	require.NoError(t, results["file:default"].Err)
+	require.Equal(t, sc.logDir, results["file:default"].Target)
+	require.NoError(t, results["fluent:good"].Err)
+	require.Error(t, results["fluent:bad"].Err)
+	require.Equal(t, "tcp://"+unreachableAddr, results["fluent:bad"].Target)
+}
@@ -0,0 +1,305 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/cli/exit"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logconfig"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// syslogSink represents a syslog server reachable over the network.
+//
+// The entries are formatted by a syslogFormatter, which produces
+// RFC 5424 messages framed using octet counting (RFC 6587). Over TCP
+// and TLS the framed messages are sent back to back; over UDP the
+// framing is removed and every message is sent in a separate datagram,
+// as prescribed by RFC 5426.
+type syslogSink struct {
+	network logconfig.SyslogNetwork
+	addr    string
+	tlsConf *tls.Config
+
+	// config is the configuration this sink was created with. It is
+	// used by DescribeAppliedConfig().
+	config *logconfig.SyslogSinkConfig
+
+	mu struct {
+		syncutil.Mutex
+		// good indicates that the connection can be used.
+		good bool
+		conn net.Conn
+	}
+}
+
+const syslogDialTimeout = 5 * time.Second
+const syslogWriteTimeout = time.Second
+
+func newSyslogSink(c logconfig.SyslogSinkConfig) *syslogSink {
+	s := &syslogSink{
+		network: *c.Net,
+		addr:    *c.Address,
+		config:  &c,
+	}
+	if s.network == logconfig.SyslogNetworkTLS {
+		s.tlsConf = &tls.Config{InsecureSkipVerify: *c.UnsafeTLS}
+	}
+	return s
+}
+
+func (l *syslogSink) String() string {
+	return fmt.Sprintf("syslog:%s://%s", l.network, l.addr)
+}
+
+// active implements the logSink interface.
+func (l *syslogSink) active() bool { return true }
+
+// probe implements the sinkProber interface.
+func (l *syslogSink) probe() error {
+	network := string(l.network)
+	if l.tlsConf != nil {
+		network = "tcp"
+	}
+	return probeDial(network, l.addr, l.tlsConf)
+}
+
+// attachHints implements the logSink interface.
+func (l *syslogSink) attachHints(stacks []byte) []byte {
+	return stacks
+}
+
+// exitCode implements the logSink interface.
+func (l *syslogSink) exitCode() exit.Code {
+	return exit.LoggingNetCollectorUnavailable()
+}
+
+// output implements the logSink interface.
+func (l *syslogSink) output(b []byte, opts sinkOutputOptions) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// The buffering wrapper may have concatenated multiple messages
+	// together, separated by newlines. Over UDP, send each of them in a
+	// separate datagram. Over TCP and TLS, strip the separators, which
+	// are not valid between octet-counted frames.
+	if l.network == logconfig.SyslogNetworkUDP {
+		for len(b) > 0 {
+			var msg []byte
+			msg, b = splitSyslogFrame(b)
+			if len(msg) == 0 {
+				continue
+			}
+			if err := l.writeWithRetryLocked(msg); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	buf := getBuffer()
+	defer putBuffer(buf)
+	for len(b) > 0 {
+		var msg []byte
+		msg, b = splitSyslogFrame(b)
+		if len(msg) == 0 {
+			continue
+		}
+		buf.WriteString(strconv.Itoa(len(msg)))
+		buf.WriteByte(' ')
+		buf.Write(msg)
+	}
+	return l.writeWithRetryLocked(buf.Bytes())
+}
+
+// writeWithRetryLocked writes the given bytes to the connection,
+// reconnecting once if the first write fails.
+func (l *syslogSink) writeWithRetryLocked(b []byte) error {
+	_ = l.tryWriteLocked(b)
+	if l.mu.good {
+		return nil
+	}
+	if err := l.ensureConnLocked(b); err != nil {
+		return err
+	}
+	return l.tryWriteLocked(b)
+}
+
+func (l *syslogSink) closeLocked() {
+	l.mu.good = false
+	if l.mu.conn != nil {
+		if err := l.mu.conn.Close(); err != nil {
+			fmt.Fprintf(OrigStderr, "error closing syslog connection: %v\n", err)
+		}
+		l.mu.conn = nil
+	}
+}
+
+func (l *syslogSink) ensureConnLocked(b []byte) error {
+	if l.mu.good {
+		return nil
+	}
+	l.closeLocked()
+	var err error
+	dialer := &net.Dialer{Timeout: syslogDialTimeout}
+	if l.tlsConf != nil {
+		l.mu.conn, err = tls.DialWithDialer(dialer, "tcp", l.addr, l.tlsConf)
+	} else {
+		l.mu.conn, err = dialer.Dial(string(l.network), l.addr)
+	}
+	if err != nil {
+		fmt.Fprintf(OrigStderr, "%s: error dialing syslog server: %v\n%s", l, err, b)
+		return err
+	}
+	fmt.Fprintf(OrigStderr, "%s: connection to syslog server resumed\n", l)
+	l.mu.good = true
+	return nil
+}
+
+func (l *syslogSink) tryWriteLocked(b []byte) error {
+	if !l.mu.good {
+		return errNoConn
+	}
+	if err := l.mu.conn.SetWriteDeadline(timeutil.Now().Add(syslogWriteTimeout)); err != nil {
+		// An error here is suggestive of a bug in the Go runtime.
+		fmt.Fprintf(OrigStderr, "%s: set write deadline error: %v\n%s",
+			l, err, b)
+		l.mu.good = false
+		return err
+	}
+	n, err := l.mu.conn.Write(b)
+	if err != nil || n < len(b) {
+		fmt.Fprintf(OrigStderr, "%s: logging error: %v or short write (%d/%d)\n%s",
+			l, err, n, len(b), b)
+		l.mu.good = false
+	}
+	return err
+}
+
+// splitSyslogFrame extracts the first message from a sequence of
+// octet-counted syslog frames, possibly separated by newlines, and
+// returns it alongside the remainder of the input. If the input is not
+// framed as expected, it is returned in full as a single message.
+func splitSyslogFrame(b []byte) (msg, rest []byte) {
+	b = bytes.TrimLeft(b, "\n")
+	sp := bytes.IndexByte(b, ' ')
+	if sp <= 0 {
+		return b, nil
+	}
+	n, err := strconv.Atoi(string(b[:sp]))
+	if err != nil || n < 0 || n > len(b)-sp-1 {
+		return b, nil
+	}
+	return b[sp+1 : sp+1+n], b[sp+1+n:]
+}
+
+// syslogFormatter wraps another formatter to produce RFC 5424 syslog
+// messages. The output of the wrapped formatter is used as the MSG
+// part of the syslog message. The resulting message is framed using
+// octet counting.
+type syslogFormatter struct {
+	inner    logFormatter
+	facility int
+	// header is the part of the syslog header that is common to all
+	// messages: HOSTNAME, APP-NAME and PROCID.
+	header string
+}
+
+func newSyslogFormatter(inner logFormatter, facility logconfig.SyslogFacility) *syslogFormatter {
+	return &syslogFormatter{
+		inner:    inner,
+		facility: facility.Code(),
+		header: fmt.Sprintf("%s %s %d",
+			syslogHeaderField(fullHostName, 255),
+			syslogHeaderField(fileNameConstants.program, 48),
+			fileNameConstants.pid),
+	}
+}
+
+// formatterName implements the logFormatter interface. The name of the
+// wrapped formatter is reported, since this is the format selected in
+// the configuration.
+func (f *syslogFormatter) formatterName() string { return f.inner.formatterName() }
+
+// doc implements the logFormatter interface.
+func (f *syslogFormatter) doc() string { return f.inner.doc() }
+
+// contentType implements the logFormatter interface.
+func (f *syslogFormatter) contentType() string { return f.inner.contentType() }
+
+// formatEntry implements the logFormatter interface.
+func (f *syslogFormatter) formatEntry(entry logEntry) *buffer {
+	sev, msgID := severity.INFO, "-"
+	if !entry.header {
+		sev, msgID = entry.sev, entry.ch.String()
+	}
+
+	msg := f.inner.formatEntry(entry)
+	defer putBuffer(msg)
+
+	hdr := getBuffer()
+	defer putBuffer(hdr)
+	// <PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA
+	fmt.Fprintf(hdr, "<%d>1 %s %s %s - ",
+		f.facility*8+syslogSeverity(sev),
+		timeutil.Unix(0, entry.ts).UTC().Format("2006-01-02T15:04:05.000000Z07:00"),
+		f.header, msgID)
+
+	body := bytes.TrimRight(msg.Bytes(), "\n")
+	buf := getBuffer()
+	buf.WriteString(strconv.Itoa(hdr.Len() + len(body)))
+	buf.WriteByte(' ')
+	buf.Write(hdr.Bytes())
+	buf.Write(body)
+	return buf
+}
+
+// syslogSeverity maps a logging severity to a syslog severity code.
+func syslogSeverity(sev Severity) int {
+	switch sev {
+	case severity.FATAL:
+		return 2 // critical
+	case severity.ERROR:
+		return 3 // error
+	case severity.WARNING:
+		return 4 // warning
+	case severity.DEBUG1, severity.DEBUG2:
+		return 7 // debug
+	default:
+		return 6 // informational
+	}
+}
+
+// syslogHeaderField sanitizes a value for use in a syslog header
+// field, which must consist of printable ASCII characters and be at
+// most maxLen characters long.
+func syslogHeaderField(s string, maxLen int) string {
+	s = strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' {
+			return '_'
+		}
+		return r
+	}, s)
+	if s == "" {
+		return "-"
+	}
+	if len(s) > maxLen {
+		s = s[:maxLen]
+	}
+	return s
+}
@@ -0,0 +1,178 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log/channel"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logconfig"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/stretchr/testify/require"
+)
+
+// syslogMessageRE matches the RFC 5424 messages produced by the syslog
+// sink for a json-compact payload.
+var syslogMessageRE = regexp.MustCompile(
+	`^<(\d+)>1 \d{4}-\d\d-\d\dT\d\d:\d\d:\d\d\.\d{6}Z \S+ \S+ \d+ (\S+) - (\{.*\})$`)
+
+func TestSyslogSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testCases := []struct {
+		net    logconfig.SyslogNetwork
+		listen func(t *testing.T) (addr string, recv func() string, cleanup func())
+	}{
+		{logconfig.SyslogNetworkUDP, serveSyslogUDP},
+		{logconfig.SyslogNetworkTCP, serveSyslogTCP},
+	}
+	for _, tc := range testCases {
+		t.Run(string(tc.net), func(t *testing.T) {
+			sc := ScopeWithoutShowLogs(t)
+			defer sc.Close(t)
+
+			addr, recv, cleanup := tc.listen(t)
+			defer cleanup()
+
+			network := tc.net
+			facility := logconfig.SyslogFacility("local0")
+			cfg := logconfig.DefaultConfig()
+			zeroBytes := logconfig.ByteSize(0)
+			zeroDuration := time.Duration(0)
+			cfg.Sinks.SyslogServers = map[string]*logconfig.SyslogSinkConfig{
+				"ops": {
+					Channels: logconfig.SelectChannels(channel.OPS),
+					SyslogDefaults: logconfig.SyslogDefaults{
+						Address:  &addr,
+						Net:      &network,
+						Facility: &facility,
+						CommonSinkConfig: logconfig.CommonSinkConfig{
+							Buffering: logconfig.CommonBufferSinkConfigWrapper{
+								CommonBufferSinkConfig: logconfig.CommonBufferSinkConfig{
+									MaxStaleness:     &zeroDuration,
+									FlushTriggerSize: &zeroBytes,
+									MaxBufferSize:    &zeroBytes,
+								},
+							},
+						},
+					},
+				},
+			}
+			require.NoError(t, cfg.Validate(&sc.logDir))
+
+			TestingResetActive()
+			cleanupCfg, err := ApplyConfig(cfg)
+			require.NoError(t, err)
+			defer cleanupCfg()
+
+			ctx := context.Background()
+			Ops.Infof(ctx, "hello world")
+			Ops.Warningf(ctx, "hello again")
+
+			// The priority is facility*8 + severity, with local0 = 16.
+			for _, exp := range []struct {
+				pri int
+				msg string
+			}{
+				{16*8 + 6, "hello world"},
+				{16*8 + 4, "hello again"},
+			} {
+				m := syslogMessageRE.FindStringSubmatch(recv())
+				require.NotNil(t, m)
+				require.Equal(t, strconv.Itoa(exp.pri), m[1])
+				require.Equal(t, "OPS", m[2])
+				require.Contains(t, m[3], fmt.Sprintf(`"message":"%s"`, exp.msg))
+			}
+		})
+	}
+}
+
+func TestSplitSyslogFrame(t *testing.T) {
+	msg, rest := splitSyslogFrame([]byte("5 hello3 abc"))
+	require.Equal(t, "hello", string(msg))
+	msg, rest = splitSyslogFrame(rest)
+	require.Equal(t, "abc", string(msg))
+	require.Empty(t, rest)
+
+	// Frames concatenated by the buffering wrapper are separated by
+	// newlines.
+	msg, rest = splitSyslogFrame([]byte("5 hello\n3 abc"))
+	require.Equal(t, "hello", string(msg))
+	msg, rest = splitSyslogFrame(rest)
+	require.Equal(t, "abc", string(msg))
+	require.Empty(t, rest)
+
+	// Unframed input is returned as-is.
+	msg, rest = splitSyslogFrame([]byte("hello world"))
+	require.Equal(t, "hello world", string(msg))
+	require.Empty(t, rest)
+	msg, rest = splitSyslogFrame([]byte("20 short"))
+	require.Equal(t, "20 short", string(msg))
+	require.Empty(t, rest)
+}
+
+// serveSyslogUDP creates an in-memory UDP listener which reports the
+// datagrams it receives.
+func serveSyslogUDP(t *testing.T) (addr string, recv func() string, cleanup func()) {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	recv = func() string {
+		require.NoError(t, conn.SetReadDeadline(timeutil.Now().Add(5*time.Second)))
+		buf := make([]byte, 65536)
+		n, err := conn.Read(buf)
+		require.NoError(t, err)
+		return string(buf[:n])
+	}
+	cleanup = func() { require.NoError(t, conn.Close()) }
+	return conn.LocalAddr().String(), recv, cleanup
+}
+
+// serveSyslogTCP creates an in-memory TCP listener which accepts one
+// connection and reports the octet-counted frames it receives.
+func serveSyslogTCP(t *testing.T) (addr string, recv func() string, cleanup func()) {
+	l, err := net.ListenTCP("tcp", &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	var conn net.Conn
+	var rd *bufio.Reader
+	recv = func() string {
+		if conn == nil {
+			require.NoError(t, l.SetDeadline(timeutil.Now().Add(5*time.Second)))
+			conn, err = l.Accept()
+			require.NoError(t, err)
+			rd = bufio.NewReader(conn)
+		}
+		require.NoError(t, conn.SetReadDeadline(timeutil.Now().Add(5*time.Second)))
+		lenStr, err := rd.ReadString(' ')
+		require.NoError(t, err)
+		n, err := strconv.Atoi(lenStr[:len(lenStr)-1])
+		require.NoError(t, err)
+		buf := make([]byte, n)
+		_, err = io.ReadFull(rd, buf)
+		require.NoError(t, err)
+		return string(buf)
+	}
+	cleanup = func() {
+		if conn != nil {
+			require.NoError(t, conn.Close())
+		}
+		require.NoError(t, l.Close())
+	}
+	return l.Addr().String(), recv, cleanup
+}
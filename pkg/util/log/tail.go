@@ -0,0 +1,229 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"context"
+	"encoding/binary"
+	"regexp"
+	"sync/atomic"
+
+	"github.com/cockroachdb/cockroach/pkg/cli/exit"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// DefaultTailBufferSize is the number of entries buffered for a
+// TailSubscription when TailOptions.BufferSize is not set.
+const DefaultTailBufferSize = 4096
+
+// TailOptions configures a TailSubscription.
+type TailOptions struct {
+	// Channels, if not empty, restricts the entries to those emitted
+	// on the given channels.
+	Channels []Channel
+	// Severity is the minimum severity of the entries. Entries below
+	// INFO are never delivered.
+	Severity Severity
+	// Pattern, if not nil, must match the message of the entries.
+	Pattern *regexp.Regexp
+	// EditMode determines how sensitive data is presented in the
+	// entries. It defaults to WithMarkedSensitiveData.
+	EditMode EditSensitiveData
+	// BufferSize is the maximum number of entries buffered for the
+	// subscriber. It defaults to DefaultTailBufferSize.
+	BufferSize int
+}
+
+// TailSubscription receives the log entries selected by its
+// TailOptions as they are emitted.
+type TailSubscription struct {
+	channels map[Channel]struct{}
+	severity Severity
+	pattern  *regexp.Regexp
+	editor   redactEditor
+
+	entries chan logpb.Entry
+	// dropped is the number of entries that were not delivered because
+	// the buffer was full, since the last call to TakeDropped().
+	dropped int64
+}
+
+// Tail subscribes to the entries emitted on all the logging channels
+// from this point on, regardless of the filtering configured on log
+// sinks.
+//
+// The entries are buffered in the subscription until they are
+// consumed via Entries(). The logging calls never wait for a
+// subscriber: when its buffer is full, entries are dropped and
+// accounted for in TakeDropped().
+//
+// The returned function should be called to cancel the subscription.
+// The channel returned by Entries() is never closed.
+//
+// Multiple subscriptions can be active simultaneously; each of them
+// is served the entries it selects.
+func Tail(ctx context.Context, opts TailOptions) (*TailSubscription, func()) {
+	s := &TailSubscription{
+		severity: opts.Severity,
+		pattern:  opts.Pattern,
+	}
+	if len(opts.Channels) > 0 {
+		s.channels = make(map[Channel]struct{}, len(opts.Channels))
+		for _, ch := range opts.Channels {
+			s.channels[ch] = struct{}{}
+		}
+	}
+	editMode := opts.EditMode
+	if editMode == 0 {
+		editMode = WithMarkedSensitiveData
+	}
+	s.editor = getEditor(editMode)
+	bufferSize := opts.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = DefaultTailBufferSize
+	}
+	s.entries = make(chan logpb.Entry, bufferSize)
+
+	InfofDepth(ctx, 1, "starting log tail")
+	logging.tail.add(s)
+	return s, func() {
+		logging.tail.del(s)
+		InfofDepth(ctx, 1, "stopping log tail")
+	}
+}
+
+// Entries returns the channel on which the entries are delivered.
+func (s *TailSubscription) Entries() <-chan logpb.Entry {
+	return s.entries
+}
+
+// TakeDropped returns the number of entries that were dropped because
+// the subscriber was not keeping up, since the previous call.
+func (s *TailSubscription) TakeDropped() int64 {
+	return atomic.SwapInt64(&s.dropped, 0)
+}
+
+func (s *TailSubscription) selects(entry *logpb.Entry) bool {
+	if entry.Severity < s.severity {
+		return false
+	}
+	if s.channels != nil {
+		if _, ok := s.channels[entry.Channel]; !ok {
+			return false
+		}
+	}
+	return s.pattern == nil || s.pattern.MatchString(entry.Message)
+}
+
+// deliver sends the entry to the subscriber, unless its buffer is
+// full.
+func (s *TailSubscription) deliver(entry logpb.Entry) {
+	editEntry(&entry, s.editor)
+	select {
+	case s.entries <- entry:
+	default:
+		atomic.AddInt64(&s.dropped, 1)
+	}
+}
+
+// editEntry applies the editor to the sensitive data of the entry.
+func editEntry(entry *logpb.Entry, editor redactEditor) {
+	if entry.Tags != "" {
+		r := editor(redactablePackage{
+			msg:        []byte(entry.Tags),
+			redactable: entry.Redactable,
+		})
+		entry.Tags = string(r.msg)
+	}
+	r := editor(redactablePackage{
+		msg:        []byte(entry.Message),
+		redactable: entry.Redactable,
+	})
+	entry.Message = string(r.msg)
+	entry.Redactable = r.redactable
+}
+
+func (l *loggingT) newTailSinkInfo() *sinkInfo {
+	si := &sinkInfo{
+		sink:       &l.tail,
+		editor:     getEditor(WithMarkedSensitiveData),
+		formatter:  formatCrdbProto{},
+		redact:     false, // the subscriptions apply their own edit mode
+		redactable: true,  // keep redaction markers
+		// Subscribers see all events regardless of filtering.
+		ignoreSeverityOverrides: true,
+	}
+	// Ensure all events are collected across all channels.
+	si.threshold.setAll(severity.INFO)
+	return si
+}
+
+// tailSink fans out the entries to the active tail subscriptions. The
+// entries are received in the crdb-proto format, so that they only
+// need to be decoded once for all the subscribers.
+type tailSink struct {
+	// activeCount is the number of subscriptions under the mutex. We
+	// keep it out to avoid locking the mutex in the active() method.
+	activeCount uint32
+	mu          struct {
+		syncutil.RWMutex
+
+		subs []*TailSubscription
+	}
+}
+
+func (t *tailSink) add(s *TailSubscription) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.mu.subs = append(t.mu.subs, s)
+	atomic.AddUint32(&t.activeCount, 1)
+}
+
+func (t *tailSink) del(toDel *TailSubscription) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for j, s := range t.mu.subs {
+		if s == toDel {
+			t.mu.subs = append(t.mu.subs[:j], t.mu.subs[j+1:]...)
+			atomic.AddUint32(&t.activeCount, ^uint32(0) /* -1 */)
+			break
+		}
+	}
+}
+
+func (t *tailSink) active() bool {
+	return atomic.LoadUint32(&t.activeCount) > 0
+}
+
+func (t *tailSink) output(b []byte, _ sinkOutputOptions) error {
+	size, n := binary.Uvarint(b)
+	if n <= 0 || size > uint64(len(b)-n) {
+		// formatCrdbProto always produces a valid length prefix.
+		return nil
+	}
+	var entry logpb.Entry
+	if err := entry.Unmarshal(b[n : n+int(size)]); err != nil {
+		return err
+	}
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	for _, s := range t.mu.subs {
+		if s.selects(&entry) {
+			s.deliver(entry)
+		}
+	}
+	return nil
+}
+
+func (t *tailSink) attachHints(stacks []byte) []byte { return stacks }
+func (t *tailSink) exitCode() exit.Code              { return exit.UnspecifiedError() }
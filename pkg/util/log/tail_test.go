@@ -0,0 +1,99 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log/channel"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+	"github.com/stretchr/testify/require"
+)
+
+func nextTailEntry(t *testing.T, s *TailSubscription) logpb.Entry {
+	t.Helper()
+	select {
+	case e := <-s.Entries():
+		return e
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for log entry")
+		return logpb.Entry{}
+	}
+}
+
+func TestTailFilters(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer Scope(t).Close(t)
+
+	ctx := context.Background()
+
+	sub, cleanup := Tail(ctx, TailOptions{
+		Channels: []Channel{channel.OPS},
+		Severity: severity.WARNING,
+		Pattern:  regexp.MustCompile(`tail me`),
+		EditMode: WithoutSensitiveData,
+	})
+	defer cleanup()
+
+	Ops.Infof(ctx, "tail me: too low")
+	Warningf(ctx, "tail me: wrong channel")
+	Ops.Warningf(ctx, "not matching")
+	Ops.Warningf(ctx, "tail me: %s", "secret")
+
+	e := nextTailEntry(t, sub)
+	require.Equal(t, channel.OPS, e.Channel)
+	require.Equal(t, severity.WARNING, e.Severity)
+	require.Equal(t, "tail me: ‹×›", e.Message)
+
+	select {
+	case e := <-sub.Entries():
+		t.Fatalf("unexpected entry: %+v", e)
+	default:
+	}
+	require.Zero(t, sub.TakeDropped())
+}
+
+func TestTailDropsWhenFull(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer Scope(t).Close(t)
+
+	ctx := context.Background()
+
+	sub, cleanup := Tail(ctx, TailOptions{
+		Pattern:    regexp.MustCompile(`overflow`),
+		BufferSize: 2,
+	})
+	defer cleanup()
+
+	for i := 0; i < 5; i++ {
+		Infof(ctx, "overflow %d", i)
+	}
+
+	require.Equal(t, "overflow 0", nextTailEntry(t, sub).Message)
+	require.Equal(t, "overflow 1", nextTailEntry(t, sub).Message)
+	require.Equal(t, int64(3), sub.TakeDropped())
+	require.Zero(t, sub.TakeDropped())
+
+	// Once the subscription is canceled, entries are not delivered any
+	// more.
+	cleanup()
+	Infof(ctx, "overflow after")
+	select {
+	case e := <-sub.Entries():
+		t.Fatalf("unexpected entry: %+v", e)
+	default:
+	}
+}
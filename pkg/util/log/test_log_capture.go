@@ -0,0 +1,107 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+
+	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/redact"
+)
+
+// ScopeWithCapture is like Scope, and additionally captures the
+// entries logged to the given channels during the lifetime of the
+// scope, regardless of the filtering configured on the log sinks. This
+// lets tests assert on the entries of e.g. the OPS and HEALTH channels
+// without scraping the log files.
+//
+// The captured entries are returned by the Captured() method of the
+// scope, or by its per-channel accessors, e.g. CapturedOps(). They can
+// be matched with the CapturedMatching() method.
+func ScopeWithCapture(t tShim, chs ...Channel) *TestLogScope {
+	t.Helper()
+	sc := Scope(t)
+	sc.capture = &channelCapture{}
+	for _, ch := range chs {
+		sc.capture.channels[ch] = true
+	}
+	sc.capture.stop = InterceptWith(context.Background(), sc.capture)
+	return sc
+}
+
+// channelCapture is the interceptor installed by ScopeWithCapture.
+type channelCapture struct {
+	// channels is the set of captured channels.
+	channels [logpb.Channel_CHANNEL_MAX]bool
+	// stop cancels the interception.
+	stop func()
+
+	mu struct {
+		syncutil.Mutex
+		entries [logpb.Channel_CHANNEL_MAX][]logpb.Entry
+	}
+}
+
+var _ Interceptor = (*channelCapture)(nil)
+
+// Intercept implements the Interceptor interface.
+func (c *channelCapture) Intercept(jsonEntry []byte) {
+	var entry logpb.Entry
+	if err := json.Unmarshal(jsonEntry, &entry); err != nil {
+		panic(errors.NewAssertionErrorWithWrappedErrf(err,
+			"interceptor API does not seem to provide valid Entry payloads"))
+	}
+	if entry.Channel < 0 || entry.Channel >= logpb.Channel_CHANNEL_MAX || !c.channels[entry.Channel] {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mu.entries[entry.Channel] = append(c.mu.entries[entry.Channel], entry)
+}
+
+// Captured returns the entries logged to the given channel since the
+// scope was created, in the order in which they were logged. The
+// channel must have been passed to ScopeWithCapture.
+func (l *TestLogScope) Captured(ch Channel) []logpb.Entry {
+	if l.capture == nil || ch < 0 || ch >= logpb.Channel_CHANNEL_MAX || !l.capture.channels[ch] {
+		panic(errors.AssertionFailedf("channel %s is not captured by the test log scope", ch))
+	}
+	l.capture.mu.Lock()
+	defer l.capture.mu.Unlock()
+	return append([]logpb.Entry(nil), l.capture.mu.entries[ch]...)
+}
+
+// CapturedMatching returns the entries logged to the given channel
+// since the scope was created, with a severity of at least sev, and
+// whose message matches re. The redaction markers are stripped from
+// the messages before they are matched.
+func (l *TestLogScope) CapturedMatching(
+	ch Channel, sev Severity, re *regexp.Regexp,
+) []logpb.Entry {
+	var res []logpb.Entry
+	for _, entry := range l.Captured(ch) {
+		if entry.Severity < sev {
+			continue
+		}
+		msg := entry.Message
+		if entry.Redactable {
+			msg = redact.RedactableString(msg).StripMarkers()
+		}
+		if re.MatchString(msg) {
+			res = append(res, entry)
+		}
+	}
+	return res
+}
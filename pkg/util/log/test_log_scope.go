@@ -34,7 +34,9 @@ import (
 type TestLogScope struct {
 	logDir    string
 	cleanupFn func()
-	previous  struct {
+	// capture, if set, is the interceptor installed by ScopeWithCapture.
+	capture  *channelCapture
+	previous struct {
 		appliedConfig           string
 		stderrSinkInfoTemplate  sinkInfo
 		stderrSinkInfo          *sinkInfo
@@ -384,6 +386,10 @@ func (l *TestLogScope) Close(t tShim) {
 	// Ensure any remaining logs are written to files.
 	Flush()
 
+	if l.capture != nil {
+		l.capture.stop()
+	}
+
 	if l.logDir != "" {
 		defer func() {
 			// Check whether there is something to remove.
@@ -11,7 +11,9 @@
 package log
 
 import (
+	"context"
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/cockroachdb/cockroach/pkg/util/log/channel"
@@ -191,3 +193,29 @@ sinks:
 		require.False(t, *def.Redactable)
 	})
 }
+
+// TestScopeWithCapture checks that the scope captures the entries of the
+// selected channels only, and that they can be matched.
+func TestScopeWithCapture(t *testing.T) {
+	sc := ScopeWithCapture(t, channel.OPS, channel.HEALTH)
+	defer sc.Close(t)
+
+	ctx := context.Background()
+	Ops.Infof(ctx, "node %d started", 1)
+	Ops.Warningf(ctx, "node %s is slow", "n1")
+	Health.Errorf(ctx, "disk full")
+	Dev.Warningf(ctx, "node %s is slow", "n2")
+
+	require.Len(t, sc.CapturedOps(), 2)
+	require.Len(t, sc.CapturedHealth(), 1)
+	// DEV is not captured.
+	require.Panics(t, func() { sc.Captured(channel.DEV) })
+
+	// The severity is a threshold, and the redaction markers are
+	// stripped before matching.
+	matches := sc.CapturedMatching(channel.OPS, severity.WARNING, regexp.MustCompile(`node n1 is slow`))
+	require.Len(t, matches, 1)
+	require.Equal(t, severity.WARNING, matches[0].Severity)
+	require.Empty(t, sc.CapturedMatching(channel.OPS, severity.WARNING, regexp.MustCompile(`started`)))
+	require.Len(t, sc.CapturedMatching(channel.HEALTH, severity.INFO, regexp.MustCompile(`disk`)), 1)
+}
@@ -195,7 +195,7 @@ func Eventf(ctx context.Context, format string, args ...interface{}) {
 func vEventf(
 	ctx context.Context, isErr bool, depth int, level Level, format string, args ...interface{},
 ) {
-	if VDepth(level, 1+depth) {
+	if vDepthForContext(ctx, level, 1+depth) {
 		// Log the message (which also logs an event).
 		sev := severity.INFO
 		if isErr {
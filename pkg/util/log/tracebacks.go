@@ -10,10 +10,22 @@
 
 package log
 
-import "os"
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+
+	"github.com/cockroachdb/cockroach/pkg/util/log/logconfig"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
 
 const (
-	tracebackNone = iota
+	// tracebackUnset is used in stackTraceConfig for the severities
+	// without a configured traceback mode.
+	tracebackUnset = iota
+	tracebackNone
 	tracebackSingle
 	tracebackAll
 )
@@ -39,3 +51,163 @@ func DisableTracebacks() func() {
 	traceback = tracebackNone
 	return func() { traceback = oldVal }
 }
+
+// stackTraceConfig determines which goroutine stacks are included in
+// log entries, depending on their severity. It is populated by
+// ApplyConfig(). Its fields are read with atomic loads, since they
+// are consulted for every entry at or above WARNING while a new
+// configuration may be applied concurrently.
+type stackTraceConfig struct {
+	// modes contains the traceback mode for each severity, indexed by
+	// severity. Severities where no mode was specified in the
+	// configuration are set to tracebackUnset: they include no stack,
+	// except for FATAL where the GOTRACEBACK environment variable
+	// applies.
+	modes [severity.FATAL + 1]int32
+
+	// allGoroutinesSeparateFile, when non-zero, causes the stacks of
+	// all goroutines to be written to a separate file in the log
+	// directory. See writeAllStacksToFile().
+	allGoroutinesSeparateFile int32
+}
+
+// stackTraceSeverities are the severities for which a traceback mode
+// can be configured.
+var stackTraceSeverities = [...]Severity{severity.WARNING, severity.ERROR, severity.FATAL}
+
+// applyConfig populates the stackTraceConfig from the logging
+// configuration.
+func (c *stackTraceConfig) applyConfig(config logconfig.StackTraceConfig) {
+	for _, sev := range stackTraceSeverities {
+		var m *logconfig.StackTraceMode
+		switch sev {
+		case severity.WARNING:
+			m = config.Warning
+		case severity.ERROR:
+			m = config.Error
+		case severity.FATAL:
+			m = config.Fatal
+		}
+		mode := tracebackUnset
+		if m != nil {
+			switch *m {
+			case logconfig.StackTraceNone:
+				mode = tracebackNone
+			case logconfig.StackTraceSingle:
+				mode = tracebackSingle
+			case logconfig.StackTraceAll:
+				mode = tracebackAll
+			}
+		}
+		atomic.StoreInt32(&c.modes[sev], int32(mode))
+	}
+	var separateFile int32
+	if config.AllGoroutinesSeparateFile != nil && *config.AllGoroutinesSeparateFile {
+		separateFile = 1
+	}
+	atomic.StoreInt32(&c.allGoroutinesSeparateFile, separateFile)
+}
+
+// describeAppliedConfig reports the configuration that was applied.
+func (c *stackTraceConfig) describeAppliedConfig() (res logconfig.StackTraceConfig) {
+	for _, sev := range stackTraceSeverities {
+		var m logconfig.StackTraceMode
+		switch atomic.LoadInt32(&c.modes[sev]) {
+		case tracebackNone:
+			m = logconfig.StackTraceNone
+		case tracebackSingle:
+			m = logconfig.StackTraceSingle
+		case tracebackAll:
+			m = logconfig.StackTraceAll
+		default:
+			continue
+		}
+		switch sev {
+		case severity.WARNING:
+			res.Warning = &m
+		case severity.ERROR:
+			res.Error = &m
+		case severity.FATAL:
+			res.Fatal = &m
+		}
+	}
+	if c.separateFile() {
+		bt := true
+		res.AllGoroutinesSeparateFile = &bt
+	}
+	return res
+}
+
+// modeFor returns the traceback mode for entries at the given
+// severity.
+func (c *stackTraceConfig) modeFor(sev Severity) int {
+	if sev < 0 || int(sev) >= len(c.modes) {
+		return tracebackNone
+	}
+	if mode := int(atomic.LoadInt32(&c.modes[sev])); mode != tracebackUnset {
+		return mode
+	}
+	if sev == severity.FATAL {
+		return traceback
+	}
+	return tracebackNone
+}
+
+// separateFile returns whether the stacks of all goroutines are
+// written to a separate file.
+func (c *stackTraceConfig) separateFile() bool {
+	return atomic.LoadInt32(&c.allGoroutinesSeparateFile) != 0
+}
+
+// getStacksForEntry retrieves the goroutine stacks to include in an
+// entry at the given severity, or nil if there are none.
+//
+// When the stacks of all goroutines are requested and the
+// configuration asks for it, the stacks of all goroutines are written
+// to a separate file in the directory of the logger's first file
+// sink, and the result only contains the stack of the current
+// goroutine, followed by a reference to that file.
+func (l *loggerT) getStacksForEntry(sev Severity) []byte {
+	switch logging.stackTraces.modeFor(sev) {
+	case tracebackSingle:
+		return getStacks(false)
+	case tracebackAll:
+		allStacks := getStacks(true)
+		if !logging.stackTraces.separateFile() {
+			return allStacks
+		}
+		fs := l.getFileSink()
+		if fs == nil {
+			// No log directory to write to.
+			return allStacks
+		}
+		path, err := fs.writeAllStacksToFile(allStacks)
+		if err != nil {
+			return append(allStacks,
+				fmt.Sprintf("\n(unable to write goroutine stacks to separate file: %v)\n", err)...)
+		}
+		return append(getStacks(false),
+			fmt.Sprintf("\nstacks of all goroutines written to: %s\n", path)...)
+	}
+	return nil
+}
+
+// writeAllStacksToFile writes the given goroutine stacks to a new
+// file in the sink's log directory and returns the path to that file.
+func (l *fileSink) writeAllStacksToFile(stacks []byte) (string, error) {
+	l.mu.RLock()
+	dir := l.mu.logDir
+	l.mu.RUnlock()
+	name := l.nameGenerator.stacksName(timeutil.Now())
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, stacks, l.filePermissions); err != nil {
+		return "", err
+	}
+	// Let the garbage collector apply the retention thresholds to the
+	// new file.
+	select {
+	case l.gcNotify <- struct{}{}:
+	default:
+	}
+	return path, nil
+}
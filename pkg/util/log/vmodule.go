@@ -12,6 +12,7 @@ package log
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"path/filepath"
 	"runtime"
@@ -20,8 +21,10 @@ import (
 	"sync"
 	"sync/atomic"
 
+	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/logtags"
 )
 
 type vmoduleConfig struct {
@@ -49,6 +52,63 @@ type vmoduleConfig struct {
 		// The state of the --vmodule flag.
 		vmodule moduleSpec
 	}
+
+	// channels holds the vmodule configuration specific to each channel,
+	// which is consulted in addition to the one above by the V{Severity}f
+	// methods of the channel loggers.
+	channels [logpb.Channel_CHANNEL_MAX]channelVModule
+
+	// tags holds the vmodule configuration specific to log tags, which is
+	// consulted in addition to the ones above when logging under a
+	// context.
+	tags tagVModule
+}
+
+// channelVModule is the vmodule configuration specific to one logging
+// channel. Unlike the --vmodule flag, it only enables the entries logged
+// to that channel, so that raising the verbosity of a noisy call site
+// for one channel does not flood the others.
+type channelVModule struct {
+	// filterLength stores the length of the filter chain. If greater than
+	// zero, it means the configuration is enabled. It is read using
+	// atomics but updated under mu.Lock.
+	filterLength int32
+
+	mu struct {
+		syncutil.Mutex
+
+		// vmap is a cache of the V Level for each V{Severity}f() call site,
+		// identified by PC. It is wiped whenever the filter changes.
+		vmap map[uintptr]Level
+
+		filter []modulePat
+	}
+}
+
+// tagVModule is the vmodule configuration specific to log tags. Unlike
+// the --vmodule flag, it enables the entries logged under a context
+// carrying a given log tag, regardless of the call site, so that e.g.
+// the activity of a single range can be traced without enabling the
+// verbose logs of all the others.
+type tagVModule struct {
+	// filterLength stores the length of the filter chain. If greater than
+	// zero, it means the configuration is enabled. It is read using
+	// atomics but updated under mu.Lock.
+	filterLength int32
+
+	mu struct {
+		syncutil.RWMutex
+
+		filter []tagPat
+	}
+}
+
+// tagPat contains a filter of the tag vmodule configuration.
+// It holds a verbosity level and the key and value of a log tag.
+type tagPat struct {
+	key   string
+	value string
+	level Level
 }
 
 func init() {
@@ -62,7 +122,12 @@ func init() {
 
 // SetVModule alters the vmodule logging level to the passed in value.
 func SetVModule(value string) error {
-	return logging.vmoduleConfig.mu.vmodule.Set(value)
+	before := GetVModule()
+	if err := logging.vmoduleConfig.mu.vmodule.Set(value); err != nil {
+		return err
+	}
+	reportRuntimeConfigChange(ConfigSourceVModule, before, GetVModule())
+	return nil
 }
 
 // GetVModule returns the current vmodule configuration.
@@ -70,12 +135,99 @@ func GetVModule() string {
 	return logging.vmoduleConfig.mu.vmodule.String()
 }
 
+// SetVModuleForChannel alters the vmodule logging level specific to the
+// given channel. The value has the same syntax as the --vmodule flag.
+// The verbosity it configures only applies to the entries logged to the
+// channel, in addition to the verbosity configured for all channels by
+// SetVModule.
+func SetVModuleForChannel(ch Channel, value string) error {
+	if ch < 0 || ch >= logpb.Channel_CHANNEL_MAX {
+		return errors.Newf("invalid channel: %d", ch)
+	}
+	filter, err := parseVModule(value)
+	if err != nil {
+		return err
+	}
+	before := describeChannelVModule(ch)
+	logging.vmoduleConfig.channels[ch].setFilter(filter)
+	reportRuntimeConfigChange(ConfigSourceChannelVModule, before, describeChannelVModule(ch))
+	return nil
+}
+
+// GetVModuleForChannel returns the vmodule configuration specific to the
+// given channel.
+func GetVModuleForChannel(ch Channel) string {
+	if ch < 0 || ch >= logpb.Channel_CHANNEL_MAX {
+		return ""
+	}
+	cv := &logging.vmoduleConfig.channels[ch]
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+	return formatVModule(cv.mu.filter)
+}
+
+// describeChannelVModule describes the vmodule configuration specific to
+// the channel, for reporting configuration changes.
+func describeChannelVModule(ch Channel) string {
+	return fmt.Sprintf("%s[%s]", ch, GetVModuleForChannel(ch))
+}
+
+// SetVModuleForTags alters the vmodule logging level specific to log
+// tags. The value is a comma-separated list of key:value=N, where key and
+// value identify a log tag, e.g. r:53=2 for the tag of range 53 or
+// job:123=1 for that of job 123. The verbosity it configures applies to
+// the entries logged under a context carrying the tag, in addition to the
+// verbosity configured by SetVModule.
+func SetVModuleForTags(value string) error {
+	filter, err := parseTagVModule(value)
+	if err != nil {
+		return err
+	}
+	before := GetVModuleForTags()
+	logging.vmoduleConfig.tags.setFilter(filter)
+	reportRuntimeConfigChange(ConfigSourceTagVModule, before, GetVModuleForTags())
+	return nil
+}
+
+// GetVModuleForTags returns the vmodule configuration specific to log
+// tags.
+func GetVModuleForTags() string {
+	tv := &logging.vmoduleConfig.tags
+	tv.mu.RLock()
+	defer tv.mu.RUnlock()
+	var b bytes.Buffer
+	for i, p := range tv.mu.filter {
+		if i > 0 {
+			b.WriteRune(',')
+		}
+		fmt.Fprintf(&b, "%s:%s=%d", p.key, p.value, p.level)
+	}
+	return b.String()
+}
+
 // VDepth reports whether verbosity at the call site is at least the requested
 // level.
 func VDepth(l Level, depth int) bool {
 	return logging.vmoduleConfig.vDepth(l, depth+1)
 }
 
+// vDepthForContext is like VDepth for a call site logging under the
+// given context. It also takes into account the vmodule configuration
+// specific to the log tags of the context.
+func vDepthForContext(ctx context.Context, l Level, depth int) bool {
+	c := &logging.vmoduleConfig
+	return c.vDepth(l, depth+1) || c.tags.vEnabled(ctx, l)
+}
+
+// vDepthForChannel is like vDepthForContext for a call site logging to
+// the given channel. It also takes into account the vmodule configuration
+// specific to the channel.
+func vDepthForChannel(ctx context.Context, l Level, depth int, ch Channel) bool {
+	c := &logging.vmoduleConfig
+	return c.vDepth(l, depth+1) || c.channels[ch].vDepth(l, depth+1, &c.pcsPool) ||
+		c.tags.vEnabled(ctx, l)
+}
+
 func (c *vmoduleConfig) vDepth(l Level, depth int) bool {
 	// This function tries hard to be cheap unless there's work to do.
 	// The fast path is three atomic loads and compares.
@@ -114,6 +266,85 @@ func (c *vmoduleConfig) vDepth(l Level, depth int) bool {
 	return false
 }
 
+func (cv *channelVModule) vDepth(l Level, depth int, pcsPool *sync.Pool) bool {
+	if atomic.LoadInt32(&cv.filterLength) == 0 {
+		return false
+	}
+	// See the comments in (*vmoduleConfig).vDepth above.
+	poolObj := pcsPool.Get()
+	pcs := poolObj.([1]uintptr)
+	if runtime.Callers(2+depth, pcs[:]) == 0 {
+		pcsPool.Put(poolObj)
+		return false
+	}
+	cv.mu.Lock()
+	v, ok := cv.mu.vmap[pcs[0]]
+	if !ok {
+		v = filterLevel(pcs, cv.mu.filter)
+		cv.mu.vmap[pcs[0]] = v
+	}
+	cv.mu.Unlock()
+	pcsPool.Put(poolObj)
+	return v >= l
+}
+
+// setFilter sets the filter of the channel configuration, and wipes the
+// pc->Level map.
+func (cv *channelVModule) setFilter(filter []modulePat) {
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+	// Disable filtering while we are in transition.
+	atomic.StoreInt32(&cv.filterLength, 0)
+	cv.mu.filter = filter
+	cv.mu.vmap = make(map[uintptr]Level)
+	atomic.StoreInt32(&cv.filterLength, int32(len(filter)))
+}
+
+// vEnabled reports whether the verbosity configured for one of the log
+// tags of the context is at least the requested level.
+func (tv *tagVModule) vEnabled(ctx context.Context, l Level) bool {
+	if atomic.LoadInt32(&tv.filterLength) == 0 {
+		return false
+	}
+	tags := logtags.FromContext(ctx)
+	if tags == nil {
+		return false
+	}
+	tv.mu.RLock()
+	defer tv.mu.RUnlock()
+	for _, p := range tv.mu.filter {
+		if p.level < l {
+			continue
+		}
+		for _, t := range tags.Get() {
+			if p.match(t) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// match reports whether the log tag matches the filter. The value of the
+// tag matches if it is equal to the value of the filter, or starts with
+// it followed by a '/'. The latter lets e.g. r:53 match the tag of range
+// 53, whose value also includes the replica ID and the span of the range.
+func (p *tagPat) match(t logtags.Tag) bool {
+	if t.Key() != p.key {
+		return false
+	}
+	v := t.ValueStr()
+	return v == p.value || (strings.HasPrefix(v, p.value) && v[len(p.value)] == '/')
+}
+
+// setFilter sets the filter of the tag configuration.
+func (tv *tagVModule) setFilter(filter []tagPat) {
+	tv.mu.Lock()
+	defer tv.mu.Unlock()
+	tv.mu.filter = filter
+	atomic.StoreInt32(&tv.filterLength, int32(len(filter)))
+}
+
 // setVState sets a consistent state for V logging.
 // l.mu is held.
 func (c *vmoduleConfig) setVState(verbosity Level, filter []modulePat, setFilter bool) {
@@ -136,12 +367,19 @@ func (c *vmoduleConfig) setVState(verbosity Level, filter []modulePat, setFilter
 
 // setV computes and remembers the V level for a given PC
 // when vmodule is enabled.
-// File pattern matching takes the basename of the file, stripped
-// of its .go suffix, and uses filepath.Match, which is a little more
-// general than the *? matching used in C++.
 //
 // c.mu is held.
 func (c *vmoduleConfig) setV(pc [1]uintptr) Level {
+	v := filterLevel(pc, c.mu.vmodule.filter)
+	c.mu.vmap[pc[0]] = v
+	return v
+}
+
+// filterLevel computes the V level for a given PC according to the
+// filter. File pattern matching takes the basename of the file, stripped
+// of its .go suffix, and uses filepath.Match, which is a little more
+// general than the *? matching used in C++.
+func filterLevel(pc [1]uintptr, filter []modulePat) Level {
 	frame, _ := runtime.CallersFrames(pc[:]).Next()
 	file := frame.File
 	// The file is something like /a/b/c/d.go. We want just the d.
@@ -149,13 +387,11 @@ func (c *vmoduleConfig) setV(pc [1]uintptr) Level {
 	if slash := strings.LastIndexByte(file, '/'); slash >= 0 {
 		file = file[slash+1:]
 	}
-	for _, filter := range c.mu.vmodule.filter {
-		if filter.match(file) {
-			c.mu.vmap[pc[0]] = filter.level
-			return filter.level
+	for _, f := range filter {
+		if f.match(file) {
+			return f.level
 		}
 	}
-	c.mu.vmap[pc[0]] = 0
 	return 0
 }
 
@@ -186,8 +422,13 @@ func (m *moduleSpec) String() string {
 	// Lock because the type is not atomic. TODO: clean this up.
 	logging.vmoduleConfig.mu.Lock()
 	defer logging.vmoduleConfig.mu.Unlock()
+	return formatVModule(m.filter)
+}
+
+// formatVModule formats a filter in the syntax of the --vmodule flag.
+func formatVModule(filter []modulePat) string {
 	var b bytes.Buffer
-	for i, f := range m.filter {
+	for i, f := range filter {
 		if i > 0 {
 			b.WriteRune(',')
 		}
@@ -200,6 +441,18 @@ var errVmoduleSyntax = errors.New("syntax error: expect comma-separated list of
 
 // Syntax: --vmodule=recordio=2,file=1,gfs*=3
 func (m *moduleSpec) Set(value string) error {
+	filter, err := parseVModule(value)
+	if err != nil {
+		return err
+	}
+	logging.vmoduleConfig.mu.Lock()
+	defer logging.vmoduleConfig.mu.Unlock()
+	logging.vmoduleConfig.setVState(logging.vmoduleConfig.verbosity, filter, true)
+	return nil
+}
+
+// parseVModule parses a filter in the syntax of the --vmodule flag.
+func parseVModule(value string) ([]modulePat, error) {
 	var filter []modulePat
 	for _, pat := range strings.Split(value, ",") {
 		if len(pat) == 0 {
@@ -208,15 +461,15 @@ func (m *moduleSpec) Set(value string) error {
 		}
 		patLev := strings.Split(pat, "=")
 		if len(patLev) != 2 || len(patLev[0]) == 0 || len(patLev[1]) == 0 {
-			return errVmoduleSyntax
+			return nil, errVmoduleSyntax
 		}
 		pattern := patLev[0]
 		v, err := strconv.Atoi(patLev[1])
 		if err != nil {
-			return errors.New("syntax error: expect comma-separated list of filename=N")
+			return nil, errors.New("syntax error: expect comma-separated list of filename=N")
 		}
 		if v < 0 {
-			return errors.New("negative value for vmodule level")
+			return nil, errors.New("negative value for vmodule level")
 		}
 		if v == 0 {
 			continue // Ignore. It's harmless but no point in paying the overhead.
@@ -224,11 +477,41 @@ func (m *moduleSpec) Set(value string) error {
 		// TODO: check syntax of filter?
 		filter = append(filter, modulePat{pattern, isLiteral(pattern), Level(v)})
 	}
+	return filter, nil
+}
 
-	logging.vmoduleConfig.mu.Lock()
-	defer logging.vmoduleConfig.mu.Unlock()
-	logging.vmoduleConfig.setVState(logging.vmoduleConfig.verbosity, filter, true)
-	return nil
+var errTagVModuleSyntax = errors.New("syntax error: expect comma-separated list of key:value=N")
+
+// parseTagVModule parses a filter in the syntax of SetVModuleForTags.
+func parseTagVModule(value string) ([]tagPat, error) {
+	var filter []tagPat
+	for _, pat := range strings.Split(value, ",") {
+		if len(pat) == 0 {
+			// Empty strings such as from a trailing comma can be ignored.
+			continue
+		}
+		eq := strings.LastIndexByte(pat, '=')
+		if eq < 0 {
+			return nil, errTagVModuleSyntax
+		}
+		tag, lev := pat[:eq], pat[eq+1:]
+		colon := strings.IndexByte(tag, ':')
+		if colon <= 0 || colon == len(tag)-1 {
+			return nil, errTagVModuleSyntax
+		}
+		v, err := strconv.Atoi(lev)
+		if err != nil {
+			return nil, errTagVModuleSyntax
+		}
+		if v < 0 {
+			return nil, errors.New("negative value for vmodule level")
+		}
+		if v == 0 {
+			continue // Ignore. It's harmless but no point in paying the overhead.
+		}
+		filter = append(filter, tagPat{key: tag[:colon], value: tag[colon+1:], level: Level(v)})
+	}
+	return filter, nil
 }
 
 // isLiteral reports whether the pattern is a literal string, that is, has no metacharacters
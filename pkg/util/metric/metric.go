@@ -581,6 +581,45 @@ func NewCounter(metadata Metadata) *Counter {
 	return &Counter{metadata, metrics.NewCounter()}
 }
 
+// NewFunctionalCounter creates a Counter metric whose value is determined
+// when asked for by calling the provided function. The function is
+// expected to return a monotonically increasing value, except for
+// resets to zero.
+// Note that Inc, Dec and Clear should NOT be called on a Counter returned
+// from NewFunctionalCounter.
+func NewFunctionalCounter(metadata Metadata, f func() int64) *Counter {
+	return &Counter{metadata, functionalCounter(f)}
+}
+
+// functionalCounter implements metrics.Counter by calling a function
+// to determine the current count.
+type functionalCounter func() int64
+
+var _ metrics.Counter = functionalCounter(nil)
+
+// Clear implements the metrics.Counter interface.
+func (f functionalCounter) Clear() {
+	panic("functional Counter cannot be cleared")
+}
+
+// Count implements the metrics.Counter interface.
+func (f functionalCounter) Count() int64 { return f() }
+
+// Dec implements the metrics.Counter interface.
+func (f functionalCounter) Dec(int64) {
+	panic("functional Counter cannot be decremented")
+}
+
+// Inc implements the metrics.Counter interface.
+func (f functionalCounter) Inc(int64) {
+	panic("functional Counter cannot be incremented")
+}
+
+// Snapshot implements the metrics.Counter interface.
+func (f functionalCounter) Snapshot() metrics.Counter {
+	return metrics.CounterSnapshot(f())
+}
+
 // Dec overrides the metric.Counter method. This method should NOT be
 // used and serves only to prevent misuse of the metric type.
 func (c *Counter) Dec(int64) {
@@ -97,6 +97,23 @@ func TestCounter(t *testing.T) {
 	testMarshal(t, c, "90")
 }
 
+func TestFunctionalCounter(t *testing.T) {
+	valToReturn := int64(10)
+	c := NewFunctionalCounter(emptyMetadata, func() int64 { return valToReturn })
+	if v := c.Count(); v != 10 {
+		t.Fatalf("unexpected value: %d", v)
+	}
+	valToReturn = 15
+	if v := c.Count(); v != 15 {
+		t.Fatalf("unexpected value: %d", v)
+	}
+	if v := c.ToPrometheusMetric().Counter.GetValue(); v != 15 {
+		t.Fatalf("unexpected value: %f", v)
+	}
+
+	testMarshal(t, c, "15")
+}
+
 func setNow(d time.Duration) {
 	now = func() time.Time {
 		return time.Time{}.Add(d)